@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"github.com/hasura/ndc-http/connector/internal"
+	"github.com/hasura/ndc-http/connector/internal/contenttype"
 	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
 	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
 	"github.com/hasura/ndc-sdk-go/connector"
@@ -22,17 +23,37 @@ type HTTPConnector struct {
 	rawSchema           *schema.RawSchemaResponse
 	httpClient          *http.Client
 	upstreams           *internal.UpstreamManager
+	replayGuard         *internal.ReplayGuard
+	snapshots           *internal.SnapshotStore
+	debugCaptures       *internal.DebugCaptureStore
+	coalescer           *internal.RequestCoalescer
+	variableBatcher     *internal.VariableBatcher
+	responseCache       *internal.ResponseCache
+	redisStore          *internal.RedisStore
+	failedSchemas       map[string][]string
+	remoteFiles         *configuration.RemoteFileCacheSyncer
+	secretProviders     map[string]SecretProvider
+	secrets             *internal.SecretRefresher
+	fake                bool
+	mockOptions         *mockOptions
+	mock                *internal.MockResponder
+	decoders            []responseDecoderOption
 	procSendHttpRequest rest.OperationInfo
 }
 
 // NewHTTPConnector creates a HTTP connector instance
 func NewHTTPConnector(opts ...Option) *HTTPConnector {
+	options := defaultOptions
 	for _, opt := range opts {
-		opt(&defaultOptions)
+		opt(&options)
 	}
 
 	return &HTTPConnector{
-		httpClient: defaultOptions.client,
+		httpClient:      options.client,
+		fake:            options.fake,
+		mockOptions:     options.mock,
+		decoders:        options.decoders,
+		secretProviders: options.secretProviders,
 	}
 }
 
@@ -43,9 +64,12 @@ func (c *HTTPConnector) ParseConfiguration(ctx context.Context, configurationDir
 		Version: "0.1.6",
 		Capabilities: schema.Capabilities{
 			Query: schema.QueryCapabilities{
-				Variables:    schema.LeafCapability{},
-				NestedFields: schema.NestedFieldCapabilities{},
-				Explain:      schema.LeafCapability{},
+				Variables: schema.LeafCapability{},
+				NestedFields: schema.NestedFieldCapabilities{
+					FilterBy: schema.LeafCapability{},
+					OrderBy:  schema.LeafCapability{},
+				},
+				Explain: schema.LeafCapability{},
 			},
 			Mutation: schema.MutationCapabilities{
 				Explain: schema.LeafCapability{},
@@ -69,23 +93,124 @@ func (c *HTTPConnector) ParseConfiguration(ctx context.Context, configurationDir
 		return nil, err
 	}
 
+	c.failedSchemas = map[string][]string{}
 	var errs map[string][]string
 	if schemas == nil {
 		logger.Debug(fmt.Sprintf("output file at %s does not exist. Parsing files...", filepath.Join(configurationDir, config.Output)))
 		schemas, errs = configuration.BuildSchemaFromConfig(config, configurationDir, logger)
 		if len(errs) > 0 {
 			printSchemaValidationError(logger, errs)
+			if !config.ContinueOnError {
+				return nil, errBuildSchemaFailed
+			}
 
-			return nil, errBuildSchemaFailed
+			schemas = c.skipFailedSchemas(schemas, errs)
 		}
+
+		c.remoteFiles = configuration.NewRemoteFileCacheSyncer(config, configurationDir, logger)
+	}
+
+	if err := configuration.RenderSchemaEnvTemplates(schemas, config.EnvTemplate); err != nil {
+		return nil, fmt.Errorf("envTemplate: %w", err)
+	}
+
+	secretResolver, err := configuration.NewSecretResolver(config.Secrets)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: %w", err)
+	}
+
+	for scheme, provider := range c.secretProviders {
+		secretResolver.RegisterProvider(scheme, provider)
+	}
+
+	// unresolved schemas are kept to resolve secrets again when they are rotated
+	unresolvedSchemas := schemas
+	schemas, err = secretResolver.ResolveSchemas(ctx, schemas)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: %w", err)
+	}
+
+	c.redisStore, err = internal.NewRedisStore(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.fake {
+		logger.Warn("fake data mode is enabled. Functions return generated data without calling upstream APIs")
+	}
+
+	if c.mockOptions != nil {
+		c.mock = internal.NewMockResponder(config, configurationDir, c.mockOptions.fixturesDir)
+		logger.Warn("mock mode is enabled. Operations return fixtures in " + c.mock.Dir() + " or examples of the API spec without calling upstream APIs")
 	}
 
 	c.config = config
 	c.upstreams = internal.NewUpstreamManager(c.httpClient, config)
+	transformers, err := configuration.NewOperationTransformers(config.Transforms)
+	if err != nil {
+		return nil, err
+	}
+	c.upstreams.SetTransformers(transformers)
+	headersMapper, err := configuration.NewForwardHeadersMapper(config.ForwardHeaders)
+	if err != nil {
+		return nil, fmt.Errorf("forwardHeaders.%w", err)
+	}
+	c.upstreams.SetForwardHeadersMapper(headersMapper)
+	decoders := contenttype.NewResponseDecoderRegistry()
+	for _, item := range c.decoders {
+		if err := decoders.Register(item.pattern, item.decoder); err != nil {
+			return nil, err
+		}
+	}
+	c.upstreams.SetResponseDecoders(decoders)
+	c.upstreams.SetGRPCClients(internal.NewGRPCClients(configurationDir))
+	c.upstreams.SetScheduler(internal.NewFairScheduler(config.Concurrency.Upstream, config.Concurrency.Weights))
+	if c.redisStore != nil {
+		c.upstreams.SetTokenStore(c.redisStore)
+		c.upstreams.SetRateLimiter(c.redisStore)
+	}
+	c.replayGuard = internal.NewReplayGuard(config)
+	c.snapshots = internal.NewSnapshotStore(config, configurationDir)
+	c.debugCaptures, err = internal.NewDebugCaptureStore(config, configurationDir)
+	if err != nil {
+		return nil, err
+	}
+	if c.debugCaptures != nil {
+		logger.Warn("debug capture is enabled. Captured files may contain business data of requests and responses")
+	}
+	auditLogger, err := internal.NewAuditLogger(config, configurationDir)
+	if err != nil {
+		return nil, err
+	}
+	c.upstreams.SetAuditLogger(auditLogger)
+
+	recorder, err := internal.NewRecorder(config, configurationDir)
+	if err != nil {
+		return nil, err
+	}
+
+	if recorder != nil {
+		logger.Warn(fmt.Sprintf("recorder is enabled in the %s mode. Cassettes are stored in %s", recorder.Mode(), recorder.Dir()))
+	}
+	c.upstreams.SetRecorder(recorder)
+	c.upstreams.SetCookieJar(internal.NewCookieJar(config))
+	c.upstreams.SetIdempotencyKeyInjector(internal.NewIdempotencyKeyInjector(config))
+	c.responseCache = internal.NewResponseCache(config, c.redisStore)
 	if err := c.ApplyNDCHttpSchemas(ctx, config, schemas, logger); err != nil {
 		return nil, fmt.Errorf("failed to validate NDC HTTP schema: %w", err)
 	}
 
+	c.secrets = internal.NewSecretRefresher(config, secretResolver, c.upstreams, unresolvedSchemas, logger)
+	c.coalescer, err = internal.NewRequestCoalescer(config, c.metadata, c.execBatchProcedure)
+	if err != nil {
+		return nil, err
+	}
+
+	c.variableBatcher, err = internal.NewVariableBatcher(config, c.metadata, c.execBatchFunction)
+	if err != nil {
+		return nil, err
+	}
+
 	return config, nil
 }
 
@@ -97,6 +222,29 @@ func (c *HTTPConnector) ParseConfiguration(ctx context.Context, configurationDir
 // In addition, this function should register any
 // connector-specific metrics with the metrics registry.
 func (c *HTTPConnector) TryInitState(ctx context.Context, configuration *configuration.Configuration, metrics *connector.TelemetryState) (*State, error) {
+	upstreamMetrics, err := internal.NewUpstreamMetrics(metrics.Meter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create upstream metrics: %w", err)
+	}
+	c.upstreams.SetMetrics(upstreamMetrics)
+
+	if err := internal.RegisterFailedSchemasMetric(metrics.Meter, c.failedSchemas); err != nil {
+		return nil, fmt.Errorf("failed to create schema metrics: %w", err)
+	}
+
+	if err := internal.RegisterServerStateMetrics(metrics.Meter, c.upstreams); err != nil {
+		return nil, fmt.Errorf("failed to create server metrics: %w", err)
+	}
+
+	c.upstreams.StartHealthChecks(ctx)
+	if c.remoteFiles != nil {
+		c.remoteFiles.Start(ctx)
+	}
+
+	if c.secrets != nil {
+		c.secrets.Start(ctx)
+	}
+
 	return &State{
 		Tracer: metrics.Tracer,
 	}, nil
@@ -109,7 +257,15 @@ func (c *HTTPConnector) TryInitState(ctx context.Context, configuration *configu
 //
 // Should throw if the check fails, else resolve.
 func (c *HTTPConnector) HealthCheck(ctx context.Context, configuration *configuration.Configuration, state *State) error {
-	return nil
+	unhealthyUpstreams := c.upstreams.GetUnhealthyUpstreams()
+	if len(unhealthyUpstreams) == 0 {
+		return nil
+	}
+
+	return schema.NewConnectorError(http.StatusServiceUnavailable, "all servers of upstreams are unhealthy", map[string]any{
+		"upstreams": unhealthyUpstreams,
+		"servers":   c.upstreams.GetHealthStatuses(),
+	})
 }
 
 // GetCapabilities get the connector's capabilities.
@@ -2,9 +2,13 @@ package connector
 
 import (
 	"bytes"
+	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/csv"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -12,9 +16,11 @@ import (
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"os"
 	"path/filepath"
 	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -23,6 +29,7 @@ import (
 
 	"github.com/hasura/ndc-http/connector/internal"
 	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/version"
 	"github.com/hasura/ndc-sdk-go/connector"
 	"github.com/hasura/ndc-sdk-go/schema"
 	"gotest.tools/v3/assert"
@@ -64,8 +71,12 @@ func TestHTTPConnectorAuthentication(t *testing.T) {
 		assert.NilError(t, err)
 		assertHTTPResponse(t, res, http.StatusOK, schema.ExplainResponse{
 			Details: schema.ExplainResponseDetails{
-				"url":     server.URL + "/pet",
-				"headers": `{"Accept":["application/json"],"Api_key":["ran*******(14)"],"Content-Type":["application/json"]}`,
+				"url":      server.URL + "/pet",
+				"headers":  `{"Accept":["application/json"],"Api_key":["ran*******(14)"],"Content-Type":["application/json"]}`,
+				"server":   "0",
+				"timeout":  "10",
+				"retry":    `{"times":1,"delay":500,"httpStatus":[429,500,501,502]}`,
+				"security": "api_key",
 			},
 		})
 	})
@@ -122,9 +133,14 @@ func TestHTTPConnectorAuthentication(t *testing.T) {
 		assert.NilError(t, err)
 		assertHTTPResponse(t, res, http.StatusOK, schema.ExplainResponse{
 			Details: schema.ExplainResponseDetails{
-				"url":     server.URL + "/pet",
-				"headers": `{"Accept":["application/json"],"Api_key":["ran*******(14)"],"Content-Type":["application/json"]}`,
-				"body":    "{\"name\":\"pet\"}\n",
+				"url":         server.URL + "/pet",
+				"headers":     `{"Accept":["application/json"],"Api_key":["ran*******(14)"],"Content-Type":["application/json"]}`,
+				"body":        "{\"name\":\"pet\"}\n",
+				"concurrency": "1",
+				"server":      "0",
+				"timeout":     "10",
+				"retry":       `{"times":1,"delay":500,"httpStatus":[429,500,501,502]}`,
+				"security":    "api_key",
 			},
 		})
 	})
@@ -174,8 +190,12 @@ func TestHTTPConnectorAuthentication(t *testing.T) {
 		assert.NilError(t, err)
 		assertHTTPResponse(t, res, http.StatusOK, schema.ExplainResponse{
 			Details: schema.ExplainResponseDetails{
-				"url":     server.URL + "/pet/findByStatus?status=available",
-				"headers": `{"Accept":["application/json"],"Authorization":["Bearer ran*******(19)"],"Content-Type":["application/json"],"X-Custom-Header":["This is a test"]}`,
+				"url":      server.URL + "/pet/findByStatus?status=available",
+				"headers":  `{"Accept":["application/json"],"Authorization":["Bearer ran*******(19)"],"Content-Type":["application/json"],"X-Custom-Header":["This is a test"]}`,
+				"server":   "0",
+				"timeout":  "10",
+				"retry":    `{"times":1,"delay":500,"httpStatus":[429,500,501,502]}`,
+				"security": "bearer",
 			},
 		})
 	})
@@ -736,6 +756,205 @@ func TestHTTPConnector_distribution(t *testing.T) {
 		assert.Equal(t, int32(1), mock.catCount)
 		assert.Equal(t, int32(0), mock.dogCount)
 	})
+
+	t.Run("compare_servers", func(t *testing.T) {
+		mock := mockDistributedServer{}
+		server := mock.createServer(t)
+		defer server.Close()
+
+		t.Setenv("PET_STORE_DOG_URL", fmt.Sprintf("%s/dog", server.URL))
+		t.Setenv("PET_STORE_CAT_URL", fmt.Sprintf("%s/cat", server.URL))
+
+		rc := NewHTTPConnector()
+		connServer, err := connector.NewServer(rc, &connector.ServerOptions{
+			Configuration: "testdata/patch",
+		}, connector.WithoutRecovery())
+		assert.NilError(t, err)
+
+		testServer := connServer.BuildTestServer()
+		defer testServer.Close()
+
+		reqBody := []byte(`{
+			"collection": "findPetsDistributed",
+			"query": {
+				"fields": {
+					"__value": {
+						"type": "column",
+						"column": "__value"
+					}
+				}
+			},
+			"arguments": {
+				"httpOptions": {
+					"type": "literal",
+					"value": {
+						"compareServers": ["cat", "dog"]
+					}
+				}
+			},
+			"collection_relationships": {}
+		}`)
+
+		res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+		assert.NilError(t, err)
+		assertHTTPResponse(t, res, http.StatusOK, schema.QueryResponse{
+			{
+				Rows: []map[string]any{
+					{"__value": map[string]any{
+						"errors": []any{},
+						"results": []any{
+							map[string]any{
+								"data": []any{
+									map[string]any{"name": "cat"},
+								},
+								"server": string("cat"),
+							},
+							map[string]any{
+								"data": []any{
+									map[string]any{"name": "dog"},
+								},
+								"server": string("dog"),
+							},
+						},
+						"comparison": map[string]any{
+							"servers": []any{"cat", "dog"},
+							"equal":   false,
+							"differences": []any{
+								map[string]any{
+									"path":  "$[0].name",
+									"left":  "cat",
+									"right": "dog",
+								},
+							},
+						},
+					}},
+				},
+			},
+		})
+		assert.Equal(t, int32(1), mock.catCount)
+		assert.Equal(t, int32(1), mock.dogCount)
+	})
+
+	createStrategyRequest := func(strategy string) []byte {
+		return []byte(fmt.Sprintf(`{
+			"collection": "findPetsDistributed",
+			"query": {
+				"fields": {
+					"__value": {
+						"type": "column",
+						"column": "__value"
+					}
+				}
+			},
+			"arguments": {
+				"httpOptions": {
+					"type": "literal",
+					"value": {
+						"strategy": "%s"
+					}
+				}
+			},
+			"collection_relationships": {}
+		}`, strategy))
+	}
+
+	t.Run("failover", func(t *testing.T) {
+		mock := mockDistributedServer{}
+		server := mock.createServer(t)
+		defer server.Close()
+
+		// the dog server has the higher priority but it is unavailable
+		t.Setenv("PET_STORE_DOG_URL", fmt.Sprintf("%s/unavailable", server.URL))
+		t.Setenv("PET_STORE_CAT_URL", fmt.Sprintf("%s/cat", server.URL))
+
+		connServer, err := connector.NewServer(NewHTTPConnector(), &connector.ServerOptions{
+			Configuration: "testdata/patch",
+		}, connector.WithoutRecovery())
+		assert.NilError(t, err)
+
+		testServer := connServer.BuildTestServer()
+		defer testServer.Close()
+
+		res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(createStrategyRequest("failover")))
+		assert.NilError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var response []struct {
+			Rows []struct {
+				Value struct {
+					Results []map[string]any `json:"results"`
+					Errors  []map[string]any `json:"errors"`
+				} `json:"__value"`
+			} `json:"rows"`
+		}
+		assert.NilError(t, json.NewDecoder(res.Body).Decode(&response))
+		value := response[0].Rows[0].Value
+		assert.DeepEqual(t, []map[string]any{
+			{
+				"data": []any{
+					map[string]any{"name": "cat"},
+				},
+				"server": "cat",
+			},
+		}, value.Results)
+		assert.Equal(t, 1, len(value.Errors))
+		assert.Equal(t, "dog", value.Errors[0]["server"])
+		assert.Equal(t, int32(1), mock.catCount)
+		assert.Equal(t, int32(0), mock.dogCount)
+	})
+
+	t.Run("race", func(t *testing.T) {
+		mock := mockDistributedServer{}
+		server := mock.createServer(t)
+		defer server.Close()
+
+		t.Setenv("PET_STORE_DOG_URL", fmt.Sprintf("%s/dog", server.URL))
+		t.Setenv("PET_STORE_CAT_URL", fmt.Sprintf("%s/cat", server.URL))
+
+		connServer, err := connector.NewServer(NewHTTPConnector(), &connector.ServerOptions{
+			Configuration: "testdata/patch",
+		}, connector.WithoutRecovery())
+		assert.NilError(t, err)
+
+		testServer := connServer.BuildTestServer()
+		defer testServer.Close()
+
+		// the cat server responds slowly, so the dog server wins
+		res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(createStrategyRequest("race")))
+		assert.NilError(t, err)
+		assertHTTPResponse(t, res, http.StatusOK, schema.QueryResponse{
+			{
+				Rows: []map[string]any{
+					{"__value": map[string]any{
+						"errors": []any{},
+						"results": []any{
+							map[string]any{
+								"data": []any{
+									map[string]any{"name": "dog"},
+								},
+								"server": string("dog"),
+							},
+						},
+					}},
+				},
+			},
+		})
+		assert.Equal(t, int32(1), mock.dogCount)
+	})
+
+	t.Run("invalid_strategy", func(t *testing.T) {
+		connServer, err := connector.NewServer(NewHTTPConnector(), &connector.ServerOptions{
+			Configuration: "testdata/patch",
+		}, connector.WithoutRecovery())
+		assert.NilError(t, err)
+
+		testServer := connServer.BuildTestServer()
+		defer testServer.Close()
+
+		res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(createStrategyRequest("random")))
+		assert.NilError(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+	})
 }
 
 func TestHTTPConnector_multiSchemas(t *testing.T) {
@@ -1463,6 +1682,51 @@ func TestConnectorArgumentPresets(t *testing.T) {
 		})
 	})
 
+	for _, tc := range []struct {
+		Name    string
+		Headers string
+		Message string
+	}{
+		{
+			Name:    "missing_header",
+			Headers: `{"X-Org-Id": "1"}`,
+			Message: "failed to apply argument preset: the forwarded header X-Pet-Status is required",
+		},
+		{
+			Name:    "invalid_header",
+			Headers: `{"x-pet-status": "unknown"}`,
+			Message: "failed to apply argument preset: the value of the forwarded header X-Pet-Status does not match the pattern ^(active|sold|pending)$",
+		},
+	} {
+		t.Run("/pet/findByStatus/"+tc.Name, func(t *testing.T) {
+			reqBody := []byte(`{
+				"collection": "findPetsByStatus",
+				"arguments": {
+					"headers": {
+						"type": "literal",
+						"value": ` + tc.Headers + `
+					}
+				},
+				"query": {
+					"fields": {
+						"__value": {
+							"type": "column",
+							"column": "__value"
+						}
+					}
+				},
+				"collection_relationships": {}
+			}`)
+
+			res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+			assert.NilError(t, err)
+			assertHTTPResponse(t, res, http.StatusUnprocessableEntity, schema.ErrorResponse{
+				Message: tc.Message,
+				Details: map[string]any{},
+			})
+		})
+	}
+
 	t.Run("POST /pet", func(t *testing.T) {
 		reqBody := []byte(`{
 			"operations": [
@@ -1489,4 +1753,562 @@ func TestConnectorArgumentPresets(t *testing.T) {
 			},
 		})
 	})
+
+	t.Run("POST /pet/explain", func(t *testing.T) {
+		reqBody := []byte(`{
+			"operations": [
+				{
+					"type": "procedure",
+					"name": "addPet",
+					"arguments": {}
+				}
+			],
+			"collection_relationships": {}
+		}`)
+
+		res, err := http.Post(fmt.Sprintf("%s/mutation/explain", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+		assert.NilError(t, err)
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		defer res.Body.Close()
+
+		var explainResp schema.ExplainResponse
+		assert.NilError(t, json.NewDecoder(res.Body).Decode(&explainResp))
+		assert.Equal(t, "10", explainResp.Details["timeout"])
+		assert.Equal(t, `{"times":1,"delay":500,"httpStatus":[429,500,502,503]}`, explainResp.Details["retry"])
+		assert.Equal(t, `["$[\"body\"][\"id\"]","$[\"body\"][\"name\"]"]`, explainResp.Details["argumentPresets"])
+	})
+}
+
+func TestConnectorFakeData(t *testing.T) {
+	// the upstream server isn't required in the fake data mode
+	t.Setenv("PET_STORE_URL", "http://localhost:1")
+	connServer, err := connector.NewServer(NewHTTPConnector(WithFakeData(true)), &connector.ServerOptions{
+		Configuration: "testdata/presets",
+	}, connector.WithoutRecovery())
+	assert.NilError(t, err)
+	testServer := connServer.BuildTestServer()
+	defer testServer.Close()
+
+	reqBody := []byte(`{
+		"collection": "findPetsByStatus",
+		"arguments": {},
+		"query": {
+			"fields": {
+				"__value": {
+					"type": "column",
+					"column": "__value",
+					"fields": {
+						"type": "array",
+						"fields": {
+							"type": "object",
+							"fields": {
+								"id": { "type": "column", "column": "id", "fields": null },
+								"name": { "type": "column", "column": "name", "fields": null }
+							}
+						}
+					}
+				}
+			}
+		},
+		"collection_relationships": {}
+	}`)
+
+	res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+	assert.NilError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var results []struct {
+		Rows []struct {
+			Value []map[string]any `json:"__value"`
+		} `json:"rows"`
+	}
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&results))
+	assert.Equal(t, 1, len(results))
+	assert.Equal(t, 1, len(results[0].Rows))
+
+	pets := results[0].Rows[0].Value
+	assert.Assert(t, len(pets) > 0)
+	for _, pet := range pets {
+		assert.Equal(t, 2, len(pet))
+		_, ok := pet["id"].(float64)
+		assert.Assert(t, ok)
+		_, ok = pet["name"].(string)
+		assert.Assert(t, ok)
+	}
+}
+
+func TestConnectorMockMode(t *testing.T) {
+	// the upstream server isn't required in the mock mode
+	t.Setenv("PET_STORE_URL", "http://localhost:1")
+	fixturesDir := t.TempDir()
+	assert.NilError(t, os.WriteFile(filepath.Join(fixturesDir, "addPet.json"), []byte(`{"id": 100, "name": "fixture"}`), 0o600))
+
+	connServer, err := connector.NewServer(NewHTTPConnector(WithMockData(true, fixturesDir)), &connector.ServerOptions{
+		Configuration: "testdata/presets",
+	}, connector.WithoutRecovery())
+	assert.NilError(t, err)
+	testServer := connServer.BuildTestServer()
+	defer testServer.Close()
+
+	reqBody := []byte(`{
+		"operations": [
+			{
+				"type": "procedure",
+				"name": "addPet",
+				"arguments": {
+					"body": { "name": "pet" }
+				},
+				"fields": {
+					"type": "object",
+					"fields": {
+						"id": { "type": "column", "column": "id", "fields": null },
+						"name": { "type": "column", "column": "name", "fields": null }
+					}
+				}
+			}
+		],
+		"collection_relationships": {}
+	}`)
+
+	res, err := http.Post(fmt.Sprintf("%s/mutation", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+	assert.NilError(t, err)
+	defer res.Body.Close()
+	assertHTTPResponse(t, res, http.StatusOK, schema.MutationResponse{
+		OperationResults: []schema.MutationOperationResults{
+			schema.NewProcedureResult(map[string]any{
+				"id":   float64(100),
+				"name": "fixture",
+			}).Encode(),
+		},
+	})
+}
+
+func TestConnectorInfo(t *testing.T) {
+	t.Setenv("PET_STORE_URL", "http://localhost:1")
+	connServer, err := connector.NewServer(NewHTTPConnector(), &connector.ServerOptions{
+		Configuration: "testdata/presets",
+	}, connector.WithoutRecovery())
+	assert.NilError(t, err)
+	testServer := connServer.BuildTestServer()
+	defer testServer.Close()
+
+	reqBody := []byte(`{
+		"collection": "_connectorInfo",
+		"arguments": {},
+		"query": {
+			"fields": {
+				"__value": {
+					"type": "column",
+					"column": "__value"
+				}
+			}
+		},
+		"collection_relationships": {}
+	}`)
+
+	res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+	assert.NilError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var results []struct {
+		Rows []struct {
+			Value struct {
+				Version string `json:"version"`
+				Schemas []struct {
+					Name        string     `json:"name"`
+					Hash        *string    `json:"hash"`
+					ConvertedAt *time.Time `json:"convertedAt"`
+				} `json:"schemas"`
+			} `json:"__value"`
+		} `json:"rows"`
+	}
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&results))
+	assert.Equal(t, 1, len(results[0].Rows))
+
+	info := results[0].Rows[0].Value
+	assert.Equal(t, version.BuildVersion, info.Version)
+	assert.Equal(t, 1, len(info.Schemas))
+	assert.Equal(t, "testdata/presets/petstore.json", info.Schemas[0].Name)
+
+	rawSpec, err := os.ReadFile("testdata/presets/petstore.json")
+	assert.NilError(t, err)
+	specHash := sha256.Sum256(rawSpec)
+	assert.DeepEqual(t, hex.EncodeToString(specHash[:]), *info.Schemas[0].Hash)
+	assert.Assert(t, info.Schemas[0].ConvertedAt != nil)
+}
+
+func TestConnectorContinueOnError(t *testing.T) {
+	t.Setenv("PET_STORE_URL", "http://localhost:1")
+	connServer, err := connector.NewServer(NewHTTPConnector(), &connector.ServerOptions{
+		Configuration: "testdata/continue-on-error",
+	}, connector.WithoutRecovery())
+	assert.NilError(t, err)
+	testServer := connServer.BuildTestServer()
+	defer testServer.Close()
+
+	reqBody := []byte(`{
+		"collection": "_connectorInfo",
+		"arguments": {},
+		"query": {
+			"fields": {
+				"__value": {
+					"type": "column",
+					"column": "__value"
+				}
+			}
+		},
+		"collection_relationships": {}
+	}`)
+
+	res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+	assert.NilError(t, err)
+	defer res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	var results []struct {
+		Rows []struct {
+			Value struct {
+				Schemas []struct {
+					Name string `json:"name"`
+				} `json:"schemas"`
+				FailedSchemas []struct {
+					Name   string   `json:"name"`
+					Errors []string `json:"errors"`
+				} `json:"failedSchemas"`
+			} `json:"__value"`
+		} `json:"rows"`
+	}
+	assert.NilError(t, json.NewDecoder(res.Body).Decode(&results))
+
+	info := results[0].Rows[0].Value
+	assert.Equal(t, 1, len(info.Schemas))
+	assert.Equal(t, "testdata/presets/petstore.json", info.Schemas[0].Name)
+	assert.Equal(t, 1, len(info.FailedSchemas))
+	assert.Equal(t, "testdata/continue-on-error/not-found.json", info.FailedSchemas[0].Name)
+	assert.Assert(t, len(info.FailedSchemas[0].Errors) > 0)
+}
+
+func TestConnectorResponseDecoder(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pet/findByStatus", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/vnd.pets+csv; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("1,Dog\n2,Cat\n"))
+	})
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+
+	decodePets := ResponseDecoderFunc(func(reader io.Reader, contentType string) (any, error) {
+		assert.Equal(t, "application/vnd.pets+csv", contentType)
+		records, err := csv.NewReader(reader).ReadAll()
+		if err != nil {
+			return nil, err
+		}
+
+		results := []any{}
+		for _, record := range records {
+			id, err := strconv.ParseFloat(record[0], 64)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, map[string]any{"id": id, "name": record[1]})
+		}
+
+		return results, nil
+	})
+
+	t.Setenv("PET_STORE_URL", httpServer.URL)
+	t.Setenv("PET_NAME", "Dog")
+	connServer, err := connector.NewServer(NewHTTPConnector(WithResponseDecoder("application/vnd.*+csv", decodePets)), &connector.ServerOptions{
+		Configuration: "testdata/presets",
+	}, connector.WithoutRecovery())
+	assert.NilError(t, err)
+	testServer := connServer.BuildTestServer()
+	defer testServer.Close()
+
+	reqBody := []byte(`{
+		"collection": "findPetsByStatus",
+		"arguments": {
+			"headers": {
+				"type": "literal",
+				"value": {
+					"X-Pet-Status": "active"
+				}
+			}
+		},
+		"query": {
+			"fields": {
+				"__value": {
+					"type": "column",
+					"column": "__value",
+					"fields": {
+						"type": "array",
+						"fields": {
+							"type": "object",
+							"fields": {
+								"name": { "type": "column", "column": "name", "fields": null }
+							}
+						}
+					}
+				}
+			}
+		},
+		"collection_relationships": {}
+	}`)
+
+	res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+	assert.NilError(t, err)
+	assertHTTPResponse(t, res, http.StatusOK, schema.QueryResponse{
+		{
+			Rows: []map[string]any{
+				{"__value": []any{
+					map[string]any{"name": "Dog"},
+					map[string]any{"name": "Cat"},
+				}},
+			},
+		},
+	})
+
+	t.Run("invalid_pattern", func(t *testing.T) {
+		_, err := NewHTTPConnector(WithResponseDecoder("application/[", decodePets)).ParseConfiguration(context.TODO(), "testdata/presets")
+		assert.ErrorContains(t, err, "invalid response decoder pattern")
+	})
+}
+
+func TestConnectorCollections(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/books", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "author=eq.Tolkien&genre=fantasy&limit=2&offset=1&year=gt.1950", r.URL.Query().Encode())
+
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"total": 3,
+			"data": [
+				{ "id": 2, "title": "The Fellowship of the Ring", "author": "Tolkien", "year": 1954 },
+				{ "id": 3, "title": "The Two Towers", "author": "Tolkien", "year": 1954 },
+				{ "id": 4, "title": "The Return of the King", "author": "Tolkien", "year": 1955 }
+			]
+		}`))
+	})
+	mux.HandleFunc("/authors", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{ "id": 1, "name": "Tolkien" }, { "id": 2, "name": "Lewis" }, { "id": 3, "name": "Pratchett" }]`))
+	})
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+
+	t.Setenv("LIBRARY_SERVER_URL", httpServer.URL)
+	connServer, err := connector.NewServer(NewHTTPConnector(), &connector.ServerOptions{
+		Configuration: "testdata/collection",
+	}, connector.WithoutRecovery())
+	assert.NilError(t, err)
+	testServer := connServer.BuildTestServer()
+	defer testServer.Close()
+
+	t.Run("schema", func(t *testing.T) {
+		res, err := http.Get(fmt.Sprintf("%s/schema", testServer.URL))
+		assert.NilError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+
+		var ndcSchema schema.SchemaResponse
+		assert.NilError(t, json.NewDecoder(res.Body).Decode(&ndcSchema))
+
+		collectionNames := []string{}
+		for _, collection := range ndcSchema.Collections {
+			collectionNames = append(collectionNames, collection.Name)
+		}
+		slices.Sort(collectionNames)
+		assert.DeepEqual(t, []string{"listAuthors", "listBooks"}, collectionNames)
+
+		functionNames := []string{}
+		for _, fn := range ndcSchema.Functions {
+			functionNames = append(functionNames, fn.Name)
+		}
+		assert.Assert(t, slices.Contains(functionNames, "getStats"))
+		assert.Assert(t, !slices.Contains(functionNames, "listBooks"))
+
+		// rows of collections aren't wrapped with forwarded response headers
+		books := ndcSchema.Collections[slices.IndexFunc(ndcSchema.Collections, func(c schema.CollectionInfo) bool {
+			return c.Name == "listBooks"
+		})]
+		assert.Equal(t, "Book", books.Type)
+	})
+
+	t.Run("predicate_pagination", func(t *testing.T) {
+		reqBody := []byte(`{
+			"collection": "listBooks",
+			"arguments": {
+				"genre": { "type": "literal", "value": "fantasy" }
+			},
+			"query": {
+				"fields": {
+					"id": { "type": "column", "column": "id" },
+					"title": { "type": "column", "column": "title" }
+				},
+				"limit": 2,
+				"offset": 1,
+				"predicate": {
+					"type": "and",
+					"expressions": [
+						{
+							"type": "binary_comparison_operator",
+							"column": { "type": "column", "name": "author", "path": [] },
+							"operator": "_eq",
+							"value": { "type": "scalar", "value": "Tolkien" }
+						},
+						{
+							"type": "binary_comparison_operator",
+							"column": { "type": "column", "name": "year", "path": [] },
+							"operator": "_gt",
+							"value": { "type": "scalar", "value": 1950 }
+						}
+					]
+				}
+			},
+			"collection_relationships": {}
+		}`)
+
+		res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+		assert.NilError(t, err)
+		assertHTTPResponse(t, res, http.StatusOK, schema.QueryResponse{
+			{
+				Rows: []map[string]any{
+					{"id": float64(2), "title": "The Fellowship of the Ring"},
+					{"id": float64(3), "title": "The Two Towers"},
+				},
+			},
+		})
+	})
+
+	t.Run("offset_in_memory", func(t *testing.T) {
+		reqBody := []byte(`{
+			"collection": "listAuthors",
+			"arguments": {},
+			"query": {
+				"fields": {
+					"name": { "type": "column", "column": "name" }
+				},
+				"limit": 1,
+				"offset": 1
+			},
+			"collection_relationships": {}
+		}`)
+
+		res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+		assert.NilError(t, err)
+		assertHTTPResponse(t, res, http.StatusOK, schema.QueryResponse{
+			{
+				Rows: []map[string]any{
+					{"name": "Lewis"},
+				},
+			},
+		})
+	})
+
+	t.Run("order_by", func(t *testing.T) {
+		reqBody := []byte(`{
+			"collection": "listAuthors",
+			"arguments": {},
+			"query": {
+				"fields": {
+					"name": { "type": "column", "column": "name" }
+				},
+				"order_by": {
+					"elements": [
+						{
+							"order_direction": "asc",
+							"target": { "type": "column", "name": "name", "path": [] }
+						}
+					]
+				}
+			},
+			"collection_relationships": {}
+		}`)
+
+		res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+		assert.NilError(t, err)
+		defer res.Body.Close()
+		assert.Equal(t, http.StatusUnprocessableEntity, res.StatusCode)
+	})
+}
+
+func TestConnectorODataCollections(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/People", func(w http.ResponseWriter, r *http.Request) {
+		rawQuery, err := url.QueryUnescape(r.URL.RawQuery)
+		assert.NilError(t, err)
+		assert.Equal(t, "$select=FirstName,UserName&$top=2&$skip=1&$filter=LastName eq 'O''Neil' and Concurrency gt 10&$orderby=FirstName desc", rawQuery)
+
+		w.Header().Add("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"@odata.context": "http://localhost/$metadata#People(FirstName,UserName)",
+			"value": [
+				{ "UserName": "scottoneil", "FirstName": "Scott" },
+				{ "UserName": "russelloneil", "FirstName": "Russell" }
+			]
+		}`))
+	})
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+
+	t.Setenv("TRIPPIN_SERVER_URL", httpServer.URL)
+	connServer, err := connector.NewServer(NewHTTPConnector(), &connector.ServerOptions{
+		Configuration: "testdata/odata",
+	}, connector.WithoutRecovery())
+	assert.NilError(t, err)
+	testServer := connServer.BuildTestServer()
+	defer testServer.Close()
+
+	reqBody := []byte(`{
+		"collection": "people",
+		"arguments": {},
+		"query": {
+			"fields": {
+				"UserName": { "type": "column", "column": "UserName" },
+				"FirstName": { "type": "column", "column": "FirstName" }
+			},
+			"limit": 2,
+			"offset": 1,
+			"predicate": {
+				"type": "and",
+				"expressions": [
+					{
+						"type": "binary_comparison_operator",
+						"column": { "type": "column", "name": "LastName", "path": [] },
+						"operator": "_eq",
+						"value": { "type": "scalar", "value": "O'Neil" }
+					},
+					{
+						"type": "binary_comparison_operator",
+						"column": { "type": "column", "name": "Concurrency", "path": [] },
+						"operator": "_gt",
+						"value": { "type": "scalar", "value": 10 }
+					}
+				]
+			},
+			"order_by": {
+				"elements": [
+					{
+						"order_direction": "desc",
+						"target": { "type": "column", "name": "FirstName", "path": [] }
+					}
+				]
+			}
+		},
+		"collection_relationships": {}
+	}`)
+
+	res, err := http.Post(fmt.Sprintf("%s/query", testServer.URL), "application/json", bytes.NewBuffer(reqBody))
+	assert.NilError(t, err)
+	assertHTTPResponse(t, res, http.StatusOK, schema.QueryResponse{
+		{
+			Rows: []map[string]any{
+				{"UserName": "scottoneil", "FirstName": "Scott"},
+				{"UserName": "russelloneil", "FirstName": "Russell"},
+			},
+		},
+	})
 }
@@ -0,0 +1,66 @@
+package connector
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/hasura/ndc-http/connector/internal"
+	"github.com/hasura/ndc-sdk-go/connector"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// start capturing the query lifecycle if the client forwards the debug capture header.
+// Headers are resolved from arguments with the first variable set.
+func (c *HTTPConnector) startQueryDebugCapture(ctx context.Context, request *schema.QueryRequest, variables map[string]any) (context.Context, *internal.DebugCapture) {
+	if c.debugCaptures == nil {
+		return ctx, nil
+	}
+
+	rawArgs, err := utils.ResolveArgumentVariables(request.Arguments, variables)
+	if err != nil {
+		return ctx, nil
+	}
+
+	capture := c.debugCaptures.Start("query", request.Collection, request, rawArgs)
+
+	return internal.WithDebugCapture(ctx, capture), capture
+}
+
+// start capturing the mutation lifecycle if any operation forwards the debug capture header.
+func (c *HTTPConnector) startMutationDebugCapture(ctx context.Context, request *schema.MutationRequest) (context.Context, *internal.DebugCapture) {
+	if c.debugCaptures == nil || len(request.Operations) == 0 {
+		return ctx, nil
+	}
+
+	rawArgs := make([]map[string]any, 0, len(request.Operations))
+	for _, operation := range request.Operations {
+		if args, err := decodeMutationArguments(operation); err == nil {
+			rawArgs = append(rawArgs, args)
+		}
+	}
+
+	capture := c.debugCaptures.Start("mutation", request.Operations[0].Name, request, rawArgs...)
+
+	return internal.WithDebugCapture(ctx, capture), capture
+}
+
+// write the debug capture file. Failures are logged only so they never affect the response.
+func (c *HTTPConnector) finishDebugCapture(ctx context.Context, capture *internal.DebugCapture, result any, err error) {
+	if capture == nil {
+		return
+	}
+
+	logger := connector.GetLogger(ctx)
+	filePath, saveErr := c.debugCaptures.Save(capture, result, err)
+	if saveErr != nil {
+		logger.Error("failed to save the debug capture", slog.String("error", saveErr.Error()))
+
+		return
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("debug_capture.path", filePath))
+	logger.Info("debug capture saved", slog.String("capture_id", capture.ID), slog.String("path", filePath))
+}
@@ -0,0 +1,53 @@
+package connector
+
+import (
+	"context"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// isDryRunOperation checks if the dryRun option of the httpOptions argument is enabled.
+func (c *HTTPConnector) isDryRunOperation(operation schema.MutationOperation) bool {
+	if !c.config.DryRun || operation.Type != schema.MutationOperationProcedure {
+		return false
+	}
+
+	rawArgs, err := decodeMutationArguments(operation)
+	if err != nil {
+		return false
+	}
+
+	httpOptions, ok := rawArgs[rest.HTTPOptionsArgumentName].(map[string]any)
+	if !ok {
+		return false
+	}
+
+	dryRun, ok := httpOptions["dryRun"].(bool)
+
+	return ok && dryRun
+}
+
+// build and validate upstream requests of the procedure, then return them with masked credentials without sending.
+// Idempotency tokens aren't reserved, so the procedure can be called again with the same token.
+func (c *HTTPConnector) execDryRunProcedure(ctx context.Context, operation schema.MutationOperation) (any, error) {
+	if err := c.authorizeMutationOperation(operation); err != nil {
+		return nil, err
+	}
+
+	requests, err := c.explainProcedure(&operation)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := c.renderMaskedRequests(ctx, requests)
+	if err != nil {
+		return nil, err
+	}
+
+	if requests.HTTPOptions.Distributed {
+		return results, nil
+	}
+
+	return results[0], nil
+}
@@ -36,6 +36,13 @@ func NewArgumentPreset(httpSchema *rest.NDCHttpSchema, preset rest.ArgumentPrese
 	}, nil
 }
 
+// Matches checks if the preset is applied to arguments of the operation.
+func (ap ArgumentPreset) Matches(operationName string) bool {
+	_, ok := ap.Targets[configuration.BuildArgumentPresetJSONPathKey(operationName, ap.Path)]
+
+	return ok
+}
+
 // Evaluate iterates and inject values into request arguments recursively.
 func (ap ArgumentPreset) Evaluate(operationName string, arguments map[string]any, headers map[string]string) (map[string]any, error) {
 	key := configuration.BuildArgumentPresetJSONPathKey(operationName, ap.Path)
@@ -51,6 +58,10 @@ func (ap ArgumentPreset) Evaluate(operationName string, arguments map[string]any
 
 	value, err := ap.Value.GetValue(headers, ap.getTypeRepresentation(key))
 	if err != nil {
+		if errors.Is(err, errArgumentPresetValueNotFound) {
+			return arguments, nil
+		}
+
 		return nil, err
 	}
 
@@ -30,6 +30,18 @@ func NewArgumentPresets(httpSchema *rest.NDCHttpSchema, presets []rest.ArgumentP
 	return result, nil
 }
 
+// Paths returns JSON paths of presets which are applied to arguments of the operation.
+func (ap ArgumentPresets) Paths(operationName string) []string {
+	var results []string
+	for _, preset := range ap.presets {
+		if preset.Matches(operationName) {
+			results = append(results, preset.Path.String())
+		}
+	}
+
+	return results
+}
+
 // ApplyArgumentPresents replace argument preset values into request arguments.
 func (ap ArgumentPresets) Apply(operationName string, arguments map[string]any, headers map[string]string) (map[string]any, error) {
 	for _, preset := range ap.presets {
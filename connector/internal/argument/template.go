@@ -0,0 +1,168 @@
+package argument
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+const sessionVariablePrefix = "x-hasura-"
+
+var templateExpressionRegex = regexp.MustCompile(`\{\{\s*([^{}]*?)\s*\}\}`)
+
+// templateSegment is either a literal text or an expression of the template.
+type templateSegment struct {
+	literal string
+	// the expression kind, e.g. headers, env, now or uuid. Empty if the segment is a literal text
+	kind string
+	// the argument of the expression, e.g. the header name
+	name string
+}
+
+// ArgumentPresetValueTemplate represents the argument preset getter which renders a template per request.
+type ArgumentPresetValueTemplate struct {
+	template string
+	segments []templateSegment
+	required bool
+}
+
+// NewArgumentPresetValueTemplate creates a new ArgumentPresetValueTemplate instance.
+func NewArgumentPresetValueTemplate(config rest.ArgumentPresetValueTemplate) (*ArgumentPresetValueTemplate, error) {
+	segments, err := parseTemplate(config.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid argument preset template %q: %w", config.Template, err)
+	}
+
+	return &ArgumentPresetValueTemplate{
+		template: config.Template,
+		segments: segments,
+		required: config.Required,
+	}, nil
+}
+
+// GetValue renders the template and parses the argument preset value.
+// Returns errArgumentPresetValueNotFound if a referenced header or environment variable doesn't exist and the template is optional.
+func (apv ArgumentPresetValueTemplate) GetValue(headers map[string]string, typeRep schema.TypeRepresentation) (any, error) {
+	var sb strings.Builder
+	now := time.Now()
+	for _, segment := range apv.segments {
+		if segment.kind == "" {
+			sb.WriteString(segment.literal)
+
+			continue
+		}
+
+		value, ok := segment.evaluate(headers, now)
+		if !ok {
+			if apv.required {
+				return nil, fmt.Errorf("the value of {{%s.%s}} in the argument preset template is required", segment.kind, segment.name)
+			}
+
+			return nil, errArgumentPresetValueNotFound
+		}
+
+		sb.WriteString(value)
+	}
+
+	if typeRep == nil {
+		return sb.String(), nil
+	}
+
+	return convertTypePresentationFromString(sb.String(), typeRep)
+}
+
+func (ts templateSegment) evaluate(headers map[string]string, now time.Time) (string, bool) {
+	switch ts.kind {
+	case "headers", "session":
+		return lookupHeader(headers, ts.name)
+	case "env":
+		return os.LookupEnv(ts.name)
+	case "now":
+		switch ts.name {
+		case "unix":
+			return strconv.FormatInt(now.Unix(), 10), true
+		case "unixMilli":
+			return strconv.FormatInt(now.UnixMilli(), 10), true
+		default:
+			return now.UTC().Format(time.RFC3339), true
+		}
+	case "uuid":
+		return uuid.NewString(), true
+	default:
+		return "", false
+	}
+}
+
+// parse the template into literal texts and expressions.
+// Supported expressions are headers.<name>, session.<name>, env.<name>, now, now.unix, now.unixMilli and uuid.
+func parseTemplate(template string) ([]templateSegment, error) {
+	var segments []templateSegment
+	var lastIndex int
+	for _, match := range templateExpressionRegex.FindAllStringSubmatchIndex(template, -1) {
+		if match[0] > lastIndex {
+			segments = append(segments, templateSegment{literal: template[lastIndex:match[0]]})
+		}
+
+		segment, err := parseTemplateExpression(template[match[2]:match[3]])
+		if err != nil {
+			return nil, err
+		}
+
+		segments = append(segments, segment)
+		lastIndex = match[1]
+	}
+
+	if lastIndex < len(template) {
+		segments = append(segments, templateSegment{literal: template[lastIndex:]})
+	}
+
+	if len(segments) == 0 {
+		return nil, errors.New("template is empty")
+	}
+
+	return segments, nil
+}
+
+func parseTemplateExpression(expression string) (templateSegment, error) {
+	kind, name, _ := strings.Cut(expression, ".")
+	segment := templateSegment{
+		kind: kind,
+		name: name,
+	}
+
+	switch kind {
+	case "headers", "env":
+		if name == "" {
+			return segment, fmt.Errorf("{{%s}}: require a name, e.g. {{%s.<name>}}", expression, kind)
+		}
+	case "session":
+		if name == "" {
+			return segment, fmt.Errorf("{{%s}}: require a session variable name, e.g. {{session.x-hasura-user-id}}", expression)
+		}
+
+		// session variables are forwarded as headers with the x-hasura- prefix
+		if !strings.HasPrefix(strings.ToLower(name), sessionVariablePrefix) {
+			segment.name = sessionVariablePrefix + name
+		}
+	case "now":
+		if name != "" && name != "unix" && name != "unixMilli" {
+			return segment, fmt.Errorf("{{%s}}: unsupported time format, expected now, now.unix or now.unixMilli", expression)
+		}
+	case "uuid":
+		if name != "" {
+			return segment, fmt.Errorf("{{%s}}: unsupported expression, expected uuid", expression)
+		}
+	default:
+		return segment, fmt.Errorf("{{%s}}: unsupported expression, expected headers, session, env, now or uuid", expression)
+	}
+
+	return segment, nil
+}
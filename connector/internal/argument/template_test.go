@@ -0,0 +1,73 @@
+package argument
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestArgumentPresetValueTemplate(t *testing.T) {
+	t.Setenv("TENANT_REGION", "eu")
+	headers := map[string]string{
+		"X-Tenant-Id":      "acme",
+		"X-Hasura-User-Id": "42",
+	}
+
+	getter, err := NewArgumentPresetValueTemplate(rest.ArgumentPresetValueTemplate{
+		Template: "{{ env.TENANT_REGION }}/{{headers.x-tenant-id}}/{{session.user-id}}-{{session.x-hasura-user-id}}",
+	})
+	assert.NilError(t, err)
+	value, err := getter.GetValue(headers, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, "eu/acme/42-42", value)
+
+	getter, err = NewArgumentPresetValueTemplate(rest.ArgumentPresetValueTemplate{Template: "{{uuid}}"})
+	assert.NilError(t, err)
+	value, err = getter.GetValue(nil, schema.NewTypeRepresentationUUID().Encode())
+	assert.NilError(t, err)
+	nonce := value.(string)
+	_, err = uuid.Parse(nonce)
+	assert.NilError(t, err)
+
+	// values are evaluated per request
+	value, err = getter.GetValue(nil, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, nonce != value)
+
+	getter, err = NewArgumentPresetValueTemplate(rest.ArgumentPresetValueTemplate{Template: "{{now.unix}}"})
+	assert.NilError(t, err)
+	value, err = getter.GetValue(nil, schema.NewTypeRepresentationInt64().Encode())
+	assert.NilError(t, err)
+	assert.Assert(t, time.Now().Unix()-value.(int64) < 5)
+
+	getter, err = NewArgumentPresetValueTemplate(rest.ArgumentPresetValueTemplate{Template: "{{now}}"})
+	assert.NilError(t, err)
+	value, err = getter.GetValue(nil, nil)
+	assert.NilError(t, err)
+	_, err = time.Parse(time.RFC3339, value.(string))
+	assert.NilError(t, err)
+
+	// optional templates don't change the argument if the header doesn't exist
+	getter, err = NewArgumentPresetValueTemplate(rest.ArgumentPresetValueTemplate{Template: "tenant-{{headers.X-Org-Id}}"})
+	assert.NilError(t, err)
+	_, err = getter.GetValue(headers, nil)
+	assert.Assert(t, errors.Is(err, errArgumentPresetValueNotFound))
+
+	getter, err = NewArgumentPresetValueTemplate(rest.ArgumentPresetValueTemplate{Template: "tenant-{{headers.X-Org-Id}}", Required: true})
+	assert.NilError(t, err)
+	_, err = getter.GetValue(headers, nil)
+	assert.ErrorContains(t, err, "the value of {{headers.X-Org-Id}} in the argument preset template is required")
+
+	for i, template := range []string{"", "{{}}", "{{headers}}", "{{now.year}}", "{{random}}"} {
+		t.Run(strconv.Itoa(i), func(t *testing.T) {
+			_, err := NewArgumentPresetValueTemplate(rest.ArgumentPresetValueTemplate{Template: template})
+			assert.ErrorContains(t, err, "invalid argument preset template")
+		})
+	}
+}
@@ -1,13 +1,18 @@
 package argument
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
 
 	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
 	"github.com/hasura/ndc-sdk-go/schema"
 )
 
+var errArgumentPresetValueNotFound = errors.New("argument preset value not found")
+
 // NewArgumentPresetValueGetter creates an ArgumentPresetValueGetter from config.
 func NewArgumentPresetValueGetter(presetValue rest.ArgumentPresetValue) (ArgumentPresetValueGetter, error) {
 	switch t := presetValue.Interface().(type) {
@@ -16,7 +21,9 @@ func NewArgumentPresetValueGetter(presetValue rest.ArgumentPresetValue) (Argumen
 	case *rest.ArgumentPresetValueEnv:
 		return NewArgumentPresetValueEnv(t.Name), nil
 	case *rest.ArgumentPresetValueForwardHeader:
-		return NewArgumentPresetValueForwardHeader(t.Name), nil
+		return NewArgumentPresetValueForwardHeader(*t)
+	case *rest.ArgumentPresetValueTemplate:
+		return NewArgumentPresetValueTemplate(*t)
 	default:
 		return nil, fmt.Errorf("unsupported argument preset value: %v", presetValue)
 	}
@@ -71,26 +78,60 @@ func (apv ArgumentPresetValueEnv) GetValue(_ map[string]string, typeRep schema.T
 
 // ArgumentPresetValueForwardHeader represents the argument preset getter from request headers.
 type ArgumentPresetValueForwardHeader struct {
-	name string
+	name     string
+	required bool
+	pattern  *regexp.Regexp
 }
 
 // NewArgumentPresetValueForwardHeader creates a new ArgumentPresetValueForwardHeader instance.
-func NewArgumentPresetValueForwardHeader(name string) *ArgumentPresetValueForwardHeader {
-	return &ArgumentPresetValueForwardHeader{
-		name: name,
+func NewArgumentPresetValueForwardHeader(config rest.ArgumentPresetValueForwardHeader) (*ArgumentPresetValueForwardHeader, error) {
+	result := &ArgumentPresetValueForwardHeader{
+		name:     config.Name,
+		required: config.Required,
+	}
+
+	if config.Pattern != "" {
+		pattern, err := regexp.Compile(config.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern of the forwarded header %s: %w", config.Name, err)
+		}
+
+		result.pattern = pattern
 	}
+
+	return result, nil
 }
 
 // GetValue gets and parses the argument preset value.
+// Returns errArgumentPresetValueNotFound if the header is optional and doesn't exist.
 func (apv ArgumentPresetValueForwardHeader) GetValue(headers map[string]string, typeRep schema.TypeRepresentation) (any, error) {
-	if len(headers) == 0 {
-		return nil, nil
+	rawValue, ok := lookupHeader(headers, apv.name)
+	if !ok {
+		if apv.required {
+			return nil, fmt.Errorf("the forwarded header %s is required", apv.name)
+		}
+
+		return nil, errArgumentPresetValueNotFound
 	}
 
-	rawValue, ok := headers[apv.name]
-	if !ok {
-		return nil, nil
+	if apv.pattern != nil && !apv.pattern.MatchString(rawValue) {
+		return nil, fmt.Errorf("the value of the forwarded header %s does not match the pattern %s", apv.name, apv.pattern.String())
 	}
 
 	return convertTypePresentationFromString(rawValue, typeRep)
 }
+
+// header names are case-insensitive
+func lookupHeader(headers map[string]string, name string) (string, bool) {
+	if value, ok := headers[name]; ok {
+		return value, true
+	}
+
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value, true
+		}
+	}
+
+	return "", false
+}
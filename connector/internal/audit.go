@@ -0,0 +1,185 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"go.opentelemetry.io/contrib/bridges/otelslog"
+)
+
+const (
+	defaultAuditLogPath         string = "audit.log"
+	defaultAuditLogMaxBodyBytes int    = 4 * 1024
+	auditLogMessage             string = "upstream request"
+)
+
+// AuditLogger records requests to upstream services as structured audit logs.
+type AuditLogger struct {
+	logger        *slog.Logger
+	includeBodies bool
+	maxBodyBytes  int
+}
+
+// NewAuditLogger creates an AuditLogger instance. Returns nil if audit logs are disabled.
+func NewAuditLogger(config *configuration.Configuration, configDir string) (*AuditLogger, error) {
+	if config == nil || config.AuditLog == nil || !config.AuditLog.Enabled {
+		return nil, nil
+	}
+
+	settings := config.AuditLog
+	result := &AuditLogger{
+		includeBodies: settings.IncludeBodies,
+		maxBodyBytes:  int(settings.MaxBodyBytes),
+	}
+
+	if result.maxBodyBytes <= 0 {
+		result.maxBodyBytes = defaultAuditLogMaxBodyBytes
+	}
+
+	switch settings.GetSink() {
+	case configuration.AuditLogSinkOTLP:
+		// the global logger provider is configured by the connector SDK
+		result.logger = slog.New(otelslog.NewHandler("ndc-http/audit"))
+	case configuration.AuditLogSinkFile:
+		filePath := settings.Path
+		if filePath == "" {
+			filePath = defaultAuditLogPath
+		}
+
+		if !filepath.IsAbs(filePath) {
+			filePath = filepath.Join(configDir, filePath)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+			return nil, fmt.Errorf("auditLog: failed to create the log directory: %w", err)
+		}
+
+		file, err := os.OpenFile(filePath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("auditLog: failed to open the log file: %w", err)
+		}
+
+		result.logger = newAuditJSONLogger(file)
+	default:
+		result.logger = newAuditJSONLogger(os.Stdout)
+	}
+
+	return result, nil
+}
+
+// SetAuditLogger sets the logger to record requests to upstream services.
+func (um *UpstreamManager) SetAuditLogger(logger *AuditLogger) {
+	um.auditLogger = logger
+}
+
+func newAuditJSONLogger(writer io.Writer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(writer, nil))
+}
+
+// AuditLogEntry represents the audit log of a request attempt to the upstream service.
+type AuditLogEntry struct {
+	logger         *AuditLogger
+	ctx            context.Context
+	url            string
+	requestHeaders map[string]string
+	attrs          []slog.Attr
+	body           *debugCaptureBuffer
+	once           sync.Once
+}
+
+// Start creates the audit log entry of the request attempt. The request is logged when the entry is finished.
+// Returns nil if audit logs are disabled.
+func (al *AuditLogger) Start(ctx context.Context, operationName string, request *RetryableRequest, requestBody []byte, attempt int) *AuditLogEntry {
+	if al == nil {
+		return nil
+	}
+
+	entry := &AuditLogEntry{
+		logger:         al,
+		ctx:            ctx,
+		url:            maskDebugCaptureURL(&request.URL),
+		requestHeaders: maskDebugCaptureHeaders(request.Headers),
+		attrs: []slog.Attr{
+			slog.String("operation", operationName),
+			slog.String("namespace", request.Namespace),
+			slog.String("server_id", request.ServerID),
+			slog.String("method", strings.ToUpper(request.RawRequest.Method)),
+			slog.Int("attempt", attempt),
+		},
+	}
+
+	if al.includeBodies && len(requestBody) > 0 {
+		entry.attrs = append(entry.attrs, slog.String("request_body", al.truncateBody(requestBody)))
+	}
+
+	return entry
+}
+
+// Finish logs the request with the response or the error.
+// The successful response body is logged after it is read and closed, so the decoder isn't blocked.
+func (ale *AuditLogEntry) Finish(resp *http.Response, errorBytes []byte, err error, latency time.Duration) {
+	if ale == nil {
+		return
+	}
+
+	ale.attrs = append(ale.attrs, slog.Int64("latency_ms", latency.Milliseconds()))
+	if err != nil {
+		ale.attrs = append(ale.attrs, slog.String("error", err.Error()))
+	}
+
+	if resp == nil {
+		ale.flush()
+
+		return
+	}
+
+	// the actual request contains credentials which are injected by the security scheme
+	if resp.Request != nil {
+		ale.url = maskDebugCaptureURL(resp.Request.URL)
+		ale.requestHeaders = maskDebugCaptureHeaders(resp.Request.Header)
+	}
+
+	ale.attrs = append(ale.attrs, slog.Int("status", resp.StatusCode))
+	if !ale.logger.includeBodies || resp.Body == nil || errorBytes != nil {
+		if ale.logger.includeBodies && len(errorBytes) > 0 {
+			ale.attrs = append(ale.attrs, slog.String("response_body", ale.logger.truncateBody(errorBytes)))
+		}
+
+		ale.flush()
+
+		return
+	}
+
+	ale.body = &debugCaptureBuffer{limit: ale.logger.maxBodyBytes}
+	resp.Body = newCountingReadCloser(&debugCaptureReadCloser{ReadCloser: resp.Body, buffer: ale.body}, func(int64) {
+		ale.flush()
+	})
+}
+
+func (ale *AuditLogEntry) flush() {
+	ale.once.Do(func() {
+		ale.attrs = append(ale.attrs, slog.String("url", ale.url), slog.Any("request_headers", ale.requestHeaders))
+		if ale.body != nil {
+			ale.attrs = append(ale.attrs, slog.String("response_body", ale.body.String()))
+		}
+
+		ale.logger.logger.LogAttrs(ale.ctx, slog.LevelInfo, auditLogMessage, ale.attrs...)
+	})
+}
+
+func (al *AuditLogger) truncateBody(body []byte) string {
+	if len(body) <= al.maxBodyBytes {
+		return string(body)
+	}
+
+	return string(body[:al.maxBodyBytes])
+}
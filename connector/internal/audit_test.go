@@ -0,0 +1,116 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestAuditLogger(t *testing.T) {
+	logger, err := NewAuditLogger(&configuration.Configuration{}, ".")
+	assert.NilError(t, err)
+	assert.Assert(t, logger == nil)
+
+	// the nil logger is a no-op
+	logger.Start(context.TODO(), "findPets", &RetryableRequest{}, nil, 0).Finish(nil, nil, nil, 0)
+
+	dir := t.TempDir()
+	logger, err = NewAuditLogger(&configuration.Configuration{
+		AuditLog: &configuration.AuditLogSettings{
+			Enabled:       true,
+			Sink:          configuration.AuditLogSinkFile,
+			Path:          "logs/audit.log",
+			IncludeBodies: true,
+			MaxBodyBytes:  16,
+		},
+	}, dir)
+	assert.NilError(t, err)
+
+	requestURL, err := url.Parse("http://localhost:1234/pet?api_key=abcdefghijklmn")
+	assert.NilError(t, err)
+
+	request := &RetryableRequest{
+		RawRequest: &rest.Request{Method: "post"},
+		URL:        *requestURL,
+		Namespace:  "petstore",
+		ServerID:   "primary",
+		Headers: http.Header{
+			"Content-Type":  []string{"application/json"},
+			"Authorization": []string{"Bearer abcdefghijklmn"},
+		},
+	}
+
+	logger.Start(context.TODO(), "addPet", request, []byte(`{"id":1}`), 0).
+		Finish(nil, nil, errors.New("connection refused"), 10*time.Millisecond)
+
+	resp := &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(`{"id":1,"name":"a long pet name"}`)),
+	}
+	logger.Start(context.TODO(), "addPet", request, []byte(`{"id":1}`), 1).
+		Finish(resp, nil, nil, 20*time.Millisecond)
+
+	// the successful response is logged after the body is closed
+	body, err := io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	assert.Equal(t, `{"id":1,"name":"a long pet name"}`, string(body))
+	assert.NilError(t, resp.Body.Close())
+
+	rawBytes, err := os.ReadFile(filepath.Join(dir, "logs", "audit.log"))
+	assert.NilError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(string(rawBytes)), "\n")
+	assert.Equal(t, 2, len(lines))
+
+	entries := make([]map[string]any, len(lines))
+	for i, line := range lines {
+		assert.NilError(t, json.Unmarshal([]byte(line), &entries[i]))
+		delete(entries[i], "time")
+	}
+
+	expectedHeaders := map[string]any{
+		"Content-Type":  "application/json",
+		"Authorization": "Bea*******(21)",
+	}
+	assert.DeepEqual(t, map[string]any{
+		"level":           "INFO",
+		"msg":             "upstream request",
+		"operation":       "addPet",
+		"namespace":       "petstore",
+		"server_id":       "primary",
+		"method":          "POST",
+		"attempt":         float64(0),
+		"request_body":    `{"id":1}`,
+		"latency_ms":      float64(10),
+		"error":           "connection refused",
+		"url":             "http://localhost:1234/pet?api_key=abc%2A%2A%2A%2A%2A%2A%2A%2814%29",
+		"request_headers": expectedHeaders,
+	}, entries[0])
+	assert.DeepEqual(t, map[string]any{
+		"level":           "INFO",
+		"msg":             "upstream request",
+		"operation":       "addPet",
+		"namespace":       "petstore",
+		"server_id":       "primary",
+		"method":          "POST",
+		"attempt":         float64(1),
+		"request_body":    `{"id":1}`,
+		"latency_ms":      float64(20),
+		"status":          float64(200),
+		"url":             "http://localhost:1234/pet?api_key=abc%2A%2A%2A%2A%2A%2A%2A%2814%29",
+		"request_headers": expectedHeaders,
+		"response_body":   `{"id":1,"name":"`,
+	}, entries[1])
+}
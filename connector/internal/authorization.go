@@ -0,0 +1,29 @@
+package internal
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// AuthorizeOperation checks if the role in forwarded headers is permitted to execute the operation.
+func AuthorizeOperation(config *configuration.Configuration, operationName string, rawArgs map[string]any) error {
+	if config == nil || config.Authorization == nil {
+		return nil
+	}
+
+	role, err := getForwardedHeader(config, rawArgs, config.Authorization.GetRoleHeader())
+	if err != nil {
+		return err
+	}
+
+	if config.Authorization.IsAllowed(role, operationName) {
+		return nil
+	}
+
+	return schema.NewConnectorError(http.StatusForbidden, fmt.Sprintf("permission denied to execute the operation %s", operationName), map[string]any{
+		"role": role,
+	})
+}
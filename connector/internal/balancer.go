@@ -0,0 +1,190 @@
+package internal
+
+import (
+	"sync"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+const (
+	// the number of consecutive failures after which the server is considered unhealthy
+	serverUnhealthyThreshold = 3
+	// the duration in which unhealthy servers are skipped before they receive requests again
+	serverUnhealthyCooldown = 30 * time.Second
+)
+
+// ServerCircuitState represents whether the server is skipped by the server selection after consecutive failures.
+type ServerCircuitState struct {
+	Namespace string
+	ServerID  string
+	Open      bool
+}
+
+// serverBalancer spreads single-target requests across servers with the round-robin or weighted strategy.
+// Servers are marked unhealthy after consecutive failures and skipped during the cooldown, unless all candidates are unhealthy.
+type serverBalancer struct {
+	strategy       rest.ServerSelectionStrategy
+	weights        map[string]uint
+	counter        int
+	currentWeights map[string]int
+	failures       map[string]int
+	unhealthyUntil map[string]time.Time
+	lock           sync.Mutex
+}
+
+func newServerBalancer(strategy rest.ServerSelectionStrategy) *serverBalancer {
+	return &serverBalancer{
+		strategy:       strategy,
+		weights:        make(map[string]uint),
+		currentWeights: make(map[string]int),
+		failures:       make(map[string]int),
+		unhealthyUntil: make(map[string]time.Time),
+	}
+}
+
+// SetWeight sets the weight of the server.
+func (sb *serverBalancer) SetWeight(serverID string, weight uint) {
+	sb.lock.Lock()
+	defer sb.lock.Unlock()
+
+	sb.weights[serverID] = weight
+}
+
+// Record updates the health of the server with the result of the request.
+// Connection errors and 5xx responses are failures.
+func (sb *serverBalancer) Record(serverID string, failed bool) {
+	if sb == nil {
+		return
+	}
+
+	sb.lock.Lock()
+	defer sb.lock.Unlock()
+
+	if !failed {
+		delete(sb.failures, serverID)
+		delete(sb.unhealthyUntil, serverID)
+
+		return
+	}
+
+	sb.failures[serverID]++
+	if sb.failures[serverID] >= serverUnhealthyThreshold {
+		sb.unhealthyUntil[serverID] = time.Now().Add(serverUnhealthyCooldown)
+	}
+}
+
+// IsOpen checks if the server is skipped during the cooldown after consecutive failures.
+func (sb *serverBalancer) IsOpen(serverID string) bool {
+	sb.lock.Lock()
+	defer sb.lock.Unlock()
+
+	return !sb.isHealthy(serverID, time.Now())
+}
+
+// Select returns the index of the selected server.
+func (sb *serverBalancer) Select(serverIDs []string) int {
+	if len(serverIDs) < 2 {
+		return 0
+	}
+
+	sb.lock.Lock()
+	defer sb.lock.Unlock()
+
+	candidates := sb.getCandidates(serverIDs)
+	if sb.strategy != rest.ServerSelectionWeighted || sb.getWeight(serverIDs[candidates[0]]) == 0 {
+		index := candidates[sb.counter%len(candidates)]
+		sb.counter++
+
+		return index
+	}
+
+	// smooth weighted round-robin: the server with the highest current weight is selected,
+	// then its current weight is reduced by the total weight, so servers are interleaved.
+	var totalWeight int
+	result := -1
+	for _, index := range candidates {
+		weight := sb.getWeight(serverIDs[index])
+		totalWeight += weight
+		sb.currentWeights[serverIDs[index]] += weight
+		if result < 0 || sb.currentWeights[serverIDs[index]] > sb.currentWeights[serverIDs[result]] {
+			result = index
+		}
+	}
+
+	sb.currentWeights[serverIDs[result]] -= totalWeight
+
+	return result
+}
+
+// get indexes of healthy servers, or all servers if every server is unhealthy.
+// Servers with zero weight are excluded from the weighted selection unless all candidates have zero weight.
+func (sb *serverBalancer) getCandidates(serverIDs []string) []int {
+	now := time.Now()
+	var healthy, all []int
+	for i, serverID := range serverIDs {
+		if sb.strategy == rest.ServerSelectionWeighted && sb.getWeight(serverID) == 0 {
+			continue
+		}
+
+		all = append(all, i)
+		if sb.isHealthy(serverID, now) {
+			healthy = append(healthy, i)
+		}
+	}
+
+	switch {
+	case len(healthy) > 0:
+		return healthy
+	case len(all) > 0:
+		return all
+	default:
+		// every server has zero weight, so servers are selected in turn
+		result := make([]int, len(serverIDs))
+		for i := range serverIDs {
+			result[i] = i
+		}
+
+		return result
+	}
+}
+
+func (sb *serverBalancer) getWeight(serverID string) int {
+	weight, ok := sb.weights[serverID]
+	if !ok {
+		return 1
+	}
+
+	return int(weight)
+}
+
+func (sb *serverBalancer) isHealthy(serverID string, now time.Time) bool {
+	until, ok := sb.unhealthyUntil[serverID]
+
+	return !ok || !now.Before(until)
+}
+
+// GetCircuitStates returns circuit states of servers in upstreams with the round-robin or weighted server selection.
+func (um *UpstreamManager) GetCircuitStates() []ServerCircuitState {
+	var results []ServerCircuitState
+	um.upstreamsLock.RLock()
+	defer um.upstreamsLock.RUnlock()
+
+	for _, namespace := range utils.GetSortedKeys(um.upstreams) {
+		upstream := um.upstreams[namespace]
+		if upstream.balancer == nil {
+			continue
+		}
+
+		for _, serverID := range upstream.getServerIDs() {
+			results = append(results, ServerCircuitState{
+				Namespace: namespace,
+				ServerID:  serverID,
+				Open:      upstream.balancer.IsOpen(serverID),
+			})
+		}
+	}
+
+	return results
+}
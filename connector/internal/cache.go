@@ -0,0 +1,401 @@
+package internal
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"maps"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"github.com/hasura/ndc-sdk-go/connector"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultResponseCacheMaxEntries         = 1000
+	defaultResponseCacheRefreshConcurrency = 10
+	staleResponseWarning                   = `110 - "Response is Stale"`
+)
+
+// CachedResponse is the last known good response of a function.
+type CachedResponse struct {
+	Value    any       `json:"value"`
+	StoredAt time.Time `json:"storedAt"`
+}
+
+// ResponseCacheStore abstracts the storage of cached responses.
+type ResponseCacheStore interface {
+	// Get returns the cached response of the key. Returns nil if not exists or expired.
+	Get(ctx context.Context, key string) (*CachedResponse, error)
+	// Set stores the response with the expiry duration.
+	Set(ctx context.Context, key string, value CachedResponse, expiry time.Duration) error
+}
+
+// SingleFlightLocker guarantees that only one caller executes the work of a key at the same time,
+// e.g. refreshing a cached response.
+type SingleFlightLocker interface {
+	// TryLock acquires the lock of the key with the expiry duration. Returns false if the key is locked by another caller.
+	TryLock(ctx context.Context, key string, expiry time.Duration) (bool, error)
+	// Unlock releases the lock of the key.
+	Unlock(ctx context.Context, key string) error
+}
+
+// ResponseCache caches responses of functions. Fresh responses are served without calling upstream services,
+// and the last known good responses are served if upstream services fail within the stale-if-error window.
+type ResponseCache struct {
+	config    *configuration.Configuration
+	functions map[string]functionCachePolicy
+	store     ResponseCacheStore
+	// limits the number of concurrent background refreshes
+	refreshSemaphore chan struct{}
+	refreshLocker    SingleFlightLocker
+}
+
+type functionCachePolicy struct {
+	ttl          time.Duration
+	softTTL      time.Duration
+	staleIfError time.Duration
+}
+
+// NewResponseCache creates a new ResponseCache instance. Responses are stored in Redis if the shared store exists, otherwise in memory.
+// Returns nil if the response cache isn't configured.
+func NewResponseCache(config *configuration.Configuration, sharedStore *RedisStore) *ResponseCache {
+	if config == nil || config.ResponseCache == nil || len(config.ResponseCache.Functions) == 0 {
+		return nil
+	}
+
+	maxEntries := defaultResponseCacheMaxEntries
+	if config.ResponseCache.MaxEntries > 0 {
+		maxEntries = int(config.ResponseCache.MaxEntries)
+	}
+
+	refreshConcurrency := defaultResponseCacheRefreshConcurrency
+	if config.ResponseCache.RefreshConcurrency > 0 {
+		refreshConcurrency = int(config.ResponseCache.RefreshConcurrency)
+	}
+
+	functions := make(map[string]functionCachePolicy)
+	for name, setting := range config.ResponseCache.Functions {
+		functions[name] = functionCachePolicy{
+			ttl:          time.Duration(setting.TTL) * time.Second,
+			softTTL:      time.Duration(setting.SoftTTL) * time.Second,
+			staleIfError: time.Duration(setting.StaleIfError) * time.Second,
+		}
+	}
+
+	result := &ResponseCache{
+		config:           config,
+		functions:        functions,
+		refreshSemaphore: make(chan struct{}, refreshConcurrency),
+	}
+
+	if sharedStore != nil {
+		result.store = sharedStore
+		result.refreshLocker = sharedStore
+	} else {
+		result.store = newMemoryResponseCacheStore(maxEntries)
+		result.refreshLocker = newMemorySingleFlightLocker()
+	}
+
+	return result
+}
+
+// Execute returns the fresh cached response of the function, or calls the fetch function and caches the result.
+// Fresh responses older than the soft TTL are refreshed in the background.
+// If the fetch function fails with a server error, the last known good response within the stale-if-error window is returned instead.
+func (rc *ResponseCache) Execute(ctx context.Context, functionName string, arguments map[string]any, selection schema.NestedField, fetch func(ctx context.Context) (any, error)) (any, error) {
+	if rc == nil {
+		return fetch(ctx)
+	}
+
+	policy, ok := rc.functions[functionName]
+	if !ok {
+		return fetch(ctx)
+	}
+
+	key, err := rc.createCacheKey(functionName, arguments, selection)
+	if err != nil {
+		return fetch(ctx)
+	}
+
+	span := trace.SpanFromContext(ctx)
+	logger := connector.GetLogger(ctx)
+	cached, err := rc.store.Get(ctx, key)
+	if err != nil {
+		// the cache is unavailable, fall back to the upstream service
+		logger.Warn("failed to get the cached response", slog.String("operation", functionName), slog.String("error", err.Error()))
+	}
+
+	if cached != nil {
+		age := time.Since(cached.StoredAt)
+		if age < policy.ttl {
+			span.SetAttributes(attribute.Bool("cache.hit", true))
+			if policy.softTTL > 0 && age >= policy.softTTL {
+				rc.refreshInBackground(ctx, functionName, key, policy, fetch)
+			}
+
+			return cached.Value, nil
+		}
+	}
+
+	result, err := fetch(ctx)
+	if err == nil {
+		rc.set(ctx, functionName, key, result, policy)
+
+		return result, nil
+	}
+
+	if cached == nil || !isServerError(err) {
+		return nil, err
+	}
+
+	age := time.Since(cached.StoredAt)
+	span.SetAttributes(attribute.Bool("cache.hit", true), attribute.Bool("cache.stale", true))
+	logger.Warn(
+		"the upstream service failed, serving the stale response",
+		slog.String("operation", functionName),
+		slog.Duration("age", age),
+		slog.String("error", err.Error()),
+	)
+
+	return rc.markStaleResponse(cached.Value, age), nil
+}
+
+func (rc *ResponseCache) set(ctx context.Context, functionName string, key string, value any, policy functionCachePolicy) {
+	err := rc.store.Set(ctx, key, CachedResponse{
+		Value:    value,
+		StoredAt: time.Now(),
+	}, policy.ttl+policy.staleIfError)
+	if err != nil {
+		connector.GetLogger(ctx).Warn("failed to store the cached response", slog.String("operation", functionName), slog.String("error", err.Error()))
+	}
+}
+
+// refresh the cached response asynchronously. The refresh is skipped if the key is being refreshed
+// or the number of concurrent refreshes reaches the limit, to avoid stampedes to the upstream service.
+func (rc *ResponseCache) refreshInBackground(ctx context.Context, functionName string, key string, policy functionCachePolicy, fetch func(ctx context.Context) (any, error)) {
+	select {
+	case rc.refreshSemaphore <- struct{}{}:
+	default:
+		return
+	}
+
+	// the request context is canceled after the response is returned
+	refreshCtx := context.WithoutCancel(ctx)
+	logger := connector.GetLogger(ctx)
+	lockKey := "refresh:" + key
+
+	go func() {
+		defer func() {
+			<-rc.refreshSemaphore
+		}()
+
+		// the lock expires after the soft window in case the refresh never finishes
+		locked, err := rc.refreshLocker.TryLock(refreshCtx, lockKey, policy.ttl-policy.softTTL)
+		if err != nil {
+			logger.Warn("failed to acquire the refresh lock", slog.String("operation", functionName), slog.String("error", err.Error()))
+		}
+
+		if !locked {
+			return
+		}
+
+		defer func() {
+			if err := rc.refreshLocker.Unlock(refreshCtx, lockKey); err != nil {
+				logger.Warn("failed to release the refresh lock", slog.String("operation", functionName), slog.String("error", err.Error()))
+			}
+		}()
+
+		result, err := fetch(refreshCtx)
+		if err != nil {
+			logger.Warn(
+				"failed to refresh the cached response",
+				slog.String("operation", functionName),
+				slog.String("error", err.Error()),
+			)
+
+			return
+		}
+
+		rc.set(refreshCtx, functionName, key, result, policy)
+	}()
+}
+
+func (rc *ResponseCache) createCacheKey(functionName string, arguments map[string]any, selection schema.NestedField) (string, error) {
+	rawBytes, err := json.Marshal([]any{arguments, selection})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(rawBytes)
+
+	return functionName + ":" + hex.EncodeToString(hash[:]), nil
+}
+
+// add the Warning and Age headers into the forwarded response headers of the stale response.
+func (rc *ResponseCache) markStaleResponse(value any, age time.Duration) any {
+	forwardHeaders := rc.config.ForwardHeaders
+	if !forwardHeaders.Enabled || forwardHeaders.ResponseHeaders == nil {
+		return value
+	}
+
+	result, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	staleHeaders := map[string]string{
+		"Warning": staleResponseWarning,
+		"Age":     strconv.FormatInt(int64(math.Floor(age.Seconds())), 10),
+	}
+
+	result = maps.Clone(result)
+	switch headers := result[forwardHeaders.ResponseHeaders.HeadersField].(type) {
+	case map[string]string:
+		newHeaders := maps.Clone(headers)
+		maps.Copy(newHeaders, staleHeaders)
+		result[forwardHeaders.ResponseHeaders.HeadersField] = newHeaders
+	case map[string]any:
+		newHeaders := maps.Clone(headers)
+		for key, header := range staleHeaders {
+			newHeaders[key] = header
+		}
+		result[forwardHeaders.ResponseHeaders.HeadersField] = newHeaders
+	default:
+		return value
+	}
+
+	return result
+}
+
+// server errors are errors of upstream services which are down or respond 5xx status.
+func isServerError(err error) bool {
+	var connectorError *schema.ConnectorError
+	if errors.As(err, &connectorError) {
+		return connectorError.StatusCode() >= http.StatusInternalServerError
+	}
+
+	return true
+}
+
+type memoryResponseCacheEntry struct {
+	value     CachedResponse
+	expiredAt time.Time
+}
+
+type memoryResponseCacheStore struct {
+	maxEntries int
+	entries    map[string]memoryResponseCacheEntry
+	lock       sync.Mutex
+}
+
+func newMemoryResponseCacheStore(maxEntries int) *memoryResponseCacheStore {
+	return &memoryResponseCacheStore{
+		maxEntries: maxEntries,
+		entries:    make(map[string]memoryResponseCacheEntry),
+	}
+}
+
+// Get returns the cached response of the key. Returns nil if not exists or expired.
+func (mcs *memoryResponseCacheStore) Get(_ context.Context, key string) (*CachedResponse, error) {
+	mcs.lock.Lock()
+	defer mcs.lock.Unlock()
+
+	entry, ok := mcs.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	if !entry.expiredAt.After(time.Now()) {
+		delete(mcs.entries, key)
+
+		return nil, nil
+	}
+
+	return &entry.value, nil
+}
+
+// Set stores the response with the expiry duration. Expired entries are evicted first if the store is full, then the oldest entry.
+func (mcs *memoryResponseCacheStore) Set(_ context.Context, key string, value CachedResponse, expiry time.Duration) error {
+	mcs.lock.Lock()
+	defer mcs.lock.Unlock()
+
+	if _, ok := mcs.entries[key]; !ok && len(mcs.entries) >= mcs.maxEntries {
+		mcs.evict()
+	}
+
+	mcs.entries[key] = memoryResponseCacheEntry{
+		value:     value,
+		expiredAt: time.Now().Add(expiry),
+	}
+
+	return nil
+}
+
+func (mcs *memoryResponseCacheStore) evict() {
+	now := time.Now()
+	var oldestKey string
+	var oldestTime time.Time
+
+	for key, entry := range mcs.entries {
+		if !entry.expiredAt.After(now) {
+			delete(mcs.entries, key)
+
+			continue
+		}
+
+		if oldestKey == "" || entry.value.StoredAt.Before(oldestTime) {
+			oldestKey = key
+			oldestTime = entry.value.StoredAt
+		}
+	}
+
+	if len(mcs.entries) >= mcs.maxEntries && oldestKey != "" {
+		delete(mcs.entries, oldestKey)
+	}
+}
+
+type memorySingleFlightLocker struct {
+	locks map[string]time.Time
+	lock  sync.Mutex
+}
+
+func newMemorySingleFlightLocker() *memorySingleFlightLocker {
+	return &memorySingleFlightLocker{
+		locks: make(map[string]time.Time),
+	}
+}
+
+// TryLock acquires the lock of the key with the expiry duration. Returns false if the key is locked by another caller.
+func (msl *memorySingleFlightLocker) TryLock(_ context.Context, key string, expiry time.Duration) (bool, error) {
+	msl.lock.Lock()
+	defer msl.lock.Unlock()
+
+	now := time.Now()
+	if expiredAt, ok := msl.locks[key]; ok && expiredAt.After(now) {
+		return false, nil
+	}
+
+	msl.locks[key] = now.Add(expiry)
+
+	return true, nil
+}
+
+// Unlock releases the lock of the key.
+func (msl *memorySingleFlightLocker) Unlock(_ context.Context, key string) error {
+	msl.lock.Lock()
+	delete(msl.locks, key)
+	msl.lock.Unlock()
+
+	return nil
+}
@@ -0,0 +1,200 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestResponseCacheStaleIfError(t *testing.T) {
+	config := &configuration.Configuration{
+		ForwardHeaders: configuration.ForwardHeadersSettings{
+			Enabled:       true,
+			ArgumentField: utils.ToPtr("headers"),
+			ResponseHeaders: &configuration.ForwardResponseHeadersSettings{
+				HeadersField: "headers",
+				ResultField:  "response",
+			},
+		},
+		ResponseCache: &configuration.ResponseCacheSettings{
+			Functions: map[string]configuration.FunctionCacheSettings{
+				"findPets": {
+					StaleIfError: 60,
+				},
+			},
+		},
+	}
+
+	cache := NewResponseCache(config, nil)
+	assert.Assert(t, cache != nil)
+
+	ctx := context.Background()
+	arguments := map[string]any{"status": "available"}
+	response := map[string]any{
+		"headers":  map[string]string{"Content-Type": "application/json"},
+		"response": []any{"dog"},
+	}
+
+	callCount := 0
+	result, err := cache.Execute(ctx, "findPets", arguments, nil, func(context.Context) (any, error) {
+		callCount++
+
+		return response, nil
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, response, result)
+
+	// the ttl is zero so the upstream is always called
+	result, err = cache.Execute(ctx, "findPets", arguments, nil, func(context.Context) (any, error) {
+		callCount++
+
+		return nil, schema.NewConnectorError(http.StatusBadGateway, "502 Bad Gateway", nil)
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, 2, callCount)
+	assert.DeepEqual(t, map[string]any{
+		"headers": map[string]string{
+			"Content-Type": "application/json",
+			"Warning":      staleResponseWarning,
+			"Age":          "0",
+		},
+		"response": []any{"dog"},
+	}, result)
+
+	// the cached response isn't mutated
+	assert.DeepEqual(t, map[string]string{"Content-Type": "application/json"}, response["headers"])
+
+	// client errors aren't recovered
+	_, err = cache.Execute(ctx, "findPets", arguments, nil, func(context.Context) (any, error) {
+		return nil, schema.UnprocessableContentError("400 Bad Request", nil)
+	})
+	assert.ErrorContains(t, err, "400 Bad Request")
+
+	// responses of other arguments aren't served
+	_, err = cache.Execute(ctx, "findPets", map[string]any{"status": "sold"}, nil, func(context.Context) (any, error) {
+		return nil, errors.New("connection refused")
+	})
+	assert.ErrorContains(t, err, "connection refused")
+
+	// functions without cache settings
+	_, err = cache.Execute(ctx, "getPetById", arguments, nil, func(context.Context) (any, error) {
+		return nil, errors.New("connection refused")
+	})
+	assert.ErrorContains(t, err, "connection refused")
+
+	var nilCache *ResponseCache
+	result, err = nilCache.Execute(ctx, "findPets", arguments, nil, func(context.Context) (any, error) {
+		return "ok", nil
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, "ok", result)
+}
+
+func TestResponseCacheTTL(t *testing.T) {
+	cache := NewResponseCache(&configuration.Configuration{
+		ResponseCache: &configuration.ResponseCacheSettings{
+			Functions: map[string]configuration.FunctionCacheSettings{
+				"findPets": {
+					TTL: 60,
+				},
+			},
+		},
+	}, nil)
+
+	callCount := 0
+	fetch := func(context.Context) (any, error) {
+		callCount++
+
+		return callCount, nil
+	}
+
+	for range 3 {
+		result, err := cache.Execute(context.Background(), "findPets", nil, nil, fetch)
+		assert.NilError(t, err)
+		assert.Equal(t, 1, result)
+	}
+}
+
+func TestMemoryResponseCacheStore(t *testing.T) {
+	ctx := context.Background()
+	store := newMemoryResponseCacheStore(2)
+	now := time.Now()
+	assert.NilError(t, store.Set(ctx, "a", CachedResponse{Value: 1, StoredAt: now.Add(-time.Second)}, time.Minute))
+	assert.NilError(t, store.Set(ctx, "b", CachedResponse{Value: 2, StoredAt: now}, time.Minute))
+	assert.NilError(t, store.Set(ctx, "c", CachedResponse{Value: 3, StoredAt: now}, time.Minute))
+
+	value, err := store.Get(ctx, "a")
+	assert.NilError(t, err)
+	assert.Assert(t, value == nil)
+
+	value, err = store.Get(ctx, "c")
+	assert.NilError(t, err)
+	assert.Equal(t, 3, value.Value)
+
+	assert.NilError(t, store.Set(ctx, "expired", CachedResponse{Value: 4, StoredAt: now}, -time.Second))
+	value, err = store.Get(ctx, "expired")
+	assert.NilError(t, err)
+	assert.Assert(t, value == nil)
+}
+
+func TestResponseCacheStaleWhileRevalidate(t *testing.T) {
+	cache := NewResponseCache(&configuration.Configuration{
+		ResponseCache: &configuration.ResponseCacheSettings{
+			Functions: map[string]configuration.FunctionCacheSettings{
+				"findPets": {
+					TTL:     60,
+					SoftTTL: 10,
+				},
+			},
+		},
+	}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	key, err := cache.createCacheKey("findPets", nil, nil)
+	assert.NilError(t, err)
+	assert.NilError(t, cache.store.Set(ctx, key, CachedResponse{Value: "old", StoredAt: time.Now().Add(-20 * time.Second)}, time.Minute))
+
+	refreshed := make(chan struct{})
+	result, err := cache.Execute(ctx, "findPets", nil, nil, func(ctx context.Context) (any, error) {
+		defer close(refreshed)
+		// the background refresh isn't canceled with the request context
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		return "new", nil
+	})
+	cancel()
+	assert.NilError(t, err)
+	assert.Equal(t, "old", result)
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("expected the cached response to be refreshed in the background")
+	}
+
+	assert.Assert(t, waitFor(func() bool {
+		cached, err := cache.store.Get(context.Background(), key)
+
+		return err == nil && cached != nil && cached.Value == "new"
+	}))
+}
+
+func waitFor(check func() bool) bool {
+	for range 100 {
+		if check() {
+			return true
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return false
+}
@@ -9,7 +9,6 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
-	"math"
 	"net/http"
 	"net/url"
 	"slices"
@@ -50,13 +49,22 @@ func (client *HTTPClient) Send(ctx context.Context, selection schema.NestedField
 		return result, headers, nil
 	}
 
-	if !httpOptions.Parallel || httpOptions.Concurrency <= 1 || len(client.requests.Requests) == 1 {
-		results, headers := client.sendSequence(ctx, client.requests.Requests, selection)
-
-		return results, headers, nil
+	var results *DistributedResponse[any]
+	var headers http.Header
+	switch {
+	case httpOptions.Strategy == rest.DistributedExecutionFailover:
+		results, headers = client.sendFailover(ctx, client.requests.Requests, selection)
+	case httpOptions.Strategy == rest.DistributedExecutionRace && len(client.requests.Requests) > 1:
+		results, headers = client.sendRace(ctx, client.requests.Requests, selection)
+	case !httpOptions.Parallel || httpOptions.Concurrency <= 1 || len(client.requests.Requests) == 1:
+		results, headers = client.sendSequence(ctx, client.requests.Requests, selection)
+	default:
+		results, headers = client.sendParallel(ctx, client.requests.Requests, selection)
 	}
 
-	results, headers := client.sendParallel(ctx, client.requests.Requests, selection)
+	if len(httpOptions.CompareServers) == 2 {
+		results.Comparison = compareDistributedResults(results.Results, httpOptions.CompareServers[0], httpOptions.CompareServers[1])
+	}
 
 	return results, headers, nil
 }
@@ -143,12 +151,93 @@ func (client *HTTPClient) sendParallel(ctx context.Context, requests []*Retryabl
 	return r, firstHeaders
 }
 
+// execute a request to remote servers in priority order until one succeeds.
+// Errors of failed servers are returned with the successful result.
+func (client *HTTPClient) sendFailover(ctx context.Context, requests []*RetryableRequest, selection schema.NestedField) (*DistributedResponse[any], http.Header) {
+	results := NewDistributedResponse[any]()
+	for _, req := range requests {
+		result, headers, err := client.sendSingle(ctx, req, selection, "failover")
+		if err == nil {
+			results.Results = append(results.Results, DistributedResult[any]{
+				Server: req.ServerID,
+				Data:   result,
+			})
+
+			return results, headers
+		}
+
+		results.Errors = append(results.Errors, DistributedError{
+			Server:         req.ServerID,
+			ConnectorError: *err,
+		})
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// execute a request to remote servers in parallel and return the first successful response.
+// Pending requests are cancelled, so only errors received before the successful response are returned.
+func (client *HTTPClient) sendRace(ctx context.Context, requests []*RetryableRequest, selection schema.NestedField) (*DistributedResponse[any], http.Header) {
+	type raceOutcome struct {
+		server  string
+		result  any
+		headers http.Header
+		err     *schema.ConnectorError
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// the channel is buffered so cancelled requests never block
+	outcomes := make(chan raceOutcome, len(requests))
+	for _, req := range requests {
+		go func(req RetryableRequest) {
+			result, headers, err := client.sendSingle(ctx, &req, selection, "race")
+			outcomes <- raceOutcome{
+				server:  req.ServerID,
+				result:  result,
+				headers: headers,
+				err:     err,
+			}
+		}(*req)
+	}
+
+	results := NewDistributedResponse[any]()
+	for range requests {
+		outcome := <-outcomes
+		if outcome.err != nil {
+			results.Errors = append(results.Errors, DistributedError{
+				Server:         outcome.server,
+				ConnectorError: *outcome.err,
+			})
+
+			continue
+		}
+
+		results.Results = append(results.Results, DistributedResult[any]{
+			Server: outcome.server,
+			Data:   outcome.result,
+		})
+
+		return results, outcome.headers
+	}
+
+	return results, nil
+}
+
 // execute a request to the remote server with retries
 func (client *HTTPClient) sendSingle(ctx context.Context, request *RetryableRequest, selection schema.NestedField, mode string) (any, http.Header, *schema.ConnectorError) {
 	ctx, span := tracer.Start(ctx, "Send Request to Server "+request.ServerID)
 	defer span.End()
 
 	span.SetAttributes(attribute.String("execution.mode", mode))
+	if tags := client.operationTags(); len(tags) > 0 {
+		span.SetAttributes(attribute.StringSlice(operationTagsAttribute, tags))
+	}
 
 	requestURL := request.URL.String()
 	rawPort := request.URL.Port()
@@ -175,97 +264,234 @@ func (client *HTTPClient) sendSingle(ctx context.Context, request *RetryableRequ
 		logger.Debug("sending request to remote server...", logAttrs...)
 	}
 
+	// the raw body is captured before compression
+	debugCapture := GetDebugCapture(ctx)
+	rawBody := request.Body
+
+	if encodeErr := client.encodeProtobufRequestBody(request); encodeErr != nil {
+		return nil, nil, client.failRequest(ctx, span, request, "failed to execute the request", encodeErr)
+	}
+
 	contentEncoding := request.Headers.Get(rest.ContentEncodingHeader)
+	if contentEncoding == "" && request.Runtime.Compression != "" && len(request.Body) > 0 &&
+		uint(len(request.Body)) >= request.Runtime.CompressionMinBytes {
+		contentEncoding = string(request.Runtime.Compression)
+		request.Headers.Set(rest.ContentEncodingHeader, contentEncoding)
+	}
+
 	if len(request.Body) > 0 && client.manager.compressors.IsEncodingSupported(contentEncoding) {
 		var buf bytes.Buffer
 		_, err := client.manager.compressors.Compress(&buf, contentEncoding, request.Body)
 		if err != nil {
-			span.SetStatus(codes.Error, "failed to execute the request")
-			span.RecordError(err)
-
-			return nil, nil, schema.NewConnectorError(http.StatusInternalServerError, err.Error(), nil)
+			return nil, nil, client.failRequest(ctx, span, request, "failed to execute the request", classifyRequestError(err))
 		}
 
+		client.manager.metrics.RecordRequestPayloadSize(ctx, int64(len(request.Body)), int64(buf.Len()), contentEncoding, client.metricAttributes(request)...)
 		request.Body = buf.Bytes()
+	} else if len(request.Body) > 0 {
+		client.manager.metrics.RecordRequestPayloadSize(ctx, int64(len(request.Body)), 0, "", client.metricAttributes(request)...)
 	}
 
 	var resp *http.Response
 	var errorBytes []byte
-	var err error
 	var cancel context.CancelFunc
+	var upstreamErr *UpstreamError
+	var exchange *DebugCaptureExchange
 
 	times := int(request.Runtime.Retry.Times)
-	delayMs := int(math.Max(float64(request.Runtime.Retry.Delay), 100))
 	for i := 0; i <= times; i++ {
+		var err error
+		auditEntry := client.manager.auditLogger.Start(ctx, client.requests.OperationName, request, rawBody, i)
+		attemptStart := time.Now()
 		resp, errorBytes, cancel, err = client.doRequest(ctx, request, port, i) //nolint:all
-		if err != nil {
-			span.SetStatus(codes.Error, "failed to execute the request")
-			span.RecordError(err)
+		auditEntry.Finish(resp, errorBytes, err, time.Since(attemptStart))
+		switch {
+		case err != nil:
+			upstreamErr = classifyRequestError(err)
+		case resp.StatusCode >= 400:
+			upstreamErr = newHTTPStatusError(resp.StatusCode, resp.Status, nil)
+			if request.RawRequest.SOAP != nil {
+				upstreamErr = evalSOAPFaultError(upstreamErr, errorBytes)
+			}
+		default:
+			upstreamErr = nil
+		}
+
+		exchange = debugCapture.AddExchange(request, rawBody, i, resp, errorBytes, err)
+		if upstreamErr != nil {
+			exchange.SetError(upstreamErr)
+		}
+
+		if upstreamErr == nil || i >= times || ctx.Err() != nil || !upstreamErr.Retryable(request.Runtime.Retry) {
+			break
+		}
 
-			return nil, nil, schema.NewConnectorError(http.StatusInternalServerError, err.Error(), nil)
+		delay, ok := getRetryDelay(request.Runtime.Retry, i, resp, time.Now())
+		// the upstream asks for a longer wait than the max delay, so the upstream error is returned without retrying
+		if !ok {
+			break
 		}
 
-		if (resp.StatusCode >= 200 && resp.StatusCode < 299) ||
-			!slices.Contains(request.Runtime.Retry.HTTPStatus, resp.StatusCode) || i >= times {
+		// don't wait for the retry if the request can't be sent before the deadline
+		if deadline, ok := ctx.Deadline(); ok && time.Now().Add(delay).After(deadline) {
 			break
 		}
 
 		if logger.Enabled(ctx, slog.LevelDebug) {
-			logger.Debug(
-				fmt.Sprintf("received error from remote server, retry %d of %d...", i+1, times),
-				slog.Int("http_status", resp.StatusCode),
-				slog.Any("response_headers", resp.Header),
-				slog.String("response_body", string(errorBytes)),
-			)
+			logAttrs := []any{
+				slog.String("error_type", string(upstreamErr.Category)),
+				slog.String("error", upstreamErr.Error()),
+				slog.Int64("retry_delay_ms", delay.Milliseconds()),
+			}
+			if resp != nil {
+				logAttrs = append(logAttrs,
+					slog.Int("http_status", resp.StatusCode),
+					slog.Any("response_headers", resp.Header),
+					slog.String("response_body", string(errorBytes)),
+				)
+			}
+
+			logger.Debug(fmt.Sprintf("received error from remote server, retry %d of %d...", i+1, times), logAttrs...)
+		}
+
+		if cancel != nil {
+			cancel()
+		}
+
+		client.manager.metrics.RecordRetry(ctx, upstreamErr.Category, client.metricAttributes(request)...)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+		case <-timer.C:
 		}
+	}
 
-		time.Sleep(time.Duration(delayMs) * time.Millisecond)
+	if resp == nil {
+		return nil, nil, client.failRequest(ctx, span, request, "failed to execute the request", upstreamErr)
 	}
 
 	defer cancel()
 
 	contentType := parseContentType(resp.Header.Get(rest.ContentTypeHeader))
-	if resp.StatusCode >= 400 {
-		details := make(map[string]any)
-		switch contentType {
-		case rest.ContentTypeJSON:
-			if json.Valid(errorBytes) {
-				details["error"] = json.RawMessage(errorBytes)
-			} else {
-				details["error"] = string(errorBytes)
-			}
-		case rest.ContentTypeXML:
-			errData, err := contenttype.DecodeArbitraryXML(bytes.NewReader(errorBytes))
-			if err != nil {
-				details["error"] = string(errorBytes)
-			} else {
-				details["error"] = errData
-			}
-		default:
-			details["error"] = string(errorBytes)
+	if upstreamErr != nil && client.isNullErrorResponse(upstreamErr.StatusCode) {
+		return client.evalNullErrorResponse(resp, selection)
+	}
+
+	if upstreamErr != nil {
+		if upstreamErr.Details == nil {
+			upstreamErr.Details = evalErrorResponseDetails(contentType, errorBytes)
 		}
+		client.evalTypedErrorDetails(upstreamErr, contentType, errorBytes, logger)
 
-		span.SetStatus(codes.Error, "received error from remote server")
+		return nil, nil, client.failRequest(ctx, span, request, "received error from remote server", upstreamErr)
+	}
 
-		statusCode := resp.StatusCode
-		if statusCode < 500 {
-			statusCode = http.StatusUnprocessableEntity
-		}
+	limitedBody, _ := resp.Body.(*limitedReadCloser)
+	decodeDuration := time.Duration(request.Runtime.MaxDecodeDurationMs) * time.Millisecond
+	if decodeDuration > 0 {
+		resp.Body = newDeadlineReadCloser(resp.Body, decodeDuration)
+	}
 
-		return nil, nil, schema.NewConnectorError(statusCode, resp.Status, details)
+	startTime := time.Now()
+	result, headers, evalErr := client.evalHTTPResponse(ctx, span, resp, contentType, selection, request.Runtime.ValidateResponse, logger)
+	if evalErr == nil && decodeDuration > 0 && time.Since(startTime) > decodeDuration {
+		evalErr = NewUpstreamError(ErrorCategoryTimeout, errDecodeDurationExceeded(decodeDuration).Error(), nil)
+	}
+
+	// decoders wrap read errors, so the size limit error is restored for a clear message
+	if evalErr != nil && limitedBody != nil && limitedBody.Exceeded() {
+		evalErr = limitedBody.error()
 	}
 
-	result, headers, evalErr := client.evalHTTPResponse(ctx, span, resp, contentType, selection, logger)
 	if evalErr != nil {
-		span.SetStatus(codes.Error, "failed to decode the http response")
-		span.RecordError(evalErr)
+		exchange.SetDecodeResult(contentType, nil, evalErr)
+	} else {
+		exchange.SetDecodeResult(contentType, result, nil)
+	}
 
-		return nil, nil, evalErr
+	if evalErr != nil {
+		return nil, nil, client.failRequest(ctx, span, request, "failed to decode the http response", evalErr)
 	}
 
 	return result, headers, nil
 }
 
+// record the classified error to the span and metrics, then convert it to the NDC error.
+func (client *HTTPClient) failRequest(ctx context.Context, span trace.Span, request *RetryableRequest, description string, err *UpstreamError) *schema.ConnectorError {
+	span.SetStatus(codes.Error, description)
+	span.SetAttributes(attribute.String("error.type", string(err.Category)))
+	span.RecordError(err)
+	client.manager.metrics.RecordRequestError(ctx, err.Category, client.metricAttributes(request)...)
+
+	return client.mapConnectorError(err)
+}
+
+func evalErrorResponseDetails(contentType string, errorBytes []byte) map[string]any {
+	details := make(map[string]any)
+	switch contentType {
+	case rest.ContentTypeJSON:
+		if json.Valid(errorBytes) {
+			details["error"] = json.RawMessage(errorBytes)
+		} else {
+			details["error"] = string(errorBytes)
+		}
+	case rest.ContentTypeXML:
+		errData, err := contenttype.DecodeArbitraryXML(bytes.NewReader(errorBytes))
+		if err != nil {
+			details["error"] = string(errorBytes)
+		} else {
+			details["error"] = errData
+		}
+	default:
+		details["error"] = string(errorBytes)
+	}
+
+	return details
+}
+
+// decode the error response body into the typedError field of error details with the error schema of the status code.
+// The raw error is kept, and the typed error is omitted if the body doesn't match the schema.
+func (client *HTTPClient) evalTypedErrorDetails(upstreamErr *UpstreamError, contentType string, errorBytes []byte, logger *slog.Logger) {
+	if len(errorBytes) == 0 || client.requests.Operation == nil || client.requests.Operation.Request == nil ||
+		client.requests.Schema == nil || client.requests.Schema.NDCHttpSchema == nil {
+		return
+	}
+
+	errResponse, ok := client.requests.Operation.Request.Response.FindError(upstreamErr.StatusCode)
+	if !ok {
+		return
+	}
+
+	var typedError any
+	var err error
+	switch {
+	case restUtils.IsContentTypeJSON(contentType):
+		typedError, err = contenttype.NewJSONDecoder(client.requests.Schema.NDCHttpSchema).Decode(bytes.NewReader(errorBytes), errResponse.Type)
+	case restUtils.IsContentTypeXML(contentType):
+		typedError, err = contenttype.NewXMLDecoder(client.requests.Schema.NDCHttpSchema).Decode(bytes.NewReader(errorBytes), errResponse.Type)
+	default:
+		return
+	}
+
+	if err != nil {
+		logger.Debug("failed to decode the typed error response", slog.Int("http_status", upstreamErr.StatusCode), slog.String("error", err.Error()))
+
+		return
+	}
+
+	if violations := ValidateResponse(client.requests.Schema.NDCHttpSchema, errResponse.Type, typedError, false); len(violations) > 0 {
+		logger.Debug("the error response doesn't match the error schema", slog.Int("http_status", upstreamErr.StatusCode), slog.Any("violations", violations))
+
+		return
+	}
+
+	if upstreamErr.Details == nil {
+		upstreamErr.Details = make(map[string]any)
+	}
+	upstreamErr.Details["typedError"] = typedError
+}
+
 func (client *HTTPClient) doRequest(ctx context.Context, request *RetryableRequest, port int, retryCount int) (*http.Response, []byte, context.CancelFunc, error) {
 	method := strings.ToUpper(request.RawRequest.Method)
 	ctx, span := tracer.Start(ctx, fmt.Sprintf("%s %s", method, request.RawRequest.URL), trace.WithSpanKind(trace.SpanKindClient))
@@ -306,7 +532,7 @@ func (client *HTTPClient) doRequest(ctx context.Context, request *RetryableReque
 	setHeaderAttributes(span, "http.request.header.", request.Headers)
 
 	client.manager.propagator.Inject(ctx, propagation.HeaderCarrier(request.Headers))
-	resp, cancel, err := client.manager.ExecuteRequest(ctx, request, namespace)
+	resp, cancel, err := client.manager.ExecuteRequest(ctx, request, namespace, client.metricAttributes(request)...)
 	if err != nil {
 		span.SetStatus(codes.Error, "error happened when executing the request")
 		span.RecordError(err)
@@ -321,14 +547,42 @@ func (client *HTTPClient) doRequest(ctx context.Context, request *RetryableReque
 		span.SetAttributes(attribute.Int64("http.response.size", resp.ContentLength))
 	}
 
-	resp.Body, err = client.manager.compressors.Decompress(resp.Body, resp.Header.Get(rest.ContentEncodingHeader))
+	contentEncoding := resp.Header.Get(rest.ContentEncodingHeader)
+	if !client.manager.compressors.IsEncodingSupported(contentEncoding) {
+		contentEncoding = ""
+	}
+
+	compressedBody := newCountingReadCloser(resp.Body, nil)
+	resp.Body, err = client.manager.compressors.Decompress(compressedBody, contentEncoding)
 	if err != nil {
 		span.SetStatus(codes.Error, "error happened when decompressing the response body")
 		span.RecordError(err)
+		cancel()
 
 		return nil, nil, nil, err
 	}
 
+	resp.Body = newCountingReadCloser(resp.Body, func(count int64) {
+		client.manager.metrics.RecordResponsePayloadSize(ctx, count, compressedBody.count, contentEncoding, client.metricAttributes(request)...)
+	})
+	// transcode non UTF-8 charsets, e.g. ISO-8859-1, UTF-16 before decoding and logging
+	resp.Body = contenttype.NewUTF8ReadCloser(resp.Body, resp.Header.Get(rest.ContentTypeHeader))
+	if request.Runtime.MaxResponseBytes > 0 {
+		maxResponseBytes := int64(request.Runtime.MaxResponseBytes)
+		// abort before reading if the declared size of the uncompressed body already exceeds the limit
+		if contentEncoding == "" && resp.ContentLength > maxResponseBytes {
+			_ = resp.Body.Close()
+			cancel()
+			err := newResponseTooLargeError(maxResponseBytes, resp.ContentLength)
+			span.SetStatus(codes.Error, err.Message)
+			span.RecordError(err)
+
+			return nil, nil, nil, err
+		}
+
+		resp.Body = newLimitedReadCloser(resp.Body, maxResponseBytes)
+	}
+
 	if resp.StatusCode < 300 {
 		return resp, nil, cancel, nil
 	}
@@ -346,14 +600,20 @@ func (client *HTTPClient) doRequest(ctx context.Context, request *RetryableReque
 	return resp, body, cancel, nil
 }
 
-func (client *HTTPClient) evalHTTPResponse(ctx context.Context, span trace.Span, resp *http.Response, contentType string, selection schema.NestedField, logger *slog.Logger) (any, http.Header, *schema.ConnectorError) {
+func (client *HTTPClient) evalHTTPResponse(ctx context.Context, span trace.Span, resp *http.Response, contentType string, selection schema.NestedField, validation rest.ResponseValidationMode, logger *slog.Logger) (any, http.Header, *UpstreamError) {
+	if client.isRedirectResponse() {
+		return client.evalRedirectResponse(resp, selection)
+	}
+
 	resultType := client.requests.Operation.ResultType
+	stream := client.responseStreamSettings()
 	if logger.Enabled(ctx, slog.LevelDebug) {
 		logAttrs := []any{
 			slog.Int("http_status", resp.StatusCode),
 			slog.Any("response_headers", resp.Header),
 		}
-		if resp.Body != nil && resp.StatusCode != http.StatusNoContent {
+		// streamed bodies are too large or endless to be logged
+		if resp.Body != nil && resp.StatusCode != http.StatusNoContent && stream == nil {
 			respBody, readErr := io.ReadAll(resp.Body)
 			_ = resp.Body.Close()
 
@@ -361,7 +621,7 @@ func (client *HTTPClient) evalHTTPResponse(ctx context.Context, span trace.Span,
 				span.SetStatus(codes.Error, "error happened when reading response body")
 				span.RecordError(readErr)
 
-				return nil, nil, schema.NewConnectorError(http.StatusInternalServerError, "error happened when reading response body", map[string]any{
+				return nil, nil, NewUpstreamError(ErrorCategoryDecode, "error happened when reading response body", map[string]any{
 					"error": readErr.Error(),
 				})
 			}
@@ -386,12 +646,67 @@ func (client *HTTPClient) evalHTTPResponse(ctx context.Context, span trace.Span,
 		return nil, resp.Header, nil
 	}
 
+	customDecoder, hasCustomDecoder := client.manager.decoders.Find(contentType)
+	if stream == nil && !hasCustomDecoder {
+		body, transcodedType, transcodeErr := client.transcodeResponseBody(resp.Body, contentType)
+		if transcodeErr != nil {
+			return nil, nil, transcodeErr
+		}
+
+		if transcodedType != contentType {
+			_ = resp.Body.Close()
+			resp.Body = io.NopCloser(body)
+			contentType = transcodedType
+		}
+
+		if envelope := client.responseEnvelope(); envelope != "" && restUtils.IsContentTypeJSON(contentType) {
+			body, err := unwrapResponseEnvelope(resp.Body, envelope)
+			if err != nil {
+				return nil, nil, NewUpstreamError(ErrorCategoryDecode, "failed to unwrap the response envelope", map[string]any{
+					"cause": err.Error(),
+				})
+			}
+
+			_ = resp.Body.Close()
+			resp.Body = io.NopCloser(body)
+		}
+	}
+
 	var result any
 	switch {
+	case stream != nil:
+		var err error
+		switch stream.Mode {
+		case rest.ResponseStreamSSE:
+			result, err = contenttype.DecodeServerSentEvents(resp.Body, int(stream.MaxChunks))
+		default:
+			result, err = contenttype.DecodeBinaryChunks(resp.Body, int(stream.ChunkSize), int(stream.MaxChunks))
+		}
+
+		if err != nil {
+			return nil, nil, NewUpstreamError(ErrorCategoryDecode, "failed to read the response stream", map[string]any{
+				"cause": err.Error(),
+			})
+		}
+	case hasCustomDecoder:
+		var err error
+		result, err = customDecoder.Decode(resp.Body, contentType)
+		if err != nil {
+			return nil, nil, NewUpstreamError(ErrorCategoryDecode, "failed to decode the response body", map[string]any{
+				"cause": err.Error(),
+			})
+		}
+	case client.requests.Operation.Request != nil && client.requests.Operation.Request.SOAP != nil &&
+		(restUtils.IsContentTypeXML(contentType) || contentType == rest.ContentTypeTextXML):
+		var soapErr *UpstreamError
+		result, soapErr = client.evalSOAPResponse(resp.Body, resultType)
+		if soapErr != nil {
+			return nil, nil, soapErr
+		}
 	case restUtils.IsContentTypeText(contentType):
 		respBody, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return nil, nil, schema.NewConnectorError(http.StatusInternalServerError, err.Error(), nil)
+			return nil, nil, NewUpstreamError(ErrorCategoryDecode, err.Error(), nil)
 		}
 
 		result = string(respBody)
@@ -404,7 +719,7 @@ func (client *HTTPClient) evalHTTPResponse(ctx context.Context, span trace.Span,
 		var err error
 		result, err = contenttype.NewXMLDecoder(client.requests.Schema.NDCHttpSchema).Decode(resp.Body, field)
 		if err != nil {
-			return nil, nil, schema.NewConnectorError(http.StatusInternalServerError, err.Error(), nil)
+			return nil, nil, NewUpstreamError(ErrorCategoryDecode, err.Error(), nil)
 		}
 	case restUtils.IsContentTypeJSON(contentType):
 		if len(resultType) > 0 {
@@ -412,7 +727,7 @@ func (client *HTTPClient) evalHTTPResponse(ctx context.Context, span trace.Span,
 			if err == nil && namedType.Name == string(rest.ScalarString) {
 				respBytes, err := io.ReadAll(resp.Body)
 				if err != nil {
-					return nil, nil, schema.NewConnectorError(http.StatusInternalServerError, "failed to read response", map[string]any{
+					return nil, nil, NewUpstreamError(ErrorCategoryDecode, "failed to read response", map[string]any{
 						"reason": err.Error(),
 					})
 				}
@@ -429,29 +744,43 @@ func (client *HTTPClient) evalHTTPResponse(ctx context.Context, span trace.Span,
 			}
 		}
 
-		var err error
+		var respBody io.Reader = resp.Body
+		if client.requests.Operation.Request != nil && client.requests.Operation.Request.GraphQL != nil {
+			data, gqlErr := client.evalGraphQLResponse(resp.Body, client.requests.Operation.Request.GraphQL)
+			if gqlErr != nil {
+				return nil, nil, gqlErr
+			}
+
+			respBody = bytes.NewReader(data)
+		}
+
+		respBody, err := client.manager.transformResponseBody(client.requests.OperationName, respBody)
+		if err != nil {
+			return nil, nil, NewUpstreamError(ErrorCategoryDecode, err.Error(), nil)
+		}
+
 		if client.requests.Schema == nil || client.requests.Schema.NDCHttpSchema == nil {
-			err = json.NewDecoder(resp.Body).Decode(&result)
+			err = json.NewDecoder(respBody).Decode(&result)
 		} else {
 			responseType, extractErr := client.extractResultType(resultType)
 			if extractErr != nil {
 				return nil, nil, extractErr
 			}
 
-			result, err = contenttype.NewJSONDecoder(client.requests.Schema.NDCHttpSchema).Decode(resp.Body, responseType)
+			result, err = contenttype.NewJSONDecoder(client.requests.Schema.NDCHttpSchema).Decode(respBody, responseType)
 		}
 
 		if err != nil {
-			return nil, nil, schema.NewConnectorError(http.StatusInternalServerError, err.Error(), nil)
+			return nil, nil, NewUpstreamError(ErrorCategoryDecode, err.Error(), nil)
 		}
-	case contentType == rest.ContentTypeNdJSON:
+	case restUtils.IsContentTypeNdJSON(contentType):
 		var results []any
 		decoder := json.NewDecoder(resp.Body)
 		for decoder.More() {
 			var r any
 			err := decoder.Decode(&r)
 			if err != nil {
-				return nil, nil, schema.NewConnectorError(http.StatusInternalServerError, err.Error(), nil)
+				return nil, nil, NewUpstreamError(ErrorCategoryDecode, err.Error(), nil)
 			}
 			results = append(results, r)
 		}
@@ -460,15 +789,32 @@ func (client *HTTPClient) evalHTTPResponse(ctx context.Context, span trace.Span,
 	case restUtils.IsContentTypeBinary(contentType):
 		rawBytes, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return nil, nil, schema.NewConnectorError(http.StatusInternalServerError, err.Error(), nil)
+			return nil, nil, NewUpstreamError(ErrorCategoryDecode, err.Error(), nil)
 		}
 		result = base64.StdEncoding.EncodeToString(rawBytes)
 	default:
-		return nil, nil, schema.NewConnectorError(http.StatusInternalServerError, "failed to evaluate response", map[string]any{
+		return nil, nil, NewUpstreamError(ErrorCategoryDecode, "failed to evaluate response", map[string]any{
 			"cause": "unsupported content type " + contentType,
 		})
 	}
 
+	if client.requests.Schema != nil {
+		var err error
+		result, err = client.manager.DecryptResponse(client.requests.Schema.Name, client.requests.OperationName, result)
+		if err != nil {
+			return nil, nil, NewUpstreamError(ErrorCategoryInternal, err.Error(), nil)
+		}
+	}
+
+	result = normalizeResponse(result, client.responseNormalizeSettings())
+
+	// streamed chunks aren't the response body of the schema
+	if validation != "" && stream == nil {
+		if validateErr := client.validateResponse(ctx, span, validation, result, logger); validateErr != nil {
+			return nil, nil, validateErr
+		}
+	}
+
 	result = client.createHeaderForwardingResponse(result, resp.Header)
 	if len(selection) == 0 {
 		return result, resp.Header, nil
@@ -476,20 +822,190 @@ func (client *HTTPClient) evalHTTPResponse(ctx context.Context, span trace.Span,
 
 	result, err := utils.EvalNestedColumnFields(selection, result)
 	if err != nil {
-		return nil, nil, schema.InternalServerError(err.Error(), nil)
+		return nil, nil, NewUpstreamError(ErrorCategoryInternal, err.Error(), nil)
 	}
 
 	return result, resp.Header, nil
 }
 
-func (client *HTTPClient) extractResultType(resultType schema.Type) (schema.Type, *schema.ConnectorError) {
-	if !client.manager.config.ForwardHeaders.Enabled || client.manager.config.ForwardHeaders.ResponseHeaders == nil || client.manager.config.ForwardHeaders.ResponseHeaders.ResultField == "" {
+// validate the decoded response body against the result type. Violations fail the request in the error mode,
+// or are logged and recorded in the span in the warn mode.
+func (client *HTTPClient) validateResponse(ctx context.Context, span trace.Span, mode rest.ResponseValidationMode, result any, logger *slog.Logger) *UpstreamError {
+	if client.requests.Schema == nil || client.requests.Schema.NDCHttpSchema == nil {
+		return nil
+	}
+
+	resultType, extractErr := client.extractResultType(client.requests.Operation.ResultType)
+	if extractErr != nil {
+		return extractErr
+	}
+
+	// fields may be omitted by the upstream API if the field selection is pushed down
+	partial := client.requests.Operation.Request != nil && client.requests.Operation.Request.FieldSelection != nil
+	violations := ValidateResponse(client.requests.Schema.NDCHttpSchema, resultType, result, partial)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if mode == rest.ResponseValidationError {
+		return NewUpstreamError(ErrorCategoryDecode, "the response body doesn't match the schema", map[string]any{
+			"errors": violations,
+		})
+	}
+
+	span.AddEvent("response_validation_failed", trace.WithAttributes(
+		attribute.Int("http.response.validation_errors", len(violations)),
+	))
+	logger.WarnContext(ctx, "the response body doesn't match the schema",
+		slog.String("operation", client.requests.OperationName),
+		slog.Any("errors", violations),
+	)
+
+	return nil
+}
+
+// unwrap the result from the body of the SOAP envelope. Faults in the body fail the request
+func (client *HTTPClient) evalSOAPResponse(body io.Reader, resultType schema.Type) (any, *UpstreamError) {
+	field, extractErr := client.extractResultType(resultType)
+	if extractErr != nil {
+		return nil, extractErr
+	}
+
+	var httpSchema *rest.NDCHttpSchema
+	if client.requests.Schema != nil {
+		httpSchema = client.requests.Schema.NDCHttpSchema
+	}
+
+	result, err := contenttype.NewXMLDecoder(httpSchema).DecodeSOAP(body, field)
+	if err == nil {
+		return result, nil
+	}
+
+	var fault *contenttype.SOAPFault
+	if !errors.As(err, &fault) {
+		return nil, NewUpstreamError(ErrorCategoryDecode, err.Error(), nil)
+	}
+
+	category := ErrorCategoryServer
+	if fault.IsSenderFault() {
+		category = ErrorCategoryClient
+	}
+
+	return nil, &UpstreamError{
+		Category: category,
+		Message:  fault.Error(),
+		Details:  fault.ToMap(),
+		Cause:    fault,
+	}
+}
+
+// evalSOAPFaultError reads the SOAP fault of the error response.
+// Sender faults are client errors which aren't retried, following the HTTP binding of SOAP 1.2.
+func evalSOAPFaultError(upstreamErr *UpstreamError, errorBytes []byte) *UpstreamError {
+	fault := contenttype.DecodeSOAPFault(bytes.NewReader(errorBytes))
+	if fault == nil {
+		return upstreamErr
+	}
+
+	upstreamErr.Message = fault.Error()
+	upstreamErr.Details = fault.ToMap()
+	upstreamErr.Cause = fault
+	if fault.IsSenderFault() {
+		upstreamErr.Category = ErrorCategoryClient
+		upstreamErr.StatusCode = http.StatusBadRequest
+	}
+
+	return upstreamErr
+}
+
+// unwrap the result field from the data of the GraphQL response. Errors in the response fail the request
+func (client *HTTPClient) evalGraphQLResponse(body io.Reader, gqlRequest *rest.GraphQLRequest) ([]byte, *UpstreamError) {
+	var gqlResponse struct {
+		Data   map[string]json.RawMessage `json:"data"`
+		Errors []any                      `json:"errors"`
+	}
+
+	if err := json.NewDecoder(body).Decode(&gqlResponse); err != nil {
+		return nil, NewUpstreamError(ErrorCategoryDecode, "failed to decode the GraphQL response", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	if len(gqlResponse.Errors) > 0 {
+		return nil, NewUpstreamError(ErrorCategoryClient, "the GraphQL response has errors", map[string]any{
+			"errors": gqlResponse.Errors,
+		})
+	}
+
+	data, ok := gqlResponse.Data[gqlRequest.ResultField]
+	if !ok {
+		return []byte("null"), nil
+	}
+
+	return data, nil
+}
+
+func (client *HTTPClient) responseStreamSettings() *rest.ResponseStreamSettings {
+	if client.requests.Operation == nil || client.requests.Operation.Request == nil {
+		return nil
+	}
+
+	return client.requests.Operation.Request.Response.Stream
+}
+
+func (client *HTTPClient) responseNormalizeSettings() *rest.ResponseNormalizeSettings {
+	if client.requests.Operation == nil || client.requests.Operation.Request == nil {
+		return nil
+	}
+
+	return client.requests.Operation.Request.Response.Normalize
+}
+
+func (client *HTTPClient) responseEnvelope() rest.ResponseEnvelope {
+	if client.requests.Operation == nil || client.requests.Operation.Request == nil {
+		return ""
+	}
+
+	return client.requests.Operation.Request.Response.Envelope
+}
+
+func (client *HTTPClient) metricAttributes(request *RetryableRequest) []attribute.KeyValue {
+	attrs := []attribute.KeyValue{
+		attribute.String("db.operation.name", client.requests.OperationName),
+	}
+
+	if client.requests.Schema != nil && client.requests.Schema.Name != "" {
+		attrs = append(attrs, attribute.String("db.namespace", client.requests.Schema.Name))
+	}
+
+	if tags := client.operationTags(); len(tags) > 0 {
+		attrs = append(attrs, attribute.StringSlice(operationTagsAttribute, tags))
+	}
+
+	if request != nil && request.ServerID != "" {
+		attrs = append(attrs, attribute.String(serverIDAttribute, request.ServerID))
+	}
+
+	return attrs
+}
+
+// operationTags returns tags of the operation, e.g. OpenAPI tags, to group upstream telemetry by domain.
+func (client *HTTPClient) operationTags() []string {
+	if client.requests.Operation == nil {
+		return nil
+	}
+
+	return client.requests.Operation.Tags
+}
+
+func (client *HTTPClient) extractResultType(resultType schema.Type) (schema.Type, *UpstreamError) {
+	if !client.isHeaderForwardingResponse() || client.manager.config.ForwardHeaders.ResponseHeaders.ResultField == "" {
 		return resultType, nil
 	}
 
 	result, err := client.extractForwardedHeadersResultType(resultType)
 	if err != nil {
-		return nil, schema.NewConnectorError(http.StatusInternalServerError, "failed to extract forwarded headers response: "+err.Error(), nil)
+		return nil, NewUpstreamError(ErrorCategoryInternal, "failed to extract forwarded headers response: "+err.Error(), nil)
 	}
 
 	return result, nil
@@ -526,18 +1042,20 @@ func (client *HTTPClient) extractForwardedHeadersResultType(resultType schema.Ty
 }
 
 func (client *HTTPClient) createHeaderForwardingResponse(result any, rawHeaders http.Header) any {
-	forwardHeaders := client.manager.config.ForwardHeaders
-	if !forwardHeaders.Enabled || forwardHeaders.ResponseHeaders == nil {
+	if !client.isHeaderForwardingResponse() {
 		return result
 	}
 
+	forwardHeaders := client.manager.config.ForwardHeaders
 	headers := make(map[string]string)
 	for key, values := range rawHeaders {
 		if len(forwardHeaders.ResponseHeaders.ForwardHeaders) > 0 && !slices.Contains(forwardHeaders.ResponseHeaders.ForwardHeaders, key) {
 			continue
 		}
-		if len(values) > 0 && values[0] != "" {
-			headers[key] = values[0]
+
+		name, ok := client.manager.headersMapper.MapResponseHeader(key)
+		if ok && len(values) > 0 && values[0] != "" {
+			headers[name] = values[0]
 		}
 	}
 
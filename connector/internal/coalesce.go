@@ -0,0 +1,286 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+const (
+	defaultCoalescingWindow  = 25 * time.Millisecond
+	defaultCoalescingMaxSize = 100
+)
+
+// CoalescedBatchExecutor executes the batch procedure with arguments.
+type CoalescedBatchExecutor func(ctx context.Context, procedureName string, arguments map[string]any) (any, error)
+
+// RequestCoalescer groups individual procedure calls within a short window into upstream batch calls.
+type RequestCoalescer struct {
+	procedures map[string]coalescingProcedure
+	executor   CoalescedBatchExecutor
+	batches    map[string]*coalescedBatch
+	lock       sync.Mutex
+}
+
+type coalescingProcedure struct {
+	batchProcedure string
+	itemArgument   string
+	batchArgument  string
+	window         time.Duration
+	maxSize        int
+}
+
+type coalescedBatch struct {
+	ctx       context.Context
+	procedure coalescingProcedure
+	arguments map[string]any
+	items     []any
+	waiters   []chan coalescedResult
+	timer     *time.Timer
+}
+
+type coalescedResult struct {
+	value any
+	err   error
+}
+
+// NewRequestCoalescer creates a new RequestCoalescer instance. Returns nil if request coalescing isn't configured.
+func NewRequestCoalescer(config *configuration.Configuration, metadata MetadataCollection, executor CoalescedBatchExecutor) (*RequestCoalescer, error) {
+	if config == nil || config.RequestCoalescing == nil || len(config.RequestCoalescing.Procedures) == 0 {
+		return nil, nil
+	}
+
+	procedures := make(map[string]coalescingProcedure)
+	for name, setting := range config.RequestCoalescing.Procedures {
+		procedure := coalescingProcedure{
+			batchProcedure: setting.BatchProcedure,
+			itemArgument:   setting.ItemArgument,
+			batchArgument:  setting.BatchArgument,
+			window:         defaultCoalescingWindow,
+			maxSize:        defaultCoalescingMaxSize,
+		}
+
+		if procedure.itemArgument == "" {
+			procedure.itemArgument = rest.BodyKey
+		}
+
+		if procedure.batchArgument == "" {
+			procedure.batchArgument = rest.BodyKey
+		}
+
+		if setting.Window > 0 {
+			procedure.window = time.Duration(setting.Window) * time.Millisecond
+		}
+
+		if setting.MaxSize > 0 {
+			procedure.maxSize = int(setting.MaxSize)
+		}
+
+		operation, _, err := metadata.GetProcedure(name)
+		if err != nil {
+			return nil, fmt.Errorf("requestCoalescing.procedures.%s: %w", name, err)
+		}
+
+		if _, ok := operation.Arguments[procedure.itemArgument]; !ok {
+			return nil, fmt.Errorf("requestCoalescing.procedures.%s: argument %s does not exist", name, procedure.itemArgument)
+		}
+
+		batchOperation, _, err := metadata.GetProcedure(procedure.batchProcedure)
+		if err != nil {
+			return nil, fmt.Errorf("requestCoalescing.procedures.%s.batchProcedure: %w", name, err)
+		}
+
+		if _, ok := batchOperation.Arguments[procedure.batchArgument]; !ok {
+			return nil, fmt.Errorf("requestCoalescing.procedures.%s.batchProcedure: argument %s does not exist", name, procedure.batchArgument)
+		}
+
+		procedures[name] = procedure
+	}
+
+	return &RequestCoalescer{
+		procedures: procedures,
+		executor:   executor,
+		batches:    make(map[string]*coalescedBatch),
+	}, nil
+}
+
+// IsEnabled checks if calls of the procedure are coalesced.
+func (rc *RequestCoalescer) IsEnabled(procedureName string) bool {
+	if rc == nil {
+		return false
+	}
+
+	_, ok := rc.procedures[procedureName]
+
+	return ok
+}
+
+// Execute adds the procedure call to a pending batch and waits for the result of its item.
+func (rc *RequestCoalescer) Execute(ctx context.Context, procedureName string, rawArgs map[string]any, selection schema.NestedField) (any, error) {
+	procedure, ok := rc.procedures[procedureName]
+	if !ok {
+		return nil, schema.InternalServerError(fmt.Sprintf("request coalescing of procedure %s isn't configured", procedureName), nil)
+	}
+
+	item, ok := rawArgs[procedure.itemArgument]
+	if !ok {
+		return nil, schema.UnprocessableContentError(fmt.Sprintf("argument %s is required", procedure.itemArgument), nil)
+	}
+
+	arguments := maps.Clone(rawArgs)
+	delete(arguments, procedure.itemArgument)
+
+	// individual calls are coalesced only if other arguments, including forwarded headers, are equal.
+	rawKey, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, schema.UnprocessableContentError("failed to encode arguments", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	key := procedureName + ":" + string(rawKey)
+	waiter := rc.enqueue(ctx, key, procedure, arguments, item)
+
+	var result coalescedResult
+	select {
+	case <-ctx.Done():
+		// the item is sent with the batch unless it is still pending, so the caller must wait for the result
+		// instead of reporting a failure of a call which is actually executed.
+		if rc.dequeue(key, waiter) {
+			return nil, ctx.Err()
+		}
+
+		result = <-waiter
+	case result = <-waiter:
+	}
+
+	if result.err != nil || len(selection) == 0 {
+		return result.value, result.err
+	}
+
+	value, err := utils.EvalNestedColumnFields(selection, result.value)
+	if err != nil {
+		return nil, schema.InternalServerError(err.Error(), nil)
+	}
+
+	return value, nil
+}
+
+func (rc *RequestCoalescer) enqueue(ctx context.Context, key string, procedure coalescingProcedure, arguments map[string]any, item any) chan coalescedResult {
+	waiter := make(chan coalescedResult, 1)
+
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	batch, ok := rc.batches[key]
+	if !ok {
+		batch = &coalescedBatch{
+			// the batch is shared by many callers, so it shouldn't be canceled with the context of the first caller.
+			ctx:       context.WithoutCancel(ctx),
+			procedure: procedure,
+			arguments: arguments,
+		}
+		batch.timer = time.AfterFunc(procedure.window, func() {
+			rc.flushExpired(key, batch)
+		})
+		rc.batches[key] = batch
+	}
+
+	batch.items = append(batch.items, item)
+	batch.waiters = append(batch.waiters, waiter)
+
+	if len(batch.items) >= procedure.maxSize {
+		batch.timer.Stop()
+		delete(rc.batches, key)
+
+		go rc.send(batch)
+	}
+
+	return waiter
+}
+
+// dequeue removes the item of the waiter from the pending batch. Returns false if the batch was already sent.
+func (rc *RequestCoalescer) dequeue(key string, waiter chan coalescedResult) bool {
+	rc.lock.Lock()
+	defer rc.lock.Unlock()
+
+	batch, ok := rc.batches[key]
+	if !ok {
+		return false
+	}
+
+	index := slices.Index(batch.waiters, waiter)
+	if index < 0 {
+		return false
+	}
+
+	batch.items = slices.Delete(batch.items, index, index+1)
+	batch.waiters = slices.Delete(batch.waiters, index, index+1)
+
+	if len(batch.items) == 0 {
+		batch.timer.Stop()
+		delete(rc.batches, key)
+	}
+
+	return true
+}
+
+func (rc *RequestCoalescer) flushExpired(key string, batch *coalescedBatch) {
+	rc.lock.Lock()
+	current, ok := rc.batches[key]
+	if !ok || current != batch {
+		// the batch was sent because it is full
+		rc.lock.Unlock()
+
+		return
+	}
+
+	delete(rc.batches, key)
+	rc.lock.Unlock()
+
+	rc.send(batch)
+}
+
+func (rc *RequestCoalescer) send(batch *coalescedBatch) {
+	arguments := maps.Clone(batch.arguments)
+	if arguments == nil {
+		arguments = map[string]any{}
+	}
+	arguments[batch.procedure.batchArgument] = batch.items
+
+	result, err := rc.executor(batch.ctx, batch.procedure.batchProcedure, arguments)
+	if err != nil {
+		for _, waiter := range batch.waiters {
+			waiter <- coalescedResult{err: err}
+		}
+
+		return
+	}
+
+	results, ok := result.([]any)
+	if !ok || len(results) != len(batch.items) {
+		err := schema.InternalServerError("the batch response must be an array with the same length as items", map[string]any{
+			"batch_procedure": batch.procedure.batchProcedure,
+			"items":           len(batch.items),
+		})
+
+		for _, waiter := range batch.waiters {
+			waiter <- coalescedResult{err: err}
+		}
+
+		return
+	}
+
+	for i, waiter := range batch.waiters {
+		waiter <- coalescedResult{value: results[i]}
+	}
+}
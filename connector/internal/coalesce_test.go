@@ -0,0 +1,240 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func newTestCoalescingMetadata() MetadataCollection {
+	return MetadataCollection{
+		{
+			Name: "test",
+			NDCHttpSchema: &rest.NDCHttpSchema{
+				Procedures: map[string]rest.OperationInfo{
+					"createPet": {
+						Arguments: map[string]rest.ArgumentInfo{
+							"body":  {},
+							"store": {},
+						},
+					},
+					"createPets": {
+						Arguments: map[string]rest.ArgumentInfo{
+							"body":  {},
+							"store": {},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestRequestCoalescer(t *testing.T) {
+	config := &configuration.Configuration{
+		RequestCoalescing: &configuration.RequestCoalescingSettings{
+			Procedures: map[string]configuration.CoalescingProcedureSettings{
+				"createPet": {
+					BatchProcedure: "createPets",
+					Window:         50,
+					MaxSize:        3,
+				},
+			},
+		},
+	}
+
+	var batchCount atomic.Int32
+	sending := make(chan struct{})
+	release := make(chan struct{})
+	coalescer, err := NewRequestCoalescer(config, newTestCoalescingMetadata(), func(ctx context.Context, procedureName string, arguments map[string]any) (any, error) {
+		batchCount.Add(1)
+		assert.Equal(t, "createPets", procedureName)
+
+		if arguments["store"] == "slow" {
+			close(sending)
+			<-release
+		}
+
+		items := arguments["body"].([]any)
+		results := make([]any, len(items))
+		for i, item := range items {
+			results[i] = map[string]any{
+				"id":    item.(map[string]any)["id"],
+				"store": arguments["store"],
+				"size":  len(items),
+			}
+		}
+
+		return results, nil
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, coalescer.IsEnabled("createPet"))
+	assert.Assert(t, !coalescer.IsEnabled("createPets"))
+
+	t.Run("window", func(t *testing.T) {
+		batchCount.Store(0)
+		results := make([]any, 4)
+		var wg sync.WaitGroup
+		for i := range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				store := "a"
+				if i%2 == 1 {
+					store = "b"
+				}
+
+				result, err := coalescer.Execute(context.Background(), "createPet", map[string]any{
+					"body":  map[string]any{"id": float64(i)},
+					"store": store,
+				}, schema.NewNestedObject(map[string]schema.FieldEncoder{
+					"id":    schema.NewColumnField("id", nil),
+					"store": schema.NewColumnField("store", nil),
+				}).Encode())
+				assert.NilError(t, err)
+				results[i] = result
+			}()
+		}
+		wg.Wait()
+
+		// calls with different arguments are sent in separate batches.
+		assert.Equal(t, int32(2), batchCount.Load())
+		for i, result := range results {
+			store := "a"
+			if i%2 == 1 {
+				store = "b"
+			}
+			assert.DeepEqual(t, map[string]any{"id": float64(i), "store": store}, result)
+		}
+	})
+
+	t.Run("max_size", func(t *testing.T) {
+		batchCount.Store(0)
+		var wg sync.WaitGroup
+		for i := range 6 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				result, err := coalescer.Execute(context.Background(), "createPet", map[string]any{
+					"body": map[string]any{"id": float64(i)},
+				}, nil)
+				assert.NilError(t, err)
+				assert.Equal(t, float64(i), result.(map[string]any)["id"])
+				assert.Equal(t, 3, result.(map[string]any)["size"])
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(2), batchCount.Load())
+	})
+
+	t.Run("canceled_pending", func(t *testing.T) {
+		batchCount.Store(0)
+		ctx, cancel := context.WithCancel(context.Background())
+		errs := make(chan error, 1)
+		go func() {
+			_, err := coalescer.Execute(ctx, "createPet", map[string]any{
+				"body":  map[string]any{"id": float64(1)},
+				"store": "c",
+			}, nil)
+			errs <- err
+		}()
+
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+		assert.ErrorIs(t, <-errs, context.Canceled)
+
+		// the canceled item is removed from the pending batch and never sent
+		result, err := coalescer.Execute(context.Background(), "createPet", map[string]any{
+			"body":  map[string]any{"id": float64(2)},
+			"store": "c",
+		}, nil)
+		assert.NilError(t, err)
+		assert.Equal(t, 1, result.(map[string]any)["size"])
+		assert.Equal(t, int32(1), batchCount.Load())
+	})
+
+	t.Run("canceled_sent", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		type executeResult struct {
+			value any
+			err   error
+		}
+		results := make(chan executeResult, 1)
+		go func() {
+			value, err := coalescer.Execute(ctx, "createPet", map[string]any{
+				"body":  map[string]any{"id": float64(3)},
+				"store": "slow",
+			}, nil)
+			results <- executeResult{value, err}
+		}()
+
+		// the batch is being sent, so the caller waits for the result of its item
+		<-sending
+		cancel()
+		close(release)
+
+		result := <-results
+		assert.NilError(t, result.err)
+		assert.Equal(t, float64(3), result.value.(map[string]any)["id"])
+	})
+
+	t.Run("missing_item", func(t *testing.T) {
+		_, err := coalescer.Execute(context.Background(), "createPet", map[string]any{}, nil)
+		assert.ErrorContains(t, err, "argument body is required")
+	})
+}
+
+func TestRequestCoalescerErrors(t *testing.T) {
+	config := &configuration.Configuration{
+		RequestCoalescing: &configuration.RequestCoalescingSettings{
+			Procedures: map[string]configuration.CoalescingProcedureSettings{
+				"createPet": {
+					BatchProcedure: "createPets",
+					Window:         1,
+				},
+			},
+		},
+	}
+
+	coalescer, err := NewRequestCoalescer(config, newTestCoalescingMetadata(), func(ctx context.Context, procedureName string, arguments map[string]any) (any, error) {
+		if arguments["store"] == "failed" {
+			return nil, errors.New("upstream error")
+		}
+
+		return map[string]any{}, nil
+	})
+	assert.NilError(t, err)
+
+	_, err = coalescer.Execute(context.Background(), "createPet", map[string]any{"body": map[string]any{}, "store": "failed"}, nil)
+	assert.ErrorContains(t, err, "upstream error")
+
+	_, err = coalescer.Execute(context.Background(), "createPet", map[string]any{"body": map[string]any{}}, nil)
+	assert.ErrorContains(t, err, "the batch response must be an array")
+
+	config.RequestCoalescing.Procedures["createPet"] = configuration.CoalescingProcedureSettings{
+		BatchProcedure: "deletePets",
+	}
+	_, err = NewRequestCoalescer(config, newTestCoalescingMetadata(), nil)
+	assert.ErrorContains(t, err, "requestCoalescing.procedures.createPet.batchProcedure: unsupported mutation: deletePets")
+
+	config.RequestCoalescing.Procedures["createPet"] = configuration.CoalescingProcedureSettings{
+		BatchProcedure: "createPets",
+		ItemArgument:   "pet",
+	}
+	_, err = NewRequestCoalescer(config, newTestCoalescingMetadata(), nil)
+	assert.ErrorContains(t, err, "argument pet does not exist")
+
+	coalescer, err = NewRequestCoalescer(&configuration.Configuration{}, nil, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, !coalescer.IsEnabled("createPet"))
+}
@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"fmt"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// BuildCollectionSelection creates the selection of the function result from fields of the collection query.
+// Rows are items of the response body, or items at the items field of the pagination settings.
+func BuildCollectionSelection(operation *rest.OperationInfo, fields schema.QueryFields) schema.NestedField {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var selection schema.NestedFieldEncoder = schema.NewNestedArray(&schema.NestedObject{
+		Type:   schema.NestedFieldTypeObject,
+		Fields: fields,
+	})
+
+	if itemsField := getCollectionItemsField(operation); itemsField != "" {
+		segments := strings.Split(itemsField, ".")
+		for i := len(segments) - 1; i >= 0; i-- {
+			selection = schema.NewNestedObject(map[string]schema.FieldEncoder{
+				segments[i]: schema.NewColumnField(segments[i], selection),
+			})
+		}
+	}
+
+	return selection.Encode()
+}
+
+// EvalCollectionRows extracts rows of the collection from the function result.
+// The offset is applied in memory if the operation doesn't declare a pagination strategy.
+func EvalCollectionRows(operation *rest.OperationInfo, result any, limit *int, offset *int) ([]map[string]any, error) {
+	items, err := getPaginationItems(result, getCollectionItemsField(operation))
+	if err != nil {
+		return nil, schema.InternalServerError(err.Error(), nil)
+	}
+
+	if (operation.Request == nil || operation.Request.Pagination == nil) && offset != nil && *offset > 0 {
+		items = items[min(*offset, len(items)):]
+	}
+
+	// the upstream API may ignore the page size
+	if limit != nil && *limit >= 0 && *limit < len(items) {
+		items = items[:*limit]
+	}
+
+	rows := make([]map[string]any, len(items))
+	for i, item := range items {
+		row, ok := item.(map[string]any)
+		if !ok {
+			return nil, schema.InternalServerError(fmt.Sprintf("expected an object of the row %d, got %T", i, item), nil)
+		}
+
+		rows[i] = row
+	}
+
+	return rows, nil
+}
+
+func getCollectionItemsField(operation *rest.OperationInfo) string {
+	if operation.Request == nil || operation.Request.Pagination == nil {
+		return ""
+	}
+
+	return operation.Request.Pagination.ItemsField
+}
@@ -0,0 +1,105 @@
+package internal
+
+import (
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strconv"
+
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// compareDistributedResults compares decoded results of two servers.
+// Returns nil if any server doesn't return a successful result.
+func compareDistributedResults(results []DistributedResult[any], leftServer string, rightServer string) *DistributedComparison {
+	leftIndex := slices.IndexFunc(results, func(r DistributedResult[any]) bool {
+		return r.Server == leftServer
+	})
+	rightIndex := slices.IndexFunc(results, func(r DistributedResult[any]) bool {
+		return r.Server == rightServer
+	})
+
+	if leftIndex < 0 || rightIndex < 0 {
+		return nil
+	}
+
+	differences := compareValues("$", normalizeJSONValue(results[leftIndex].Data), normalizeJSONValue(results[rightIndex].Data))
+
+	return &DistributedComparison{
+		Servers:     []string{leftServer, rightServer},
+		Equal:       len(differences) == 0,
+		Differences: differences,
+	}
+}
+
+func compareValues(path string, left any, right any) []DistributedDifference {
+	switch l := left.(type) {
+	case map[string]any:
+		r, ok := right.(map[string]any)
+		if !ok {
+			break
+		}
+
+		differences := []DistributedDifference{}
+		keys := utils.GetKeys(l)
+		for key := range r {
+			if _, ok := l[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+
+		for _, key := range keys {
+			differences = append(differences, compareValues(path+"."+key, l[key], r[key])...)
+		}
+
+		return differences
+	case []any:
+		r, ok := right.([]any)
+		if !ok {
+			break
+		}
+
+		differences := []DistributedDifference{}
+		for i := range max(len(l), len(r)) {
+			var leftItem, rightItem any
+			if i < len(l) {
+				leftItem = l[i]
+			}
+			if i < len(r) {
+				rightItem = r[i]
+			}
+
+			differences = append(differences, compareValues(path+"["+strconv.Itoa(i)+"]", leftItem, rightItem)...)
+		}
+
+		return differences
+	}
+
+	if reflect.DeepEqual(left, right) {
+		return []DistributedDifference{}
+	}
+
+	return []DistributedDifference{
+		{
+			Path:  path,
+			Left:  left,
+			Right: right,
+		},
+	}
+}
+
+// normalizeJSONValue converts the decoded value to generic JSON types, so results of different decoders are comparable.
+func normalizeJSONValue(value any) any {
+	rawBytes, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var result any
+	if err := json.Unmarshal(rawBytes, &result); err != nil {
+		return value
+	}
+
+	return result
+}
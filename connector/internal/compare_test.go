@@ -0,0 +1,47 @@
+package internal
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCompareDistributedResults(t *testing.T) {
+	results := []DistributedResult[any]{
+		{
+			Server: "cat",
+			Data: map[string]any{
+				"id":   1,
+				"name": "cat",
+				"tags": []string{"a", "b"},
+			},
+		},
+		{
+			Server: "dog",
+			Data: map[string]any{
+				"id":     int64(1),
+				"name":   "dog",
+				"tags":   []any{"a"},
+				"status": "available",
+			},
+		},
+	}
+
+	assert.DeepEqual(t, &DistributedComparison{
+		Servers: []string{"cat", "dog"},
+		Equal:   false,
+		Differences: []DistributedDifference{
+			{Path: "$.name", Left: "cat", Right: "dog"},
+			{Path: "$.status", Left: nil, Right: "available"},
+			{Path: "$.tags[1]", Left: "b", Right: nil},
+		},
+	}, compareDistributedResults(results, "cat", "dog"))
+
+	assert.DeepEqual(t, &DistributedComparison{
+		Servers:     []string{"cat", "cat"},
+		Equal:       true,
+		Differences: []DistributedDifference{},
+	}, compareDistributedResults(results, "cat", "cat"))
+
+	assert.Assert(t, compareDistributedResults(results, "cat", "bird") == nil)
+}
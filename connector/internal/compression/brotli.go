@@ -0,0 +1,35 @@
+package compression
+
+import (
+	"io"
+
+	"github.com/andybalholm/brotli"
+)
+
+const (
+	EncodingBrotli = "br"
+)
+
+// BrotliCompressor implements the compression handler for brotli encoding.
+type BrotliCompressor struct{}
+
+// Compress the reader content with brotli encoding.
+func (bc BrotliCompressor) Compress(w io.Writer, data []byte) (int, error) {
+	bw := brotli.NewWriter(w)
+
+	size, err := bw.Write(data)
+	if err != nil {
+		return size, err
+	}
+	err = bw.Close()
+
+	return size, err
+}
+
+// Decompress the reader content with brotli encoding.
+func (bc BrotliCompressor) Decompress(reader io.ReadCloser) (io.ReadCloser, error) {
+	return readCloserWrapper{
+		CompressionReader: io.NopCloser(brotli.NewReader(reader)),
+		OriginalReader:    reader,
+	}, nil
+}
@@ -23,6 +23,8 @@ func NewCompressors() *Compressors {
 	compressors := map[string]Compressor{
 		EncodingGzip:    GzipCompressor{},
 		EncodingDeflate: DeflateCompressor{},
+		EncodingBrotli:  BrotliCompressor{},
+		EncodingZstd:    ZstdCompressor{},
 	}
 
 	return &Compressors{
@@ -38,7 +40,7 @@ func (c Compressors) AcceptEncoding() string {
 
 // IsEncodingSupported checks if the input encoding is supported.
 func (c Compressors) IsEncodingSupported(encoding string) bool {
-	_, ok := c.compressors[encoding]
+	_, ok := c.compressors[strings.ToLower(strings.TrimSpace(encoding))]
 
 	return ok
 }
@@ -0,0 +1,36 @@
+package compression
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCompressors(t *testing.T) {
+	compressors := NewCompressors()
+	assert.Equal(t, "br, deflate, gzip, zstd", compressors.AcceptEncoding())
+	assert.Assert(t, !compressors.IsEncodingSupported("compress"))
+
+	data := []byte(strings.Repeat(`{"id":1,"name":"doggie","status":"available"}`, 100))
+	for _, encoding := range []string{EncodingGzip, EncodingDeflate, EncodingBrotli, EncodingZstd} {
+		t.Run(encoding, func(t *testing.T) {
+			assert.Assert(t, compressors.IsEncodingSupported(strings.ToUpper(encoding)))
+
+			var buf bytes.Buffer
+			size, err := compressors.Compress(&buf, encoding, data)
+			assert.NilError(t, err)
+			assert.Equal(t, len(data), size)
+			assert.Assert(t, buf.Len() < len(data))
+
+			reader, err := compressors.Decompress(io.NopCloser(&buf), encoding)
+			assert.NilError(t, err)
+			result, err := io.ReadAll(reader)
+			assert.NilError(t, err)
+			assert.NilError(t, reader.Close())
+			assert.DeepEqual(t, data, result)
+		})
+	}
+}
@@ -0,0 +1,45 @@
+package compression
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	EncodingZstd = "zstd"
+)
+
+// ZstdCompressor implements the compression handler for zstd encoding.
+type ZstdCompressor struct{}
+
+// Compress the reader content with zstd encoding.
+func (zc ZstdCompressor) Compress(w io.Writer, data []byte) (int, error) {
+	zw, err := zstd.NewWriter(w)
+	if err != nil {
+		return 0, err
+	}
+
+	size, err := zw.Write(data)
+	if err != nil {
+		_ = zw.Close()
+
+		return size, err
+	}
+	err = zw.Close()
+
+	return size, err
+}
+
+// Decompress the reader content with zstd encoding.
+func (zc ZstdCompressor) Decompress(reader io.ReadCloser) (io.ReadCloser, error) {
+	decoder, err := zstd.NewReader(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	return readCloserWrapper{
+		CompressionReader: decoder.IOReadCloser(),
+		OriginalReader:    reader,
+	}, nil
+}
@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"crypto/tls"
+	"net/http"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+// newHTTPClientConnection creates a new HTTP client with a dedicated transport which is tuned by connection settings.
+// Settings are applied in order, so later settings take precedence. Returns the base client if there is no setting.
+func newHTTPClientConnection(baseClient *http.Client, configs ...*rest.ConnectionConfig) (*http.Client, error) {
+	var settings []*rest.ConnectionSettings
+	for _, config := range configs {
+		if config == nil {
+			continue
+		}
+
+		setting, err := config.Evaluate()
+		if err != nil {
+			return nil, err
+		}
+
+		settings = append(settings, setting)
+	}
+
+	if len(settings) == 0 {
+		return baseClient, nil
+	}
+
+	baseTransport, ok := baseClient.Transport.(*http.Transport)
+	if !ok {
+		baseTransport, _ = http.DefaultTransport.(*http.Transport)
+	}
+
+	transport := baseTransport.Clone()
+	for _, setting := range settings {
+		applyConnectionSettings(transport, setting)
+	}
+
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: baseClient.CheckRedirect,
+		Jar:           baseClient.Jar,
+		Timeout:       baseClient.Timeout,
+	}, nil
+}
+
+func applyConnectionSettings(transport *http.Transport, settings *rest.ConnectionSettings) {
+	if settings.MaxIdleConns != nil {
+		transport.MaxIdleConns = *settings.MaxIdleConns
+	}
+
+	if settings.MaxIdleConnsPerHost != nil {
+		transport.MaxIdleConnsPerHost = *settings.MaxIdleConnsPerHost
+	}
+
+	if settings.MaxConnsPerHost != nil {
+		transport.MaxConnsPerHost = *settings.MaxConnsPerHost
+	}
+
+	if settings.IdleConnTimeout != nil {
+		transport.IdleConnTimeout = *settings.IdleConnTimeout
+	}
+
+	if settings.DisableKeepAlives != nil {
+		transport.DisableKeepAlives = *settings.DisableKeepAlives
+	}
+
+	if settings.HTTP2 != nil {
+		transport.ForceAttemptHTTP2 = *settings.HTTP2
+		if *settings.HTTP2 {
+			transport.TLSNextProto = nil
+		} else {
+			// a non-nil empty map disables HTTP/2
+			transport.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package internal
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestNewHTTPClientConnection(t *testing.T) {
+	baseClient := &http.Client{Timeout: time.Minute}
+	client, err := newHTTPClientConnection(baseClient, nil)
+	assert.NilError(t, err)
+	assert.Equal(t, baseClient, client)
+
+	t.Setenv("UPSTREAM_MAX_CONNS_PER_HOST", "50")
+	client, err = newHTTPClientConnection(baseClient, &rest.ConnectionConfig{
+		MaxIdleConns:        utils.ToPtr(utils.NewEnvIntValue(200)),
+		MaxIdleConnsPerHost: utils.ToPtr(utils.NewEnvIntValue(20)),
+		MaxConnsPerHost:     utils.ToPtr(utils.NewEnvIntValue(10)),
+		IdleConnTimeout:     utils.ToPtr(utils.NewEnvIntValue(30)),
+		HTTP2:               utils.ToPtr(utils.NewEnvBoolValue(false)),
+		DisableKeepAlives:   utils.ToPtr(utils.NewEnvBoolVariable("UNKNOWN_DISABLE_KEEP_ALIVES")),
+	}, &rest.ConnectionConfig{
+		MaxConnsPerHost: utils.ToPtr(utils.NewEnvIntVariable("UPSTREAM_MAX_CONNS_PER_HOST")),
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, time.Minute, client.Timeout)
+
+	transport, ok := client.Transport.(*http.Transport)
+	assert.Assert(t, ok)
+	assert.Assert(t, transport != http.DefaultTransport)
+	assert.Equal(t, 200, transport.MaxIdleConns)
+	assert.Equal(t, 20, transport.MaxIdleConnsPerHost)
+	assert.Equal(t, 50, transport.MaxConnsPerHost)
+	assert.Equal(t, 30*time.Second, transport.IdleConnTimeout)
+	assert.Equal(t, false, transport.DisableKeepAlives)
+	assert.Equal(t, false, transport.ForceAttemptHTTP2)
+	assert.Assert(t, transport.TLSNextProto != nil)
+
+	_, err = newHTTPClientConnection(baseClient, &rest.ConnectionConfig{
+		MaxIdleConns: utils.ToPtr(utils.NewEnvIntValue(-1)),
+	})
+	assert.ErrorContains(t, err, "maxIdleConns: expected a non-negative integer, got -1")
+}
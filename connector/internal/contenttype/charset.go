@@ -0,0 +1,72 @@
+package contenttype
+
+import (
+	"io"
+	"mime"
+	"strings"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// NewUTF8ReadCloser wraps the reader to transcode the content from the charset declared in the Content-Type header to UTF-8.
+// Returns the original reader if the charset is empty, UTF-8 or unsupported.
+func NewUTF8ReadCloser(reader io.ReadCloser, contentType string) io.ReadCloser {
+	enc := getCharsetEncoding(contentType)
+	if enc == nil {
+		return reader
+	}
+
+	return struct {
+		io.Reader
+		io.Closer
+	}{
+		Reader: transform.NewReader(reader, enc.NewDecoder()),
+		Closer: reader,
+	}
+}
+
+// TranscodeToUTF8 converts the bytes from the charset declared in the Content-Type header to UTF-8.
+// The input is returned as it is if the charset is empty, UTF-8 or unsupported.
+func TranscodeToUTF8(data []byte, contentType string) []byte {
+	enc := getCharsetEncoding(contentType)
+	if enc == nil || len(data) == 0 {
+		return data
+	}
+
+	result, _, err := transform.Bytes(enc.NewDecoder(), data)
+	if err != nil {
+		return data
+	}
+
+	return result
+}
+
+func getCharsetEncoding(contentType string) encoding.Encoding {
+	if contentType == "" {
+		return nil
+	}
+
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil
+	}
+
+	charset := strings.ToLower(strings.TrimSpace(params["charset"]))
+	switch charset {
+	case "", "utf-8", "utf8", "us-ascii":
+		return nil
+	case "utf-16":
+		// RFC 2781: UTF-16 without BOM is big-endian
+		return unicode.UTF16(unicode.BigEndian, unicode.UseBOM)
+	}
+
+	enc, err := htmlindex.Get(charset)
+	if err != nil || enc == unicode.UTF8 {
+		return nil
+	}
+
+	return enc
+}
@@ -0,0 +1,67 @@
+package contenttype
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTranscodeToUTF8(t *testing.T) {
+	testCases := []struct {
+		Name        string
+		ContentType string
+		Input       []byte
+		Expected    string
+	}{
+		{
+			Name:        "latin1",
+			ContentType: "text/html; charset=ISO-8859-1",
+			Input:       []byte{'C', 'a', 'f', 0xe9},
+			Expected:    "Café",
+		},
+		{
+			Name:        "utf16le_bom",
+			ContentType: "application/json; charset=utf-16",
+			Input:       []byte{0xff, 0xfe, '{', 0, '}', 0},
+			Expected:    "{}",
+		},
+		{
+			Name:        "utf16be",
+			ContentType: "text/plain; charset=UTF-16BE",
+			Input:       []byte{0, 'o', 0, 'k'},
+			Expected:    "ok",
+		},
+		{
+			Name:        "utf8",
+			ContentType: "text/plain; charset=utf-8",
+			Input:       []byte("Café"),
+			Expected:    "Café",
+		},
+		{
+			Name:        "unknown",
+			ContentType: "text/plain; charset=unknown",
+			Input:       []byte("ok"),
+			Expected:    "ok",
+		},
+		{
+			Name:        "no_charset",
+			ContentType: "application/json",
+			Input:       []byte("{}"),
+			Expected:    "{}",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, string(TranscodeToUTF8(tc.Input, tc.ContentType)))
+
+			reader := NewUTF8ReadCloser(io.NopCloser(bytes.NewReader(tc.Input)), tc.ContentType)
+			result, err := io.ReadAll(reader)
+			assert.NilError(t, err)
+			assert.NilError(t, reader.Close())
+			assert.Equal(t, tc.Expected, string(result))
+		})
+	}
+}
@@ -44,7 +44,7 @@ func (c *JSONDecoder) Decode(r io.Reader, resultType schema.Type) (any, error) {
 			return nil, nil
 		}
 
-		return c.evalArrayType(rawResult, t, []string{})
+		return c.evalArrayType(rawResult, t, nil, []string{})
 	case *schema.NamedType:
 		var result any
 		err := json.NewDecoder(r).Decode(&result)
@@ -56,7 +56,7 @@ func (c *JSONDecoder) Decode(r io.Reader, resultType schema.Type) (any, error) {
 			return nil, nil
 		}
 
-		return c.evalNamedType(result, t, []string{})
+		return c.evalNamedType(result, t, nil, []string{})
 	default:
 		var result any
 		err := json.NewDecoder(r).Decode(&result)
@@ -65,32 +65,37 @@ func (c *JSONDecoder) Decode(r io.Reader, resultType schema.Type) (any, error) {
 	}
 }
 
-func (c *JSONDecoder) evalSchemaType(value any, schemaType schema.Type, fieldPaths []string) (any, error) {
+func (c *JSONDecoder) evalSchemaType(value any, schemaType schema.Type, typeSchema *rest.TypeSchema, fieldPaths []string) (any, error) {
 	if utils.IsNil(value) {
 		return nil, nil
 	}
 
 	switch t := schemaType.Interface().(type) {
 	case *schema.NullableType:
-		return c.evalSchemaType(value, t.UnderlyingType, fieldPaths)
+		return c.evalSchemaType(value, t.UnderlyingType, typeSchema, fieldPaths)
 	case *schema.ArrayType:
-		return c.evalArrayType(value, t, fieldPaths)
+		return c.evalArrayType(value, t, typeSchema, fieldPaths)
 	case *schema.NamedType:
-		return c.evalNamedType(value, t, fieldPaths)
+		return c.evalNamedType(value, t, typeSchema, fieldPaths)
 	default:
 		return value, nil
 	}
 }
 
-func (c *JSONDecoder) evalArrayType(value any, arrayType *schema.ArrayType, fieldPaths []string) (any, error) {
+func (c *JSONDecoder) evalArrayType(value any, arrayType *schema.ArrayType, typeSchema *rest.TypeSchema, fieldPaths []string) (any, error) {
 	arrayValue, ok := value.([]any)
 	if !ok {
 		return value, nil
 	}
 
+	var itemSchema *rest.TypeSchema
+	if typeSchema != nil {
+		itemSchema = typeSchema.Items
+	}
+
 	results := make([]any, len(arrayValue))
 	for i, item := range arrayValue {
-		result, err := c.evalSchemaType(item, arrayType.ElementType, append(fieldPaths, strconv.Itoa(i)))
+		result, err := c.evalSchemaType(item, arrayType.ElementType, itemSchema, append(fieldPaths, strconv.Itoa(i)))
 		if err != nil {
 			return nil, err
 		}
@@ -100,8 +105,17 @@ func (c *JSONDecoder) evalArrayType(value any, arrayType *schema.ArrayType, fiel
 	return results, nil
 }
 
-func (c *JSONDecoder) evalNamedType(value any, schemaType *schema.NamedType, fieldPaths []string) (any, error) {
+func (c *JSONDecoder) evalNamedType(value any, schemaType *schema.NamedType, typeSchema *rest.TypeSchema, fieldPaths []string) (any, error) {
 	scalarType, ok := c.schema.ScalarTypes[schemaType.Name]
+	if ok && IsTypedMap(typeSchema) {
+		result, err := EvalTypedMap(value, typeSchema)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.Join(fieldPaths, "."), err)
+		}
+
+		return result, nil
+	}
+
 	if ok {
 		result, err := c.evalScalarType(value, scalarType)
 		if err != nil {
@@ -121,6 +135,10 @@ func (c *JSONDecoder) evalNamedType(value any, schemaType *schema.NamedType, fie
 		return value, nil
 	}
 
+	if objectType.Discriminator != nil {
+		return c.evalUnionObject(objectValue, objectType, fieldPaths)
+	}
+
 	results := make(map[string]any)
 	for key, field := range objectType.Fields {
 		fieldValue, ok := objectValue[key]
@@ -134,7 +152,7 @@ func (c *JSONDecoder) evalNamedType(value any, schemaType *schema.NamedType, fie
 			continue
 		}
 
-		result, err := c.evalSchemaType(fieldValue, field.Type, append(fieldPaths, key))
+		result, err := c.evalSchemaType(fieldValue, field.Type, field.HTTP, append(fieldPaths, key))
 		if err != nil {
 			return nil, err
 		}
@@ -145,6 +163,34 @@ func (c *JSONDecoder) evalNamedType(value any, schemaType *schema.NamedType, fie
 	return results, nil
 }
 
+// wrap the variant object into the field of the tagged union object by the discriminator value.
+// Only the discriminator field is returned if the value is an unknown variant.
+func (c *JSONDecoder) evalUnionObject(value map[string]any, objectType rest.ObjectType, fieldPaths []string) (any, error) {
+	discriminator := objectType.Discriminator
+	tag := value[discriminator.PropertyName]
+	results := map[string]any{
+		discriminator.PropertyName: tag,
+	}
+
+	fieldName, ok := discriminator.GetVariantField(tag)
+	if !ok {
+		return results, nil
+	}
+
+	field, ok := objectType.Fields[fieldName]
+	if !ok {
+		return results, nil
+	}
+
+	variant, err := c.evalSchemaType(value, field.Type, field.HTTP, append(fieldPaths, fieldName))
+	if err != nil {
+		return nil, err
+	}
+	results[fieldName] = variant
+
+	return results, nil
+}
+
 func (c *JSONDecoder) evalScalarType(value any, scalarType schema.ScalarType) (any, error) {
 	switch scalarType.Representation.Interface().(type) {
 	case *schema.TypeRepresentationBoolean:
@@ -0,0 +1,176 @@
+package contenttype
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// JSONEncoder implements a dynamic JSON encoder from the HTTP schema.
+type JSONEncoder struct {
+	schema *rest.NDCHttpSchema
+}
+
+// NewJSONEncoder creates a new JSON encoder.
+func NewJSONEncoder(httpSchema *rest.NDCHttpSchema) *JSONEncoder {
+	return &JSONEncoder{
+		schema: httpSchema,
+	}
+}
+
+// Encode marshals the body to JSON bytes.
+// Tagged union objects are flattened to variant objects with the discriminator property.
+func (c *JSONEncoder) Encode(bodyInfo *rest.ArgumentInfo, bodyData any) ([]byte, error) {
+	var typeSchema *rest.TypeSchema
+	if bodyInfo.HTTP != nil {
+		typeSchema = bodyInfo.HTTP.Schema
+	}
+
+	value, err := c.evalSchemaType(bodyData, bodyInfo.Type, typeSchema, []string{})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+func (c *JSONEncoder) evalSchemaType(value any, schemaType schema.Type, typeSchema *rest.TypeSchema, fieldPaths []string) (any, error) {
+	if utils.IsNil(value) || schemaType == nil || c.schema == nil {
+		return value, nil
+	}
+
+	switch t := schemaType.Interface().(type) {
+	case *schema.NullableType:
+		return c.evalSchemaType(value, t.UnderlyingType, typeSchema, fieldPaths)
+	case *schema.ArrayType:
+		arrayValue, ok := value.([]any)
+		if !ok {
+			return value, nil
+		}
+
+		var itemSchema *rest.TypeSchema
+		if typeSchema != nil {
+			itemSchema = typeSchema.Items
+		}
+
+		results := make([]any, len(arrayValue))
+		for i, item := range arrayValue {
+			result, err := c.evalSchemaType(item, t.ElementType, itemSchema, append(fieldPaths, strconv.Itoa(i)))
+			if err != nil {
+				return nil, err
+			}
+			results[i] = result
+		}
+
+		return results, nil
+	case *schema.NamedType:
+		if _, ok := c.schema.ScalarTypes[t.Name]; ok && IsTypedMap(typeSchema) {
+			result, err := EvalTypedMap(value, typeSchema)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", strings.Join(fieldPaths, "."), err)
+			}
+
+			return result, nil
+		}
+
+		objectType, ok := c.schema.ObjectTypes[t.Name]
+		if !ok {
+			return value, nil
+		}
+
+		objectValue, ok := value.(map[string]any)
+		if !ok {
+			return value, nil
+		}
+
+		if objectType.Discriminator != nil {
+			return c.evalUnionObject(objectValue, objectType, fieldPaths)
+		}
+
+		results := make(map[string]any, len(objectValue))
+		for key, fieldValue := range objectValue {
+			field, ok := objectType.Fields[key]
+			if !ok {
+				results[key] = fieldValue
+
+				continue
+			}
+
+			result, err := c.evalSchemaType(fieldValue, field.Type, field.HTTP, append(fieldPaths, key))
+			if err != nil {
+				return nil, err
+			}
+			results[key] = result
+		}
+
+		return results, nil
+	default:
+		return value, nil
+	}
+}
+
+// flatten the tagged union object to the variant object and set the discriminator property.
+// The tag is inferred from the variant field if it is omitted.
+func (c *JSONEncoder) evalUnionObject(value map[string]any, objectType rest.ObjectType, fieldPaths []string) (any, error) {
+	discriminator := objectType.Discriminator
+	tag := value[discriminator.PropertyName]
+
+	var variantField string
+	for _, key := range utils.GetSortedKeys(value) {
+		if key == discriminator.PropertyName || utils.IsNil(value[key]) {
+			continue
+		}
+
+		if variantField != "" {
+			return nil, fmt.Errorf("%s: expected only one variant of the union object, got %s and %s", strings.Join(fieldPaths, "."), variantField, key)
+		}
+
+		variantField = key
+	}
+
+	if variantField == "" {
+		return nil, fmt.Errorf("%s: expected a variant of the union object", strings.Join(fieldPaths, "."))
+	}
+
+	if utils.IsNil(tag) {
+		inferredTag, ok := discriminator.GetTag(variantField)
+		if !ok {
+			return nil, fmt.Errorf("%s: unknown variant %s of the union object", strings.Join(fieldPaths, "."), variantField)
+		}
+
+		tag = inferredTag
+	} else if fieldName, ok := discriminator.GetVariantField(tag); !ok || fieldName != variantField {
+		return nil, fmt.Errorf("%s: the variant %s doesn't match the discriminator value %v", strings.Join(fieldPaths, "."), variantField, tag)
+	}
+
+	field := objectType.Fields[variantField]
+	result, err := c.evalSchemaType(value[variantField], field.Type, field.HTTP, append(fieldPaths, variantField))
+	if err != nil {
+		return nil, err
+	}
+
+	variantObject, ok := result.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("%s.%s: expected an object, got %v", strings.Join(fieldPaths, "."), variantField, result)
+	}
+
+	variant := maps.Clone(variantObject)
+	variant[discriminator.PropertyName] = tag
+
+	return variant, nil
+}
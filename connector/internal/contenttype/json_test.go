@@ -0,0 +1,149 @@
+package contenttype
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestJSONTaggedUnion(t *testing.T) {
+	ndcSchema := &rest.NDCHttpSchema{
+		ScalarTypes: schema.SchemaResponseScalarTypes{
+			"String": *schema.NewScalarType(),
+			"PaymentSourceObjectEnum": schema.ScalarType{
+				Representation: schema.NewTypeRepresentationEnum([]string{"card", "bank_account"}).Encode(),
+			},
+		},
+		ObjectTypes: map[string]rest.ObjectType{
+			"PaymentSource": {
+				Discriminator: &rest.UnionDiscriminator{
+					PropertyName: "object",
+					Mapping: map[string]string{
+						"card":         "card",
+						"bank_account": "bankAccount",
+					},
+				},
+				Fields: map[string]rest.ObjectField{
+					"object": {
+						ObjectField: schema.ObjectField{Type: schema.NewNamedType("PaymentSourceObjectEnum").Encode()},
+					},
+					"card": {
+						ObjectField: schema.ObjectField{Type: schema.NewNullableNamedType("Card").Encode()},
+					},
+					"bankAccount": {
+						ObjectField: schema.ObjectField{Type: schema.NewNullableNamedType("BankAccount").Encode()},
+					},
+				},
+			},
+			"Card": {
+				Fields: map[string]rest.ObjectField{
+					"object": {
+						ObjectField: schema.ObjectField{Type: schema.NewNamedType("String").Encode()},
+					},
+					"brand": {
+						ObjectField: schema.ObjectField{Type: schema.NewNullableNamedType("String").Encode()},
+					},
+				},
+			},
+			"BankAccount": {
+				Fields: map[string]rest.ObjectField{
+					"object": {
+						ObjectField: schema.ObjectField{Type: schema.NewNamedType("String").Encode()},
+					},
+					"routing_number": {
+						ObjectField: schema.ObjectField{Type: schema.NewNullableNamedType("String").Encode()},
+					},
+				},
+			},
+		},
+	}
+	arrayType := schema.NewArrayType(schema.NewNamedType("PaymentSource")).Encode()
+	rawPayload := `[{"brand":"visa","object":"card"},{"object":"bank_account","routing_number":"110000000"}]`
+
+	t.Run("decode", func(t *testing.T) {
+		result, err := NewJSONDecoder(ndcSchema).Decode(strings.NewReader(rawPayload), arrayType)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []any{
+			map[string]any{
+				"object": "card",
+				"card": map[string]any{
+					"object": "card",
+					"brand":  "visa",
+				},
+			},
+			map[string]any{
+				"object": "bank_account",
+				"bankAccount": map[string]any{
+					"object":         "bank_account",
+					"routing_number": "110000000",
+				},
+			},
+		}, result)
+
+		// the round trip is lossless
+		bodyBytes, err := NewJSONEncoder(ndcSchema).Encode(&rest.ArgumentInfo{
+			ArgumentInfo: schema.ArgumentInfo{Type: arrayType},
+		}, result)
+		assert.NilError(t, err)
+		assert.Equal(t, rawPayload+"\n", string(bodyBytes))
+	})
+
+	t.Run("decode_unknown_variant", func(t *testing.T) {
+		result, err := NewJSONDecoder(ndcSchema).Decode(strings.NewReader(`{"object":"wallet","provider":"paypal"}`), schema.NewNamedType("PaymentSource").Encode())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, map[string]any{"object": "wallet"}, result)
+	})
+
+	bodyInfo := &rest.ArgumentInfo{
+		ArgumentInfo: schema.ArgumentInfo{Type: schema.NewNamedType("PaymentSource").Encode()},
+	}
+
+	t.Run("encode_inferred_tag", func(t *testing.T) {
+		bodyBytes, err := NewJSONEncoder(ndcSchema).Encode(bodyInfo, map[string]any{
+			"card":        map[string]any{"brand": "visa"},
+			"bankAccount": nil,
+		})
+		assert.NilError(t, err)
+
+		var result map[string]any
+		assert.NilError(t, json.Unmarshal(bodyBytes, &result))
+		assert.DeepEqual(t, map[string]any{"object": "card", "brand": "visa"}, result)
+	})
+
+	for _, tc := range []struct {
+		Name     string
+		Body     map[string]any
+		ErrorMsg string
+	}{
+		{
+			Name:     "empty",
+			Body:     map[string]any{"object": "card"},
+			ErrorMsg: "expected a variant of the union object",
+		},
+		{
+			Name: "many_variants",
+			Body: map[string]any{
+				"card":        map[string]any{"brand": "visa"},
+				"bankAccount": map[string]any{"routing_number": "110000000"},
+			},
+			ErrorMsg: "expected only one variant of the union object, got bankAccount and card",
+		},
+		{
+			Name: "mismatched_tag",
+			Body: map[string]any{
+				"object": "bank_account",
+				"card":   map[string]any{"brand": "visa"},
+			},
+			ErrorMsg: "the variant card doesn't match the discriminator value bank_account",
+		},
+	} {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := NewJSONEncoder(ndcSchema).Encode(bodyInfo, tc.Body)
+			assert.ErrorContains(t, err, tc.ErrorMsg)
+		})
+	}
+}
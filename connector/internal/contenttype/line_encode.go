@@ -0,0 +1,158 @@
+package contenttype
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
+)
+
+// LineEncoder encodes items of an array body line by line, e.g. ndjson (JSON Lines) or CSV.
+// Each item is written to the writer as soon as it's encoded so the whole payload isn't built in memory.
+type LineEncoder struct {
+	contentType string
+	items       reflect.Value
+	columns     []string
+}
+
+// NewLineEncoder creates a new LineEncoder instance. The value must be an array.
+// CSV columns default to sorted keys of the first object item.
+func NewLineEncoder(contentType string, value any, columns []string) (*LineEncoder, error) {
+	if !restUtils.IsContentTypeNdJSON(contentType) && contentType != rest.ContentTypeCSV {
+		return nil, fmt.Errorf("unsupported content type %s to be encoded line by line", contentType)
+	}
+
+	items := reflect.ValueOf(value)
+	for items.Kind() == reflect.Pointer || items.Kind() == reflect.Interface {
+		items = items.Elem()
+	}
+
+	if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+		return nil, fmt.Errorf("expected an array body to be encoded line by line, got %s", items.Kind())
+	}
+
+	encoder := &LineEncoder{
+		contentType: contentType,
+		items:       items,
+		columns:     columns,
+	}
+
+	if contentType == rest.ContentTypeCSV && len(columns) == 0 && items.Len() > 0 {
+		if firstItem, ok := items.Index(0).Interface().(map[string]any); ok {
+			for key := range firstItem {
+				encoder.columns = append(encoder.columns, key)
+			}
+			slices.Sort(encoder.columns)
+		}
+	}
+
+	return encoder, nil
+}
+
+// Encode writes items to the writer line by line.
+func (e *LineEncoder) Encode(w io.Writer) error {
+	if e.contentType == rest.ContentTypeCSV {
+		return e.encodeCSV(w)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+
+	for i := range e.items.Len() {
+		if err := enc.Encode(e.items.Index(i).Interface()); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (e *LineEncoder) encodeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if len(e.columns) > 0 {
+		if err := writer.Write(e.columns); err != nil {
+			return err
+		}
+	}
+
+	for i := range e.items.Len() {
+		record, err := e.evalCSVRecord(e.items.Index(i).Interface())
+		if err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+
+		// flush each record so the writer doesn't buffer the whole payload
+		writer.Flush()
+		if err := writer.Error(); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+
+	return writer.Error()
+}
+
+func (e *LineEncoder) evalCSVRecord(item any) ([]string, error) {
+	switch value := item.(type) {
+	case map[string]any:
+		if len(e.columns) == 0 {
+			return nil, errors.New("columns are required to encode object items to CSV")
+		}
+
+		record := make([]string, len(e.columns))
+		for i, column := range e.columns {
+			cell, err := stringifyCSVCell(value[column])
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", column, err)
+			}
+
+			record[i] = cell
+		}
+
+		return record, nil
+	case []any:
+		record := make([]string, len(value))
+		for i, v := range value {
+			cell, err := stringifyCSVCell(v)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+
+			record[i] = cell
+		}
+
+		return record, nil
+	default:
+		return nil, fmt.Errorf("expected an object or array item, got %T", item)
+	}
+}
+
+func stringifyCSVCell(value any) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	kind := reflectValue.Kind()
+	if kind == reflect.Map || kind == reflect.Slice || kind == reflect.Array || kind == reflect.Struct {
+		rawValue, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+
+		return string(rawValue), nil
+	}
+
+	return StringifySimpleScalar(reflectValue, kind)
+}
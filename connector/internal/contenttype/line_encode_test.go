@@ -0,0 +1,85 @@
+package contenttype
+
+import (
+	"bytes"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestLineEncoder(t *testing.T) {
+	items := []any{
+		map[string]any{"id": float64(1), "name": "Dog, \"Rex\"", "tags": []any{"a"}},
+		map[string]any{"id": float64(2), "name": "<Cat>", "status": nil},
+	}
+
+	testCases := []struct {
+		Name        string
+		ContentType string
+		Value       any
+		Columns     []string
+		Expected    string
+		Error       string
+	}{
+		{
+			Name:        "ndjson",
+			ContentType: rest.ContentTypeNdJSON,
+			Value:       items,
+			Expected:    "{\"id\":1,\"name\":\"Dog, \\\"Rex\\\"\",\"tags\":[\"a\"]}\n{\"id\":2,\"name\":\"<Cat>\",\"status\":null}\n",
+		},
+		{
+			Name:        "jsonl",
+			ContentType: "application/jsonl",
+			Value:       []any{float64(1), "a", nil},
+			Expected:    "1\n\"a\"\nnull\n",
+		},
+		{
+			Name:        "csv",
+			ContentType: rest.ContentTypeCSV,
+			Value:       items,
+			Expected:    "id,name,tags\n1,\"Dog, \"\"Rex\"\"\",\"[\"\"a\"\"]\"\n2,<Cat>,\n",
+		},
+		{
+			Name:        "csv_columns",
+			ContentType: rest.ContentTypeCSV,
+			Value:       items,
+			Columns:     []string{"name", "status"},
+			Expected:    "name,status\n\"Dog, \"\"Rex\"\"\",\n<Cat>,\n",
+		},
+		{
+			Name:        "csv_arrays",
+			ContentType: rest.ContentTypeCSV,
+			Value:       []any{[]any{"a", float64(1), true}},
+			Expected:    "a,1,true\n",
+		},
+		{
+			Name:        "not_array",
+			ContentType: rest.ContentTypeNdJSON,
+			Value:       map[string]any{},
+			Error:       "expected an array body",
+		},
+		{
+			Name:        "unsupported",
+			ContentType: rest.ContentTypeJSON,
+			Value:       items,
+			Error:       "unsupported content type",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			encoder, err := NewLineEncoder(tc.ContentType, tc.Value, tc.Columns)
+			if tc.Error != "" {
+				assert.ErrorContains(t, err, tc.Error)
+
+				return
+			}
+			assert.NilError(t, err)
+
+			var buf bytes.Buffer
+			assert.NilError(t, encoder.Encode(&buf))
+			assert.Equal(t, tc.Expected, buf.String())
+		})
+	}
+}
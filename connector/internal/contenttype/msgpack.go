@@ -0,0 +1,91 @@
+package contenttype
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// EncodeMsgPack encodes the value to MessagePack.
+func EncodeMsgPack(value any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetSortMapKeys(true)
+	enc.SetCustomStructTag("json")
+
+	if err := enc.Encode(value); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// DecodeMsgPack decodes the MessagePack data into JSON-compatible values.
+// Map keys are converted to strings, integers are widened to 64 bits, binary values are encoded as base64 strings
+// and timestamps are formatted in RFC 3339.
+func DecodeMsgPack(reader io.Reader) (any, error) {
+	dec := msgpack.NewDecoder(reader)
+	// map keys aren't always strings in MessagePack
+	dec.SetMapDecoder(func(d *msgpack.Decoder) (any, error) {
+		return d.DecodeUntypedMap()
+	})
+
+	var result any
+	if err := dec.Decode(&result); err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	return normalizeMsgPackValue(result), nil
+}
+
+func normalizeMsgPackValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalizeMsgPackValue(item)
+		}
+
+		return v
+	case map[any]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = normalizeMsgPackValue(item)
+		}
+
+		return result
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeMsgPackValue(item)
+		}
+
+		return v
+	case int8:
+		return int64(v)
+	case int16:
+		return int64(v)
+	case int32:
+		return int64(v)
+	case uint8:
+		return uint64(v)
+	case uint16:
+		return uint64(v)
+	case uint32:
+		return uint64(v)
+	case float32:
+		return float64(v)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
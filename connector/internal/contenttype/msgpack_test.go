@@ -0,0 +1,52 @@
+package contenttype
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"gotest.tools/v3/assert"
+)
+
+func TestMsgPack(t *testing.T) {
+	value := map[string]any{
+		"id":     int64(1),
+		"name":   "doggie",
+		"price":  10.5,
+		"tags":   []any{"dog", "cat"},
+		"active": true,
+		"owner":  nil,
+	}
+
+	rawBytes, err := EncodeMsgPack(value)
+	assert.NilError(t, err)
+
+	result, err := DecodeMsgPack(bytes.NewReader(rawBytes))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, value, result)
+
+	t.Run("normalize", func(t *testing.T) {
+		createdAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+		rawBytes, err := msgpack.Marshal(map[any]any{
+			1:           "one",
+			"data":      []byte("hello"),
+			"createdAt": createdAt,
+		})
+		assert.NilError(t, err)
+
+		result, err := DecodeMsgPack(bytes.NewReader(rawBytes))
+		assert.NilError(t, err)
+		assert.DeepEqual(t, map[string]any{
+			"1":         "one",
+			"data":      "aGVsbG8=",
+			"createdAt": "2024-01-02T03:04:05Z",
+		}, result)
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		result, err := DecodeMsgPack(bytes.NewReader(nil))
+		assert.NilError(t, err)
+		assert.Assert(t, result == nil)
+	})
+}
@@ -0,0 +1,70 @@
+package contenttype
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// ProtobufCodec encodes and decodes protobuf messages of the message descriptor.
+// Values are transcoded with the canonical JSON mapping of protobuf, so they can be typed with the JSON schema of the operation.
+type ProtobufCodec struct {
+	message protoreflect.MessageDescriptor
+}
+
+// NewProtobufCodec creates a new ProtobufCodec instance.
+func NewProtobufCodec(message protoreflect.MessageDescriptor) *ProtobufCodec {
+	return &ProtobufCodec{
+		message: message,
+	}
+}
+
+// Encode encodes the JSON-compatible value to the binary protobuf message.
+func (pc *ProtobufCodec) Encode(value any) ([]byte, error) {
+	rawJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := dynamicpb.NewMessage(pc.message)
+	if err := protojson.Unmarshal(rawJSON, msg); err != nil {
+		return nil, fmt.Errorf("failed to encode the protobuf message %s: %w", pc.message.FullName(), err)
+	}
+
+	return proto.Marshal(msg)
+}
+
+// DecodeJSON decodes the binary protobuf message and transcodes it to JSON.
+func (pc *ProtobufCodec) DecodeJSON(reader io.Reader) ([]byte, error) {
+	rawBytes, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	msg := dynamicpb.NewMessage(pc.message)
+	if err := proto.Unmarshal(rawBytes, msg); err != nil {
+		return nil, fmt.Errorf("failed to decode the protobuf message %s: %w", pc.message.FullName(), err)
+	}
+
+	return protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(msg)
+}
+
+// Decode decodes the binary protobuf message into JSON-compatible values.
+func (pc *ProtobufCodec) Decode(reader io.Reader) (any, error) {
+	rawJSON, err := pc.DecodeJSON(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	var result any
+	if err := json.Unmarshal(rawJSON, &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package contenttype
+
+import (
+	"bytes"
+	"testing"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"gotest.tools/v3/assert"
+)
+
+func TestProtobufCodec(t *testing.T) {
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("pet.proto"),
+		Package: proto.String("test.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Pet"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("id"), JsonName: proto.String("id"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+					{Name: proto.String("pet_name"), JsonName: proto.String("petName"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+					{Name: proto.String("tags"), JsonName: proto.String("tags"), Number: proto.Int32(3), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_REPEATED.Enum()},
+				},
+			},
+		},
+	}, nil)
+	assert.NilError(t, err)
+
+	codec := NewProtobufCodec(file.Messages().Get(0))
+	rawBytes, err := codec.Encode(map[string]any{
+		"id":      1,
+		"petName": "doggie",
+		"tags":    []any{"dog"},
+	})
+	assert.NilError(t, err)
+
+	result, err := codec.Decode(bytes.NewReader(rawBytes))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]any{
+		"id":      float64(1),
+		"petName": "doggie",
+		"tags":    []any{"dog"},
+	}, result)
+
+	// unpopulated fields are emitted
+	result, err = codec.Decode(bytes.NewReader(nil))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]any{
+		"id":      float64(0),
+		"petName": "",
+		"tags":    []any{},
+	}, result)
+
+	_, err = codec.Encode(map[string]any{"unknown": true})
+	assert.ErrorContains(t, err, "failed to encode the protobuf message test.v1.Pet")
+
+	_, err = codec.Decode(bytes.NewReader([]byte{0xff}))
+	assert.ErrorContains(t, err, "failed to decode the protobuf message test.v1.Pet")
+}
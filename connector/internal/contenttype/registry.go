@@ -0,0 +1,82 @@
+package contenttype
+
+import (
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// ResponseDecoder abstracts a decoder of response bodies with a custom content type.
+// The decoded value should be composed of JSON-compatible types, e.g. map[string]any, []any, string, float64 and bool,
+// so the result selection can be evaluated.
+type ResponseDecoder interface {
+	Decode(reader io.Reader, contentType string) (any, error)
+}
+
+// ResponseDecoderFunc is a function adapter of the ResponseDecoder interface.
+type ResponseDecoderFunc func(reader io.Reader, contentType string) (any, error)
+
+// Decode calls the decoder function.
+func (fn ResponseDecoderFunc) Decode(reader io.Reader, contentType string) (any, error) {
+	return fn(reader, contentType)
+}
+
+type registeredDecoder struct {
+	pattern string
+	decoder ResponseDecoder
+}
+
+// ResponseDecoderRegistry holds custom response decoders keyed by media type patterns.
+type ResponseDecoderRegistry struct {
+	decoders []registeredDecoder
+}
+
+// NewResponseDecoderRegistry creates a new ResponseDecoderRegistry instance.
+func NewResponseDecoderRegistry() *ResponseDecoderRegistry {
+	return &ResponseDecoderRegistry{}
+}
+
+// Register adds a decoder for content types which match the media type pattern,
+// e.g. application/vnd.vendor+cbor or application/*+cbor. Patterns follow the syntax of [path.Match] and are case-insensitive.
+func (r *ResponseDecoderRegistry) Register(pattern string, decoder ResponseDecoder) error {
+	pattern = strings.ToLower(strings.TrimSpace(pattern))
+	if pattern == "" {
+		return fmt.Errorf("%w: empty media type pattern", errInvalidDecoderPattern)
+	}
+
+	if decoder == nil {
+		return fmt.Errorf("%w: decoder of %s must not be nil", errInvalidDecoderPattern, pattern)
+	}
+
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("%w pattern %s: %w", errInvalidDecoderPattern, pattern, err)
+	}
+
+	r.decoders = append(r.decoders, registeredDecoder{
+		pattern: pattern,
+		decoder: decoder,
+	})
+
+	return nil
+}
+
+// Find returns the first registered decoder whose pattern matches the content type.
+func (r *ResponseDecoderRegistry) Find(contentType string) (ResponseDecoder, bool) {
+	if r == nil || len(r.decoders) == 0 {
+		return nil, false
+	}
+
+	mediaType := strings.ToLower(strings.TrimSpace(contentType))
+	if mediaType == "" {
+		return nil, false
+	}
+
+	for _, item := range r.decoders {
+		if matched, _ := path.Match(item.pattern, mediaType); matched {
+			return item.decoder, true
+		}
+	}
+
+	return nil, false
+}
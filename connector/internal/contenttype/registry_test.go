@@ -0,0 +1,41 @@
+package contenttype
+
+import (
+	"io"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestResponseDecoderRegistry(t *testing.T) {
+	newDecoder := func(name string) ResponseDecoder {
+		return ResponseDecoderFunc(func(reader io.Reader, contentType string) (any, error) {
+			return name, nil
+		})
+	}
+
+	registry := NewResponseDecoderRegistry()
+	assert.NilError(t, registry.Register("application/vnd.vendor+cbor", newDecoder("vendor")))
+	assert.NilError(t, registry.Register("Application/*+CBOR", newDecoder("cbor")))
+	assert.ErrorContains(t, registry.Register("application/[", newDecoder("invalid")), "invalid response decoder pattern")
+	assert.ErrorContains(t, registry.Register("", newDecoder("empty")), "empty media type pattern")
+	assert.ErrorContains(t, registry.Register("text/*", nil), "must not be nil")
+
+	for contentType, expected := range map[string]string{
+		"application/vnd.vendor+cbor": "vendor",
+		"application/VND.other+cbor":  "cbor",
+	} {
+		decoder, ok := registry.Find(contentType)
+		assert.Assert(t, ok, contentType)
+		result, err := decoder.Decode(nil, contentType)
+		assert.NilError(t, err)
+		assert.Equal(t, expected, result)
+	}
+
+	_, ok := registry.Find("application/json")
+	assert.Assert(t, !ok)
+
+	var nilRegistry *ResponseDecoderRegistry
+	_, ok = nilRegistry.Find("application/json")
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,172 @@
+package contenttype
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+const (
+	soap11EnvelopeNamespace = "http://schemas.xmlsoap.org/soap/envelope/"
+	soap12EnvelopeNamespace = "http://www.w3.org/2003/05/soap-envelope"
+)
+
+var errSOAPEnvelopeRequired = errors.New("the response isn't a SOAP envelope")
+
+// SOAPFault represents the fault element in the body of SOAP 1.1 and 1.2 responses.
+type SOAPFault struct {
+	// The fault code, e.g. soap:Client (1.1) or soap:Sender (1.2)
+	Code string
+	// The human-readable explanation of the fault
+	Reason string
+	// The application-specific error information
+	Detail any
+}
+
+// Error implements the error interface.
+func (sf *SOAPFault) Error() string {
+	return fmt.Sprintf("SOAP fault %s: %s", sf.Code, sf.Reason)
+}
+
+// IsSenderFault checks if the fault is caused by the request message, i.e. the Client (1.1) or Sender (1.2) fault code.
+func (sf *SOAPFault) IsSenderFault() bool {
+	code := sf.Code
+	if i := strings.LastIndex(code, ":"); i >= 0 {
+		code = code[i+1:]
+	}
+
+	return code == "Sender" || code == "Client" || strings.HasPrefix(code, "Client.")
+}
+
+// ToMap converts the fault to error details.
+func (sf *SOAPFault) ToMap() map[string]any {
+	result := map[string]any{
+		"code":   sf.Code,
+		"reason": sf.Reason,
+	}
+	if sf.Detail != nil {
+		result["detail"] = sf.Detail
+	}
+
+	return result
+}
+
+// EncodeSOAPEnvelope wraps the XML body in the SOAP envelope of the version.
+func EncodeSOAPEnvelope(version rest.SOAPVersion, body []byte) []byte {
+	namespace := soap11EnvelopeNamespace
+	if version == rest.SOAPVersion12 {
+		namespace = soap12EnvelopeNamespace
+	}
+
+	// remove the XML declaration of the body element
+	body = bytes.TrimSpace(body)
+	if bytes.HasPrefix(body, []byte("<?xml")) {
+		if end := bytes.Index(body, []byte("?>")); end >= 0 {
+			body = bytes.TrimSpace(body[end+2:])
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<soap:Envelope xmlns:soap="` + namespace + `"><soap:Body>`)
+	buf.Write(body)
+	buf.WriteString(`</soap:Body></soap:Envelope>`)
+
+	return buf.Bytes()
+}
+
+// DecodeSOAP decodes the result from the element in the body of the SOAP envelope.
+// Returns a *SOAPFault error if the body contains a fault.
+func (c *XMLDecoder) DecodeSOAP(r io.Reader, resultType schema.Type) (any, error) {
+	element, err := decodeSOAPBodyElement(r)
+	if err != nil || element == nil {
+		return nil, err
+	}
+
+	if element.Start.Name.Local == "Fault" {
+		return nil, parseSOAPFault(element)
+	}
+
+	if c.schema == nil {
+		return decodeArbitraryXMLBlock(element), nil
+	}
+
+	result, err := c.evalXMLField(element, "", rest.ObjectField{
+		ObjectField: schema.ObjectField{
+			Type: resultType,
+		},
+		HTTP: &rest.TypeSchema{},
+	}, []string{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the SOAP result: %w", err)
+	}
+
+	return result, nil
+}
+
+// DecodeSOAPFault decodes the fault from the SOAP response. Returns nil if the response doesn't contain any fault.
+func DecodeSOAPFault(r io.Reader) *SOAPFault {
+	element, err := decodeSOAPBodyElement(r)
+	if err != nil || element == nil || element.Start.Name.Local != "Fault" {
+		return nil
+	}
+
+	return parseSOAPFault(element)
+}
+
+func decodeSOAPBodyElement(r io.Reader) (*xmlBlock, error) {
+	envelope, err := newXMLTreeDecoder(r).Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	if envelope == nil || envelope.Start.Name.Local != "Envelope" {
+		return nil, errSOAPEnvelopeRequired
+	}
+
+	bodies := envelope.Fields["Body"]
+	if len(bodies) == 0 {
+		return nil, errSOAPEnvelopeRequired
+	}
+
+	for _, elements := range bodies[0].Fields {
+		if len(elements) > 0 {
+			return &elements[0], nil
+		}
+	}
+
+	return nil, nil
+}
+
+// parseSOAPFault reads the fault element of SOAP 1.1 (faultcode, faultstring, detail)
+// or SOAP 1.2 (Code/Value, Reason/Text, Detail).
+func parseSOAPFault(block *xmlBlock) *SOAPFault {
+	fault := &SOAPFault{}
+	if elements := block.Fields["faultcode"]; len(elements) > 0 {
+		fault.Code = strings.TrimSpace(elements[0].Data)
+	} else if codes := block.Fields["Code"]; len(codes) > 0 && len(codes[0].Fields["Value"]) > 0 {
+		fault.Code = strings.TrimSpace(codes[0].Fields["Value"][0].Data)
+	}
+
+	if elements := block.Fields["faultstring"]; len(elements) > 0 {
+		fault.Reason = strings.TrimSpace(elements[0].Data)
+	} else if reasons := block.Fields["Reason"]; len(reasons) > 0 && len(reasons[0].Fields["Text"]) > 0 {
+		fault.Reason = strings.TrimSpace(reasons[0].Fields["Text"][0].Data)
+	}
+
+	for _, key := range []string{"detail", "Detail"} {
+		if elements := block.Fields[key]; len(elements) > 0 {
+			fault.Detail = decodeArbitraryXMLBlock(&elements[0])
+
+			break
+		}
+	}
+
+	return fault
+}
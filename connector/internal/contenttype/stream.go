@@ -0,0 +1,106 @@
+package contenttype
+
+import (
+	"bufio"
+	"encoding/base64"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DefaultStreamChunkSize is the default size of binary chunks.
+const DefaultStreamChunkSize = 1024 * 1024
+
+// DecodeServerSentEvents reads server-sent events from the text/event-stream body line by line.
+// Each event is decoded to an object with id, event, data and retry fields. Comments and events without data are ignored.
+// The reader stops when reaching the maximum number of events. Unlimited if maxEvents is zero.
+func DecodeServerSentEvents(reader io.Reader, maxEvents int) ([]any, error) {
+	results := []any{}
+	scanner := bufio.NewScanner(reader)
+	// allow data lines up to 1 MiB
+	scanner.Buffer(make([]byte, 0, 64*1024), DefaultStreamChunkSize)
+
+	event := map[string]any{}
+	var dataLines []string
+
+	flushEvent := func() {
+		if len(dataLines) > 0 {
+			event["data"] = strings.Join(dataLines, "\n")
+			results = append(results, event)
+		}
+
+		event = map[string]any{}
+		dataLines = nil
+	}
+
+	for scanner.Scan() {
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			flushEvent()
+
+			if maxEvents > 0 && len(results) >= maxEvents {
+				return results, nil
+			}
+
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "data":
+			dataLines = append(dataLines, value)
+		case "id", "event":
+			event[field] = value
+		case "retry":
+			if retry, err := strconv.ParseInt(value, 10, 64); err == nil {
+				event[field] = retry
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	// the stream may end without the trailing blank line
+	if maxEvents <= 0 || len(results) < maxEvents {
+		flushEvent()
+	}
+
+	return results, nil
+}
+
+// DecodeBinaryChunks reads the binary body into base64-encoded chunks with the fixed size, so the raw content
+// isn't buffered entirely in memory. The reader stops when reaching the maximum number of chunks. Unlimited if maxChunks is zero.
+func DecodeBinaryChunks(reader io.Reader, chunkSize int, maxChunks int) ([]any, error) {
+	if chunkSize <= 0 {
+		chunkSize = DefaultStreamChunkSize
+	}
+
+	results := []any{}
+	buf := make([]byte, chunkSize)
+
+	for maxChunks <= 0 || len(results) < maxChunks {
+		n, err := io.ReadFull(reader, buf)
+		if n > 0 {
+			results = append(results, base64.StdEncoding.EncodeToString(buf[:n]))
+		}
+
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return results, nil
+}
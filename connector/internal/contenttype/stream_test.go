@@ -0,0 +1,55 @@
+package contenttype
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDecodeServerSentEvents(t *testing.T) {
+	body := `: this is a comment
+id: 1
+event: message
+data: {"text": "hello"}
+
+data: multi
+data: line
+retry: 3000
+
+event: ping
+
+id: 3
+data: last`
+
+	results, err := DecodeServerSentEvents(strings.NewReader(body), 0)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []any{
+		map[string]any{"id": "1", "event": "message", "data": `{"text": "hello"}`},
+		map[string]any{"data": "multi\nline", "retry": int64(3000)},
+		map[string]any{"id": "3", "data": "last"},
+	}, results)
+
+	results, err = DecodeServerSentEvents(strings.NewReader(body), 1)
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(results))
+}
+
+func TestDecodeBinaryChunks(t *testing.T) {
+	results, err := DecodeBinaryChunks(strings.NewReader("hello world"), 4, 0)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []any{
+		base64.StdEncoding.EncodeToString([]byte("hell")),
+		base64.StdEncoding.EncodeToString([]byte("o wo")),
+		base64.StdEncoding.EncodeToString([]byte("rld")),
+	}, results)
+
+	results, err = DecodeBinaryChunks(strings.NewReader("hello world"), 4, 2)
+	assert.NilError(t, err)
+	assert.Equal(t, 2, len(results))
+
+	results, err = DecodeBinaryChunks(strings.NewReader(""), 0, 0)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []any{}, results)
+}
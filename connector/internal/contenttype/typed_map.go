@@ -0,0 +1,70 @@
+package contenttype
+
+import (
+	"fmt"
+	"reflect"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// IsTypedMap checks if the type schema is a homogeneous map which is converted from additionalProperties.
+func IsTypedMap(typeSchema *rest.TypeSchema) bool {
+	return typeSchema != nil && typeSchema.AdditionalProperties != nil
+}
+
+// EvalTypedMap validates and converts values of the homogeneous map with the value schema, e.g. map[string]int64.
+// Null values are kept as is.
+func EvalTypedMap(value any, typeSchema *rest.TypeSchema) (map[string]any, error) {
+	reflectValue, ok := utils.UnwrapPointerFromAnyToReflectValue(value)
+	if !ok {
+		return nil, nil
+	}
+
+	object, ok := reflectValue.Interface().(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("expected an object, got %v", reflectValue.Interface())
+	}
+
+	valueSchema := typeSchema.AdditionalProperties
+	if len(valueSchema.Type) != 1 {
+		return object, nil
+	}
+
+	results := make(map[string]any, len(object))
+	for key, item := range object {
+		if utils.IsNil(item) {
+			results[key] = nil
+
+			continue
+		}
+
+		result, err := evalTypedMapValue(item, valueSchema.Type[0])
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+
+		results[key] = result
+	}
+
+	return results, nil
+}
+
+func evalTypedMapValue(value any, typeName string) (any, error) {
+	switch typeName {
+	case "boolean":
+		return utils.DecodeBoolean(value)
+	case "integer", "long":
+		return utils.DecodeInt[int64](value)
+	case "number":
+		return utils.DecodeFloat[float64](value)
+	case "string":
+		if str, ok := value.(string); ok {
+			return str, nil
+		}
+
+		return nil, fmt.Errorf("expected a string, got <%s> %v", reflect.TypeOf(value), value)
+	default:
+		return value, nil
+	}
+}
@@ -0,0 +1,135 @@
+package contenttype
+
+import (
+	"strings"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestEvalTypedMap(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		ValueType string
+		Input     any
+		Expected  map[string]any
+		ErrorMsg  string
+	}{
+		{
+			Name:      "integer",
+			ValueType: "integer",
+			Input:     map[string]any{"a": float64(1000000), "b": nil},
+			Expected:  map[string]any{"a": int64(1000000), "b": nil},
+		},
+		{
+			Name:      "boolean",
+			ValueType: "boolean",
+			Input:     map[string]any{"enabled": true},
+			Expected:  map[string]any{"enabled": true},
+		},
+		{
+			Name:      "invalid_string",
+			ValueType: "string",
+			Input:     map[string]any{"name": float64(1)},
+			ErrorMsg:  "name: expected a string, got <float64> 1",
+		},
+		{
+			Name:      "invalid_object",
+			ValueType: "string",
+			Input:     []any{"foo"},
+			ErrorMsg:  "expected an object, got [foo]",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := EvalTypedMap(tc.Input, &rest.TypeSchema{
+				Type: []string{"object"},
+				AdditionalProperties: &rest.TypeSchema{
+					Type: []string{tc.ValueType},
+				},
+			})
+			if tc.ErrorMsg != "" {
+				assert.ErrorContains(t, err, tc.ErrorMsg)
+
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.Expected, result)
+		})
+	}
+}
+
+func TestTypedMapEncoding(t *testing.T) {
+	ndcSchema := &rest.NDCHttpSchema{
+		ScalarTypes: schema.SchemaResponseScalarTypes{
+			"Int64Map": schema.ScalarType{
+				Representation: schema.NewTypeRepresentationJSON().Encode(),
+			},
+		},
+		ObjectTypes: map[string]rest.ObjectType{
+			"UpdateCounterBody": {
+				XML: &rest.XMLSchema{Name: "body"},
+				Fields: map[string]rest.ObjectField{
+					"counters": {
+						ObjectField: schema.ObjectField{
+							Type: schema.NewNamedType("Int64Map").Encode(),
+						},
+						HTTP: &rest.TypeSchema{
+							Type: []string{"object"},
+							AdditionalProperties: &rest.TypeSchema{
+								Type:   []string{"integer"},
+								Format: "int64",
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	bodyInfo := &rest.ArgumentInfo{
+		ArgumentInfo: schema.ArgumentInfo{
+			Type: schema.NewNamedType("UpdateCounterBody").Encode(),
+		},
+		HTTP: &rest.RequestParameter{},
+	}
+
+	t.Run("form_urlencoded", func(t *testing.T) {
+		result, err := NewURLParameterEncoder(ndcSchema, rest.ContentTypeFormURLEncoded).Encode(bodyInfo, map[string]any{
+			"counters": map[string]any{"views": float64(1000000)},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, "counters[views]=1000000", string(result))
+
+		_, err = NewURLParameterEncoder(ndcSchema, rest.ContentTypeFormURLEncoded).Encode(bodyInfo, map[string]any{
+			"counters": map[string]any{"views": "many"},
+		})
+		assert.ErrorContains(t, err, "views: ")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		result, err := NewJSONEncoder(ndcSchema).Encode(bodyInfo, map[string]any{
+			"counters": map[string]any{"views": float64(1000000)},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, `{"counters":{"views":1000000}}`+"\n", string(result))
+
+		decoded, err := NewJSONDecoder(ndcSchema).Decode(strings.NewReader(`{"counters":{"views":12}}`), bodyInfo.Type)
+		assert.NilError(t, err)
+		assert.DeepEqual(t, map[string]any{"counters": map[string]any{"views": int64(12)}}, decoded)
+
+		_, err = NewJSONDecoder(ndcSchema).Decode(strings.NewReader(`{"counters":{"views":true}}`), bodyInfo.Type)
+		assert.ErrorContains(t, err, "counters: views: ")
+	})
+
+	t.Run("xml", func(t *testing.T) {
+		result, err := NewXMLEncoder(ndcSchema).Encode(bodyInfo, map[string]any{
+			"counters": map[string]any{"views": float64(1000000)},
+		})
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(string(result), "<counters><views>1000000</views></counters>"), string(result))
+	})
+}
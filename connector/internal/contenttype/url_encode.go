@@ -127,6 +127,16 @@ func (c *URLParameterEncoder) EncodeParameterValues(objectField *rest.ObjectFiel
 			return nil, fmt.Errorf("%s: %w", strings.Join(fieldPaths, ""), errArgumentRequired)
 		}
 		iScalar, ok := c.schema.ScalarTypes[ty.Name]
+		if ok && IsTypedMap(typeSchema) {
+			// entries of the map are serialized as object properties with the style and explode rules
+			object, err := EvalTypedMap(reflectValue.Interface(), typeSchema)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", strings.Join(fieldPaths, ""), err)
+			}
+
+			return c.encodeParameterReflectionValues(reflect.ValueOf(object), fieldPaths)
+		}
+
 		if ok {
 			return c.encodeScalarParameterReflectionValues(reflectValue, &iScalar, fieldPaths)
 		}
@@ -16,6 +16,7 @@ var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
 var (
 	errArgumentRequired        = errors.New("argument is required")
 	errRequestBodyTypeRequired = errors.New("failed to decode request body, empty body type")
+	errInvalidDecoderPattern   = errors.New("invalid response decoder")
 )
 
 func escapeQuotes(s string) string {
@@ -1,8 +1,10 @@
 package contenttype
 
 import (
+	"bytes"
 	"encoding/json"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
 	"strconv"
@@ -12,10 +14,21 @@ import (
 	"github.com/hasura/ndc-sdk-go/schema"
 )
 
+const (
+	// XMLMaxDepth is the maximum nesting depth of elements in XML documents to be decoded.
+	XMLMaxDepth = 256
+	// XMLMaxTokens is the maximum number of tokens in XML documents to be decoded.
+	XMLMaxTokens = 10_000_000
+)
+
+var (
+	errXMLLimitExceeded     = errors.New("xml limit exceeded")
+	errXMLEntityDeclaration = errors.New("xml entity declarations are not allowed")
+)
+
 // XMLDecoder implements a dynamic XML decoder from the HTTP schema.
 type XMLDecoder struct {
-	schema  *rest.NDCHttpSchema
-	decoder *xml.Decoder
+	schema *rest.NDCHttpSchema
 }
 
 // NewXMLDecoder creates a new XML encoder.
@@ -27,42 +40,26 @@ func NewXMLDecoder(httpSchema *rest.NDCHttpSchema) *XMLDecoder {
 
 // Decode unmarshals xml bytes to a dynamic type.
 func (c *XMLDecoder) Decode(r io.Reader, resultType schema.Type) (any, error) {
-	c.decoder = xml.NewDecoder(r)
-
-	for {
-		token, err := c.decoder.Token()
-		if err != nil {
-			return nil, err
-		}
-		if token == nil {
-			break
-		}
-
-		if se, ok := token.(xml.StartElement); ok {
-			xmlTree := createXMLBlock(se)
-			if err := evalXMLTree(c.decoder, xmlTree); err != nil {
-				return nil, fmt.Errorf("failed to decode the xml result: %w", err)
-			}
-
-			if c.schema == nil {
-				return decodeArbitraryXMLBlock(xmlTree), nil
-			}
+	xmlTree, err := newXMLTreeDecoder(r).Decode()
+	if err != nil || xmlTree == nil {
+		return nil, err
+	}
 
-			result, err := c.evalXMLField(xmlTree, "", rest.ObjectField{
-				ObjectField: schema.ObjectField{
-					Type: resultType,
-				},
-				HTTP: &rest.TypeSchema{},
-			}, []string{})
-			if err != nil {
-				return nil, fmt.Errorf("failed to decode the xml result: %w", err)
-			}
+	if c.schema == nil {
+		return decodeArbitraryXMLBlock(xmlTree), nil
+	}
 
-			return result, nil
-		}
+	result, err := c.evalXMLField(xmlTree, "", rest.ObjectField{
+		ObjectField: schema.ObjectField{
+			Type: resultType,
+		},
+		HTTP: &rest.TypeSchema{},
+	}, []string{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the xml result: %w", err)
 	}
 
-	return nil, nil
+	return result, nil
 }
 
 func (c *XMLDecoder) evalXMLField(block *xmlBlock, fieldName string, field rest.ObjectField, fieldPaths []string) (any, error) {
@@ -409,10 +406,86 @@ func createXMLBlock(start xml.StartElement) *xmlBlock {
 	}
 }
 
-func evalXMLTree(decoder *xml.Decoder, block *xmlBlock) error {
+// DecodeArbitraryXML decodes an arbitrary XML from a reader stream.
+func DecodeArbitraryXML(r io.Reader) (any, error) {
+	xmlTree, err := newXMLTreeDecoder(r).Decode()
+	if err != nil || xmlTree == nil {
+		return nil, err
+	}
+
+	return decodeArbitraryXMLBlock(xmlTree), nil
+}
+
+// xmlTreeDecoder reads untrusted XML documents into xmlBlock trees with explicit limits.
+// Entities other than the predefined XML entities are never resolved.
+// DTD entity declarations are rejected so external entities can't be loaded or expanded.
+type xmlTreeDecoder struct {
+	decoder    *xml.Decoder
+	maxDepth   int
+	maxTokens  int
+	tokenCount int
+}
+
+func newXMLTreeDecoder(r io.Reader) *xmlTreeDecoder {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = true
+	decoder.Entity = nil
+
+	return &xmlTreeDecoder{
+		decoder:   decoder,
+		maxDepth:  XMLMaxDepth,
+		maxTokens: XMLMaxTokens,
+	}
+}
+
+// Decode reads the root element and its children.
+func (d *xmlTreeDecoder) Decode() (*xmlBlock, error) {
+	for {
+		token, err := d.token()
+		if err != nil {
+			return nil, err
+		}
+		if token == nil {
+			return nil, nil
+		}
+
+		if se, ok := token.(xml.StartElement); ok {
+			xmlTree := createXMLBlock(se)
+			if err := d.evalXMLTree(xmlTree, 1); err != nil {
+				return nil, fmt.Errorf("failed to decode the xml result: %w", err)
+			}
+
+			return xmlTree, nil
+		}
+	}
+}
+
+func (d *xmlTreeDecoder) token() (xml.Token, error) {
+	token, err := d.decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	d.tokenCount++
+	if d.maxTokens > 0 && d.tokenCount > d.maxTokens {
+		return nil, fmt.Errorf("%w: the number of tokens exceeds the limit of %d", errXMLLimitExceeded, d.maxTokens)
+	}
+
+	if directive, ok := token.(xml.Directive); ok && isXMLEntityDeclaration(directive) {
+		return nil, errXMLEntityDeclaration
+	}
+
+	return token, nil
+}
+
+func (d *xmlTreeDecoder) evalXMLTree(block *xmlBlock, depth int) error {
+	if d.maxDepth > 0 && depth > d.maxDepth {
+		return fmt.Errorf("%w: the nesting depth exceeds the limit of %d", errXMLLimitExceeded, d.maxDepth)
+	}
+
 L:
 	for {
-		nextToken, err := decoder.Token()
+		nextToken, err := d.token()
 		if err != nil {
 			return err
 		}
@@ -424,7 +497,7 @@ L:
 		switch tok := nextToken.(type) {
 		case xml.StartElement:
 			childBlock := createXMLBlock(tok)
-			if err := evalXMLTree(decoder, childBlock); err != nil {
+			if err := d.evalXMLTree(childBlock, depth+1); err != nil {
 				return err
 			}
 			block.Fields[tok.Name.Local] = append(block.Fields[tok.Name.Local], *childBlock)
@@ -438,32 +511,9 @@ L:
 	return nil
 }
 
-// DecodeArbitraryXML decodes an arbitrary XML from a reader stream.
-func DecodeArbitraryXML(r io.Reader) (any, error) {
-	decoder := xml.NewDecoder(r)
-
-	for {
-		token, err := decoder.Token()
-		if err != nil {
-			return nil, err
-		}
-		if token == nil {
-			break
-		}
-
-		if se, ok := token.(xml.StartElement); ok {
-			xmlTree := createXMLBlock(se)
-			if err := evalXMLTree(decoder, xmlTree); err != nil {
-				return nil, fmt.Errorf("failed to decode the xml result: %w", err)
-			}
-
-			result := decodeArbitraryXMLBlock(xmlTree)
-
-			return result, nil
-		}
-	}
-
-	return nil, nil
+// isXMLEntityDeclaration checks if the directive declares entities, e.g. <!DOCTYPE foo [<!ENTITY xxe SYSTEM "file:///etc/passwd">]>.
+func isXMLEntityDeclaration(directive xml.Directive) bool {
+	return bytes.Contains(bytes.ToUpper(directive), []byte("<!ENTITY"))
 }
 
 func findXMLLeafObjectField(objectType rest.ObjectType) (*rest.ObjectField, string, bool) {
@@ -72,6 +72,69 @@ func TestDecodeXML(t *testing.T) {
 	}
 }
 
+func TestDecodeXMLLimits(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Body     string
+		ErrorMsg string
+	}{
+		{
+			Name:     "external_entity",
+			Body:     `<?xml version="1.0"?><!DOCTYPE foo [<!ENTITY xxe SYSTEM "file:///etc/passwd">]><foo>&xxe;</foo>`,
+			ErrorMsg: "xml entity declarations are not allowed",
+		},
+		{
+			Name:     "billion_laughs",
+			Body:     `<?xml version="1.0"?><!DOCTYPE lolz [<!entity lol "lol"><!ENTITY lol2 "&lol;&lol;">]><lolz>&lol2;</lolz>`,
+			ErrorMsg: "xml entity declarations are not allowed",
+		},
+		{
+			Name:     "undefined_entity",
+			Body:     `<foo>&xxe;</foo>`,
+			ErrorMsg: "invalid character entity &xxe;",
+		},
+		{
+			Name:     "depth",
+			Body:     strings.Repeat("<a>", XMLMaxDepth+1) + strings.Repeat("</a>", XMLMaxDepth+1),
+			ErrorMsg: "the nesting depth exceeds the limit of 256",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := DecodeArbitraryXML(strings.NewReader(tc.Body))
+			assert.ErrorContains(t, err, tc.ErrorMsg)
+		})
+	}
+
+	result, err := DecodeArbitraryXML(strings.NewReader(`<!DOCTYPE note SYSTEM "note.dtd"><note>&amp;</note>`))
+	assert.NilError(t, err)
+	assert.Equal(t, "&", result)
+
+	decoder := newXMLTreeDecoder(strings.NewReader("<a><b>1</b><b>2</b><b>3</b></a>"))
+	decoder.maxTokens = 5
+	_, err = decoder.Decode()
+	assert.ErrorContains(t, err, "the number of tokens exceeds the limit of 5")
+}
+
+func FuzzDecodeArbitraryXML(f *testing.F) {
+	for _, seed := range []string{
+		`<pet><id>1</id><name>doggie</name></pet>`,
+		`<?xml version="1.0" encoding="UTF-8"?><items><item id="1">a</item><item id="2">b</item></items>`,
+		`<!DOCTYPE foo [<!ENTITY xxe SYSTEM "file:///etc/passwd">]><foo>&xxe;</foo>`,
+		`<a><b><c><d/></c></b></a>`,
+		`<a>`,
+		``,
+	} {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, body string) {
+		_, _ = DecodeArbitraryXML(strings.NewReader(body))
+		_, _ = NewXMLDecoder(nil).Decode(strings.NewReader(body), schema.NewNamedType("JSON").Encode())
+	})
+}
+
 func createMockSchema(t *testing.T) *rest.NDCHttpSchema {
 	var ndcSchema rest.NDCHttpSchema
 	rawSchemaBytes, err := os.ReadFile("../../../ndc-http-schema/openapi/testdata/petstore3/expected.json")
@@ -31,11 +31,16 @@ func (c *XMLEncoder) Encode(bodyInfo *rest.ArgumentInfo, bodyData any) ([]byte,
 	var buf bytes.Buffer
 	enc := xml.NewEncoder(&buf)
 
+	var typeSchema *rest.TypeSchema
+	if bodyInfo.HTTP != nil {
+		typeSchema = bodyInfo.HTTP.Schema
+	}
+
 	err := c.evalXMLField(enc, "", rest.ObjectField{
 		ObjectField: schema.ObjectField{
 			Type: bodyInfo.Type,
 		},
-		HTTP: bodyInfo.HTTP.Schema,
+		HTTP: typeSchema,
 	}, bodyData, []string{})
 
 	if err != nil {
@@ -151,6 +156,15 @@ func (c *XMLEncoder) evalXMLField(enc *xml.Encoder, name string, field rest.Obje
 		}
 
 		if _, ok := c.schema.ScalarTypes[t.Name]; ok {
+			if IsTypedMap(field.HTTP) {
+				object, err := EvalTypedMap(value, field.HTTP)
+				if err != nil {
+					return fmt.Errorf("%s: %w", strings.Join(fieldPaths, "."), err)
+				}
+
+				value = object
+			}
+
 			if err := c.encodeSimpleScalar(enc, xmlName, reflect.ValueOf(value), attributes, fieldPaths); err != nil {
 				return fmt.Errorf("%s: %w", strings.Join(fieldPaths, "."), err)
 			}
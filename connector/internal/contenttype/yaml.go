@@ -0,0 +1,68 @@
+package contenttype
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeYAML decodes YAML documents into JSON-compatible values.
+// The value of the single document is returned, or an array of values if the stream contains many documents.
+// Map keys are converted to strings and timestamps are formatted in RFC 3339.
+func DecodeYAML(reader io.Reader) (any, error) {
+	decoder := yaml.NewDecoder(reader)
+
+	var documents []any
+	for {
+		var document any
+		if err := decoder.Decode(&document); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+
+			return nil, err
+		}
+
+		documents = append(documents, normalizeYAMLValue(document))
+	}
+
+	switch len(documents) {
+	case 0:
+		return nil, nil
+	case 1:
+		return documents[0], nil
+	default:
+		return documents, nil
+	}
+}
+
+func normalizeYAMLValue(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			v[key] = normalizeYAMLValue(item)
+		}
+
+		return v
+	case map[any]any:
+		result := make(map[string]any, len(v))
+		for key, item := range v {
+			result[fmt.Sprint(key)] = normalizeYAMLValue(item)
+		}
+
+		return result
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeYAMLValue(item)
+		}
+
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
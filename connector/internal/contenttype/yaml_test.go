@@ -0,0 +1,70 @@
+package contenttype
+
+import (
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Body     string
+		Expected any
+	}{
+		{
+			Name: "object",
+			Body: `apiVersion: v1
+kind: Pod
+metadata:
+  name: nginx
+  labels:
+    1: one
+  creationTimestamp: 2024-01-02T03:04:05Z
+spec:
+  replicas: 2
+  ready: true
+  ports: [80, 443]
+`,
+			Expected: map[string]any{
+				"apiVersion": "v1",
+				"kind":       "Pod",
+				"metadata": map[string]any{
+					"name":              "nginx",
+					"labels":            map[string]any{"1": "one"},
+					"creationTimestamp": "2024-01-02T03:04:05Z",
+				},
+				"spec": map[string]any{
+					"replicas": 2,
+					"ready":    true,
+					"ports":    []any{80, 443},
+				},
+			},
+		},
+		{
+			Name: "multiple_documents",
+			Body: "name: a\n---\nname: b\n",
+			Expected: []any{
+				map[string]any{"name": "a"},
+				map[string]any{"name": "b"},
+			},
+		},
+		{
+			Name:     "empty",
+			Body:     "",
+			Expected: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := DecodeYAML(strings.NewReader(tc.Body))
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.Expected, result)
+		})
+	}
+
+	_, err := DecodeYAML(strings.NewReader("name: [a"))
+	assert.ErrorContains(t, err, "yaml")
+}
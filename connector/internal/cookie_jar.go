@@ -0,0 +1,247 @@
+package internal
+
+import (
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+)
+
+const (
+	defaultCookieJarTTL         = time.Hour
+	defaultCookieJarMaxSessions = 1000
+	defaultCookieJarMaxCookies  = 50
+)
+
+// CookieJar stores cookies of upstream responses in memory and sends them in subsequent requests,
+// e.g. session cookies established by a login procedure. Cookies are scoped by the server and the forwarded session identity.
+type CookieJar struct {
+	sessionHeader string
+	ttl           time.Duration
+	maxSessions   int
+	maxCookies    int
+	sessions      map[string]*cookieSession
+	lock          sync.Mutex
+}
+
+type cookieSession struct {
+	// cookies are ordered by the insertion time, so the oldest cookie is evicted first
+	cookies    []storedCookie
+	lastAccess time.Time
+}
+
+type storedCookie struct {
+	name      string
+	value     string
+	expiresAt time.Time
+}
+
+// NewCookieJar creates a CookieJar instance. Returns nil if the cookie jar isn't configured.
+func NewCookieJar(config *configuration.Configuration) *CookieJar {
+	if config == nil || config.CookieJar == nil {
+		return nil
+	}
+
+	result := &CookieJar{
+		sessionHeader: config.CookieJar.SessionHeader,
+		ttl:           defaultCookieJarTTL,
+		maxSessions:   defaultCookieJarMaxSessions,
+		maxCookies:    defaultCookieJarMaxCookies,
+		sessions:      make(map[string]*cookieSession),
+	}
+
+	if config.CookieJar.TTL > 0 {
+		result.ttl = time.Duration(config.CookieJar.TTL) * time.Second
+	}
+
+	if config.CookieJar.MaxSessions > 0 {
+		result.maxSessions = int(config.CookieJar.MaxSessions)
+	}
+
+	if config.CookieJar.MaxCookies > 0 {
+		result.maxCookies = int(config.CookieJar.MaxCookies)
+	}
+
+	return result
+}
+
+// SetCookieJar sets the cookie jar of upstream requests.
+func (um *UpstreamManager) SetCookieJar(jar *CookieJar) {
+	um.cookieJar = jar
+}
+
+// GetSession returns the session identity from forwarded headers. Returns nil if the request doesn't use the cookie jar.
+func (cj *CookieJar) GetSession(headers map[string]string) *string {
+	if cj == nil {
+		return nil
+	}
+
+	if cj.sessionHeader == "" {
+		session := ""
+
+		return &session
+	}
+
+	for key, value := range headers {
+		if strings.EqualFold(key, cj.sessionHeader) && value != "" {
+			return &value
+		}
+	}
+
+	return nil
+}
+
+// Cookies returns unexpired cookies of the session on the server.
+func (cj *CookieJar) Cookies(namespace, serverID, session string) []*http.Cookie {
+	now := time.Now()
+	key := getCookieJarKey(namespace, serverID, session)
+
+	cj.lock.Lock()
+	defer cj.lock.Unlock()
+
+	cs, ok := cj.sessions[key]
+	if !ok {
+		return nil
+	}
+
+	if now.Sub(cs.lastAccess) > cj.ttl {
+		delete(cj.sessions, key)
+
+		return nil
+	}
+
+	cs.lastAccess = now
+	cs.removeExpiredCookies(now)
+	results := make([]*http.Cookie, len(cs.cookies))
+	for i, cookie := range cs.cookies {
+		results[i] = &http.Cookie{
+			Name:  cookie.name,
+			Value: cookie.value,
+		}
+	}
+
+	return results
+}
+
+// SetCookies stores cookies of the Set-Cookie response headers. Cookies with a past expiry or a negative max age are removed.
+func (cj *CookieJar) SetCookies(namespace, serverID, session string, cookies []*http.Cookie) {
+	if len(cookies) == 0 {
+		return
+	}
+
+	now := time.Now()
+	key := getCookieJarKey(namespace, serverID, session)
+
+	cj.lock.Lock()
+	defer cj.lock.Unlock()
+
+	cs, ok := cj.sessions[key]
+	if !ok || now.Sub(cs.lastAccess) > cj.ttl {
+		cj.evictSessions(now)
+		cs = &cookieSession{}
+		cj.sessions[key] = cs
+	}
+
+	cs.lastAccess = now
+	for _, cookie := range cookies {
+		cs.removeCookie(cookie.Name)
+
+		var expiresAt time.Time
+		switch {
+		case cookie.MaxAge < 0:
+			continue
+		case cookie.MaxAge > 0:
+			expiresAt = now.Add(time.Duration(cookie.MaxAge) * time.Second)
+		case !cookie.Expires.IsZero():
+			if !cookie.Expires.After(now) {
+				continue
+			}
+			expiresAt = cookie.Expires
+		}
+
+		cs.cookies = append(cs.cookies, storedCookie{
+			name:      cookie.Name,
+			value:     cookie.Value,
+			expiresAt: expiresAt,
+		})
+	}
+
+	cs.removeExpiredCookies(now)
+	if len(cs.cookies) > cj.maxCookies {
+		cs.cookies = cs.cookies[len(cs.cookies)-cj.maxCookies:]
+	}
+}
+
+// remove idle sessions and the least recently used session if the jar is full. The caller must hold the lock.
+func (cj *CookieJar) evictSessions(now time.Time) {
+	var oldestKey string
+	var oldestAccess time.Time
+
+	for key, cs := range cj.sessions {
+		if now.Sub(cs.lastAccess) > cj.ttl {
+			delete(cj.sessions, key)
+
+			continue
+		}
+
+		if oldestKey == "" || cs.lastAccess.Before(oldestAccess) {
+			oldestKey = key
+			oldestAccess = cs.lastAccess
+		}
+	}
+
+	if len(cj.sessions) >= cj.maxSessions {
+		delete(cj.sessions, oldestKey)
+	}
+}
+
+func (cs *cookieSession) removeCookie(name string) {
+	for i, cookie := range cs.cookies {
+		if cookie.name == name {
+			cs.cookies = append(cs.cookies[:i], cs.cookies[i+1:]...)
+
+			return
+		}
+	}
+}
+
+func (cs *cookieSession) removeExpiredCookies(now time.Time) {
+	cookies := cs.cookies[:0]
+	for _, cookie := range cs.cookies {
+		if cookie.expiresAt.IsZero() || cookie.expiresAt.After(now) {
+			cookies = append(cookies, cookie)
+		}
+	}
+
+	cs.cookies = cookies
+}
+
+// inject stored cookies into the request. Cookies in the forwarded Cookie header take precedence.
+func (cj *CookieJar) inject(req *http.Request, request *RetryableRequest, namespace string) {
+	if cj == nil || request.CookieSession == nil {
+		return
+	}
+
+	for _, cookie := range cj.Cookies(namespace, request.ServerID, *request.CookieSession) {
+		if _, err := req.Cookie(cookie.Name); err == nil {
+			continue
+		}
+
+		req.AddCookie(cookie)
+	}
+}
+
+// store cookies of the response.
+func (cj *CookieJar) store(resp *http.Response, request *RetryableRequest, namespace string) {
+	if cj == nil || request.CookieSession == nil || resp == nil {
+		return
+	}
+
+	cj.SetCookies(namespace, request.ServerID, *request.CookieSession, resp.Cookies())
+}
+
+func getCookieJarKey(namespace, serverID, session string) string {
+	return strings.Join([]string{namespace, serverID, session}, "\x00")
+}
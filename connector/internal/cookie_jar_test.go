@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"gotest.tools/v3/assert"
+)
+
+func TestCookieJar(t *testing.T) {
+	assert.Assert(t, NewCookieJar(&configuration.Configuration{}) == nil)
+
+	jar := NewCookieJar(&configuration.Configuration{
+		CookieJar: &configuration.CookieJarSettings{
+			SessionHeader: "X-Hasura-User-Id",
+			MaxSessions:   2,
+			MaxCookies:    2,
+		},
+	})
+
+	assert.Assert(t, jar.GetSession(map[string]string{}) == nil)
+	session := jar.GetSession(map[string]string{"x-hasura-user-id": "1"})
+	assert.Equal(t, "1", *session)
+
+	request := &RetryableRequest{ServerID: "foo", CookieSession: session}
+	jar.store(&http.Response{
+		Header: http.Header{
+			"Set-Cookie": []string{
+				"session=abc; Path=/; HttpOnly",
+				"expired=1; Max-Age=-1",
+				"old=1; Expires=" + time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat),
+			},
+		},
+	}, request, "pet store")
+
+	req, err := http.NewRequest(http.MethodGet, "http://localhost/pets", nil)
+	assert.NilError(t, err)
+	jar.inject(req, request, "pet store")
+	assert.Equal(t, "session=abc", req.Header.Get("Cookie"))
+
+	// cookies are scoped by the server and the session
+	assert.Equal(t, 0, len(jar.Cookies("pet store", "bar", "1")))
+	assert.Equal(t, 0, len(jar.Cookies("pet store", "foo", "2")))
+
+	// cookies in the forwarded Cookie header take precedence
+	req, err = http.NewRequest(http.MethodGet, "http://localhost/pets", nil)
+	assert.NilError(t, err)
+	req.Header.Set("Cookie", "session=xyz")
+	jar.inject(req, request, "pet store")
+	assert.Equal(t, "session=xyz", req.Header.Get("Cookie"))
+
+	t.Run("max_cookies", func(t *testing.T) {
+		jar.SetCookies("pet store", "foo", "1", []*http.Cookie{
+			{Name: "a", Value: "1"},
+			{Name: "b", Value: "2"},
+		})
+		cookies := jar.Cookies("pet store", "foo", "1")
+		assert.DeepEqual(t, []string{"a=1", "b=2"}, []string{cookies[0].String(), cookies[1].String()})
+
+		// the cookie is removed if the max age is negative
+		jar.SetCookies("pet store", "foo", "1", []*http.Cookie{{Name: "a", MaxAge: -1}})
+		cookies = jar.Cookies("pet store", "foo", "1")
+		assert.Equal(t, 1, len(cookies))
+		assert.Equal(t, "b=2", cookies[0].String())
+	})
+
+	t.Run("max_sessions", func(t *testing.T) {
+		jar.SetCookies("pet store", "foo", "2", []*http.Cookie{{Name: "a", Value: "2"}})
+		jar.SetCookies("pet store", "foo", "3", []*http.Cookie{{Name: "a", Value: "3"}})
+		assert.Equal(t, 2, len(jar.sessions))
+		assert.Equal(t, 0, len(jar.Cookies("pet store", "foo", "1")))
+		assert.Equal(t, 1, len(jar.Cookies("pet store", "foo", "3")))
+	})
+
+	t.Run("ttl", func(t *testing.T) {
+		jar.ttl = time.Millisecond
+		time.Sleep(5 * time.Millisecond)
+		assert.Equal(t, 0, len(jar.Cookies("pet store", "foo", "3")))
+	})
+}
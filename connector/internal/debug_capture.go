@@ -0,0 +1,562 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+const (
+	defaultDebugCaptureDir          string = "debug-captures"
+	defaultDebugCaptureMaxBodyBytes int    = 64 * 1024
+	defaultDebugCaptureMaxFiles     int    = 100
+	defaultDebugCaptureMaxTotal     int64  = 50 * 1024 * 1024
+)
+
+const (
+	debugCaptureTimeFormat = "20060102T150405"
+	debugCaptureFileExt    = ".json"
+)
+
+type debugCaptureContextKey struct{}
+
+// DebugCaptureStore creates debug captures of request lifecycles and writes them to JSON files.
+type DebugCaptureStore struct {
+	config        *configuration.Configuration
+	header        string
+	token         string
+	dir           string
+	maxBodyBytes  int
+	maxFiles      int
+	maxTotalBytes int64
+	// serializes writes and the retention cleanup of captured files
+	lock sync.Mutex
+}
+
+// NewDebugCaptureStore creates a DebugCaptureStore instance. Returns nil if the debug capture is disabled.
+func NewDebugCaptureStore(config *configuration.Configuration, configDir string) (*DebugCaptureStore, error) {
+	if config == nil || config.DebugCapture == nil || !config.DebugCapture.Enabled {
+		return nil, nil
+	}
+
+	settings := config.DebugCapture
+	store := &DebugCaptureStore{
+		config:        config,
+		header:        settings.GetHeader(),
+		dir:           settings.Dir,
+		maxBodyBytes:  int(settings.MaxBodyBytes),
+		maxFiles:      int(settings.MaxFiles),
+		maxTotalBytes: int64(settings.MaxTotalBytes),
+	}
+
+	if err := settings.Validate(); err != nil {
+		return nil, fmt.Errorf("debugCapture: %w", err)
+	}
+
+	token, err := settings.Token.Get()
+	if err != nil {
+		return nil, fmt.Errorf("debugCapture.token: %w", err)
+	}
+
+	// an empty token would let any client capture business data of requests
+	if token == "" {
+		return nil, errors.New("debugCapture.token must not be empty")
+	}
+
+	store.token = token
+
+	if store.dir == "" {
+		store.dir = defaultDebugCaptureDir
+	}
+
+	if !filepath.IsAbs(store.dir) {
+		store.dir = filepath.Join(configDir, store.dir)
+	}
+
+	if store.maxBodyBytes <= 0 {
+		store.maxBodyBytes = defaultDebugCaptureMaxBodyBytes
+	}
+
+	if store.maxFiles <= 0 {
+		store.maxFiles = defaultDebugCaptureMaxFiles
+	}
+
+	if store.maxTotalBytes <= 0 {
+		store.maxTotalBytes = defaultDebugCaptureMaxTotal
+	}
+
+	return store, nil
+}
+
+// Start creates a debug capture if any of raw arguments forwards the trigger header with the expected token.
+// Returns nil if the request isn't captured.
+func (dcs *DebugCaptureStore) Start(kind string, operationName string, request any, rawArgs ...map[string]any) *DebugCapture {
+	if dcs == nil {
+		return nil
+	}
+
+	var triggered bool
+	for _, args := range rawArgs {
+		value, err := getForwardedHeader(dcs.config, args, dcs.header)
+		if err != nil || value == "" {
+			continue
+		}
+
+		if subtle.ConstantTimeCompare([]byte(value), []byte(dcs.token)) == 1 {
+			triggered = true
+
+			break
+		}
+	}
+
+	if !triggered {
+		return nil
+	}
+
+	var argumentField string
+	if dcs.config.ForwardHeaders.ArgumentField != nil {
+		argumentField = *dcs.config.ForwardHeaders.ArgumentField
+	}
+
+	return &DebugCapture{
+		ID:           uuid.NewString(),
+		Kind:         kind,
+		Operation:    operationName,
+		StartedAt:    time.Now().UTC(),
+		Request:      maskDebugCaptureRequest(request, argumentField, dcs.header),
+		Upstream:     []*DebugCaptureExchange{},
+		maxBodyBytes: dcs.maxBodyBytes,
+	}
+}
+
+// Save completes the capture with the final result or error and writes it to a JSON file. Returns the file path.
+// The oldest captured files are removed afterwards to keep the directory within the retention limits.
+func (dcs *DebugCaptureStore) Save(capture *DebugCapture, result any, err error) (string, error) {
+	if dcs == nil || capture == nil {
+		return "", nil
+	}
+
+	capture.finish(result, err)
+
+	rawBytes, err := json.MarshalIndent(capture, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode the debug capture: %w", err)
+	}
+
+	// a single capture larger than the total limit would evict every other file and itself
+	if int64(len(rawBytes)) > dcs.maxTotalBytes {
+		return "", fmt.Errorf("the debug capture size %d exceeds the maximum total size %d", len(rawBytes), dcs.maxTotalBytes)
+	}
+
+	dcs.lock.Lock()
+	defer dcs.lock.Unlock()
+
+	if err := os.MkdirAll(dcs.dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create the debug capture directory: %w", err)
+	}
+
+	filePath := filepath.Join(dcs.dir, fmt.Sprintf("%s-%s%s", capture.StartedAt.Format(debugCaptureTimeFormat), capture.ID, debugCaptureFileExt))
+	if err := os.WriteFile(filePath, rawBytes, 0o600); err != nil {
+		return "", fmt.Errorf("failed to write the debug capture file: %w", err)
+	}
+
+	if err := dcs.evict(); err != nil {
+		return filePath, fmt.Errorf("failed to remove old debug capture files: %w", err)
+	}
+
+	return filePath, nil
+}
+
+// remove the oldest captured files until the number and the total size of files are within limits.
+// File names start with the capture time, so the lexical order is the chronological order.
+func (dcs *DebugCaptureStore) evict() error {
+	entries, err := os.ReadDir(dcs.dir)
+	if err != nil {
+		return err
+	}
+
+	type captureFile struct {
+		name string
+		size int64
+	}
+
+	files := make([]captureFile, 0, len(entries))
+	var totalBytes int64
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !isDebugCaptureFileName(entry.Name()) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+
+		files = append(files, captureFile{name: entry.Name(), size: info.Size()})
+		totalBytes += info.Size()
+	}
+
+	slices.SortFunc(files, func(a, b captureFile) int {
+		return strings.Compare(a.name, b.name)
+	})
+
+	for len(files) > 0 && (len(files) > dcs.maxFiles || totalBytes > dcs.maxTotalBytes) {
+		if err := os.Remove(filepath.Join(dcs.dir, files[0].name)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return err
+		}
+
+		totalBytes -= files[0].size
+		files = files[1:]
+	}
+
+	return nil
+}
+
+// check if the file name matches the pattern of captured files, so other files in the directory are never removed.
+func isDebugCaptureFileName(name string) bool {
+	if !strings.HasSuffix(name, debugCaptureFileExt) || len(name) <= len(debugCaptureTimeFormat)+1 {
+		return false
+	}
+
+	_, err := time.Parse(debugCaptureTimeFormat, name[:len(debugCaptureTimeFormat)])
+
+	return err == nil && name[len(debugCaptureTimeFormat)] == '-'
+}
+
+// DebugCapture records the NDC request, upstream requests, raw responses and decode steps of a request lifecycle.
+type DebugCapture struct {
+	ID         string                  `json:"id"`
+	Kind       string                  `json:"kind"`
+	Operation  string                  `json:"operation"`
+	StartedAt  time.Time               `json:"startedAt"`
+	DurationMs int64                   `json:"durationMs"`
+	Request    any                     `json:"request"`
+	Upstream   []*DebugCaptureExchange `json:"upstream"`
+	Result     any                     `json:"result,omitempty"`
+	Error      any                     `json:"error,omitempty"`
+
+	maxBodyBytes int
+	lock         sync.Mutex
+}
+
+// DebugCaptureExchange records an attempt of the upstream request and its raw response.
+// Sensitive headers and query parameters are masked.
+type DebugCaptureExchange struct {
+	Attempt         int                     `json:"attempt"`
+	Method          string                  `json:"method"`
+	URL             string                  `json:"url"`
+	RequestHeaders  map[string]string       `json:"requestHeaders"`
+	RequestBody     string                  `json:"requestBody,omitempty"`
+	StatusCode      int                     `json:"statusCode,omitempty"`
+	ResponseHeaders map[string]string       `json:"responseHeaders,omitempty"`
+	ResponseBody    string                  `json:"responseBody,omitempty"`
+	Truncated       bool                    `json:"truncated,omitempty"`
+	Error           string                  `json:"error,omitempty"`
+	Decode          *DebugCaptureDecodeStep `json:"decode,omitempty"`
+
+	body *debugCaptureBuffer
+}
+
+// DebugCaptureDecodeStep records the decoded result of the response body.
+type DebugCaptureDecodeStep struct {
+	ContentType string `json:"contentType"`
+	Result      any    `json:"result,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// WithDebugCapture returns a child context which carries the debug capture.
+func WithDebugCapture(ctx context.Context, capture *DebugCapture) context.Context {
+	if capture == nil {
+		return ctx
+	}
+
+	return context.WithValue(ctx, debugCaptureContextKey{}, capture)
+}
+
+// GetDebugCapture gets the debug capture from the context. Returns nil if the request isn't captured.
+func GetDebugCapture(ctx context.Context) *DebugCapture {
+	capture, _ := ctx.Value(debugCaptureContextKey{}).(*DebugCapture)
+
+	return capture
+}
+
+// AddExchange records an attempt of the upstream request. The raw body of successful responses is captured while it is read.
+func (dc *DebugCapture) AddExchange(request *RetryableRequest, requestBody []byte, attempt int, resp *http.Response, errorBytes []byte, err error) *DebugCaptureExchange {
+	if dc == nil {
+		return nil
+	}
+
+	exchange := &DebugCaptureExchange{
+		Attempt:        attempt,
+		Method:         strings.ToUpper(request.RawRequest.Method),
+		URL:            maskDebugCaptureURL(&request.URL),
+		RequestHeaders: maskDebugCaptureHeaders(request.Headers),
+	}
+	exchange.RequestBody, _ = dc.truncateBody(requestBody)
+
+	if resp != nil {
+		// the actual request contains credentials which are injected by the security scheme
+		if resp.Request != nil {
+			exchange.URL = maskDebugCaptureURL(resp.Request.URL)
+			exchange.RequestHeaders = maskDebugCaptureHeaders(resp.Request.Header)
+		}
+
+		exchange.StatusCode = resp.StatusCode
+		exchange.ResponseHeaders = maskDebugCaptureHeaders(resp.Header)
+		if errorBytes != nil {
+			exchange.ResponseBody, exchange.Truncated = dc.truncateBody(errorBytes)
+		} else if resp.Body != nil {
+			exchange.body = &debugCaptureBuffer{limit: dc.maxBodyBytes}
+			resp.Body = &debugCaptureReadCloser{ReadCloser: resp.Body, buffer: exchange.body}
+		}
+	}
+
+	if err != nil {
+		exchange.Error = err.Error()
+	}
+
+	dc.lock.Lock()
+	dc.Upstream = append(dc.Upstream, exchange)
+	dc.lock.Unlock()
+
+	return exchange
+}
+
+// SetError records the error of the exchange.
+func (dce *DebugCaptureExchange) SetError(err error) {
+	if dce == nil || err == nil {
+		return
+	}
+
+	dce.Error = err.Error()
+}
+
+// SetDecodeResult records the decoded result or the decoding error of the response body.
+func (dce *DebugCaptureExchange) SetDecodeResult(contentType string, result any, err error) {
+	if dce == nil {
+		return
+	}
+
+	dce.Decode = &DebugCaptureDecodeStep{
+		ContentType: contentType,
+		Result:      result,
+	}
+
+	if err != nil {
+		dce.Decode.Error = err.Error()
+	}
+}
+
+func (dc *DebugCapture) finish(result any, err error) {
+	dc.lock.Lock()
+	defer dc.lock.Unlock()
+
+	dc.DurationMs = time.Since(dc.StartedAt).Milliseconds()
+	dc.Result = result
+
+	if err != nil {
+		var connectorErr *schema.ConnectorError
+		if errors.As(err, &connectorErr) {
+			dc.Error = connectorErr
+		} else {
+			dc.Error = map[string]any{
+				"message": err.Error(),
+			}
+		}
+	}
+
+	for _, exchange := range dc.Upstream {
+		if exchange.body == nil {
+			continue
+		}
+
+		exchange.ResponseBody = exchange.body.String()
+		exchange.Truncated = exchange.body.truncated
+	}
+}
+
+func (dc *DebugCapture) truncateBody(body []byte) (string, bool) {
+	if len(body) <= dc.maxBodyBytes {
+		return string(body), false
+	}
+
+	return string(body[:dc.maxBodyBytes]), true
+}
+
+// debugCaptureBuffer keeps the first bytes of the body up to the limit.
+type debugCaptureBuffer struct {
+	buf       bytes.Buffer
+	limit     int
+	truncated bool
+	lock      sync.Mutex
+}
+
+func (dcb *debugCaptureBuffer) Write(p []byte) {
+	dcb.lock.Lock()
+	defer dcb.lock.Unlock()
+
+	remaining := dcb.limit - dcb.buf.Len()
+	if len(p) > remaining {
+		p = p[:max(remaining, 0)]
+		dcb.truncated = true
+	}
+
+	dcb.buf.Write(p)
+}
+
+func (dcb *debugCaptureBuffer) String() string {
+	dcb.lock.Lock()
+	defer dcb.lock.Unlock()
+
+	return dcb.buf.String()
+}
+
+// debugCaptureReadCloser copies bytes of the response body to the capture buffer while the body is read.
+type debugCaptureReadCloser struct {
+	io.ReadCloser
+
+	buffer *debugCaptureBuffer
+}
+
+// Read implements io.Reader.
+func (dcrc *debugCaptureReadCloser) Read(p []byte) (int, error) {
+	n, err := dcrc.ReadCloser.Read(p)
+	if n > 0 {
+		dcrc.buffer.Write(p[:n])
+	}
+
+	return n, err
+}
+
+func maskDebugCaptureURL(input *url.URL) string {
+	if input == nil {
+		return ""
+	}
+
+	result := cloneURL(input)
+	if result.User != nil {
+		password, hasPassword := result.User.Password()
+		maskedUser := restUtils.MaskString(result.User.Username())
+		if hasPassword {
+			result.User = url.UserPassword(maskedUser, restUtils.MaskString(password))
+		} else {
+			result.User = url.User(maskedUser)
+		}
+	}
+
+	query := result.Query()
+	if len(query) > 0 {
+		for key, values := range query {
+			if !IsSensitiveHeader(key) {
+				continue
+			}
+
+			for i, value := range values {
+				values[i] = restUtils.MaskString(value)
+			}
+		}
+		result.RawQuery = query.Encode()
+	}
+
+	return result.String()
+}
+
+func maskDebugCaptureHeaders(headers http.Header) map[string]string {
+	result := make(map[string]string)
+	for key, values := range headers {
+		value := strings.Join(values, ", ")
+		if isDebugCaptureSensitiveHeader(key) {
+			value = restUtils.MaskString(value)
+		}
+
+		result[key] = value
+	}
+
+	return result
+}
+
+func isDebugCaptureSensitiveHeader(name string) bool {
+	return IsSensitiveHeader(name) || strings.EqualFold(name, "cookie") || strings.EqualFold(name, "set-cookie")
+}
+
+// mask sensitive headers and the trigger header in the forwarded headers argument of the NDC request.
+func maskDebugCaptureRequest(request any, argumentField string, triggerHeader string) any {
+	rawBytes, err := json.Marshal(request)
+	if err != nil {
+		return request
+	}
+
+	var value any
+	if err := json.Unmarshal(rawBytes, &value); err != nil {
+		return request
+	}
+
+	if argumentField == "" {
+		return value
+	}
+
+	return maskDebugCaptureValue(value, argumentField, triggerHeader)
+}
+
+func maskDebugCaptureValue(value any, argumentField string, triggerHeader string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if key == argumentField {
+				v[key] = maskDebugCaptureForwardedHeaders(item, triggerHeader)
+			} else {
+				v[key] = maskDebugCaptureValue(item, argumentField, triggerHeader)
+			}
+		}
+
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = maskDebugCaptureValue(item, argumentField, triggerHeader)
+		}
+
+		return v
+	default:
+		return value
+	}
+}
+
+func maskDebugCaptureForwardedHeaders(value any, triggerHeader string) any {
+	headers, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	// literal arguments of query requests wrap the value, e.g. { "type": "literal", "value": {} }
+	if argType, ok := headers["type"].(string); ok && argType == string(schema.ArgumentTypeLiteral) {
+		headers["value"] = maskDebugCaptureForwardedHeaders(headers["value"], triggerHeader)
+
+		return headers
+	}
+
+	for key, item := range headers {
+		if str, ok := item.(string); ok && (isDebugCaptureSensitiveHeader(key) || strings.EqualFold(key, triggerHeader)) {
+			headers[key] = restUtils.MaskString(str)
+		}
+	}
+
+	return headers
+}
@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"maps"
+	"slices"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// fill omitted arguments and properties of object arguments, e.g. the request body, with default values of the schema.
+// Arguments are copied on write so the input values aren't mutated.
+func (c *RequestBuilder) applyDefaultValues() {
+	var arguments map[string]any
+
+	setArgument := func(key string, value any) {
+		if arguments == nil {
+			arguments = maps.Clone(c.Arguments)
+			if arguments == nil {
+				arguments = make(map[string]any)
+			}
+		}
+
+		arguments[key] = value
+	}
+
+	for key, argument := range c.Operation.Arguments {
+		value, ok := c.Arguments[key]
+		if !ok || value == nil {
+			if argument.HTTP != nil && argument.HTTP.Schema != nil && argument.HTTP.Schema.Default != nil {
+				setArgument(key, argument.HTTP.Schema.Default)
+			}
+
+			continue
+		}
+
+		if result, changed := c.applyDefaultValuesByType(argument.Type, value); changed {
+			setArgument(key, result)
+		}
+	}
+
+	if arguments != nil {
+		c.Arguments = arguments
+	}
+}
+
+// recursively fill omitted fields of object values. Returns true if the value is changed.
+func (c *RequestBuilder) applyDefaultValuesByType(schemaType schema.Type, value any) (any, bool) {
+	if value == nil {
+		return value, false
+	}
+
+	rawType, err := schemaType.InterfaceT()
+	if err != nil {
+		return value, false
+	}
+
+	switch t := rawType.(type) {
+	case *schema.NullableType:
+		return c.applyDefaultValuesByType(t.UnderlyingType, value)
+	case *schema.ArrayType:
+		items, ok := value.([]any)
+		if !ok {
+			return value, false
+		}
+
+		var results []any
+		for i, item := range items {
+			result, changed := c.applyDefaultValuesByType(t.ElementType, item)
+			if !changed {
+				continue
+			}
+
+			if results == nil {
+				results = slices.Clone(items)
+			}
+
+			results[i] = result
+		}
+
+		if results == nil {
+			return value, false
+		}
+
+		return results, true
+	case *schema.NamedType:
+		objectType, ok := c.Schema.ObjectTypes[t.Name]
+		if !ok {
+			return value, false
+		}
+
+		object, ok := value.(map[string]any)
+		if !ok {
+			return value, false
+		}
+
+		return c.applyObjectDefaultValues(objectType, object)
+	default:
+		return value, false
+	}
+}
+
+func (c *RequestBuilder) applyObjectDefaultValues(objectType rest.ObjectType, object map[string]any) (any, bool) {
+	var result map[string]any
+
+	for key, field := range objectType.Fields {
+		value, ok := object[key]
+
+		var newValue any
+		var changed bool
+		if !ok || value == nil {
+			if field.HTTP != nil && field.HTTP.Default != nil {
+				newValue, changed = field.HTTP.Default, true
+			}
+		} else {
+			newValue, changed = c.applyDefaultValuesByType(field.Type, value)
+		}
+
+		if !changed {
+			continue
+		}
+
+		if result == nil {
+			result = maps.Clone(object)
+		}
+
+		result[key] = newValue
+	}
+
+	if result == nil {
+		return object, false
+	}
+
+	return result, true
+}
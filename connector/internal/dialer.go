@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+const defaultDialTimeout = 30 * time.Second
+
+// hostDialer dials connections with static host overrides, custom DNS servers and address family preferences.
+type hostDialer struct {
+	dialer *net.Dialer
+	hosts  map[string]string
+	// the preferred IP version, is one of 4, 6 or 0 if there is no preference
+	preferredIPVersion int
+}
+
+// DialContext implements the dial function of http.Transport.
+func (hd *hostDialer) DialContext(ctx context.Context, network string, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	// host:port overrides take precedence over host overrides
+	if ip, ok := hd.hosts[address]; ok {
+		return hd.dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+	}
+
+	if ip, ok := hd.hosts[host]; ok {
+		return hd.dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+	}
+
+	if hd.preferredIPVersion == 0 || network != "tcp" || net.ParseIP(host) != nil {
+		return hd.dialer.DialContext(ctx, network, address)
+	}
+
+	primaryNetwork, fallbackNetwork := "tcp4", "tcp6"
+	if hd.preferredIPVersion == 6 {
+		primaryNetwork, fallbackNetwork = fallbackNetwork, primaryNetwork
+	}
+
+	conn, primaryErr := hd.dialer.DialContext(ctx, primaryNetwork, address)
+	if primaryErr == nil {
+		return conn, nil
+	}
+
+	conn, fallbackErr := hd.dialer.DialContext(ctx, fallbackNetwork, address)
+	if fallbackErr == nil {
+		return conn, nil
+	}
+
+	return nil, errors.Join(primaryErr, fallbackErr)
+}
+
+// newHTTPClientDialer creates a new HTTP client which dials connections with custom DNS and dialer settings of the server.
+// Returns the base client if there is no custom setting.
+func newHTTPClientDialer(baseClient *http.Client, server rest.ServerConfig) (*http.Client, error) {
+	if server.DNS == nil && server.Dialer == nil {
+		return baseClient, nil
+	}
+
+	hd := &hostDialer{
+		dialer: &net.Dialer{
+			Timeout:   defaultDialTimeout,
+			KeepAlive: defaultDialTimeout,
+		},
+	}
+
+	if server.DNS != nil {
+		if err := hd.applyDNSConfig(server.DNS); err != nil {
+			return nil, fmt.Errorf("dns: %w", err)
+		}
+	}
+
+	if server.Dialer != nil {
+		if err := hd.applyDialerConfig(server.Dialer); err != nil {
+			return nil, fmt.Errorf("dialer: %w", err)
+		}
+	}
+
+	return cloneHTTPClientWithDialer(baseClient, hd), nil
+}
+
+func (hd *hostDialer) applyDNSConfig(dnsConfig *rest.DNSConfig) error {
+	hosts, err := dnsConfig.GetHosts()
+	if err != nil {
+		return err
+	}
+
+	dnsServers, err := dnsConfig.GetServers()
+	if err != nil {
+		return err
+	}
+
+	hd.hosts = hosts
+	if len(dnsServers) > 0 {
+		hd.dialer.Resolver = newDNSResolver(dnsServers)
+	}
+
+	return nil
+}
+
+func (hd *hostDialer) applyDialerConfig(dialerConfig *rest.DialerConfig) error {
+	preferredIPVersion, err := dialerConfig.GetPreferredIPVersion()
+	if err != nil {
+		return err
+	}
+
+	fallbackDelay, err := dialerConfig.GetFallbackDelay()
+	if err != nil {
+		return err
+	}
+
+	hd.preferredIPVersion = preferredIPVersion
+	if fallbackDelay != nil {
+		hd.dialer.FallbackDelay = time.Duration(*fallbackDelay) * time.Millisecond
+	}
+
+	return nil
+}
+
+// newDNSResolver creates a resolver which queries custom DNS servers in order.
+func newDNSResolver(servers []string) *net.Resolver {
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var errs []error
+			dialer := net.Dialer{}
+			for _, server := range servers {
+				conn, err := dialer.DialContext(ctx, network, server)
+				if err == nil {
+					return conn, nil
+				}
+
+				errs = append(errs, err)
+			}
+
+			return nil, fmt.Errorf("failed to connect DNS servers: %w", errors.Join(errs...))
+		},
+	}
+}
+
+func cloneHTTPClientWithDialer(baseClient *http.Client, dialer *hostDialer) *http.Client {
+	baseTransport, ok := baseClient.Transport.(*http.Transport)
+	if !ok {
+		baseTransport, _ = http.DefaultTransport.(*http.Transport)
+	}
+
+	transport := baseTransport.Clone()
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: baseClient.CheckRedirect,
+		Jar:           baseClient.Jar,
+		Timeout:       baseClient.Timeout,
+	}
+}
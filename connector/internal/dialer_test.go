@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestHTTPClientDNS(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.Host))
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	assert.NilError(t, err)
+
+	client, err := newHTTPClientDialer(&http.Client{}, rest.ServerConfig{DNS: &rest.DNSConfig{
+		Hosts: map[string]utils.EnvString{
+			"api.example.local":                      utils.NewEnvStringValue(serverURL.Hostname()),
+			"blue.example.local:" + serverURL.Port(): utils.NewEnvStringValue(serverURL.Hostname()),
+			"blue.example.local":                     utils.NewEnvStringValue("192.0.2.1"),
+		},
+		Servers: []utils.EnvString{utils.NewEnvStringValue("127.0.0.1")},
+	}})
+	assert.NilError(t, err)
+
+	for _, host := range []string{"api.example.local", "blue.example.local"} {
+		resp, err := client.Get(fmt.Sprintf("http://%s:%s/", host, serverURL.Port()))
+		assert.NilError(t, err)
+
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		assert.NilError(t, err)
+		assert.Equal(t, fmt.Sprintf("%s:%s", host, serverURL.Port()), string(body))
+	}
+
+	_, err = newHTTPClientDialer(&http.Client{}, rest.ServerConfig{DNS: &rest.DNSConfig{
+		Hosts: map[string]utils.EnvString{
+			"api.example.local": utils.NewEnvStringValue("invalid"),
+		},
+	}})
+	assert.ErrorContains(t, err, "invalid IP address invalid")
+
+	dnsServers, err := rest.DNSConfig{
+		Servers: []utils.EnvString{utils.NewEnvStringValue("10.0.0.2"), utils.NewEnvStringValue("[::1]:5353")},
+	}.GetServers()
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"10.0.0.2:53", "[::1]:5353"}, dnsServers)
+}
+
+func TestHTTPClientDialer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL)
+	assert.NilError(t, err)
+
+	baseClient := &http.Client{}
+	client, err := newHTTPClientDialer(baseClient, rest.ServerConfig{})
+	assert.NilError(t, err)
+	assert.Equal(t, baseClient, client)
+
+	for _, dialerConfig := range []rest.DialerConfig{
+		{PreferIPv4: utils.ToPtr(utils.NewEnvBoolValue(true)), FallbackDelay: utils.ToPtr(utils.NewEnvIntValue(-1))},
+		{PreferIPv6: utils.ToPtr(utils.NewEnvBoolValue(true)), FallbackDelay: utils.ToPtr(utils.NewEnvIntValue(100))},
+	} {
+		client, err := newHTTPClientDialer(baseClient, rest.ServerConfig{Dialer: &dialerConfig})
+		assert.NilError(t, err)
+
+		// the test server listens on IPv4 only, so IPv6 dials fall back to IPv4
+		resp, err := client.Get(fmt.Sprintf("http://localhost:%s/", serverURL.Port()))
+		assert.NilError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+
+	_, err = newHTTPClientDialer(baseClient, rest.ServerConfig{Dialer: &rest.DialerConfig{
+		PreferIPv4: utils.ToPtr(utils.NewEnvBoolValue(true)),
+		PreferIPv6: utils.ToPtr(utils.NewEnvBoolValue(true)),
+	}})
+	assert.ErrorContains(t, err, "preferIPv4 and preferIPv6 can't be enabled at the same time")
+}
@@ -0,0 +1,189 @@
+package internal
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/theory/jsonpath"
+)
+
+// FieldEncryptor encrypts argument fields before sending requests and decrypts response fields after receiving responses.
+type FieldEncryptor struct {
+	rules []fieldEncryptionRule
+}
+
+type fieldEncryptionRule struct {
+	aead      cipher.AEAD
+	arguments []*jsonpath.Path
+	response  []*jsonpath.Path
+	targets   []regexp.Regexp
+}
+
+// NewFieldEncryptor creates a FieldEncryptor instance from encryption settings.
+// Data keys of rules with KMS settings are decrypted by AWS KMS.
+func NewFieldEncryptor(ctx context.Context, configs []rest.FieldEncryptionConfig) (*FieldEncryptor, error) {
+	result := &FieldEncryptor{}
+	for i, config := range configs {
+		arguments, response, targets, err := config.Validate()
+		if err != nil {
+			return nil, fmt.Errorf("encryption[%d]: %w", i, err)
+		}
+
+		aead, err := newFieldEncryptionAEAD(ctx, config)
+		if err != nil {
+			return nil, fmt.Errorf("encryption[%d]: %w", i, err)
+		}
+
+		result.rules = append(result.rules, fieldEncryptionRule{
+			aead:      aead,
+			arguments: arguments,
+			response:  response,
+			targets:   targets,
+		})
+	}
+
+	return result, nil
+}
+
+func newFieldEncryptionAEAD(ctx context.Context, config rest.FieldEncryptionConfig) (cipher.AEAD, error) {
+	rawKey, err := config.Key.Get()
+	if err != nil {
+		return nil, fmt.Errorf("key: %w", err)
+	}
+
+	if rawKey == "" {
+		return nil, errors.New("key: the encryption key is empty")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(rawKey)
+	if err != nil {
+		return nil, fmt.Errorf("key: the encryption key must be base64-encoded: %w", err)
+	}
+
+	if config.KMS != nil {
+		// the key is the ciphertext blob of the data key
+		key, err = decryptKMSDataKey(ctx, http.DefaultClient, config.KMS, rawKey)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("key: %w", err)
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// EncryptArguments returns a copy of arguments with encrypted fields of the operation.
+func (fe *FieldEncryptor) EncryptArguments(operationName string, arguments map[string]any) (map[string]any, error) {
+	if fe == nil {
+		return arguments, nil
+	}
+
+	for _, rule := range fe.rules {
+		if !rule.isTarget(operationName) {
+			continue
+		}
+
+		for _, jsonPath := range rule.arguments {
+			result, err := rest.UpdateFieldsByJSONPath(arguments, jsonPath.Query().Segments(), false, rule.encrypt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encrypt argument %s: %w", jsonPath.String(), err)
+			}
+
+			arguments, _ = result.(map[string]any)
+		}
+	}
+
+	return arguments, nil
+}
+
+// DecryptResponse decrypts fields in the response body of the operation.
+func (fe *FieldEncryptor) DecryptResponse(operationName string, value any) (any, error) {
+	if fe == nil || value == nil {
+		return value, nil
+	}
+
+	for _, rule := range fe.rules {
+		if !rule.isTarget(operationName) {
+			continue
+		}
+
+		for _, jsonPath := range rule.response {
+			var err error
+			value, err = rest.UpdateFieldsByJSONPath(value, jsonPath.Query().Segments(), false, rule.decrypt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decrypt response field %s: %w", jsonPath.String(), err)
+			}
+		}
+	}
+
+	return value, nil
+}
+
+func (fer fieldEncryptionRule) isTarget(operationName string) bool {
+	return len(fer.targets) == 0 || slices.ContainsFunc(fer.targets, func(expr regexp.Regexp) bool {
+		return expr.MatchString(operationName)
+	})
+}
+
+// encrypt the value with a random nonce. String values are encrypted as raw bytes, other values are encoded to JSON first.
+// The output is the base64-encoded concatenation of the nonce and the sealed data.
+func (fer fieldEncryptionRule) encrypt(value any) (any, error) {
+	var plaintext []byte
+	switch v := value.(type) {
+	case string:
+		plaintext = []byte(v)
+	case *string:
+		plaintext = []byte(*v)
+	default:
+		var err error
+		plaintext, err = json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	nonce := make([]byte, fer.aead.NonceSize(), fer.aead.NonceSize()+len(plaintext)+fer.aead.Overhead())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.EncodeToString(fer.aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+func (fer fieldEncryptionRule) decrypt(value any) (any, error) {
+	encrypted, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected a base64-encoded string, got %T", value)
+	}
+
+	rawBytes, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := fer.aead.NonceSize()
+	if len(rawBytes) < nonceSize {
+		return nil, errors.New("the encrypted value is too short")
+	}
+
+	plaintext, err := fer.aead.Open(nil, rawBytes[:nonceSize], rawBytes[nonceSize:], nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(plaintext), nil
+}
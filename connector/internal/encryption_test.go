@@ -0,0 +1,225 @@
+package internal
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestFieldEncryptor(t *testing.T) {
+	key := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef0123456789abcdef"))
+	t.Setenv("PII_ENCRYPTION_KEY", key)
+
+	encryptor, err := NewFieldEncryptor(context.TODO(), []rest.FieldEncryptionConfig{
+		{
+			Key:       utils.NewEnvStringVariable("PII_ENCRYPTION_KEY"),
+			Arguments: []string{"body.ssn", "body.contacts[*].email", "age"},
+			Response:  []string{"ssn", "$.contacts[*].email"},
+			Targets:   []string{"^createUser$"},
+		},
+	})
+	assert.NilError(t, err)
+
+	arguments := map[string]any{
+		"age": float64(30),
+		"body": map[string]any{
+			"name": "John",
+			"ssn":  "123-45-6789",
+			"contacts": []any{
+				map[string]any{"email": "john@example.com"},
+				map[string]any{"phone": "0123456789"},
+			},
+		},
+	}
+
+	encrypted, err := encryptor.EncryptArguments("createUser", arguments)
+	assert.NilError(t, err)
+
+	// the input arguments aren't mutated
+	assert.Equal(t, "123-45-6789", arguments["body"].(map[string]any)["ssn"])
+
+	body := encrypted["body"].(map[string]any)
+	assert.Equal(t, "John", body["name"])
+	assert.Assert(t, body["ssn"] != "123-45-6789")
+	assert.DeepEqual(t, map[string]any{"phone": "0123456789"}, body["contacts"].([]any)[1])
+
+	decrypted, err := encryptor.DecryptResponse("createUser", body)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]any{
+		"name": "John",
+		"ssn":  "123-45-6789",
+		"contacts": []any{
+			map[string]any{"email": "john@example.com"},
+			map[string]any{"phone": "0123456789"},
+		},
+	}, decrypted)
+
+	// non-string values are encoded to JSON before encryption
+	decryptedAge, err := encryptor.rules[0].decrypt(encrypted["age"])
+	assert.NilError(t, err)
+	assert.Equal(t, "30", decryptedAge)
+
+	// operations that aren't targets are ignored
+	notEncrypted, err := encryptor.EncryptArguments("getUser", arguments)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, arguments, notEncrypted)
+
+	_, err = encryptor.DecryptResponse("createUser", map[string]any{"ssn": "invalid"})
+	assert.ErrorContains(t, err, `failed to decrypt response field $["ssn"]: ssn: illegal base64 data`)
+
+	var nilEncryptor *FieldEncryptor
+	result, err := nilEncryptor.EncryptArguments("createUser", arguments)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, arguments, result)
+}
+
+func TestFieldEncryptorKMS(t *testing.T) {
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+	ciphertextBlob := base64.StdEncoding.EncodeToString([]byte("encrypted-data-key"))
+	kmsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Amz-Target") != "TrentService.Decrypt" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-key/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/kms/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		var input struct {
+			CiphertextBlob    string
+			KeyId             string
+			EncryptionContext map[string]string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil ||
+			input.CiphertextBlob != ciphertextBlob ||
+			input.EncryptionContext["purpose"] != "pii" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"KeyId":     input.KeyId,
+			"Plaintext": base64.StdEncoding.EncodeToString(dataKey),
+		})
+	}))
+	defer kmsServer.Close()
+
+	kmsConfig := &rest.FieldEncryptionKMSConfig{
+		KeyID:             utils.ToPtr(utils.NewEnvStringValue("arn:aws:kms:eu-west-1:123456789012:key/pii")),
+		EncryptionContext: map[string]string{"purpose": "pii"},
+		AccessKeyID:       utils.ToPtr(utils.NewEnvStringValue("test-key")),
+		SecretAccessKey:   utils.ToPtr(utils.NewEnvStringValue("test-secret")),
+		Endpoint:          utils.ToPtr(utils.NewEnvStringValue(kmsServer.URL)),
+	}
+
+	encryptor, err := NewFieldEncryptor(context.TODO(), []rest.FieldEncryptionConfig{
+		{
+			Key:       utils.NewEnvStringValue(ciphertextBlob),
+			KMS:       kmsConfig,
+			Arguments: []string{"body.ssn"},
+		},
+	})
+	assert.NilError(t, err)
+
+	encrypted, err := encryptor.EncryptArguments("createUser", map[string]any{
+		"body": map[string]any{"ssn": "123-45-6789"},
+	})
+	assert.NilError(t, err)
+
+	// values are encrypted with the plaintext data key
+	plainEncryptor, err := NewFieldEncryptor(context.TODO(), []rest.FieldEncryptionConfig{
+		{
+			Key:      utils.NewEnvStringValue(base64.StdEncoding.EncodeToString(dataKey)),
+			Response: []string{"ssn"},
+		},
+	})
+	assert.NilError(t, err)
+	decrypted, err := plainEncryptor.DecryptResponse("createUser", encrypted["body"])
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]any{"ssn": "123-45-6789"}, decrypted)
+
+	invalidConfig := *kmsConfig
+	invalidConfig.EncryptionContext = nil
+	_, err = NewFieldEncryptor(context.TODO(), []rest.FieldEncryptionConfig{
+		{
+			Key:       utils.NewEnvStringValue(ciphertextBlob),
+			KMS:       &invalidConfig,
+			Arguments: []string{"body.ssn"},
+		},
+	})
+	assert.ErrorContains(t, err, "encryption[0]: kms: failed to decrypt the data key, AWS KMS responded with the status 400 Bad Request")
+
+	t.Setenv("AWS_ACCESS_KEY_ID", "")
+	_, err = NewFieldEncryptor(context.TODO(), []rest.FieldEncryptionConfig{
+		{
+			Key:       utils.NewEnvStringValue(ciphertextBlob),
+			KMS:       &rest.FieldEncryptionKMSConfig{},
+			Arguments: []string{"body.ssn"},
+		},
+	})
+	assert.ErrorContains(t, err, "kms: both the access key ID and the secret access key are required")
+}
+
+func TestFieldEncryptorInvalid(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Config   rest.FieldEncryptionConfig
+		ErrorMsg string
+	}{
+		{
+			Name: "invalid_key_size",
+			Config: rest.FieldEncryptionConfig{
+				Key:       utils.NewEnvStringValue(base64.StdEncoding.EncodeToString([]byte("short"))),
+				Arguments: []string{"body.ssn"},
+			},
+			ErrorMsg: "invalid key size",
+		},
+		{
+			Name: "empty_key",
+			Config: rest.FieldEncryptionConfig{
+				Key:       utils.NewEnvStringVariable("UNKNOWN_PII_ENCRYPTION_KEY"),
+				Arguments: []string{"body.ssn"},
+			},
+			ErrorMsg: "key: UNKNOWN_PII_ENCRYPTION_KEY: the environment variable value is empty",
+		},
+		{
+			Name: "no_field",
+			Config: rest.FieldEncryptionConfig{
+				Key: utils.NewEnvStringValue("key"),
+			},
+			ErrorMsg: "require at least one argument or response field",
+		},
+		{
+			Name: "descendant_path",
+			Config: rest.FieldEncryptionConfig{
+				Key:      utils.NewEnvStringValue("key"),
+				Response: []string{"$..ssn"},
+			},
+			ErrorMsg: "response[0]: unsupported json path",
+		},
+		{
+			Name: "root_argument_index",
+			Config: rest.FieldEncryptionConfig{
+				Key:       utils.NewEnvStringValue("key"),
+				Arguments: []string{"[0]"},
+			},
+			ErrorMsg: "arguments[0]: invalid json path. The root selector must be an argument name",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			_, err := NewFieldEncryptor(context.TODO(), []rest.FieldEncryptionConfig{tc.Config})
+			assert.ErrorContains(t, err, tc.ErrorMsg)
+		})
+	}
+}
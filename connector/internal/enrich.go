@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"golang.org/x/sync/errgroup"
+)
+
+const defaultEnrichConcurrency = 5
+
+// EnrichFetchFunc executes a function with arguments and returns the result.
+type EnrichFetchFunc func(ctx context.Context, operationName string, arguments map[string]any) (any, error)
+
+// EnrichResponse calls other functions for items of the response and embeds results into items.
+// Keys are deduplicated so each function is called once per distinct key.
+func EnrichResponse(ctx context.Context, result any, settings []rest.ResponseEnrichSettings, fetch EnrichFetchFunc) (any, error) {
+	var items []map[string]any
+	switch value := result.(type) {
+	case map[string]any:
+		items = append(items, value)
+	case []any:
+		for _, item := range value {
+			if itemMap, ok := item.(map[string]any); ok {
+				items = append(items, itemMap)
+			}
+		}
+	}
+
+	if len(items) == 0 {
+		return result, nil
+	}
+
+	for _, enrich := range settings {
+		if err := enrichItems(ctx, items, enrich, fetch); err != nil {
+			return nil, fmt.Errorf("failed to enrich field %s with %s: %w", enrich.Field, enrich.Operation, err)
+		}
+	}
+
+	return result, nil
+}
+
+func enrichItems(ctx context.Context, items []map[string]any, enrich rest.ResponseEnrichSettings, fetch EnrichFetchFunc) error {
+	var keys []any
+	keyIDs := make(map[string]bool)
+	for _, item := range items {
+		key := item[enrich.KeyField]
+		if key == nil {
+			continue
+		}
+
+		keyID := fmt.Sprint(key)
+		if !keyIDs[keyID] {
+			keyIDs[keyID] = true
+			keys = append(keys, key)
+		}
+	}
+
+	concurrency := int(enrich.Concurrency)
+	if concurrency <= 0 {
+		concurrency = defaultEnrichConcurrency
+	}
+
+	var lock sync.Mutex
+	results := make(map[string]any)
+	eg, ctx := errgroup.WithContext(ctx)
+	eg.SetLimit(concurrency)
+
+	if enrich.BatchSize == 0 {
+		for _, key := range keys {
+			eg.Go(func() error {
+				result, err := fetch(ctx, enrich.Operation, map[string]any{enrich.Argument: key})
+				if err != nil {
+					return err
+				}
+
+				lock.Lock()
+				results[fmt.Sprint(key)] = result
+				lock.Unlock()
+
+				return nil
+			})
+		}
+	} else {
+		resultKeyField := enrich.ResultKeyField
+		if resultKeyField == "" {
+			resultKeyField = enrich.KeyField
+		}
+
+		for start := 0; start < len(keys); start += int(enrich.BatchSize) {
+			batch := keys[start:min(start+int(enrich.BatchSize), len(keys))]
+			eg.Go(func() error {
+				result, err := fetch(ctx, enrich.Operation, map[string]any{enrich.Argument: batch})
+				if err != nil {
+					return err
+				}
+
+				resultItems, ok := result.([]any)
+				if !ok && result != nil {
+					return fmt.Errorf("expected an array result of the batch, got %T", result)
+				}
+
+				lock.Lock()
+				defer lock.Unlock()
+
+				for _, resultItem := range resultItems {
+					if itemMap, ok := resultItem.(map[string]any); ok && itemMap[resultKeyField] != nil {
+						results[fmt.Sprint(itemMap[resultKeyField])] = itemMap
+					}
+				}
+
+				return nil
+			})
+		}
+	}
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if key := item[enrich.KeyField]; key != nil {
+			item[enrich.Field] = results[fmt.Sprint(key)]
+		} else {
+			item[enrich.Field] = nil
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestEnrichResponse(t *testing.T) {
+	newItems := func() []any {
+		return []any{
+			map[string]any{"id": 1, "ownerId": int64(10)},
+			map[string]any{"id": 2, "ownerId": int64(20)},
+			map[string]any{"id": 3, "ownerId": int64(10)},
+			map[string]any{"id": 4},
+		}
+	}
+
+	owners := map[int64]any{
+		10: map[string]any{"id": float64(10), "name": "Alice"},
+		20: map[string]any{"id": float64(20), "name": "Bob"},
+	}
+
+	t.Run("per_item", func(t *testing.T) {
+		var callCount atomic.Int32
+		result, err := EnrichResponse(context.Background(), newItems(), []rest.ResponseEnrichSettings{
+			{
+				Field:       "owner",
+				Operation:   "getOwnerById",
+				KeyField:    "ownerId",
+				Argument:    "id",
+				Concurrency: 1,
+			},
+		}, func(ctx context.Context, operationName string, arguments map[string]any) (any, error) {
+			callCount.Add(1)
+			assert.Equal(t, "getOwnerById", operationName)
+
+			return owners[arguments["id"].(int64)], nil
+		})
+		assert.NilError(t, err)
+		// keys are deduplicated
+		assert.Equal(t, int32(2), callCount.Load())
+		assert.DeepEqual(t, []any{
+			map[string]any{"id": 1, "ownerId": int64(10), "owner": owners[10]},
+			map[string]any{"id": 2, "ownerId": int64(20), "owner": owners[20]},
+			map[string]any{"id": 3, "ownerId": int64(10), "owner": owners[10]},
+			map[string]any{"id": 4, "owner": nil},
+		}, result)
+	})
+
+	t.Run("batch", func(t *testing.T) {
+		var callCount atomic.Int32
+		result, err := EnrichResponse(context.Background(), newItems(), []rest.ResponseEnrichSettings{
+			{
+				Field:          "owner",
+				Operation:      "findOwners",
+				KeyField:       "ownerId",
+				Argument:       "ids",
+				BatchSize:      1,
+				ResultKeyField: "id",
+			},
+		}, func(ctx context.Context, operationName string, arguments map[string]any) (any, error) {
+			callCount.Add(1)
+			ids := arguments["ids"].([]any)
+			assert.Equal(t, 1, len(ids))
+
+			return []any{owners[ids[0].(int64)]}, nil
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, int32(2), callCount.Load())
+		assert.DeepEqual(t, map[string]any{"id": 2, "ownerId": int64(20), "owner": owners[20]}, result.([]any)[1])
+	})
+
+	t.Run("error", func(t *testing.T) {
+		_, err := EnrichResponse(context.Background(), newItems(), []rest.ResponseEnrichSettings{
+			{
+				Field:     "owner",
+				Operation: "getOwnerById",
+				KeyField:  "ownerId",
+				Argument:  "id",
+			},
+		}, func(ctx context.Context, operationName string, arguments map[string]any) (any, error) {
+			return nil, errors.New("connection refused")
+		})
+		assert.ErrorContains(t, err, "failed to enrich field owner with getOwnerById: connection refused")
+	})
+}
@@ -0,0 +1,182 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+// unwrapResponseEnvelope decodes the JSON body and unwraps the standard envelope into flat objects,
+// so the result is decoded with the flat result type of the operation.
+func unwrapResponseEnvelope(body io.Reader, envelope rest.ResponseEnvelope) (io.Reader, error) {
+	var value any
+	if err := json.NewDecoder(body).Decode(&value); err != nil {
+		if err == io.EOF {
+			return bytes.NewReader(nil), nil
+		}
+
+		return nil, err
+	}
+
+	switch envelope {
+	case rest.ResponseEnvelopeJSONAPI:
+		value = unwrapJSONAPIDocument(value)
+	case rest.ResponseEnvelopeHAL:
+		value = unwrapHALResource(value)
+	default:
+	}
+
+	rawBytes, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return bytes.NewReader(rawBytes), nil
+}
+
+// unwrapJSONAPIDocument returns the primary data of the JSON:API document with flattened resources.
+// Related resources are embedded if they are included in the document, otherwise resource identifiers are returned.
+// The document is returned as it is if it doesn't contain primary data, e.g. error documents.
+func unwrapJSONAPIDocument(value any) any {
+	document, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	data, ok := document["data"]
+	if !ok {
+		return value
+	}
+
+	included := map[string]map[string]any{}
+	if items, ok := document["included"].([]any); ok {
+		for _, item := range items {
+			if resource, ok := item.(map[string]any); ok {
+				included[jsonAPIResourceKey(resource)] = resource
+			}
+		}
+	}
+
+	switch d := data.(type) {
+	case map[string]any:
+		return flattenJSONAPIResource(d, included, true)
+	case []any:
+		results := make([]any, len(d))
+		for i, item := range d {
+			if resource, ok := item.(map[string]any); ok {
+				results[i] = flattenJSONAPIResource(resource, included, true)
+			} else {
+				results[i] = item
+			}
+		}
+
+		return results
+	default:
+		return data
+	}
+}
+
+// flattenJSONAPIResource merges attributes and relationships of the resource object into a flat object.
+// Included resources are embedded one level deep to avoid cycles.
+func flattenJSONAPIResource(resource map[string]any, included map[string]map[string]any, embed bool) map[string]any {
+	result := make(map[string]any)
+	for key, value := range resource {
+		switch key {
+		case "attributes", "relationships", "links", "meta":
+		default:
+			result[key] = value
+		}
+	}
+
+	if attributes, ok := resource["attributes"].(map[string]any); ok {
+		for key, value := range attributes {
+			if _, exists := result[key]; !exists {
+				result[key] = value
+			}
+		}
+	}
+
+	relationships, ok := resource["relationships"].(map[string]any)
+	if !ok {
+		return result
+	}
+
+	for name, rawRelationship := range relationships {
+		if _, exists := result[name]; exists {
+			continue
+		}
+
+		relationship, ok := rawRelationship.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		data, ok := relationship["data"]
+		if !ok {
+			continue
+		}
+
+		switch d := data.(type) {
+		case map[string]any:
+			result[name] = resolveJSONAPIIdentifier(d, included, embed)
+		case []any:
+			items := make([]any, len(d))
+			for i, item := range d {
+				if identifier, ok := item.(map[string]any); ok {
+					items[i] = resolveJSONAPIIdentifier(identifier, included, embed)
+				} else {
+					items[i] = item
+				}
+			}
+			result[name] = items
+		default:
+			result[name] = data
+		}
+	}
+
+	return result
+}
+
+func resolveJSONAPIIdentifier(identifier map[string]any, included map[string]map[string]any, embed bool) map[string]any {
+	if embed {
+		if resource, ok := included[jsonAPIResourceKey(identifier)]; ok {
+			return flattenJSONAPIResource(resource, included, false)
+		}
+	}
+
+	return flattenJSONAPIResource(identifier, included, false)
+}
+
+func jsonAPIResourceKey(resource map[string]any) string {
+	return fmt.Sprintf("%v/%v", resource["type"], resource["id"])
+}
+
+// unwrapHALResource removes links of the HAL resource and lifts embedded resources into the object.
+// Fields of the resource take precedence over embedded resources with the same name.
+func unwrapHALResource(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		embedded, _ := v["_embedded"].(map[string]any)
+		delete(v, "_links")
+		delete(v, "_embedded")
+
+		for key, item := range embedded {
+			if _, exists := v[key]; !exists {
+				v[key] = unwrapHALResource(item)
+			}
+		}
+
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = unwrapHALResource(item)
+		}
+
+		return v
+	default:
+		return value
+	}
+}
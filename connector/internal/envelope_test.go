@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestUnwrapResponseEnvelope(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Envelope rest.ResponseEnvelope
+		Body     string
+		Expected string
+	}{
+		{
+			Name:     "jsonapi_collection",
+			Envelope: rest.ResponseEnvelopeJSONAPI,
+			Body: `{
+				"data": [{
+					"type": "articles",
+					"id": "1",
+					"attributes": { "title": "JSON:API paints my bikeshed!" },
+					"relationships": {
+						"author": { "data": { "type": "people", "id": "9" } },
+						"comments": { "data": [{ "type": "comments", "id": "5" }] }
+					},
+					"links": { "self": "http://example.com/articles/1" }
+				}],
+				"included": [{
+					"type": "people",
+					"id": "9",
+					"attributes": { "name": "Dan" },
+					"relationships": { "articles": { "data": [{ "type": "articles", "id": "1" }] } }
+				}]
+			}`,
+			Expected: `[{
+				"type": "articles",
+				"id": "1",
+				"title": "JSON:API paints my bikeshed!",
+				"author": { "type": "people", "id": "9", "name": "Dan", "articles": [{ "type": "articles", "id": "1" }] },
+				"comments": [{ "type": "comments", "id": "5" }]
+			}]`,
+		},
+		{
+			Name:     "jsonapi_single",
+			Envelope: rest.ResponseEnvelopeJSONAPI,
+			Body:     `{ "data": { "type": "people", "id": "9", "attributes": { "name": "Dan" } }, "meta": { "total": 1 } }`,
+			Expected: `{ "type": "people", "id": "9", "name": "Dan" }`,
+		},
+		{
+			Name:     "jsonapi_errors",
+			Envelope: rest.ResponseEnvelopeJSONAPI,
+			Body:     `{ "errors": [{ "status": "404" }] }`,
+			Expected: `{ "errors": [{ "status": "404" }] }`,
+		},
+		{
+			Name:     "hal",
+			Envelope: rest.ResponseEnvelopeHAL,
+			Body: `{
+				"id": 1,
+				"status": "shipped",
+				"_links": { "self": { "href": "/orders/1" } },
+				"_embedded": {
+					"items": [{ "sku": "A1", "_links": { "self": { "href": "/items/A1" } } }],
+					"status": "ignored"
+				}
+			}`,
+			Expected: `{ "id": 1, "status": "shipped", "items": [{ "sku": "A1" }] }`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			reader, err := unwrapResponseEnvelope(strings.NewReader(tc.Body), tc.Envelope)
+			assert.NilError(t, err)
+
+			rawBytes, err := io.ReadAll(reader)
+			assert.NilError(t, err)
+
+			var expected, result any
+			assert.NilError(t, json.Unmarshal([]byte(tc.Expected), &expected))
+			assert.NilError(t, json.Unmarshal(rawBytes, &result))
+			assert.DeepEqual(t, expected, result)
+		})
+	}
+
+	reader, err := unwrapResponseEnvelope(strings.NewReader(""), rest.ResponseEnvelopeHAL)
+	assert.NilError(t, err)
+	rawBytes, err := io.ReadAll(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(rawBytes))
+}
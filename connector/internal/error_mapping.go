@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// mapConnectorError converts the upstream error to the NDC error with the status code of the error mapping settings.
+func (client *HTTPClient) mapConnectorError(err *UpstreamError) *schema.ConnectorError {
+	mapping := client.manager.config.ErrorMapping
+	if mapping == nil || err.StatusCode == 0 {
+		return err.ConnectorError()
+	}
+
+	var connectorErr *schema.ConnectorError
+	if errors.As(err.Cause, &connectorErr) {
+		return connectorErr
+	}
+
+	return schema.NewConnectorError(mapping.StatusCode(err.StatusCode, err.NDCStatusCode()), err.Message, err.Details)
+}
+
+// isNullErrorResponse checks if the error response of the function is returned as a null result
+// with the 404AsNull setting of the operation or the error mapping settings.
+func (client *HTTPClient) isNullErrorResponse(statusCode int) bool {
+	if statusCode == 0 || !client.requests.IsFunction() {
+		return false
+	}
+
+	method := ""
+	if client.requests.Operation != nil && client.requests.Operation.Request != nil {
+		if statusCode == http.StatusNotFound && client.requests.Operation.Request.Response.NotFoundAsNull {
+			return true
+		}
+
+		method = client.requests.Operation.Request.Method
+	}
+
+	mapping := client.manager.config.ErrorMapping
+
+	return mapping != nil && mapping.IsNull(statusCode, method)
+}
+
+// evalNullErrorResponse discards the error response and returns a null result.
+// Response headers are still forwarded if the header forwarding is enabled.
+func (client *HTTPClient) evalNullErrorResponse(resp *http.Response, selection schema.NestedField) (any, http.Header, *schema.ConnectorError) {
+	if resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+
+	output := client.createHeaderForwardingResponse(nil, resp.Header)
+	if output == nil || len(selection) == 0 {
+		return output, resp.Header, nil
+	}
+
+	output, err := utils.EvalNestedColumnFields(selection, output)
+	if err != nil {
+		return nil, nil, schema.InternalServerError(err.Error(), nil)
+	}
+
+	return output, resp.Header, nil
+}
@@ -0,0 +1,157 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"syscall"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// ErrorCategory classifies failures of requests to upstream services.
+type ErrorCategory string
+
+const (
+	// ErrorCategoryNetwork represents failures to connect or transfer data to the upstream service.
+	ErrorCategoryNetwork ErrorCategory = "network"
+	// ErrorCategoryTimeout represents requests which exceed the timeout.
+	ErrorCategoryTimeout ErrorCategory = "timeout"
+	// ErrorCategoryAuth represents failures to authenticate to the upstream service,
+	// including 401 and 403 responses and failures to inject credentials.
+	ErrorCategoryAuth ErrorCategory = "auth"
+	// ErrorCategoryClient represents 4xx responses of the upstream service.
+	ErrorCategoryClient ErrorCategory = "client"
+	// ErrorCategoryServer represents 5xx responses of the upstream service.
+	ErrorCategoryServer ErrorCategory = "server"
+	// ErrorCategoryDecode represents failures to read or decode response bodies.
+	ErrorCategoryDecode ErrorCategory = "decode"
+	// ErrorCategoryInternal represents failures of the connector itself, e.g. compressing the request body.
+	ErrorCategoryInternal ErrorCategory = "internal"
+)
+
+// UpstreamError represents a classified failure of a request to an upstream service.
+// The category drives retry decisions, metric labels and NDC error codes.
+type UpstreamError struct {
+	Category ErrorCategory
+	// The HTTP status code of the upstream response. Zero if the response isn't received
+	StatusCode int
+	Message    string
+	Details    map[string]any
+	Cause      error
+}
+
+// NewUpstreamError creates a new UpstreamError instance.
+func NewUpstreamError(category ErrorCategory, message string, details map[string]any) *UpstreamError {
+	return &UpstreamError{
+		Category: category,
+		Message:  message,
+		Details:  details,
+	}
+}
+
+// newHTTPStatusError classifies the error response of the upstream service by the status code.
+func newHTTPStatusError(statusCode int, status string, details map[string]any) *UpstreamError {
+	category := ErrorCategoryServer
+	switch {
+	case statusCode == http.StatusUnauthorized || statusCode == http.StatusForbidden:
+		category = ErrorCategoryAuth
+	case statusCode == http.StatusRequestTimeout || statusCode == http.StatusGatewayTimeout:
+		category = ErrorCategoryTimeout
+	case statusCode < 500:
+		category = ErrorCategoryClient
+	}
+
+	return &UpstreamError{
+		Category:   category,
+		StatusCode: statusCode,
+		Message:    status,
+		Details:    details,
+	}
+}
+
+// classifyRequestError classifies the error of sending a request which doesn't receive any response.
+func classifyRequestError(err error) *UpstreamError {
+	var upstreamErr *UpstreamError
+	if errors.As(err, &upstreamErr) {
+		return upstreamErr
+	}
+
+	category := ErrorCategoryInternal
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		category = ErrorCategoryTimeout
+	// the connection is closed or reset by the upstream service before the response is received
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, syscall.ECONNRESET):
+		category = ErrorCategoryNetwork
+	case errors.As(err, &netErr):
+		category = ErrorCategoryNetwork
+		if netErr.Timeout() {
+			category = ErrorCategoryTimeout
+		}
+	}
+
+	return &UpstreamError{
+		Category: category,
+		Message:  err.Error(),
+		Cause:    err,
+	}
+}
+
+// Error implements the error interface.
+func (ue *UpstreamError) Error() string {
+	return ue.Message
+}
+
+// Unwrap returns the underlying error.
+func (ue *UpstreamError) Unwrap() error {
+	return ue.Cause
+}
+
+// Retryable checks if the request can be retried with the retry policy.
+// Error responses are retried if the status code is listed in the policy.
+// Network failures and timeouts without any response are always retried.
+func (ue *UpstreamError) Retryable(policy rest.RetryPolicy) bool {
+	if ue.StatusCode > 0 {
+		return slices.Contains(policy.HTTPStatus, ue.StatusCode)
+	}
+
+	return ue.Category == ErrorCategoryNetwork || ue.Category == ErrorCategoryTimeout
+}
+
+// ConnectorError converts the error to the NDC error.
+// Upstream 4xx errors are unprocessable content so the engine doesn't treat them as connector failures.
+func (ue *UpstreamError) ConnectorError() *schema.ConnectorError {
+	var connectorErr *schema.ConnectorError
+	if errors.As(ue.Cause, &connectorErr) {
+		return connectorErr
+	}
+
+	return schema.NewConnectorError(ue.NDCStatusCode(), ue.Message, ue.Details)
+}
+
+// NDCStatusCode returns the status code of the NDC error response.
+func (ue *UpstreamError) NDCStatusCode() int {
+	switch {
+	case ue.StatusCode >= 500:
+		return ue.StatusCode
+	case ue.StatusCode >= 400:
+		return http.StatusUnprocessableEntity
+	}
+
+	switch ue.Category {
+	case ErrorCategoryNetwork:
+		return http.StatusBadGateway
+	case ErrorCategoryTimeout:
+		return http.StatusGatewayTimeout
+	case ErrorCategoryClient, ErrorCategoryAuth:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
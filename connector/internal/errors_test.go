@@ -0,0 +1,353 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"syscall"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestUpstreamError(t *testing.T) {
+	policy := rest.RetryPolicy{
+		HTTPStatus: defaultRetryHTTPStatus,
+	}
+
+	testCases := []struct {
+		Name          string
+		Error         *UpstreamError
+		Category      ErrorCategory
+		Retryable     bool
+		NDCStatusCode int
+	}{
+		{
+			Name:          "unauthorized",
+			Error:         newHTTPStatusError(http.StatusUnauthorized, "401 Unauthorized", nil),
+			Category:      ErrorCategoryAuth,
+			NDCStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:          "not_found",
+			Error:         newHTTPStatusError(http.StatusNotFound, "404 Not Found", nil),
+			Category:      ErrorCategoryClient,
+			NDCStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:          "too_many_requests",
+			Error:         newHTTPStatusError(http.StatusTooManyRequests, "429 Too Many Requests", nil),
+			Category:      ErrorCategoryClient,
+			Retryable:     true,
+			NDCStatusCode: http.StatusUnprocessableEntity,
+		},
+		{
+			Name:          "bad_gateway",
+			Error:         newHTTPStatusError(http.StatusBadGateway, "502 Bad Gateway", nil),
+			Category:      ErrorCategoryServer,
+			Retryable:     true,
+			NDCStatusCode: http.StatusBadGateway,
+		},
+		{
+			Name:          "gateway_timeout",
+			Error:         newHTTPStatusError(http.StatusGatewayTimeout, "504 Gateway Timeout", nil),
+			Category:      ErrorCategoryTimeout,
+			NDCStatusCode: http.StatusGatewayTimeout,
+		},
+		{
+			Name:          "network",
+			Error:         classifyRequestError(&net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}),
+			Category:      ErrorCategoryNetwork,
+			Retryable:     true,
+			NDCStatusCode: http.StatusBadGateway,
+		},
+		{
+			Name:          "connection_reset",
+			Error:         classifyRequestError(fmt.Errorf("failed to send the request: %w", syscall.ECONNRESET)),
+			Category:      ErrorCategoryNetwork,
+			Retryable:     true,
+			NDCStatusCode: http.StatusBadGateway,
+		},
+		{
+			Name:          "connection_closed",
+			Error:         classifyRequestError(fmt.Errorf("failed to read the response: %w", io.ErrUnexpectedEOF)),
+			Category:      ErrorCategoryNetwork,
+			Retryable:     true,
+			NDCStatusCode: http.StatusBadGateway,
+		},
+		{
+			Name:          "timeout",
+			Error:         classifyRequestError(fmt.Errorf("failed to send the request: %w", context.DeadlineExceeded)),
+			Category:      ErrorCategoryTimeout,
+			Retryable:     true,
+			NDCStatusCode: http.StatusGatewayTimeout,
+		},
+		{
+			Name:          "canceled",
+			Error:         classifyRequestError(context.Canceled),
+			Category:      ErrorCategoryInternal,
+			NDCStatusCode: http.StatusInternalServerError,
+		},
+		{
+			Name:          "decode",
+			Error:         NewUpstreamError(ErrorCategoryDecode, "unexpected EOF", nil),
+			Category:      ErrorCategoryDecode,
+			NDCStatusCode: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.Equal(t, tc.Category, tc.Error.Category)
+			assert.Equal(t, tc.Retryable, tc.Error.Retryable(policy))
+			assert.Equal(t, tc.NDCStatusCode, tc.Error.NDCStatusCode())
+			assert.Equal(t, tc.NDCStatusCode, tc.Error.ConnectorError().StatusCode())
+		})
+	}
+
+	t.Run("wrapped", func(t *testing.T) {
+		authErr := &UpstreamError{
+			Category: ErrorCategoryAuth,
+			Message:  "failed to fetch the token",
+		}
+		assert.Equal(t, authErr, classifyRequestError(fmt.Errorf("request failed: %w", authErr)))
+
+		connectorErr := schema.NewConnectorError(http.StatusConflict, "conflict", nil)
+		assert.Equal(t, connectorErr, classifyRequestError(connectorErr).ConnectorError())
+	})
+}
+
+func TestTypedErrorDetails(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pets/1", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"code": "PET_NOT_FOUND", "message": "pet 1 not found", "retryable": false}`))
+	})
+	mux.HandleFunc("/pets/2", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`["unavailable"]`))
+	})
+	mux.HandleFunc("/pets/3", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`bad request`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ndcSchema := &rest.NDCHttpSchema{
+		ObjectTypes: map[string]rest.ObjectType{
+			"Error": {
+				Fields: map[string]rest.ObjectField{
+					"code": {
+						ObjectField: schema.ObjectField{
+							Type: schema.NewNamedType("String").Encode(),
+						},
+					},
+					"message": {
+						ObjectField: schema.ObjectField{
+							Type: schema.NewNullableNamedType("String").Encode(),
+						},
+					},
+				},
+			},
+		},
+		ScalarTypes: schema.SchemaResponseScalarTypes{
+			"String": *schema.NewScalarType(),
+		},
+	}
+	rawRequest := &rest.Request{
+		Method: "get",
+		Response: rest.Response{
+			ContentType: rest.ContentTypeJSON,
+			Errors: map[string]rest.ErrorResponse{
+				"4XX": {
+					ContentType: rest.ContentTypeJSON,
+					Type:        schema.NewNamedType("Error").Encode(),
+				},
+				"default": {
+					ContentType: rest.ContentTypeJSON,
+					Type:        schema.NewNamedType("Error").Encode(),
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		Name       string
+		Path       string
+		TypedError any
+	}{
+		{
+			Name: "status_class",
+			Path: "/pets/1",
+			TypedError: map[string]any{
+				"code":    "PET_NOT_FOUND",
+				"message": "pet 1 not found",
+			},
+		},
+		{
+			Name: "invalid_body",
+			Path: "/pets/2",
+		},
+		{
+			Name: "text",
+			Path: "/pets/3",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			um := NewUpstreamManager(http.DefaultClient, &configuration.Configuration{})
+			requestURL, err := url.Parse(server.URL + tc.Path)
+			assert.NilError(t, err)
+
+			results := &RequestBuilderResults{
+				OperationName: "getPet",
+				Operation: &rest.OperationInfo{
+					Request:    rawRequest,
+					ResultType: schema.NewNamedType("Error").Encode(),
+				},
+				Schema: &configuration.NDCHttpRuntimeSchema{
+					NDCHttpSchema: ndcSchema,
+				},
+				Requests: []*RetryableRequest{
+					{
+						URL:        *requestURL,
+						Headers:    http.Header{},
+						RawRequest: rawRequest,
+					},
+				},
+				HTTPOptions: &HTTPOptions{},
+			}
+
+			_, _, err = um.CreateHTTPClient(results).Send(context.TODO(), nil)
+			var connectorErr *schema.ConnectorError
+			assert.Assert(t, errors.As(err, &connectorErr))
+			assert.Assert(t, connectorErr.Details["error"] != nil)
+
+			typedError, ok := connectorErr.Details["typedError"]
+			if tc.TypedError == nil {
+				assert.Assert(t, !ok, "%v", typedError)
+
+				return
+			}
+
+			assert.DeepEqual(t, tc.TypedError, typedError)
+		})
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/pets/404":
+			w.WriteHeader(http.StatusNotFound)
+		case "/pets/401":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "/pets/409":
+			w.WriteHeader(http.StatusConflict)
+		default:
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	errorMapping := &configuration.ErrorMappingSettings{
+		PassThrough:    true,
+		NotFoundAsNull: true,
+		Statuses: map[string]configuration.ErrorMappingRule{
+			"409": {Status: http.StatusBadRequest},
+			"5XX": {Status: http.StatusServiceUnavailable},
+		},
+	}
+
+	testCases := []struct {
+		Name         string
+		Path         string
+		Method       string
+		Procedure    bool
+		NotFoundNull bool
+		ErrorMapping *configuration.ErrorMappingSettings
+		StatusCode   int
+	}{
+		{Name: "default", Path: "/pets/401", Method: "get", StatusCode: http.StatusUnprocessableEntity},
+		{Name: "operation_not_found_null", Path: "/pets/404", Method: "post", NotFoundNull: true},
+		{Name: "operation_not_found_procedure", Path: "/pets/404", Method: "post", Procedure: true, NotFoundNull: true, StatusCode: http.StatusUnprocessableEntity},
+		{Name: "not_found_null", Path: "/pets/404", Method: "get", ErrorMapping: errorMapping},
+		{Name: "not_found_post", Path: "/pets/404", Method: "post", ErrorMapping: errorMapping, StatusCode: http.StatusNotFound},
+		{Name: "not_found_procedure", Path: "/pets/404", Method: "get", Procedure: true, ErrorMapping: errorMapping, StatusCode: http.StatusNotFound},
+		{Name: "pass_through", Path: "/pets/401", Method: "get", ErrorMapping: errorMapping, StatusCode: http.StatusUnauthorized},
+		{Name: "status_rule", Path: "/pets/409", Method: "get", ErrorMapping: errorMapping, StatusCode: http.StatusBadRequest},
+		{Name: "status_range", Path: "/pets/502", Method: "get", ErrorMapping: errorMapping, StatusCode: http.StatusServiceUnavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			um := NewUpstreamManager(http.DefaultClient, &configuration.Configuration{
+				ErrorMapping: tc.ErrorMapping,
+			})
+			requestURL, err := url.Parse(server.URL + tc.Path)
+			assert.NilError(t, err)
+
+			rawRequest := &rest.Request{
+				Method: tc.Method,
+				Response: rest.Response{
+					ContentType:    rest.ContentTypeJSON,
+					NotFoundAsNull: tc.NotFoundNull,
+				},
+			}
+			operation := rest.OperationInfo{
+				Request:    rawRequest,
+				ResultType: schema.NewNullableNamedType("Pet").Encode(),
+			}
+			ndcSchema := &rest.NDCHttpSchema{
+				Functions:  map[string]rest.OperationInfo{},
+				Procedures: map[string]rest.OperationInfo{},
+			}
+			if tc.Procedure {
+				ndcSchema.Procedures["getPet"] = operation
+			} else {
+				ndcSchema.Functions["getPet"] = operation
+			}
+
+			results := &RequestBuilderResults{
+				OperationName: "getPet",
+				Operation:     &operation,
+				Schema: &configuration.NDCHttpRuntimeSchema{
+					NDCHttpSchema: ndcSchema,
+				},
+				Requests: []*RetryableRequest{
+					{
+						URL:        *requestURL,
+						Headers:    http.Header{},
+						RawRequest: rawRequest,
+					},
+				},
+				HTTPOptions: &HTTPOptions{},
+			}
+
+			result, _, err := um.CreateHTTPClient(results).Send(context.TODO(), nil)
+			if tc.StatusCode == 0 {
+				assert.NilError(t, err)
+				assert.Assert(t, result == nil)
+
+				return
+			}
+
+			var connectorErr *schema.ConnectorError
+			assert.Assert(t, errors.As(err, &connectorErr))
+			assert.Equal(t, tc.StatusCode, connectorErr.StatusCode())
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package internal
+
+import (
+	"encoding/base64"
+	"fmt"
+	"hash/fnv"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+const (
+	fakeArrayLength = 2
+	// nullable fields and arrays are empty at the max depth to stop recursive types
+	fakeMaxDepth = 4
+)
+
+var (
+	fakeWords     = []string{"lorem", "ipsum", "dolor", "sit", "amet", "consectetur", "adipiscing", "elit", "sed", "tempor"}
+	fakeStartTime = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// GenerateFakeData generates fake data which conforms to the result type of the operation.
+// The random generator is seeded with the operation name so the response is stable across requests.
+func GenerateFakeData(httpSchema *rest.NDCHttpSchema, operationName string, resultType schema.Type) (any, error) {
+	seed := fnv.New64a()
+	_, _ = seed.Write([]byte(operationName))
+
+	generator := &fakeDataGenerator{
+		schema: httpSchema,
+		rand:   rand.New(rand.NewPCG(seed.Sum64(), 0)), //nolint:gosec
+	}
+
+	return generator.generate(resultType, nil, 0)
+}
+
+type fakeDataGenerator struct {
+	schema *rest.NDCHttpSchema
+	rand   *rand.Rand
+}
+
+func (g *fakeDataGenerator) generate(resultType schema.Type, typeSchema *rest.TypeSchema, depth int) (any, error) {
+	ty, err := resultType.InterfaceT()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := ty.(type) {
+	case *schema.NullableType:
+		if depth >= fakeMaxDepth {
+			return nil, nil
+		}
+
+		return g.generate(t.UnderlyingType, typeSchema, depth)
+	case *schema.ArrayType:
+		if depth >= fakeMaxDepth {
+			return []any{}, nil
+		}
+
+		var itemSchema *rest.TypeSchema
+		if typeSchema != nil {
+			itemSchema = typeSchema.Items
+		}
+
+		results := make([]any, fakeArrayLength)
+		for i := range results {
+			results[i], err = g.generate(t.ElementType, itemSchema, depth+1)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return results, nil
+	case *schema.NamedType:
+		if objectType, ok := g.schema.ObjectTypes[t.Name]; ok {
+			return g.generateObject(objectType, depth)
+		}
+
+		if scalarType, ok := g.schema.ScalarTypes[t.Name]; ok {
+			return g.generateScalar(t.Name, scalarType, typeSchema), nil
+		}
+
+		return nil, fmt.Errorf("type %s does not exist", t.Name)
+	default:
+		return nil, fmt.Errorf("unsupported type %v", resultType)
+	}
+}
+
+func (g *fakeDataGenerator) generateObject(objectType rest.ObjectType, depth int) (map[string]any, error) {
+	result := make(map[string]any, len(objectType.Fields))
+	// fields are generated in order to keep the result stable
+	for _, key := range utils.GetSortedKeys(objectType.Fields) {
+		field := objectType.Fields[key]
+		value, err := g.generate(field.Type, field.HTTP, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+func (g *fakeDataGenerator) generateScalar(name string, scalarType schema.ScalarType, typeSchema *rest.TypeSchema) any {
+	var format string
+	if typeSchema != nil {
+		format = typeSchema.Format
+	}
+
+	switch rest.ScalarName(name) {
+	case rest.ScalarEmail:
+		format = "email"
+	case rest.ScalarURI:
+		format = "uri"
+	case rest.ScalarIPV4:
+		format = "ipv4"
+	case rest.ScalarIPV6:
+		format = "ipv6"
+	case rest.ScalarUnixTime:
+		return g.generateTime().Unix()
+	default:
+	}
+
+	representation, err := scalarType.Representation.Type()
+	if err != nil {
+		// the representation defaults to JSON
+		return map[string]any{}
+	}
+
+	switch representation {
+	case schema.TypeRepresentationTypeBoolean:
+		return g.rand.IntN(2) == 1
+	case schema.TypeRepresentationTypeEnum:
+		enum, err := scalarType.Representation.AsEnum()
+		if err != nil || len(enum.OneOf) == 0 {
+			return nil
+		}
+
+		return enum.OneOf[g.rand.IntN(len(enum.OneOf))]
+	case schema.TypeRepresentationTypeInt8:
+		return g.generateInteger(typeSchema, math.MinInt8, math.MaxInt8)
+	case schema.TypeRepresentationTypeInt16:
+		return g.generateInteger(typeSchema, math.MinInt16, math.MaxInt16)
+	case schema.TypeRepresentationTypeInt32:
+		return g.generateInteger(typeSchema, math.MinInt32, math.MaxInt32)
+	case schema.TypeRepresentationTypeInt64, schema.TypeRepresentationTypeInteger, schema.TypeRepresentationTypeBigInteger:
+		return g.generateInteger(typeSchema, math.MinInt64, math.MaxInt64)
+	case schema.TypeRepresentationTypeFloat32, schema.TypeRepresentationTypeFloat64, schema.TypeRepresentationTypeNumber, schema.TypeRepresentationTypeBigDecimal:
+		return g.generateNumber(typeSchema)
+	case schema.TypeRepresentationTypeUUID:
+		return g.generateUUID()
+	case schema.TypeRepresentationTypeDate:
+		return g.generateTime().Format(time.DateOnly)
+	case schema.TypeRepresentationTypeTimestamp, schema.TypeRepresentationTypeTimestampTZ:
+		return g.generateTime().Format(time.RFC3339)
+	case schema.TypeRepresentationTypeBytes:
+		return base64.StdEncoding.EncodeToString([]byte(g.generateWord()))
+	case schema.TypeRepresentationTypeString:
+		return g.generateString(format, typeSchema)
+	default:
+		return map[string]any{}
+	}
+}
+
+// generateInteger generates an integer in the range of minimum and maximum constraints of the type schema.
+func (g *fakeDataGenerator) generateInteger(typeSchema *rest.TypeSchema, lowerBound int64, upperBound int64) int64 {
+	low, high := int64(1), int64(1000)
+	if typeSchema != nil && typeSchema.Minimum != nil {
+		low = int64(math.Ceil(*typeSchema.Minimum))
+		high = low + 999
+	}
+
+	if typeSchema != nil && typeSchema.Maximum != nil {
+		high = int64(math.Floor(*typeSchema.Maximum))
+		if typeSchema.Minimum == nil {
+			low = min(low, high)
+		}
+	}
+
+	low = max(low, lowerBound)
+	high = min(high, upperBound)
+	if high <= low {
+		return low
+	}
+
+	return low + g.rand.Int64N(high-low+1)
+}
+
+// generateNumber generates a number with 2 decimals in the range of minimum and maximum constraints of the type schema.
+func (g *fakeDataGenerator) generateNumber(typeSchema *rest.TypeSchema) float64 {
+	low, high := float64(0), float64(1000)
+	if typeSchema != nil && typeSchema.Minimum != nil {
+		low = *typeSchema.Minimum
+		high = low + 1000
+	}
+
+	if typeSchema != nil && typeSchema.Maximum != nil {
+		high = *typeSchema.Maximum
+		if typeSchema.Minimum == nil {
+			low = min(low, high)
+		}
+	}
+
+	if high <= low {
+		return low
+	}
+
+	value := math.Round((low+g.rand.Float64()*(high-low))*100) / 100
+
+	return min(high, max(low, value))
+}
+
+func (g *fakeDataGenerator) generateString(format string, typeSchema *rest.TypeSchema) string {
+	switch format {
+	case "email":
+		return fmt.Sprintf("%s.%s@example.com", g.generateWord(), g.generateWord())
+	case "uri", "url":
+		return "https://example.com/" + g.generateWord()
+	case "uuid":
+		return g.generateUUID()
+	case "date":
+		return g.generateTime().Format(time.DateOnly)
+	case "date-time":
+		return g.generateTime().Format(time.RFC3339)
+	case "ipv4":
+		return fmt.Sprintf("192.0.2.%d", g.rand.IntN(255)+1)
+	case "ipv6":
+		return fmt.Sprintf("2001:db8::%x", g.rand.IntN(0xffff)+1)
+	default:
+	}
+
+	var minLength, maxLength int
+	if typeSchema != nil && typeSchema.MinLength != nil {
+		minLength = int(*typeSchema.MinLength)
+	}
+
+	if typeSchema != nil && typeSchema.MaxLength != nil {
+		maxLength = int(*typeSchema.MaxLength)
+	}
+
+	words := []string{g.generateWord(), g.generateWord()}
+	result := strings.Join(words, " ")
+	for len(result) < minLength {
+		result += " " + g.generateWord()
+	}
+
+	if maxLength > 0 && len(result) > maxLength {
+		result = strings.TrimSpace(result[:maxLength])
+		// the trimmed string may be shorter than the min length if the last character is a space
+		for len(result) < minLength {
+			result += "x"
+		}
+	}
+
+	return result
+}
+
+func (g *fakeDataGenerator) generateWord() string {
+	return fakeWords[g.rand.IntN(len(fakeWords))]
+}
+
+func (g *fakeDataGenerator) generateTime() time.Time {
+	return fakeStartTime.Add(time.Duration(g.rand.Int64N(int64(365 * 24 * time.Hour)))).Truncate(time.Second)
+}
+
+func (g *fakeDataGenerator) generateUUID() string {
+	var value uuid.UUID
+	for i := 0; i < len(value); i += 8 {
+		n := g.rand.Uint64()
+		for j := range 8 {
+			value[i+j] = byte(n >> (8 * j))
+		}
+	}
+
+	// set the version 4 and variant bits
+	value[6] = (value[6] & 0x0f) | 0x40
+	value[8] = (value[8] & 0x3f) | 0x80
+
+	return value.String()
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"net/mail"
+	"testing"
+
+	"github.com/google/uuid"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestGenerateFakeData(t *testing.T) {
+	httpSchema := rest.NewNDCHttpSchema()
+	httpSchema.ScalarTypes = schema.SchemaResponseScalarTypes{
+		"Int32":     schema.ScalarType{Representation: schema.NewTypeRepresentationInt32().Encode()},
+		"String":    schema.ScalarType{Representation: schema.NewTypeRepresentationString().Encode()},
+		"Email":     schema.ScalarType{Representation: schema.NewTypeRepresentationString().Encode()},
+		"UUID":      schema.ScalarType{Representation: schema.NewTypeRepresentationUUID().Encode()},
+		"PetStatus": schema.ScalarType{Representation: schema.NewTypeRepresentationEnum([]string{"available", "pending", "sold"}).Encode()},
+	}
+	httpSchema.ObjectTypes = map[string]rest.ObjectType{
+		"Pet": {
+			Fields: map[string]rest.ObjectField{
+				"id": {
+					ObjectField: schema.ObjectField{Type: schema.NewNamedType("UUID").Encode()},
+				},
+				"age": {
+					ObjectField: schema.ObjectField{Type: schema.NewNamedType("Int32").Encode()},
+					HTTP: &rest.TypeSchema{
+						Type:    []string{"integer"},
+						Minimum: utils.ToPtr(float64(1)),
+						Maximum: utils.ToPtr(float64(3)),
+					},
+				},
+				"name": {
+					ObjectField: schema.ObjectField{Type: schema.NewNamedType("String").Encode()},
+					HTTP: &rest.TypeSchema{
+						Type:      []string{"string"},
+						MinLength: utils.ToPtr[int64](20),
+						MaxLength: utils.ToPtr[int64](25),
+					},
+				},
+				"ownerEmail": {
+					ObjectField: schema.ObjectField{Type: schema.NewNullableType(schema.NewNamedType("Email")).Encode()},
+				},
+				"status": {
+					ObjectField: schema.ObjectField{Type: schema.NewNamedType("PetStatus").Encode()},
+				},
+				"children": {
+					ObjectField: schema.ObjectField{Type: schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("Pet"))).Encode()},
+				},
+			},
+		},
+	}
+
+	resultType := schema.NewArrayType(schema.NewNamedType("Pet")).Encode()
+	result, err := GenerateFakeData(httpSchema, "findPets", resultType)
+	assert.NilError(t, err)
+
+	var assertPet func(value any)
+	assertPet = func(value any) {
+		pet, ok := value.(map[string]any)
+		assert.Assert(t, ok)
+
+		_, err := uuid.Parse(pet["id"].(string))
+		assert.NilError(t, err)
+
+		age := pet["age"].(int64)
+		assert.Assert(t, age >= 1 && age <= 3)
+
+		name := pet["name"].(string)
+		assert.Assert(t, len(name) >= 20 && len(name) <= 25, name)
+
+		if pet["ownerEmail"] != nil {
+			_, err = mail.ParseAddress(pet["ownerEmail"].(string))
+			assert.NilError(t, err)
+		}
+
+		assert.Assert(t, pet["status"] == "available" || pet["status"] == "pending" || pet["status"] == "sold")
+
+		if pet["children"] != nil {
+			for _, child := range pet["children"].([]any) {
+				assertPet(child)
+			}
+		}
+	}
+
+	pets, ok := result.([]any)
+	assert.Assert(t, ok)
+	assert.Equal(t, fakeArrayLength, len(pets))
+	for _, pet := range pets {
+		assertPet(pet)
+	}
+
+	// the result is stable for the same operation
+	result2, err := GenerateFakeData(httpSchema, "findPets", resultType)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, result, result2)
+
+	_, err = GenerateFakeData(httpSchema, "findPets", schema.NewNamedType("Unknown").Encode())
+	assert.ErrorContains(t, err, "type Unknown does not exist")
+}
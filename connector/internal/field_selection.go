@@ -0,0 +1,252 @@
+package internal
+
+import (
+	"net/url"
+	"slices"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// fieldSelectionTree represents selected fields of a query.
+// A nil child means that the field is selected in full.
+type fieldSelectionTree map[string]fieldSelectionTree
+
+// PushDownFieldSelection maps field selections of the query onto the upstream query parameter
+// if the operation enables field selection pushdown.
+// The upstream query parameter isn't overridden if it's already set by arguments.
+func (um *UpstreamManager) PushDownFieldSelection(results *RequestBuilderResults, fields schema.NestedField) error {
+	if results.Operation == nil || results.Operation.Request == nil || len(fields) == 0 {
+		return nil
+	}
+
+	settings := results.Operation.Request.FieldSelection
+	if settings == nil || settings.Parameter == "" {
+		return nil
+	}
+
+	// selections are evaluated against the transformed response
+	if um.transformers[results.OperationName].HasResponseTransforms() {
+		return nil
+	}
+
+	if !results.IsCollection() {
+		var ok bool
+		fields, ok = um.unwrapForwardedHeadersSelection(fields)
+		if !ok {
+			return nil
+		}
+	}
+
+	tree := fieldSelectionTree{}
+	if err := tree.evalNestedField(fields); err != nil {
+		return schema.UnprocessableContentError("failed to evaluate the field selection", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	// fields of collection rows are selected in the items field, e.g. $select of OData services
+	if results.IsCollection() {
+		tree = tree.child(getCollectionItemsField(results.Operation))
+	}
+
+	// enriched fields don't exist in the upstream response but their keys are required
+	for _, enrich := range results.Operation.Request.Response.Enrich {
+		delete(tree, enrich.Field)
+		tree.addPath(enrich.KeyField)
+	}
+
+	if len(tree) == 0 {
+		return nil
+	}
+
+	for _, path := range settings.Always {
+		tree.addPath(path)
+	}
+
+	// the next cursor is required to fetch more pages
+	if pagination := results.Operation.Request.Pagination; pagination != nil && pagination.Type == rest.PaginationCursor && !results.IsCollection() {
+		tree.addPath(pagination.CursorField)
+	}
+
+	value := tree.render(settings.Style)
+	for _, req := range results.Requests {
+		if req.URL.Query().Has(settings.Parameter) {
+			continue
+		}
+
+		query := url.QueryEscape(settings.Parameter) + "=" + url.QueryEscape(value)
+		if req.URL.RawQuery == "" {
+			req.URL.RawQuery = query
+		} else {
+			req.URL.RawQuery += "&" + query
+		}
+	}
+
+	return nil
+}
+
+// get the selection of the result field if response headers are forwarded.
+func (um *UpstreamManager) unwrapForwardedHeadersSelection(fields schema.NestedField) (schema.NestedField, bool) {
+	forwardHeaders := um.config.ForwardHeaders
+	if !forwardHeaders.Enabled || forwardHeaders.ResponseHeaders == nil {
+		return fields, true
+	}
+
+	nested, err := fields.InterfaceT()
+	if err != nil {
+		return nil, false
+	}
+
+	object, ok := nested.(*schema.NestedObject)
+	if !ok {
+		return nil, false
+	}
+
+	for _, field := range object.Fields {
+		column, err := field.AsColumn()
+		if err != nil || column.Column != forwardHeaders.ResponseHeaders.ResultField {
+			continue
+		}
+
+		return column.Fields, len(column.Fields) > 0
+	}
+
+	return nil, false
+}
+
+func (fst fieldSelectionTree) evalNestedField(fields schema.NestedField) error {
+	nested, err := fields.InterfaceT()
+	if err != nil {
+		return err
+	}
+
+	switch nf := nested.(type) {
+	case *schema.NestedObject:
+		for _, field := range nf.Fields {
+			column, err := field.AsColumn()
+			if err != nil {
+				return err
+			}
+
+			child, exists := fst[column.Column]
+			if len(column.Fields) == 0 || (exists && child == nil) {
+				fst[column.Column] = nil
+
+				continue
+			}
+
+			if child == nil {
+				child = fieldSelectionTree{}
+				fst[column.Column] = child
+			}
+
+			if err := child.evalNestedField(column.Fields); err != nil {
+				return err
+			}
+		}
+	case *schema.NestedArray:
+		return fst.evalNestedField(nf.Fields)
+	}
+
+	return nil
+}
+
+// get the subtree at the field path in dot notation. Returns the tree itself if the path is empty.
+func (fst fieldSelectionTree) child(path string) fieldSelectionTree {
+	if path == "" {
+		return fst
+	}
+
+	current := fst
+	for _, segment := range strings.Split(path, ".") {
+		current = current[segment]
+		if current == nil {
+			return fieldSelectionTree{}
+		}
+	}
+
+	return current
+}
+
+// add a field path in dot notation to the tree.
+func (fst fieldSelectionTree) addPath(path string) {
+	if path == "" {
+		return
+	}
+
+	current := fst
+	segments := strings.Split(path, ".")
+	for i, segment := range segments {
+		child, exists := current[segment]
+		if i == len(segments)-1 {
+			if !exists {
+				current[segment] = nil
+			}
+
+			return
+		}
+
+		// the parent field is already selected in full
+		if exists && child == nil {
+			return
+		}
+
+		if child == nil {
+			child = fieldSelectionTree{}
+			current[segment] = child
+		}
+
+		current = child
+	}
+}
+
+// render encodes the selection tree to the parameter value with the style.
+func (fst fieldSelectionTree) render(style rest.FieldSelectionStyle) string {
+	switch style {
+	case rest.FieldSelectionDot:
+		return strings.Join(fst.renderPaths(""), ",")
+	case rest.FieldSelectionParentheses:
+		return fst.renderParentheses()
+	default:
+		return strings.Join(fst.sortedKeys(), ",")
+	}
+}
+
+func (fst fieldSelectionTree) renderPaths(prefix string) []string {
+	var results []string
+	for _, key := range fst.sortedKeys() {
+		child := fst[key]
+		if len(child) == 0 {
+			results = append(results, prefix+key)
+
+			continue
+		}
+
+		results = append(results, child.renderPaths(prefix+key+".")...)
+	}
+
+	return results
+}
+
+func (fst fieldSelectionTree) renderParentheses() string {
+	keys := fst.sortedKeys()
+	for i, key := range keys {
+		if child := fst[key]; len(child) > 0 {
+			keys[i] = key + "(" + child.renderParentheses() + ")"
+		}
+	}
+
+	return strings.Join(keys, ",")
+}
+
+func (fst fieldSelectionTree) sortedKeys() []string {
+	keys := make([]string, 0, len(fst))
+	for key := range fst {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
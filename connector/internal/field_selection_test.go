@@ -0,0 +1,155 @@
+package internal
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestPushDownFieldSelection(t *testing.T) {
+	fields := schema.NewNestedArray(schema.NewNestedObject(map[string]schema.FieldEncoder{
+		"id":      schema.NewColumnField("id", nil),
+		"petName": schema.NewColumnField("name", nil),
+		"owner": schema.NewColumnField("owner", schema.NewNestedObject(map[string]schema.FieldEncoder{
+			"name": schema.NewColumnField("name", nil),
+			"address": schema.NewColumnField("address", schema.NewNestedObject(map[string]schema.FieldEncoder{
+				"city": schema.NewColumnField("city", nil),
+			})),
+		})),
+		"details": schema.NewColumnField("details", nil),
+	})).Encode()
+
+	testCases := []struct {
+		Name     string
+		Settings *rest.FieldSelectionSettings
+		RawQuery string
+		Enrich   []rest.ResponseEnrichSettings
+		Expected string
+	}{
+		{
+			Name:     "disabled",
+			RawQuery: "status=available",
+			Expected: "status=available",
+		},
+		{
+			Name: "comma",
+			Settings: &rest.FieldSelectionSettings{
+				Parameter: "fields",
+			},
+			RawQuery: "status=available",
+			Expected: "status=available&fields=details%2Cid%2Cname%2Cowner",
+		},
+		{
+			Name: "dot",
+			Settings: &rest.FieldSelectionSettings{
+				Parameter: "fields",
+				Style:     rest.FieldSelectionDot,
+				Always:    []string{"owner.id", "details.code"},
+			},
+			Expected: "fields=details%2Cid%2Cname%2Cowner.address.city%2Cowner.id%2Cowner.name",
+		},
+		{
+			Name: "parentheses",
+			Settings: &rest.FieldSelectionSettings{
+				Parameter: "fields",
+				Style:     rest.FieldSelectionParentheses,
+			},
+			Expected: "fields=details%2Cid%2Cname%2Cowner%28address%28city%29%2Cname%29",
+		},
+		{
+			Name: "enrich",
+			Settings: &rest.FieldSelectionSettings{
+				Parameter: "fields",
+			},
+			Enrich: []rest.ResponseEnrichSettings{
+				{Field: "details", KeyField: "detailId"},
+			},
+			Expected: "fields=detailId%2Cid%2Cname%2Cowner",
+		},
+		{
+			Name: "override",
+			Settings: &rest.FieldSelectionSettings{
+				Parameter: "fields",
+			},
+			RawQuery: "fields=id",
+			Expected: "fields=id",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			um := NewUpstreamManager(nil, &configuration.Configuration{})
+			results := &RequestBuilderResults{
+				OperationName: "findPets",
+				Operation: &rest.OperationInfo{
+					Request: &rest.Request{
+						FieldSelection: tc.Settings,
+						Response: rest.Response{
+							Enrich: tc.Enrich,
+						},
+					},
+				},
+				Requests: []*RetryableRequest{
+					{
+						URL: url.URL{
+							Scheme:   "https",
+							Host:     "example.com",
+							Path:     "/pets",
+							RawQuery: tc.RawQuery,
+						},
+					},
+				},
+			}
+
+			assert.NilError(t, um.PushDownFieldSelection(results, fields))
+			assert.Equal(t, tc.Expected, results.Requests[0].URL.RawQuery)
+		})
+	}
+
+	t.Run("collection", func(t *testing.T) {
+		um := NewUpstreamManager(nil, &configuration.Configuration{})
+		operation := &rest.OperationInfo{
+			Collection: true,
+			Request: &rest.Request{
+				FieldSelection: &rest.FieldSelectionSettings{Parameter: "$select"},
+				Pagination: &rest.PaginationSettings{
+					Type:            rest.PaginationOffset,
+					OffsetParameter: "$skip",
+					ItemsField:      "value",
+				},
+			},
+		}
+		results := &RequestBuilderResults{
+			OperationName: "people",
+			Operation:     operation,
+			Requests:      []*RetryableRequest{{}},
+		}
+		selection := BuildCollectionSelection(operation, schema.QueryFields{
+			"UserName":  schema.NewColumnField("UserName", nil).Encode(),
+			"FirstName": schema.NewColumnField("FirstName", nil).Encode(),
+		})
+
+		assert.NilError(t, um.PushDownFieldSelection(results, selection))
+		assert.Equal(t, "%24select=FirstName%2CUserName", results.Requests[0].URL.RawQuery)
+	})
+
+	t.Run("relationship", func(t *testing.T) {
+		um := NewUpstreamManager(nil, &configuration.Configuration{})
+		results := &RequestBuilderResults{
+			Operation: &rest.OperationInfo{
+				Request: &rest.Request{
+					FieldSelection: &rest.FieldSelectionSettings{Parameter: "fields"},
+				},
+			},
+		}
+		relationshipFields := schema.NewNestedObject(map[string]schema.FieldEncoder{
+			"pets": schema.NewRelationshipField(schema.Query{}, "pets", nil),
+		}).Encode()
+
+		assert.ErrorContains(t, um.PushDownFieldSelection(results, relationshipFields), "failed to evaluate the field selection")
+	})
+}
@@ -0,0 +1,320 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/hasura/ndc-http/connector/internal/contenttype"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// filterBuilder translates predicates of the query into upstream query parameters.
+type filterBuilder struct {
+	settings  *rest.FilterSettings
+	variables map[string]any
+	// the result field of the response if response headers are forwarded
+	resultField string
+	// the items field of collection rows. Filter fields are paths of the result object
+	itemsField string
+	params     []string
+	// comparison expressions which are joined by and in the odata style
+	expressions []string
+}
+
+// odataComparisonOperators map comparison operators to operators of the OData $filter expression.
+var odataComparisonOperators = map[string]string{
+	"_eq":  "eq",
+	"_neq": "ne",
+	"_gt":  "gt",
+	"_gte": "ge",
+	"_lt":  "lt",
+	"_lte": "le",
+	"_in":  "in",
+}
+
+// PushDownPredicate maps comparison predicates of the query onto upstream query parameters
+// if the operation declares filter settings. Only binary comparisons which are joined by and are supported.
+func (um *UpstreamManager) PushDownPredicate(results *RequestBuilderResults, predicate schema.Expression, variables map[string]any) error {
+	if len(predicate) == 0 {
+		return nil
+	}
+
+	var settings *rest.FilterSettings
+	if results.Operation != nil && results.Operation.Request != nil {
+		settings = results.Operation.Request.Filter
+	}
+
+	// the connector doesn't filter results itself, so the predicate can't be ignored
+	if settings == nil {
+		return schema.UnprocessableContentError(fmt.Sprintf("the operation %s doesn't support predicates", results.OperationName), nil)
+	}
+
+	builder := &filterBuilder{
+		settings:  settings,
+		variables: variables,
+	}
+
+	if results.IsCollection() {
+		builder.itemsField = getCollectionItemsField(results.Operation)
+	}
+
+	if forwardHeaders := um.config.ForwardHeaders; forwardHeaders.Enabled && forwardHeaders.ResponseHeaders != nil && !results.IsCollection() {
+		builder.resultField = forwardHeaders.ResponseHeaders.ResultField
+	}
+
+	if err := builder.evalExpression(predicate); err != nil {
+		return schema.UnprocessableContentError("failed to push down the predicate", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	if len(builder.expressions) > 0 {
+		// OData services expect spaces to be encoded as %20
+		builder.params = append(builder.params, url.QueryEscape(settings.GetParameter())+"="+
+			strings.ReplaceAll(url.QueryEscape(strings.Join(builder.expressions, " and ")), "+", "%20"))
+	}
+
+	query := strings.Join(builder.params, "&")
+	for _, req := range results.Requests {
+		if req.URL.RawQuery == "" {
+			req.URL.RawQuery = query
+		} else {
+			req.URL.RawQuery += "&" + query
+		}
+	}
+
+	return nil
+}
+
+func (fb *filterBuilder) evalExpression(predicate schema.Expression) error {
+	expr, err := predicate.InterfaceT()
+	if err != nil {
+		return err
+	}
+
+	switch e := expr.(type) {
+	case *schema.ExpressionAnd:
+		for _, child := range e.Expressions {
+			if err := fb.evalExpression(child); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	case *schema.ExpressionBinaryComparisonOperator:
+		return fb.evalBinaryComparison(e)
+	default:
+		return fmt.Errorf("unsupported expression type %s", predicate["type"])
+	}
+}
+
+func (fb *filterBuilder) evalBinaryComparison(expr *schema.ExpressionBinaryComparisonOperator) error {
+	fieldPath, err := fb.evalFieldPath(expr.Column)
+	if err != nil {
+		return err
+	}
+
+	field, ok := fb.settings.Fields[fieldPath]
+	if !ok {
+		return fmt.Errorf("the field %s isn't filterable", fieldPath)
+	}
+
+	if !slices.Contains(field.GetOperators(), expr.Operator) {
+		return fmt.Errorf("%s: unsupported comparison operator %s", fieldPath, expr.Operator)
+	}
+
+	value, err := fb.evalComparisonValue(expr.Value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fieldPath, err)
+	}
+
+	if fb.settings.Style == rest.FilterOData {
+		expression, err := fb.renderODataExpression(fieldPath, field, expr.Operator, value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", fieldPath, err)
+		}
+
+		fb.expressions = append(fb.expressions, expression)
+
+		return nil
+	}
+
+	paramValue, err := fb.renderValue(expr.Operator, value)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fieldPath, err)
+	}
+
+	fb.params = append(fb.params, url.QueryEscape(field.GetParameter(fieldPath))+"="+url.QueryEscape(paramValue))
+
+	return nil
+}
+
+// get the field path in dot notation of the comparison target, relative to the upstream response.
+func (fb *filterBuilder) evalFieldPath(target schema.ComparisonTarget) (string, error) {
+	if target.Type != schema.ComparisonTargetTypeColumn || len(target.Path) > 0 {
+		return "", errors.New("only comparisons of columns of the result are supported")
+	}
+
+	segments := target.FieldPath
+	// function results are returned in the __value column
+	if target.Name != "__value" {
+		segments = append([]string{target.Name}, segments...)
+	}
+
+	if fb.itemsField != "" {
+		segments = append(strings.Split(fb.itemsField, "."), segments...)
+	}
+
+	if fb.resultField != "" {
+		if len(segments) == 0 || segments[0] != fb.resultField {
+			return "", fmt.Errorf("expected a field of %s", fb.resultField)
+		}
+
+		segments = segments[1:]
+	}
+
+	if len(segments) == 0 {
+		return "", errors.New("the field path of the comparison target is empty")
+	}
+
+	return strings.Join(segments, "."), nil
+}
+
+func (fb *filterBuilder) evalComparisonValue(value schema.ComparisonValue) (any, error) {
+	cv, err := value.InterfaceT()
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := cv.(type) {
+	case *schema.ComparisonValueScalar:
+		return v.Value, nil
+	case *schema.ComparisonValueVariable:
+		result, ok := fb.variables[v.Name]
+		if !ok {
+			return nil, fmt.Errorf("variable %s not found", v.Name)
+		}
+
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unsupported comparison value type %s", value["type"])
+	}
+}
+
+func (fb *filterBuilder) renderValue(operator string, value any) (string, error) {
+	if operator != "_in" {
+		str, err := stringifyFilterValue(value)
+		if err != nil {
+			return "", err
+		}
+
+		if fb.settings.Style == rest.FilterOperator {
+			return rest.FilterComparisonOperators[operator] + "." + str, nil
+		}
+
+		return str, nil
+	}
+
+	items := reflect.ValueOf(value)
+	if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+		return "", fmt.Errorf("expected an array value of the _in operator, got %T", value)
+	}
+
+	values := make([]string, items.Len())
+	for i := range items.Len() {
+		str, err := stringifyFilterValue(items.Index(i).Interface())
+		if err != nil {
+			return "", fmt.Errorf("[%d]: %w", i, err)
+		}
+
+		values[i] = str
+	}
+
+	if fb.settings.Style == rest.FilterOperator {
+		return "in.(" + strings.Join(values, ",") + ")", nil
+	}
+
+	return strings.Join(values, ","), nil
+}
+
+// render the comparison expression of the OData $filter query option, e.g. Status eq 'available' or Price gt 10.
+// The property path defaults to the field path of rows with slashes, e.g. Address/City.
+func (fb *filterBuilder) renderODataExpression(fieldPath string, field rest.FilterFieldSettings, operator string, value any) (string, error) {
+	property := field.Parameter
+	if property == "" {
+		if fb.itemsField != "" {
+			fieldPath = strings.TrimPrefix(fieldPath, fb.itemsField+".")
+		}
+		property = strings.ReplaceAll(fieldPath, ".", "/")
+	}
+
+	if operator != "_in" {
+		literal, err := renderODataLiteral(value, field.Unquoted)
+		if err != nil {
+			return "", err
+		}
+
+		return property + " " + odataComparisonOperators[operator] + " " + literal, nil
+	}
+
+	items := reflect.ValueOf(value)
+	if items.Kind() != reflect.Slice && items.Kind() != reflect.Array {
+		return "", fmt.Errorf("expected an array value of the _in operator, got %T", value)
+	}
+
+	literals := make([]string, items.Len())
+	for i := range items.Len() {
+		literal, err := renderODataLiteral(items.Index(i).Interface(), field.Unquoted)
+		if err != nil {
+			return "", fmt.Errorf("[%d]: %w", i, err)
+		}
+
+		literals[i] = literal
+	}
+
+	return property + " in (" + strings.Join(literals, ",") + ")", nil
+}
+
+// render the value to an OData literal. Strings are quoted and single quotes are escaped by doubling.
+func renderODataLiteral(value any, unquoted bool) (string, error) {
+	if value == nil {
+		return "null", nil
+	}
+
+	reflectValue := reflect.ValueOf(value)
+	kind := reflectValue.Kind()
+	if kind == reflect.Pointer {
+		if reflectValue.IsNil() {
+			return "null", nil
+		}
+
+		reflectValue = reflectValue.Elem()
+		kind = reflectValue.Kind()
+	}
+
+	str, err := contenttype.StringifySimpleScalar(reflectValue, kind)
+	if err != nil {
+		return "", err
+	}
+
+	if kind != reflect.String || unquoted {
+		return str, nil
+	}
+
+	return "'" + strings.ReplaceAll(str, "'", "''") + "'", nil
+}
+
+func stringifyFilterValue(value any) (string, error) {
+	if value == nil {
+		return "", errors.New("null values aren't supported")
+	}
+
+	reflectValue := reflect.ValueOf(value)
+
+	return contenttype.StringifySimpleScalar(reflectValue, reflectValue.Kind())
+}
@@ -0,0 +1,180 @@
+package internal
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestPushDownPredicate(t *testing.T) {
+	fields := map[string]rest.FilterFieldSettings{
+		"status": {
+			Operators: []string{"_eq", "_in"},
+		},
+		"price": {
+			Parameter: "price_value",
+			Operators: []string{"_gte", "_lt"},
+		},
+		"owner.name": {
+			Parameter: "owner",
+		},
+		"createdAt": {
+			Operators: []string{"_lt"},
+			Unquoted:  true,
+		},
+	}
+	valueColumn := func(fieldPath ...string) schema.ComparisonTarget {
+		return *schema.NewComparisonTargetColumn("__value", fieldPath, nil)
+	}
+
+	testCases := []struct {
+		Name      string
+		Style     rest.FilterStyle
+		Predicate schema.ExpressionEncoder
+		Variables map[string]any
+		RawQuery  string
+		Expected  string
+		Error     string
+	}{
+		{
+			Name:      "plain",
+			Predicate: schema.NewExpressionBinaryComparisonOperator(valueColumn("status"), "_eq", schema.NewComparisonValueScalar("available")),
+			RawQuery:  "limit=10",
+			Expected:  "limit=10&status=available",
+		},
+		{
+			Name: "plain_in",
+			Predicate: schema.NewExpressionAnd(
+				schema.NewExpressionBinaryComparisonOperator(valueColumn("status"), "_in", schema.NewComparisonValueScalar([]any{"available", "sold"})),
+				schema.NewExpressionBinaryComparisonOperator(valueColumn("owner", "name"), "_eq", schema.NewComparisonValueVariable("owner")),
+			),
+			Variables: map[string]any{"owner": "Alice"},
+			Expected:  "status=available%2Csold&owner=Alice",
+		},
+		{
+			Name:  "operator",
+			Style: rest.FilterOperator,
+			Predicate: schema.NewExpressionAnd(
+				schema.NewExpressionBinaryComparisonOperator(valueColumn("status"), "_in", schema.NewComparisonValueScalar([]any{"available", "sold"})),
+				schema.NewExpressionBinaryComparisonOperator(valueColumn("price"), "_gte", schema.NewComparisonValueScalar(float64(10))),
+				schema.NewExpressionBinaryComparisonOperator(valueColumn("price"), "_lt", schema.NewComparisonValueScalar(20.5)),
+			),
+			Expected: "status=in.%28available%2Csold%29&price_value=gte.10&price_value=lt.20.5",
+		},
+		{
+			Name:  "odata",
+			Style: rest.FilterOData,
+			Predicate: schema.NewExpressionAnd(
+				schema.NewExpressionBinaryComparisonOperator(valueColumn("status"), "_in", schema.NewComparisonValueScalar([]any{"available", "it's sold"})),
+				schema.NewExpressionBinaryComparisonOperator(valueColumn("price"), "_gte", schema.NewComparisonValueScalar(float64(10))),
+				schema.NewExpressionBinaryComparisonOperator(valueColumn("owner", "name"), "_eq", schema.NewComparisonValueScalar("Alice")),
+				schema.NewExpressionBinaryComparisonOperator(valueColumn("createdAt"), "_lt", schema.NewComparisonValueScalar("2024-01-01T00:00:00Z")),
+			),
+			RawQuery: "$top=10",
+			Expected: "$top=10&%24filter=status%20in%20%28%27available%27%2C%27it%27%27s%20sold%27%29%20and%20price_value%20ge%2010%20and%20owner%20eq%20%27Alice%27%20and%20createdAt%20lt%202024-01-01T00%3A00%3A00Z",
+		},
+		{
+			Name:      "unsupported_operator",
+			Predicate: schema.NewExpressionBinaryComparisonOperator(valueColumn("price"), "_eq", schema.NewComparisonValueScalar(10)),
+			Error:     "price: unsupported comparison operator _eq",
+		},
+		{
+			Name:      "unknown_field",
+			Predicate: schema.NewExpressionBinaryComparisonOperator(valueColumn("id"), "_eq", schema.NewComparisonValueScalar(1)),
+			Error:     "the field id isn't filterable",
+		},
+		{
+			Name: "or",
+			Predicate: schema.NewExpressionOr(
+				schema.NewExpressionBinaryComparisonOperator(valueColumn("status"), "_eq", schema.NewComparisonValueScalar("sold")),
+			),
+			Error: "unsupported expression type or",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			um := NewUpstreamManager(nil, &configuration.Configuration{})
+			results := &RequestBuilderResults{
+				OperationName: "findPets",
+				Operation: &rest.OperationInfo{
+					Request: &rest.Request{
+						Filter: &rest.FilterSettings{
+							Style:  tc.Style,
+							Fields: fields,
+						},
+					},
+				},
+				Requests: []*RetryableRequest{
+					{
+						URL: url.URL{
+							Scheme:   "https",
+							Host:     "example.com",
+							Path:     "/pets",
+							RawQuery: tc.RawQuery,
+						},
+					},
+				},
+			}
+
+			err := um.PushDownPredicate(results, tc.Predicate.Encode(), tc.Variables)
+			if tc.Error != "" {
+				var connectorErr *schema.ConnectorError
+				assert.Assert(t, errors.As(err, &connectorErr))
+				assert.Equal(t, tc.Error, connectorErr.Details["cause"])
+
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, tc.Expected, results.Requests[0].URL.RawQuery)
+		})
+	}
+
+	t.Run("odata_collection", func(t *testing.T) {
+		um := NewUpstreamManager(nil, &configuration.Configuration{})
+		results := &RequestBuilderResults{
+			OperationName: "people",
+			Operation: &rest.OperationInfo{
+				Collection: true,
+				Request: &rest.Request{
+					Pagination: &rest.PaginationSettings{
+						Type:            rest.PaginationOffset,
+						LimitParameter:  "$top",
+						OffsetParameter: "$skip",
+						ItemsField:      "value",
+					},
+					Filter: &rest.FilterSettings{
+						Style: rest.FilterOData,
+						Fields: map[string]rest.FilterFieldSettings{
+							"value.AddressInfo.City": {},
+						},
+					},
+				},
+			},
+			Requests: []*RetryableRequest{{}},
+		}
+		predicate := schema.NewExpressionBinaryComparisonOperator(*schema.NewComparisonTargetColumn("AddressInfo", []string{"City"}, nil), "_eq", schema.NewComparisonValueScalar("Boise")).Encode()
+
+		assert.NilError(t, um.PushDownPredicate(results, predicate, nil))
+		assert.Equal(t, "%24filter=AddressInfo%2FCity%20eq%20%27Boise%27", results.Requests[0].URL.RawQuery)
+	})
+
+	t.Run("not_supported", func(t *testing.T) {
+		um := NewUpstreamManager(nil, &configuration.Configuration{})
+		results := &RequestBuilderResults{
+			OperationName: "findPets",
+			Operation: &rest.OperationInfo{
+				Request: &rest.Request{},
+			},
+		}
+		predicate := schema.NewExpressionBinaryComparisonOperator(valueColumn("status"), "_eq", schema.NewComparisonValueScalar("sold")).Encode()
+
+		assert.ErrorContains(t, um.PushDownPredicate(results, predicate, nil), "the operation findPets doesn't support predicates")
+	})
+}
@@ -0,0 +1,368 @@
+package internal
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hasura/ndc-http/connector/internal/security"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/connector"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// HTTP headers which aren't forwarded to gRPC metadata.
+var grpcSkippedHeaders = []string{
+	"accept",
+	"accept-encoding",
+	"connection",
+	"content-length",
+	"content-type",
+	"host",
+	"te",
+	"user-agent",
+}
+
+// HTTP status codes of gRPC status codes, following the mapping of gRPC-HTTP transcoding.
+var grpcHTTPStatusCodes = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// GRPCClients manage protobuf descriptors and connections of gRPC upstreams.
+// Descriptor sets and connections are loaded lazily and reused by requests.
+type GRPCClients struct {
+	configDir string
+	files     map[string]*protoregistry.Files
+	conns     map[string]*grpc.ClientConn
+	lock      sync.Mutex
+}
+
+// NewGRPCClients creates a new GRPCClients instance.
+// Relative paths of descriptor sets are resolved from the configuration directory.
+func NewGRPCClients(configDir string) *GRPCClients {
+	return &GRPCClients{
+		configDir: configDir,
+		files:     map[string]*protoregistry.Files{},
+		conns:     map[string]*grpc.ClientConn{},
+	}
+}
+
+// SetGRPCClients sets the manager of gRPC upstreams.
+func (um *UpstreamManager) SetGRPCClients(clients *GRPCClients) {
+	um.grpcClients = clients
+}
+
+// Invoke calls the unary gRPC method with the JSON body of the HTTP request.
+// The response message is transcoded to a JSON response, and non-OK statuses are mapped to HTTP error responses,
+// so the response is evaluated in the same way as HTTP upstreams.
+func (gc *GRPCClients) Invoke(req *http.Request, settings *rest.GRPCRequest) (*http.Response, error) {
+	method, err := gc.findMethod(settings)
+	if err != nil {
+		return nil, err
+	}
+
+	if method.IsStreamingClient() || method.IsStreamingServer() {
+		return nil, fmt.Errorf("%s: streaming gRPC methods aren't supported", settings.FullMethod())
+	}
+
+	input := dynamicpb.NewMessage(method.Input())
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(bytes.TrimSpace(body)) > 0 {
+			if err := protojson.Unmarshal(body, input); err != nil {
+				return nil, schema.UnprocessableContentError("failed to transcode arguments to the gRPC request message", map[string]any{
+					"cause": err.Error(),
+				})
+			}
+		}
+	}
+
+	conn, err := gc.getConnection(req, settings)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := metadata.NewOutgoingContext(req.Context(), evalGRPCMetadata(req.Header))
+	output := dynamicpb.NewMessage(method.Output())
+
+	var header metadata.MD
+	if err := conn.Invoke(ctx, settings.FullMethod(), input, output, grpc.Header(&header)); err != nil {
+		return createGRPCErrorResponse(req, header, err)
+	}
+
+	body, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("failed to transcode the gRPC response message: %w", err)
+	}
+
+	return createGRPCResponse(req, http.StatusOK, header, body), nil
+}
+
+// Close closes all connections.
+func (gc *GRPCClients) Close() error {
+	gc.lock.Lock()
+	defer gc.lock.Unlock()
+
+	for key, conn := range gc.conns {
+		_ = conn.Close()
+		delete(gc.conns, key)
+	}
+
+	return nil
+}
+
+func (gc *GRPCClients) findMethod(settings *rest.GRPCRequest) (protoreflect.MethodDescriptor, error) {
+	files, err := gc.loadDescriptorSet(settings.DescriptorSet)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(settings.Service))
+	if err != nil {
+		return nil, fmt.Errorf("gRPC service %s: %w", settings.Service, err)
+	}
+
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s isn't a gRPC service", settings.Service)
+	}
+
+	method := service.Methods().ByName(protoreflect.Name(settings.Method))
+	if method == nil {
+		return nil, fmt.Errorf("gRPC method %s not found", settings.FullMethod())
+	}
+
+	return method, nil
+}
+
+// FindMessage finds the message descriptor by the fully-qualified name in the descriptor set.
+func (gc *GRPCClients) FindMessage(descriptorSet string, name string) (protoreflect.MessageDescriptor, error) {
+	files, err := gc.loadDescriptorSet(descriptorSet)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := files.FindDescriptorByName(protoreflect.FullName(name))
+	if err != nil {
+		return nil, fmt.Errorf("protobuf message %s: %w", name, err)
+	}
+
+	message, ok := desc.(protoreflect.MessageDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s isn't a protobuf message", name)
+	}
+
+	return message, nil
+}
+
+func (gc *GRPCClients) loadDescriptorSet(filePath string) (*protoregistry.Files, error) {
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(gc.configDir, filePath)
+	}
+
+	gc.lock.Lock()
+	defer gc.lock.Unlock()
+
+	if files, ok := gc.files[filePath]; ok {
+		return files, nil
+	}
+
+	rawBytes, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the protobuf descriptor set: %w", err)
+	}
+
+	var descriptorSet descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(rawBytes, &descriptorSet); err != nil {
+		return nil, fmt.Errorf("failed to decode the protobuf descriptor set %s: %w", filePath, err)
+	}
+
+	files, err := protodesc.NewFiles(&descriptorSet)
+	if err != nil {
+		return nil, fmt.Errorf("invalid protobuf descriptor set %s: %w", filePath, err)
+	}
+
+	gc.files[filePath] = files
+
+	return files, nil
+}
+
+func (gc *GRPCClients) getConnection(req *http.Request, settings *rest.GRPCRequest) (*grpc.ClientConn, error) {
+	useTLS := settings.TLS != nil || req.URL.Scheme == "https"
+	target := req.URL.Host
+	if req.URL.Port() == "" {
+		if useTLS {
+			target += ":443"
+		} else {
+			target += ":80"
+		}
+	}
+
+	key := target + "|" + strconv.FormatBool(useTLS)
+	if settings.TLS != nil {
+		key += fmt.Sprintf("|%p", settings.TLS)
+	}
+
+	gc.lock.Lock()
+	defer gc.lock.Unlock()
+
+	if conn, ok := gc.conns[key]; ok {
+		return conn, nil
+	}
+
+	creds := insecure.NewCredentials()
+	if useTLS {
+		tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+		if settings.TLS != nil {
+			var err error
+			tlsConfig, err = security.LoadTLSConfig(settings.TLS, connector.GetLogger(req.Context()))
+			if err != nil {
+				return nil, fmt.Errorf("failed to load TLS config: %w", err)
+			}
+		}
+
+		creds = credentials.NewTLS(tlsConfig)
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, err
+	}
+
+	gc.conns[key] = conn
+
+	return conn, nil
+}
+
+// build the gRPC request message from arguments. The body argument is the request message if the argument list is empty.
+func (c *RequestBuilder) buildGRPCRequestBody(request *RetryableRequest, grpcRequest *rest.GRPCRequest) error {
+	var message any = map[string]any{}
+	if len(grpcRequest.Arguments) > 0 {
+		fields := make(map[string]any)
+		for _, name := range grpcRequest.Arguments {
+			if value, ok := c.Arguments[name]; ok && value != nil {
+				fields[name] = value
+			}
+		}
+
+		message = fields
+	} else if body, ok := c.Arguments[rest.BodyKey]; ok && body != nil {
+		message = body
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(message); err != nil {
+		return err
+	}
+
+	request.ContentType = rest.ContentTypeJSON
+	request.Body = buf.Bytes()
+
+	return nil
+}
+
+func evalGRPCMetadata(headers http.Header) metadata.MD {
+	md := metadata.MD{}
+	for key, values := range headers {
+		name := strings.ToLower(key)
+		if len(values) == 0 || strings.HasPrefix(name, "grpc-") {
+			continue
+		}
+
+		if !slices.Contains(grpcSkippedHeaders, name) {
+			md.Append(name, values...)
+		}
+	}
+
+	return md
+}
+
+func createGRPCErrorResponse(req *http.Request, header metadata.MD, err error) (*http.Response, error) {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil, err
+	}
+
+	statusCode, ok := grpcHTTPStatusCodes[st.Code()]
+	if !ok {
+		statusCode = http.StatusInternalServerError
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"code":    int(st.Code()),
+		"status":  st.Code().String(),
+		"message": st.Message(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return createGRPCResponse(req, statusCode, header, body), nil
+}
+
+func createGRPCResponse(req *http.Request, statusCode int, header metadata.MD, body []byte) *http.Response {
+	respHeader := http.Header{}
+	for key, values := range header {
+		for _, value := range values {
+			respHeader.Add(key, value)
+		}
+	}
+
+	respHeader.Set(rest.ContentTypeHeader, rest.ContentTypeJSON)
+
+	return &http.Response{
+		Status:        strconv.Itoa(statusCode) + " " + http.StatusText(statusCode),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/2.0",
+		ProtoMajor:    2,
+		Header:        respHeader,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,184 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"gotest.tools/v3/assert"
+)
+
+func TestGRPCInvoke(t *testing.T) {
+	fileDesc := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("greeter.proto"),
+		Package: proto.String("test.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("HelloRequest"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("name"), JsonName: proto.String("name"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				},
+			},
+			{
+				Name: proto.String("HelloReply"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("message"), JsonName: proto.String("message"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+					{Name: proto.String("token"), JsonName: proto.String("token"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				},
+			},
+		},
+		Service: []*descriptorpb.ServiceDescriptorProto{
+			{
+				Name: proto.String("Greeter"),
+				Method: []*descriptorpb.MethodDescriptorProto{
+					{Name: proto.String("SayHello"), InputType: proto.String(".test.v1.HelloRequest"), OutputType: proto.String(".test.v1.HelloReply")},
+				},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	rawDescriptorSet, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{fileDesc}})
+	assert.NilError(t, err)
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "greeter.pb"), rawDescriptorSet, 0o600))
+
+	file, err := protodesc.NewFile(fileDesc, nil)
+	assert.NilError(t, err)
+	method := file.Services().Get(0).Methods().Get(0)
+
+	server := grpc.NewServer()
+	server.RegisterService(&grpc.ServiceDesc{
+		ServiceName: "test.v1.Greeter",
+		HandlerType: (*any)(nil),
+		Methods: []grpc.MethodDesc{
+			{
+				MethodName: "SayHello",
+				Handler: func(_ any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+					input := dynamicpb.NewMessage(method.Input())
+					if err := dec(input); err != nil {
+						return nil, err
+					}
+
+					name := input.Get(method.Input().Fields().ByName("name")).String()
+					if name == "" {
+						return nil, status.Error(codes.InvalidArgument, "name is required")
+					}
+
+					var token string
+					if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get("authorization")) > 0 {
+						token = md.Get("authorization")[0]
+					}
+
+					output := dynamicpb.NewMessage(method.Output())
+					output.Set(method.Output().Fields().ByName("message"), protoreflect.ValueOfString("Hello "+name))
+					output.Set(method.Output().Fields().ByName("token"), protoreflect.ValueOfString(token))
+
+					return output, nil
+				},
+			},
+		},
+	}, struct{}{})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer server.Stop()
+
+	clients := NewGRPCClients(dir)
+	defer clients.Close()
+
+	um := NewUpstreamManager(http.DefaultClient, &configuration.Configuration{})
+	um.SetGRPCClients(clients)
+
+	sendRequest := func(t *testing.T, body string) (int, map[string]any) {
+		t.Helper()
+
+		request := &RetryableRequest{
+			RawRequest: &rest.Request{
+				Method:   http.MethodPost,
+				Protocol: rest.ProtocolGRPC,
+				GRPC: &rest.GRPCRequest{
+					DescriptorSet: "greeter.pb",
+					Service:       "test.v1.Greeter",
+					Method:        "SayHello",
+				},
+			},
+			URL: url.URL{
+				Scheme: "http",
+				Host:   listener.Addr().String(),
+				Path:   "/test.v1.Greeter/SayHello",
+			},
+			Headers: http.Header{
+				"Authorization": []string{"Bearer secret"},
+			},
+			ContentType: rest.ContentTypeJSON,
+			Body:        []byte(body),
+		}
+
+		resp, cancel, err := um.ExecuteRequest(context.Background(), request, "")
+		assert.NilError(t, err)
+		defer cancel()
+		defer resp.Body.Close()
+
+		assert.Equal(t, rest.ContentTypeJSON, resp.Header.Get(rest.ContentTypeHeader))
+		rawBody, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+
+		var result map[string]any
+		assert.NilError(t, json.Unmarshal(rawBody, &result))
+
+		return resp.StatusCode, result
+	}
+
+	t.Run("success", func(t *testing.T) {
+		statusCode, result := sendRequest(t, `{"name": "Alice"}`)
+		assert.Equal(t, http.StatusOK, statusCode)
+		assert.DeepEqual(t, map[string]any{
+			"message": "Hello Alice",
+			"token":   "Bearer secret",
+		}, result)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		statusCode, result := sendRequest(t, `{}`)
+		assert.Equal(t, http.StatusBadRequest, statusCode)
+		assert.DeepEqual(t, map[string]any{
+			"code":    float64(codes.InvalidArgument),
+			"status":  "InvalidArgument",
+			"message": "name is required",
+		}, result)
+	})
+
+	t.Run("invalid_arguments", func(t *testing.T) {
+		_, err := clients.Invoke(&http.Request{
+			URL:  &url.URL{Scheme: "http", Host: listener.Addr().String()},
+			Body: io.NopCloser(strings.NewReader(`{"unknown": true}`)),
+		}, &rest.GRPCRequest{
+			DescriptorSet: "greeter.pb",
+			Service:       "test.v1.Greeter",
+			Method:        "SayHello",
+		})
+		assert.ErrorContains(t, err, "failed to transcode arguments to the gRPC request message")
+	})
+}
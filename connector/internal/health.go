@@ -0,0 +1,239 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"slices"
+	"sync"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/version"
+	"github.com/hasura/ndc-sdk-go/connector"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// ServerHealthStatus represents the result of active health checks of a server.
+type ServerHealthStatus struct {
+	Namespace     string     `json:"namespace"`
+	ServerID      string     `json:"serverId"`
+	Healthy       bool       `json:"healthy"`
+	LastCheckedAt *time.Time `json:"lastCheckedAt,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// serverHealthCheck probes the health endpoint of a server and holds its health status.
+// The server is healthy until the number of consecutive failed probes reaches the threshold.
+type serverHealthCheck struct {
+	path               string
+	interval           time.Duration
+	timeout            time.Duration
+	unhealthyThreshold int
+
+	failures      int
+	healthy       bool
+	lastCheckedAt time.Time
+	lastError     string
+	lock          sync.RWMutex
+}
+
+func newServerHealthCheck(config *rest.HealthCheckConfig) *serverHealthCheck {
+	return &serverHealthCheck{
+		path:               config.Path,
+		interval:           config.GetInterval(),
+		timeout:            config.GetTimeout(),
+		unhealthyThreshold: config.GetUnhealthyThreshold(),
+		healthy:            true,
+	}
+}
+
+// IsHealthy checks if the server is healthy.
+func (shc *serverHealthCheck) IsHealthy() bool {
+	if shc == nil {
+		return true
+	}
+
+	shc.lock.RLock()
+	defer shc.lock.RUnlock()
+
+	return shc.healthy
+}
+
+// Record updates the health status with the result of the probe. Returns true if the status is changed.
+func (shc *serverHealthCheck) Record(err error) bool {
+	shc.lock.Lock()
+	defer shc.lock.Unlock()
+
+	previous := shc.healthy
+	shc.lastCheckedAt = time.Now()
+
+	if err == nil {
+		shc.failures = 0
+		shc.healthy = true
+		shc.lastError = ""
+	} else {
+		shc.failures++
+		shc.lastError = err.Error()
+		if shc.failures >= shc.unhealthyThreshold {
+			shc.healthy = false
+		}
+	}
+
+	return previous != shc.healthy
+}
+
+// Probe sends a GET request to the health endpoint of the server.
+// The probe fails if the request fails or the response status isn't 2xx.
+func (shc *serverHealthCheck) Probe(ctx context.Context, server Server, httpClient *http.Client, headers map[string]string) error {
+	ctx, cancel := context.WithTimeout(ctx, shc.timeout)
+	defer cancel()
+
+	endpoint := *server.URL
+	endpoint.Path = path.Join("/", endpoint.Path, shc.path)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("User-Agent", "ndc-http/"+version.BuildVersion)
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	for key, value := range server.Headers {
+		if value != "" {
+			req.Header.Set(key, value)
+		}
+	}
+
+	if server.HTTPClient != nil {
+		httpClient = server.HTTPClient
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("health endpoint responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (shc *serverHealthCheck) getStatus(namespace string, serverID string) ServerHealthStatus {
+	shc.lock.RLock()
+	defer shc.lock.RUnlock()
+
+	result := ServerHealthStatus{
+		Namespace: namespace,
+		ServerID:  serverID,
+		Healthy:   shc.healthy,
+		Error:     shc.lastError,
+	}
+
+	if !shc.lastCheckedAt.IsZero() {
+		result.LastCheckedAt = utils.ToPtr(shc.lastCheckedAt)
+	}
+
+	return result
+}
+
+// StartHealthChecks probes health endpoints of servers in background until the context is canceled.
+func (um *UpstreamManager) StartHealthChecks(ctx context.Context) {
+	logger := connector.GetLogger(ctx)
+	um.upstreamsLock.RLock()
+	defer um.upstreamsLock.RUnlock()
+
+	for namespace, upstream := range um.upstreams {
+		for serverID, check := range upstream.healthChecks {
+			server, ok := upstream.servers[serverID]
+			if !ok {
+				continue
+			}
+
+			go um.runHealthCheck(ctx, logger.With(slog.String("namespace", namespace), slog.String("server_id", serverID)), upstream, server, check)
+		}
+	}
+}
+
+func (um *UpstreamManager) runHealthCheck(ctx context.Context, logger *slog.Logger, upstream UpstreamSetting, server Server, check *serverHealthCheck) {
+	httpClient := upstream.httpClient
+	if httpClient == nil {
+		httpClient = um.defaultClient
+	}
+
+	ticker := time.NewTicker(check.interval)
+	defer ticker.Stop()
+
+	for {
+		err := check.Probe(ctx, server, httpClient, upstream.headers)
+		if ctx.Err() != nil {
+			return
+		}
+
+		// only log changes of the health status to avoid noisy logs
+		if check.Record(err) {
+			if err != nil {
+				logger.Warn("upstream server is unhealthy: " + err.Error())
+			} else {
+				logger.Info("upstream server is healthy again")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// GetHealthStatuses returns health statuses of servers which enable active health checks.
+func (um *UpstreamManager) GetHealthStatuses() []ServerHealthStatus {
+	var results []ServerHealthStatus
+	um.upstreamsLock.RLock()
+	defer um.upstreamsLock.RUnlock()
+
+	for _, namespace := range utils.GetSortedKeys(um.upstreams) {
+		upstream := um.upstreams[namespace]
+		for _, serverID := range upstream.getServerIDs() {
+			if check, ok := upstream.healthChecks[serverID]; ok {
+				results = append(results, check.getStatus(namespace, serverID))
+			}
+		}
+	}
+
+	return results
+}
+
+// GetUnhealthyUpstreams returns namespaces of upstreams which don't have any healthy server.
+// Servers without health checks are always healthy.
+func (um *UpstreamManager) GetUnhealthyUpstreams() []string {
+	var results []string
+	um.upstreamsLock.RLock()
+	defer um.upstreamsLock.RUnlock()
+
+	for namespace, upstream := range um.upstreams {
+		if len(upstream.servers) == 0 || len(upstream.healthChecks) == 0 {
+			continue
+		}
+
+		if !slices.ContainsFunc(upstream.getServerIDs(), upstream.isServerHealthy) {
+			results = append(results, namespace)
+		}
+	}
+
+	slices.Sort(results)
+
+	return results
+}
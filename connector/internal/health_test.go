@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestServerHealthCheck(t *testing.T) {
+	var healthy atomic.Bool
+	healthy.Store(true)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/health" || r.Header.Get("X-Api-Key") != "secret" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	serverURL, err := url.Parse(server.URL + "/v1")
+	assert.NilError(t, err)
+
+	check := newServerHealthCheck(&rest.HealthCheckConfig{
+		Path:               "/health",
+		UnhealthyThreshold: utils.ToPtr[uint](2),
+	})
+	assert.Equal(t, 30*time.Second, check.interval)
+	assert.Equal(t, 5*time.Second, check.timeout)
+
+	upstreamServer := Server{
+		URL:     serverURL,
+		Headers: map[string]string{"X-Api-Key": "secret"},
+	}
+	assert.NilError(t, check.Probe(context.TODO(), upstreamServer, http.DefaultClient, nil))
+	assert.Assert(t, !check.Record(nil))
+	assert.Assert(t, check.IsHealthy())
+
+	healthy.Store(false)
+	err = check.Probe(context.TODO(), upstreamServer, http.DefaultClient, nil)
+	assert.ErrorContains(t, err, "health endpoint responded with status 503")
+
+	// the server is unhealthy after consecutive failures reach the threshold
+	assert.Assert(t, !check.Record(err))
+	assert.Assert(t, check.IsHealthy())
+	assert.Assert(t, check.Record(err))
+	assert.Assert(t, !check.IsHealthy())
+
+	status := check.getStatus("test", "primary")
+	assert.Equal(t, "health endpoint responded with status 503", status.Error)
+	assert.Assert(t, !status.Healthy)
+	assert.Assert(t, status.LastCheckedAt != nil)
+
+	assert.Assert(t, check.Record(nil))
+	assert.Assert(t, check.IsHealthy())
+}
+
+func TestUpstreamHealthChecks(t *testing.T) {
+	var primaryHealthy atomic.Bool
+	primary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !primaryHealthy.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer primary.Close()
+
+	secondary := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer secondary.Close()
+
+	primaryURL, err := url.Parse(primary.URL)
+	assert.NilError(t, err)
+	secondaryURL, err := url.Parse(secondary.URL)
+	assert.NilError(t, err)
+
+	setting := UpstreamSetting{
+		servers: map[string]Server{
+			"primary":   {URL: primaryURL},
+			"secondary": {URL: secondaryURL},
+		},
+		serverIDs: []string{"primary", "secondary"},
+		balancer:  newServerBalancer(rest.ServerSelectionRoundRobin),
+		healthChecks: map[string]*serverHealthCheck{
+			"primary":   newServerHealthCheck(&rest.HealthCheckConfig{Path: "/health"}),
+			"secondary": newServerHealthCheck(&rest.HealthCheckConfig{Path: "/health"}),
+		},
+	}
+	for _, check := range setting.healthChecks {
+		check.interval = 20 * time.Millisecond
+	}
+	manager := NewUpstreamManager(http.DefaultClient, nil)
+	manager.upstreams["test"] = setting
+
+	// servers are healthy until the first probe
+	assert.Equal(t, 0, len(manager.GetUnhealthyUpstreams()))
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	manager.StartHealthChecks(ctx)
+
+	assert.Assert(t, waitFor(func() bool {
+		return !setting.isServerHealthy("primary") && !setting.isServerHealthy("secondary")
+	}))
+	assert.DeepEqual(t, []string{"test"}, manager.GetUnhealthyUpstreams())
+	statuses := manager.GetHealthStatuses()
+	assert.Equal(t, 2, len(statuses))
+	assert.Equal(t, "primary", statuses[0].ServerID)
+	assert.Equal(t, "secondary", statuses[1].ServerID)
+
+	// all servers are candidates if every server is unhealthy
+	assert.DeepEqual(t, []string{"primary", "secondary"}, setting.filterHealthyServers([]string{"primary", "secondary"}))
+
+	primaryHealthy.Store(true)
+	assert.Assert(t, waitFor(func() bool {
+		return setting.isServerHealthy("primary")
+	}))
+	assert.Equal(t, 0, len(manager.GetUnhealthyUpstreams()))
+	assert.DeepEqual(t, []string{"primary"}, setting.filterHealthyServers([]string{"primary", "secondary"}))
+
+	// unhealthy servers are excluded from the server selection
+	for range 4 {
+		_, serverID, err := setting.getBaseURLFromServers("test", nil)
+		assert.NilError(t, err)
+		assert.Equal(t, "primary", serverID)
+	}
+}
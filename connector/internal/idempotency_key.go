@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+)
+
+// IdempotencyKeyInjector attaches a generated idempotency key to procedure requests.
+// The key is generated once per operation and stored in request headers, so retries of the request reuse the same key.
+type IdempotencyKeyInjector struct {
+	header   string
+	settings configuration.IdempotencyKeySettings
+}
+
+// NewIdempotencyKeyInjector creates an IdempotencyKeyInjector instance. Returns nil if the setting isn't configured.
+func NewIdempotencyKeyInjector(config *configuration.Configuration) *IdempotencyKeyInjector {
+	if config == nil || config.IdempotencyKey == nil {
+		return nil
+	}
+
+	return &IdempotencyKeyInjector{
+		header:   http.CanonicalHeaderKey(config.IdempotencyKey.GetHeader()),
+		settings: *config.IdempotencyKey,
+	}
+}
+
+// Inject attaches the idempotency key to requests of the procedure if they match the setting.
+// Keys which already exist in request headers, e.g. forwarded from the client, are kept.
+func (iki *IdempotencyKeyInjector) Inject(runtimeSchema *configuration.NDCHttpRuntimeSchema, operationName string, requests []*RetryableRequest) {
+	if iki == nil || runtimeSchema == nil || runtimeSchema.NDCHttpSchema == nil {
+		return
+	}
+
+	if _, ok := runtimeSchema.Procedures[operationName]; !ok {
+		return
+	}
+
+	iki.inject(operationName, requests)
+}
+
+// InjectRaw attaches the idempotency key to requests of the raw sendHttpRequest procedure if they match the setting.
+func (iki *IdempotencyKeyInjector) InjectRaw(requests []*RetryableRequest) {
+	if iki == nil {
+		return
+	}
+
+	iki.inject(ProcedureSendHTTPRequest, requests)
+}
+
+func (iki *IdempotencyKeyInjector) inject(operationName string, requests []*RetryableRequest) {
+	var key string
+	for _, req := range requests {
+		if req.RawRequest == nil || !iki.settings.Matches(operationName, req.RawRequest.Method) {
+			continue
+		}
+
+		if req.Headers == nil {
+			req.Headers = http.Header{}
+		}
+
+		if req.Headers.Get(iki.header) != "" {
+			continue
+		}
+
+		if key == "" {
+			key = uuid.NewString()
+		}
+
+		req.Headers.Set(iki.header, key)
+	}
+}
@@ -0,0 +1,118 @@
+package internal
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestIdempotencyKeyInjector(t *testing.T) {
+	assert.Assert(t, NewIdempotencyKeyInjector(&configuration.Configuration{}) == nil)
+
+	runtimeSchema := &configuration.NDCHttpRuntimeSchema{
+		NDCHttpSchema: &rest.NDCHttpSchema{
+			Functions: map[string]rest.OperationInfo{
+				"findPets": {},
+			},
+			Procedures: map[string]rest.OperationInfo{
+				"addPet":    {},
+				"updatePet": {},
+				"createJob": {},
+			},
+		},
+	}
+
+	newRequests := func(method string, count int) []*RetryableRequest {
+		results := make([]*RetryableRequest, count)
+		for i := range results {
+			results[i] = &RetryableRequest{
+				RawRequest: &rest.Request{Method: method},
+				Headers:    http.Header{},
+			}
+		}
+
+		return results
+	}
+
+	t.Run("default", func(t *testing.T) {
+		injector := NewIdempotencyKeyInjector(&configuration.Configuration{
+			IdempotencyKey: &configuration.IdempotencyKeySettings{},
+		})
+
+		requests := newRequests("post", 2)
+		injector.Inject(runtimeSchema, "addPet", requests)
+		key := requests[0].Headers.Get("Idempotency-Key")
+		assert.Assert(t, key != "")
+		// distributed requests of the same operation share the key
+		assert.Equal(t, key, requests[1].Headers.Get("Idempotency-Key"))
+
+		// the key is generated per operation
+		nextRequests := newRequests("post", 1)
+		injector.Inject(runtimeSchema, "addPet", nextRequests)
+		assert.Assert(t, nextRequests[0].Headers.Get("Idempotency-Key") != key)
+
+		requests = newRequests("put", 1)
+		injector.Inject(runtimeSchema, "updatePet", requests)
+		assert.Equal(t, "", requests[0].Headers.Get("Idempotency-Key"))
+
+		// functions are never injected
+		requests = newRequests("post", 1)
+		injector.Inject(runtimeSchema, "findPets", requests)
+		assert.Equal(t, "", requests[0].Headers.Get("Idempotency-Key"))
+
+		// forwarded keys are kept
+		requests = newRequests("post", 1)
+		requests[0].Headers.Set("Idempotency-Key", "client-key")
+		injector.Inject(runtimeSchema, "addPet", requests)
+		assert.Equal(t, "client-key", requests[0].Headers.Get("Idempotency-Key"))
+	})
+
+	t.Run("custom", func(t *testing.T) {
+		injector := NewIdempotencyKeyInjector(&configuration.Configuration{
+			IdempotencyKey: &configuration.IdempotencyKeySettings{
+				Header:     "X-Idempotency-Key",
+				Methods:    []string{"PATCH"},
+				Operations: []string{"create*"},
+			},
+		})
+
+		requests := newRequests("put", 1)
+		injector.Inject(runtimeSchema, "createJob", requests)
+		assert.Assert(t, requests[0].Headers.Get("X-Idempotency-Key") != "")
+
+		requests = newRequests("patch", 1)
+		injector.Inject(runtimeSchema, "updatePet", requests)
+		assert.Assert(t, requests[0].Headers.Get("X-Idempotency-Key") != "")
+
+		// POST isn't the default method if methods or operations are set
+		requests = newRequests("post", 1)
+		injector.Inject(runtimeSchema, "addPet", requests)
+		assert.Equal(t, "", requests[0].Headers.Get("X-Idempotency-Key"))
+	})
+	t.Run("raw", func(t *testing.T) {
+		config := &configuration.Configuration{
+			IdempotencyKey: &configuration.IdempotencyKeySettings{},
+		}
+		um := NewUpstreamManager(nil, config)
+		um.SetIdempotencyKeyInjector(NewIdempotencyKeyInjector(config))
+
+		buildRawRequest := func(method string) *RetryableRequest {
+			results, err := um.BuildRawRequests(schema.MutationOperation{
+				Type:      schema.MutationOperationProcedure,
+				Name:      ProcedureSendHTTPRequest,
+				Arguments: []byte(`{"url": "http://localhost:1234/pets", "method": "` + method + `", "body": {"name": "dog"}}`),
+			})
+			assert.NilError(t, err)
+			assert.Equal(t, 1, len(results.Requests))
+
+			return results.Requests[0]
+		}
+
+		assert.Assert(t, buildRawRequest("post").Headers.Get("Idempotency-Key") != "")
+		assert.Equal(t, "", buildRawRequest("put").Headers.Get("Idempotency-Key"))
+	})
+}
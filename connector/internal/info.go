@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+const (
+	FunctionConnectorInfo         string = "_connectorInfo"
+	objectTypeConnectorInfo       string = "ConnectorInfo"
+	objectTypeConnectorSchemaInfo string = "ConnectorSchemaInfo"
+	objectTypeConnectorFailedInfo string = "ConnectorFailedSchemaInfo"
+)
+
+// ConnectorInfo represents the build information of the connector and loaded schemas
+type ConnectorInfo struct {
+	Version       string                      `json:"version"`
+	Schemas       []ConnectorSchemaInfo       `json:"schemas"`
+	FailedSchemas []ConnectorFailedSchemaInfo `json:"failedSchemas"`
+}
+
+// ConnectorFailedSchemaInfo represents a schema file which failed to load if continueOnError is enabled
+type ConnectorFailedSchemaInfo struct {
+	Name   string   `json:"name"`
+	Errors []string `json:"errors"`
+}
+
+// ConnectorSchemaInfo represents the information of a loaded schema file
+type ConnectorSchemaInfo struct {
+	Name        string     `json:"name"`
+	Version     *string    `json:"version"`
+	Hash        *string    `json:"hash"`
+	ConvertedAt *time.Time `json:"convertedAt"`
+}
+
+// NewConnectorInfo creates the connector information from the build version, loaded and failed schemas.
+func NewConnectorInfo(version string, metadata MetadataCollection, failedSchemas map[string][]string) ConnectorInfo {
+	result := ConnectorInfo{
+		Version:       version,
+		Schemas:       make([]ConnectorSchemaInfo, len(metadata)),
+		FailedSchemas: make([]ConnectorFailedSchemaInfo, 0, len(failedSchemas)),
+	}
+
+	for _, name := range utils.GetSortedKeys(failedSchemas) {
+		result.FailedSchemas = append(result.FailedSchemas, ConnectorFailedSchemaInfo{
+			Name:   name,
+			Errors: failedSchemas[name],
+		})
+	}
+
+	for i, meta := range metadata {
+		item := ConnectorSchemaInfo{
+			Name:        meta.Name,
+			ConvertedAt: meta.ConvertedAt,
+		}
+
+		if meta.Hash != "" {
+			item.Hash = &meta.Hash
+		}
+
+		if meta.NDCHttpSchema != nil && meta.Settings != nil && meta.Settings.Version != "" {
+			item.Version = &meta.Settings.Version
+		}
+
+		result.Schemas[i] = item
+	}
+
+	return result
+}
+
+// ApplyConnectorInfoSchema adds the _connectorInfo function and related types to the schema.
+func ApplyConnectorInfoSchema(input *schema.SchemaResponse, forwardHeaderConfig configuration.ForwardHeadersSettings) {
+	if _, ok := input.ScalarTypes[string(rest.ScalarTimestampTZ)]; !ok {
+		input.ScalarTypes[string(rest.ScalarTimestampTZ)] = schema.ScalarType{
+			AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+			ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+			Representation:      schema.NewTypeRepresentationTimestampTZ().Encode(),
+		}
+	}
+
+	input.ObjectTypes[objectTypeConnectorSchemaInfo] = schema.ObjectType{
+		Description: utils.ToPtr("Information of a loaded schema file"),
+		Fields: schema.ObjectTypeFields{
+			"name": {
+				Description: utils.ToPtr("The name of the schema file"),
+				Type:        schema.NewNamedType(string(rest.ScalarString)).Encode(),
+			},
+			"version": {
+				Description: utils.ToPtr("The version of the API spec"),
+				Type:        schema.NewNullableNamedType(string(rest.ScalarString)).Encode(),
+			},
+			"hash": {
+				Description: utils.ToPtr("The SHA-256 hash of the source spec content"),
+				Type:        schema.NewNullableNamedType(string(rest.ScalarString)).Encode(),
+			},
+			"convertedAt": {
+				Description: utils.ToPtr("The time when the source spec was converted"),
+				Type:        schema.NewNullableNamedType(string(rest.ScalarTimestampTZ)).Encode(),
+			},
+		},
+	}
+
+	input.ObjectTypes[objectTypeConnectorFailedInfo] = schema.ObjectType{
+		Description: utils.ToPtr("Information of a schema file which failed to load"),
+		Fields: schema.ObjectTypeFields{
+			"name": {
+				Description: utils.ToPtr("The name of the schema file"),
+				Type:        schema.NewNamedType(string(rest.ScalarString)).Encode(),
+			},
+			"errors": {
+				Description: utils.ToPtr("Error messages of the schema file"),
+				Type:        schema.NewArrayType(schema.NewNamedType(string(rest.ScalarString))).Encode(),
+			},
+		},
+	}
+
+	input.ObjectTypes[objectTypeConnectorInfo] = schema.ObjectType{
+		Description: utils.ToPtr("Build information of the connector and loaded schemas"),
+		Fields: schema.ObjectTypeFields{
+			"version": {
+				Description: utils.ToPtr("The build version of the connector"),
+				Type:        schema.NewNamedType(string(rest.ScalarString)).Encode(),
+			},
+			"schemas": {
+				Description: utils.ToPtr("Loaded schema files"),
+				Type:        schema.NewArrayType(schema.NewNamedType(objectTypeConnectorSchemaInfo)).Encode(),
+			},
+			"failedSchemas": {
+				Description: utils.ToPtr("Schema files which failed to load if continueOnError is enabled"),
+				Type:        schema.NewArrayType(schema.NewNamedType(objectTypeConnectorFailedInfo)).Encode(),
+			},
+		},
+	}
+
+	fn := schema.FunctionInfo{
+		Name:        FunctionConnectorInfo,
+		Description: utils.ToPtr("Get the build version of the connector and loaded schema versions"),
+		Arguments:   map[string]schema.ArgumentInfo{},
+		ResultType:  schema.NewNamedType(objectTypeConnectorInfo).Encode(),
+	}
+
+	if forwardHeaderConfig.ArgumentField != nil && *forwardHeaderConfig.ArgumentField != "" {
+		fn.Arguments[*forwardHeaderConfig.ArgumentField] = configuration.NewHeadersArgumentInfo().ArgumentInfo
+	}
+
+	input.Functions = append(input.Functions, fn)
+}
@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// decryptKMSDataKey decrypts the ciphertext blob of the data key with the Decrypt action of AWS KMS.
+func decryptKMSDataKey(ctx context.Context, httpClient *http.Client, settings *rest.FieldEncryptionKMSConfig, ciphertextBlob string) ([]byte, error) {
+	var credentials restUtils.S3Credentials
+	var keyID string
+	for _, field := range []struct {
+		name       string
+		value      *utils.EnvString
+		defaultEnv string
+		target     *string
+	}{
+		{"keyId", settings.KeyID, "", &keyID},
+		{"accessKeyId", settings.AccessKeyID, "AWS_ACCESS_KEY_ID", &credentials.AccessKeyID},
+		{"secretAccessKey", settings.SecretAccessKey, "AWS_SECRET_ACCESS_KEY", &credentials.SecretAccessKey},
+		{"sessionToken", settings.SessionToken, "AWS_SESSION_TOKEN", &credentials.SessionToken},
+		{"region", settings.Region, "AWS_REGION", &credentials.Region},
+		{"endpoint", settings.Endpoint, "AWS_ENDPOINT_URL_KMS", &credentials.Endpoint},
+	} {
+		if field.value == nil {
+			if field.defaultEnv != "" {
+				*field.target = os.Getenv(field.defaultEnv)
+			}
+
+			continue
+		}
+
+		value, err := field.value.GetOrDefault("")
+		if err != nil {
+			return nil, fmt.Errorf("kms.%s: %w", field.name, err)
+		}
+
+		*field.target = value
+	}
+
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, errors.New("kms: both the access key ID and the secret access key are required")
+	}
+
+	region := credentials.Region
+	// arn:aws:kms:<region>:<account>:key/<id>
+	if arnParts := strings.Split(keyID, ":"); len(arnParts) > 4 && arnParts[0] == "arn" && arnParts[3] != "" {
+		region = arnParts[3]
+	}
+
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := credentials.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://kms.%s.amazonaws.com", region)
+	}
+
+	input := map[string]any{
+		"CiphertextBlob": ciphertextBlob,
+	}
+	if keyID != "" {
+		input["KeyId"] = keyID
+	}
+	if len(settings.EncryptionContext) > 0 {
+		input["EncryptionContext"] = settings.EncryptionContext
+	}
+
+	payload, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return nil, fmt.Errorf("kms: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "TrentService.Decrypt")
+	restUtils.SignAWSRequest(req, &credentials, region, "kms", payload, time.Now().UTC())
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("kms: %w", err)
+	}
+
+	defer resp.Body.Close()
+
+	// response bodies of errors aren't returned because they may contain sensitive data
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("kms: failed to decrypt the data key, AWS KMS responded with the status %s", resp.Status)
+	}
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("kms: %w", err)
+	}
+
+	var result struct {
+		Plaintext string `json:"Plaintext"`
+	}
+
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return nil, fmt.Errorf("kms: failed to decode the AWS KMS response: %w", err)
+	}
+
+	key, err := base64.StdEncoding.DecodeString(result.Plaintext)
+	if err != nil {
+		return nil, fmt.Errorf("kms: failed to decode the plaintext data key: %w", err)
+	}
+
+	return key, nil
+}
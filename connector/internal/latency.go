@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+)
+
+const (
+	// the weight of the latest sample in the exponential moving average
+	latencyEWMAWeight = 0.3
+	// the probability to select a random server to refresh latencies of slower servers
+	latencyExplorationRate = 0.1
+	// the minimum latency recorded when the request fails, so broken servers are deprioritized
+	latencyErrorPenalty = 5 * time.Second
+)
+
+// serverLatencyTracker tracks the exponential weighted moving average (EWMA) latency of servers.
+type serverLatencyTracker struct {
+	latencies map[string]float64
+	lock      sync.RWMutex
+}
+
+func newServerLatencyTracker() *serverLatencyTracker {
+	return &serverLatencyTracker{
+		latencies: make(map[string]float64),
+	}
+}
+
+// Record adds a latency sample of the server.
+func (slt *serverLatencyTracker) Record(serverID string, duration time.Duration, err error) {
+	if slt == nil {
+		return
+	}
+
+	if err != nil && duration < latencyErrorPenalty {
+		duration = latencyErrorPenalty
+	}
+
+	slt.lock.Lock()
+	defer slt.lock.Unlock()
+
+	value := float64(duration)
+	if current, ok := slt.latencies[serverID]; ok {
+		value = latencyEWMAWeight*value + (1-latencyEWMAWeight)*current
+	}
+
+	slt.latencies[serverID] = value
+}
+
+// Get returns the average latency of the server.
+func (slt *serverLatencyTracker) Get(serverID string) (time.Duration, bool) {
+	slt.lock.RLock()
+	defer slt.lock.RUnlock()
+
+	value, ok := slt.latencies[serverID]
+
+	return time.Duration(value), ok
+}
+
+// Select returns the index of the fastest server.
+// Servers without any sample are selected first. Sometimes a random server is selected to explore latencies of other servers.
+func (slt *serverLatencyTracker) Select(serverIDs []string) int {
+	if len(serverIDs) < 2 {
+		return 0
+	}
+
+	if rand.Float64() < latencyExplorationRate {
+		return rand.IntN(len(serverIDs))
+	}
+
+	slt.lock.RLock()
+	defer slt.lock.RUnlock()
+
+	result := 0
+	minLatency := -1.0
+	for i, serverID := range serverIDs {
+		latency, ok := slt.latencies[serverID]
+		if !ok {
+			return i
+		}
+
+		if minLatency < 0 || latency < minLatency {
+			result = i
+			minLatency = latency
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,94 @@
+package internal
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+// limitedReadCloser returns an error if the number of read bytes exceeds the limit.
+type limitedReadCloser struct {
+	io.ReadCloser
+
+	remaining int64
+	limit     int64
+}
+
+func newLimitedReadCloser(reader io.ReadCloser, limit int64) *limitedReadCloser {
+	return &limitedReadCloser{
+		ReadCloser: reader,
+		remaining:  limit,
+		limit:      limit,
+	}
+}
+
+// Read implements io.Reader.
+func (lrc *limitedReadCloser) Read(p []byte) (int, error) {
+	if lrc.remaining < 0 {
+		return 0, lrc.error()
+	}
+
+	// read one more byte than the remaining size to detect the overflow
+	if int64(len(p)) > lrc.remaining+1 {
+		p = p[:lrc.remaining+1]
+	}
+
+	n, err := lrc.ReadCloser.Read(p)
+	lrc.remaining -= int64(n)
+	if lrc.remaining < 0 {
+		return n + int(lrc.remaining), lrc.error()
+	}
+
+	return n, err
+}
+
+// Exceeded checks if the number of read bytes exceeds the limit.
+func (lrc *limitedReadCloser) Exceeded() bool {
+	return lrc.remaining < 0
+}
+
+func (lrc *limitedReadCloser) error() *UpstreamError {
+	return newResponseTooLargeError(lrc.limit, -1)
+}
+
+// newResponseTooLargeError creates an error of the response body which exceeds the size limit.
+// The content length is unknown if negative.
+func newResponseTooLargeError(limit int64, contentLength int64) *UpstreamError {
+	details := map[string]any{
+		"max_response_bytes": limit,
+	}
+	if contentLength >= 0 {
+		details["content_length"] = contentLength
+	}
+
+	return NewUpstreamError(ErrorCategoryDecode, fmt.Sprintf("the response body exceeds the limit of %d bytes", limit), details)
+}
+
+// deadlineReadCloser returns an error if the reader is still being read after the deadline.
+type deadlineReadCloser struct {
+	io.ReadCloser
+
+	deadline time.Time
+	duration time.Duration
+}
+
+func newDeadlineReadCloser(reader io.ReadCloser, duration time.Duration) *deadlineReadCloser {
+	return &deadlineReadCloser{
+		ReadCloser: reader,
+		deadline:   time.Now().Add(duration),
+		duration:   duration,
+	}
+}
+
+// Read implements io.Reader.
+func (drc *deadlineReadCloser) Read(p []byte) (int, error) {
+	if time.Now().After(drc.deadline) {
+		return 0, errDecodeDurationExceeded(drc.duration)
+	}
+
+	return drc.ReadCloser.Read(p)
+}
+
+func errDecodeDurationExceeded(duration time.Duration) error {
+	return fmt.Errorf("decoding the response body exceeds the limit of %d ms", duration.Milliseconds())
+}
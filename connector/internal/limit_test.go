@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLimitedReadCloser(t *testing.T) {
+	reader := newLimitedReadCloser(io.NopCloser(strings.NewReader("hello")), 5)
+	result, err := io.ReadAll(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, "hello", string(result))
+
+	reader = newLimitedReadCloser(io.NopCloser(strings.NewReader("hello world")), 5)
+	result, err = io.ReadAll(reader)
+	assert.ErrorContains(t, err, "exceeds the limit of 5 bytes")
+	assert.Equal(t, "hello", string(result))
+
+	_, err = reader.Read(make([]byte, 10))
+	assert.ErrorContains(t, err, "exceeds the limit of 5 bytes")
+	assert.Assert(t, reader.Exceeded())
+
+	upstreamErr := newResponseTooLargeError(5, 11)
+	assert.Equal(t, ErrorCategoryDecode, upstreamErr.Category)
+	assert.DeepEqual(t, map[string]any{
+		"max_response_bytes": int64(5),
+		"content_length":     int64(11),
+	}, upstreamErr.Details)
+}
+
+func TestDeadlineReadCloser(t *testing.T) {
+	reader := newDeadlineReadCloser(io.NopCloser(bytes.NewReader([]byte("hello"))), time.Second)
+	result, err := io.ReadAll(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, "hello", string(result))
+
+	reader = newDeadlineReadCloser(io.NopCloser(bytes.NewReader([]byte("hello"))), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	_, err = io.ReadAll(reader)
+	assert.ErrorContains(t, err, "exceeds the limit of 1 ms")
+}
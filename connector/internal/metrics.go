@@ -0,0 +1,268 @@
+package internal
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	metricsPrefix          = "ndc_http."
+	operationTagsAttribute = "ndc_http.operation.tags"
+	serverIDAttribute      = "ndc_http.server.id"
+)
+
+// UpstreamMetrics hold metric instruments of requests to upstream services.
+type UpstreamMetrics struct {
+	requestDuration     metric.Float64Histogram
+	requestRetries      metric.Int64Counter
+	requestPayloadSize  metric.Int64Histogram
+	responsePayloadSize metric.Int64Histogram
+	requestErrors       metric.Int64Counter
+	credentialRotations metric.Int64Counter
+}
+
+// NewUpstreamMetrics creates metric instruments for requests to upstream services.
+func NewUpstreamMetrics(meter metric.Meter) (*UpstreamMetrics, error) {
+	requestDuration, err := meter.Float64Histogram(
+		metricsPrefix+"request.duration",
+		metric.WithDescription("Duration of requests to upstream services until response headers are received. Each attempt is recorded with the response status code or the error type"),
+		metric.WithUnit("s"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	requestRetries, err := meter.Int64Counter(
+		metricsPrefix+"request.retries",
+		metric.WithDescription("Retried requests to upstream services. Retries are classified by the error.type attribute of the previous attempt"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	requestPayloadSize, err := meter.Int64Histogram(
+		metricsPrefix+"request.payload_size",
+		metric.WithDescription("Size of request bodies sent to upstream services. Compressed sizes are recorded with the compressed=true attribute"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	responsePayloadSize, err := meter.Int64Histogram(
+		metricsPrefix+"response.payload_size",
+		metric.WithDescription("Size of response bodies received from upstream services. Compressed sizes are recorded with the compressed=true attribute"),
+		metric.WithUnit("By"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	requestErrors, err := meter.Int64Counter(
+		metricsPrefix+"request.errors",
+		metric.WithDescription("Failed requests to upstream services. Failures are classified by the error.type attribute"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	credentialRotations, err := meter.Int64Counter(
+		metricsPrefix+"credential.rotation_needed",
+		metric.WithDescription("Requests which were retried with the secondary credential because the primary credential was rejected"),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return &UpstreamMetrics{
+		requestDuration:     requestDuration,
+		requestRetries:      requestRetries,
+		requestPayloadSize:  requestPayloadSize,
+		responsePayloadSize: responsePayloadSize,
+		requestErrors:       requestErrors,
+		credentialRotations: credentialRotations,
+	}, nil
+}
+
+// RegisterFailedSchemasMetric registers the gauge of schema files which failed to load when continueOnError is enabled.
+// Each failed schema is observed with the value 1 and the schema name attribute.
+func RegisterFailedSchemasMetric(meter metric.Meter, failedSchemas map[string][]string) error {
+	_, err := meter.Int64ObservableGauge(
+		metricsPrefix+"schema.failed",
+		metric.WithDescription("Schema files which failed to load at startup"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			for name := range failedSchemas {
+				observer.Observe(1, metric.WithAttributes(attribute.String("ndc_http.schema.name", name)))
+			}
+
+			return nil
+		}),
+	)
+
+	return err
+}
+
+// RegisterServerStateMetrics registers gauges of server states:
+//   - health statuses of servers which enable active health checks. Healthy servers are observed with the value 1, otherwise 0.
+//   - circuit states of servers which are tracked by the round-robin or weighted server selection.
+//     Servers which are skipped after consecutive failures are observed with the value 1, otherwise 0.
+func RegisterServerStateMetrics(meter metric.Meter, upstreams *UpstreamManager) error {
+	_, err := meter.Int64ObservableGauge(
+		metricsPrefix+"server.health",
+		metric.WithDescription("Health statuses of upstream servers from active health checks"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			for _, status := range upstreams.GetHealthStatuses() {
+				var value int64
+				if status.Healthy {
+					value = 1
+				}
+
+				observer.Observe(value, metric.WithAttributes(
+					attribute.String("db.namespace", status.Namespace),
+					attribute.String(serverIDAttribute, status.ServerID),
+				))
+			}
+
+			return nil
+		}),
+	)
+	if err != nil {
+		return err
+	}
+
+	_, err = meter.Int64ObservableGauge(
+		metricsPrefix+"server.circuit_open",
+		metric.WithDescription("Servers which are skipped by the server selection after consecutive failed requests"),
+		metric.WithInt64Callback(func(_ context.Context, observer metric.Int64Observer) error {
+			for _, state := range upstreams.GetCircuitStates() {
+				var value int64
+				if state.Open {
+					value = 1
+				}
+
+				observer.Observe(value, metric.WithAttributes(
+					attribute.String("db.namespace", state.Namespace),
+					attribute.String(serverIDAttribute, state.ServerID),
+				))
+			}
+
+			return nil
+		}),
+	)
+
+	return err
+}
+
+// RecordRequestDuration records the duration of the request attempt with the response status code or the error type.
+func (um *UpstreamMetrics) RecordRequestDuration(ctx context.Context, duration time.Duration, method string, resp *http.Response, err error, attrs ...attribute.KeyValue) {
+	if um == nil {
+		return
+	}
+
+	attrs = append(attrs, attribute.String("http.request.method", method))
+	if err != nil {
+		attrs = append(attrs, attribute.String("error.type", string(classifyRequestError(err).Category)))
+	} else if resp != nil {
+		attrs = append(attrs, attribute.Int("http.response.status_code", resp.StatusCode))
+	}
+
+	um.requestDuration.Record(ctx, duration.Seconds(), metric.WithAttributes(attrs...))
+}
+
+// RecordRetry counts the retried request with the error category of the previous attempt.
+func (um *UpstreamMetrics) RecordRetry(ctx context.Context, category ErrorCategory, attrs ...attribute.KeyValue) {
+	if um == nil {
+		return
+	}
+
+	um.requestRetries.Add(ctx, 1, metric.WithAttributes(append(attrs, attribute.String("error.type", string(category)))...))
+}
+
+// RecordRequestPayloadSize records the request body size before and after compression.
+// The compressed size is ignored if the encoding is empty.
+func (um *UpstreamMetrics) RecordRequestPayloadSize(ctx context.Context, size int64, compressedSize int64, encoding string, attrs ...attribute.KeyValue) {
+	if um == nil {
+		return
+	}
+
+	recordPayloadSize(ctx, um.requestPayloadSize, size, compressedSize, encoding, attrs)
+}
+
+// RecordResponsePayloadSize records the response body size before and after decompression.
+// The compressed size is ignored if the encoding is empty.
+func (um *UpstreamMetrics) RecordResponsePayloadSize(ctx context.Context, size int64, compressedSize int64, encoding string, attrs ...attribute.KeyValue) {
+	if um == nil {
+		return
+	}
+
+	recordPayloadSize(ctx, um.responsePayloadSize, size, compressedSize, encoding, attrs)
+}
+
+// RecordRequestError counts the failed request with the error category.
+func (um *UpstreamMetrics) RecordRequestError(ctx context.Context, category ErrorCategory, attrs ...attribute.KeyValue) {
+	if um == nil {
+		return
+	}
+
+	um.requestErrors.Add(ctx, 1, metric.WithAttributes(append(attrs, attribute.String("error.type", string(category)))...))
+}
+
+// RecordCredentialRotation counts the request which was retried with the secondary credential.
+func (um *UpstreamMetrics) RecordCredentialRotation(ctx context.Context, accepted bool, attrs ...attribute.KeyValue) {
+	if um == nil {
+		return
+	}
+
+	um.credentialRotations.Add(ctx, 1, metric.WithAttributes(append(attrs, attribute.Bool("ndc_http.credential.secondary_accepted", accepted))...))
+}
+
+func recordPayloadSize(ctx context.Context, histogram metric.Int64Histogram, size int64, compressedSize int64, encoding string, attrs []attribute.KeyValue) {
+	attrs = append(attrs, attribute.String("http.content_encoding", encoding))
+	histogram.Record(ctx, size, metric.WithAttributes(append(attrs, attribute.Bool("compressed", false))...))
+
+	if encoding != "" {
+		histogram.Record(ctx, compressedSize, metric.WithAttributes(append(attrs, attribute.Bool("compressed", true))...))
+	}
+}
+
+// countingReadCloser counts the number of bytes read from the underlying reader.
+type countingReadCloser struct {
+	io.ReadCloser
+
+	count   int64
+	onClose func(count int64)
+	once    sync.Once
+}
+
+func newCountingReadCloser(reader io.ReadCloser, onClose func(count int64)) *countingReadCloser {
+	return &countingReadCloser{
+		ReadCloser: reader,
+		onClose:    onClose,
+	}
+}
+
+// Read implements io.Reader.
+func (crc *countingReadCloser) Read(p []byte) (int, error) {
+	n, err := crc.ReadCloser.Read(p)
+	crc.count += int64(n)
+
+	return n, err
+}
+
+// Close implements io.Closer.
+func (crc *countingReadCloser) Close() error {
+	err := crc.ReadCloser.Close()
+	if crc.onClose != nil {
+		crc.once.Do(func() {
+			crc.onClose(crc.count)
+		})
+	}
+
+	return err
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	"gotest.tools/v3/assert"
+)
+
+func TestCountingReadCloser(t *testing.T) {
+	var closedCount int64
+	calls := 0
+	reader := newCountingReadCloser(io.NopCloser(bytes.NewBufferString("hello world")), func(count int64) {
+		closedCount = count
+		calls++
+	})
+
+	data, err := io.ReadAll(reader)
+	assert.NilError(t, err)
+	assert.Equal(t, "hello world", string(data))
+	assert.NilError(t, reader.Close())
+	assert.NilError(t, reader.Close())
+	assert.Equal(t, int64(11), closedCount)
+	assert.Equal(t, 1, calls)
+}
+
+func TestUpstreamMetrics(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	meter := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)).Meter("test")
+
+	metrics, err := NewUpstreamMetrics(meter)
+	assert.NilError(t, err)
+
+	attrs := []attribute.KeyValue{
+		attribute.String("db.namespace", "petstore"),
+		attribute.String(serverIDAttribute, "primary"),
+	}
+	metrics.RecordRequestDuration(context.TODO(), 100*time.Millisecond, http.MethodGet, &http.Response{StatusCode: http.StatusOK}, nil, attrs...)
+	metrics.RecordRequestDuration(context.TODO(), time.Second, http.MethodGet, nil, context.DeadlineExceeded, attrs...)
+	metrics.RecordRetry(context.TODO(), ErrorCategoryServer, attrs...)
+
+	manager := NewUpstreamManager(http.DefaultClient, nil)
+	manager.upstreams["petstore"] = UpstreamSetting{
+		servers: map[string]Server{
+			"primary":   {},
+			"secondary": {},
+		},
+		serverIDs: []string{"primary", "secondary"},
+		balancer:  newServerBalancer(rest.ServerSelectionRoundRobin),
+	}
+	for range serverUnhealthyThreshold {
+		manager.upstreams["petstore"].balancer.Record("secondary", true)
+	}
+	assert.NilError(t, RegisterServerStateMetrics(meter, manager))
+
+	var data metricdata.ResourceMetrics
+	assert.NilError(t, reader.Collect(context.TODO(), &data))
+
+	results := map[string]metricdata.Aggregation{}
+	for _, scope := range data.ScopeMetrics {
+		for _, m := range scope.Metrics {
+			results[m.Name] = m.Data
+		}
+	}
+
+	duration, ok := results["ndc_http.request.duration"].(metricdata.Histogram[float64])
+	assert.Assert(t, ok)
+	assert.Equal(t, 2, len(duration.DataPoints))
+	for _, point := range duration.DataPoints {
+		serverID, _ := point.Attributes.Value(serverIDAttribute)
+		assert.Equal(t, "primary", serverID.AsString())
+		if status, ok := point.Attributes.Value("http.response.status_code"); ok {
+			assert.Equal(t, int64(http.StatusOK), status.AsInt64())
+		} else {
+			errorType, _ := point.Attributes.Value("error.type")
+			assert.Equal(t, string(ErrorCategoryTimeout), errorType.AsString())
+		}
+	}
+
+	retries, ok := results["ndc_http.request.retries"].(metricdata.Sum[int64])
+	assert.Assert(t, ok)
+	assert.Equal(t, 1, len(retries.DataPoints))
+	assert.Equal(t, int64(1), retries.DataPoints[0].Value)
+
+	circuits, ok := results["ndc_http.server.circuit_open"].(metricdata.Gauge[int64])
+	assert.Assert(t, ok)
+	circuitStates := map[string]int64{}
+	for _, point := range circuits.DataPoints {
+		serverID, _ := point.Attributes.Value(serverIDAttribute)
+		circuitStates[serverID.AsString()] = point.Value
+	}
+	assert.DeepEqual(t, map[string]int64{"primary": 0, "secondary": 1}, circuitStates)
+}
@@ -0,0 +1,145 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+// DefaultMockFixturesDir is the default directory of fixture files in the mock mode, relative to the configuration directory.
+const DefaultMockFixturesDir = "mocks"
+
+// MockResponder serves responses of operations without calling upstream APIs.
+// The response body is resolved from the fixture file of the operation, or the example of the API spec
+// which is overlaid onto generated fake data, so fields without examples still conform to the result type.
+type MockResponder struct {
+	dir             string
+	responseHeaders *configuration.ForwardResponseHeadersSettings
+}
+
+// NewMockResponder creates a MockResponder instance.
+// Fixture files are read from the directory which is relative to the configuration directory.
+func NewMockResponder(config *configuration.Configuration, configDir string, fixturesDir string) *MockResponder {
+	if fixturesDir == "" {
+		fixturesDir = DefaultMockFixturesDir
+	}
+
+	if !filepath.IsAbs(fixturesDir) {
+		fixturesDir = filepath.Join(configDir, fixturesDir)
+	}
+
+	result := &MockResponder{
+		dir: fixturesDir,
+	}
+
+	if config != nil && config.ForwardHeaders.Enabled {
+		result.responseHeaders = config.ForwardHeaders.ResponseHeaders
+	}
+
+	return result
+}
+
+// Dir returns the directory of fixture files.
+func (mr *MockResponder) Dir() string {
+	return mr.dir
+}
+
+// Resolve returns the mock response of the operation.
+func (mr *MockResponder) Resolve(httpSchema *rest.NDCHttpSchema, operationName string, operation *rest.OperationInfo) (any, error) {
+	fixture, ok, err := mr.ReadFixture(operationName)
+	if err != nil {
+		return nil, err
+	}
+
+	var example any
+	if ok {
+		example = fixture
+	} else if operation.Request != nil {
+		example = operation.Request.Response.Example
+	}
+
+	result, err := GenerateFakeData(httpSchema, operationName, operation.ResultType)
+	if err != nil {
+		return nil, err
+	}
+
+	if example == nil {
+		return result, nil
+	}
+
+	// the example is the response body which is wrapped with forwarded headers in the result
+	if mr.responseHeaders != nil {
+		if wrapper, ok := result.(map[string]any); ok {
+			wrapper[mr.responseHeaders.ResultField] = mergeMockExample(wrapper[mr.responseHeaders.ResultField], example)
+
+			return wrapper, nil
+		}
+	}
+
+	return mergeMockExample(result, example), nil
+}
+
+// ReadFixture reads the fixture file of the operation at <dir>/<operationName>.json.
+// The file is read on every request so fixtures can be edited without restarting the connector.
+func (mr *MockResponder) ReadFixture(operationName string) (any, bool, error) {
+	rawBytes, err := os.ReadFile(filepath.Join(mr.dir, operationName+".json"))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, false, nil
+		}
+
+		return nil, false, fmt.Errorf("failed to read the mock fixture of %s: %w", operationName, err)
+	}
+
+	var result any
+	if err := json.Unmarshal(rawBytes, &result); err != nil {
+		return nil, false, fmt.Errorf("failed to decode the mock fixture of %s: %w", operationName, err)
+	}
+
+	return result, true, nil
+}
+
+// overlay the example onto the fake value. Objects are merged recursively so fields without examples keep fake values,
+// and items of example arrays are merged with the first fake item.
+func mergeMockExample(fake any, example any) any {
+	switch exampleValue := example.(type) {
+	case map[string]any:
+		fakeObject, ok := fake.(map[string]any)
+		if !ok {
+			return exampleValue
+		}
+
+		result := make(map[string]any, len(fakeObject)+len(exampleValue))
+		for key, value := range fakeObject {
+			result[key] = value
+		}
+
+		for key, value := range exampleValue {
+			result[key] = mergeMockExample(fakeObject[key], value)
+		}
+
+		return result
+	case []any:
+		fakeArray, ok := fake.([]any)
+		if !ok || len(fakeArray) == 0 {
+			return exampleValue
+		}
+
+		result := make([]any, len(exampleValue))
+		for i, item := range exampleValue {
+			result[i] = mergeMockExample(fakeArray[0], item)
+		}
+
+		return result
+	case nil:
+		return fake
+	default:
+		return exampleValue
+	}
+}
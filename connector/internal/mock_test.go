@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestMockResponder(t *testing.T) {
+	httpSchema := rest.NewNDCHttpSchema()
+	httpSchema.ScalarTypes = schema.SchemaResponseScalarTypes{
+		"Int32":  schema.ScalarType{Representation: schema.NewTypeRepresentationInt32().Encode()},
+		"String": schema.ScalarType{Representation: schema.NewTypeRepresentationString().Encode()},
+	}
+	httpSchema.ObjectTypes = map[string]rest.ObjectType{
+		"Pet": {
+			Fields: map[string]rest.ObjectField{
+				"id": {
+					ObjectField: schema.ObjectField{Type: schema.NewNamedType("Int32").Encode()},
+				},
+				"name": {
+					ObjectField: schema.ObjectField{Type: schema.NewNamedType("String").Encode()},
+				},
+			},
+		},
+	}
+
+	operation := &rest.OperationInfo{
+		Request: &rest.Request{
+			Response: rest.Response{
+				Example: []any{map[string]any{"name": "doggie"}, map[string]any{"name": "kitty"}},
+			},
+		},
+		ResultType: schema.NewArrayType(schema.NewNamedType("Pet")).Encode(),
+	}
+
+	dir := t.TempDir()
+	responder := NewMockResponder(&configuration.Configuration{}, dir, "")
+	assert.Equal(t, filepath.Join(dir, DefaultMockFixturesDir), responder.Dir())
+
+	// fields without examples keep fake values
+	result, err := responder.Resolve(httpSchema, "findPets", operation)
+	assert.NilError(t, err)
+	pets := result.([]any)
+	assert.Equal(t, 2, len(pets))
+	for i, name := range []string{"doggie", "kitty"} {
+		pet := pets[i].(map[string]any)
+		assert.Equal(t, name, pet["name"])
+		_, ok := pet["id"].(int64)
+		assert.Assert(t, ok)
+	}
+
+	// the fixture file takes precedence over the example
+	assert.NilError(t, os.MkdirAll(responder.Dir(), 0o755))
+	assert.NilError(t, os.WriteFile(filepath.Join(responder.Dir(), "findPets.json"), []byte(`[{"id": 1, "name": "fixture"}]`), 0o600))
+	result, err = responder.Resolve(httpSchema, "findPets", operation)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []any{map[string]any{"id": float64(1), "name": "fixture"}}, result)
+
+	assert.NilError(t, os.WriteFile(filepath.Join(responder.Dir(), "findPets.json"), []byte(`{`), 0o600))
+	_, err = responder.Resolve(httpSchema, "findPets", operation)
+	assert.ErrorContains(t, err, "failed to decode the mock fixture of findPets")
+}
@@ -0,0 +1,55 @@
+package internal
+
+import (
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+// normalizeResponse replaces null fields and empty collections of objects in the response with default values,
+// or removes them if configured. Items of arrays are normalized recursively but never removed.
+func normalizeResponse(value any, settings *rest.ResponseNormalizeSettings) any {
+	if settings == nil {
+		return value
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		for key, fieldValue := range v {
+			fieldValue = normalizeResponse(fieldValue, settings)
+			if defaultValue, ok := settings.Defaults[key]; ok && isNullOrEmptyCollection(fieldValue) {
+				fieldValue = defaultValue
+			}
+
+			switch {
+			case fieldValue == nil && settings.StripNulls:
+				delete(v, key)
+			case fieldValue != nil && settings.StripEmptyCollections && isNullOrEmptyCollection(fieldValue):
+				delete(v, key)
+			default:
+				v[key] = fieldValue
+			}
+		}
+
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = normalizeResponse(item, settings)
+		}
+
+		return v
+	default:
+		return value
+	}
+}
+
+func isNullOrEmptyCollection(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	default:
+		return false
+	}
+}
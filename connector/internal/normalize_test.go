@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestNormalizeResponse(t *testing.T) {
+	newValue := func() any {
+		return map[string]any{
+			"id":       1,
+			"name":     nil,
+			"tags":     []any{},
+			"category": map[string]any{},
+			"status":   nil,
+			"photos": []any{
+				map[string]any{"url": nil, "size": 10},
+				nil,
+			},
+		}
+	}
+
+	testCases := []struct {
+		Name     string
+		Settings *rest.ResponseNormalizeSettings
+		Expected any
+	}{
+		{
+			Name:     "disabled",
+			Expected: newValue(),
+		},
+		{
+			Name: "strip_nulls",
+			Settings: &rest.ResponseNormalizeSettings{
+				StripNulls: true,
+			},
+			Expected: map[string]any{
+				"id":       1,
+				"tags":     []any{},
+				"category": map[string]any{},
+				"photos": []any{
+					map[string]any{"size": 10},
+					nil,
+				},
+			},
+		},
+		{
+			Name: "strip_empty_collections",
+			Settings: &rest.ResponseNormalizeSettings{
+				StripEmptyCollections: true,
+			},
+			Expected: map[string]any{
+				"id":     1,
+				"name":   nil,
+				"status": nil,
+				"photos": []any{
+					map[string]any{"url": nil, "size": 10},
+					nil,
+				},
+			},
+		},
+		{
+			Name: "defaults",
+			Settings: &rest.ResponseNormalizeSettings{
+				StripNulls:            true,
+				StripEmptyCollections: true,
+				Defaults: map[string]any{
+					"tags":   []any{"none"},
+					"status": "unknown",
+					"url":    "",
+				},
+			},
+			Expected: map[string]any{
+				"id":     1,
+				"tags":   []any{"none"},
+				"status": "unknown",
+				"photos": []any{
+					map[string]any{"url": "", "size": 10},
+					nil,
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.DeepEqual(t, tc.Expected, normalizeResponse(newValue(), tc.Settings))
+		})
+	}
+}
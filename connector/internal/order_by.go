@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// PushDownOrderBy maps order_by elements of the collection query onto the upstream query parameter
+// if the operation declares order by settings, e.g. $orderby=Name asc,Price desc.
+func (um *UpstreamManager) PushDownOrderBy(results *RequestBuilderResults, orderBy *schema.OrderBy) error {
+	if orderBy == nil || len(orderBy.Elements) == 0 {
+		return nil
+	}
+
+	var settings *rest.OrderBySettings
+	if results.Operation != nil && results.Operation.Request != nil {
+		settings = results.Operation.Request.OrderBy
+	}
+
+	// the connector doesn't sort rows itself, so the order can't be ignored
+	if settings == nil || !results.IsCollection() {
+		return schema.UnprocessableContentError(fmt.Sprintf("the collection %s doesn't support sorting", results.OperationName), nil)
+	}
+
+	value, err := renderOrderBy(settings, orderBy.Elements)
+	if err != nil {
+		return schema.UnprocessableContentError("failed to push down the order by", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	// OData services expect spaces to be encoded as %20
+	query := url.QueryEscape(settings.Parameter) + "=" + strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+	for _, req := range results.Requests {
+		if req.URL.RawQuery == "" {
+			req.URL.RawQuery = query
+		} else {
+			req.URL.RawQuery += "&" + query
+		}
+	}
+
+	return nil
+}
+
+// render order by elements to the parameter value with the style of settings.
+func renderOrderBy(settings *rest.OrderBySettings, elements []schema.OrderByElement) (string, error) {
+	values := make([]string, len(elements))
+	for i, element := range elements {
+		column, err := element.Target.AsColumn()
+		if err != nil {
+			return "", err
+		}
+
+		if len(column.Path) > 0 {
+			return "", errors.New("ordering by columns of relationships isn't supported")
+		}
+
+		fieldPath := strings.Join(append([]string{column.Name}, column.FieldPath...), ".")
+		if !slices.Contains(settings.Fields, fieldPath) {
+			return "", fmt.Errorf("the field %s isn't sortable", fieldPath)
+		}
+
+		switch settings.Style {
+		case rest.OrderBySign:
+			if element.OrderDirection == schema.OrderDirectionDesc {
+				fieldPath = "-" + fieldPath
+			}
+
+			values[i] = fieldPath
+		default:
+			values[i] = strings.ReplaceAll(fieldPath, ".", "/") + " " + string(element.OrderDirection)
+		}
+	}
+
+	return strings.Join(values, ","), nil
+}
@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestPushDownOrderBy(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Style    rest.OrderByStyle
+		Elements []schema.OrderByElement
+		RawQuery string
+		Expected string
+		Error    string
+	}{
+		{
+			Name: "odata",
+			Elements: []schema.OrderByElement{
+				{Target: schema.NewOrderByColumnName("Name").Encode(), OrderDirection: schema.OrderDirectionAsc},
+				{Target: schema.NewOrderByColumn("Address", nil, []string{"City"}).Encode(), OrderDirection: schema.OrderDirectionDesc},
+			},
+			RawQuery: "$top=10",
+			Expected: "$top=10&%24orderby=Name%20asc%2CAddress%2FCity%20desc",
+		},
+		{
+			Name:  "sign",
+			Style: rest.OrderBySign,
+			Elements: []schema.OrderByElement{
+				{Target: schema.NewOrderByColumnName("Name").Encode(), OrderDirection: schema.OrderDirectionAsc},
+				{Target: schema.NewOrderByColumnName("Price").Encode(), OrderDirection: schema.OrderDirectionDesc},
+			},
+			Expected: "%24orderby=Name%2C-Price",
+		},
+		{
+			Name: "unsortable",
+			Elements: []schema.OrderByElement{
+				{Target: schema.NewOrderByColumnName("Id").Encode(), OrderDirection: schema.OrderDirectionAsc},
+			},
+			Error: "the field Id isn't sortable",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			um := NewUpstreamManager(nil, &configuration.Configuration{})
+			results := &RequestBuilderResults{
+				OperationName: "products",
+				Operation: &rest.OperationInfo{
+					Collection: true,
+					Request: &rest.Request{
+						OrderBy: &rest.OrderBySettings{
+							Parameter: "$orderby",
+							Style:     tc.Style,
+							Fields:    []string{"Name", "Price", "Address.City"},
+						},
+					},
+				},
+				Requests: []*RetryableRequest{
+					{
+						URL: url.URL{
+							Scheme:   "https",
+							Host:     "example.com",
+							Path:     "/Products",
+							RawQuery: tc.RawQuery,
+						},
+					},
+				},
+			}
+
+			err := um.PushDownOrderBy(results, &schema.OrderBy{Elements: tc.Elements})
+			if tc.Error != "" {
+				var connectorErr *schema.ConnectorError
+				assert.Assert(t, errors.As(err, &connectorErr))
+				assert.Equal(t, tc.Error, connectorErr.Details["cause"])
+
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, tc.Expected, results.Requests[0].URL.RawQuery)
+		})
+	}
+
+	t.Run("not_supported", func(t *testing.T) {
+		um := NewUpstreamManager(nil, &configuration.Configuration{})
+		results := &RequestBuilderResults{
+			OperationName: "products",
+			Operation: &rest.OperationInfo{
+				Collection: true,
+				Request:    &rest.Request{},
+			},
+		}
+		orderBy := &schema.OrderBy{
+			Elements: []schema.OrderByElement{
+				{Target: schema.NewOrderByColumnName("Name").Encode(), OrderDirection: schema.OrderDirectionAsc},
+			},
+		}
+
+		assert.ErrorContains(t, um.PushDownOrderBy(results, orderBy), "the collection products doesn't support sorting")
+	})
+}
@@ -0,0 +1,337 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// paginationState holds the evaluated pagination of a request.
+type paginationState struct {
+	settings *rest.PaginationSettings
+	// the maximum number of items of the query
+	limit *int
+	// the number of items per page
+	pageSize int
+	offset   int
+	page     int
+}
+
+// ApplyPagination maps the limit and offset of the query onto pagination parameters of upstream requests
+// if the operation declares a pagination strategy.
+func (um *UpstreamManager) ApplyPagination(results *RequestBuilderResults, limit *int, offset *int) error {
+	if results.Operation == nil || results.Operation.Request == nil || results.Operation.Request.Pagination == nil {
+		return nil
+	}
+
+	settings := results.Operation.Request.Pagination
+	state := &paginationState{
+		settings: settings,
+		pageSize: int(settings.PageSize),
+		page:     1,
+	}
+	if settings.FirstPage != nil {
+		state.page = *settings.FirstPage
+	}
+
+	if limit != nil && *limit >= 0 {
+		state.limit = limit
+		if state.pageSize <= 0 || *limit < state.pageSize {
+			state.pageSize = *limit
+		}
+	}
+
+	if offset != nil && *offset > 0 {
+		state.offset = *offset
+	}
+
+	// parameters are appended in order, so the URL is deterministic, e.g. for cache keys and request matching
+	params := [][2]string{}
+	if settings.LimitParameter != "" && state.pageSize > 0 {
+		params = append(params, [2]string{settings.LimitParameter, strconv.Itoa(state.pageSize)})
+	}
+
+	switch settings.Type {
+	case rest.PaginationOffset:
+		if state.offset > 0 {
+			params = append(params, [2]string{settings.OffsetParameter, strconv.Itoa(state.offset)})
+		}
+	case rest.PaginationPage:
+		if state.offset > 0 {
+			if state.pageSize <= 0 || state.offset%state.pageSize != 0 {
+				return schema.UnprocessableContentError("offset must be a multiple of the page size in the page pagination", nil)
+			}
+
+			state.page += state.offset / state.pageSize
+			params = append(params, [2]string{settings.PageParameter, strconv.Itoa(state.page)})
+		}
+	default:
+		if state.offset > 0 {
+			return schema.UnprocessableContentError(fmt.Sprintf("offset isn't supported by the %s pagination", settings.Type), nil)
+		}
+	}
+
+	for _, req := range results.Requests {
+		for _, param := range params {
+			setURLQueryParam(&req.URL, param[0], param[1])
+		}
+	}
+
+	results.pagination = state
+
+	return nil
+}
+
+// CanFetchPages checks if pages of the response can be fetched automatically.
+func (client *HTTPClient) CanFetchPages() bool {
+	state := client.requests.pagination
+
+	return state != nil && state.settings.MaxPages > 1 && !client.requests.HTTPOptions.Distributed
+}
+
+// SendPages fetches pages of the response until the last page, the limit of the query or the maximum number of pages is reached.
+// Items of pages are concatenated into the response body of the first page.
+func (client *HTTPClient) SendPages(ctx context.Context) (any, http.Header, error) {
+	state := client.requests.pagination
+	settings := state.settings
+	request := *client.requests.Requests[0]
+
+	var firstResult any
+	var headers http.Header
+	var items []any
+
+	for i := 0; i < int(settings.MaxPages); i++ {
+		// the request body may be replaced with the compressed one when sending
+		pageRequest := request
+		result, respHeaders, sendErr := client.sendSingle(ctx, &pageRequest, nil, "single")
+		if sendErr != nil {
+			return nil, nil, sendErr
+		}
+
+		payload := client.unwrapHeaderForwardingResponse(result)
+		pageItems, err := getPaginationItems(payload, settings.ItemsField)
+		if err != nil {
+			return nil, nil, NewUpstreamError(ErrorCategoryDecode, err.Error(), nil).ConnectorError()
+		}
+
+		if i == 0 {
+			firstResult = result
+			headers = respHeaders
+		}
+
+		items = append(items, pageItems...)
+		if len(pageItems) == 0 || (state.limit != nil && len(items) >= *state.limit) {
+			break
+		}
+
+		if !state.next(&request, payload, respHeaders, len(pageItems)) {
+			break
+		}
+	}
+
+	if state.limit != nil && len(items) > *state.limit {
+		items = items[:*state.limit]
+	}
+
+	if items == nil {
+		items = []any{}
+	}
+
+	payload := client.unwrapHeaderForwardingResponse(firstResult)
+	if settings.ItemsField == "" {
+		payload = items
+	} else if err := setPaginationItems(payload, settings.ItemsField, items); err != nil {
+		return nil, nil, NewUpstreamError(ErrorCategoryDecode, err.Error(), nil).ConnectorError()
+	}
+
+	if wrapper, ok := firstResult.(map[string]any); ok && client.isHeaderForwardingResponse() {
+		wrapper[client.manager.config.ForwardHeaders.ResponseHeaders.ResultField] = payload
+
+		return wrapper, headers, nil
+	}
+
+	return payload, headers, nil
+}
+
+// move the request to the next page. Returns false if there is no more page.
+func (ps *paginationState) next(request *RetryableRequest, payload any, headers http.Header, count int) bool {
+	settings := ps.settings
+	switch settings.Type {
+	case rest.PaginationOffset:
+		if ps.pageSize > 0 && count < ps.pageSize {
+			return false
+		}
+
+		ps.offset += count
+		setURLQueryParam(&request.URL, settings.OffsetParameter, strconv.Itoa(ps.offset))
+	case rest.PaginationPage:
+		if ps.pageSize > 0 && count < ps.pageSize {
+			return false
+		}
+
+		ps.page++
+		setURLQueryParam(&request.URL, settings.PageParameter, strconv.Itoa(ps.page))
+	case rest.PaginationCursor:
+		cursor, err := getValueByPath(payload, settings.CursorField)
+		if err != nil || cursor == nil || cursor == "" {
+			return false
+		}
+
+		setURLQueryParam(&request.URL, settings.CursorParameter, fmt.Sprint(cursor))
+	case rest.PaginationLink:
+		next := parseNextLink(headers.Values("Link"))
+		if next == "" {
+			return false
+		}
+
+		nextURL, err := request.URL.Parse(next)
+		if err != nil {
+			return false
+		}
+
+		request.URL = *nextURL
+	default:
+		return false
+	}
+
+	return true
+}
+
+// rows of collections can't carry response headers, so they are never wrapped.
+func (client *HTTPClient) isHeaderForwardingResponse() bool {
+	forwardHeaders := client.manager.config.ForwardHeaders
+
+	return forwardHeaders.Enabled && forwardHeaders.ResponseHeaders != nil && !client.requests.IsCollection()
+}
+
+// get the result field of the response if response headers are forwarded.
+func (client *HTTPClient) unwrapHeaderForwardingResponse(result any) any {
+	if !client.isHeaderForwardingResponse() {
+		return result
+	}
+
+	if resultMap, ok := result.(map[string]any); ok {
+		return resultMap[client.manager.config.ForwardHeaders.ResponseHeaders.ResultField]
+	}
+
+	return result
+}
+
+func getPaginationItems(payload any, itemsField string) ([]any, error) {
+	value, err := getValueByPath(payload, itemsField)
+	if err != nil {
+		return nil, err
+	}
+
+	switch items := value.(type) {
+	case nil:
+		return nil, nil
+	case []any:
+		return items, nil
+	default:
+		return nil, fmt.Errorf("expected an array of items at %s, got %T", itemsField, value)
+	}
+}
+
+func setPaginationItems(payload any, itemsField string, items []any) error {
+	segments := strings.Split(itemsField, ".")
+	parent, err := getValueByPath(payload, strings.Join(segments[:len(segments)-1], "."))
+	if err != nil {
+		return err
+	}
+
+	parentMap, ok := parent.(map[string]any)
+	if !ok {
+		return fmt.Errorf("expected an object at %s, got %T", itemsField, parent)
+	}
+
+	parentMap[segments[len(segments)-1]] = items
+
+	return nil
+}
+
+// get the value of the object at the path in dot notation. Returns the value itself if the path is empty.
+func getValueByPath(value any, path string) (any, error) {
+	if path == "" {
+		return value, nil
+	}
+
+	current := value
+	for _, segment := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case nil:
+			return nil, nil
+		case map[string]any:
+			current = v[segment]
+		default:
+			return nil, errors.New(path + ": expected an object")
+		}
+	}
+
+	return current, nil
+}
+
+// find the URL of the next page in Link headers, e.g. <https://api.example.com/items?page=2>; rel="next".
+func parseNextLink(values []string) string {
+	for _, value := range values {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if len(target) < 2 || target[0] != '<' || target[len(target)-1] != '>' {
+				continue
+			}
+
+			for _, param := range parts[1:] {
+				key, rel, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(key), "rel") {
+					continue
+				}
+
+				for _, r := range strings.Fields(strings.Trim(strings.TrimSpace(rel), `"`)) {
+					if strings.EqualFold(r, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+// set the query parameter of the URL. Other parameters are kept as they are without re-encoding.
+func setURLQueryParam(u *url.URL, key string, value string) {
+	param := url.QueryEscape(key) + "=" + url.QueryEscape(value)
+	var params []string
+	replaced := false
+	for _, item := range strings.Split(u.RawQuery, "&") {
+		if item == "" {
+			continue
+		}
+
+		name, _, _ := strings.Cut(item, "=")
+		if unescaped, err := url.QueryUnescape(name); err == nil && unescaped == key {
+			if !replaced {
+				params = append(params, param)
+				replaced = true
+			}
+
+			continue
+		}
+
+		params = append(params, item)
+	}
+
+	if !replaced {
+		params = append(params, param)
+	}
+
+	u.RawQuery = strings.Join(params, "&")
+}
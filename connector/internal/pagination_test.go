@@ -0,0 +1,222 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestPagination(t *testing.T) {
+	const total = 7
+	writeJSON := func(w http.ResponseWriter, value any) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(value)
+	}
+	createItems := func(start int, size int) []any {
+		items := []any{}
+		for i := start; i < start+size && i < total; i++ {
+			items = append(items, map[string]any{"id": float64(i)})
+		}
+
+		return items
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/offset", func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		writeJSON(w, createItems(offset, limit))
+	})
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		size, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		writeJSON(w, map[string]any{
+			"data": createItems(page*size, size),
+		})
+	})
+	mux.HandleFunc("/cursor", func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		next := any(nil)
+		if start+3 < total {
+			next = strconv.Itoa(start + 3)
+		}
+		writeJSON(w, map[string]any{
+			"items": createItems(start, 3),
+			"meta":  map[string]any{"next": next},
+		})
+	})
+	mux.HandleFunc("/link", func(w http.ResponseWriter, r *http.Request) {
+		start, _ := strconv.Atoi(r.URL.Query().Get("since"))
+		if start+3 < total {
+			w.Header().Set("Link", fmt.Sprintf(`</link?since=%d>; rel="next", </link?since=0>; rel="first"`, start+3))
+		}
+		writeJSON(w, createItems(start, 3))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testCases := []struct {
+		Name     string
+		Path     string
+		Settings rest.PaginationSettings
+		Limit    *int
+		Offset   *int
+		Expected any
+	}{
+		{
+			Name: "offset",
+			Path: "/offset",
+			Settings: rest.PaginationSettings{
+				Type:            rest.PaginationOffset,
+				LimitParameter:  "limit",
+				OffsetParameter: "offset",
+				PageSize:        2,
+				MaxPages:        10,
+			},
+			Offset:   utils.ToPtr(1),
+			Expected: createItems(1, total),
+		},
+		{
+			Name: "offset_limit",
+			Path: "/offset",
+			Settings: rest.PaginationSettings{
+				Type:            rest.PaginationOffset,
+				LimitParameter:  "limit",
+				OffsetParameter: "offset",
+				PageSize:        2,
+				MaxPages:        10,
+			},
+			Limit:    utils.ToPtr(3),
+			Expected: createItems(0, 3),
+		},
+		{
+			Name: "page_max_pages",
+			Path: "/page",
+			Settings: rest.PaginationSettings{
+				Type:           rest.PaginationPage,
+				LimitParameter: "per_page",
+				PageParameter:  "page",
+				FirstPage:      utils.ToPtr(0),
+				PageSize:       2,
+				MaxPages:       2,
+				ItemsField:     "data",
+			},
+			Expected: map[string]any{
+				"data": createItems(0, 4),
+			},
+		},
+		{
+			Name: "page_offset",
+			Path: "/page",
+			Settings: rest.PaginationSettings{
+				Type:           rest.PaginationPage,
+				LimitParameter: "per_page",
+				PageParameter:  "page",
+				FirstPage:      utils.ToPtr(0),
+				MaxPages:       10,
+				ItemsField:     "data",
+			},
+			Limit:  utils.ToPtr(2),
+			Offset: utils.ToPtr(4),
+			Expected: map[string]any{
+				"data": createItems(4, 2),
+			},
+		},
+		{
+			Name: "cursor",
+			Path: "/cursor",
+			Settings: rest.PaginationSettings{
+				Type:            rest.PaginationCursor,
+				CursorParameter: "cursor",
+				CursorField:     "meta.next",
+				ItemsField:      "items",
+				MaxPages:        10,
+			},
+			Expected: map[string]any{
+				"items": createItems(0, total),
+				"meta":  map[string]any{"next": "3"},
+			},
+		},
+		{
+			Name: "link",
+			Path: "/link",
+			Settings: rest.PaginationSettings{
+				Type:     rest.PaginationLink,
+				MaxPages: 10,
+			},
+			Limit:    utils.ToPtr(5),
+			Expected: createItems(0, 5),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			um := NewUpstreamManager(http.DefaultClient, &configuration.Configuration{})
+			requestURL, err := url.Parse(server.URL + tc.Path)
+			assert.NilError(t, err)
+
+			results := &RequestBuilderResults{
+				OperationName: "findItems",
+				Operation: &rest.OperationInfo{
+					Request: &rest.Request{
+						Method:     "get",
+						Pagination: &tc.Settings,
+						Response: rest.Response{
+							ContentType: rest.ContentTypeJSON,
+						},
+					},
+				},
+				Requests: []*RetryableRequest{
+					{
+						URL:        *requestURL,
+						Headers:    http.Header{},
+						RawRequest: &rest.Request{Method: "get"},
+					},
+				},
+				HTTPOptions: &HTTPOptions{},
+			}
+
+			assert.NilError(t, um.ApplyPagination(results, tc.Limit, tc.Offset))
+			client := um.CreateHTTPClient(results)
+			assert.Assert(t, client.CanFetchPages())
+
+			result, _, err := client.SendPages(context.TODO())
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.Expected, result)
+		})
+	}
+
+	t.Run("invalid_offset", func(t *testing.T) {
+		um := NewUpstreamManager(http.DefaultClient, &configuration.Configuration{})
+		results := &RequestBuilderResults{
+			Operation: &rest.OperationInfo{
+				Request: &rest.Request{
+					Pagination: &rest.PaginationSettings{
+						Type:            rest.PaginationCursor,
+						CursorParameter: "cursor",
+						CursorField:     "next",
+					},
+				},
+			},
+		}
+
+		assert.ErrorContains(t, um.ApplyPagination(results, nil, utils.ToPtr(2)), "offset isn't supported by the cursor pagination")
+	})
+}
+
+func TestParseNextLink(t *testing.T) {
+	assert.Equal(t, "https://api.example.com/items?page=3", parseNextLink([]string{
+		`<https://api.example.com/items?page=1>; rel="prev", <https://api.example.com/items?page=3>; rel="next"`,
+	}))
+	assert.Equal(t, "", parseNextLink([]string{`<https://api.example.com/items?page=1>; rel="last"`}))
+}
@@ -0,0 +1,111 @@
+package internal
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+// newHTTPClientProxy creates a new HTTP client with a dedicated transport which sends requests through the proxy.
+// The last non-nil config takes precedence, so server settings replace global settings.
+// Returns the base client if there is no setting.
+func newHTTPClientProxy(baseClient *http.Client, configs ...*rest.ProxyConfig) (*http.Client, error) {
+	var config *rest.ProxyConfig
+	for _, c := range configs {
+		if c != nil {
+			config = c
+		}
+	}
+
+	if config == nil {
+		return baseClient, nil
+	}
+
+	settings, err := config.Evaluate()
+	if err != nil {
+		return nil, err
+	}
+
+	baseTransport, ok := baseClient.Transport.(*http.Transport)
+	if !ok {
+		baseTransport, _ = http.DefaultTransport.(*http.Transport)
+	}
+
+	transport := baseTransport.Clone()
+	// the proxy config replaces proxy environment variables, requests are sent directly if the proxy URL is empty
+	transport.Proxy = nil
+	if settings.URL != nil {
+		transport.Proxy = newProxyFunc(settings)
+	}
+
+	return &http.Client{
+		Transport:     transport,
+		CheckRedirect: baseClient.CheckRedirect,
+		Jar:           baseClient.Jar,
+		Timeout:       baseClient.Timeout,
+	}, nil
+}
+
+func newProxyFunc(settings *rest.ProxySettings) func(*http.Request) (*url.URL, error) {
+	return func(req *http.Request) (*url.URL, error) {
+		if matchNoProxy(settings.NoProxy, req.URL) {
+			return nil, nil
+		}
+
+		return settings.URL, nil
+	}
+}
+
+// matchNoProxy checks if the request URL matches any entry of the no-proxy list.
+func matchNoProxy(entries []string, reqURL *url.URL) bool {
+	host := strings.ToLower(reqURL.Hostname())
+	port := reqURL.Port()
+	if port == "" {
+		switch reqURL.Scheme {
+		case "https":
+			port = "443"
+		default:
+			port = "80"
+		}
+	}
+
+	for _, entry := range entries {
+		if entry == "*" {
+			return true
+		}
+
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			if ip := net.ParseIP(host); ip != nil && cidr.Contains(ip) {
+				return true
+			}
+
+			continue
+		}
+
+		entryHost := entry
+		if h, p, err := net.SplitHostPort(entry); err == nil {
+			if p != port {
+				continue
+			}
+			entryHost = h
+		}
+
+		entryHost = strings.Trim(entryHost, "[]")
+		if strings.HasPrefix(entryHost, ".") {
+			if strings.HasSuffix(host, entryHost) || host == entryHost[1:] {
+				return true
+			}
+
+			continue
+		}
+
+		if host == entryHost || strings.HasSuffix(host, "."+entryHost) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,104 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/connector"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// RateLimiter abstracts the state store of rate limiters.
+type RateLimiter interface {
+	// Reserve takes a request slot of the key and returns the duration to wait before sending the request.
+	Reserve(ctx context.Context, key string, settings rest.RateLimitSettings) (time.Duration, error)
+}
+
+// memoryRateLimiter implements the generic cell rate algorithm in memory.
+// The state of each key is the theoretical arrival time of the next request.
+type memoryRateLimiter struct {
+	lock         sync.Mutex
+	arrivalTimes map[string]time.Time
+}
+
+var _ RateLimiter = (*memoryRateLimiter)(nil)
+
+func newMemoryRateLimiter() *memoryRateLimiter {
+	return &memoryRateLimiter{
+		arrivalTimes: make(map[string]time.Time),
+	}
+}
+
+// Reserve takes a request slot of the key and returns the duration to wait before sending the request.
+func (rl *memoryRateLimiter) Reserve(_ context.Context, key string, settings rest.RateLimitSettings) (time.Duration, error) {
+	interval, burst := rateLimitInterval(settings)
+	now := time.Now()
+
+	rl.lock.Lock()
+	defer rl.lock.Unlock()
+
+	arrivalTime := rl.arrivalTimes[key]
+	if arrivalTime.Before(now) {
+		arrivalTime = now
+	}
+
+	nextArrivalTime := arrivalTime.Add(interval)
+	rl.arrivalTimes[key] = nextArrivalTime
+
+	return max(0, nextArrivalTime.Add(-interval*time.Duration(burst)).Sub(now)), nil
+}
+
+// rateLimitInterval returns the emission interval between requests and the burst size.
+func rateLimitInterval(settings rest.RateLimitSettings) (time.Duration, int64) {
+	burst := int64(settings.Burst)
+	if burst <= 0 {
+		burst = 1
+	}
+
+	return time.Duration(float64(time.Second) / settings.RequestsPerSecond), burst
+}
+
+// waitRateLimit blocks until the request is allowed to be sent by the rate limiter of the server.
+// Requests of operations which override the rate limit have their own limiter.
+func (um *UpstreamManager) waitRateLimit(ctx context.Context, request *RetryableRequest) (time.Duration, error) {
+	settings := request.Runtime.RateLimit
+	if settings == nil || settings.RequestsPerSecond <= 0 || um.rateLimiter == nil {
+		return 0, nil
+	}
+
+	key := request.Namespace + ":" + request.ServerID
+	if request.RawRequest.RuntimeSettings != nil && request.RawRequest.RuntimeSettings.RateLimit != nil {
+		key += ":" + request.RawRequest.Method + " " + request.RawRequest.URL
+	}
+
+	wait, err := um.rateLimiter.Reserve(ctx, key, *settings)
+	if err != nil {
+		// the request isn't blocked if the shared state is unavailable
+		connector.GetLogger(ctx).Warn("failed to reserve the rate limit: "+err.Error(), slog.String("namespace", request.Namespace), slog.String("server_id", request.ServerID))
+
+		return 0, nil
+	}
+
+	trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("http.request.rate_limit.wait_ms", wait.Milliseconds()))
+	if wait <= 0 {
+		return 0, nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+		return wait, errRateLimitExceeded
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return wait, ctx.Err()
+	case <-timer.C:
+		return wait, nil
+	}
+}
@@ -0,0 +1,92 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func testRateLimiter(t *testing.T, limiter RateLimiter) {
+	t.Helper()
+
+	ctx := context.Background()
+	settings := rest.RateLimitSettings{
+		RequestsPerSecond: 10,
+		Burst:             2,
+	}
+
+	for range 2 {
+		wait, err := limiter.Reserve(ctx, "pets:dog", settings)
+		assert.NilError(t, err)
+		assert.Equal(t, time.Duration(0), wait)
+	}
+
+	wait, err := limiter.Reserve(ctx, "pets:dog", settings)
+	assert.NilError(t, err)
+	assert.Assert(t, wait > 0 && wait <= 100*time.Millisecond, "unexpected wait duration %s", wait)
+
+	wait, err = limiter.Reserve(ctx, "pets:dog", settings)
+	assert.NilError(t, err)
+	assert.Assert(t, wait > 100*time.Millisecond && wait <= 200*time.Millisecond, "unexpected wait duration %s", wait)
+
+	// other keys have their own limit
+	wait, err = limiter.Reserve(ctx, "pets:cat", settings)
+	assert.NilError(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+}
+
+func TestMemoryRateLimiter(t *testing.T) {
+	testRateLimiter(t, newMemoryRateLimiter())
+}
+
+func TestWaitRateLimit(t *testing.T) {
+	um := NewUpstreamManager(nil, nil)
+	rawRequest := &rest.Request{
+		URL:    "/pet",
+		Method: "get",
+	}
+	request := &RetryableRequest{
+		RawRequest: rawRequest,
+		Namespace:  "petstore",
+		ServerID:   "dog",
+		Runtime: rest.RuntimeSettings{
+			RateLimit: &rest.RateLimitSettings{
+				RequestsPerSecond: 20,
+			},
+		},
+	}
+
+	ctx := context.Background()
+	wait, err := um.waitRateLimit(ctx, request)
+	assert.NilError(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+
+	start := time.Now()
+	wait, err = um.waitRateLimit(ctx, request)
+	assert.NilError(t, err)
+	assert.Assert(t, wait > 0)
+	assert.Assert(t, time.Since(start) >= wait)
+
+	// the wait time is longer than the request timeout
+	timeoutCtx, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+	_, err = um.waitRateLimit(timeoutCtx, request)
+	assert.ErrorIs(t, err, errRateLimitExceeded)
+
+	// operations with rate limit overrides use their own limiter
+	rawRequest.RuntimeSettings = &rest.RuntimeSettings{
+		RateLimit: request.Runtime.RateLimit,
+	}
+	wait, err = um.waitRateLimit(ctx, request)
+	assert.NilError(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+
+	// the rate limit is disabled
+	request.Runtime.RateLimit = nil
+	wait, err = um.waitRateLimit(ctx, request)
+	assert.NilError(t, err)
+	assert.Equal(t, time.Duration(0), wait)
+}
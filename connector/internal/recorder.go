@@ -0,0 +1,298 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
+)
+
+const (
+	defaultRecorderDir         string = "cassettes"
+	defaultCassetteName        string = "default"
+	cassetteBodyEncodingBase64 string = "base64"
+)
+
+var cassetteNameRegex = regexp.MustCompile(`[^\w-]`)
+
+// Cassette holds recorded request and response pairs of an upstream namespace.
+type Cassette struct {
+	Interactions []CassetteInteraction `json:"interactions"`
+}
+
+// CassetteInteraction represents a recorded request and response pair.
+type CassetteInteraction struct {
+	Request  CassetteRequest  `json:"request"`
+	Response CassetteResponse `json:"response"`
+}
+
+// CassetteRequest represents a recorded request. Credentials in the URL and headers are masked.
+type CassetteRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// CassetteResponse represents a recorded response.
+type CassetteResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	// The encoding of the body. Binary bodies are encoded as base64
+	BodyEncoding string `json:"bodyEncoding,omitempty"`
+}
+
+// Recorder records upstream requests and responses to cassette files, or replays recorded responses
+// without calling upstream services. Cassettes are JSON files per namespace in the cassette directory.
+type Recorder struct {
+	mode      configuration.RecorderMode
+	dir       string
+	matchBody bool
+	cassettes map[string]*Cassette
+	// the number of replayed responses of each request, so repeated requests are served in the recorded order
+	cursors map[string]int
+	lock    sync.Mutex
+}
+
+// NewRecorder creates a Recorder instance. Returns nil if the recorder is disabled.
+// Cassettes are loaded in the replay mode and recorded from scratch in the record mode.
+func NewRecorder(config *configuration.Configuration, configDir string) (*Recorder, error) {
+	if config == nil || config.Recorder == nil {
+		return nil, nil
+	}
+
+	dir := config.Recorder.Dir
+	if dir == "" {
+		dir = defaultRecorderDir
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(configDir, dir)
+	}
+
+	recorder := &Recorder{
+		mode:      config.Recorder.Mode,
+		dir:       dir,
+		matchBody: config.Recorder.MatchBody,
+		cassettes: make(map[string]*Cassette),
+		cursors:   make(map[string]int),
+	}
+
+	if recorder.mode == configuration.RecorderModeRecord {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, fmt.Errorf("recorder: failed to create the cassette directory: %w", err)
+		}
+
+		return recorder, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("recorder: %w", err)
+	}
+
+	for _, filePath := range files {
+		rawBytes, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, fmt.Errorf("recorder: failed to read the cassette: %w", err)
+		}
+
+		var cassette Cassette
+		if err := json.Unmarshal(rawBytes, &cassette); err != nil {
+			return nil, fmt.Errorf("recorder: failed to decode the cassette %s: %w", filePath, err)
+		}
+
+		recorder.cassettes[strings.TrimSuffix(filepath.Base(filePath), ".json")] = &cassette
+	}
+
+	return recorder, nil
+}
+
+// SetRecorder sets the recorder of upstream requests.
+func (um *UpstreamManager) SetRecorder(recorder *Recorder) {
+	um.recorder = recorder
+}
+
+// Mode returns the recorder mode.
+func (r *Recorder) Mode() configuration.RecorderMode {
+	return r.mode
+}
+
+// Dir returns the directory of cassette files.
+func (r *Recorder) Dir() string {
+	return r.dir
+}
+
+// Do sends the request and records the response in the record mode,
+// or returns the matched recorded response without sending the request in the replay mode.
+func (r *Recorder) Do(req *http.Request, body []byte, namespace string, send func() (*http.Response, error)) (*http.Response, error) {
+	cassetteName := getCassetteName(namespace)
+	if r.mode == configuration.RecorderModeReplay {
+		return r.replay(req, body, cassetteName)
+	}
+
+	resp, err := send()
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+	interaction := CassetteInteraction{
+		Request: CassetteRequest{
+			Method:  req.Method,
+			URL:     getRecorderRequestURL(req),
+			Headers: maskDebugCaptureHeaders(req.Header),
+			Body:    string(body),
+		},
+		Response: CassetteResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    resp.Header.Clone(),
+		},
+	}
+
+	// cookies are credentials, so masked cookies are replayed
+	for key, values := range interaction.Response.Headers {
+		if isDebugCaptureSensitiveHeader(key) {
+			for i, value := range values {
+				values[i] = restUtils.MaskString(value)
+			}
+		}
+	}
+
+	if utf8.Valid(responseBody) {
+		interaction.Response.Body = string(responseBody)
+	} else {
+		interaction.Response.Body = base64.StdEncoding.EncodeToString(responseBody)
+		interaction.Response.BodyEncoding = cassetteBodyEncodingBase64
+	}
+
+	if err := r.record(cassetteName, interaction); err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (r *Recorder) record(cassetteName string, interaction CassetteInteraction) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	cassette, ok := r.cassettes[cassetteName]
+	if !ok {
+		cassette = &Cassette{}
+		r.cassettes[cassetteName] = cassette
+	}
+
+	cassette.Interactions = append(cassette.Interactions, interaction)
+	rawBytes, err := json.MarshalIndent(cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filepath.Join(r.dir, cassetteName+".json"), rawBytes, 0o600); err != nil {
+		return fmt.Errorf("recorder: failed to write the cassette: %w", err)
+	}
+
+	return nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte, cassetteName string) (*http.Response, error) {
+	requestURL := getRecorderRequestURL(req)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	var matches []CassetteInteraction
+	if cassette, ok := r.cassettes[cassetteName]; ok {
+		for _, interaction := range cassette.Interactions {
+			if interaction.Request.Method == req.Method && interaction.Request.URL == requestURL &&
+				(!r.matchBody || interaction.Request.Body == string(body)) {
+				matches = append(matches, interaction)
+			}
+		}
+	}
+
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("recorder: no recorded interaction in the %s cassette matches %s %s", cassetteName, req.Method, requestURL)
+	}
+
+	// repeated requests are served in the recorded order and the last response is served afterwards
+	cursorKey := strings.Join([]string{cassetteName, req.Method, requestURL}, " ")
+	if r.matchBody {
+		cursorKey += " " + string(body)
+	}
+
+	index := min(r.cursors[cursorKey], len(matches)-1)
+	r.cursors[cursorKey]++
+
+	return matches[index].Response.toHTTPResponse(req)
+}
+
+func (cr CassetteResponse) toHTTPResponse(req *http.Request) (*http.Response, error) {
+	body := []byte(cr.Body)
+	switch cr.BodyEncoding {
+	case "":
+	case cassetteBodyEncodingBase64:
+		var err error
+		body, err = base64.StdEncoding.DecodeString(cr.Body)
+		if err != nil {
+			return nil, fmt.Errorf("recorder: failed to decode the recorded body: %w", err)
+		}
+	default:
+		return nil, errors.New("recorder: unsupported body encoding " + cr.BodyEncoding)
+	}
+
+	header := cr.Headers.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", cr.StatusCode, http.StatusText(cr.StatusCode)),
+		StatusCode:    cr.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
+
+// get the masked path and query string of the request. The host is excluded so cassettes can be replayed against other servers.
+func getRecorderRequestURL(req *http.Request) string {
+	requestURL := cloneURL(req.URL)
+	requestURL.Scheme = ""
+	requestURL.Host = ""
+	requestURL.User = nil
+
+	return maskDebugCaptureURL(requestURL)
+}
+
+func getCassetteName(namespace string) string {
+	if namespace == "" {
+		return defaultCassetteName
+	}
+
+	return cassetteNameRegex.ReplaceAllString(namespace, "_")
+}
@@ -0,0 +1,86 @@
+package internal
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"gotest.tools/v3/assert"
+)
+
+func TestRecorder(t *testing.T) {
+	recorder, err := NewRecorder(&configuration.Configuration{}, ".")
+	assert.NilError(t, err)
+	assert.Assert(t, recorder == nil)
+
+	var count atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abcdefghijklmn")
+		_, _ = w.Write([]byte(`{"count":` + strconv.Itoa(int(count.Add(1))) + `}`))
+	}))
+
+	dir := t.TempDir()
+	recorder, err = NewRecorder(&configuration.Configuration{
+		Recorder: &configuration.RecorderSettings{
+			Mode: configuration.RecorderModeRecord,
+		},
+	}, dir)
+	assert.NilError(t, err)
+	assert.Equal(t, filepath.Join(dir, defaultRecorderDir), recorder.Dir())
+
+	sendRequest := func(recorder *Recorder) (*http.Response, error) {
+		req, err := http.NewRequest(http.MethodGet, server.URL+"/pets?api_key=abcdefghijklmn&status=available", nil)
+		assert.NilError(t, err)
+		req.Header.Set("Authorization", "Bearer abcdefghijklmn")
+
+		return recorder.Do(req, nil, "pet store", func() (*http.Response, error) {
+			return http.DefaultClient.Do(req)
+		})
+	}
+
+	for _, expected := range []string{`{"count":1}`, `{"count":2}`} {
+		resp, err := sendRequest(recorder)
+		assert.NilError(t, err)
+		body, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+		assert.Equal(t, expected, string(body))
+	}
+
+	rawBytes, err := os.ReadFile(filepath.Join(recorder.Dir(), "pet_store.json"))
+	assert.NilError(t, err)
+	// credentials aren't stored in cassettes
+	assert.Assert(t, !strings.Contains(string(rawBytes), "abcdefghijklmn"))
+
+	// responses are replayed in the recorded order without calling the server
+	server.Close()
+	recorder, err = NewRecorder(&configuration.Configuration{
+		Recorder: &configuration.RecorderSettings{
+			Mode: configuration.RecorderModeReplay,
+		},
+	}, dir)
+	assert.NilError(t, err)
+
+	for _, expected := range []string{`{"count":1}`, `{"count":2}`, `{"count":2}`} {
+		resp, err := sendRequest(recorder)
+		assert.NilError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+		body, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+		assert.Equal(t, expected, string(body))
+	}
+	assert.Equal(t, int32(2), count.Load())
+
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/pets", nil)
+	assert.NilError(t, err)
+	_, err = recorder.Do(req, nil, "pet store", nil)
+	assert.ErrorContains(t, err, "no recorded interaction in the pet_store cassette matches POST /pets")
+}
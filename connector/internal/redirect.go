@@ -0,0 +1,61 @@
+package internal
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// newNoRedirectClient copies the HTTP client with the redirect policy which returns the redirection response as is.
+func newNoRedirectClient(httpClient *http.Client) *http.Client {
+	client := *httpClient
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return &client
+}
+
+func (client *HTTPClient) isRedirectResponse() bool {
+	return client.requests.Operation != nil && client.requests.Operation.Request != nil &&
+		client.requests.Operation.Request.Response.Redirect
+}
+
+// evalRedirectResponse returns the status code and the Location header of the response as the RedirectResult object.
+// The response body is discarded.
+func (client *HTTPClient) evalRedirectResponse(resp *http.Response, selection schema.NestedField) (any, http.Header, *UpstreamError) {
+	if resp.Body != nil {
+		_ = resp.Body.Close()
+	}
+
+	result := map[string]any{
+		"status":   resp.StatusCode,
+		"location": nil,
+	}
+
+	// relative locations are resolved from the request URL
+	location, err := resp.Location()
+	switch {
+	case err == nil:
+		result["location"] = location.String()
+	case !errors.Is(err, http.ErrNoLocation):
+		return nil, nil, NewUpstreamError(ErrorCategoryDecode, "failed to parse the Location header", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	var output any = result
+	output = client.createHeaderForwardingResponse(output, resp.Header)
+	if len(selection) == 0 {
+		return output, resp.Header, nil
+	}
+
+	output, err = utils.EvalNestedColumnFields(selection, output)
+	if err != nil {
+		return nil, nil, NewUpstreamError(ErrorCategoryInternal, err.Error(), nil)
+	}
+
+	return output, resp.Header, nil
+}
@@ -0,0 +1,97 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestRedirectResponse(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/consent?state=abc", http.StatusFound)
+	})
+	mux.HandleFunc("/download", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "https://storage.example.com/file.pdf?signature=xyz", http.StatusTemporaryRedirect)
+	})
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true}`))
+	})
+	mux.HandleFunc("/consent", func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the redirect must not be followed")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testCases := []struct {
+		Name     string
+		Path     string
+		Expected any
+	}{
+		{
+			Name: "relative_location",
+			Path: "/authorize",
+			Expected: map[string]any{
+				"status":   http.StatusFound,
+				"location": server.URL + "/consent?state=abc",
+			},
+		},
+		{
+			Name: "absolute_location",
+			Path: "/download",
+			Expected: map[string]any{
+				"status":   http.StatusTemporaryRedirect,
+				"location": "https://storage.example.com/file.pdf?signature=xyz",
+			},
+		},
+		{
+			Name: "no_redirect",
+			Path: "/ok",
+			Expected: map[string]any{
+				"status":   http.StatusOK,
+				"location": nil,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			um := NewUpstreamManager(http.DefaultClient, &configuration.Configuration{})
+			requestURL, err := url.Parse(server.URL + tc.Path)
+			assert.NilError(t, err)
+
+			rawRequest := &rest.Request{
+				Method: "get",
+				Response: rest.Response{
+					ContentType: rest.ContentTypeJSON,
+					Redirect:    true,
+				},
+			}
+			results := &RequestBuilderResults{
+				OperationName: "authorize",
+				Operation: &rest.OperationInfo{
+					Request: rawRequest,
+				},
+				Requests: []*RetryableRequest{
+					{
+						URL:        *requestURL,
+						Headers:    http.Header{},
+						RawRequest: rawRequest,
+					},
+				},
+				HTTPOptions: &HTTPOptions{},
+			}
+
+			result, _, err := um.CreateHTTPClient(results).Send(context.TODO(), nil)
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.Expected, result)
+		})
+	}
+}
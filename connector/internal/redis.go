@@ -0,0 +1,170 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hasura/ndc-http/connector/internal/security"
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/oauth2"
+)
+
+const defaultRedisKeyPrefix = "ndc_http:"
+
+// RedisStore stores shared state of connector replicas in Redis, including cached responses, single-flight locks and OAuth2 tokens.
+type RedisStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+var (
+	_ ResponseCacheStore  = (*RedisStore)(nil)
+	_ SingleFlightLocker  = (*RedisStore)(nil)
+	_ security.TokenStore = (*RedisStore)(nil)
+	_ RateLimiter         = (*RedisStore)(nil)
+)
+
+// rateLimitScript implements the generic cell rate algorithm with the clock of the Redis server,
+// so the rate limit is shared between replicas. Times are in microseconds.
+var rateLimitScript = redis.NewScript(`
+local now = redis.call('TIME')
+now = tonumber(now[1]) * 1000000 + tonumber(now[2])
+local interval = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local arrival = tonumber(redis.call('GET', KEYS[1]) or now)
+if arrival < now then
+  arrival = now
+end
+local next_arrival = arrival + interval
+redis.call('SET', KEYS[1], next_arrival, 'PX', math.ceil((next_arrival - now) / 1000) + 1)
+local wait = next_arrival - burst * interval - now
+if wait < 0 then
+  return 0
+end
+return wait
+`)
+
+// NewRedisStore creates a new RedisStore instance. Returns nil if Redis isn't configured.
+func NewRedisStore(config *configuration.Configuration) (*RedisStore, error) {
+	if config == nil || config.Redis == nil {
+		return nil, nil
+	}
+
+	redisURL, err := config.Redis.URL.Get()
+	if err != nil {
+		return nil, fmt.Errorf("redis.url: %w", err)
+	}
+
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("redis.url: %w", err)
+	}
+
+	keyPrefix := config.Redis.KeyPrefix
+	if keyPrefix == "" {
+		keyPrefix = defaultRedisKeyPrefix
+	}
+
+	return &RedisStore{
+		client:    redis.NewClient(options),
+		keyPrefix: keyPrefix,
+	}, nil
+}
+
+// Get returns the cached response of the key. Returns nil if not exists or expired.
+func (rs *RedisStore) Get(ctx context.Context, key string) (*CachedResponse, error) {
+	rawBytes, err := rs.client.Get(ctx, rs.keyPrefix+"cache:"+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var result CachedResponse
+	if err := json.Unmarshal(rawBytes, &result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
+
+// Set stores the response with the expiry duration.
+func (rs *RedisStore) Set(ctx context.Context, key string, value CachedResponse, expiry time.Duration) error {
+	rawBytes, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return rs.client.Set(ctx, rs.keyPrefix+"cache:"+key, rawBytes, expiry).Err()
+}
+
+// TryLock acquires the lock of the key with the expiry duration. Returns false if the key is locked by another replica.
+func (rs *RedisStore) TryLock(ctx context.Context, key string, expiry time.Duration) (bool, error) {
+	return rs.client.SetNX(ctx, rs.keyPrefix+"lock:"+key, 1, expiry).Result()
+}
+
+// Unlock releases the lock of the key.
+func (rs *RedisStore) Unlock(ctx context.Context, key string) error {
+	return rs.client.Del(ctx, rs.keyPrefix+"lock:"+key).Err()
+}
+
+// GetToken returns the shared OAuth2 token of the key. Returns nil if not exists.
+func (rs *RedisStore) GetToken(ctx context.Context, key string) (*oauth2.Token, error) {
+	rawBytes, err := rs.client.Get(ctx, rs.keyPrefix+"oauth2:"+key).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var token oauth2.Token
+	if err := json.Unmarshal(rawBytes, &token); err != nil {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// SetToken stores the OAuth2 token until it expires.
+func (rs *RedisStore) SetToken(ctx context.Context, key string, token *oauth2.Token) error {
+	var expiry time.Duration
+	if !token.Expiry.IsZero() {
+		expiry = time.Until(token.Expiry)
+		if expiry <= 0 {
+			return nil
+		}
+	}
+
+	rawBytes, err := json.Marshal(token)
+	if err != nil {
+		return err
+	}
+
+	return rs.client.Set(ctx, rs.keyPrefix+"oauth2:"+key, rawBytes, expiry).Err()
+}
+
+// Reserve takes a request slot of the key and returns the duration to wait before sending the request.
+func (rs *RedisStore) Reserve(ctx context.Context, key string, settings rest.RateLimitSettings) (time.Duration, error) {
+	interval, burst := rateLimitInterval(settings)
+
+	wait, err := rateLimitScript.Run(ctx, rs.client, []string{rs.keyPrefix + "ratelimit:" + key}, interval.Microseconds(), burst).Int64()
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(wait) * time.Microsecond, nil
+}
+
+// Close closes the Redis client.
+func (rs *RedisStore) Close() error {
+	return rs.client.Close()
+}
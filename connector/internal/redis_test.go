@@ -0,0 +1,128 @@
+package internal
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hasura/ndc-http/connector/internal/security"
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func newTestRedisStore(t *testing.T) (*RedisStore, *miniredis.Miniredis) {
+	t.Helper()
+
+	server := miniredis.RunT(t)
+	store, err := NewRedisStore(&configuration.Configuration{
+		Redis: &configuration.RedisSettings{
+			URL: utils.NewEnvStringValue("redis://" + server.Addr()),
+		},
+	})
+	assert.NilError(t, err)
+	t.Cleanup(func() {
+		_ = store.Close()
+	})
+
+	return store, server
+}
+
+func TestRedisStore(t *testing.T) {
+	store, server := newTestRedisStore(t)
+	ctx := context.Background()
+
+	cached, err := store.Get(ctx, "findPets:1")
+	assert.NilError(t, err)
+	assert.Assert(t, cached == nil)
+
+	storedAt := time.Now().Truncate(time.Second)
+	assert.NilError(t, store.Set(ctx, "findPets:1", CachedResponse{
+		Value:    map[string]any{"id": float64(1)},
+		StoredAt: storedAt,
+	}, time.Minute))
+	assert.Assert(t, server.Exists("ndc_http:cache:findPets:1"))
+
+	cached, err = store.Get(ctx, "findPets:1")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, map[string]any{"id": float64(1)}, cached.Value)
+	assert.Assert(t, storedAt.Equal(cached.StoredAt))
+
+	server.FastForward(time.Minute)
+	cached, err = store.Get(ctx, "findPets:1")
+	assert.NilError(t, err)
+	assert.Assert(t, cached == nil)
+
+	// single-flight locks are shared between replicas
+	locked, err := store.TryLock(ctx, "refresh", time.Minute)
+	assert.NilError(t, err)
+	assert.Assert(t, locked)
+
+	locked, err = store.TryLock(ctx, "refresh", time.Minute)
+	assert.NilError(t, err)
+	assert.Assert(t, !locked)
+
+	assert.NilError(t, store.Unlock(ctx, "refresh"))
+	locked, err = store.TryLock(ctx, "refresh", time.Minute)
+	assert.NilError(t, err)
+	assert.Assert(t, locked)
+
+	_, err = NewRedisStore(&configuration.Configuration{
+		Redis: &configuration.RedisSettings{
+			URL: utils.NewEnvStringValue("http://localhost:6379"),
+		},
+	})
+	assert.ErrorContains(t, err, "redis.url: redis: invalid URL scheme: http")
+}
+
+func TestRedisSharedOAuth2Token(t *testing.T) {
+	store, _ := newTestRedisStore(t)
+
+	var tokenRequests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/oauth2/token":
+			tokenRequests.Add(1)
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token":"shared_token","token_type":"Bearer","expires_in":3600}`))
+		default:
+			if r.Header.Get("Authorization") != "Bearer shared_token" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	flow := &rest.OAuthFlow{
+		TokenURL:     utils.ToPtr(utils.NewEnvStringValue(server.URL + "/oauth2/token")),
+		ClientID:     utils.ToPtr(utils.NewEnvStringValue("client_id")),
+		ClientSecret: utils.ToPtr(utils.NewEnvStringValue("client_secret")),
+	}
+
+	// two replicas with the same credentials
+	for range 2 {
+		client, err := security.NewOAuth2Client(context.Background(), http.DefaultClient, rest.ClientCredentialsFlow, flow, store)
+		assert.NilError(t, err)
+
+		resp, err := client.GetClient().Get(server.URL + "/pets")
+		assert.NilError(t, err)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	assert.Equal(t, int32(1), tokenRequests.Load())
+}
+
+func TestRedisRateLimiter(t *testing.T) {
+	store, server := newTestRedisStore(t)
+	testRateLimiter(t, store)
+	assert.Assert(t, server.Exists("ndc_http:ratelimit:pets:dog"))
+}
@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+const defaultIdempotencyTokenTTL = 5 * time.Minute
+
+// ReplayGuard rejects duplicated procedure invocations with the same idempotency token within the TTL window.
+type ReplayGuard struct {
+	config *configuration.Configuration
+	ttl    time.Duration
+	tokens map[string]time.Time
+	lock   sync.Mutex
+}
+
+// NewReplayGuard creates a new ReplayGuard instance. Returns nil if the idempotency header isn't configured.
+func NewReplayGuard(config *configuration.Configuration) *ReplayGuard {
+	if config == nil || config.ReplayProtection == nil || config.ReplayProtection.IdempotencyHeader == "" {
+		return nil
+	}
+
+	ttl := defaultIdempotencyTokenTTL
+	if config.ReplayProtection.TTL > 0 {
+		ttl = time.Duration(config.ReplayProtection.TTL) * time.Second
+	}
+
+	return &ReplayGuard{
+		config: config,
+		ttl:    ttl,
+		tokens: make(map[string]time.Time),
+	}
+}
+
+// Acquire reserves the idempotency token of the operation.
+// Returns an empty key if the client doesn't send the token, or a conflict error if the token was used within the TTL window.
+func (rg *ReplayGuard) Acquire(operationName string, rawArgs map[string]any) (string, error) {
+	if rg == nil {
+		return "", nil
+	}
+
+	token, err := getForwardedHeader(rg.config, rawArgs, rg.config.ReplayProtection.IdempotencyHeader)
+	if err != nil || token == "" {
+		return "", err
+	}
+
+	key := operationName + ":" + token
+	now := time.Now()
+
+	rg.lock.Lock()
+	defer rg.lock.Unlock()
+
+	for k, expiry := range rg.tokens {
+		if !expiry.After(now) {
+			delete(rg.tokens, k)
+		}
+	}
+
+	if _, ok := rg.tokens[key]; ok {
+		return "", schema.NewConnectorError(http.StatusConflict, "duplicated request with the same idempotency token", map[string]any{
+			"operation": operationName,
+			"header":    rg.config.ReplayProtection.IdempotencyHeader,
+		})
+	}
+
+	rg.tokens[key] = now.Add(rg.ttl)
+
+	return key, nil
+}
+
+// Release removes the reserved token so the client can retry failed operations.
+func (rg *ReplayGuard) Release(key string) {
+	if rg == nil || key == "" {
+		return
+	}
+
+	rg.lock.Lock()
+	delete(rg.tokens, key)
+	rg.lock.Unlock()
+}
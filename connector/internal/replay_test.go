@@ -0,0 +1,49 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestReplayGuard(t *testing.T) {
+	assert.Assert(t, NewReplayGuard(&configuration.Configuration{}) == nil)
+
+	guard := NewReplayGuard(&configuration.Configuration{
+		ForwardHeaders: configuration.ForwardHeadersSettings{
+			Enabled:       true,
+			ArgumentField: utils.ToPtr("headers"),
+		},
+		ReplayProtection: &configuration.ReplayProtectionSettings{
+			IdempotencyHeader: "Idempotency-Key",
+		},
+	})
+
+	args := map[string]any{
+		"headers": map[string]any{
+			"idempotency-key": "foo",
+		},
+	}
+
+	key, err := guard.Acquire("addPet", args)
+	assert.NilError(t, err)
+	assert.Equal(t, "addPet:foo", key)
+
+	_, err = guard.Acquire("addPet", args)
+	assert.ErrorContains(t, err, "duplicated request with the same idempotency token")
+
+	// the same token of another operation is allowed
+	_, err = guard.Acquire("updatePet", args)
+	assert.NilError(t, err)
+
+	// requests without the token are always allowed
+	key, err = guard.Acquire("addPet", map[string]any{})
+	assert.NilError(t, err)
+	assert.Equal(t, "", key)
+
+	guard.Release("addPet:foo")
+	_, err = guard.Acquire("addPet", args)
+	assert.NilError(t, err)
+}
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"bytes"
+	"compress/gzip"
 	"context"
 	"io"
 	"net/http"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hasura/ndc-http/connector/internal/contenttype"
 	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
 )
 
@@ -21,22 +23,32 @@ type RetryableRequest struct {
 	ContentType string
 	Headers     http.Header
 	Body        []byte
-	Runtime     rest.RuntimeSettings
+	// The body which is encoded while sending. Body is ignored if set
+	StreamBody *StreamBody
+	Runtime    rest.RuntimeSettings
+	// The session identity of the cookie jar. The cookie jar isn't used if nil
+	CookieSession *string
+}
+
+// GetTimeout returns the request timeout in seconds or the default timeout.
+func (r *RetryableRequest) GetTimeout() uint {
+	if r.Runtime.Timeout == 0 {
+		return defaultTimeoutSeconds
+	}
+
+	return r.Runtime.Timeout
 }
 
 // CreateRequest creates an HTTP request with body copied
 func (r *RetryableRequest) CreateRequest(ctx context.Context) (*http.Request, context.CancelFunc, error) {
 	var body io.Reader
-	if len(r.Body) > 0 {
+	if r.StreamBody != nil {
+		body = r.StreamBody.Reader()
+	} else if len(r.Body) > 0 {
 		body = bytes.NewBuffer(r.Body)
 	}
 
-	timeout := r.Runtime.Timeout
-	if timeout == 0 {
-		timeout = defaultTimeoutSeconds
-	}
-
-	ctxR, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	ctxR, cancel := context.WithTimeout(ctx, time.Duration(r.GetTimeout())*time.Second)
 	request, err := http.NewRequestWithContext(ctxR, strings.ToUpper(r.RawRequest.Method), r.URL.String(), body)
 	if err != nil {
 		cancel()
@@ -47,6 +59,89 @@ func (r *RetryableRequest) CreateRequest(ctx context.Context) (*http.Request, co
 		request.Header[key] = header
 	}
 	request.Header.Set(rest.ContentTypeHeader, r.ContentType)
+	if r.StreamBody != nil {
+		// the body is encoded again for retries and authentication challenges
+		request.GetBody = func() (io.ReadCloser, error) {
+			return r.StreamBody.Reader(), nil
+		}
+	}
 
 	return request, cancel, nil
 }
+
+// StreamBody encodes items of the request body line by line while sending, with optional gzip compression.
+type StreamBody struct {
+	Encoder *contenttype.LineEncoder
+	Gzip    bool
+	// The gzip compression level. Default to gzip.DefaultCompression if zero
+	CompressionLevel int
+}
+
+// Reader returns a reader of the encoded body.
+// Items are encoded in a goroutine when the reader is read for the first time.
+func (sb *StreamBody) Reader() io.ReadCloser {
+	return &lazyPipeReader{
+		write: sb.Encode,
+	}
+}
+
+// Encode encodes and writes the body to the writer.
+func (sb *StreamBody) Encode(w io.Writer) error {
+	if !sb.Gzip {
+		return sb.Encoder.Encode(w)
+	}
+
+	level := sb.CompressionLevel
+	if level == 0 {
+		level = gzip.DefaultCompression
+	}
+
+	zw, err := gzip.NewWriterLevel(w, level)
+	if err != nil {
+		return err
+	}
+
+	if err := sb.Encoder.Encode(zw); err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Explain returns the uncompressed body for explain responses.
+func (sb *StreamBody) Explain() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := sb.Encoder.Encode(&buf); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// lazyPipeReader starts writing to the pipe on the first read,
+// so the writer goroutine isn't leaked if the request is never sent.
+type lazyPipeReader struct {
+	write  func(w io.Writer) error
+	reader *io.PipeReader
+}
+
+func (lpr *lazyPipeReader) Read(p []byte) (int, error) {
+	if lpr.reader == nil {
+		reader, writer := io.Pipe()
+		lpr.reader = reader
+
+		go func() {
+			writer.CloseWithError(lpr.write(writer))
+		}()
+	}
+
+	return lpr.reader.Read(p)
+}
+
+func (lpr *lazyPipeReader) Close() error {
+	if lpr.reader == nil {
+		return nil
+	}
+
+	return lpr.reader.Close()
+}
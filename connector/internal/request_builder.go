@@ -8,8 +8,10 @@ import (
 	"net/url"
 	"reflect"
 	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/hasura/ndc-http/connector/internal/compression"
 	"github.com/hasura/ndc-http/connector/internal/contenttype"
 	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
 	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
@@ -37,6 +39,8 @@ func NewRequestBuilder(restSchema *rest.NDCHttpSchema, operation *rest.Operation
 
 // Build evaluates and builds a RetryableRequest
 func (c *RequestBuilder) Build() (*RetryableRequest, error) {
+	c.applyDefaultValues()
+
 	endpoint, headers, err := c.evalURLAndHeaderParameters()
 	if err != nil {
 		return nil, schema.UnprocessableContentError("failed to evaluate URL and Headers from parameters", map[string]any{
@@ -74,6 +78,36 @@ func (c *RequestBuilder) Build() (*RetryableRequest, error) {
 		if rawRequest.RuntimeSettings.Retry.HTTPStatus != nil {
 			request.Runtime.Retry.HTTPStatus = rawRequest.RuntimeSettings.Retry.HTTPStatus
 		}
+		if rawRequest.RuntimeSettings.Retry.Backoff != "" {
+			request.Runtime.Retry.Backoff = rawRequest.RuntimeSettings.Retry.Backoff
+		}
+		if rawRequest.RuntimeSettings.Retry.Multiplier > 0 {
+			request.Runtime.Retry.Multiplier = rawRequest.RuntimeSettings.Retry.Multiplier
+		}
+		if rawRequest.RuntimeSettings.Retry.MaxDelay > 0 {
+			request.Runtime.Retry.MaxDelay = rawRequest.RuntimeSettings.Retry.MaxDelay
+		}
+		if rawRequest.RuntimeSettings.Retry.Jitter > 0 {
+			request.Runtime.Retry.Jitter = rawRequest.RuntimeSettings.Retry.Jitter
+		}
+		if rawRequest.RuntimeSettings.MaxResponseBytes > 0 {
+			request.Runtime.MaxResponseBytes = rawRequest.RuntimeSettings.MaxResponseBytes
+		}
+		if rawRequest.RuntimeSettings.MaxDecodeDurationMs > 0 {
+			request.Runtime.MaxDecodeDurationMs = rawRequest.RuntimeSettings.MaxDecodeDurationMs
+		}
+		if rawRequest.RuntimeSettings.RateLimit != nil {
+			request.Runtime.RateLimit = rawRequest.RuntimeSettings.RateLimit
+		}
+		if rawRequest.RuntimeSettings.ValidateResponse != "" {
+			request.Runtime.ValidateResponse = rawRequest.RuntimeSettings.ValidateResponse
+		}
+		if rawRequest.RuntimeSettings.Compression != "" {
+			request.Runtime.Compression = rawRequest.RuntimeSettings.Compression
+		}
+		if rawRequest.RuntimeSettings.CompressionMinBytes > 0 {
+			request.Runtime.CompressionMinBytes = rawRequest.RuntimeSettings.CompressionMinBytes
+		}
 	}
 	if request.Runtime.Retry.HTTPStatus == nil {
 		request.Runtime.Retry.HTTPStatus = defaultRetryHTTPStatus
@@ -82,7 +116,39 @@ func (c *RequestBuilder) Build() (*RetryableRequest, error) {
 	return request, nil
 }
 
+// encode items of the array body line by line while sending the request.
+func (c *RequestBuilder) buildStreamRequestBody(request *RetryableRequest, contentType string, bodyData any, settings *rest.RequestBodyStreamSettings) error {
+	encoder, err := contenttype.NewLineEncoder(contentType, bodyData, settings.Columns)
+	if err != nil {
+		return err
+	}
+
+	request.StreamBody = &StreamBody{
+		Encoder:          encoder,
+		Gzip:             settings.Gzip,
+		CompressionLevel: settings.CompressionLevel,
+	}
+
+	if settings.Gzip {
+		request.Headers.Set(rest.ContentEncodingHeader, compression.EncodingGzip)
+	}
+
+	return nil
+}
+
 func (c *RequestBuilder) buildRequestBody(request *RetryableRequest, rawRequest *rest.Request) error {
+	if rawRequest.Protocol == rest.ProtocolGRPC && rawRequest.GRPC != nil {
+		return c.buildGRPCRequestBody(request, rawRequest.GRPC)
+	}
+
+	if rawRequest.GraphQL != nil {
+		return c.buildGraphQLRequestBody(request, rawRequest.GraphQL)
+	}
+
+	if rawRequest.SOAP != nil {
+		return c.buildSOAPRequestBody(request, rawRequest.SOAP)
+	}
+
 	if rawRequest.RequestBody == nil {
 		request.ContentType = rest.ContentTypeJSON
 
@@ -96,6 +162,9 @@ func (c *RequestBuilder) buildRequestBody(request *RetryableRequest, rawRequest
 
 	if ok && bodyData != nil {
 		binaryBody := c.getRequestUploadBody(c.Operation.Request, &bodyInfo)
+		if stream := rawRequest.RequestBody.Stream; stream != nil && binaryBody == nil {
+			return c.buildStreamRequestBody(request, contentType, bodyData, stream)
+		}
 
 		switch {
 		case binaryBody != nil:
@@ -137,15 +206,12 @@ func (c *RequestBuilder) buildRequestBody(request *RetryableRequest, rawRequest
 
 			return nil
 		case contentType == "" || restUtils.IsContentTypeJSON(contentType):
-			var buf bytes.Buffer
-			enc := json.NewEncoder(&buf)
-			enc.SetEscapeHTML(false)
-
-			if err := enc.Encode(bodyData); err != nil {
+			bodyBytes, err := contenttype.NewJSONEncoder(c.Schema).Encode(&bodyInfo, bodyData)
+			if err != nil {
 				return err
 			}
 
-			request.Body = buf.Bytes()
+			request.Body = bodyBytes
 
 			return nil
 		case restUtils.IsContentTypeXML(contentType):
@@ -156,6 +222,39 @@ func (c *RequestBuilder) buildRequestBody(request *RetryableRequest, rawRequest
 
 			request.Body = bodyBytes
 
+			return nil
+		case restUtils.IsContentTypeNdJSON(contentType):
+			encoder, err := contenttype.NewLineEncoder(contentType, bodyData, nil)
+			if err != nil {
+				return err
+			}
+
+			var buf bytes.Buffer
+			if err := encoder.Encode(&buf); err != nil {
+				return err
+			}
+
+			request.Body = buf.Bytes()
+
+			return nil
+		case restUtils.IsContentTypeMsgPack(contentType):
+			bodyBytes, err := contenttype.EncodeMsgPack(bodyData)
+			if err != nil {
+				return err
+			}
+
+			request.Body = bodyBytes
+
+			return nil
+		case restUtils.IsContentTypeProtobuf(contentType) && rawRequest.Protobuf != nil && rawRequest.Protobuf.RequestMessage != "":
+			// the JSON body is encoded to the protobuf message with the descriptor set while sending the request
+			bodyBytes, err := json.Marshal(bodyData)
+			if err != nil {
+				return err
+			}
+
+			request.Body = bodyBytes
+
 			return nil
 		default:
 			return fmt.Errorf("unsupported content type %s", contentType)
@@ -173,6 +272,74 @@ func (c *RequestBuilder) buildRequestBody(request *RetryableRequest, rawRequest
 	return nil
 }
 
+// build the GraphQL request payload with the query document. Arguments are sent as variables
+func (c *RequestBuilder) buildGraphQLRequestBody(request *RetryableRequest, gqlRequest *rest.GraphQLRequest) error {
+	variables := make(map[string]any)
+	for _, name := range gqlRequest.Variables {
+		if value, ok := c.Arguments[name]; ok {
+			variables[name] = value
+		}
+	}
+
+	payload := map[string]any{
+		"query":     gqlRequest.Query,
+		"variables": variables,
+	}
+	if gqlRequest.OperationName != "" {
+		payload["operationName"] = gqlRequest.OperationName
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+
+	if err := enc.Encode(payload); err != nil {
+		return err
+	}
+
+	request.ContentType = rest.ContentTypeJSON
+	request.Body = buf.Bytes()
+
+	return nil
+}
+
+// build the SOAP envelope with the XML body. The SOAP action is sent in the header (1.1) or the content type (1.2)
+func (c *RequestBuilder) buildSOAPRequestBody(request *RetryableRequest, soapRequest *rest.SOAPRequest) error {
+	var body []byte
+	bodyInfo, infoOk := c.Operation.Arguments[rest.BodyKey]
+	bodyData, ok := c.Arguments[rest.BodyKey]
+	switch {
+	case ok && bodyData != nil && infoOk:
+		bodyBytes, err := contenttype.NewXMLEncoder(c.Schema).Encode(&bodyInfo, bodyData)
+		if err != nil {
+			return err
+		}
+
+		body = bodyBytes
+	case infoOk:
+		ty, err := bodyInfo.Type.Type()
+		if err != nil {
+			return err
+		}
+		if ty != schema.TypeNullable {
+			return errRequestBodyRequired
+		}
+	}
+
+	version := soapRequest.GetVersion()
+	request.ContentType = soapRequest.ContentType() + "; charset=utf-8"
+	switch {
+	case version == rest.SOAPVersion11:
+		request.Headers.Set("SOAPAction", strconv.Quote(soapRequest.Action))
+	case soapRequest.Action != "":
+		request.ContentType += "; action=" + strconv.Quote(soapRequest.Action)
+	}
+
+	request.Body = contenttype.EncodeSOAPEnvelope(version, body)
+
+	return nil
+}
+
 func (c *RequestBuilder) getRequestUploadBody(rawRequest *rest.Request, bodyInfo *rest.ArgumentInfo) *rest.RequestBody {
 	if rawRequest.RequestBody == nil || bodyInfo == nil {
 		return nil
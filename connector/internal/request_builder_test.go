@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
 	"gotest.tools/v3/assert"
 )
 
@@ -217,3 +218,120 @@ func createMockSchemaOAS2(t *testing.T) *rest.NDCHttpSchema {
 
 	return &ndcSchema
 }
+
+func TestBuildRequestBodyLineDelimited(t *testing.T) {
+	for _, contentType := range []string{rest.ContentTypeNdJSON, "application/jsonl"} {
+		t.Run(contentType, func(t *testing.T) {
+			builder := RequestBuilder{
+				Schema: &rest.NDCHttpSchema{},
+				Operation: &rest.OperationInfo{
+					Request: &rest.Request{
+						URL:    "/bulk",
+						Method: "post",
+						RequestBody: &rest.RequestBody{
+							ContentType: contentType,
+						},
+					},
+				},
+				Arguments: map[string]any{
+					"body": []any{
+						map[string]any{"id": float64(1)},
+						map[string]any{"id": float64(2)},
+					},
+				},
+			}
+
+			request := &RetryableRequest{}
+			assert.NilError(t, builder.buildRequestBody(request, builder.Operation.Request))
+			assert.Equal(t, contentType, request.ContentType)
+			assert.Equal(t, "{\"id\":1}\n{\"id\":2}\n", string(request.Body))
+			assert.Assert(t, request.StreamBody == nil)
+		})
+	}
+
+	builder := RequestBuilder{
+		Schema: &rest.NDCHttpSchema{},
+		Operation: &rest.OperationInfo{
+			Request: &rest.Request{
+				RequestBody: &rest.RequestBody{
+					ContentType: rest.ContentTypeNdJSON,
+				},
+			},
+		},
+		Arguments: map[string]any{
+			"body": map[string]any{"id": float64(1)},
+		},
+	}
+	err := builder.buildRequestBody(&RetryableRequest{}, builder.Operation.Request)
+	assert.ErrorContains(t, err, "expected an array body to be encoded line by line")
+}
+
+func TestBuildRequestDefaultValues(t *testing.T) {
+	ndcSchema := &rest.NDCHttpSchema{
+		ObjectTypes: map[string]rest.ObjectType{
+			"CreateBookBody": {
+				Fields: map[string]rest.ObjectField{
+					"title": {
+						ObjectField: schema.ObjectField{Type: schema.NewNamedType("String").Encode()},
+						HTTP:        &rest.TypeSchema{Type: []string{"string"}},
+					},
+					"pages": {
+						ObjectField: schema.ObjectField{Type: schema.NewNullableNamedType("Int32").Encode()},
+						HTTP:        &rest.TypeSchema{Type: []string{"integer"}, Default: 100},
+					},
+				},
+			},
+		},
+		ScalarTypes: schema.SchemaResponseScalarTypes{
+			"String": *schema.NewScalarType(),
+			"Int32":  *schema.NewScalarType(),
+		},
+	}
+	operation := &rest.OperationInfo{
+		Request: &rest.Request{
+			URL:    "/books",
+			Method: "post",
+			RequestBody: &rest.RequestBody{
+				ContentType: rest.ContentTypeJSON,
+			},
+		},
+		Arguments: map[string]rest.ArgumentInfo{
+			"lang": {
+				ArgumentInfo: schema.ArgumentInfo{Type: schema.NewNullableNamedType("String").Encode()},
+				HTTP: &rest.RequestParameter{
+					Name:   "lang",
+					In:     rest.InQuery,
+					Schema: &rest.TypeSchema{Type: []string{"string"}, Default: "en"},
+				},
+			},
+			"body": {
+				ArgumentInfo: schema.ArgumentInfo{Type: schema.NewNamedType("CreateBookBody").Encode()},
+			},
+		},
+	}
+
+	t.Run("omitted", func(t *testing.T) {
+		arguments := map[string]any{
+			"body": map[string]any{"title": "Dune"},
+		}
+		request, err := NewRequestBuilder(ndcSchema, operation, arguments, rest.RuntimeSettings{}).Build()
+		assert.NilError(t, err)
+		assert.Equal(t, "/books?lang=en", request.URL.String())
+		assert.Equal(t, "{\"pages\":100,\"title\":\"Dune\"}\n", string(request.Body))
+		// input arguments aren't mutated
+		assert.DeepEqual(t, map[string]any{"title": "Dune"}, arguments["body"])
+		_, ok := arguments["lang"]
+		assert.Assert(t, !ok)
+	})
+
+	t.Run("explicit", func(t *testing.T) {
+		arguments := map[string]any{
+			"lang": "vi",
+			"body": map[string]any{"title": "Dune", "pages": float64(412)},
+		}
+		request, err := NewRequestBuilder(ndcSchema, operation, arguments, rest.RuntimeSettings{}).Build()
+		assert.NilError(t, err)
+		assert.Equal(t, "/books?lang=vi", request.URL.String())
+		assert.Equal(t, "{\"pages\":412,\"title\":\"Dune\"}\n", string(request.Body))
+	})
+}
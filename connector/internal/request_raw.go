@@ -71,12 +71,25 @@ func (rqe *RawRequestBuilder) Build() (*RequestBuilderResults, error) {
 	}
 
 	return &RequestBuilderResults{
-		Requests:    []*RetryableRequest{httpRequest},
-		HTTPOptions: &HTTPOptions{},
-		Schema:      &configuration.NDCHttpRuntimeSchema{},
+		Requests:      []*RetryableRequest{httpRequest},
+		OperationName: ProcedureSendHTTPRequest,
+		HTTPOptions:   &HTTPOptions{},
+		Schema:        &configuration.NDCHttpRuntimeSchema{},
 	}, nil
 }
 
+// BuildRawRequests builds the request of the raw sendHttpRequest procedure and attaches the idempotency key if configured.
+func (um *UpstreamManager) BuildRawRequests(operation schema.MutationOperation) (*RequestBuilderResults, error) {
+	results, err := NewRawRequestBuilder(operation, um.config.ForwardHeaders).Build()
+	if err != nil {
+		return nil, err
+	}
+
+	um.idempotency.InjectRaw(results.Requests)
+
+	return results, nil
+}
+
 func (rqe *RawRequestBuilder) explain() (*RetryableRequest, error) {
 	request, err := rqe.decodeArguments()
 	if err != nil {
@@ -136,6 +149,10 @@ func (rqe *RawRequestBuilder) decodeArguments() (*RetryableRequest, error) {
 		if err := json.Unmarshal(rawRetry, &retryPolicy); err != nil {
 			return nil, fmt.Errorf("retry: %w", err)
 		}
+
+		if err := retryPolicy.Validate(); err != nil {
+			return nil, fmt.Errorf("retry: %w", err)
+		}
 	}
 
 	headers := http.Header{}
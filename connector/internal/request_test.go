@@ -0,0 +1,59 @@
+package internal
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"net/url"
+	"testing"
+
+	"github.com/hasura/ndc-http/connector/internal/contenttype"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestStreamBody(t *testing.T) {
+	encoder, err := contenttype.NewLineEncoder(rest.ContentTypeNdJSON, []any{
+		map[string]any{"id": 1},
+		map[string]any{"id": 2},
+	}, nil)
+	assert.NilError(t, err)
+
+	request := &RetryableRequest{
+		URL:         url.URL{Scheme: "http", Host: "localhost", Path: "/upload"},
+		RawRequest:  &rest.Request{Method: "post"},
+		ContentType: rest.ContentTypeNdJSON,
+		StreamBody: &StreamBody{
+			Encoder:          encoder,
+			Gzip:             true,
+			CompressionLevel: gzip.BestSpeed,
+		},
+	}
+
+	req, cancel, err := request.CreateRequest(context.TODO())
+	assert.NilError(t, err)
+	defer cancel()
+
+	readBody := func(body io.ReadCloser) string {
+		defer body.Close()
+
+		reader, err := gzip.NewReader(body)
+		assert.NilError(t, err)
+		rawBody, err := io.ReadAll(reader)
+		assert.NilError(t, err)
+
+		return string(rawBody)
+	}
+
+	expected := "{\"id\":1}\n{\"id\":2}\n"
+	assert.Equal(t, expected, readBody(req.Body))
+
+	// the body can be encoded again for retries
+	body, err := req.GetBody()
+	assert.NilError(t, err)
+	assert.Equal(t, expected, readBody(body))
+
+	explainBody, err := request.StreamBody.Explain()
+	assert.NilError(t, err)
+	assert.Equal(t, expected, string(explainBody))
+}
@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+const (
+	minRetryDelay                 = 100 * time.Millisecond
+	defaultRetryBackoffMultiplier = 2
+	// X-RateLimit-Reset values larger than this are unix timestamps in seconds, otherwise they are delays in seconds
+	rateLimitResetEpochThreshold = 1_000_000_000
+)
+
+// getRetryDelay computes the delay before the next retry with the backoff strategy of the retry policy.
+// The retry count starts from zero. The delay of Retry-After or X-RateLimit-Reset headers of the response
+// is honored as sent if it is longer than the computed delay. Returns false if that delay exceeds the max delay
+// of the policy, so the request shouldn't be retried earlier than the upstream asks for.
+func getRetryDelay(policy rest.RetryPolicy, retryCount int, resp *http.Response, now time.Time) (time.Duration, bool) {
+	delay := float64(max(time.Duration(policy.Delay)*time.Millisecond, minRetryDelay))
+	if policy.Backoff == rest.RetryBackoffExponential {
+		multiplier := policy.Multiplier
+		if multiplier < 1 {
+			multiplier = defaultRetryBackoffMultiplier
+		}
+
+		delay *= math.Pow(multiplier, float64(retryCount))
+	}
+
+	maxDelay := time.Duration(policy.MaxDelay) * time.Millisecond
+	if maxDelay > 0 && delay > float64(maxDelay) {
+		delay = float64(maxDelay)
+	}
+
+	if policy.Jitter > 0 {
+		delay -= delay * policy.Jitter * rand.Float64() //nolint:gosec
+	}
+
+	result := time.Duration(math.Min(delay, float64(math.MaxInt64)))
+	if resp != nil {
+		if retryAfter, ok := parseRetryAfter(resp, now); ok && retryAfter > result {
+			if maxDelay > 0 && retryAfter > maxDelay {
+				return retryAfter, false
+			}
+
+			result = retryAfter
+		}
+	}
+
+	return result, true
+}
+
+// parseRetryAfter parses the delay from the Retry-After header, which can be seconds or an HTTP date,
+// or the X-RateLimit-Reset header if the rate limit is exhausted.
+func parseRetryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if value := resp.Header.Get("Retry-After"); value != "" {
+		if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return time.Duration(max(seconds, 0)) * time.Second, true
+		}
+
+		if date, err := http.ParseTime(value); err == nil {
+			return max(date.Sub(now), 0), true
+		}
+	}
+
+	if resp.StatusCode != http.StatusTooManyRequests && resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+
+	value := resp.Header.Get("X-RateLimit-Reset")
+	if value == "" {
+		return 0, false
+	}
+
+	reset, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, false
+	}
+
+	if reset > rateLimitResetEpochThreshold {
+		return max(time.Unix(reset, 0).Sub(now), 0), true
+	}
+
+	return time.Duration(max(reset, 0)) * time.Second, true
+}
@@ -0,0 +1,146 @@
+package internal
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestGetRetryDelay(t *testing.T) {
+	// HTTP dates and unix timestamps are in seconds
+	now := time.Now().Truncate(time.Second)
+	testCases := []struct {
+		Name       string
+		Policy     rest.RetryPolicy
+		RetryCount int
+		Response   *http.Response
+		Expected   time.Duration
+		Stop       bool
+	}{
+		{
+			Name:     "min_delay",
+			Expected: 100 * time.Millisecond,
+		},
+		{
+			Name:       "fixed",
+			Policy:     rest.RetryPolicy{Delay: 500},
+			RetryCount: 3,
+			Expected:   500 * time.Millisecond,
+		},
+		{
+			Name:       "exponential",
+			Policy:     rest.RetryPolicy{Delay: 500, Backoff: rest.RetryBackoffExponential},
+			RetryCount: 3,
+			Expected:   4 * time.Second,
+		},
+		{
+			Name:       "exponential_multiplier",
+			Policy:     rest.RetryPolicy{Delay: 100, Backoff: rest.RetryBackoffExponential, Multiplier: 3},
+			RetryCount: 2,
+			Expected:   900 * time.Millisecond,
+		},
+		{
+			Name:       "max_delay",
+			Policy:     rest.RetryPolicy{Delay: 1000, Backoff: rest.RetryBackoffExponential, MaxDelay: 5000},
+			RetryCount: 10,
+			Expected:   5 * time.Second,
+		},
+		{
+			Name:   "retry_after_seconds",
+			Policy: rest.RetryPolicy{Delay: 500},
+			Response: &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{"Retry-After": []string{"3"}},
+			},
+			Expected: 3 * time.Second,
+		},
+		{
+			Name:   "retry_after_date",
+			Policy: rest.RetryPolicy{Delay: 500},
+			Response: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{now.Add(10 * time.Second).UTC().Format(http.TimeFormat)}},
+			},
+			Expected: 10 * time.Second,
+		},
+		{
+			Name:   "retry_after_max_delay",
+			Policy: rest.RetryPolicy{Delay: 500, MaxDelay: 2000},
+			Response: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"60"}},
+			},
+			Expected: time.Minute,
+			Stop:     true,
+		},
+		{
+			Name:   "retry_after_within_max_delay",
+			Policy: rest.RetryPolicy{Delay: 500, MaxDelay: 2000},
+			Response: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"2"}},
+			},
+			Expected: 2 * time.Second,
+		},
+		{
+			Name:   "retry_after_shorter",
+			Policy: rest.RetryPolicy{Delay: 5000},
+			Response: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"Retry-After": []string{"1"}},
+			},
+			Expected: 5 * time.Second,
+		},
+		{
+			Name: "rate_limit_reset_seconds",
+			Response: &http.Response{
+				StatusCode: http.StatusTooManyRequests,
+				Header:     http.Header{"X-Ratelimit-Reset": []string{"2"}},
+			},
+			Expected: 2 * time.Second,
+		},
+		{
+			Name: "rate_limit_reset_timestamp",
+			Response: &http.Response{
+				StatusCode: http.StatusForbidden,
+				Header: http.Header{
+					"X-Ratelimit-Remaining": []string{"0"},
+					"X-Ratelimit-Reset":     []string{strconv.FormatInt(now.Add(30*time.Second).Unix(), 10)},
+				},
+			},
+			Expected: 30 * time.Second,
+		},
+		{
+			Name: "rate_limit_remaining",
+			Response: &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Header: http.Header{
+					"X-Ratelimit-Remaining": []string{"10"},
+					"X-Ratelimit-Reset":     []string{"30"},
+				},
+			},
+			Expected: 100 * time.Millisecond,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			delay, ok := getRetryDelay(tc.Policy, tc.RetryCount, tc.Response, now)
+			assert.Equal(t, tc.Expected, delay)
+			assert.Equal(t, !tc.Stop, ok)
+		})
+	}
+
+	t.Run("jitter", func(t *testing.T) {
+		policy := rest.RetryPolicy{Delay: 1000, Jitter: 0.5}
+		for range 100 {
+			delay, ok := getRetryDelay(policy, 0, nil, now)
+			assert.Assert(t, ok)
+			assert.Assert(t, delay > 500*time.Millisecond && delay <= time.Second, "got: %s", delay)
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package internal
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// FairScheduler limits the number of concurrent requests to upstream services of all schemas.
+// Free slots are shared across schemas by weights with start-time fair queuing,
+// so a high-volume schema can't starve the capacity of other schemas.
+type FairScheduler struct {
+	capacity    int
+	running     int
+	weights     map[string]uint
+	queues      map[string]*schedulerQueue
+	virtualTime float64
+	lock        sync.Mutex
+}
+
+// schedulerQueue holds waiting requests of a schema.
+type schedulerQueue struct {
+	weight float64
+	// the virtual finish time of the last dispatched request
+	finishTime float64
+	waiters    []chan struct{}
+}
+
+// NewFairScheduler creates a new FairScheduler instance.
+// Returns nil if the capacity is unlimited.
+func NewFairScheduler(capacity uint, weights map[string]uint) *FairScheduler {
+	if capacity == 0 {
+		return nil
+	}
+
+	return &FairScheduler{
+		capacity: int(capacity),
+		weights:  weights,
+		queues:   make(map[string]*schedulerQueue),
+	}
+}
+
+// SetScheduler sets the global scheduler of upstream requests.
+func (um *UpstreamManager) SetScheduler(scheduler *FairScheduler) {
+	um.scheduler = scheduler
+}
+
+// Acquire blocks until a request slot is available for the namespace.
+// The returned function must be called to release the slot when the request is done.
+func (fs *FairScheduler) Acquire(ctx context.Context, namespace string) (func(), error) {
+	fs.lock.Lock()
+	queue := fs.getQueue(namespace)
+	if fs.running < fs.capacity && !fs.hasWaiters() {
+		fs.running++
+		fs.charge(queue)
+		fs.lock.Unlock()
+
+		return fs.releaseOnce(), nil
+	}
+
+	ready := make(chan struct{})
+	queue.waiters = append(queue.waiters, ready)
+	fs.lock.Unlock()
+
+	start := time.Now()
+	select {
+	case <-ready:
+		trace.SpanFromContext(ctx).SetAttributes(attribute.Int64("http.request.scheduler.wait_ms", time.Since(start).Milliseconds()))
+
+		return fs.releaseOnce(), nil
+	case <-ctx.Done():
+		fs.lock.Lock()
+		defer fs.lock.Unlock()
+
+		for i, waiter := range queue.waiters {
+			if waiter == ready {
+				queue.waiters = append(queue.waiters[:i], queue.waiters[i+1:]...)
+
+				return nil, ctx.Err()
+			}
+		}
+
+		// the slot was granted while the context is canceled, pass it to the next request
+		fs.running--
+		fs.dispatch()
+
+		return nil, ctx.Err()
+	}
+}
+
+// Running returns the number of running requests.
+func (fs *FairScheduler) Running() int {
+	fs.lock.Lock()
+	defer fs.lock.Unlock()
+
+	return fs.running
+}
+
+func (fs *FairScheduler) releaseOnce() func() {
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			fs.lock.Lock()
+			defer fs.lock.Unlock()
+
+			fs.running--
+			fs.dispatch()
+		})
+	}
+}
+
+func (fs *FairScheduler) getQueue(namespace string) *schedulerQueue {
+	queue, ok := fs.queues[namespace]
+	if !ok {
+		weight := fs.weights[namespace]
+		if weight == 0 {
+			weight = 1
+		}
+
+		queue = &schedulerQueue{
+			weight: float64(weight),
+		}
+		fs.queues[namespace] = queue
+	}
+
+	return queue
+}
+
+func (fs *FairScheduler) hasWaiters() bool {
+	for _, queue := range fs.queues {
+		if len(queue.waiters) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// charge advances the virtual time of the queue by the cost of a request.
+// Idle queues restart at the global virtual time so they can't save up credits.
+func (fs *FairScheduler) charge(queue *schedulerQueue) {
+	startTime := max(queue.finishTime, fs.virtualTime)
+	queue.finishTime = startTime + 1/queue.weight
+	fs.virtualTime = startTime
+}
+
+// dispatch wakes up waiting requests with the smallest virtual finish time while slots are available.
+func (fs *FairScheduler) dispatch() {
+	for fs.running < fs.capacity {
+		var next *schedulerQueue
+		for _, queue := range fs.queues {
+			if len(queue.waiters) == 0 {
+				continue
+			}
+
+			if next == nil || max(queue.finishTime, fs.virtualTime) < max(next.finishTime, fs.virtualTime) {
+				next = queue
+			}
+		}
+
+		if next == nil {
+			return
+		}
+
+		ready := next.waiters[0]
+		next.waiters = next.waiters[1:]
+		fs.running++
+		fs.charge(next)
+		close(ready)
+	}
+}
@@ -0,0 +1,95 @@
+package internal
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFairScheduler(t *testing.T) {
+	assert.Assert(t, NewFairScheduler(0, nil) == nil)
+
+	t.Run("weighted", func(t *testing.T) {
+		scheduler := NewFairScheduler(1, map[string]uint{"bulk": 3})
+		release, err := scheduler.Acquire(context.Background(), "other")
+		assert.NilError(t, err)
+
+		order := make(chan string, 8)
+		enqueue := func(namespace string, count int) {
+			for i := range count {
+				go func() {
+					release, err := scheduler.Acquire(context.Background(), namespace)
+					if err != nil {
+						order <- err.Error()
+
+						return
+					}
+
+					order <- namespace
+					release()
+				}()
+
+				waitSchedulerQueue(t, scheduler, namespace, i+1)
+			}
+		}
+
+		enqueue("bulk", 6)
+		enqueue("search", 2)
+		assert.Equal(t, 1, scheduler.Running())
+
+		release()
+		release()
+
+		counts := map[string]int{}
+		for range 4 {
+			counts[<-order]++
+		}
+		assert.DeepEqual(t, map[string]int{"bulk": 3, "search": 1}, counts)
+
+		for range 4 {
+			counts[<-order]++
+		}
+		assert.DeepEqual(t, map[string]int{"bulk": 6, "search": 2}, counts)
+		assert.Equal(t, 0, scheduler.Running())
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		scheduler := NewFairScheduler(1, nil)
+		release, err := scheduler.Acquire(context.Background(), "a")
+		assert.NilError(t, err)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		_, err = scheduler.Acquire(ctx, "b")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+		release()
+		assert.Equal(t, 0, scheduler.Running())
+
+		release, err = scheduler.Acquire(context.Background(), "b")
+		assert.NilError(t, err)
+		release()
+	})
+}
+
+func waitSchedulerQueue(t *testing.T, scheduler *FairScheduler, namespace string, length int) {
+	t.Helper()
+
+	for range 100 {
+		scheduler.lock.Lock()
+		queue, ok := scheduler.queues[namespace]
+		ready := ok && len(queue.waiters) == length
+		scheduler.lock.Unlock()
+
+		if ready {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("timed out waiting for %d requests of %s in the queue", length, namespace)
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+)
+
+// SecretRefresher fetches secrets periodically and refreshes credentials and headers of upstreams whose secrets are rotated.
+type SecretRefresher struct {
+	resolver  *configuration.SecretResolver
+	upstreams *UpstreamManager
+	schemas   []configuration.NDCHttpRuntimeSchema
+	interval  time.Duration
+	logger    *slog.Logger
+}
+
+// NewSecretRefresher creates a refresher of secrets. Schemas must be unresolved to resolve references again.
+// Returns nil if the refresh interval isn't set or there is no secret provider.
+func NewSecretRefresher(config *configuration.Configuration, resolver *configuration.SecretResolver, upstreams *UpstreamManager, schemas []configuration.NDCHttpRuntimeSchema, logger *slog.Logger) *SecretRefresher {
+	if config.Secrets == nil || config.Secrets.RefreshInterval == 0 || resolver == nil || resolver.IsEmpty() {
+		return nil
+	}
+
+	return &SecretRefresher{
+		resolver:  resolver,
+		upstreams: upstreams,
+		schemas:   schemas,
+		interval:  time.Duration(config.Secrets.RefreshInterval) * time.Second,
+		logger:    logger,
+	}
+}
+
+// Start refreshes secrets in the background until the context is canceled.
+func (sr *SecretRefresher) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(sr.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				sr.Refresh(ctx)
+			}
+		}
+	}()
+}
+
+// Refresh fetches secrets again and refreshes credentials of upstreams which use changed secrets.
+// Returns namespaces of refreshed upstreams.
+func (sr *SecretRefresher) Refresh(ctx context.Context) []string {
+	changedKeys, err := sr.resolver.Refresh(ctx)
+	if err != nil {
+		sr.logger.Warn("failed to refresh secrets, cached secrets are used: " + err.Error())
+	}
+
+	if len(changedKeys) == 0 {
+		return nil
+	}
+
+	var namespaces []string
+	for _, item := range sr.schemas {
+		if item.NDCHttpSchema == nil || item.Settings == nil {
+			continue
+		}
+
+		settings, keys, err := sr.resolver.ResolveSettings(ctx, item.Settings)
+		if err != nil {
+			sr.logger.Error(fmt.Sprintf("failed to resolve secrets of %s: %s", item.Name, err))
+
+			continue
+		}
+
+		if !slices.ContainsFunc(keys, func(key string) bool {
+			return slices.Contains(changedKeys, key)
+		}) {
+			continue
+		}
+
+		if _, ok := sr.upstreams.getUpstream(item.Name); !ok {
+			continue
+		}
+
+		if err := sr.upstreams.RefreshCredentials(ctx, item.Name, settings); err != nil {
+			sr.logger.Error(err.Error())
+
+			continue
+		}
+
+		namespaces = append(namespaces, item.Name)
+		sr.logger.Info("secrets are rotated, credentials of the upstream are refreshed", slog.String("namespace", item.Name))
+	}
+
+	return namespaces
+}
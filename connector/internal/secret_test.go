@@ -0,0 +1,82 @@
+package internal
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hasura/ndc-http/connector/internal/security"
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+type testSecretProvider struct {
+	value atomic.Value
+}
+
+func (tsp *testSecretProvider) GetSecret(ctx context.Context, path string) (string, error) {
+	return tsp.value.Load().(string), nil
+}
+
+func TestSecretRefresher(t *testing.T) {
+	provider := &testSecretProvider{}
+	provider.value.Store("key-1")
+
+	config := &configuration.Configuration{
+		Secrets: &configuration.SecretsSettings{
+			RefreshInterval: 60,
+		},
+	}
+	resolver, err := configuration.NewSecretResolver(config.Secrets)
+	assert.NilError(t, err)
+	resolver.RegisterProvider("test", provider)
+
+	schemas := []configuration.NDCHttpRuntimeSchema{
+		{
+			Name: "petstore",
+			NDCHttpSchema: &rest.NDCHttpSchema{
+				Settings: &rest.NDCHttpSettings{
+					Servers: []rest.ServerConfig{
+						{URL: utils.NewEnvStringValue("https://petstore.example.com")},
+					},
+					SecuritySchemes: map[string]rest.SecurityScheme{
+						"api_key": {
+							SecuritySchemer: rest.NewAPIKeyAuthConfig("api_key", rest.APIKeyInHeader, utils.NewEnvStringValue("test:petstore")),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	resolvedSchemas, err := resolver.ResolveSchemas(context.TODO(), schemas)
+	assert.NilError(t, err)
+
+	um := NewUpstreamManager(http.DefaultClient, config)
+	assert.NilError(t, um.Register(context.TODO(), &resolvedSchemas[0], resolvedSchemas[0].NDCHttpSchema))
+
+	getAPIKey := func() string {
+		upstream, ok := um.getUpstream("petstore")
+		assert.Assert(t, ok)
+
+		return upstream.credentials["api_key"].(*security.ApiKeyCredential).Value
+	}
+	assert.Equal(t, "key-1", getAPIKey())
+
+	refresher := NewSecretRefresher(config, resolver, um, schemas, slog.Default())
+	assert.Assert(t, refresher != nil)
+	assert.Equal(t, 0, len(refresher.Refresh(context.TODO())))
+
+	provider.value.Store("key-2")
+	assert.DeepEqual(t, []string{"petstore"}, refresher.Refresh(context.TODO()))
+	assert.Equal(t, "key-2", getAPIKey())
+
+	upstream, _ := um.getUpstream("petstore")
+	assert.Equal(t, "https://petstore.example.com", upstream.servers["0"].URL.String())
+
+	assert.Assert(t, NewSecretRefresher(&configuration.Configuration{}, resolver, um, schemas, slog.Default()) == nil)
+}
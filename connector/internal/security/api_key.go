@@ -3,6 +3,8 @@ package security
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/hasura/ndc-http/ndc-http-schema/schema"
 	"github.com/hasura/ndc-http/ndc-http-schema/utils"
@@ -14,9 +16,12 @@ type ApiKeyCredential struct {
 	Name  string
 	Value string
 
-	client *http.Client
+	client   *http.Client
+	rotation *credentialRotation
 }
 
+var _ RotatableCredential = &ApiKeyCredential{}
+
 // NewApiKeyCredential creates a new APIKeyCredential instance.
 func NewApiKeyCredential(client *http.Client, config *schema.APIKeyAuthConfig) (*ApiKeyCredential, error) {
 	value, err := config.Value.Get()
@@ -24,13 +29,23 @@ func NewApiKeyCredential(client *http.Client, config *schema.APIKeyAuthConfig) (
 		return nil, fmt.Errorf("failed to create ApiKeyCredential: %w", err)
 	}
 
-	return &ApiKeyCredential{
+	result := &ApiKeyCredential{
 		In:    config.In,
 		Name:  config.Name,
 		Value: value,
 
 		client: client,
-	}, nil
+	}
+
+	// the secondary value is optional. The credential works with the primary value only if the variable isn't set
+	if config.SecondaryValue != nil {
+		if secondary, err := config.SecondaryValue.Get(); err == nil && secondary != "" && secondary != value {
+			result.rotation = &credentialRotation{secondary: secondary}
+			result.client = newRotationClient(client, result.rotation, result.injectSecondary)
+		}
+	}
+
+	return result, nil
 }
 
 // GetClient gets the HTTP client that is compatible with the current credential.
@@ -38,6 +53,13 @@ func (akc ApiKeyCredential) GetClient() *http.Client {
 	return akc.client
 }
 
+// SetRotationHandler sets the handler which is called when the request is retried with the secondary key.
+func (akc *ApiKeyCredential) SetRotationHandler(handler RotationHandler) {
+	if akc.rotation != nil {
+		akc.rotation.handler = handler
+	}
+}
+
 // Inject the credential into the incoming request
 func (akc ApiKeyCredential) Inject(req *http.Request) (bool, error) {
 	if akc.Value == "" {
@@ -65,10 +87,61 @@ func (akc ApiKeyCredential) inject(req *http.Request, value string) {
 	case schema.APIKeyInHeader:
 		req.Header.Set(akc.Name, value)
 	case schema.APIKeyInQuery:
-		endpoint := req.URL
-		q := endpoint.Query()
-		q.Add(akc.Name, value)
-		endpoint.RawQuery = q.Encode()
-		req.URL = endpoint
+		// append the key to the raw query to keep the order and encoding of existing parameters
+		endpoint := *req.URL
+		query := url.Values{akc.Name: []string{value}}.Encode()
+		if endpoint.RawQuery == "" {
+			endpoint.RawQuery = query
+		} else {
+			endpoint.RawQuery += "&" + query
+		}
+		req.URL = &endpoint
+	}
+}
+
+// replace the primary key of the request with the secondary key.
+func (akc ApiKeyCredential) injectSecondary(req *http.Request) {
+	if akc.In != schema.APIKeyInQuery {
+		akc.inject(req, akc.rotation.secondary)
+
+		return
 	}
+
+	primaryQuery := url.Values{akc.Name: []string{akc.Value}}.Encode()
+	pairs := strings.Split(req.URL.RawQuery, "&")
+	rawQuery := make([]string, 0, len(pairs))
+	for _, pair := range pairs {
+		if pair != "" && pair != primaryQuery {
+			rawQuery = append(rawQuery, pair)
+		}
+	}
+
+	endpoint := *req.URL
+	endpoint.RawQuery = strings.Join(rawQuery, "&")
+	req.URL = &endpoint
+	akc.inject(req, akc.rotation.secondary)
+}
+
+// MaskURL returns a copy of the URL with the masked API key if the key is in the query string.
+func (akc ApiKeyCredential) MaskURL(endpoint *url.URL) *url.URL {
+	if akc.In != schema.APIKeyInQuery || akc.Value == "" || endpoint == nil {
+		return endpoint
+	}
+
+	q := endpoint.Query()
+	values, ok := q[akc.Name]
+	if !ok {
+		return endpoint
+	}
+
+	for i, value := range values {
+		if value == akc.Value || (akc.rotation != nil && value == akc.rotation.secondary) {
+			values[i] = utils.MaskString(value)
+		}
+	}
+
+	result := *endpoint
+	result.RawQuery = q.Encode()
+
+	return &result
 }
@@ -0,0 +1,36 @@
+package security
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestApiKeyCredentialInQuery(t *testing.T) {
+	cred, err := NewApiKeyCredential(http.DefaultClient, schema.NewAPIKeyAuthConfig("api_key", schema.APIKeyInQuery, utils.NewEnvStringValue("secret-key")))
+	assert.NilError(t, err)
+
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, "https://api.example.com/v1/pets?tags=b&tags=a&name=a%20b", nil)
+	assert.NilError(t, err)
+	originalURL := req.URL
+
+	ok, err := cred.Inject(req)
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.Equal(t, "https://api.example.com/v1/pets?tags=b&tags=a&name=a%20b&api_key=secret-key", req.URL.String())
+	// the original URL isn't modified
+	assert.Equal(t, "https://api.example.com/v1/pets?tags=b&tags=a&name=a%20b", originalURL.String())
+
+	masked := cred.MaskURL(req.URL)
+	assert.Equal(t, "https://api.example.com/v1/pets?api_key=s%2A%2A%2A%2A%2A%2A%2A%2A%2A&name=a+b&tags=b&tags=a", masked.String())
+	assert.Equal(t, "secret-key", req.URL.Query().Get("api_key"))
+
+	mockReq, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, "https://api.example.com/v1/pets", nil)
+	assert.NilError(t, err)
+	assert.Assert(t, cred.InjectMock(mockReq))
+	assert.Assert(t, mockReq.URL.Query().Get("api_key") != "secret-key")
+}
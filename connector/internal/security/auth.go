@@ -6,6 +6,7 @@ import (
 	"net/http"
 
 	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/connector"
 )
 
 // Credential abstracts an authentication credential interface.
@@ -18,8 +19,8 @@ type Credential interface {
 	InjectMock(request *http.Request) bool
 }
 
-// NewCredential creates a generic credential from the security scheme.
-func NewCredential(ctx context.Context, httpClient *http.Client, security schema.SecurityScheme) (Credential, bool, error) {
+// NewCredential creates a generic credential from the security scheme. The token store is optional.
+func NewCredential(ctx context.Context, httpClient *http.Client, security schema.SecurityScheme, tokenStore TokenStore) (Credential, bool, error) {
 	if security.SecuritySchemer == nil {
 		return nil, false, errors.New("empty security scheme")
 	}
@@ -32,33 +33,72 @@ func NewCredential(ctx context.Context, httpClient *http.Client, security schema
 	case *schema.BasicAuthConfig:
 		cred, err := NewBasicCredential(httpClient, ss)
 
+		return cred, err != nil, err
+	case *schema.DigestAuthConfig:
+		cred, err := NewDigestCredential(httpClient, ss)
+
 		return cred, err != nil, err
 	case *schema.HTTPAuthConfig:
 		cred, err := NewHTTPCredential(httpClient, ss)
 
 		return cred, err != nil, err
-	case *schema.OAuth2Config:
-		var headerForwardingRequired bool
-		for flowType, flow := range ss.Flows {
-			if flowType != schema.ClientCredentialsFlow {
-				headerForwardingRequired = true
-			}
+	case *schema.HMACAuthConfig:
+		cred, err := NewHMACCredential(httpClient, ss)
+
+		return cred, err != nil, err
+	case *schema.JWTAuthConfig:
+		cred, err := NewJWTCredential(httpClient, ss)
 
-			cred, err := NewOAuth2Client(ctx, httpClient, flowType, &flow)
+		return cred, err != nil, err
+	case *schema.OAuth2Config:
+		flowType, flow, ok := selectOAuthFlow(ss.Flows)
+		if !ok {
+			break
+		}
 
-			return cred, headerForwardingRequired || err != nil, err
+		cred, err := NewOAuth2Client(ctx, httpClient, flowType, &flow, tokenStore)
+		if err != nil {
+			return nil, true, err
 		}
+
+		// the access token must be forwarded if the connector can't request tokens itself
+		return cred, cred.isEmpty && flowType != schema.ClientCredentialsFlow, nil
 	case *schema.CookieAuthConfig:
-		cred, err := NewCookieCredential(httpClient)
+		cred, err := NewCookieCredential(httpClient, ss)
+		if err != nil {
+			return nil, true, err
+		}
 
-		return cred, true, err
+		// the Cookie header must be forwarded if the connector can't log in itself
+		return cred, cred.login == nil, nil
 	case *schema.MutualTLSAuthConfig:
-		return NewNoopCredential(httpClient), false, nil
+		cred, err := NewMutualTLSCredential(httpClient, ss, connector.GetLogger(ctx))
+
+		return cred, false, err
 	}
 
 	return NewNoopCredential(httpClient), true, nil
 }
 
+// selectOAuthFlow selects the OAuth2 flow which the connector can request access tokens with.
+// Flows with configured credentials are preferred in the order of client credentials, password and authorization code.
+func selectOAuthFlow(flows map[schema.OAuthFlowType]schema.OAuthFlow) (schema.OAuthFlowType, schema.OAuthFlow, bool) {
+	flowTypes := []schema.OAuthFlowType{schema.ClientCredentialsFlow, schema.PasswordFlow, schema.AuthorizationCodeFlow, schema.ImplicitFlow}
+	for _, flowType := range flowTypes {
+		if flow, ok := flows[flowType]; ok && hasOAuth2Credentials(flowType, &flow) {
+			return flowType, flow, true
+		}
+	}
+
+	for _, flowType := range flowTypes {
+		if flow, ok := flows[flowType]; ok {
+			return flowType, flow, true
+		}
+	}
+
+	return "", schema.OAuthFlow{}, false
+}
+
 // NoopCredential implements a no-op credential.
 type NoopCredential struct {
 	client *http.Client
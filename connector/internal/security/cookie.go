@@ -0,0 +1,321 @@
+package security
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+)
+
+const (
+	defaultCookieLoginTTL = time.Hour
+	// the session is renewed before cookies expire to avoid being rejected due to clock skew
+	cookieLoginExpiryLeeway = 10 * time.Second
+)
+
+var cookieLoginTemplateRegex = regexp.MustCompile(`\{\{\s*env\.(\w+)\s*\}\}`)
+
+// CookieCredential presents a cookie credential. If the login request is configured,
+// the connector executes it to obtain session cookies when they are missing or expired.
+// Otherwise the Cookie header must be forwarded from the Hasura engine.
+type CookieCredential struct {
+	client *http.Client
+	login  *cookieLogin
+}
+
+var _ Credential = &CookieCredential{}
+
+type cookieLogin struct {
+	client      *http.Client
+	url         string
+	method      string
+	headers     map[string]string
+	body        string
+	contentType string
+	cookieNames []string
+	ttl         time.Duration
+	now         func() time.Time
+
+	cookies   []*http.Cookie
+	expiresAt time.Time
+	lock      sync.Mutex
+}
+
+// NewCookieCredential creates a new CookieCredential instance.
+func NewCookieCredential(client *http.Client, config *schema.CookieAuthConfig) (*CookieCredential, error) {
+	cred := &CookieCredential{
+		client: client,
+	}
+
+	if config == nil || config.Login == nil {
+		return cred, nil
+	}
+
+	loginURL, err := config.Login.URL.Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CookieCredential: login.url: %w", err)
+	}
+
+	// the credential is inactive if the login URL is empty
+	if loginURL == "" {
+		return cred, nil
+	}
+
+	login := &cookieLogin{
+		client:      newCookieLoginClient(client),
+		url:         loginURL,
+		method:      strings.ToUpper(config.Login.Method),
+		headers:     make(map[string]string),
+		contentType: config.Login.ContentType,
+		cookieNames: config.Login.Cookies,
+		ttl:         time.Duration(config.Login.TTL) * time.Second,
+		now:         time.Now,
+	}
+
+	if login.method == "" {
+		login.method = http.MethodPost
+	}
+
+	if login.contentType == "" {
+		login.contentType = schema.ContentTypeJSON
+	}
+
+	if login.ttl <= 0 {
+		login.ttl = defaultCookieLoginTTL
+	}
+
+	for key, header := range config.Login.Headers {
+		value, err := header.Get()
+		if err != nil {
+			return nil, fmt.Errorf("failed to create CookieCredential: login.headers.%s: %w", key, err)
+		}
+
+		login.headers[key] = value
+	}
+
+	login.body = renderCookieLoginBody(config.Login.Body, login.contentType)
+	cred.login = login
+	cred.client = newCookieSessionClient(client, login)
+
+	return cred, nil
+}
+
+// GetClient gets the HTTP client that is compatible with the current credential.
+func (cc CookieCredential) GetClient() *http.Client {
+	return cc.client
+}
+
+// Inject the credential into the incoming request
+func (cc CookieCredential) Inject(req *http.Request) (bool, error) {
+	if cc.login == nil {
+		return false, nil
+	}
+
+	cookies, err := cc.login.Cookies(req)
+	if err != nil {
+		return false, err
+	}
+
+	injectCookies(req, cookies)
+
+	return true, nil
+}
+
+// InjectMock injects the mock credential into the incoming request for explain APIs.
+// The login request isn't executed, so only cached session cookies are injected.
+func (cc CookieCredential) InjectMock(req *http.Request) bool {
+	if cc.login == nil {
+		return false
+	}
+
+	cc.login.lock.Lock()
+	cookies := make([]*http.Cookie, len(cc.login.cookies))
+	for i, cookie := range cc.login.cookies {
+		cookies[i] = &http.Cookie{
+			Name:  cookie.Name,
+			Value: utils.MaskString(cookie.Value),
+		}
+	}
+	cc.login.lock.Unlock()
+
+	injectCookies(req, cookies)
+
+	return true
+}
+
+// Cookies returns cached session cookies, or executes the login request if they are missing or expired.
+func (cl *cookieLogin) Cookies(req *http.Request) ([]*http.Cookie, error) {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	if len(cl.cookies) > 0 && cl.now().Before(cl.expiresAt) {
+		return cl.cookies, nil
+	}
+
+	var body io.Reader
+	if cl.body != "" {
+		body = strings.NewReader(cl.body)
+	}
+
+	loginReq, err := http.NewRequestWithContext(req.Context(), cl.method, cl.url, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the login request: %w", err)
+	}
+
+	if cl.body != "" {
+		loginReq.Header.Set(schema.ContentTypeHeader, cl.contentType)
+	}
+
+	for key, value := range cl.headers {
+		loginReq.Header.Set(key, value)
+	}
+
+	resp, err := cl.client.Do(loginReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute the login request: %w", err)
+	}
+	defer func() {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}()
+
+	// session cookies are usually set in redirect responses of login forms
+	if resp.StatusCode >= http.StatusBadRequest {
+		return nil, fmt.Errorf("the login request failed with status %d", resp.StatusCode)
+	}
+
+	now := cl.now()
+	expiresAt := now.Add(cl.ttl)
+	var cookies []*http.Cookie
+	for _, cookie := range resp.Cookies() {
+		if cookie.MaxAge < 0 || (len(cl.cookieNames) > 0 && !slices.Contains(cl.cookieNames, cookie.Name)) {
+			continue
+		}
+
+		switch {
+		case cookie.MaxAge > 0:
+			expiresAt = minTime(expiresAt, now.Add(time.Duration(cookie.MaxAge)*time.Second))
+		case !cookie.Expires.IsZero():
+			expiresAt = minTime(expiresAt, cookie.Expires)
+		}
+
+		cookies = append(cookies, &http.Cookie{
+			Name:  cookie.Name,
+			Value: cookie.Value,
+		})
+	}
+
+	if len(cookies) == 0 {
+		return nil, errors.New("the login response doesn't contain session cookies")
+	}
+
+	cl.cookies = cookies
+	cl.expiresAt = expiresAt.Add(-cookieLoginExpiryLeeway)
+
+	return cookies, nil
+}
+
+// Invalidate removes cached session cookies, so the next request logs in again.
+func (cl *cookieLogin) Invalidate() {
+	cl.lock.Lock()
+	defer cl.lock.Unlock()
+
+	cl.cookies = nil
+}
+
+// inject cookies into the request. Cookies in the forwarded Cookie header take precedence.
+func injectCookies(req *http.Request, cookies []*http.Cookie) {
+	for _, cookie := range cookies {
+		if _, err := req.Cookie(cookie.Name); err == nil {
+			continue
+		}
+
+		req.AddCookie(cookie)
+	}
+}
+
+// replace {{env.NAME}} placeholders with environment variables. Values are escaped if the body is JSON or a form.
+func renderCookieLoginBody(template string, contentType string) string {
+	return cookieLoginTemplateRegex.ReplaceAllStringFunc(template, func(match string) string {
+		value := os.Getenv(cookieLoginTemplateRegex.FindStringSubmatch(match)[1])
+		switch {
+		case utils.IsContentTypeJSON(contentType):
+			rawValue, _ := json.Marshal(value)
+
+			return string(rawValue[1 : len(rawValue)-1])
+		case strings.HasPrefix(contentType, schema.ContentTypeFormURLEncoded):
+			return url.QueryEscape(value)
+		default:
+			return value
+		}
+	})
+}
+
+// the login client doesn't follow redirects, so session cookies in redirect responses aren't lost.
+func newCookieLoginClient(httpClient *http.Client) *http.Client {
+	client := &http.Client{}
+	if httpClient != nil {
+		*client = *httpClient
+	}
+
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return http.ErrUseLastResponse
+	}
+
+	return client
+}
+
+// newCookieSessionClient creates a copy of the HTTP client which invalidates session cookies
+// if the upstream rejects the request with 401.
+func newCookieSessionClient(httpClient *http.Client, login *cookieLogin) *http.Client {
+	client := &http.Client{}
+	if httpClient != nil {
+		*client = *httpClient
+	}
+
+	client.Transport = &cookieSessionTransport{
+		base:  client.Transport,
+		login: login,
+	}
+
+	return client
+}
+
+type cookieSessionTransport struct {
+	base  http.RoundTripper
+	login *cookieLogin
+}
+
+// RoundTrip implements http.RoundTripper.
+func (cst *cookieSessionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := cst.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err == nil && resp.StatusCode == http.StatusUnauthorized {
+		cst.login.Invalidate()
+	}
+
+	return resp, err
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+
+	return a
+}
@@ -0,0 +1,134 @@
+package security
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestCookieCredential(t *testing.T) {
+	t.Setenv("LOGIN_PASSWORD", `p"ss`)
+
+	var logins atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			body, err := io.ReadAll(r.Body)
+			assert.NilError(t, err)
+			assert.Equal(t, `{"username":"admin","password":"p\"ss"}`, string(body))
+			assert.Equal(t, "application/json", r.Header.Get(schema.ContentTypeHeader))
+			assert.Equal(t, "bar", r.Header.Get("X-Foo"))
+
+			count := logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "session-" + strconv.Itoa(int(count))})
+			http.SetCookie(w, &http.Cookie{Name: "tracking", Value: "1"})
+			http.Redirect(w, r, "/home", http.StatusFound)
+		case "/pets":
+			cookie, err := r.Cookie("session")
+			if err != nil || cookie.Value == "session-1" && r.URL.Query().Get("expired") == "true" {
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+
+			_, _ = w.Write([]byte(cookie.Value))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	config := schema.NewCookieAuthConfig()
+	config.Login = &schema.CookieLoginConfig{
+		URL: utils.NewEnvStringValue(server.URL + "/login"),
+		Headers: map[string]utils.EnvString{
+			"X-Foo": utils.NewEnvStringValue("bar"),
+		},
+		Body:    `{"username":"admin","password":"{{ env.LOGIN_PASSWORD }}"}`,
+		Cookies: []string{"session"},
+	}
+	assert.NilError(t, config.Validate())
+
+	cred, err := NewCookieCredential(http.DefaultClient, config)
+	assert.NilError(t, err)
+
+	sendRequest := func(query string) (*http.Response, string) {
+		req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, server.URL+"/pets"+query, nil)
+		assert.NilError(t, err)
+
+		ok, err := cred.Inject(req)
+		assert.NilError(t, err)
+		assert.Assert(t, ok)
+
+		resp, err := cred.GetClient().Do(req)
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+
+		return resp, string(body)
+	}
+
+	// the session is reused until the upstream rejects it
+	for range 2 {
+		resp, body := sendRequest("")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "session-1", body)
+	}
+	assert.Equal(t, int32(1), logins.Load())
+
+	resp, _ := sendRequest("?expired=true")
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp, body := sendRequest("?expired=true")
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "session-2", body)
+	assert.Equal(t, int32(2), logins.Load())
+
+	// cookies in the forwarded Cookie header take precedence
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, server.URL+"/pets", nil)
+	assert.NilError(t, err)
+	req.Header.Set("Cookie", "session=forwarded")
+	_, err = cred.Inject(req)
+	assert.NilError(t, err)
+	assert.Equal(t, "session=forwarded", req.Header.Get("Cookie"))
+
+	mockReq, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, server.URL+"/pets", nil)
+	assert.NilError(t, err)
+	assert.Assert(t, cred.InjectMock(mockReq))
+	assert.Equal(t, "session=s********", mockReq.Header.Get("Cookie"))
+
+	t.Run("login_failed", func(t *testing.T) {
+		config := schema.NewCookieAuthConfig()
+		config.Login = &schema.CookieLoginConfig{
+			URL: utils.NewEnvStringValue(server.URL + "/unknown"),
+		}
+
+		cred, err := NewCookieCredential(http.DefaultClient, config)
+		assert.NilError(t, err)
+
+		req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, server.URL+"/pets", nil)
+		assert.NilError(t, err)
+		_, err = cred.Inject(req)
+		assert.ErrorContains(t, err, "the login request failed with status 404")
+	})
+
+	t.Run("without_login", func(t *testing.T) {
+		cred, err := NewCookieCredential(http.DefaultClient, schema.NewCookieAuthConfig())
+		assert.NilError(t, err)
+
+		ok, err := cred.Inject(req)
+		assert.NilError(t, err)
+		assert.Assert(t, !ok)
+		assert.Assert(t, !cred.InjectMock(req))
+	})
+}
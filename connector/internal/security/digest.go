@@ -0,0 +1,400 @@
+package security
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+const (
+	digestAuthenticateHeader = "WWW-Authenticate"
+	digestQOPAuth            = "auth"
+	digestQOPAuthInt         = "auth-int"
+)
+
+var errDigestChallengeUnsupported = errors.New("unsupported digest challenge")
+
+// DigestCredential represents the digest authentication credential.
+// The HTTP client handles the challenge-response handshake of the server.
+// The latest challenge is reused for next requests with an incremented nonce count.
+type DigestCredential struct {
+	username string
+	password string
+	client   *http.Client
+
+	challenge  *digestChallenge
+	nonceCount uint32
+	lock       sync.Mutex
+}
+
+var _ Credential = &DigestCredential{}
+
+// NewDigestCredential creates a new DigestCredential instance.
+func NewDigestCredential(httpClient *http.Client, config *schema.DigestAuthConfig) (*DigestCredential, error) {
+	user, err := config.Username.Get()
+	if err != nil {
+		return nil, fmt.Errorf("DigestAuthConfig.Username: %w", err)
+	}
+
+	password, err := config.Password.Get()
+	if err != nil {
+		return nil, fmt.Errorf("DigestAuthConfig.Password: %w", err)
+	}
+
+	result := &DigestCredential{
+		username: user,
+		password: password,
+	}
+
+	client := &http.Client{}
+	if httpClient != nil {
+		*client = *httpClient
+	}
+
+	client.Transport = &digestTransport{
+		base:       client.Transport,
+		credential: result,
+	}
+	result.client = client
+
+	return result, nil
+}
+
+// GetClient gets the HTTP client that is compatible with the current credential.
+func (dc *DigestCredential) GetClient() *http.Client {
+	return dc.client
+}
+
+// Inject the credential into the incoming request.
+// The authorization header is evaluated by the HTTP client after the challenge of the server.
+func (dc *DigestCredential) Inject(req *http.Request) (bool, error) {
+	return dc.username != "", nil
+}
+
+// InjectMock injects the mock credential into the incoming request for explain APIs.
+func (dc *DigestCredential) InjectMock(req *http.Request) bool {
+	if dc.username == "" {
+		return false
+	}
+
+	req.Header.Set(schema.AuthorizationHeader, `Digest username="xxx", response="xxx"`)
+
+	return true
+}
+
+// authorize evaluates the authorization header from the cached challenge.
+// Returns an empty string if there is no challenge.
+func (dc *DigestCredential) authorize(req *http.Request) (string, error) {
+	dc.lock.Lock()
+	challenge := dc.challenge
+	dc.nonceCount++
+	nonceCount := dc.nonceCount
+	dc.lock.Unlock()
+
+	if challenge == nil {
+		return "", nil
+	}
+
+	return challenge.authorize(req, dc.username, dc.password, nonceCount)
+}
+
+// setChallenge stores the new challenge and resets the nonce count.
+func (dc *DigestCredential) setChallenge(challenge *digestChallenge) {
+	dc.lock.Lock()
+	dc.challenge = challenge
+	dc.nonceCount = 0
+	dc.lock.Unlock()
+}
+
+// digestTransport sends the digest authorization header of the cached challenge.
+// If the server responds a new challenge, the request is retried once with the new challenge.
+type digestTransport struct {
+	base       http.RoundTripper
+	credential *DigestCredential
+}
+
+// RoundTrip implements http.RoundTripper.
+func (dt *digestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := dt.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	if dt.credential.username == "" {
+		return base.RoundTrip(req)
+	}
+
+	firstReq := req
+	authorization, err := dt.credential.authorize(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if authorization != "" {
+		firstReq = req.Clone(req.Context())
+		firstReq.Header.Set(schema.AuthorizationHeader, authorization)
+	}
+
+	resp, err := base.RoundTrip(firstReq)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	challenge, err := parseDigestChallenge(resp.Header.Values(digestAuthenticateHeader))
+	if err != nil {
+		return resp, nil
+	}
+
+	// the body can't be sent again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	dt.credential.setChallenge(challenge)
+
+	authorization, err = dt.credential.authorize(req)
+	if err != nil {
+		return resp, nil
+	}
+
+	retryReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retryReq.Body, err = req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	retryReq.Header.Set(schema.AuthorizationHeader, authorization)
+
+	return base.RoundTrip(retryReq)
+}
+
+type digestChallenge struct {
+	Realm     string
+	Nonce     string
+	Opaque    string
+	Algorithm string
+	QOP       string
+	newHash   func() hash.Hash
+	session   bool
+}
+
+// parseDigestChallenge parses the first supported digest challenge from WWW-Authenticate headers.
+func parseDigestChallenge(headers []string) (*digestChallenge, error) {
+	for _, header := range headers {
+		for _, rawChallenge := range splitDigestChallenges(header) {
+			scheme, rawParams, _ := strings.Cut(strings.TrimSpace(rawChallenge), " ")
+			if !strings.EqualFold(scheme, "digest") {
+				continue
+			}
+
+			params := parseDigestParams(rawParams)
+			challenge := &digestChallenge{
+				Realm:     params["realm"],
+				Nonce:     params["nonce"],
+				Opaque:    params["opaque"],
+				Algorithm: params["algorithm"],
+			}
+
+			if challenge.Nonce == "" {
+				continue
+			}
+
+			algorithm := strings.ToUpper(challenge.Algorithm)
+			algorithm, challenge.session = strings.CutSuffix(algorithm, "-SESS")
+			switch algorithm {
+			case "", "MD5":
+				challenge.newHash = md5.New
+			case "SHA-256":
+				challenge.newHash = sha256.New
+			case "SHA-512-256":
+				challenge.newHash = sha512.New512_256
+			default:
+				continue
+			}
+
+			if qop, ok := params["qop"]; ok {
+				options := strings.Split(qop, ",")
+				for i, option := range options {
+					options[i] = strings.TrimSpace(option)
+				}
+
+				switch {
+				case slices.Contains(options, digestQOPAuth):
+					challenge.QOP = digestQOPAuth
+				case slices.Contains(options, digestQOPAuthInt):
+					challenge.QOP = digestQOPAuthInt
+				default:
+					continue
+				}
+			}
+
+			return challenge, nil
+		}
+	}
+
+	return nil, errDigestChallengeUnsupported
+}
+
+func (dc *digestChallenge) authorize(req *http.Request, username string, password string, nonceCount uint32) (string, error) {
+	cnonce, err := generateDigestCNonce()
+	if err != nil {
+		return "", err
+	}
+
+	nc := fmt.Sprintf("%08x", nonceCount)
+	uri := req.URL.RequestURI()
+
+	ha1 := dc.hash(username + ":" + dc.Realm + ":" + password)
+	if dc.session {
+		ha1 = dc.hash(ha1 + ":" + dc.Nonce + ":" + cnonce)
+	}
+
+	a2 := req.Method + ":" + uri
+	if dc.QOP == digestQOPAuthInt {
+		var body []byte
+		if req.GetBody != nil {
+			reader, err := req.GetBody()
+			if err != nil {
+				return "", err
+			}
+
+			body, err = io.ReadAll(reader)
+			_ = reader.Close()
+			if err != nil {
+				return "", err
+			}
+		}
+
+		a2 += ":" + dc.hash(string(body))
+	}
+	ha2 := dc.hash(a2)
+
+	var response string
+	if dc.QOP == "" {
+		response = dc.hash(ha1 + ":" + dc.Nonce + ":" + ha2)
+	} else {
+		response = dc.hash(strings.Join([]string{ha1, dc.Nonce, nc, cnonce, dc.QOP, ha2}, ":"))
+	}
+
+	params := []string{
+		fmt.Sprintf("username=%q", username),
+		fmt.Sprintf("realm=%q", dc.Realm),
+		fmt.Sprintf("nonce=%q", dc.Nonce),
+		fmt.Sprintf("uri=%q", uri),
+	}
+
+	if dc.Algorithm != "" {
+		params = append(params, "algorithm="+dc.Algorithm)
+	}
+
+	params = append(params, fmt.Sprintf("response=%q", response))
+	if dc.Opaque != "" {
+		params = append(params, fmt.Sprintf("opaque=%q", dc.Opaque))
+	}
+
+	if dc.QOP != "" {
+		params = append(params, "qop="+dc.QOP, "nc="+nc, fmt.Sprintf("cnonce=%q", cnonce))
+	}
+
+	return "Digest " + strings.Join(params, ", "), nil
+}
+
+func (dc *digestChallenge) hash(value string) string {
+	h := dc.newHash()
+	_, _ = h.Write([]byte(value))
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// splitDigestChallenges splits many challenges in a WWW-Authenticate header, e.g. Digest realm="a", Basic realm="b".
+// A new challenge starts with a token which is followed by a space instead of the equal sign.
+func splitDigestChallenges(header string) []string {
+	var results []string
+	var start int
+	var quoted bool
+	for i := 0; i < len(header); i++ {
+		switch header[i] {
+		case '"':
+			quoted = !quoted
+		case '\\':
+			i++
+		case ',':
+			if quoted {
+				continue
+			}
+
+			rest := strings.TrimLeft(header[i+1:], " ")
+			token, _, _ := strings.Cut(rest, " ")
+			if token != "" && !strings.Contains(token, "=") {
+				results = append(results, header[start:i])
+				start = len(header) - len(rest)
+			}
+		}
+	}
+
+	return append(results, header[start:])
+}
+
+// parseDigestParams parses comma-separated auth params with quoted values.
+func parseDigestParams(input string) map[string]string {
+	results := make(map[string]string)
+	for input != "" {
+		input = strings.TrimLeft(input, " ,")
+		key, rest, ok := strings.Cut(input, "=")
+		if !ok {
+			break
+		}
+
+		key = strings.ToLower(strings.TrimSpace(key))
+		rest = strings.TrimLeft(rest, " ")
+
+		var value strings.Builder
+		if strings.HasPrefix(rest, `"`) {
+			i := 1
+			for ; i < len(rest) && rest[i] != '"'; i++ {
+				if rest[i] == '\\' && i+1 < len(rest) {
+					i++
+				}
+				value.WriteByte(rest[i])
+			}
+			input = rest[min(i+1, len(rest)):]
+		} else {
+			end := strings.IndexByte(rest, ',')
+			if end < 0 {
+				end = len(rest)
+			}
+			value.WriteString(strings.TrimSpace(rest[:end]))
+			input = rest[end:]
+		}
+
+		results[key] = value.String()
+	}
+
+	return results
+}
+
+func generateDigestCNonce() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(buf), nil
+}
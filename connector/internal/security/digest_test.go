@@ -0,0 +1,96 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestDigestCredential(t *testing.T) {
+	testCases := []struct {
+		Name      string
+		Algorithm string
+		NewHash   func() hash.Hash
+	}{
+		{Name: "md5", Algorithm: "MD5", NewHash: md5.New},
+		{Name: "sha256", Algorithm: "SHA-256", NewHash: sha256.New},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var challenges atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				params := parseDigestParams(strings.TrimPrefix(r.Header.Get(schema.AuthorizationHeader), "Digest "))
+				if params["response"] == "" {
+					challenges.Add(1)
+					w.Header().Set(digestAuthenticateHeader, fmt.Sprintf(`Basic realm="test", Digest realm="test", qop="auth,auth-int", algorithm=%s, nonce="abc123", opaque="xyz"`, tc.Algorithm))
+					w.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				digest := func(value string) string {
+					h := tc.NewHash()
+					_, _ = h.Write([]byte(value))
+
+					return hex.EncodeToString(h.Sum(nil))
+				}
+
+				ha1 := digest("user:test:secret")
+				ha2 := digest(r.Method + ":" + params["uri"])
+				expected := digest(strings.Join([]string{ha1, params["nonce"], params["nc"], params["cnonce"], params["qop"], ha2}, ":"))
+				if params["username"] != "user" || params["opaque"] != "xyz" || params["response"] != expected {
+					w.WriteHeader(http.StatusUnauthorized)
+
+					return
+				}
+
+				body, _ := io.ReadAll(r.Body)
+				_, _ = w.Write(body)
+			}))
+			defer server.Close()
+
+			cred, err := NewDigestCredential(http.DefaultClient, schema.NewDigestAuthConfig(utils.NewEnvStringValue("user"), utils.NewEnvStringValue("secret")))
+			assert.NilError(t, err)
+
+			for i := range 2 {
+				req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, server.URL+"/pets?limit=10", bytes.NewBufferString(`{"name":"Dog"}`))
+				assert.NilError(t, err)
+
+				ok, err := cred.Inject(req)
+				assert.NilError(t, err)
+				assert.Assert(t, ok)
+
+				resp, err := cred.GetClient().Do(req)
+				assert.NilError(t, err)
+				body, err := io.ReadAll(resp.Body)
+				_ = resp.Body.Close()
+				assert.NilError(t, err)
+				assert.Equal(t, http.StatusOK, resp.StatusCode, "request %d", i)
+				assert.Equal(t, `{"name":"Dog"}`, string(body))
+			}
+
+			// the cached challenge is reused for the next request
+			assert.Equal(t, int32(1), challenges.Load())
+
+			mockReq, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, server.URL, nil)
+			assert.NilError(t, err)
+			assert.Assert(t, cred.InjectMock(mockReq))
+			assert.Assert(t, strings.HasPrefix(mockReq.Header.Get(schema.AuthorizationHeader), "Digest "))
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package security
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"golang.org/x/oauth2"
+)
+
+const (
+	dpopHeader         = "DPoP"
+	dpopNonceHeader    = "DPoP-Nonce"
+	dpopNonceErrorCode = "use_dpop_nonce"
+)
+
+// DPoPSigner generates DPoP proof JWTs which bind access tokens to the key of the client.
+type DPoPSigner struct {
+	algorithm schema.DPoPAlgorithm
+	key       crypto.Signer
+	jwk       map[string]any
+	nonce     string
+	lock      sync.RWMutex
+}
+
+// NewDPoPSigner creates a DPoP signer from the config.
+func NewDPoPSigner(config *schema.DPoPConfig) (*DPoPSigner, error) {
+	algorithm := config.Algorithm
+	if algorithm == "" {
+		algorithm = schema.DPoPES256
+	}
+
+	var keyPem string
+	if config.PrivateKey != nil {
+		var err error
+		keyPem, err = config.PrivateKey.GetOrDefault("")
+		if err != nil {
+			return nil, fmt.Errorf("privateKey: %w", err)
+		}
+	}
+
+	var key crypto.Signer
+	var err error
+	if keyPem == "" {
+		key, err = generateDPoPKey(algorithm)
+	} else {
+		key, err = parsePrivateKey([]byte(keyPem))
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	signer := &DPoPSigner{
+		algorithm: algorithm,
+		key:       key,
+	}
+
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		if algorithm != schema.DPoPES256 || k.Curve != elliptic.P256() {
+			return nil, fmt.Errorf("the private key isn't compatible with the algorithm %s", algorithm)
+		}
+
+		signer.jwk = map[string]any{
+			"kty": "EC",
+			"crv": "P-256",
+			"x":   base64.RawURLEncoding.EncodeToString(k.X.FillBytes(make([]byte, 32))),
+			"y":   base64.RawURLEncoding.EncodeToString(k.Y.FillBytes(make([]byte, 32))),
+		}
+	case *rsa.PrivateKey:
+		if algorithm != schema.DPoPRS256 {
+			return nil, fmt.Errorf("the private key isn't compatible with the algorithm %s", algorithm)
+		}
+
+		signer.jwk = map[string]any{
+			"kty": "RSA",
+			"n":   base64.RawURLEncoding.EncodeToString(k.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(k.E)).Bytes()),
+		}
+	default:
+		return nil, errors.New("unsupported private key type, expected ECDSA or RSA")
+	}
+
+	return signer, nil
+}
+
+// Sign creates a DPoP proof of the HTTP request. The access token hash is included if the token isn't empty.
+func (ds *DPoPSigner) Sign(method string, requestURL *url.URL, accessToken string) (string, error) {
+	htu := url.URL{
+		Scheme: requestURL.Scheme,
+		Host:   requestURL.Host,
+		Path:   requestURL.Path,
+	}
+
+	header := map[string]any{
+		"typ": "dpop+jwt",
+		"alg": ds.algorithm,
+		"jwk": ds.jwk,
+	}
+
+	claims := map[string]any{
+		"jti": uuid.NewString(),
+		"htm": method,
+		"htu": htu.String(),
+		"iat": time.Now().Unix(),
+	}
+
+	if accessToken != "" {
+		ath := sha256.Sum256([]byte(accessToken))
+		claims["ath"] = base64.RawURLEncoding.EncodeToString(ath[:])
+	}
+
+	ds.lock.RLock()
+	if ds.nonce != "" {
+		claims["nonce"] = ds.nonce
+	}
+	ds.lock.RUnlock()
+
+	return signJWT(ds.key, header, claims)
+}
+
+// SetNonce stores the server-provided nonce for next proofs.
+func (ds *DPoPSigner) SetNonce(nonce string) {
+	if nonce == "" {
+		return
+	}
+
+	ds.lock.Lock()
+	ds.nonce = nonce
+	ds.lock.Unlock()
+}
+
+// dpopTransport attaches DPoP proofs to outgoing requests.
+// If the token source is set, the access token is sent with the DPoP authorization scheme.
+type dpopTransport struct {
+	base   http.RoundTripper
+	signer *DPoPSigner
+	source oauth2.TokenSource
+}
+
+// RoundTrip implements http.RoundTripper.
+// If the server rejects the proof with the use_dpop_nonce error, the request is retried once with a fresh proof of the new nonce.
+func (dt *dpopTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var accessToken string
+	if dt.source != nil {
+		token, err := dt.source.Token()
+		if err != nil {
+			return nil, err
+		}
+		accessToken = token.AccessToken
+	}
+
+	resp, err := dt.roundTrip(req, req.Body, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	// the request body can't be sent again without GetBody
+	if !isDPoPNonceError(resp) || (req.Body != nil && req.Body != http.NoBody && req.GetBody == nil) {
+		return resp, nil
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	body := req.Body
+	if req.GetBody != nil {
+		body, err = req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the request body to retry with the DPoP nonce: %w", err)
+		}
+	}
+
+	return dt.roundTrip(req, body, accessToken)
+}
+
+func (dt *dpopTransport) roundTrip(req *http.Request, body io.ReadCloser, accessToken string) (*http.Response, error) {
+	proof, err := dt.signer.Sign(req.Method, req.URL, accessToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the DPoP proof: %w", err)
+	}
+
+	newReq := req.Clone(req.Context())
+	newReq.Body = body
+	newReq.Header.Set(dpopHeader, proof)
+	if accessToken != "" {
+		newReq.Header.Set(schema.AuthorizationHeader, "DPoP "+accessToken)
+	}
+
+	base := dt.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(newReq)
+	if err != nil {
+		return nil, err
+	}
+
+	dt.signer.SetNonce(resp.Header.Get(dpopNonceHeader))
+
+	return resp, nil
+}
+
+// isDPoPNonceError checks if the server requires a nonce in the DPoP proof with a new DPoP-Nonce header.
+// Authorization servers respond 400 with the use_dpop_nonce error in the body, and resource servers respond 401
+// with the error in the WWW-Authenticate header. See https://datatracker.ietf.org/doc/html/rfc9449#section-8.
+func isDPoPNonceError(resp *http.Response) bool {
+	if resp.Header.Get(dpopNonceHeader) == "" {
+		return false
+	}
+
+	switch resp.StatusCode {
+	case http.StatusUnauthorized:
+		return strings.Contains(resp.Header.Get("WWW-Authenticate"), dpopNonceErrorCode)
+	case http.StatusBadRequest:
+		if resp.Body == nil {
+			return false
+		}
+
+		// the read part of the body is restored, so the caller can read the whole body if the error isn't use_dpop_nonce
+		rawBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+		resp.Body = &dpopErrorBody{
+			Reader: io.MultiReader(bytes.NewReader(rawBody), resp.Body),
+			Closer: resp.Body,
+		}
+		if err != nil {
+			return false
+		}
+
+		var errorBody struct {
+			Error string `json:"error"`
+		}
+
+		return json.Unmarshal(rawBody, &errorBody) == nil && errorBody.Error == dpopNonceErrorCode
+	default:
+		return false
+	}
+}
+
+type dpopErrorBody struct {
+	io.Reader
+	io.Closer
+}
+
+func newDPoPHTTPClient(httpClient *http.Client, signer *DPoPSigner, source oauth2.TokenSource) *http.Client {
+	client := &http.Client{}
+	if httpClient != nil {
+		*client = *httpClient
+	}
+
+	client.Transport = &dpopTransport{
+		base:   client.Transport,
+		signer: signer,
+		source: source,
+	}
+
+	return client
+}
+
+func generateDPoPKey(algorithm schema.DPoPAlgorithm) (crypto.Signer, error) {
+	if algorithm == schema.DPoPRS256 {
+		return rsa.GenerateKey(rand.Reader, 2048)
+	}
+
+	return ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+}
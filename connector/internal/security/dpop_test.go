@@ -0,0 +1,145 @@
+package security
+
+import (
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestDPoPSigner(t *testing.T) {
+	signer, err := NewDPoPSigner(&schema.DPoPConfig{})
+	assert.NilError(t, err)
+
+	signer.SetNonce("server-nonce")
+	requestURL, err := url.Parse("https://example.com/pets?status=active#foo")
+	assert.NilError(t, err)
+
+	proof, err := signer.Sign("GET", requestURL, "access-token")
+	assert.NilError(t, err)
+
+	parts := strings.Split(proof, ".")
+	assert.Equal(t, 3, len(parts))
+
+	var header map[string]any
+	rawHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	assert.NilError(t, err)
+	assert.NilError(t, json.Unmarshal(rawHeader, &header))
+	assert.Equal(t, "dpop+jwt", header["typ"])
+	assert.Equal(t, "ES256", header["alg"])
+
+	var claims map[string]any
+	rawClaims, err := base64.RawURLEncoding.DecodeString(parts[1])
+	assert.NilError(t, err)
+	assert.NilError(t, json.Unmarshal(rawClaims, &claims))
+	assert.Equal(t, "GET", claims["htm"])
+	assert.Equal(t, "https://example.com/pets", claims["htu"])
+	assert.Equal(t, "server-nonce", claims["nonce"])
+
+	ath := sha256.Sum256([]byte("access-token"))
+	assert.Equal(t, base64.RawURLEncoding.EncodeToString(ath[:]), claims["ath"])
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.NilError(t, err)
+	assert.Equal(t, 64, len(signature))
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	publicKey := signer.key.Public().(*ecdsa.PublicKey)
+	assert.Assert(t, ecdsa.Verify(publicKey, digest[:], new(big.Int).SetBytes(signature[:32]), new(big.Int).SetBytes(signature[32:])))
+
+	_, err = NewDPoPSigner(&schema.DPoPConfig{Algorithm: schema.DPoPRS256})
+	assert.NilError(t, err)
+}
+
+func TestDPoPTransportNonceRetry(t *testing.T) {
+	getProofNonce := func(r *http.Request) string {
+		parts := strings.Split(r.Header.Get(dpopHeader), ".")
+		if len(parts) != 3 {
+			return ""
+		}
+
+		var claims map[string]any
+		rawClaims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		_ = json.Unmarshal(rawClaims, &claims)
+		nonce, _ := claims["nonce"].(string)
+
+		return nonce
+	}
+
+	var requestCount atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestCount.Add(1)
+		switch r.URL.Path {
+		case "/pets":
+			if getProofNonce(r) != "resource-nonce" {
+				w.Header().Set(dpopNonceHeader, "resource-nonce")
+				w.Header().Set("WWW-Authenticate", `DPoP error="use_dpop_nonce", error_description="Resource server requires nonce in DPoP proof"`)
+				w.WriteHeader(http.StatusUnauthorized)
+
+				return
+			}
+		case "/token":
+			if getProofNonce(r) != "token-nonce" {
+				w.Header().Set(dpopNonceHeader, "token-nonce")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"error":"use_dpop_nonce"}`))
+
+				return
+			}
+
+			body, _ := io.ReadAll(r.Body)
+			if string(body) != "grant_type=client_credentials" {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+
+				return
+			}
+		default:
+			w.Header().Set(dpopNonceHeader, "other-nonce")
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_request"}`))
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	signer, err := NewDPoPSigner(&schema.DPoPConfig{})
+	assert.NilError(t, err)
+	client := newDPoPHTTPClient(server.Client(), signer, nil)
+
+	resp, err := client.Get(server.URL + "/pets")
+	assert.NilError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), requestCount.Load())
+
+	// the request body is sent again
+	resp, err = client.Post(server.URL+"/token", "application/x-www-form-urlencoded", strings.NewReader("grant_type=client_credentials"))
+	assert.NilError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(4), requestCount.Load())
+
+	// other errors aren't retried and the body is kept
+	resp, err = client.Get(server.URL + "/other")
+	assert.NilError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	assert.NilError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, `{"error":"invalid_request"}`, string(body))
+	assert.Equal(t, int32(5), requestCount.Load())
+}
@@ -0,0 +1,145 @@
+package security
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+const defaultHMACTimestampHeader = "X-Timestamp"
+
+// HMACCredential signs requests with HMAC signatures
+type HMACCredential struct {
+	Header          string
+	Prefix          string
+	Template        string
+	TimestampHeader string
+	Encoding        schema.HMACEncoding
+
+	secret  []byte
+	newHash func() hash.Hash
+	client  *http.Client
+	now     func() time.Time
+}
+
+var _ Credential = &HMACCredential{}
+
+// NewHMACCredential creates a new HMACCredential instance.
+func NewHMACCredential(client *http.Client, config *schema.HMACAuthConfig) (*HMACCredential, error) {
+	secret, err := config.Secret.Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HMACCredential: %w", err)
+	}
+
+	cred := &HMACCredential{
+		Header:          config.Header,
+		Prefix:          config.Prefix,
+		Template:        config.Template,
+		TimestampHeader: config.TimestampHeader,
+		Encoding:        config.Encoding,
+		secret:          []byte(secret),
+		newHash:         sha256.New,
+		client:          client,
+		now:             time.Now,
+	}
+
+	if config.Algorithm == schema.HMACSHA512 {
+		cred.newHash = sha512.New
+	}
+
+	if cred.Template == "" {
+		cred.Template = schema.DefaultHMACTemplate
+	}
+
+	if cred.TimestampHeader == "" && strings.Contains(cred.Template, "{timestamp}") {
+		cred.TimestampHeader = defaultHMACTimestampHeader
+	}
+
+	return cred, nil
+}
+
+// GetClient gets the HTTP client that is compatible with the current credential.
+func (hc HMACCredential) GetClient() *http.Client {
+	return hc.client
+}
+
+// Inject the credential into the incoming request
+func (hc HMACCredential) Inject(req *http.Request) (bool, error) {
+	if len(hc.secret) == 0 {
+		return false, nil
+	}
+
+	var body []byte
+	if req.GetBody != nil {
+		reader, err := req.GetBody()
+		if err != nil {
+			return false, fmt.Errorf("failed to read the request body to sign: %w", err)
+		}
+
+		body, err = io.ReadAll(reader)
+		_ = reader.Close()
+		if err != nil {
+			return false, fmt.Errorf("failed to read the request body to sign: %w", err)
+		}
+	}
+
+	timestamp := strconv.FormatInt(hc.now().Unix(), 10)
+	hc.inject(req, timestamp, hc.Sign(req, timestamp, body))
+
+	return true, nil
+}
+
+// InjectMock injects the mock credential into the incoming request for explain APIs.
+func (hc HMACCredential) InjectMock(req *http.Request) bool {
+	if len(hc.secret) == 0 {
+		return false
+	}
+
+	hc.inject(req, strconv.FormatInt(hc.now().Unix(), 10), "xxx")
+
+	return true
+}
+
+// Sign renders the canonical string of the request and returns the encoded signature.
+func (hc HMACCredential) Sign(req *http.Request, timestamp string, body []byte) string {
+	bodyHash := hc.newHash()
+	_, _ = bodyHash.Write(body)
+
+	canonical := strings.NewReplacer(
+		"{method}", strings.ToUpper(req.Method),
+		"{host}", req.URL.Host,
+		"{path}", req.URL.EscapedPath(),
+		"{query}", req.URL.RawQuery,
+		"{timestamp}", timestamp,
+		"{body}", string(body),
+		"{bodyDigest}", hex.EncodeToString(bodyHash.Sum(nil)),
+	).Replace(hc.Template)
+
+	mac := hmac.New(hc.newHash, hc.secret)
+	_, _ = mac.Write([]byte(canonical))
+	signature := mac.Sum(nil)
+
+	if hc.Encoding == schema.HMACEncodingBase64 {
+		return base64.StdEncoding.EncodeToString(signature)
+	}
+
+	return hex.EncodeToString(signature)
+}
+
+func (hc HMACCredential) inject(req *http.Request, timestamp string, signature string) {
+	req.Header.Set(hc.Header, hc.Prefix+signature)
+	if hc.TimestampHeader != "" {
+		req.Header.Set(hc.TimestampHeader, timestamp)
+	}
+}
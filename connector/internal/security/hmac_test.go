@@ -0,0 +1,95 @@
+package security
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestHMACCredential(t *testing.T) {
+	testCases := []struct {
+		Name            string
+		Config          schema.HMACAuthConfig
+		Header          string
+		Expected        string
+		TimestampHeader string
+	}{
+		{
+			Name: "default",
+			Config: schema.HMACAuthConfig{
+				Type:   schema.HMACScheme,
+				Secret: utils.NewEnvStringValue("secret"),
+				Header: "X-Signature",
+			},
+			Header:          "X-Signature",
+			Expected:        "ecc4568007a446466d505d3b3ce93152aca317e39da761a7241138b4ed4f9ad5",
+			TimestampHeader: "X-Timestamp",
+		},
+		{
+			Name: "sha512_base64",
+			Config: schema.HMACAuthConfig{
+				Type:            schema.HMACScheme,
+				Algorithm:       schema.HMACSHA512,
+				Secret:          utils.NewEnvStringValue("secret"),
+				Header:          "Webhook-Signature",
+				Prefix:          "v1=",
+				Template:        "{timestamp}.{body}",
+				TimestampHeader: "Webhook-Timestamp",
+				Encoding:        schema.HMACEncodingBase64,
+			},
+			Header:          "Webhook-Signature",
+			Expected:        "v1=E441dtB4HcSTBert1woUpfqWiqp9ASSeO14LLWlOip4HclKCN4vcURJ1I2Ldcgeddjrq4vNinfZ4rTQIpCpYpA==",
+			TimestampHeader: "Webhook-Timestamp",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			cred, err := NewHMACCredential(http.DefaultClient, &tc.Config)
+			assert.NilError(t, err)
+			cred.now = func() time.Time {
+				return time.Unix(1700000000, 0)
+			}
+
+			req, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, "https://api.example.com/v1/pets?limit=10", bytes.NewBufferString(`{"name":"Dog"}`))
+			assert.NilError(t, err)
+
+			ok, err := cred.Inject(req)
+			assert.NilError(t, err)
+			assert.Assert(t, ok)
+			assert.Equal(t, tc.Expected, req.Header.Get(tc.Header))
+			assert.Equal(t, "1700000000", req.Header.Get(tc.TimestampHeader))
+
+			// the body can still be sent after signing
+			body, err := io.ReadAll(req.Body)
+			assert.NilError(t, err)
+			assert.Equal(t, `{"name":"Dog"}`, string(body))
+
+			mockReq, err := http.NewRequestWithContext(context.TODO(), http.MethodPost, "https://api.example.com/v1/pets", nil)
+			assert.NilError(t, err)
+			assert.Assert(t, cred.InjectMock(mockReq))
+			assert.Assert(t, mockReq.Header.Get(tc.Header) != "")
+		})
+	}
+
+	t.Run("empty_secret", func(t *testing.T) {
+		t.Setenv("HMAC_SECRET", "")
+		cred, err := NewHMACCredential(http.DefaultClient, schema.NewHMACAuthConfig("X-Signature", utils.NewEnvStringVariable("HMAC_SECRET")))
+		assert.NilError(t, err)
+
+		req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, "https://api.example.com/v1/pets", nil)
+		assert.NilError(t, err)
+
+		ok, err := cred.Inject(req)
+		assert.NilError(t, err)
+		assert.Assert(t, !ok)
+		assert.Equal(t, "", req.Header.Get("X-Signature"))
+	})
+}
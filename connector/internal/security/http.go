@@ -14,10 +14,11 @@ type HTTPCredential struct {
 	Scheme string
 	Value  string
 
-	client *http.Client
+	client   *http.Client
+	rotation *credentialRotation
 }
 
-var _ Credential = &HTTPCredential{}
+var _ RotatableCredential = &HTTPCredential{}
 
 // NewHTTPCredential creates a new HTTPCredential instance.
 func NewHTTPCredential(client *http.Client, config *schema.HTTPAuthConfig) (*HTTPCredential, error) {
@@ -26,12 +27,24 @@ func NewHTTPCredential(client *http.Client, config *schema.HTTPAuthConfig) (*HTT
 		return nil, fmt.Errorf("failed to create ApiKeyCredential: %w", err)
 	}
 
-	return &HTTPCredential{
+	result := &HTTPCredential{
 		Header: config.Header,
 		Scheme: config.Scheme,
 		Value:  value,
 		client: client,
-	}, nil
+	}
+
+	// the secondary value is optional. The credential works with the primary value only if the variable isn't set
+	if config.SecondaryValue != nil {
+		if secondary, err := config.SecondaryValue.Get(); err == nil && secondary != "" && secondary != value {
+			result.rotation = &credentialRotation{secondary: secondary}
+			result.client = newRotationClient(client, result.rotation, func(req *http.Request) {
+				result.inject(req, secondary)
+			})
+		}
+	}
+
+	return result, nil
 }
 
 // GetClient gets the HTTP client that is compatible with the current credential.
@@ -39,6 +52,13 @@ func (hc HTTPCredential) GetClient() *http.Client {
 	return hc.client
 }
 
+// SetRotationHandler sets the handler which is called when the request is retried with the secondary token.
+func (hc *HTTPCredential) SetRotationHandler(handler RotationHandler) {
+	if hc.rotation != nil {
+		hc.rotation.handler = handler
+	}
+}
+
 // Inject the credential into the incoming request
 func (hc HTTPCredential) Inject(req *http.Request) (bool, error) {
 	if hc.Value == "" {
@@ -73,32 +93,3 @@ func (hc HTTPCredential) inject(req *http.Request, value string) {
 
 	req.Header.Set(headerName, scheme+" "+value)
 }
-
-// CookieCredential presents a cookie credential
-type CookieCredential struct {
-	client *http.Client
-}
-
-var _ Credential = &CookieCredential{}
-
-// NewCookieCredential creates a new CookieCredential instance.
-func NewCookieCredential(client *http.Client) (*CookieCredential, error) {
-	return &CookieCredential{
-		client: client,
-	}, nil
-}
-
-// GetClient gets the HTTP client that is compatible with the current credential.
-func (cc CookieCredential) GetClient() *http.Client {
-	return cc.client
-}
-
-// Inject the credential into the incoming request
-func (cc CookieCredential) Inject(req *http.Request) (bool, error) {
-	return false, nil
-}
-
-// InjectMock injects the mock credential into the incoming request for explain APIs.
-func (cc CookieCredential) InjectMock(req *http.Request) bool {
-	return false
-}
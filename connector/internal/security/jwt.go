@@ -0,0 +1,278 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// the token is renewed before it expires to avoid being rejected due to clock skew
+const jwtExpiryLeeway = 30 * time.Second
+
+// JWTCredential mints JWTs from a private key and sends them as bearer tokens.
+// The token is cached and renewed shortly before it expires.
+type JWTCredential struct {
+	Header string
+	Scheme string
+
+	algorithm schema.JWTAlgorithm
+	key       crypto.Signer
+	keyID     string
+	claims    map[string]any
+	ttl       time.Duration
+	client    *http.Client
+	now       func() time.Time
+
+	token     string
+	expiresAt time.Time
+	lock      sync.Mutex
+}
+
+var _ Credential = &JWTCredential{}
+
+// NewJWTCredential creates a new JWTCredential instance.
+func NewJWTCredential(client *http.Client, config *schema.JWTAuthConfig) (*JWTCredential, error) {
+	keyPem, err := config.PrivateKey.Get()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWTCredential: %w", err)
+	}
+
+	cred := &JWTCredential{
+		Header:    config.Header,
+		Scheme:    config.Scheme,
+		algorithm: config.Algorithm,
+		claims:    map[string]any{},
+		ttl:       time.Duration(config.TTL) * time.Second,
+		client:    client,
+		now:       time.Now,
+	}
+
+	if cred.Header == "" {
+		cred.Header = schema.AuthorizationHeader
+	}
+
+	if cred.Scheme == "" && cred.Header == schema.AuthorizationHeader {
+		cred.Scheme = "Bearer"
+	}
+
+	if cred.algorithm == "" {
+		cred.algorithm = schema.JWTRS256
+	}
+
+	if cred.ttl <= 0 {
+		cred.ttl = schema.DefaultJWTTTL * time.Second
+	}
+
+	maps.Copy(cred.claims, config.Claims)
+
+	for name, value := range map[string]*utils.EnvString{
+		"iss": config.Issuer,
+		"sub": config.Subject,
+		"aud": config.Audience,
+	} {
+		if value == nil {
+			continue
+		}
+
+		claim, err := value.GetOrDefault("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create JWTCredential: %s: %w", name, err)
+		}
+
+		if claim != "" {
+			cred.claims[name] = claim
+		}
+	}
+
+	if config.KeyID != nil {
+		cred.keyID, err = config.KeyID.GetOrDefault("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create JWTCredential: keyId: %w", err)
+		}
+	}
+
+	// the credential is inactive if the private key is empty
+	if keyPem == "" {
+		return cred, nil
+	}
+
+	cred.key, err = parsePrivateKey([]byte(keyPem))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWTCredential: %w", err)
+	}
+
+	if err := validateSigningKey(cred.key, cred.algorithm); err != nil {
+		return nil, fmt.Errorf("failed to create JWTCredential: %w", err)
+	}
+
+	return cred, nil
+}
+
+// GetClient gets the HTTP client that is compatible with the current credential.
+func (jc *JWTCredential) GetClient() *http.Client {
+	return jc.client
+}
+
+// Inject the credential into the incoming request
+func (jc *JWTCredential) Inject(req *http.Request) (bool, error) {
+	if jc.key == nil {
+		return false, nil
+	}
+
+	token, err := jc.Token()
+	if err != nil {
+		return false, err
+	}
+
+	jc.inject(req, token)
+
+	return true, nil
+}
+
+// InjectMock injects the mock credential into the incoming request for explain APIs.
+func (jc *JWTCredential) InjectMock(req *http.Request) bool {
+	if jc.key == nil {
+		return false
+	}
+
+	jc.inject(req, "xxx")
+
+	return true
+}
+
+// Token returns the cached token or mints a new one if the cached token is about to expire.
+func (jc *JWTCredential) Token() (string, error) {
+	jc.lock.Lock()
+	defer jc.lock.Unlock()
+
+	now := jc.now()
+	if jc.token != "" && now.Add(min(jwtExpiryLeeway, jc.ttl/2)).Before(jc.expiresAt) {
+		return jc.token, nil
+	}
+
+	header := map[string]any{
+		"typ": "JWT",
+		"alg": jc.algorithm,
+	}
+
+	if jc.keyID != "" {
+		header["kid"] = jc.keyID
+	}
+
+	expiresAt := now.Add(jc.ttl)
+	claims := maps.Clone(jc.claims)
+	claims["jti"] = uuid.NewString()
+	claims["iat"] = now.Unix()
+	claims["exp"] = expiresAt.Unix()
+
+	token, err := signJWT(jc.key, header, claims)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign the JWT: %w", err)
+	}
+
+	jc.token = token
+	jc.expiresAt = expiresAt
+
+	return token, nil
+}
+
+func (jc *JWTCredential) inject(req *http.Request, token string) {
+	if jc.Scheme != "" {
+		token = jc.Scheme + " " + token
+	}
+
+	req.Header.Set(jc.Header, token)
+}
+
+// signJWT encodes and signs the JWT with the private key. Only ES256 and RS256 are supported.
+func signJWT(key crypto.Signer, header map[string]any, claims map[string]any) (string, error) {
+	rawHeader, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+
+	rawClaims, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64.RawURLEncoding.EncodeToString(rawHeader) + "." + base64.RawURLEncoding.EncodeToString(rawClaims)
+	digest := sha256.Sum256([]byte(signingInput))
+
+	var signature []byte
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		r, s, err := ecdsa.Sign(rand.Reader, k, digest[:])
+		if err != nil {
+			return "", err
+		}
+
+		// JWS requires the fixed-size R || S format instead of ASN.1
+		signature = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	default:
+		signature, err = key.Sign(rand.Reader, digest[:], crypto.SHA256)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// validateSigningKey checks if the private key is compatible with the JWT algorithm.
+func validateSigningKey(key crypto.Signer, algorithm schema.JWTAlgorithm) error {
+	switch k := key.(type) {
+	case *ecdsa.PrivateKey:
+		if algorithm == schema.JWTES256 && k.Curve == elliptic.P256() {
+			return nil
+		}
+	case *rsa.PrivateKey:
+		if algorithm == schema.JWTRS256 {
+			return nil
+		}
+	default:
+		return errors.New("unsupported private key type, expected ECDSA or RSA")
+	}
+
+	return fmt.Errorf("the private key isn't compatible with the algorithm %s", algorithm)
+}
+
+// parsePrivateKey parses the PEM-encoded private key in PKCS #8, SEC 1 or PKCS #1 formats.
+func parsePrivateKey(keyPem []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPem)
+	if block == nil {
+		return nil, errors.New("failed to decode the PEM private key")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		signer, ok := key.(crypto.Signer)
+		if !ok {
+			return nil, errors.New("unsupported private key type, expected ECDSA or RSA")
+		}
+
+		return signer, nil
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return x509.ParsePKCS1PrivateKey(block.Bytes)
+}
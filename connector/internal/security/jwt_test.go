@@ -0,0 +1,167 @@
+package security
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestJWTCredential(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	assert.NilError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		Name      string
+		Algorithm schema.JWTAlgorithm
+		Key       crypto.Signer
+	}{
+		{
+			Name: "RS256",
+			Key:  rsaKey,
+		},
+		{
+			Name:      "ES256",
+			Algorithm: schema.JWTES256,
+			Key:       ecKey,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			t.Setenv("JWT_PRIVATE_KEY", encodeTestPrivateKey(t, tc.Key))
+			config := schema.NewJWTAuthConfig(utils.NewEnvStringVariable("JWT_PRIVATE_KEY"))
+			config.Algorithm = tc.Algorithm
+			config.KeyID = utils.ToPtr(utils.NewEnvStringValue("key-1"))
+			config.Issuer = utils.ToPtr(utils.NewEnvStringValue("connector@example.com"))
+			config.Audience = utils.ToPtr(utils.NewEnvStringValue("https://api.example.com/"))
+			config.TTL = 600
+			config.Claims = map[string]any{
+				"scope": "read write",
+			}
+			assert.NilError(t, config.Validate())
+
+			cred, err := NewJWTCredential(http.DefaultClient, config)
+			assert.NilError(t, err)
+
+			now := time.Unix(1700000000, 0)
+			cred.now = func() time.Time {
+				return now
+			}
+
+			req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, "https://api.example.com/v1/pets", nil)
+			assert.NilError(t, err)
+
+			ok, err := cred.Inject(req)
+			assert.NilError(t, err)
+			assert.Assert(t, ok)
+
+			token, found := strings.CutPrefix(req.Header.Get(schema.AuthorizationHeader), "Bearer ")
+			assert.Assert(t, found)
+
+			header, claims := verifyTestJWT(t, token, tc.Key.Public())
+			assert.Equal(t, "key-1", header["kid"])
+			assert.Equal(t, "connector@example.com", claims["iss"])
+			assert.Equal(t, "https://api.example.com/", claims["aud"])
+			assert.Equal(t, "read write", claims["scope"])
+			assert.Equal(t, float64(1700000000), claims["iat"])
+			assert.Equal(t, float64(1700000600), claims["exp"])
+			assert.Assert(t, claims["sub"] == nil)
+
+			// the token is reused until it's about to expire
+			now = now.Add(9 * time.Minute)
+			cachedToken, err := cred.Token()
+			assert.NilError(t, err)
+			assert.Equal(t, token, cachedToken)
+
+			now = now.Add(40 * time.Second)
+			newToken, err := cred.Token()
+			assert.NilError(t, err)
+			assert.Assert(t, token != newToken)
+
+			mockReq, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, "https://api.example.com/v1/pets", nil)
+			assert.NilError(t, err)
+			assert.Assert(t, cred.InjectMock(mockReq))
+			assert.Equal(t, "Bearer xxx", mockReq.Header.Get(schema.AuthorizationHeader))
+		})
+	}
+
+	t.Run("incompatible_key", func(t *testing.T) {
+		config := schema.NewJWTAuthConfig(utils.NewEnvStringValue(encodeTestPrivateKey(t, ecKey)))
+		_, err := NewJWTCredential(http.DefaultClient, config)
+		assert.ErrorContains(t, err, "the private key isn't compatible with the algorithm RS256")
+	})
+
+	t.Run("empty_key", func(t *testing.T) {
+		t.Setenv("JWT_PRIVATE_KEY", "")
+		cred, err := NewJWTCredential(http.DefaultClient, schema.NewJWTAuthConfig(utils.NewEnvStringVariable("JWT_PRIVATE_KEY")))
+		assert.NilError(t, err)
+
+		req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, "https://api.example.com/v1/pets", nil)
+		assert.NilError(t, err)
+
+		ok, err := cred.Inject(req)
+		assert.NilError(t, err)
+		assert.Assert(t, !ok)
+		assert.Equal(t, "", req.Header.Get(schema.AuthorizationHeader))
+	})
+}
+
+func encodeTestPrivateKey(t *testing.T, key crypto.Signer) string {
+	t.Helper()
+
+	raw, err := x509.MarshalPKCS8PrivateKey(key)
+	assert.NilError(t, err)
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: raw}))
+}
+
+func verifyTestJWT(t *testing.T, token string, publicKey crypto.PublicKey) (map[string]any, map[string]any) {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	assert.Equal(t, 3, len(parts))
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	assert.NilError(t, err)
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+
+	switch pub := publicKey.(type) {
+	case *rsa.PublicKey:
+		assert.NilError(t, rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature))
+	case *ecdsa.PublicKey:
+		assert.Equal(t, 64, len(signature))
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		assert.Assert(t, ecdsa.Verify(pub, digest[:], r, s))
+	default:
+		t.Fatalf("unsupported public key %T", publicKey)
+	}
+
+	var header, claims map[string]any
+	for i, target := range []*map[string]any{&header, &claims} {
+		raw, err := base64.RawURLEncoding.DecodeString(parts[i])
+		assert.NilError(t, err)
+		assert.NilError(t, json.Unmarshal(raw, target))
+	}
+
+	return header, claims
+}
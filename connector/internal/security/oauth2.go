@@ -5,23 +5,33 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/connector"
+	"github.com/hasura/ndc-sdk-go/utils"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 )
 
-// OAuth2Client represent the client of the OAuth2 client credentials
+// OAuth2Client represent the client of OAuth2 flows
 type OAuth2Client struct {
 	client  *http.Client
 	isEmpty bool
+	dpop    bool
 }
 
 var _ Credential = &OAuth2Client{}
 
-// NewOAuth2Client creates an OAuth2 client from the security scheme
-func NewOAuth2Client(ctx context.Context, httpClient *http.Client, flowType schema.OAuthFlowType, config *schema.OAuthFlow) (*OAuth2Client, error) {
-	if flowType != schema.ClientCredentialsFlow || config.TokenURL == nil || config.ClientID == nil || config.ClientSecret == nil {
+// NewOAuth2Client creates an OAuth2 client from the security scheme.
+// The client credentials, password and authorization code flows request access tokens with the configured credentials.
+// The authorization code flow requires a refresh token because the connector can't interact with users.
+// The client is empty if credentials aren't configured, so the access token should be forwarded from request headers.
+// Tokens are shared between connector replicas if the token store isn't nil.
+func NewOAuth2Client(ctx context.Context, httpClient *http.Client, flowType schema.OAuthFlowType, config *schema.OAuthFlow, tokenStore TokenStore) (*OAuth2Client, error) {
+	if !hasOAuth2Credentials(flowType, config) {
 		return &OAuth2Client{
 			client:  httpClient,
 			isEmpty: true,
@@ -47,38 +57,187 @@ func NewOAuth2Client(ctx context.Context, httpClient *http.Client, flowType sche
 		return nil, fmt.Errorf("clientId: %w", err)
 	}
 
-	clientSecret, err := config.ClientSecret.Get()
+	var clientSecret string
+	if flowType == schema.ClientCredentialsFlow {
+		clientSecret, err = config.ClientSecret.Get()
+	} else if config.ClientSecret != nil {
+		// public clients of the password and authorization code flows don't have secrets
+		clientSecret, err = config.ClientSecret.GetOrDefault("")
+	}
+
 	if err != nil {
 		return nil, fmt.Errorf("clientSecret: %w", err)
 	}
 
-	var endpointParams url.Values
-	for key, envValue := range config.EndpointParams {
-		value, err := envValue.GetOrDefault("")
+	if config.DPoP != nil {
+		signer, err := NewDPoPSigner(config.DPoP)
 		if err != nil {
-			return nil, fmt.Errorf("endpointParams[%s]: %w", key, err)
+			return nil, fmt.Errorf("dpop: %w", err)
 		}
-		if value != "" {
-			endpointParams.Set(key, value)
+
+		// the token request must also be signed to get a DPoP-bound access token.
+		// DPoP-bound tokens aren't shared because replicas may use different proof keys.
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, newDPoPHTTPClient(httpClient, signer, nil))
+		source, _, err := newOAuth2TokenSource(ctx, flowType, config, clientID, clientSecret, tokenURL, scopes)
+		if err != nil {
+			return nil, err
 		}
+
+		return &OAuth2Client{
+			client: newDPoPHTTPClient(httpClient, signer, source),
+			dpop:   true,
+		}, nil
 	}
 
 	ctx = context.WithValue(ctx, oauth2.HTTPClient, httpClient)
-	conf := &clientcredentials.Config{
-		ClientID:       clientID,
-		ClientSecret:   clientSecret,
-		Scopes:         scopes,
-		TokenURL:       tokenURL,
-		EndpointParams: endpointParams,
+	source, keyParts, err := newOAuth2TokenSource(ctx, flowType, config, clientID, clientSecret, tokenURL, scopes)
+	if err != nil {
+		return nil, err
 	}
 
-	client := conf.Client(ctx)
+	client := oauth2.NewClient(ctx, newSharedTokenSource(ctx, tokenStore, source, keyParts...))
 
 	return &OAuth2Client{
 		client: client,
 	}, nil
 }
 
+// newOAuth2TokenSource creates the token source of the flow. Returns the token source and identities of the credentials.
+func newOAuth2TokenSource(ctx context.Context, flowType schema.OAuthFlowType, config *schema.OAuthFlow, clientID string, clientSecret string, tokenURL string, scopes []string) (oauth2.TokenSource, []string, error) {
+	sortedScopes := slices.Clone(scopes)
+	slices.Sort(sortedScopes)
+	keyParts := []string{string(flowType), tokenURL, clientID, strings.Join(sortedScopes, " ")}
+	if flowType == schema.ClientCredentialsFlow {
+		var endpointParams url.Values
+		for key, envValue := range config.EndpointParams {
+			value, err := envValue.GetOrDefault("")
+			if err != nil {
+				return nil, nil, fmt.Errorf("endpointParams[%s]: %w", key, err)
+			}
+			if value != "" {
+				if endpointParams == nil {
+					endpointParams = url.Values{}
+				}
+				endpointParams.Set(key, value)
+			}
+		}
+
+		conf := &clientcredentials.Config{
+			ClientID:       clientID,
+			ClientSecret:   clientSecret,
+			Scopes:         scopes,
+			TokenURL:       tokenURL,
+			EndpointParams: endpointParams,
+		}
+
+		return conf.TokenSource(ctx), keyParts, nil
+	}
+
+	conf := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Scopes:       scopes,
+		Endpoint: oauth2.Endpoint{
+			TokenURL: tokenURL,
+		},
+	}
+
+	if flowType == schema.PasswordFlow {
+		username, err := config.Username.Get()
+		if err != nil {
+			return nil, nil, fmt.Errorf("username: %w", err)
+		}
+
+		password, err := config.Password.Get()
+		if err != nil {
+			return nil, nil, fmt.Errorf("password: %w", err)
+		}
+
+		return &passwordTokenSource{
+			ctx:      ctx,
+			conf:     conf,
+			username: username,
+			password: password,
+		}, append(keyParts, username), nil
+	}
+
+	refreshToken, err := config.RefreshToken.Get()
+	if err != nil {
+		return nil, nil, fmt.Errorf("refreshToken: %w", err)
+	}
+
+	// the refresh token is rotated in memory if the authorization server issues a new one
+	return conf.TokenSource(ctx, &oauth2.Token{RefreshToken: refreshToken}), keyParts, nil
+}
+
+// hasOAuth2Credentials checks if the flow has credentials to request access tokens.
+// Unset environment variables are treated as empty values.
+func hasOAuth2Credentials(flowType schema.OAuthFlowType, config *schema.OAuthFlow) bool {
+	if config.TokenURL == nil || config.ClientID == nil {
+		return false
+	}
+
+	switch flowType {
+	case schema.ClientCredentialsFlow:
+		return config.ClientSecret != nil
+	case schema.PasswordFlow:
+		return isEnvStringSet(config.ClientID) && isEnvStringSet(config.Username) && isEnvStringSet(config.Password)
+	case schema.AuthorizationCodeFlow:
+		return isEnvStringSet(config.ClientID) && isEnvStringSet(config.RefreshToken)
+	default:
+		return false
+	}
+}
+
+func isEnvStringSet(value *utils.EnvString) bool {
+	if value == nil {
+		return false
+	}
+
+	result, err := value.GetOrDefault("")
+
+	return err == nil && result != ""
+}
+
+// passwordTokenSource requests access tokens with the resource owner password credentials.
+// Tokens are refreshed with the refresh token if exists, otherwise the password grant is requested again.
+type passwordTokenSource struct {
+	ctx      context.Context
+	conf     *oauth2.Config
+	username string
+	password string
+
+	lock         sync.Mutex
+	refreshToken string
+}
+
+// Token returns a new access token.
+func (pts *passwordTokenSource) Token() (*oauth2.Token, error) {
+	pts.lock.Lock()
+	defer pts.lock.Unlock()
+
+	if pts.refreshToken != "" {
+		token, err := pts.conf.TokenSource(pts.ctx, &oauth2.Token{RefreshToken: pts.refreshToken}).Token()
+		if err == nil {
+			pts.refreshToken = token.RefreshToken
+
+			return token, nil
+		}
+
+		// fall back to the password grant if the refresh token is expired or revoked
+		connector.GetLogger(pts.ctx).Debug("failed to refresh the OAuth2 token: " + err.Error())
+	}
+
+	token, err := pts.conf.PasswordCredentialsToken(pts.ctx, pts.username, pts.password)
+	if err != nil {
+		return nil, err
+	}
+
+	pts.refreshToken = token.RefreshToken
+
+	return token, nil
+}
+
 // GetClient gets the HTTP client that is compatible with the current credential.
 func (oc OAuth2Client) GetClient() *http.Client {
 	return oc.client
@@ -95,7 +254,12 @@ func (oc OAuth2Client) InjectMock(req *http.Request) bool {
 		return false
 	}
 
-	req.Header.Set(schema.AuthorizationHeader, "Bearer xxx")
+	if oc.dpop {
+		req.Header.Set(schema.AuthorizationHeader, "DPoP xxx")
+		req.Header.Set(dpopHeader, "xxx")
+	} else {
+		req.Header.Set(schema.AuthorizationHeader, "Bearer xxx")
+	}
 
 	return true
 }
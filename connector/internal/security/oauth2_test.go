@@ -0,0 +1,204 @@
+package security
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"golang.org/x/oauth2"
+	"gotest.tools/v3/assert"
+)
+
+type mockOAuth2Server struct {
+	lock     sync.Mutex
+	requests []map[string]string
+	server   *httptest.Server
+}
+
+func newMockOAuth2Server(t *testing.T) *mockOAuth2Server {
+	t.Helper()
+
+	ms := &mockOAuth2Server{}
+	ms.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NilError(t, r.ParseForm())
+
+		ms.lock.Lock()
+		count := len(ms.requests) + 1
+		ms.requests = append(ms.requests, map[string]string{
+			"grant_type":    r.PostForm.Get("grant_type"),
+			"username":      r.PostForm.Get("username"),
+			"password":      r.PostForm.Get("password"),
+			"refresh_token": r.PostForm.Get("refresh_token"),
+		})
+		ms.lock.Unlock()
+
+		if r.PostForm.Get("refresh_token") == "revoked" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error": "invalid_grant"}`))
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		// the token expires immediately so every request refreshes it
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  fmt.Sprintf("access-%d", count),
+			"refresh_token": fmt.Sprintf("refresh-%d", count),
+			"token_type":    "Bearer",
+			"expires_in":    1,
+		})
+	}))
+	t.Cleanup(ms.server.Close)
+
+	return ms
+}
+
+func (ms *mockOAuth2Server) Requests() []map[string]string {
+	ms.lock.Lock()
+	defer ms.lock.Unlock()
+
+	return ms.requests
+}
+
+func newMockResourceServer(t *testing.T) (*httptest.Server, *[]string) {
+	t.Helper()
+
+	var lock sync.Mutex
+	var authorizations []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lock.Lock()
+		authorizations = append(authorizations, r.Header.Get(schema.AuthorizationHeader))
+		lock.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &authorizations
+}
+
+func sendOAuth2Requests(t *testing.T, client *http.Client, endpoint string, count int) {
+	t.Helper()
+
+	for range count {
+		resp, err := client.Get(endpoint)
+		assert.NilError(t, err)
+		assert.NilError(t, resp.Body.Close())
+	}
+}
+
+func TestOAuth2PasswordFlow(t *testing.T) {
+	tokenServer := newMockOAuth2Server(t)
+	resourceServer, authorizations := newMockResourceServer(t)
+
+	config := &schema.OAuthFlow{
+		TokenURL: utils.ToPtr(utils.NewEnvStringValue(tokenServer.server.URL)),
+		ClientID: utils.ToPtr(utils.NewEnvStringValue("client")),
+		Username: utils.ToPtr(utils.NewEnvStringValue("user")),
+		Password: utils.ToPtr(utils.NewEnvStringValue("secret")),
+	}
+
+	cred, err := NewOAuth2Client(context.TODO(), http.DefaultClient, schema.PasswordFlow, config, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, !cred.isEmpty)
+
+	sendOAuth2Requests(t, cred.GetClient(), resourceServer.URL, 2)
+	assert.DeepEqual(t, []string{"Bearer access-1", "Bearer access-2"}, *authorizations)
+
+	requests := tokenServer.Requests()
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "password", requests[0]["grant_type"])
+	assert.Equal(t, "user", requests[0]["username"])
+	assert.Equal(t, "secret", requests[0]["password"])
+	assert.Equal(t, "refresh_token", requests[1]["grant_type"])
+	assert.Equal(t, "refresh-1", requests[1]["refresh_token"])
+}
+
+func TestOAuth2AuthorizationCodeFlow(t *testing.T) {
+	tokenServer := newMockOAuth2Server(t)
+	resourceServer, authorizations := newMockResourceServer(t)
+
+	config := &schema.OAuthFlow{
+		TokenURL:     utils.ToPtr(utils.NewEnvStringValue(tokenServer.server.URL)),
+		ClientID:     utils.ToPtr(utils.NewEnvStringValue("client")),
+		RefreshToken: utils.ToPtr(utils.NewEnvStringValue("initial")),
+	}
+
+	cred, err := NewOAuth2Client(context.TODO(), http.DefaultClient, schema.AuthorizationCodeFlow, config, NewMemoryTokenStore())
+	assert.NilError(t, err)
+
+	sendOAuth2Requests(t, cred.GetClient(), resourceServer.URL, 2)
+	assert.DeepEqual(t, []string{"Bearer access-1", "Bearer access-2"}, *authorizations)
+
+	requests := tokenServer.Requests()
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "initial", requests[0]["refresh_token"])
+	// the rotated refresh token is used in the next request
+	assert.Equal(t, "refresh-1", requests[1]["refresh_token"])
+}
+
+func TestOAuth2EmptyClient(t *testing.T) {
+	t.Setenv("OAUTH2_USERNAME", "")
+
+	flows := map[schema.OAuthFlowType]schema.OAuthFlow{
+		schema.ImplicitFlow: {},
+		schema.PasswordFlow: {
+			TokenURL: utils.ToPtr(utils.NewEnvStringValue("http://localhost/token")),
+			ClientID: utils.ToPtr(utils.NewEnvStringValue("client")),
+			Username: utils.ToPtr(utils.NewEnvStringVariable("OAUTH2_USERNAME")),
+			Password: utils.ToPtr(utils.NewEnvStringVariable("OAUTH2_PASSWORD")),
+		},
+	}
+
+	flowType, flow, ok := selectOAuthFlow(flows)
+	assert.Assert(t, ok)
+	assert.Equal(t, schema.PasswordFlow, flowType)
+
+	cred, err := NewOAuth2Client(context.TODO(), http.DefaultClient, flowType, &flow, nil)
+	assert.NilError(t, err)
+	assert.Assert(t, cred.isEmpty)
+
+	t.Setenv("OAUTH2_USERNAME", "user")
+	t.Setenv("OAUTH2_PASSWORD", "secret")
+	flows[schema.AuthorizationCodeFlow] = schema.OAuthFlow{}
+
+	flowType, _, ok = selectOAuthFlow(flows)
+	assert.Assert(t, ok)
+	assert.Equal(t, schema.PasswordFlow, flowType)
+}
+
+func TestOAuth2PasswordFallback(t *testing.T) {
+	tokenServer := newMockOAuth2Server(t)
+	source := &passwordTokenSource{
+		ctx:          context.TODO(),
+		conf:         newTestOAuth2Config(tokenServer.server.URL),
+		username:     "user",
+		password:     "secret",
+		refreshToken: "revoked",
+	}
+
+	token, err := source.Token()
+	assert.NilError(t, err)
+	assert.Equal(t, "access-2", token.AccessToken)
+	assert.Equal(t, "refresh-2", source.refreshToken)
+
+	requests := tokenServer.Requests()
+	assert.Equal(t, 2, len(requests))
+	assert.Equal(t, "refresh_token", requests[0]["grant_type"])
+	assert.Equal(t, "password", requests[1]["grant_type"])
+}
+
+func newTestOAuth2Config(tokenURL string) *oauth2.Config {
+	return &oauth2.Config{
+		ClientID: "client",
+		Endpoint: oauth2.Endpoint{
+			TokenURL:  tokenURL,
+			AuthStyle: oauth2.AuthStyleInParams,
+		},
+	}
+}
@@ -0,0 +1,92 @@
+package security
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// RotationHandler is called after the upstream rejects the primary credential and the request is retried with the secondary credential.
+// The accepted flag is true if the secondary credential isn't rejected.
+type RotationHandler func(ctx context.Context, accepted bool)
+
+// RotatableCredential abstracts a credential with primary and secondary values for zero-downtime key rotation.
+type RotatableCredential interface {
+	Credential
+
+	// SetRotationHandler sets the handler which is called when the request is retried with the secondary credential.
+	SetRotationHandler(handler RotationHandler)
+}
+
+// credentialRotation holds the secondary credential value and the rotation handler.
+type credentialRotation struct {
+	secondary string
+	handler   RotationHandler
+}
+
+// notify calls the rotation handler if it is set.
+func (cr *credentialRotation) notify(ctx context.Context, accepted bool) {
+	if cr.handler != nil {
+		cr.handler(ctx, accepted)
+	}
+}
+
+// newRotationClient creates a copy of the HTTP client which retries requests with the secondary credential.
+func newRotationClient(httpClient *http.Client, rotation *credentialRotation, injectSecondary func(req *http.Request)) *http.Client {
+	client := &http.Client{}
+	if httpClient != nil {
+		*client = *httpClient
+	}
+
+	client.Transport = &rotationTransport{
+		base:            client.Transport,
+		rotation:        rotation,
+		injectSecondary: injectSecondary,
+	}
+
+	return client
+}
+
+// rotationTransport retries the request once with the secondary credential
+// if the upstream rejects the primary credential with 401.
+type rotationTransport struct {
+	base            http.RoundTripper
+	rotation        *credentialRotation
+	injectSecondary func(req *http.Request)
+}
+
+// RoundTrip implements http.RoundTripper.
+func (rt *rotationTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := rt.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+
+	// the body can't be sent again
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	retryReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		retryReq.Body, err = req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	rt.injectSecondary(retryReq)
+
+	resp, err = base.RoundTrip(retryReq)
+	rt.rotation.notify(req.Context(), err == nil && resp.StatusCode != http.StatusUnauthorized)
+
+	return resp, err
+}
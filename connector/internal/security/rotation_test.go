@@ -0,0 +1,121 @@
+package security
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestCredentialRotation(t *testing.T) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get("X-Api-Key")
+		if key == "" {
+			key = r.URL.Query().Get("api_key")
+		}
+		if key == "" {
+			key = strings.TrimPrefix(r.Header.Get(schema.AuthorizationHeader), "Bearer ")
+		}
+
+		body := make([]byte, r.ContentLength)
+		_, _ = r.Body.Read(body)
+		requests = append(requests, key+":"+r.URL.RawQuery+":"+string(body))
+
+		if key != "new-key" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	send := func(t *testing.T, cred Credential, method string, body string) *http.Response {
+		t.Helper()
+
+		req, err := http.NewRequestWithContext(context.TODO(), method, server.URL+"/pets?limit=10", strings.NewReader(body))
+		assert.NilError(t, err)
+
+		ok, err := cred.Inject(req)
+		assert.NilError(t, err)
+		assert.Assert(t, ok)
+
+		resp, err := cred.GetClient().Do(req)
+		assert.NilError(t, err)
+		_ = resp.Body.Close()
+
+		return resp
+	}
+
+	t.Run("api_key_header", func(t *testing.T) {
+		requests = nil
+		config := schema.NewAPIKeyAuthConfig("X-Api-Key", schema.APIKeyInHeader, utils.NewEnvStringValue("old-key"))
+		config.SecondaryValue = utils.ToPtr(utils.NewEnvStringValue("new-key"))
+		cred, err := NewApiKeyCredential(http.DefaultClient, config)
+		assert.NilError(t, err)
+
+		var rotations []bool
+		cred.SetRotationHandler(func(ctx context.Context, accepted bool) {
+			rotations = append(rotations, accepted)
+		})
+
+		resp := send(t, cred, http.MethodPost, `{"name":"doggie"}`)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.DeepEqual(t, []string{`old-key:limit=10:{"name":"doggie"}`, `new-key:limit=10:{"name":"doggie"}`}, requests)
+		assert.DeepEqual(t, []bool{true}, rotations)
+	})
+
+	t.Run("api_key_query", func(t *testing.T) {
+		requests = nil
+		config := schema.NewAPIKeyAuthConfig("api_key", schema.APIKeyInQuery, utils.NewEnvStringValue("old-key"))
+		config.SecondaryValue = utils.ToPtr(utils.NewEnvStringValue("new-key"))
+		cred, err := NewApiKeyCredential(http.DefaultClient, config)
+		assert.NilError(t, err)
+
+		resp := send(t, cred, http.MethodGet, "")
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.DeepEqual(t, []string{"old-key:limit=10&api_key=old-key:", "new-key:limit=10&api_key=new-key:"}, requests)
+
+		endpoint, err := http.NewRequest(http.MethodGet, server.URL+"/pets?api_key=new-key", nil)
+		assert.NilError(t, err)
+		assert.Assert(t, !strings.Contains(cred.MaskURL(endpoint.URL).String(), "new-key"))
+	})
+
+	t.Run("bearer_rejected", func(t *testing.T) {
+		requests = nil
+		config := schema.NewHTTPAuthConfig("bearer", schema.AuthorizationHeader, utils.NewEnvStringValue("old-key"))
+		config.SecondaryValue = utils.ToPtr(utils.NewEnvStringValue("other-key"))
+		cred, err := NewHTTPCredential(http.DefaultClient, config)
+		assert.NilError(t, err)
+
+		var rotations []bool
+		cred.SetRotationHandler(func(ctx context.Context, accepted bool) {
+			rotations = append(rotations, accepted)
+		})
+
+		resp := send(t, cred, http.MethodGet, "")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.DeepEqual(t, []string{"old-key:limit=10:", "other-key:limit=10:"}, requests)
+		assert.DeepEqual(t, []bool{false}, rotations)
+	})
+
+	t.Run("without_secondary", func(t *testing.T) {
+		requests = nil
+		cred, err := NewHTTPCredential(http.DefaultClient, schema.NewHTTPAuthConfig("bearer", schema.AuthorizationHeader, utils.NewEnvStringValue("old-key")))
+		assert.NilError(t, err)
+		cred.SetRotationHandler(func(ctx context.Context, accepted bool) {
+			t.Fatal("the rotation handler must not be called")
+		})
+
+		resp := send(t, cred, http.MethodGet, "")
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+		assert.Equal(t, 1, len(requests))
+	})
+}
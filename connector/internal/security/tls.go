@@ -4,6 +4,7 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/base64"
+	"encoding/pem"
 	"errors"
 	"fmt"
 	"log/slog"
@@ -12,6 +13,8 @@ import (
 	"path/filepath"
 
 	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
 )
 
 var systemCertPool = x509.SystemCertPool
@@ -23,7 +26,7 @@ func NewHTTPClientTLS(baseClient *http.Client, tlsConfig *schema.TLSConfig, logg
 		baseTransport, _ = http.DefaultTransport.(*http.Transport)
 	}
 
-	tlsCfg, err := loadTLSConfig(tlsConfig, logger)
+	tlsCfg, err := LoadTLSConfig(tlsConfig, logger)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS config: %w", err)
 	}
@@ -39,9 +42,81 @@ func NewHTTPClientTLS(baseClient *http.Client, tlsConfig *schema.TLSConfig, logg
 	}, nil
 }
 
-// loadTLSConfig loads TLS certificates and returns a tls.Config.
+// MutualTLSCredential sends requests with the client certificate set of the mutualTLS security scheme
+// instead of the TLS config of the server.
+type MutualTLSCredential struct {
+	client *http.Client
+	active bool
+}
+
+var _ Credential = &MutualTLSCredential{}
+
+// NewMutualTLSCredential creates a new MutualTLSCredential instance.
+// The CA pool of the server is reused if the security scheme doesn't configure the CA.
+func NewMutualTLSCredential(client *http.Client, config *schema.MutualTLSAuthConfig, logger *slog.Logger) (*MutualTLSCredential, error) {
+	cred := &MutualTLSCredential{
+		client: client,
+	}
+
+	if config == nil || config.TLS == nil {
+		return cred, nil
+	}
+
+	tlsCfg, err := LoadTLSConfig(config.TLS, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MutualTLSCredential: %w", err)
+	}
+
+	// the credential is inactive if the client certificate is empty
+	if tlsCfg == nil || len(tlsCfg.Certificates) == 0 {
+		return cred, nil
+	}
+
+	baseClient := client
+	if baseClient == nil {
+		baseClient = http.DefaultClient
+	}
+
+	baseTransport, ok := baseClient.Transport.(*http.Transport)
+	if !ok {
+		baseTransport, _ = http.DefaultTransport.(*http.Transport)
+	}
+
+	if tlsCfg.RootCAs == nil && baseTransport.TLSClientConfig != nil {
+		tlsCfg.RootCAs = baseTransport.TLSClientConfig.RootCAs
+	}
+
+	transport := baseTransport.Clone()
+	transport.TLSClientConfig = tlsCfg
+	cred.client = &http.Client{
+		Transport:     transport,
+		CheckRedirect: baseClient.CheckRedirect,
+		Jar:           baseClient.Jar,
+		Timeout:       baseClient.Timeout,
+	}
+	cred.active = true
+
+	return cred, nil
+}
+
+// GetClient gets the HTTP client that is compatible with the current credential.
+func (mc MutualTLSCredential) GetClient() *http.Client {
+	return mc.client
+}
+
+// Inject the credential into the incoming request. The client certificate is sent by the HTTP client.
+func (mc MutualTLSCredential) Inject(req *http.Request) (bool, error) {
+	return mc.active, nil
+}
+
+// InjectMock injects the mock credential into the incoming request for explain APIs.
+func (mc MutualTLSCredential) InjectMock(req *http.Request) bool {
+	return mc.active
+}
+
+// LoadTLSConfig loads TLS certificates and returns a tls.Config.
 // This will set the RootCAs and Certificates of a tls.Config.
-func loadTLSConfig(tlsConfig *schema.TLSConfig, logger *slog.Logger) (*tls.Config, error) {
+func LoadTLSConfig(tlsConfig *schema.TLSConfig, logger *slog.Logger) (*tls.Config, error) {
 	certPool, err := loadCACertPool(tlsConfig)
 	if err != nil {
 		return nil, err
@@ -174,9 +249,25 @@ func loadCertPem(certPem []byte, includeSystemCACertsPool bool) (*x509.CertPool,
 
 func loadCertificate(tlsConfig *schema.TLSConfig, insecureSkipVerify bool, logger *slog.Logger) (*tls.Certificate, error) {
 	var certData, keyData []byte
-	var certPem, keyPem string
+	var certPem, keyPem, passphrase string
 	var err error
 
+	if tlsConfig.KeyPassphrase != nil {
+		passphrase, err = tlsConfig.KeyPassphrase.GetOrDefault("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key passphrase: %w", err)
+		}
+	}
+
+	pkcs12Data, err := loadPKCS12Data(tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(pkcs12Data) > 0 {
+		return loadPKCS12Certificate(pkcs12Data, passphrase)
+	}
+
 	if tlsConfig.CertPem != nil {
 		certPem, err = tlsConfig.CertPem.GetOrDefault("")
 		if err != nil {
@@ -245,6 +336,11 @@ func loadCertificate(tlsConfig *schema.TLSConfig, insecureSkipVerify bool, logge
 		return nil, errors.New("provide both certificate and key, or neither")
 	}
 
+	keyData, err = decryptPrivateKeyPEM(keyData, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
 	certificate, err := tls.X509KeyPair(certData, keyData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to load TLS cert and key PEMs: %w", err)
@@ -253,6 +349,103 @@ func loadCertificate(tlsConfig *schema.TLSConfig, insecureSkipVerify bool, logge
 	return &certificate, err
 }
 
+func loadPKCS12Data(tlsConfig *schema.TLSConfig) ([]byte, error) {
+	if tlsConfig.PKCS12Base64 != nil {
+		pkcs12Base64, err := tlsConfig.PKCS12Base64.GetOrDefault("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load PKCS#12 bundle: %w", err)
+		}
+
+		if pkcs12Base64 != "" {
+			data, err := base64.StdEncoding.DecodeString(pkcs12Base64)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode PKCS#12 bundle from base64: %w", err)
+			}
+
+			return data, nil
+		}
+	}
+
+	if tlsConfig.PKCS12File != nil {
+		pkcs12File, err := tlsConfig.PKCS12File.GetOrDefault("")
+		if err != nil {
+			return nil, fmt.Errorf("failed to load PKCS#12 file: %w", err)
+		}
+
+		if pkcs12File != "" {
+			data, err := os.ReadFile(pkcs12File)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read PKCS#12 file: %w", err)
+			}
+
+			return data, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// loadPKCS12Certificate decodes the PKCS#12 bundle which contains the private key, the leaf certificate and optional CA certificates.
+func loadPKCS12Certificate(data []byte, passphrase string) (*tls.Certificate, error) {
+	privateKey, leaf, caCerts, err := pkcs12.DecodeChain(data, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode PKCS#12 bundle: %w", err)
+	}
+
+	certificate := &tls.Certificate{
+		Certificate: [][]byte{leaf.Raw},
+		PrivateKey:  privateKey,
+		Leaf:        leaf,
+	}
+
+	for _, caCert := range caCerts {
+		certificate.Certificate = append(certificate.Certificate, caCert.Raw)
+	}
+
+	return certificate, nil
+}
+
+// decryptPrivateKeyPEM decrypts the private key if the PEM block is encrypted,
+// either in the PKCS#8 format or the legacy OpenSSL format with the DEK-Info header.
+func decryptPrivateKeyPEM(keyData []byte, passphrase string) ([]byte, error) {
+	block, _ := pem.Decode(keyData)
+	if block == nil {
+		return keyData, nil
+	}
+
+	isPKCS8Encrypted := block.Type == "ENCRYPTED PRIVATE KEY"
+	isLegacyEncrypted := x509.IsEncryptedPEMBlock(block) //nolint:staticcheck
+
+	if !isPKCS8Encrypted && !isLegacyEncrypted {
+		return keyData, nil
+	}
+
+	if passphrase == "" {
+		return nil, errors.New("the private key is encrypted but the key passphrase is empty")
+	}
+
+	if isPKCS8Encrypted {
+		privateKey, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, []byte(passphrase))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt the private key: %w", err)
+		}
+
+		der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt the private key: %w", err)
+		}
+
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+	}
+
+	der, err := x509.DecryptPEMBlock(block, []byte(passphrase)) //nolint:staticcheck
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt the private key: %w", err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+}
+
 func convertCipherSuites(cipherSuites []string) ([]uint16, error) {
 	var result []uint16
 	var errs []error
@@ -0,0 +1,199 @@
+package security
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"io"
+	"log/slog"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"github.com/youmark/pkcs8"
+	"gotest.tools/v3/assert"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestMutualTLSCredential(t *testing.T) {
+	caCert, clientCert, clientKey := generateTestClientCertificate(t)
+	clientKeyDER, err := x509.MarshalECPrivateKey(clientKey)
+	assert.NilError(t, err)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(caCert)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	server.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	config := schema.NewMutualTLSAuthConfig()
+	config.TLS = &schema.TLSConfig{
+		CertPem: utils.ToPtr(utils.NewEnvStringValue(base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientCert.Raw})))),
+		KeyPem:  utils.ToPtr(utils.NewEnvStringValue(base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: clientKeyDER})))),
+	}
+	assert.NilError(t, config.Validate())
+
+	// the CA pool of the server client is reused
+	cred, err := NewMutualTLSCredential(server.Client(), config, slog.Default())
+	assert.NilError(t, err)
+
+	req, err := http.NewRequestWithContext(context.TODO(), http.MethodGet, server.URL, nil)
+	assert.NilError(t, err)
+	ok, err := cred.Inject(req)
+	assert.NilError(t, err)
+	assert.Assert(t, ok)
+	assert.Assert(t, cred.InjectMock(req))
+
+	resp, err := cred.GetClient().Do(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+	assert.Equal(t, "product-a", string(body))
+
+	// the server client without the client certificate is rejected
+	_, err = server.Client().Get(server.URL)
+	assert.ErrorContains(t, err, "certificate")
+
+	t.Run("without_tls", func(t *testing.T) {
+		cred, err := NewMutualTLSCredential(server.Client(), schema.NewMutualTLSAuthConfig(), slog.Default())
+		assert.NilError(t, err)
+
+		ok, err := cred.Inject(req)
+		assert.NilError(t, err)
+		assert.Assert(t, !ok)
+	})
+}
+
+func TestLoadCertificatePKCS12(t *testing.T) {
+	caCert, clientCert, clientKey := generateTestClientCertificate(t)
+	pfxData, err := pkcs12.Modern.Encode(clientKey, clientCert, []*x509.Certificate{caCert}, "secret")
+	assert.NilError(t, err)
+
+	pfxFile := filepath.Join(t.TempDir(), "client.p12")
+	assert.NilError(t, os.WriteFile(pfxFile, pfxData, 0o600))
+	t.Setenv("CLIENT_KEY_PASSPHRASE", "secret")
+
+	for _, tlsConfig := range []*schema.TLSConfig{
+		{
+			PKCS12File:    utils.ToPtr(utils.NewEnvStringValue(pfxFile)),
+			KeyPassphrase: utils.ToPtr(utils.NewEnvStringVariable("CLIENT_KEY_PASSPHRASE")),
+		},
+		{
+			PKCS12Base64:  utils.ToPtr(utils.NewEnvStringValue(base64.StdEncoding.EncodeToString(pfxData))),
+			KeyPassphrase: utils.ToPtr(utils.NewEnvStringValue("secret")),
+		},
+	} {
+		assert.NilError(t, tlsConfig.Validate())
+
+		cert, err := loadCertificate(tlsConfig, false, slog.Default())
+		assert.NilError(t, err)
+		assert.Equal(t, "product-a", cert.Leaf.Subject.CommonName)
+		assert.Equal(t, 2, len(cert.Certificate))
+		assert.Assert(t, clientKey.Equal(cert.PrivateKey))
+	}
+
+	_, err = loadCertificate(&schema.TLSConfig{
+		PKCS12File:    utils.ToPtr(utils.NewEnvStringValue(pfxFile)),
+		KeyPassphrase: utils.ToPtr(utils.NewEnvStringValue("wrong")),
+	}, false, slog.Default())
+	assert.ErrorContains(t, err, "failed to decode PKCS#12 bundle")
+
+	assert.ErrorContains(t, (&schema.TLSConfig{
+		PKCS12File: utils.ToPtr(utils.NewEnvStringValue(pfxFile)),
+		KeyFile:    utils.ToPtr(utils.NewEnvStringValue("client.key")),
+	}).Validate(), "the PKCS#12 bundle can't be used with the certificate and key")
+}
+
+func TestLoadCertificateEncryptedKey(t *testing.T) {
+	_, clientCert, clientKey := generateTestClientCertificate(t)
+	certPem := base64.StdEncoding.EncodeToString(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: clientCert.Raw}))
+
+	pkcs8DER, err := pkcs8.MarshalPrivateKey(clientKey, []byte("secret"), nil)
+	assert.NilError(t, err)
+
+	ecDER, err := x509.MarshalECPrivateKey(clientKey)
+	assert.NilError(t, err)
+	legacyBlock, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", ecDER, []byte("secret"), x509.PEMCipherAES256) //nolint:staticcheck
+	assert.NilError(t, err)
+
+	for name, keyPem := range map[string][]byte{
+		"pkcs8":  pem.EncodeToMemory(&pem.Block{Type: "ENCRYPTED PRIVATE KEY", Bytes: pkcs8DER}),
+		"legacy": pem.EncodeToMemory(legacyBlock),
+	} {
+		t.Run(name, func(t *testing.T) {
+			tlsConfig := &schema.TLSConfig{
+				CertPem:       utils.ToPtr(utils.NewEnvStringValue(certPem)),
+				KeyPem:        utils.ToPtr(utils.NewEnvStringValue(base64.StdEncoding.EncodeToString(keyPem))),
+				KeyPassphrase: utils.ToPtr(utils.NewEnvStringValue("secret")),
+			}
+
+			cert, err := loadCertificate(tlsConfig, false, slog.Default())
+			assert.NilError(t, err)
+			assert.Assert(t, clientKey.Equal(cert.PrivateKey))
+
+			tlsConfig.KeyPassphrase = nil
+			_, err = loadCertificate(tlsConfig, false, slog.Default())
+			assert.ErrorContains(t, err, "the key passphrase is empty")
+
+			// a wrong passphrase may rarely pass the padding check, so the key pair validation fails instead
+			tlsConfig.KeyPassphrase = utils.ToPtr(utils.NewEnvStringValue("wrong"))
+			_, err = loadCertificate(tlsConfig, false, slog.Default())
+			assert.Assert(t, err != nil)
+		})
+	}
+}
+
+func generateTestClientCertificate(t *testing.T) (*x509.Certificate, *x509.Certificate, *ecdsa.PrivateKey) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "test-ca"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	assert.NilError(t, err)
+	caCert, err := x509.ParseCertificate(caDER)
+	assert.NilError(t, err)
+
+	clientKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.NilError(t, err)
+	clientDER, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "product-a"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, caCert, &clientKey.PublicKey, caKey)
+	assert.NilError(t, err)
+	clientCert, err := x509.ParseCertificate(clientDER)
+	assert.NilError(t, err)
+
+	return caCert, clientCert, clientKey
+}
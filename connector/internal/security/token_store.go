@@ -0,0 +1,113 @@
+package security
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hasura/ndc-sdk-go/connector"
+	"golang.org/x/oauth2"
+)
+
+// the token is refreshed before it expires to avoid using expired tokens due to clock skews.
+const sharedTokenExpiryDelta = 10 * time.Second
+
+// TokenStore abstracts the storage of OAuth2 tokens which are shared between connector replicas.
+type TokenStore interface {
+	// GetToken returns the token of the key. Returns nil if not exists.
+	GetToken(ctx context.Context, key string) (*oauth2.Token, error)
+	// SetToken stores the token until it expires.
+	SetToken(ctx context.Context, key string, token *oauth2.Token) error
+}
+
+// sharedTokenSource reads tokens from the shared store before requesting new tokens from the authorization server.
+type sharedTokenSource struct {
+	ctx    context.Context
+	key    string
+	store  TokenStore
+	source oauth2.TokenSource
+}
+
+// newSharedTokenSource creates a token source which shares tokens of the credentials in the store.
+// The key of tokens is the hash of identities of the credentials.
+func newSharedTokenSource(ctx context.Context, store TokenStore, source oauth2.TokenSource, keyParts ...string) oauth2.TokenSource {
+	if store == nil {
+		return source
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join(keyParts, "\n")))
+
+	return oauth2.ReuseTokenSource(nil, &sharedTokenSource{
+		ctx:    ctx,
+		key:    hex.EncodeToString(hash[:]),
+		store:  store,
+		source: source,
+	})
+}
+
+// Token returns a valid token from the shared store, or requests a new token and stores it.
+// Errors of the store are logged and don't fail the request.
+func (sts *sharedTokenSource) Token() (*oauth2.Token, error) {
+	logger := connector.GetLogger(sts.ctx)
+	token, err := sts.store.GetToken(sts.ctx, sts.key)
+	if err != nil {
+		logger.Warn("failed to get the shared OAuth2 token", slog.String("error", err.Error()))
+	}
+
+	if token != nil && token.Valid() && (token.Expiry.IsZero() || time.Until(token.Expiry) > sharedTokenExpiryDelta) {
+		return token, nil
+	}
+
+	token, err = sts.source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := sts.store.SetToken(sts.ctx, sts.key, token); err != nil {
+		logger.Warn("failed to store the shared OAuth2 token", slog.String("error", err.Error()))
+	}
+
+	return token, nil
+}
+
+// MemoryTokenStore stores OAuth2 tokens in memory, so credentials of the same client are shared between servers and schemas of the connector.
+type MemoryTokenStore struct {
+	lock   sync.RWMutex
+	tokens map[string]*oauth2.Token
+}
+
+var _ TokenStore = (*MemoryTokenStore)(nil)
+
+// NewMemoryTokenStore creates a new MemoryTokenStore instance.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{
+		tokens: make(map[string]*oauth2.Token),
+	}
+}
+
+// GetToken returns the token of the key. Returns nil if not exists or expired.
+func (mts *MemoryTokenStore) GetToken(_ context.Context, key string) (*oauth2.Token, error) {
+	mts.lock.RLock()
+	defer mts.lock.RUnlock()
+
+	token, ok := mts.tokens[key]
+	if !ok || !token.Valid() {
+		return nil, nil
+	}
+
+	return token, nil
+}
+
+// SetToken stores the token until it expires.
+func (mts *MemoryTokenStore) SetToken(_ context.Context, key string, token *oauth2.Token) error {
+	mts.lock.Lock()
+	defer mts.lock.Unlock()
+
+	mts.tokens[key] = token
+
+	return nil
+}
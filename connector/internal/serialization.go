@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/hasura/ndc-http/connector/internal/contenttype"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
+)
+
+// encodeProtobufRequestBody encodes the JSON body of the request to the binary protobuf message of the operation.
+func (client *HTTPClient) encodeProtobufRequestBody(request *RetryableRequest) *UpstreamError {
+	settings := request.RawRequest.Protobuf
+	if settings == nil || settings.RequestMessage == "" || len(request.Body) == 0 || request.StreamBody != nil {
+		return nil
+	}
+
+	if !restUtils.IsContentTypeProtobuf(parseContentType(request.ContentType)) {
+		return nil
+	}
+
+	message, err := client.manager.grpcClients.FindMessage(settings.DescriptorSet, settings.RequestMessage)
+	if err != nil {
+		return NewUpstreamError(ErrorCategoryInternal, err.Error(), nil)
+	}
+
+	body, err := contenttype.NewProtobufCodec(message).Encode(json.RawMessage(request.Body))
+	if err != nil {
+		return NewUpstreamError(ErrorCategoryInternal, "failed to encode the request body", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	request.Body = body
+
+	return nil
+}
+
+// transcodeResponseBody transcodes YAML, MessagePack and protobuf response bodies to JSON,
+// so they are decoded with types of the schema in the same way as JSON responses.
+// Returns the original content type if the body isn't transcoded.
+func (client *HTTPClient) transcodeResponseBody(body io.Reader, contentType string) (io.Reader, string, *UpstreamError) {
+	var rawJSON []byte
+	switch {
+	case restUtils.IsContentTypeYAML(contentType):
+		// the raw YAML document is returned if the result type is String
+		if namedType, err := client.requests.Operation.ResultType.AsNamed(); err == nil && namedType.Name == string(rest.ScalarString) {
+			return body, contentType, nil
+		}
+
+		value, err := contenttype.DecodeYAML(body)
+		if err != nil {
+			return nil, "", NewUpstreamError(ErrorCategoryDecode, "failed to decode the YAML response body", map[string]any{
+				"cause": err.Error(),
+			})
+		}
+
+		rawJSON, err = json.Marshal(value)
+		if err != nil {
+			return nil, "", NewUpstreamError(ErrorCategoryDecode, err.Error(), nil)
+		}
+	case restUtils.IsContentTypeMsgPack(contentType):
+		value, err := contenttype.DecodeMsgPack(body)
+		if err != nil {
+			return nil, "", NewUpstreamError(ErrorCategoryDecode, "failed to decode the MessagePack response body", map[string]any{
+				"cause": err.Error(),
+			})
+		}
+
+		rawJSON, err = json.Marshal(value)
+		if err != nil {
+			return nil, "", NewUpstreamError(ErrorCategoryDecode, err.Error(), nil)
+		}
+	case restUtils.IsContentTypeProtobuf(contentType):
+		rawRequest := client.requests.Operation.Request
+		if rawRequest == nil || rawRequest.Protobuf == nil || rawRequest.Protobuf.ResponseMessage == "" {
+			return body, contentType, nil
+		}
+
+		message, err := client.manager.grpcClients.FindMessage(rawRequest.Protobuf.DescriptorSet, rawRequest.Protobuf.ResponseMessage)
+		if err != nil {
+			return nil, "", NewUpstreamError(ErrorCategoryInternal, err.Error(), nil)
+		}
+
+		rawJSON, err = contenttype.NewProtobufCodec(message).DecodeJSON(body)
+		if err != nil {
+			return nil, "", NewUpstreamError(ErrorCategoryDecode, "failed to decode the protobuf response body", map[string]any{
+				"cause": err.Error(),
+			})
+		}
+	default:
+		return body, contentType, nil
+	}
+
+	return bytes.NewReader(rawJSON), rest.ContentTypeJSON, nil
+}
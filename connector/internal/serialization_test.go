@@ -0,0 +1,135 @@
+package internal
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hasura/ndc-http/connector/internal/contenttype"
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"gotest.tools/v3/assert"
+)
+
+func TestTranscodeResponseBody(t *testing.T) {
+	fileDesc := &descriptorpb.FileDescriptorProto{
+		Name:    proto.String("pet.proto"),
+		Package: proto.String("test.v1"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{
+			{
+				Name: proto.String("Pet"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("id"), JsonName: proto.String("id"), Number: proto.Int32(1), Type: descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+					{Name: proto.String("name"), JsonName: proto.String("name"), Number: proto.Int32(2), Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum()},
+				},
+			},
+		},
+	}
+
+	dir := t.TempDir()
+	rawDescriptorSet, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{fileDesc}})
+	assert.NilError(t, err)
+	assert.NilError(t, os.WriteFile(filepath.Join(dir, "pet.pb"), rawDescriptorSet, 0o600))
+
+	file, err := protodesc.NewFile(fileDesc, nil)
+	assert.NilError(t, err)
+	codec := contenttype.NewProtobufCodec(file.Messages().Get(0))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NilError(t, err)
+
+		switch r.URL.Path {
+		case "/protobuf":
+			assert.Equal(t, rest.ContentTypeProtobuf, r.Header.Get(rest.ContentTypeHeader))
+			pet, err := codec.Decode(bytes.NewReader(body))
+			assert.NilError(t, err)
+			assert.DeepEqual(t, map[string]any{"id": float64(1), "name": "doggie"}, pet)
+
+			w.Header().Set(rest.ContentTypeHeader, rest.ContentTypeProtobuf)
+			_, _ = w.Write(body)
+		case "/yaml":
+			w.Header().Set(rest.ContentTypeHeader, "application/yaml")
+			_, _ = w.Write([]byte("kind: Pod\nmetadata:\n  name: nginx\n"))
+		case "/msgpack":
+			assert.Equal(t, rest.ContentTypeMsgPack, r.Header.Get(rest.ContentTypeHeader))
+			w.Header().Set(rest.ContentTypeHeader, "application/x-msgpack")
+			_, _ = w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	um := NewUpstreamManager(http.DefaultClient, &configuration.Configuration{})
+	um.SetGRPCClients(NewGRPCClients(dir))
+
+	sendRequest := func(t *testing.T, path string, contentType string, body []byte, settings *rest.ProtobufSettings) any {
+		t.Helper()
+
+		requestURL, err := url.Parse(server.URL + path)
+		assert.NilError(t, err)
+
+		rawRequest := &rest.Request{
+			Method:   http.MethodPost,
+			Protobuf: settings,
+			Response: rest.Response{
+				ContentType: contentType,
+			},
+		}
+
+		client := um.CreateHTTPClient(&RequestBuilderResults{
+			OperationName: "createPet",
+			Operation: &rest.OperationInfo{
+				Request: rawRequest,
+			},
+			Requests: []*RetryableRequest{
+				{
+					URL:         *requestURL,
+					RawRequest:  rawRequest,
+					ContentType: contentType,
+					Headers:     http.Header{rest.ContentTypeHeader: []string{contentType}},
+					Body:        body,
+				},
+			},
+			HTTPOptions: &HTTPOptions{},
+		})
+
+		result, _, err := client.Send(context.TODO(), nil)
+		assert.NilError(t, err)
+
+		return result
+	}
+
+	t.Run("protobuf", func(t *testing.T) {
+		result := sendRequest(t, "/protobuf", rest.ContentTypeProtobuf, []byte(`{"id":1,"name":"doggie"}`), &rest.ProtobufSettings{
+			DescriptorSet:   "pet.pb",
+			RequestMessage:  "test.v1.Pet",
+			ResponseMessage: "test.v1.Pet",
+		})
+		assert.DeepEqual(t, map[string]any{"id": float64(1), "name": "doggie"}, result)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		result := sendRequest(t, "/yaml", "application/yaml", nil, nil)
+		assert.DeepEqual(t, map[string]any{"kind": "Pod", "metadata": map[string]any{"name": "nginx"}}, result)
+	})
+
+	t.Run("msgpack", func(t *testing.T) {
+		body, err := contenttype.EncodeMsgPack(map[string]any{"id": 1, "name": "doggie", "tags": []string{"dog"}})
+		assert.NilError(t, err)
+
+		result := sendRequest(t, "/msgpack", rest.ContentTypeMsgPack, body, nil)
+		assert.DeepEqual(t, map[string]any{"id": float64(1), "name": "doggie", "tags": []any{"dog"}}, result)
+	})
+}
@@ -0,0 +1,255 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+const (
+	ProcedureSnapshotTest        string = "_snapshotTest"
+	objectTypeSnapshotTestResult string = "SnapshotTestResult"
+	objectTypeSnapshotDifference string = "SnapshotDifference"
+	defaultSnapshotDir           string = "snapshots"
+)
+
+// SnapshotTestStatus represents the status of a snapshot assertion
+type SnapshotTestStatus string
+
+const (
+	SnapshotTestCreated    SnapshotTestStatus = "created"
+	SnapshotTestUpdated    SnapshotTestStatus = "updated"
+	SnapshotTestMatched    SnapshotTestStatus = "matched"
+	SnapshotTestMismatched SnapshotTestStatus = "mismatched"
+)
+
+var snapshotNameRegex = regexp.MustCompile(`^[\w-]+$`)
+
+// SnapshotTestArguments represent arguments of the _snapshotTest procedure
+type SnapshotTestArguments struct {
+	Name      string         `json:"name"`
+	Operation string         `json:"operation"`
+	Arguments map[string]any `json:"arguments"`
+	Update    bool           `json:"update"`
+}
+
+// Validate checks if arguments are valid.
+func (sta SnapshotTestArguments) Validate() error {
+	if !snapshotNameRegex.MatchString(sta.Name) {
+		return fmt.Errorf("invalid snapshot name %q, expected letters, digits, underscores and dashes only", sta.Name)
+	}
+
+	if sta.Operation == "" {
+		return errors.New("operation is required")
+	}
+
+	if sta.Operation == ProcedureSnapshotTest {
+		return errors.New("can't create snapshots of the snapshot test procedure")
+	}
+
+	return nil
+}
+
+// SnapshotTestResult represents the report of a snapshot assertion
+type SnapshotTestResult struct {
+	Name        string               `json:"name"`
+	Status      SnapshotTestStatus   `json:"status"`
+	Passed      bool                 `json:"passed"`
+	Differences []SnapshotDifference `json:"differences"`
+}
+
+// SnapshotDifference represents a different value between the snapshot and the actual result
+type SnapshotDifference struct {
+	Path     string `json:"path"`
+	Expected any    `json:"expected"`
+	Actual   any    `json:"actual"`
+}
+
+// SnapshotStore reads and writes snapshot files in a directory.
+type SnapshotStore struct {
+	dir string
+}
+
+// NewSnapshotStore creates a SnapshotStore instance. Returns nil if the snapshot test is disabled.
+func NewSnapshotStore(config *configuration.Configuration, configDir string) *SnapshotStore {
+	if config == nil || config.SnapshotTest == nil || !config.SnapshotTest.Enabled {
+		return nil
+	}
+
+	dir := config.SnapshotTest.Dir
+	if dir == "" {
+		dir = defaultSnapshotDir
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(configDir, dir)
+	}
+
+	return &SnapshotStore{
+		dir: dir,
+	}
+}
+
+// Assert compares the result with the stored snapshot.
+// The snapshot is created if it doesn't exist, or overwritten if the update flag is true.
+func (ss *SnapshotStore) Assert(name string, result any, update bool) (*SnapshotTestResult, error) {
+	actual := normalizeJSONValue(result)
+	filePath := filepath.Join(ss.dir, name+".json")
+
+	rawBytes, err := os.ReadFile(filePath)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read the snapshot file: %w", err)
+	}
+
+	if err != nil || update {
+		status := SnapshotTestCreated
+		if err == nil {
+			status = SnapshotTestUpdated
+		}
+
+		if err := ss.write(filePath, actual); err != nil {
+			return nil, err
+		}
+
+		return &SnapshotTestResult{
+			Name:        name,
+			Status:      status,
+			Passed:      true,
+			Differences: []SnapshotDifference{},
+		}, nil
+	}
+
+	var expected any
+	if err := json.Unmarshal(rawBytes, &expected); err != nil {
+		return nil, fmt.Errorf("failed to decode the snapshot file: %w", err)
+	}
+
+	differences := compareValues("$", expected, actual)
+	report := &SnapshotTestResult{
+		Name:        name,
+		Status:      SnapshotTestMatched,
+		Passed:      len(differences) == 0,
+		Differences: make([]SnapshotDifference, len(differences)),
+	}
+
+	if !report.Passed {
+		report.Status = SnapshotTestMismatched
+	}
+
+	for i, diff := range differences {
+		report.Differences[i] = SnapshotDifference{
+			Path:     diff.Path,
+			Expected: diff.Left,
+			Actual:   diff.Right,
+		}
+	}
+
+	return report, nil
+}
+
+func (ss *SnapshotStore) write(filePath string, value any) error {
+	rawBytes, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(ss.dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create the snapshot directory: %w", err)
+	}
+
+	if err := os.WriteFile(filePath, rawBytes, 0o644); err != nil { //nolint:gosec
+		return fmt.Errorf("failed to write the snapshot file: %w", err)
+	}
+
+	return nil
+}
+
+// ApplySnapshotTestSchema adds the _snapshotTest procedure and related types to the schema.
+func ApplySnapshotTestSchema(input *schema.SchemaResponse, forwardHeaderConfig configuration.ForwardHeadersSettings) {
+	if _, ok := input.ScalarTypes[string(rest.ScalarBoolean)]; !ok {
+		input.ScalarTypes[string(rest.ScalarBoolean)] = schema.ScalarType{
+			AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+			ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+			Representation:      schema.NewTypeRepresentationBoolean().Encode(),
+		}
+	}
+
+	input.ObjectTypes[objectTypeSnapshotDifference] = schema.ObjectType{
+		Description: utils.ToPtr("A different value between the snapshot and the actual result"),
+		Fields: schema.ObjectTypeFields{
+			"path": {
+				Description: utils.ToPtr("The JSON path of the different value"),
+				Type:        schema.NewNamedType(string(rest.ScalarString)).Encode(),
+			},
+			"expected": {
+				Description: utils.ToPtr("The value in the snapshot"),
+				Type:        schema.NewNullableNamedType(string(rest.ScalarJSON)).Encode(),
+			},
+			"actual": {
+				Description: utils.ToPtr("The value in the actual result"),
+				Type:        schema.NewNullableNamedType(string(rest.ScalarJSON)).Encode(),
+			},
+		},
+	}
+
+	input.ObjectTypes[objectTypeSnapshotTestResult] = schema.ObjectType{
+		Description: utils.ToPtr("The report of a snapshot assertion"),
+		Fields: schema.ObjectTypeFields{
+			"name": {
+				Description: utils.ToPtr("Name of the snapshot"),
+				Type:        schema.NewNamedType(string(rest.ScalarString)).Encode(),
+			},
+			"status": {
+				Description: utils.ToPtr("Status of the assertion, is one of created, updated, matched, mismatched"),
+				Type:        schema.NewNamedType(string(rest.ScalarString)).Encode(),
+			},
+			"passed": {
+				Description: utils.ToPtr("Whether the result matches the snapshot"),
+				Type:        schema.NewNamedType(string(rest.ScalarBoolean)).Encode(),
+			},
+			"differences": {
+				Description: utils.ToPtr("Differences between the snapshot and the actual result"),
+				Type:        schema.NewArrayType(schema.NewNamedType(objectTypeSnapshotDifference)).Encode(),
+			},
+		},
+	}
+
+	procSnapshotTest := schema.ProcedureInfo{
+		Name:        ProcedureSnapshotTest,
+		Description: utils.ToPtr("Execute an operation and compare the result against the stored snapshot. The snapshot is created on the first run"),
+		Arguments: map[string]schema.ArgumentInfo{
+			"name": {
+				Description: utils.ToPtr("Name of the snapshot"),
+				Type:        schema.NewNamedType(string(rest.ScalarString)).Encode(),
+			},
+			"operation": {
+				Description: utils.ToPtr("Name of the function or procedure to be executed"),
+				Type:        schema.NewNamedType(string(rest.ScalarString)).Encode(),
+			},
+			"arguments": {
+				Description: utils.ToPtr("Arguments of the operation"),
+				Type:        schema.NewNullableNamedType(string(rest.ScalarJSON)).Encode(),
+			},
+			"update": {
+				Description: utils.ToPtr("Overwrite the stored snapshot with the actual result"),
+				Type:        schema.NewNullableNamedType(string(rest.ScalarBoolean)).Encode(),
+			},
+		},
+		ResultType: schema.NewNamedType(objectTypeSnapshotTestResult).Encode(),
+	}
+
+	// forwarded headers are passed to the target operation
+	if forwardHeaderConfig.ArgumentField != nil && *forwardHeaderConfig.ArgumentField != "" {
+		procSnapshotTest.Arguments[*forwardHeaderConfig.ArgumentField] = configuration.NewHeadersArgumentInfo().ArgumentInfo
+	}
+
+	input.Procedures = append(input.Procedures, procSnapshotTest)
+}
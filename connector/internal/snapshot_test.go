@@ -0,0 +1,65 @@
+package internal
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"gotest.tools/v3/assert"
+)
+
+func TestSnapshotStore(t *testing.T) {
+	assert.Assert(t, NewSnapshotStore(&configuration.Configuration{}, t.TempDir()) == nil)
+
+	configDir := t.TempDir()
+	store := NewSnapshotStore(&configuration.Configuration{
+		SnapshotTest: &configuration.SnapshotTestSettings{
+			Enabled: true,
+		},
+	}, configDir)
+
+	result := []map[string]any{
+		{"id": 1, "name": "cat"},
+	}
+
+	report, err := store.Assert("findPets", result, false)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &SnapshotTestResult{
+		Name:        "findPets",
+		Status:      SnapshotTestCreated,
+		Passed:      true,
+		Differences: []SnapshotDifference{},
+	}, report)
+
+	_, err = os.Stat(filepath.Join(configDir, "snapshots", "findPets.json"))
+	assert.NilError(t, err)
+
+	report, err = store.Assert("findPets", result, false)
+	assert.NilError(t, err)
+	assert.Equal(t, SnapshotTestMatched, report.Status)
+	assert.Assert(t, report.Passed)
+
+	report, err = store.Assert("findPets", []map[string]any{{"id": 1, "name": "dog"}}, false)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, &SnapshotTestResult{
+		Name:   "findPets",
+		Status: SnapshotTestMismatched,
+		Passed: false,
+		Differences: []SnapshotDifference{
+			{Path: "$[0].name", Expected: "cat", Actual: "dog"},
+		},
+	}, report)
+
+	report, err = store.Assert("findPets", []map[string]any{{"id": 1, "name": "dog"}}, true)
+	assert.NilError(t, err)
+	assert.Equal(t, SnapshotTestUpdated, report.Status)
+
+	report, err = store.Assert("findPets", []map[string]any{{"id": 1, "name": "dog"}}, false)
+	assert.NilError(t, err)
+	assert.Equal(t, SnapshotTestMatched, report.Status)
+
+	assert.ErrorContains(t, SnapshotTestArguments{Name: "../foo", Operation: "findPets"}.Validate(), "invalid snapshot name")
+	assert.ErrorContains(t, SnapshotTestArguments{Name: "foo"}.Validate(), "operation is required")
+	assert.NilError(t, SnapshotTestArguments{Name: "find-pets_1", Operation: "findPets"}.Validate())
+}
@@ -0,0 +1,77 @@
+package internal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// SetTransformers sets compiled request and response transformations of operations.
+func (um *UpstreamManager) SetTransformers(transformers map[string]*configuration.OperationTransformer) {
+	um.transformers = transformers
+}
+
+// apply request transformations of the operation to built requests.
+// Body transformations are applied to JSON bodies only.
+func (um *UpstreamManager) transformRequests(results *RequestBuilderResults, rawArgs map[string]any) (*RequestBuilderResults, error) {
+	transformer := um.transformers[results.OperationName]
+	if !transformer.HasRequestTransforms() {
+		return results, nil
+	}
+
+	for _, req := range results.Requests {
+		var body any
+		isJSONBody := len(req.Body) > 0 && restUtils.IsContentTypeJSON(req.ContentType)
+		if isJSONBody {
+			if err := json.Unmarshal(req.Body, &body); err != nil {
+				return nil, schema.InternalServerError("failed to decode the request body to transform", map[string]any{
+					"cause": err.Error(),
+				})
+			}
+		}
+
+		for key, header := range transformer.RequestHeaders(rawArgs, body) {
+			req.Headers.Set(key, header)
+		}
+
+		if !isJSONBody || !transformer.HasRequestBodyTransforms() {
+			continue
+		}
+
+		rawBody, err := json.Marshal(transformer.TransformRequestBody(rawArgs, body))
+		if err != nil {
+			return nil, schema.InternalServerError("failed to encode the transformed request body", map[string]any{
+				"cause": err.Error(),
+			})
+		}
+
+		req.Body = rawBody
+	}
+
+	return results, nil
+}
+
+// transformResponseBody applies response transformations of the operation to the JSON response body.
+func (um *UpstreamManager) transformResponseBody(operationName string, body io.Reader) (io.Reader, error) {
+	transformer := um.transformers[operationName]
+	if !transformer.HasResponseTransforms() {
+		return body, nil
+	}
+
+	var value any
+	if err := json.NewDecoder(body).Decode(&value); err != nil {
+		return nil, fmt.Errorf("failed to decode the response body to transform: %w", err)
+	}
+
+	rawBody, err := json.Marshal(transformer.TransformResponseBody(value))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode the transformed response body: %w", err)
+	}
+
+	return bytes.NewReader(rawBody), nil
+}
@@ -20,6 +20,7 @@ const (
 
 var (
 	errRequestBodyRequired = errors.New("request body is required")
+	errRateLimitExceeded   = errors.New("rate limit exceeded: the wait time is longer than the request timeout")
 )
 
 var defaultRetryHTTPStatus = []int{429, 500, 502, 503}
@@ -28,8 +29,13 @@ var urlAndHeaderLocations = []rest.ParameterLocation{rest.InPath, rest.InQuery,
 
 // HTTPOptions represent execution options for HTTP requests
 type HTTPOptions struct {
-	Servers  []string `json:"serverIds" yaml:"serverIds"`
-	Parallel bool     `json:"parallel"  yaml:"parallel"`
+	Servers        []string `json:"serverIds"                yaml:"serverIds"`
+	Parallel       bool     `json:"parallel"                 yaml:"parallel"`
+	CompareServers []string `json:"compareServers,omitempty" yaml:"compareServers,omitempty"`
+	// The execution strategy of distributed requests. Default to all
+	Strategy rest.DistributedExecutionStrategy `json:"strategy,omitempty" yaml:"strategy,omitempty"`
+	// Build and validate requests of the procedure without sending them
+	DryRun bool `json:"dryRun,omitempty" yaml:"dryRun,omitempty"`
 
 	Distributed bool `json:"-" yaml:"-"`
 	Concurrency uint `json:"-" yaml:"-"`
@@ -58,6 +64,31 @@ func (ro *HTTPOptions) FromValue(value any) error {
 	}
 	ro.Parallel = parallel != nil && *parallel
 
+	compareServers, err := utils.GetNullableStringSlice(valueMap, "compareServers")
+	if err != nil {
+		return fmt.Errorf("invalid compareServers in http options: %w", err)
+	}
+	if compareServers != nil {
+		ro.CompareServers = *compareServers
+	}
+
+	dryRun, err := utils.GetNullableBoolean(valueMap, "dryRun")
+	if err != nil {
+		return fmt.Errorf("invalid dryRun in http options: %w", err)
+	}
+	ro.DryRun = dryRun != nil && *dryRun
+
+	strategy, err := utils.GetNullableString(valueMap, "strategy")
+	if err != nil {
+		return fmt.Errorf("invalid strategy in http options: %w", err)
+	}
+	if strategy != nil && *strategy != "" {
+		ro.Strategy, err = rest.ParseDistributedExecutionStrategy(*strategy)
+		if err != nil {
+			return fmt.Errorf("invalid strategy in http options: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -90,8 +121,23 @@ type DistributedResult[T any] struct {
 
 // DistributedResponse represents the response object of distributed operations
 type DistributedResponse[T any] struct {
-	Results []DistributedResult[T] `json:"results" yaml:"results"`
-	Errors  []DistributedError     `json:"errors"  yaml:"errors"`
+	Results    []DistributedResult[T] `json:"results"              yaml:"results"`
+	Errors     []DistributedError     `json:"errors"               yaml:"errors"`
+	Comparison *DistributedComparison `json:"comparison,omitempty" yaml:"comparison,omitempty"`
+}
+
+// DistributedComparison represents the structural comparison between results of two servers
+type DistributedComparison struct {
+	Servers     []string                `json:"servers"     yaml:"servers"`
+	Equal       bool                    `json:"equal"       yaml:"equal"`
+	Differences []DistributedDifference `json:"differences" yaml:"differences"`
+}
+
+// DistributedDifference represents a different value between results of two servers
+type DistributedDifference struct {
+	Path  string `json:"path"  yaml:"path"`
+	Left  any    `json:"left"  yaml:"left"`
+	Right any    `json:"right" yaml:"right"`
 }
 
 // NewDistributedResponse creates an empty DistributedResponse instance
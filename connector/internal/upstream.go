@@ -2,13 +2,21 @@ package internal
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strconv"
+	"sync"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/hasura/ndc-http/connector/internal/argument"
 	"github.com/hasura/ndc-http/connector/internal/compression"
+	"github.com/hasura/ndc-http/connector/internal/contenttype"
 	"github.com/hasura/ndc-http/connector/internal/security"
 	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
 	"github.com/hasura/ndc-http/ndc-http-schema/schema"
@@ -17,6 +25,7 @@ import (
 	"github.com/hasura/ndc-sdk-go/connector"
 	"github.com/hasura/ndc-sdk-go/utils"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/propagation"
 )
 
@@ -25,8 +34,21 @@ type UpstreamManager struct {
 	config        *configuration.Configuration
 	defaultClient *http.Client
 	upstreams     map[string]UpstreamSetting
+	upstreamsLock sync.RWMutex
 	compressors   *compression.Compressors
 	propagator    propagation.TextMapPropagator
+	metrics       *UpstreamMetrics
+	tokenStore    security.TokenStore
+	rateLimiter   RateLimiter
+	transformers  map[string]*configuration.OperationTransformer
+	decoders      *contenttype.ResponseDecoderRegistry
+	grpcClients   *GRPCClients
+	scheduler     *FairScheduler
+	auditLogger   *AuditLogger
+	recorder      *Recorder
+	headersMapper *configuration.ForwardHeadersMapper
+	cookieJar     *CookieJar
+	idempotency   *IdempotencyKeyInjector
 }
 
 // NewUpstreamManager creates a new UpstreamManager instance.
@@ -37,6 +59,9 @@ func NewUpstreamManager(httpClient *http.Client, config *configuration.Configura
 		upstreams:     make(map[string]UpstreamSetting),
 		compressors:   compression.NewCompressors(),
 		propagator:    otel.GetTextMapPropagator(),
+		tokenStore:    security.NewMemoryTokenStore(),
+		rateLimiter:   newMemoryRateLimiter(),
+		grpcClients:   NewGRPCClients("."),
 	}
 }
 
@@ -57,14 +82,33 @@ func (um *UpstreamManager) Register(ctx context.Context, runtimeSchema *configur
 		}
 	}
 
+	httpClient, err := newHTTPClientProxy(httpClient, runtimeSchema.Settings.Proxy)
+	if err != nil {
+		return fmt.Errorf("%s.proxy: %w", namespace, err)
+	}
+
+	httpClient, err = newHTTPClientConnection(httpClient, runtimeSchema.Settings.Connection)
+	if err != nil {
+		return fmt.Errorf("%s.connection: %w", namespace, err)
+	}
+
 	settings := UpstreamSetting{
 		servers:     make(map[string]Server),
 		security:    runtimeSchema.Settings.Security,
 		headers:     um.getHeadersFromEnv(logger, namespace, runtimeSchema.Settings.Headers),
-		credentials: um.registerSecurityCredentials(ctx, httpClient, runtimeSchema.Settings.SecuritySchemes, logger.With(slog.String("namespace", namespace))),
+		credentials: um.registerSecurityCredentials(ctx, httpClient, namespace, runtimeSchema.Settings.SecuritySchemes, logger.With(slog.String("namespace", namespace))),
 		httpClient:  httpClient,
 	}
 
+	switch runtimeSchema.Settings.ServerSelection {
+	case rest.ServerSelectionFastest:
+		settings.serverSelection = rest.ServerSelectionFastest
+		settings.latencies = newServerLatencyTracker()
+	case rest.ServerSelectionRoundRobin, rest.ServerSelectionWeighted:
+		settings.serverSelection = runtimeSchema.Settings.ServerSelection
+		settings.balancer = newServerBalancer(runtimeSchema.Settings.ServerSelection)
+	}
+
 	if len(runtimeSchema.Settings.ArgumentPresets) > 0 {
 		argumentPresets, err := argument.NewArgumentPresets(ndcSchema, runtimeSchema.Settings.ArgumentPresets, true)
 		if err != nil {
@@ -73,6 +117,14 @@ func (um *UpstreamManager) Register(ctx context.Context, runtimeSchema *configur
 		settings.argumentPresets = argumentPresets
 	}
 
+	if len(runtimeSchema.Settings.Encryption) > 0 {
+		encryptor, err := NewFieldEncryptor(ctx, runtimeSchema.Settings.Encryption)
+		if err != nil {
+			return fmt.Errorf("%s: %w", namespace, err)
+		}
+		settings.encryptor = encryptor
+	}
+
 	for i, server := range runtimeSchema.Settings.Servers {
 		serverID := server.ID
 		if serverID == "" {
@@ -100,11 +152,33 @@ func (um *UpstreamManager) Register(ctx context.Context, runtimeSchema *configur
 			}
 		}
 
+		serverClient, err = newHTTPClientDialer(serverClient, server)
+		if err != nil {
+			return fmt.Errorf("%s.server[%s]: %w", namespace, serverID, err)
+		}
+
+		serverClient, err = newHTTPClientProxy(serverClient, runtimeSchema.Settings.Proxy, server.Proxy)
+		if err != nil {
+			return fmt.Errorf("%s.server[%s].proxy: %w", namespace, serverID, err)
+		}
+
+		// each server has a dedicated transport if connection settings exist
+		serverClient, err = newHTTPClientConnection(serverClient, runtimeSchema.Settings.Connection, server.Connection)
+		if err != nil {
+			return fmt.Errorf("%s.server[%s].connection: %w", namespace, serverID, err)
+		}
+
+		isDefault, err := server.IsDefault()
+		if err != nil {
+			logger.Error(fmt.Sprintf("failed to evaluate the default setting of server %s:%s, %s", namespace, serverID, err))
+		}
+
 		newServer := Server{
 			URL:         serverURL,
+			Default:     isDefault,
 			Headers:     um.getHeadersFromEnv(logger, namespace, server.Headers),
 			Security:    server.Security,
-			Credentials: um.registerSecurityCredentials(ctx, serverClient, server.SecuritySchemes, logger.With(slog.String("namespace", namespace), slog.String("server_id", serverID))),
+			Credentials: um.registerSecurityCredentials(ctx, serverClient, namespace, server.SecuritySchemes, logger.With(slog.String("namespace", namespace), slog.String("server_id", serverID))),
 			HTTPClient:  serverClient,
 		}
 
@@ -117,13 +191,116 @@ func (um *UpstreamManager) Register(ctx context.Context, runtimeSchema *configur
 		}
 
 		settings.servers[serverID] = newServer
+		settings.serverIDs = append(settings.serverIDs, serverID)
+		if settings.balancer != nil {
+			settings.balancer.SetWeight(serverID, server.GetWeight())
+		}
+
+		if server.HealthCheck != nil {
+			if settings.healthChecks == nil {
+				settings.healthChecks = make(map[string]*serverHealthCheck)
+			}
+			settings.healthChecks[serverID] = newServerHealthCheck(server.HealthCheck)
+		}
 	}
 
+	um.upstreamsLock.Lock()
 	um.upstreams[namespace] = settings
+	um.upstreamsLock.Unlock()
+
+	return nil
+}
+
+// RefreshCredentials rebuilds credentials and headers of the upstream and its servers from the settings, e.g. after secrets are rotated.
+// Other settings, such as server URLs and TLS, are kept.
+func (um *UpstreamManager) RefreshCredentials(ctx context.Context, namespace string, settings *rest.NDCHttpSettings) error {
+	current, ok := um.getUpstream(namespace)
+	if !ok {
+		return fmt.Errorf("upstream with namespace %s does not exist", namespace)
+	}
+
+	logger := connector.GetLogger(ctx)
+	updated := current
+	updated.headers = um.getHeadersFromEnv(logger, namespace, settings.Headers)
+	updated.credentials = um.registerSecurityCredentials(ctx, current.httpClient, namespace, settings.SecuritySchemes, logger.With(slog.String("namespace", namespace)))
+	updated.servers = make(map[string]Server, len(current.servers))
+	for serverID, server := range current.servers {
+		updated.servers[serverID] = server
+	}
+
+	for i, serverSetting := range settings.Servers {
+		serverID := serverSetting.ID
+		if serverID == "" {
+			serverID = strconv.Itoa(i)
+		}
+
+		server, ok := updated.servers[serverID]
+		if !ok {
+			continue
+		}
+
+		server.Headers = um.getHeadersFromEnv(logger, namespace, serverSetting.Headers)
+		server.Credentials = um.registerSecurityCredentials(ctx, server.HTTPClient, namespace, serverSetting.SecuritySchemes, logger.With(slog.String("namespace", namespace), slog.String("server_id", serverID)))
+		updated.servers[serverID] = server
+	}
+
+	um.upstreamsLock.Lock()
+	um.upstreams[namespace] = updated
+	um.upstreamsLock.Unlock()
 
 	return nil
 }
 
+// get settings of the upstream. Settings are replaced as a whole when credentials are refreshed.
+func (um *UpstreamManager) getUpstream(namespace string) (UpstreamSetting, bool) {
+	um.upstreamsLock.RLock()
+	defer um.upstreamsLock.RUnlock()
+
+	upstream, ok := um.upstreams[namespace]
+
+	return upstream, ok
+}
+
+// DecryptResponse decrypts encrypted fields in the response of the operation.
+func (um *UpstreamManager) DecryptResponse(namespace string, operationName string, result any) (any, error) {
+	upstream, ok := um.getUpstream(namespace)
+	if !ok || upstream.encryptor == nil {
+		return result, nil
+	}
+
+	return upstream.encryptor.DecryptResponse(operationName, result)
+}
+
+// SetMetrics sets metric instruments to record upstream requests.
+func (um *UpstreamManager) SetMetrics(metrics *UpstreamMetrics) {
+	um.metrics = metrics
+}
+
+// SetTokenStore sets the store to share OAuth2 tokens between connector replicas. It must be called before registering upstreams.
+func (um *UpstreamManager) SetTokenStore(store security.TokenStore) {
+	um.tokenStore = store
+}
+
+// SetRateLimiter sets the state store of rate limiters, e.g. to share the rate limit across connector replicas.
+func (um *UpstreamManager) SetRateLimiter(limiter RateLimiter) {
+	um.rateLimiter = limiter
+}
+
+// SetForwardHeadersMapper sets mapping rules of forwarded request and response headers.
+func (um *UpstreamManager) SetForwardHeadersMapper(mapper *configuration.ForwardHeadersMapper) {
+	um.headersMapper = mapper
+}
+
+// SetIdempotencyKeyInjector sets the injector of idempotency keys into procedure requests.
+func (um *UpstreamManager) SetIdempotencyKeyInjector(injector *IdempotencyKeyInjector) {
+	um.idempotency = injector
+}
+
+// SetResponseDecoders sets custom decoders of response bodies.
+func (um *UpstreamManager) SetResponseDecoders(decoders *contenttype.ResponseDecoderRegistry) {
+	um.decoders = decoders
+}
+
 // CreateHTTPClient create an HTTP client with requests.
 func (um *UpstreamManager) CreateHTTPClient(requests *RequestBuilderResults) *HTTPClient {
 	return &HTTPClient{
@@ -133,7 +310,8 @@ func (um *UpstreamManager) CreateHTTPClient(requests *RequestBuilderResults) *HT
 }
 
 // ExecuteRequest executes a request to the upstream server.
-func (um *UpstreamManager) ExecuteRequest(ctx context.Context, request *RetryableRequest, namespace string) (*http.Response, context.CancelFunc, error) {
+// The duration of the request is recorded with metric attributes.
+func (um *UpstreamManager) ExecuteRequest(ctx context.Context, request *RetryableRequest, namespace string, metricAttrs ...attribute.KeyValue) (*http.Response, context.CancelFunc, error) {
 	req, cancel, err := request.CreateRequest(ctx)
 	if err != nil {
 		return nil, nil, err
@@ -143,24 +321,82 @@ func (um *UpstreamManager) ExecuteRequest(ctx context.Context, request *Retryabl
 	if err != nil {
 		cancel()
 
-		return nil, nil, err
+		return nil, nil, &UpstreamError{
+			Category: ErrorCategoryAuth,
+			Message:  err.Error(),
+			Cause:    err,
+		}
+	}
+
+	if request.RawRequest.Response.Redirect {
+		httpClient = newNoRedirectClient(httpClient)
 	}
 
 	req.Header.Set(acceptEncodingHeader, um.compressors.AcceptEncoding())
 	req.Header.Set("User-Agent", "ndc-http/"+version.BuildVersion)
-	resp, err := httpClient.Do(req)
-	if err != nil {
+	if um.config.ReplayProtection != nil && um.config.ReplayProtection.NonceHeader != "" {
+		req.Header.Set(um.config.ReplayProtection.NonceHeader, uuid.NewString())
+	}
+
+	if _, err := um.waitRateLimit(req.Context(), request); err != nil {
 		cancel()
 
 		return nil, nil, err
 	}
 
+	if um.scheduler != nil {
+		release, err := um.scheduler.Acquire(req.Context(), namespace)
+		if err != nil {
+			cancel()
+
+			return nil, nil, err
+		}
+
+		// the slot is held until the response body is consumed and the request is canceled
+		cancelRequest := cancel
+		cancel = func() {
+			cancelRequest()
+			release()
+		}
+	}
+
+	um.cookieJar.inject(req, request, namespace)
+	start := time.Now()
+	var resp *http.Response
+	send := func() (*http.Response, error) {
+		if request.RawRequest.Protocol == rest.ProtocolGRPC && request.RawRequest.GRPC != nil {
+			return um.grpcClients.Invoke(req, request.RawRequest.GRPC)
+		}
+
+		return httpClient.Do(req)
+	}
+
+	if um.recorder != nil {
+		resp, err = um.recorder.Do(req, request.Body, namespace, send)
+	} else {
+		resp, err = send()
+	}
+	duration := time.Since(start)
+	um.metrics.RecordRequestDuration(ctx, duration, req.Method, resp, err, metricAttrs...)
+	if settings, ok := um.getUpstream(namespace); ok {
+		settings.latencies.Record(request.ServerID, duration, err)
+		settings.balancer.Record(request.ServerID, err != nil || resp.StatusCode >= http.StatusInternalServerError)
+	}
+
+	if err != nil {
+		cancel()
+
+		return nil, nil, um.maskURLError(err, namespace, request.ServerID)
+	}
+
+	um.cookieJar.store(resp, request, namespace)
+
 	return resp, cancel, nil
 }
 
 func (um *UpstreamManager) evalRequestSettings(ctx context.Context, request *RetryableRequest, req *http.Request, namespace string) (*http.Client, error) {
 	httpClient := um.defaultClient
-	settings, ok := um.upstreams[namespace]
+	settings, ok := um.getUpstream(namespace)
 	if !ok {
 		return um.defaultClient, nil
 	}
@@ -243,10 +479,11 @@ func (um *UpstreamManager) evalSecuritySchemes(req *http.Request, securities res
 }
 
 // InjectMockCredential injects mock credential into the request for explain APIs.
-func (um *UpstreamManager) InjectMockRequestSettings(req *http.Request, namespace string, securities rest.AuthSecurities) {
-	settings, ok := um.upstreams[namespace]
+// Returns the name of the security scheme which is applied.
+func (um *UpstreamManager) InjectMockRequestSettings(req *http.Request, namespace string, securities rest.AuthSecurities) string {
+	settings, ok := um.getUpstream(namespace)
 	if !ok {
-		return
+		return ""
 	}
 
 	for key, header := range settings.headers {
@@ -258,7 +495,7 @@ func (um *UpstreamManager) InjectMockRequestSettings(req *http.Request, namespac
 	}
 
 	if securities.IsOptional() || len(settings.credentials) == 0 {
-		return
+		return ""
 	}
 
 	for _, security := range securities {
@@ -268,9 +505,112 @@ func (um *UpstreamManager) InjectMockRequestSettings(req *http.Request, namespac
 		}
 		hasAuth := sc.InjectMock(req)
 		if hasAuth {
-			return
+			return security.Name()
+		}
+	}
+
+	return ""
+}
+
+// GetArgumentPresetPaths returns JSON paths of argument presets of the upstream and the server which are applied to the operation.
+func (um *UpstreamManager) GetArgumentPresetPaths(namespace string, serverID string, operationName string) []string {
+	settings, ok := um.getUpstream(namespace)
+	if !ok {
+		return nil
+	}
+
+	var results []string
+	if settings.argumentPresets != nil {
+		results = append(results, settings.argumentPresets.Paths(operationName)...)
+	}
+
+	if server, ok := settings.servers[serverID]; ok && server.ArgumentPresets != nil {
+		results = append(results, server.ArgumentPresets.Paths(operationName)...)
+	}
+
+	return results
+}
+
+// CredentialFingerprint returns the hash of API keys of the upstream.
+// The fingerprint is a part of the response cache key so cached responses aren't shared if credentials are changed.
+func (um *UpstreamManager) CredentialFingerprint(namespace string) string {
+	credentials := um.getAPIKeyCredentials(namespace, "")
+	if len(credentials) == 0 {
+		return ""
+	}
+
+	hash := sha256.New()
+	for _, key := range utils.GetSortedKeys(credentials) {
+		cred := credentials[key]
+		_, _ = fmt.Fprintf(hash, "%s:%s:%s:%s\n", key, cred.In, cred.Name, cred.Value)
+	}
+
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// maskURLError masks API keys in the query string of the failed request URL.
+func (um *UpstreamManager) maskURLError(err error, namespace string, serverID string) error {
+	var urlErr *url.Error
+	if !errors.As(err, &urlErr) {
+		return err
+	}
+
+	endpoint, parseErr := url.Parse(urlErr.URL)
+	if parseErr != nil {
+		return err
+	}
+
+	for _, cred := range um.getAPIKeyCredentials(namespace, serverID) {
+		endpoint = cred.MaskURL(endpoint)
+	}
+
+	urlErr.URL = endpoint.String()
+
+	return err
+}
+
+// getAPIKeyCredentials returns API key credentials of the upstream and its servers.
+// Credentials of all servers are returned if the server ID is empty.
+func (um *UpstreamManager) getAPIKeyCredentials(namespace string, serverID string) map[string]*security.ApiKeyCredential {
+	settings, ok := um.getUpstream(namespace)
+	if !ok {
+		return nil
+	}
+
+	results := make(map[string]*security.ApiKeyCredential)
+	addCredentials := func(prefix string, credentials map[string]security.Credential) {
+		for key, cred := range credentials {
+			if apiKey, ok := cred.(*security.ApiKeyCredential); ok && apiKey.Value != "" {
+				results[prefix+key] = apiKey
+			}
 		}
 	}
+
+	addCredentials("", settings.credentials)
+	for id, server := range settings.servers {
+		if serverID == "" || serverID == id {
+			addCredentials(id+"/", server.Credentials)
+		}
+	}
+
+	return results
+}
+
+// newRotationHandler creates the handler which logs and counts requests retried with the secondary credential of the security scheme.
+// Metrics are evaluated lazily because instruments are created after upstreams are registered.
+func (um *UpstreamManager) newRotationHandler(namespace string, scheme string, logger *slog.Logger) security.RotationHandler {
+	return func(ctx context.Context, accepted bool) {
+		logger.Warn(
+			"the primary credential was rejected, the request was retried with the secondary credential. Rotate the primary credential",
+			slog.String("scheme", scheme),
+			slog.Bool("secondary_accepted", accepted),
+		)
+
+		um.metrics.RecordCredentialRotation(ctx, accepted,
+			attribute.String("db.namespace", namespace),
+			attribute.String("ndc_http.security.scheme", scheme),
+		)
+	}
 }
 
 func (um *UpstreamManager) getHeadersFromEnv(logger *slog.Logger, namespace string, headers map[string]utils.EnvString) map[string]string {
@@ -287,11 +627,11 @@ func (um *UpstreamManager) getHeadersFromEnv(logger *slog.Logger, namespace stri
 	return results
 }
 
-func (um *UpstreamManager) registerSecurityCredentials(ctx context.Context, httpClient *http.Client, securitySchemes map[string]rest.SecurityScheme, logger *slog.Logger) map[string]security.Credential {
+func (um *UpstreamManager) registerSecurityCredentials(ctx context.Context, httpClient *http.Client, namespace string, securitySchemes map[string]rest.SecurityScheme, logger *slog.Logger) map[string]security.Credential {
 	credentials := make(map[string]security.Credential)
 
 	for key, ss := range securitySchemes {
-		cred, headerForwardRequired, err := security.NewCredential(ctx, httpClient, ss)
+		cred, headerForwardRequired, err := security.NewCredential(ctx, httpClient, ss, um.tokenStore)
 		if err != nil {
 			// Relax the error to allow schema introspection without environment variables setting.
 			// Moreover, because there are many security schemes the user may use one of them.
@@ -303,6 +643,10 @@ func (um *UpstreamManager) registerSecurityCredentials(ctx context.Context, http
 			continue
 		}
 
+		if rc, ok := cred.(security.RotatableCredential); ok {
+			rc.SetRotationHandler(um.newRotationHandler(namespace, key, logger))
+		}
+
 		credentials[key] = cred
 		if headerForwardRequired && (!um.config.ForwardHeaders.Enabled || um.config.ForwardHeaders.ArgumentField == nil || *um.config.ForwardHeaders.ArgumentField == "") {
 			logger.Warn("the security scheme needs header forwarding enabled with argumentField set", slog.String("scheme", key))
@@ -1,6 +1,7 @@
 package internal
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -8,16 +9,34 @@ import (
 	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
 	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
 	"github.com/hasura/ndc-sdk-go/schema"
-	"github.com/hasura/ndc-sdk-go/utils"
 )
 
 // RequestBuilderResults hold the result of built requests.
 type RequestBuilderResults struct {
-	Requests  []*RetryableRequest
-	Operation *rest.OperationInfo
-	Schema    *configuration.NDCHttpRuntimeSchema
+	Requests      []*RetryableRequest
+	OperationName string
+	Operation     *rest.OperationInfo
+	Schema        *configuration.NDCHttpRuntimeSchema
 
 	*HTTPOptions
+
+	pagination *paginationState
+}
+
+// IsCollection checks if the operation is executed as a collection.
+func (rbr *RequestBuilderResults) IsCollection() bool {
+	return rbr.Operation != nil && rbr.Operation.Collection
+}
+
+// IsFunction checks if the operation is a function.
+func (rbr *RequestBuilderResults) IsFunction() bool {
+	if rbr.Schema == nil || rbr.Schema.NDCHttpSchema == nil {
+		return false
+	}
+
+	_, ok := rbr.Schema.Functions[rbr.OperationName]
+
+	return ok
 }
 
 func (um *UpstreamManager) BuildRequests(runtimeSchema *configuration.NDCHttpRuntimeSchema, operationName string, operation *rest.OperationInfo, rawArgs map[string]any) (*RequestBuilderResults, error) {
@@ -29,7 +48,13 @@ func (um *UpstreamManager) BuildRequests(runtimeSchema *configuration.NDCHttpRun
 		})
 	}
 
-	upstream, ok := um.upstreams[runtimeSchema.Name]
+	if httpOptions.DryRun && runtimeSchema.GetProcedure(operationName) == nil {
+		return nil, schema.UnprocessableContentError("invalid http options", map[string]any{
+			"cause": "dryRun is only supported in procedures",
+		})
+	}
+
+	upstream, ok := um.getUpstream(runtimeSchema.Name)
 	if !ok {
 		return nil, schema.InternalServerError(fmt.Sprintf("upstream with namespace %s does not exist", runtimeSchema.Name), nil)
 	}
@@ -48,24 +73,45 @@ func (um *UpstreamManager) BuildRequests(runtimeSchema *configuration.NDCHttpRun
 	if upstream.argumentPresets != nil {
 		rawArgs, err = upstream.argumentPresets.Apply(operationName, rawArgs, headers)
 		if err != nil {
+			return nil, schema.UnprocessableContentError(err.Error(), nil)
+		}
+	}
+
+	cookieSession := um.cookieJar.GetSession(headers)
+
+	// presets are evaluated with incoming header names, then forwarded headers are renamed for upstream services
+	headers = um.headersMapper.MapRequestHeaders(headers)
+
+	if um.config.ValidateArguments {
+		if err := ValidateArguments(runtimeSchema.NDCHttpSchema, operation, rawArgs); err != nil {
 			return nil, err
 		}
 	}
 
+	// templates of request transformations are evaluated with unencrypted arguments
+	transformArgs := rawArgs
 	results := &RequestBuilderResults{
-		Operation:   operation,
-		Schema:      runtimeSchema,
-		HTTPOptions: httpOptions,
+		OperationName: operationName,
+		Operation:     operation,
+		Schema:        runtimeSchema,
+		HTTPOptions:   httpOptions,
 	}
 	results.HTTPOptions.Concurrency = um.config.Concurrency.HTTP
 
 	if strings.HasPrefix(operation.Request.URL, "http") {
+		rawArgs, err = upstream.encryptor.EncryptArguments(operationName, rawArgs)
+		if err != nil {
+			return nil, err
+		}
+
 		// 4. build the request
-		req, err := NewRequestBuilder(runtimeSchema.NDCHttpSchema, operation, rawArgs, runtimeSchema.Runtime).Build()
+		req, err := NewRequestBuilder(runtimeSchema.NDCHttpSchema, operation, rawArgs, runtimeSchema.GetOperationRuntime(operationName)).Build()
 		if err != nil {
 			return nil, err
 		}
 		req.Namespace = runtimeSchema.Name
+		req.CookieSession = cookieSession
+		req.URL.Path = runtimeSchema.PathRewrite.Rewrite(req.URL.Path)
 
 		if err := evalForwardedHeaders(req, headers); err != nil {
 			return nil, schema.UnprocessableContentError("invalid forwarded headers", map[string]any{
@@ -74,23 +120,33 @@ func (um *UpstreamManager) BuildRequests(runtimeSchema *configuration.NDCHttpRun
 		}
 
 		results.Requests = []*RetryableRequest{req}
+		um.idempotency.Inject(runtimeSchema, operationName, results.Requests)
 
-		return results, nil
+		return um.transformRequests(results, transformArgs)
 	}
 
-	if !httpOptions.Distributed || len(upstream.servers) == 1 {
+	if (!httpOptions.Distributed || len(upstream.servers) == 1) && len(httpOptions.CompareServers) == 0 {
 		req, err := upstream.buildRequest(runtimeSchema, operationName, operation, rawArgs, headers, httpOptions.Servers)
 		if err != nil {
 			return nil, err
 		}
+		req.CookieSession = cookieSession
 		results.Requests = []*RetryableRequest{req}
+		um.idempotency.Inject(runtimeSchema, operationName, results.Requests)
 
-		return results, nil
+		return um.transformRequests(results, transformArgs)
 	}
 
 	serverIDs := httpOptions.Servers
-	if len(serverIDs) == 0 {
-		serverIDs = utils.GetKeys(upstream.servers)
+	if len(httpOptions.CompareServers) > 0 {
+		serverIDs = httpOptions.CompareServers
+	} else if len(serverIDs) == 0 {
+		serverIDs = upstream.getServerIDs()
+	}
+
+	// compared servers are always requested because the comparison needs responses of both servers
+	if len(httpOptions.CompareServers) == 0 {
+		serverIDs = upstream.filterHealthyServers(serverIDs)
 	}
 
 	for _, serverID := range serverIDs {
@@ -98,10 +154,12 @@ func (um *UpstreamManager) BuildRequests(runtimeSchema *configuration.NDCHttpRun
 		if err != nil {
 			return nil, err
 		}
+		req.CookieSession = cookieSession
 		results.Requests = append(results.Requests, req)
 	}
+	um.idempotency.Inject(runtimeSchema, operationName, results.Requests)
 
-	return results, nil
+	return um.transformRequests(results, transformArgs)
 }
 
 func (um *UpstreamManager) parseHTTPOptionsFromArguments(argumentsInfo map[string]rest.ArgumentInfo, rawArgs map[string]any) (*HTTPOptions, error) {
@@ -119,6 +177,30 @@ func (um *UpstreamManager) parseHTTPOptionsFromArguments(argumentsInfo map[strin
 	httpOptionsNamedType := schema.GetUnderlyingNamedType(argInfo.Type)
 	result.Distributed = httpOptionsNamedType != nil && httpOptionsNamedType.Name == rest.HTTPDistributedOptionsObjectName
 
+	if result.DryRun && !um.config.DryRun {
+		return nil, errors.New("dryRun is disabled in the connector configuration")
+	}
+
+	if len(result.CompareServers) > 0 {
+		if !result.Distributed {
+			return nil, errors.New("compareServers is only supported in distributed operations")
+		}
+
+		if len(result.CompareServers) != 2 || result.CompareServers[0] == result.CompareServers[1] {
+			return nil, fmt.Errorf("compareServers requires two different server IDs, got %v", result.CompareServers)
+		}
+	}
+
+	if result.Strategy != "" && result.Strategy != rest.DistributedExecutionAll {
+		if !result.Distributed {
+			return nil, errors.New("strategy is only supported in distributed operations")
+		}
+
+		if len(result.CompareServers) > 0 {
+			return nil, fmt.Errorf("compareServers can't be used with the %s strategy", result.Strategy)
+		}
+	}
+
 	return &result, nil
 }
 
@@ -13,6 +13,7 @@ import (
 	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
 	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
 	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
 )
 
 // Server contains server settings.
@@ -23,16 +24,24 @@ type Server struct {
 	ArgumentPresets *argument.ArgumentPresets
 	Security        rest.AuthSecurities
 	HTTPClient      *http.Client
+	// The server is the preferred target of single executions.
+	Default bool
 }
 
 // UpstreamSetting represents a setting for upstream servers.
 type UpstreamSetting struct {
 	httpClient      *http.Client
 	servers         map[string]Server
+	serverIDs       []string
 	headers         map[string]string
 	security        rest.AuthSecurities
 	credentials     map[string]security.Credential
 	argumentPresets *argument.ArgumentPresets
+	serverSelection rest.ServerSelectionStrategy
+	latencies       *serverLatencyTracker
+	balancer        *serverBalancer
+	healthChecks    map[string]*serverHealthCheck
+	encryptor       *FieldEncryptor
 }
 
 func (us *UpstreamSetting) buildRequest(runtimeSchema *configuration.NDCHttpRuntimeSchema, operationName string, operation *rest.OperationInfo, arguments map[string]any, headers map[string]string, servers []string) (*RetryableRequest, error) {
@@ -45,11 +54,16 @@ func (us *UpstreamSetting) buildRequest(runtimeSchema *configuration.NDCHttpRunt
 	if server.ArgumentPresets != nil {
 		arguments, err = server.ArgumentPresets.Apply(operationName, arguments, headers)
 		if err != nil {
-			return nil, err
+			return nil, schema.UnprocessableContentError(err.Error(), nil)
 		}
 	}
 
-	req, err := NewRequestBuilder(runtimeSchema.NDCHttpSchema, operation, arguments, runtimeSchema.Runtime).Build()
+	arguments, err = us.encryptor.EncryptArguments(operationName, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := NewRequestBuilder(runtimeSchema.NDCHttpSchema, operation, arguments, runtimeSchema.GetOperationRuntime(operationName)).Build()
 	if err != nil {
 		return nil, err
 	}
@@ -63,36 +77,85 @@ func (us *UpstreamSetting) buildRequest(runtimeSchema *configuration.NDCHttpRunt
 
 	req.URL.Scheme = baseURL.Scheme
 	req.URL.Host = baseURL.Host
-	req.URL.Path = path.Join(baseURL.Path, req.URL.Path)
+	req.URL.Path = runtimeSchema.PathRewrite.Rewrite(path.Join(baseURL.Path, req.URL.Path))
 	req.ServerID = serverID
 
 	return req, nil
 }
 
+// get IDs of registered servers in the order of settings, which is the priority order of failover executions.
+func (us *UpstreamSetting) getServerIDs() []string {
+	if len(us.serverIDs) != len(us.servers) {
+		return utils.GetSortedKeys(us.servers)
+	}
+
+	return us.serverIDs
+}
+
 func (us *UpstreamSetting) getBaseURLFromServers(namespace string, serverIDs []string) (*url.URL, string, error) {
-	var results []*url.URL
 	var selectedServerIDs []string
-	for key, server := range us.servers {
+	var defaultServerIDs []string
+	// servers are iterated in the order of settings so the round-robin selection is stable
+	for _, key := range us.getServerIDs() {
 		if len(serverIDs) > 0 && !slices.Contains(serverIDs, key) {
 			continue
 		}
 
-		hostPtr := server.URL
-		results = append(results, hostPtr)
 		selectedServerIDs = append(selectedServerIDs, key)
+		if us.servers[key].Default {
+			defaultServerIDs = append(defaultServerIDs, key)
+		}
+	}
+
+	// prefer default servers if the client doesn't request specific servers
+	if len(serverIDs) == 0 && len(defaultServerIDs) > 0 {
+		selectedServerIDs = defaultServerIDs
 	}
 
-	switch len(results) {
+	selectedServerIDs = us.filterHealthyServers(selectedServerIDs)
+
+	switch len(selectedServerIDs) {
 	case 0:
 		return nil, "", fmt.Errorf("requested servers %v in the upstream with namespace %s do not exist", serverIDs, namespace)
 	case 1:
-		result := results[0]
-
-		return result, selectedServerIDs[0], nil
+		return us.servers[selectedServerIDs[0]].URL, selectedServerIDs[0], nil
 	default:
-		index := rand.IntN(len(results) - 1)
-		host := results[index]
+		var index int
+		switch {
+		case us.serverSelection == rest.ServerSelectionFastest && us.latencies != nil:
+			index = us.latencies.Select(selectedServerIDs)
+		case us.balancer != nil:
+			index = us.balancer.Select(selectedServerIDs)
+		default:
+			index = rand.IntN(len(selectedServerIDs))
+		}
+		serverID := selectedServerIDs[index]
+
+		return us.servers[serverID].URL, serverID, nil
+	}
+}
+
+func (us *UpstreamSetting) isServerHealthy(serverID string) bool {
+	return us.healthChecks[serverID].IsHealthy()
+}
 
-		return host, selectedServerIDs[index], nil
+// exclude servers which failed active health checks. All servers are returned if every server is unhealthy,
+// so requests still reach the upstream and fail with the real error.
+func (us *UpstreamSetting) filterHealthyServers(serverIDs []string) []string {
+	if len(us.healthChecks) == 0 || len(serverIDs) < 2 {
+		return serverIDs
 	}
+
+	results := make([]string, 0, len(serverIDs))
+	for _, serverID := range serverIDs {
+		if us.isServerHealthy(serverID) {
+			results = append(results, serverID)
+		}
+	}
+
+	if len(results) == 0 {
+		return serverIDs
+	}
+
+	return results
 }
@@ -0,0 +1,178 @@
+package internal
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestGetBaseURLFromServers(t *testing.T) {
+	setting := UpstreamSetting{
+		servers: map[string]Server{
+			"sandbox":    {URL: &url.URL{Scheme: "http", Host: "sandbox.local"}},
+			"production": {URL: &url.URL{Scheme: "http", Host: "production.local"}, Default: true},
+			"staging":    {URL: &url.URL{Scheme: "http", Host: "staging.local"}},
+		},
+	}
+
+	for range 10 {
+		baseURL, serverID, err := setting.getBaseURLFromServers("test", nil)
+		assert.NilError(t, err)
+		assert.Equal(t, "production", serverID)
+		assert.Equal(t, "production.local", baseURL.Host)
+	}
+
+	baseURL, serverID, err := setting.getBaseURLFromServers("test", []string{"sandbox"})
+	assert.NilError(t, err)
+	assert.Equal(t, "sandbox", serverID)
+	assert.Equal(t, "sandbox.local", baseURL.Host)
+
+	// all servers can be selected if there is no default server
+	delete(setting.servers, "production")
+	selected := map[string]bool{}
+	for range 100 {
+		_, serverID, err := setting.getBaseURLFromServers("test", nil)
+		assert.NilError(t, err)
+		selected[serverID] = true
+	}
+	assert.DeepEqual(t, map[string]bool{"sandbox": true, "staging": true}, selected)
+
+	_, _, err = setting.getBaseURLFromServers("test", []string{"unknown"})
+	assert.ErrorContains(t, err, "do not exist")
+}
+
+func TestGetBaseURLFromServersFastest(t *testing.T) {
+	setting := UpstreamSetting{
+		servers: map[string]Server{
+			"us": {URL: &url.URL{Scheme: "http", Host: "us.local"}},
+			"eu": {URL: &url.URL{Scheme: "http", Host: "eu.local"}},
+		},
+		serverSelection: rest.ServerSelectionFastest,
+		latencies:       newServerLatencyTracker(),
+	}
+
+	// servers without latency samples are explored first
+	setting.latencies.Record("us", 200*time.Millisecond, nil)
+	selected := map[string]int{}
+	for range 1000 {
+		_, serverID, err := setting.getBaseURLFromServers("test", nil)
+		assert.NilError(t, err)
+		selected[serverID]++
+	}
+	assert.Assert(t, selected["eu"] > 800, "%v", selected)
+
+	setting.latencies.Record("eu", 20*time.Millisecond, nil)
+	selected = map[string]int{}
+	for range 1000 {
+		_, serverID, err := setting.getBaseURLFromServers("test", nil)
+		assert.NilError(t, err)
+		selected[serverID]++
+	}
+	assert.Assert(t, selected["eu"] > 800, "%v", selected)
+	assert.Assert(t, selected["us"] > 0, "slower servers should be explored: %v", selected)
+
+	// failed requests are penalized
+	for range 10 {
+		setting.latencies.Record("eu", time.Millisecond, errors.New("connection refused"))
+	}
+	latency, ok := setting.latencies.Get("eu")
+	assert.Assert(t, ok)
+	assert.Assert(t, latency > time.Second, "%s", latency)
+
+	selected = map[string]int{}
+	for range 1000 {
+		_, serverID, err := setting.getBaseURLFromServers("test", nil)
+		assert.NilError(t, err)
+		selected[serverID]++
+	}
+	assert.Assert(t, selected["us"] > 800, "%v", selected)
+}
+
+func TestGetBaseURLFromServersRoundRobin(t *testing.T) {
+	setting := UpstreamSetting{
+		servers: map[string]Server{
+			"us": {URL: &url.URL{Scheme: "http", Host: "us.local"}},
+			"eu": {URL: &url.URL{Scheme: "http", Host: "eu.local"}},
+			"ap": {URL: &url.URL{Scheme: "http", Host: "ap.local"}},
+		},
+		serverIDs:       []string{"us", "eu", "ap"},
+		serverSelection: rest.ServerSelectionRoundRobin,
+		balancer:        newServerBalancer(rest.ServerSelectionRoundRobin),
+	}
+
+	var selected []string
+	for range 6 {
+		_, serverID, err := setting.getBaseURLFromServers("test", nil)
+		assert.NilError(t, err)
+		selected = append(selected, serverID)
+	}
+	assert.DeepEqual(t, []string{"us", "eu", "ap", "us", "eu", "ap"}, selected)
+
+	// unhealthy servers are skipped after consecutive failures
+	for range serverUnhealthyThreshold {
+		setting.balancer.Record("eu", true)
+	}
+
+	for range 4 {
+		_, serverID, err := setting.getBaseURLFromServers("test", nil)
+		assert.NilError(t, err)
+		assert.Assert(t, serverID != "eu")
+	}
+
+	// all servers are candidates if every server is unhealthy
+	for _, serverID := range []string{"us", "ap"} {
+		for range serverUnhealthyThreshold {
+			setting.balancer.Record(serverID, true)
+		}
+	}
+
+	selectedSet := map[string]bool{}
+	for range 3 {
+		_, serverID, err := setting.getBaseURLFromServers("test", nil)
+		assert.NilError(t, err)
+		selectedSet[serverID] = true
+	}
+	assert.DeepEqual(t, map[string]bool{"us": true, "eu": true, "ap": true}, selectedSet)
+
+	// a successful request recovers the server
+	setting.balancer.Record("us", false)
+	_, serverID, err := setting.getBaseURLFromServers("test", nil)
+	assert.NilError(t, err)
+	assert.Equal(t, "us", serverID)
+}
+
+func TestGetBaseURLFromServersWeighted(t *testing.T) {
+	setting := UpstreamSetting{
+		servers: map[string]Server{
+			"primary":   {URL: &url.URL{Scheme: "http", Host: "primary.local"}},
+			"secondary": {URL: &url.URL{Scheme: "http", Host: "secondary.local"}},
+			"drained":   {URL: &url.URL{Scheme: "http", Host: "drained.local"}},
+		},
+		serverIDs:       []string{"primary", "secondary", "drained"},
+		serverSelection: rest.ServerSelectionWeighted,
+		balancer:        newServerBalancer(rest.ServerSelectionWeighted),
+	}
+	setting.balancer.SetWeight("primary", 3)
+	setting.balancer.SetWeight("secondary", 1)
+	setting.balancer.SetWeight("drained", 0)
+
+	var selected []string
+	for range 8 {
+		_, serverID, err := setting.getBaseURLFromServers("test", nil)
+		assert.NilError(t, err)
+		selected = append(selected, serverID)
+	}
+	assert.DeepEqual(t, []string{
+		"primary", "primary", "secondary", "primary",
+		"primary", "primary", "secondary", "primary",
+	}, selected)
+
+	// servers with zero weight can still be requested explicitly
+	_, serverID, err := setting.getBaseURLFromServers("test", []string{"drained"})
+	assert.NilError(t, err)
+	assert.Equal(t, "drained", serverID)
+}
@@ -1,11 +1,15 @@
 package internal
 
 import (
+	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
 
+	"github.com/go-viper/mapstructure/v2"
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
 	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 )
@@ -53,6 +57,31 @@ func evalForwardedHeaders(req *RetryableRequest, headers map[string]string) erro
 	return nil
 }
 
+// get the header value from the forwarded headers argument, case-insensitive.
+func getForwardedHeader(config *configuration.Configuration, rawArgs map[string]any, name string) (string, error) {
+	if !config.ForwardHeaders.Enabled || config.ForwardHeaders.ArgumentField == nil || *config.ForwardHeaders.ArgumentField == "" {
+		return "", nil
+	}
+
+	rawHeaders, ok := rawArgs[*config.ForwardHeaders.ArgumentField]
+	if !ok || rawHeaders == nil {
+		return "", nil
+	}
+
+	headers := make(map[string]string)
+	if err := mapstructure.Decode(rawHeaders, &headers); err != nil {
+		return "", schema.UnprocessableContentError(fmt.Sprintf("arguments.%s: %s", *config.ForwardHeaders.ArgumentField, err), nil)
+	}
+
+	for key, value := range headers {
+		if strings.EqualFold(key, name) {
+			return value, nil
+		}
+	}
+
+	return "", nil
+}
+
 func cloneURL(input *url.URL) *url.URL {
 	return &url.URL{
 		Scheme:      input.Scheme,
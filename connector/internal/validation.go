@@ -0,0 +1,313 @@
+package internal
+
+import (
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// compiled patterns are cached because the same operations are validated in every request
+var validationPatterns sync.Map
+
+var uuidRegex = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// the maximum number of violations of the response body, so large arrays with invalid items don't flood errors
+const maxResponseValidationErrors = 100
+
+// ValidationError represents a constraint violation at the field path of arguments or the response body.
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+// ValidateArguments validates argument values against pattern, minLength, maxLength, minimum and maximum constraints of the type schema.
+// All violations are aggregated into a bad request error with field paths.
+func ValidateArguments(httpSchema *rest.NDCHttpSchema, operation *rest.OperationInfo, arguments map[string]any) error {
+	validator := &schemaValidator{
+		schema: httpSchema,
+	}
+
+	for _, key := range utils.GetSortedKeys(operation.Arguments) {
+		value, ok := arguments[key]
+		if !ok {
+			continue
+		}
+
+		argument := operation.Arguments[key]
+		var typeSchema *rest.TypeSchema
+		if argument.HTTP != nil {
+			typeSchema = argument.HTTP.Schema
+		}
+
+		validator.validate(key, argument.Type, typeSchema, value)
+	}
+
+	if len(validator.errors) == 0 {
+		return nil
+	}
+
+	return schema.BadRequestError("invalid arguments", map[string]any{
+		"errors": validator.errors,
+	})
+}
+
+// ValidateResponse validates the decoded response body against the result type and constraints of the type schema.
+// Besides constraints of arguments, types, required fields, enums and formats are checked. Returns violations of the response.
+// Missing fields are allowed if the response is partial, e.g. fields are selected by the upstream API.
+func ValidateResponse(httpSchema *rest.NDCHttpSchema, resultType schema.Type, value any, partial bool) []ValidationError {
+	validator := &schemaValidator{
+		schema:       httpSchema,
+		strict:       true,
+		allowMissing: partial,
+		maxErrors:    maxResponseValidationErrors,
+	}
+
+	validator.validate("", resultType, nil, value)
+
+	return validator.errors
+}
+
+type schemaValidator struct {
+	schema *rest.NDCHttpSchema
+	// validate types, required fields, enums and formats of values which are decoded from upstream responses
+	strict       bool
+	allowMissing bool
+	maxErrors    int
+	errors       []ValidationError
+}
+
+func (av *schemaValidator) validate(path string, fieldType schema.Type, typeSchema *rest.TypeSchema, value any) {
+	ty, err := fieldType.InterfaceT()
+	if err != nil {
+		return
+	}
+
+	if utils.IsNil(value) {
+		if _, ok := ty.(*schema.NullableType); !ok && av.strict {
+			av.addError(path, "value must not be null")
+		}
+
+		return
+	}
+
+	switch t := ty.(type) {
+	case *schema.NullableType:
+		av.validate(path, t.UnderlyingType, typeSchema, value)
+	case *schema.ArrayType:
+		reflectValue := reflect.ValueOf(value)
+		if reflectValue.Kind() != reflect.Slice && reflectValue.Kind() != reflect.Array {
+			if av.strict {
+				av.addError(path, fmt.Sprintf("expected an array, got %T", value))
+			}
+
+			return
+		}
+
+		var itemSchema *rest.TypeSchema
+		if typeSchema != nil {
+			itemSchema = typeSchema.Items
+		}
+
+		for i := range reflectValue.Len() {
+			av.validate(path+"["+strconv.Itoa(i)+"]", t.ElementType, itemSchema, reflectValue.Index(i).Interface())
+		}
+	case *schema.NamedType:
+		if objectType, ok := av.schema.ObjectTypes[t.Name]; ok {
+			object, ok := value.(map[string]any)
+			if !ok {
+				if av.strict {
+					av.addError(path, fmt.Sprintf("expected an object of %s, got %T", t.Name, value))
+				}
+
+				return
+			}
+
+			for _, key := range utils.GetSortedKeys(objectType.Fields) {
+				field := objectType.Fields[key]
+				fieldValue, ok := object[key]
+				if !ok {
+					if _, err := field.Type.AsNullable(); err != nil && av.strict && !av.allowMissing {
+						av.addError(joinValidationPath(path, key), "field is required")
+					}
+
+					continue
+				}
+
+				av.validate(joinValidationPath(path, key), field.Type, field.HTTP, fieldValue)
+			}
+
+			return
+		}
+
+		if av.strict {
+			if scalarType, ok := av.schema.ScalarTypes[t.Name]; ok && !av.validateScalarType(path, t.Name, scalarType, value) {
+				return
+			}
+		}
+
+		if typeSchema != nil {
+			av.validateScalar(path, typeSchema, value)
+		}
+	}
+}
+
+// validate the value against the representation of the scalar type. Returns false if the value type is invalid.
+func (av *schemaValidator) validateScalarType(path string, name string, scalarType schema.ScalarType, value any) bool {
+	representation, err := scalarType.Representation.InterfaceT()
+	if err != nil {
+		return true
+	}
+
+	var valid bool
+	kind := reflect.ValueOf(value).Kind()
+	switch r := representation.(type) {
+	case *schema.TypeRepresentationBoolean:
+		valid = kind == reflect.Bool
+	case *schema.TypeRepresentationInt8, *schema.TypeRepresentationInt16, *schema.TypeRepresentationInt32,
+		*schema.TypeRepresentationFloat32, *schema.TypeRepresentationFloat64:
+		_, err := utils.DecodeFloat[float64](value)
+		valid = err == nil && kind != reflect.String
+	case *schema.TypeRepresentationEnum:
+		str, ok := value.(string)
+		if !ok {
+			break
+		}
+
+		if !slices.Contains(r.OneOf, str) {
+			av.addError(path, fmt.Sprintf("value must be one of %v", r.OneOf))
+		}
+
+		return true
+	case *schema.TypeRepresentationString, *schema.TypeRepresentationDate, *schema.TypeRepresentationTimestamp,
+		*schema.TypeRepresentationTimestampTZ, *schema.TypeRepresentationUUID:
+		valid = kind == reflect.String
+	default:
+		return true
+	}
+
+	if !valid {
+		av.addError(path, fmt.Sprintf("expected a value of %s, got %T", name, value))
+	}
+
+	return valid
+}
+
+func (av *schemaValidator) validateScalar(path string, typeSchema *rest.TypeSchema, value any) {
+	if str, ok := value.(string); ok {
+		length := int64(utf8.RuneCountInString(str))
+		if typeSchema.MinLength != nil && length < *typeSchema.MinLength {
+			av.addError(path, fmt.Sprintf("length must be greater than or equal to %d", *typeSchema.MinLength))
+		}
+
+		if typeSchema.MaxLength != nil && length > *typeSchema.MaxLength {
+			av.addError(path, fmt.Sprintf("length must be less than or equal to %d", *typeSchema.MaxLength))
+		}
+
+		if typeSchema.Pattern != "" {
+			if pattern := getValidationPattern(typeSchema.Pattern); pattern != nil && !pattern.MatchString(str) {
+				av.addError(path, "value does not match the pattern "+typeSchema.Pattern)
+			}
+		}
+
+		if av.strict && typeSchema.Format != "" && !isValidFormat(typeSchema.Format, str) {
+			av.addError(path, "value does not match the format "+typeSchema.Format)
+		}
+
+		return
+	}
+
+	if typeSchema.Minimum == nil && typeSchema.Maximum == nil {
+		return
+	}
+
+	number, err := utils.DecodeFloat[float64](value)
+	if err != nil {
+		return
+	}
+
+	if typeSchema.Minimum != nil && number < *typeSchema.Minimum {
+		av.addError(path, "value must be greater than or equal to "+strconv.FormatFloat(*typeSchema.Minimum, 'f', -1, 64))
+	}
+
+	if typeSchema.Maximum != nil && number > *typeSchema.Maximum {
+		av.addError(path, "value must be less than or equal to "+strconv.FormatFloat(*typeSchema.Maximum, 'f', -1, 64))
+	}
+}
+
+func (av *schemaValidator) addError(path string, message string) {
+	if av.maxErrors > 0 && len(av.errors) >= av.maxErrors {
+		return
+	}
+
+	av.errors = append(av.errors, ValidationError{
+		Path:    path,
+		Message: message,
+	})
+}
+
+// getValidationPattern gets the compiled pattern from the cache.
+// Returns nil if the pattern isn't a valid regular expression in Go, e.g. lookaround assertions of ECMA 262.
+func getValidationPattern(pattern string) *regexp.Regexp {
+	if cached, ok := validationPatterns.Load(pattern); ok {
+		return cached.(*regexp.Regexp)
+	}
+
+	// invalid patterns are cached as nil to skip compiling them again
+	compiled, err := regexp.Compile(pattern)
+	if err != nil {
+		compiled = nil
+	}
+
+	validationPatterns.Store(pattern, compiled)
+
+	return compiled
+}
+
+func joinValidationPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+
+	return path + "." + key
+}
+
+// check if the string value matches the format of the type schema. Unknown formats are valid.
+func isValidFormat(format string, value string) bool {
+	var err error
+	switch format {
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, value)
+	case "date":
+		_, err = time.Parse(time.DateOnly, value)
+	case "uuid":
+		return uuidRegex.MatchString(value)
+	case "email":
+		_, err = mail.ParseAddress(value)
+	case "ipv4":
+		var addr netip.Addr
+		addr, err = netip.ParseAddr(value)
+
+		return err == nil && addr.Is4()
+	case "ipv6":
+		var addr netip.Addr
+		addr, err = netip.ParseAddr(value)
+
+		return err == nil && addr.Is6()
+	case "uri":
+		_, err = url.ParseRequestURI(value)
+	}
+
+	return err == nil
+}
@@ -0,0 +1,159 @@
+package internal
+
+import (
+	"net/http"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateArguments(t *testing.T) {
+	httpSchema := rest.NewNDCHttpSchema()
+	httpSchema.ObjectTypes = map[string]rest.ObjectType{
+		"Pet": {
+			Fields: map[string]rest.ObjectField{
+				"name": {
+					ObjectField: schema.ObjectField{Type: schema.NewNamedType("String").Encode()},
+					HTTP: &rest.TypeSchema{
+						Type:      []string{"string"},
+						MinLength: utils.ToPtr[int64](2),
+						MaxLength: utils.ToPtr[int64](5),
+					},
+				},
+				"tags": {
+					ObjectField: schema.ObjectField{Type: schema.NewNullableType(schema.NewArrayType(schema.NewNamedType("String"))).Encode()},
+					HTTP: &rest.TypeSchema{
+						Type: []string{"array"},
+						Items: &rest.TypeSchema{
+							Type:    []string{"string"},
+							Pattern: "^[a-z]+$",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	operation := &rest.OperationInfo{
+		Arguments: map[string]rest.ArgumentInfo{
+			"limit": {
+				ArgumentInfo: schema.ArgumentInfo{Type: schema.NewNullableType(schema.NewNamedType("Int32")).Encode()},
+				HTTP: &rest.RequestParameter{
+					Schema: &rest.TypeSchema{
+						Type:    []string{"integer"},
+						Minimum: utils.ToPtr(float64(1)),
+						Maximum: utils.ToPtr(float64(100)),
+					},
+				},
+			},
+			"body": {
+				ArgumentInfo: schema.ArgumentInfo{Type: schema.NewNamedType("Pet").Encode()},
+			},
+		},
+	}
+
+	assert.NilError(t, ValidateArguments(httpSchema, operation, map[string]any{
+		"limit": float64(10),
+		"body": map[string]any{
+			"name": "Dog",
+			"tags": []any{"cute"},
+		},
+	}))
+
+	assert.NilError(t, ValidateArguments(httpSchema, operation, map[string]any{
+		"limit": nil,
+		"body": map[string]any{
+			"name": "Cat",
+			"tags": nil,
+		},
+	}))
+
+	err := ValidateArguments(httpSchema, operation, map[string]any{
+		"limit": 0,
+		"body": map[string]any{
+			"name": "Doggie",
+			"tags": []string{"cute", "Good1"},
+		},
+	})
+
+	connectorErr, ok := err.(*schema.ConnectorError)
+	assert.Assert(t, ok)
+	assert.Equal(t, http.StatusBadRequest, connectorErr.StatusCode())
+	assert.DeepEqual(t, map[string]any{
+		"errors": []ValidationError{
+			{Path: "body.name", Message: "length must be less than or equal to 5"},
+			{Path: "body.tags[1]", Message: "value does not match the pattern ^[a-z]+$"},
+			{Path: "limit", Message: "value must be greater than or equal to 1"},
+		},
+	}, connectorErr.Details)
+}
+
+func TestValidateResponse(t *testing.T) {
+	httpSchema := rest.NewNDCHttpSchema()
+	httpSchema.ScalarTypes = schema.SchemaResponseScalarTypes{
+		"String":    {Representation: schema.NewTypeRepresentationString().Encode()},
+		"Int32":     {Representation: schema.NewTypeRepresentationInt32().Encode()},
+		"PetStatus": {Representation: schema.NewTypeRepresentationEnum([]string{"available", "sold"}).Encode()},
+	}
+	httpSchema.ObjectTypes = map[string]rest.ObjectType{
+		"Pet": {
+			Fields: map[string]rest.ObjectField{
+				"id": {
+					ObjectField: schema.ObjectField{Type: schema.NewNamedType("Int32").Encode()},
+					HTTP: &rest.TypeSchema{
+						Type:    []string{"integer"},
+						Minimum: utils.ToPtr(float64(1)),
+					},
+				},
+				"name": {
+					ObjectField: schema.ObjectField{Type: schema.NewNamedType("String").Encode()},
+					HTTP:        &rest.TypeSchema{Type: []string{"string"}},
+				},
+				"status": {
+					ObjectField: schema.ObjectField{Type: schema.NewNullableType(schema.NewNamedType("PetStatus")).Encode()},
+					HTTP:        &rest.TypeSchema{Type: []string{"string"}},
+				},
+				"createdAt": {
+					ObjectField: schema.ObjectField{Type: schema.NewNullableType(schema.NewNamedType("String")).Encode()},
+					HTTP: &rest.TypeSchema{
+						Type:   []string{"string"},
+						Format: "date-time",
+					},
+				},
+			},
+		},
+	}
+	resultType := schema.NewArrayType(schema.NewNamedType("Pet")).Encode()
+
+	assert.Equal(t, 0, len(ValidateResponse(httpSchema, resultType, []any{
+		map[string]any{"id": int64(1), "name": "Dog", "status": "available", "createdAt": "2024-01-01T00:00:00Z"},
+		map[string]any{"id": float64(2), "name": "Cat", "status": nil},
+	}, false)))
+
+	assert.DeepEqual(t, []ValidationError{
+		{Path: "[0].createdAt", Message: "value does not match the format date-time"},
+		{Path: "[0].id", Message: "value must be greater than or equal to 1"},
+		{Path: "[0].status", Message: "value must be one of [available sold]"},
+		{Path: "[1].id", Message: "expected a value of Int32, got string"},
+		{Path: "[1].name", Message: "field is required"},
+		{Path: "[2]", Message: "expected an object of Pet, got string"},
+		{Path: "[3]", Message: "value must not be null"},
+	}, ValidateResponse(httpSchema, resultType, []any{
+		map[string]any{"id": int64(0), "name": "Dog", "status": "unknown", "createdAt": "yesterday"},
+		map[string]any{"id": "2"},
+		"Fish",
+		nil,
+	}, false))
+
+	// missing fields are allowed if the upstream API selects fields
+	assert.Equal(t, 0, len(ValidateResponse(httpSchema, resultType, []any{
+		map[string]any{"name": "Dog"},
+	}, true)))
+
+	assert.DeepEqual(t, []ValidationError{
+		{Path: "", Message: "expected an array, got map[string]interface {}"},
+	}, ValidateResponse(httpSchema, resultType, map[string]any{}, false))
+}
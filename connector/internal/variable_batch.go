@@ -0,0 +1,250 @@
+package internal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/hasura/ndc-http/connector/internal/contenttype"
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+const defaultVariableBatchMaxSize = 100
+
+// VariableBatchExecutor executes the batch function with arguments.
+type VariableBatchExecutor func(ctx context.Context, functionName string, arguments map[string]any) (any, error)
+
+// VariableBatcher fetches rows of a query with many variable sets in batch upstream requests.
+// Values of the argument in variable sets are collected into the array argument of the batch function,
+// then items of the batch result are fanned out into results of variable sets by the key field.
+type VariableBatcher struct {
+	functions map[string]variableBatchFunction
+	executor  VariableBatchExecutor
+}
+
+type variableBatchFunction struct {
+	argument      string
+	batchFunction string
+	batchArgument string
+	keyField      string
+	maxSize       int
+	// the individual function returns an array of items rather than a single item
+	resultIsArray bool
+}
+
+type variableBatchGroup struct {
+	arguments map[string]any
+	keys      []string
+	values    []any
+}
+
+// NewVariableBatcher creates a new VariableBatcher instance. Returns nil if variable batching isn't configured.
+func NewVariableBatcher(config *configuration.Configuration, metadata MetadataCollection, executor VariableBatchExecutor) (*VariableBatcher, error) {
+	if config == nil || config.VariableBatching == nil || len(config.VariableBatching.Functions) == 0 {
+		return nil, nil
+	}
+
+	functions := make(map[string]variableBatchFunction)
+	for name, setting := range config.VariableBatching.Functions {
+		function := variableBatchFunction{
+			argument:      setting.Argument,
+			batchFunction: setting.BatchFunction,
+			batchArgument: setting.BatchArgument,
+			keyField:      setting.KeyField,
+			maxSize:       defaultVariableBatchMaxSize,
+		}
+
+		if function.batchFunction == "" {
+			function.batchFunction = name
+		}
+
+		if setting.MaxSize > 0 {
+			function.maxSize = int(setting.MaxSize)
+		}
+
+		operation, _, err := metadata.GetFunction(name)
+		if err != nil {
+			return nil, fmt.Errorf("variableBatching.functions.%s: %w", name, err)
+		}
+
+		if _, ok := operation.Arguments[function.argument]; !ok {
+			return nil, fmt.Errorf("variableBatching.functions.%s: argument %s does not exist", name, function.argument)
+		}
+
+		resultType, _, err := contenttype.UnwrapNullableType(operation.ResultType)
+		if err != nil {
+			return nil, fmt.Errorf("variableBatching.functions.%s: %w", name, err)
+		}
+
+		_, function.resultIsArray = resultType.(*schema.ArrayType)
+
+		batchOperation, _, err := metadata.GetFunction(function.batchFunction)
+		if err != nil {
+			return nil, fmt.Errorf("variableBatching.functions.%s.batchFunction: %w", name, err)
+		}
+
+		if _, ok := batchOperation.Arguments[function.batchArgument]; !ok {
+			return nil, fmt.Errorf("variableBatching.functions.%s.batchFunction: argument %s does not exist", name, function.batchArgument)
+		}
+
+		batchResultType, _, err := contenttype.UnwrapNullableType(batchOperation.ResultType)
+		if err != nil {
+			return nil, fmt.Errorf("variableBatching.functions.%s.batchFunction: %w", name, err)
+		}
+
+		if _, ok := batchResultType.(*schema.ArrayType); !ok {
+			return nil, fmt.Errorf("variableBatching.functions.%s.batchFunction: the result type of %s must be an array", name, function.batchFunction)
+		}
+
+		functions[name] = function
+	}
+
+	return &VariableBatcher{
+		functions: functions,
+		executor:  executor,
+	}, nil
+}
+
+// IsEnabled checks if variable sets of the function are batched.
+func (vb *VariableBatcher) IsEnabled(functionName string) bool {
+	if vb == nil {
+		return false
+	}
+
+	_, ok := vb.functions[functionName]
+
+	return ok
+}
+
+// Execute fetches results of variable sets in batch requests. Arguments are resolved from variable sets.
+// Results are returned in the same order of arguments.
+func (vb *VariableBatcher) Execute(ctx context.Context, functionName string, argumentsList []map[string]any) ([]any, error) {
+	function, ok := vb.functions[functionName]
+	if !ok {
+		return nil, schema.InternalServerError(fmt.Sprintf("variable batching of function %s isn't configured", functionName), nil)
+	}
+
+	groups, groupIndexes, err := function.groupArguments(argumentsList)
+	if err != nil {
+		return nil, err
+	}
+
+	itemsByKey := make([]map[string][]any, len(groups))
+	for i, group := range groups {
+		itemsByKey[i] = make(map[string][]any)
+		for start := 0; start < len(group.values); start += function.maxSize {
+			arguments := maps.Clone(group.arguments)
+			arguments[function.batchArgument] = group.values[start:min(start+function.maxSize, len(group.values))]
+
+			result, err := vb.executor(ctx, function.batchFunction, arguments)
+			if err != nil {
+				return nil, err
+			}
+
+			if err := function.collectItems(itemsByKey[i], result); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	results := make([]any, len(argumentsList))
+	for i, arguments := range argumentsList {
+		items := itemsByKey[groupIndexes[i]][getVariableBatchKey(arguments[function.argument])]
+		switch {
+		case function.resultIsArray:
+			if items == nil {
+				items = []any{}
+			}
+
+			results[i] = items
+		case len(items) > 0:
+			results[i] = items[0]
+		}
+	}
+
+	return results, nil
+}
+
+// group arguments by other arguments than the batched argument. Duplicated values are requested once.
+// Returns groups and the group index of each arguments.
+func (vbf variableBatchFunction) groupArguments(argumentsList []map[string]any) ([]variableBatchGroup, []int, error) {
+	var groups []variableBatchGroup
+	groupIndexes := make([]int, len(argumentsList))
+	groupKeys := make(map[string]int)
+
+	for i, arguments := range argumentsList {
+		value, ok := arguments[vbf.argument]
+		if !ok || value == nil {
+			return nil, nil, schema.UnprocessableContentError(fmt.Sprintf("argument %s is required", vbf.argument), nil)
+		}
+
+		otherArguments := maps.Clone(arguments)
+		delete(otherArguments, vbf.argument)
+		rawArgs, err := json.Marshal(otherArguments)
+		if err != nil {
+			return nil, nil, schema.UnprocessableContentError("failed to encode arguments", map[string]any{
+				"cause": err.Error(),
+			})
+		}
+
+		groupIndex, ok := groupKeys[string(rawArgs)]
+		if !ok {
+			groups = append(groups, variableBatchGroup{
+				arguments: otherArguments,
+			})
+			groupIndex = len(groups) - 1
+			groupKeys[string(rawArgs)] = groupIndex
+		}
+
+		groupIndexes[i] = groupIndex
+		group := &groups[groupIndex]
+		key := getVariableBatchKey(value)
+		if !slices.Contains(group.keys, key) {
+			group.keys = append(group.keys, key)
+			group.values = append(group.values, value)
+		}
+	}
+
+	return groups, groupIndexes, nil
+}
+
+func (vbf variableBatchFunction) collectItems(itemsByKey map[string][]any, result any) error {
+	if result == nil {
+		return nil
+	}
+
+	items, ok := result.([]any)
+	if !ok {
+		return schema.InternalServerError(fmt.Sprintf("the result of batch function %s must be an array, got: %T", vbf.batchFunction, result), nil)
+	}
+
+	for _, item := range items {
+		object, ok := item.(map[string]any)
+		if !ok {
+			return schema.InternalServerError(fmt.Sprintf("items of the result of batch function %s must be objects, got: %T", vbf.batchFunction, item), nil)
+		}
+
+		key := getVariableBatchKey(object[vbf.keyField])
+		itemsByKey[key] = append(itemsByKey[key], item)
+	}
+
+	return nil
+}
+
+// get the comparable key of the value. String and numeric values are equal if their text representations are equal,
+// e.g. id "1" in the path parameter matches id 1 in the response.
+func getVariableBatchKey(value any) string {
+	if str, ok := value.(string); ok {
+		return str
+	}
+
+	rawBytes, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(rawBytes)
+}
@@ -0,0 +1,151 @@
+package internal
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func newTestVariableBatchMetadata() MetadataCollection {
+	return MetadataCollection{
+		{
+			Name: "test",
+			NDCHttpSchema: &rest.NDCHttpSchema{
+				Functions: map[string]rest.OperationInfo{
+					"getPetById": {
+						Arguments: map[string]rest.ArgumentInfo{
+							"petId": {},
+							"store": {},
+						},
+						ResultType: schema.NewNullableType(schema.NewNamedType("Pet")).Encode(),
+					},
+					"findPetsByOwner": {
+						Arguments: map[string]rest.ArgumentInfo{
+							"ownerId": {},
+						},
+						ResultType: schema.NewArrayType(schema.NewNamedType("Pet")).Encode(),
+					},
+					"findPets": {
+						Arguments: map[string]rest.ArgumentInfo{
+							"ids":      {},
+							"ownerIds": {},
+							"store":    {},
+						},
+						ResultType: schema.NewArrayType(schema.NewNamedType("Pet")).Encode(),
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestVariableBatcher(t *testing.T) {
+	config := &configuration.Configuration{
+		VariableBatching: &configuration.VariableBatchingSettings{
+			Functions: map[string]configuration.VariableBatchFunctionSettings{
+				"getPetById": {
+					Argument:      "petId",
+					BatchFunction: "findPets",
+					BatchArgument: "ids",
+					KeyField:      "id",
+					MaxSize:       2,
+				},
+				"findPetsByOwner": {
+					Argument:      "ownerId",
+					BatchFunction: "findPets",
+					BatchArgument: "ownerIds",
+					KeyField:      "ownerId",
+				},
+			},
+		},
+	}
+
+	var calls []map[string]any
+	batcher, err := NewVariableBatcher(config, newTestVariableBatchMetadata(), func(ctx context.Context, functionName string, arguments map[string]any) (any, error) {
+		assert.Equal(t, "findPets", functionName)
+		calls = append(calls, arguments)
+
+		var results []any
+		if ids, ok := arguments["ids"].([]any); ok {
+			for _, id := range ids {
+				results = append(results, map[string]any{"id": id, "store": arguments["store"]})
+			}
+		}
+
+		if ownerIDs, ok := arguments["ownerIds"].([]any); ok {
+			for i, id := range ownerIDs {
+				results = append(results, map[string]any{"id": float64(i), "ownerId": id}, map[string]any{"id": float64(i + 10), "ownerId": id})
+			}
+		}
+
+		return results, nil
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, batcher.IsEnabled("getPetById"))
+	assert.Assert(t, !batcher.IsEnabled("findPets"))
+
+	t.Run("single_item", func(t *testing.T) {
+		calls = nil
+		results, err := batcher.Execute(context.TODO(), "getPetById", []map[string]any{
+			{"petId": float64(1), "store": "a"},
+			{"petId": "2", "store": "a"},
+			{"petId": float64(1), "store": "a"},
+			{"petId": float64(3), "store": "a"},
+			{"petId": float64(1), "store": "b"},
+		})
+		assert.NilError(t, err)
+
+		// variable sets are grouped by other arguments, deduplicated and split by the max size
+		assert.DeepEqual(t, []map[string]any{
+			{"ids": []any{float64(1), "2"}, "store": "a"},
+			{"ids": []any{float64(3)}, "store": "a"},
+			{"ids": []any{float64(1)}, "store": "b"},
+		}, calls)
+		assert.DeepEqual(t, []any{
+			map[string]any{"id": float64(1), "store": "a"},
+			map[string]any{"id": "2", "store": "a"},
+			map[string]any{"id": float64(1), "store": "a"},
+			map[string]any{"id": float64(3), "store": "a"},
+			map[string]any{"id": float64(1), "store": "b"},
+		}, results)
+	})
+
+	t.Run("array", func(t *testing.T) {
+		calls = nil
+		results, err := batcher.Execute(context.TODO(), "findPetsByOwner", []map[string]any{
+			{"ownerId": "x"},
+			{"ownerId": "y"},
+		})
+		assert.NilError(t, err)
+		assert.Equal(t, 1, len(calls))
+		assert.DeepEqual(t, []any{
+			[]any{map[string]any{"id": float64(0), "ownerId": "x"}, map[string]any{"id": float64(10), "ownerId": "x"}},
+			[]any{map[string]any{"id": float64(1), "ownerId": "y"}, map[string]any{"id": float64(11), "ownerId": "y"}},
+		}, results)
+	})
+
+	t.Run("missing_argument", func(t *testing.T) {
+		_, err := batcher.Execute(context.TODO(), "getPetById", []map[string]any{{"store": "a"}})
+		assert.ErrorContains(t, err, "argument petId is required")
+	})
+
+	t.Run("invalid_batch_function", func(t *testing.T) {
+		_, err := NewVariableBatcher(&configuration.Configuration{
+			VariableBatching: &configuration.VariableBatchingSettings{
+				Functions: map[string]configuration.VariableBatchFunctionSettings{
+					"findPets": {
+						Argument:      "ids",
+						BatchFunction: "getPetById",
+						BatchArgument: "petId",
+						KeyField:      "id",
+					},
+				},
+			},
+		}, newTestVariableBatchMetadata(), nil)
+		assert.ErrorContains(t, err, "the result type of getPetById must be an array")
+	})
+}
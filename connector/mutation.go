@@ -4,21 +4,31 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/hasura/ndc-http/connector/internal"
 	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
 	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
 	"go.opentelemetry.io/otel/codes"
 	"golang.org/x/sync/errgroup"
 )
 
 // Mutation executes a mutation.
 func (c *HTTPConnector) Mutation(ctx context.Context, configuration *configuration.Configuration, state *State, request *schema.MutationRequest) (*schema.MutationResponse, error) {
-	if len(request.Operations) == 1 || c.config.Concurrency.Mutation <= 1 {
-		return c.execMutationSync(ctx, state, request)
+	ctx, capture := c.startMutationDebugCapture(ctx, request)
+
+	var response *schema.MutationResponse
+	var err error
+	if concurrency := c.getMutationConcurrency(request); concurrency <= 1 {
+		response, err = c.execMutationSync(ctx, state, request)
+	} else {
+		response, err = c.execMutationAsync(ctx, state, request, concurrency)
 	}
+	c.finishDebugCapture(ctx, capture, response, err)
 
-	return c.execMutationAsync(ctx, state, request)
+	return response, err
 }
 
 // MutationExplain explains a mutation by creating an execution plan.
@@ -27,13 +37,37 @@ func (c *HTTPConnector) MutationExplain(ctx context.Context, configuration *conf
 		return nil, schema.BadRequestError("mutation operations must not be empty", nil)
 	}
 
-	operation := request.Operations[0]
+	explainResp, err := c.explainMutationOperation(ctx, configuration, request.Operations[0])
+	if err != nil {
+		return nil, err
+	}
+
+	// the concurrency of mutation operations in the request, 1 if operations are executed sequentially
+	explainResp.Details["concurrency"] = strconv.FormatUint(uint64(c.getMutationConcurrency(request)), 10)
+
+	return explainResp, nil
+}
+
+func (c *HTTPConnector) explainMutationOperation(ctx context.Context, configuration *configuration.Configuration, operation schema.MutationOperation) (*schema.ExplainResponse, error) {
 	switch operation.Type {
 	case schema.MutationOperationProcedure:
+		if err := c.authorizeMutationOperation(operation); err != nil {
+			return nil, err
+		}
+
 		if operation.Name == internal.ProcedureSendHTTPRequest {
 			return internal.NewRawRequestBuilder(operation, configuration.ForwardHeaders).Explain()
 		}
 
+		if operation.Name == internal.ProcedureSnapshotTest && c.snapshots != nil {
+			_, requests, err := c.explainSnapshotTest(operation)
+			if err != nil {
+				return nil, err
+			}
+
+			return c.serializeExplainResponse(ctx, requests)
+		}
+
 		requests, err := c.explainProcedure(&operation)
 		if err != nil {
 			return nil, err
@@ -45,6 +79,23 @@ func (c *HTTPConnector) MutationExplain(ctx context.Context, configuration *conf
 	}
 }
 
+// getMutationConcurrency returns the maximum number of operations of the mutation request which are executed in parallel.
+// Operations are executed one by one if any procedure belongs to a schema file which requires sequential mutations.
+func (c *HTTPConnector) getMutationConcurrency(request *schema.MutationRequest) uint {
+	if len(request.Operations) <= 1 || c.config.Concurrency.Mutation <= 1 {
+		return 1
+	}
+
+	for _, operation := range request.Operations {
+		_, metadata, err := c.metadata.GetProcedure(operation.Name)
+		if err == nil && metadata.Runtime.SequentialMutation {
+			return 1
+		}
+	}
+
+	return min(c.config.Concurrency.Mutation, uint(len(request.Operations)))
+}
+
 func (c *HTTPConnector) explainProcedure(operation *schema.MutationOperation) (*internal.RequestBuilderResults, error) {
 	procedure, metadata, err := c.metadata.GetProcedure(operation.Name)
 	if err != nil {
@@ -58,7 +109,16 @@ func (c *HTTPConnector) explainProcedure(operation *schema.MutationOperation) (*
 		})
 	}
 
-	return c.upstreams.BuildRequests(metadata, operation.Name, procedure, rawArgs)
+	requests, err := c.upstreams.BuildRequests(metadata, operation.Name, procedure, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.upstreams.PushDownFieldSelection(requests, operation.Fields); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
 }
 
 func (c *HTTPConnector) execMutationSync(ctx context.Context, state *State, request *schema.MutationRequest) (*schema.MutationResponse, error) {
@@ -76,11 +136,11 @@ func (c *HTTPConnector) execMutationSync(ctx context.Context, state *State, requ
 	}, nil
 }
 
-func (c *HTTPConnector) execMutationAsync(ctx context.Context, state *State, request *schema.MutationRequest) (*schema.MutationResponse, error) {
+func (c *HTTPConnector) execMutationAsync(ctx context.Context, state *State, request *schema.MutationRequest, concurrency uint) (*schema.MutationResponse, error) {
 	operationResults := make([]schema.MutationOperationResults, len(request.Operations))
 
 	eg, ctx := errgroup.WithContext(ctx)
-	eg.SetLimit(int(c.config.Concurrency.Mutation))
+	eg.SetLimit(int(concurrency))
 
 	for i, operation := range request.Operations {
 		func(index int, op schema.MutationOperation) {
@@ -109,30 +169,194 @@ func (c *HTTPConnector) execMutationOperation(parentCtx context.Context, state *
 	ctx, span := state.Tracer.Start(parentCtx, fmt.Sprintf("Execute Operation %d", index))
 	defer span.End()
 
+	if c.isDryRunOperation(operation) {
+		result, err := c.execDryRunProcedure(ctx, operation)
+		if err != nil {
+			span.SetStatus(codes.Error, "failed to build the dry-run request")
+			span.RecordError(err)
+
+			return nil, err
+		}
+
+		return schema.NewProcedureResult(result).Encode(), nil
+	}
+
+	idempotencyKey, err := c.guardMutationOperation(operation)
+	if err != nil {
+		span.SetStatus(codes.Error, "failed to validate mutation")
+		span.RecordError(err)
+
+		return nil, err
+	}
+
+	result, err := c.execMutationRequests(ctx, operation)
+	if err != nil {
+		c.replayGuard.Release(idempotencyKey)
+		span.SetStatus(codes.Error, "failed to execute mutation")
+		span.RecordError(err)
+
+		return nil, err
+	}
+
+	return schema.NewProcedureResult(result).Encode(), nil
+}
+
+func (c *HTTPConnector) execMutationRequests(ctx context.Context, operation schema.MutationOperation) (any, error) {
+	if operation.Name == internal.ProcedureSnapshotTest && c.snapshots != nil {
+		return c.execSnapshotTest(ctx, operation)
+	}
+
+	if c.mock != nil {
+		return c.execMockProcedure(operation)
+	}
+
+	if c.coalescer.IsEnabled(operation.Name) {
+		rawArgs, err := decodeMutationArguments(operation)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.coalescer.Execute(ctx, operation.Name, rawArgs, operation.Fields)
+	}
+
 	var requests *internal.RequestBuilderResults
 	var err error
 	if operation.Name == internal.ProcedureSendHTTPRequest {
-		requests, err = internal.NewRawRequestBuilder(operation, c.config.ForwardHeaders).Build()
-		requests.Operation = &c.procSendHttpRequest
+		requests, err = c.upstreams.BuildRawRequests(operation)
+		if err == nil {
+			requests.Operation = &c.procSendHttpRequest
+		}
 	} else {
 		requests, err = c.explainProcedure(&operation)
 	}
 
 	if err != nil {
-		span.SetStatus(codes.Error, "failed to explain mutation")
-		span.RecordError(err)
-
 		return nil, err
 	}
 
 	client := c.upstreams.CreateHTTPClient(requests)
-	result, _, err := client.Send(ctx, operation.Fields)
+	var enrichSettings []rest.ResponseEnrichSettings
+	if requests.Operation.Request != nil {
+		enrichSettings = requests.Operation.Request.Response.Enrich
+	}
+
+	if len(enrichSettings) == 0 || requests.HTTPOptions.Distributed {
+		result, _, err := client.Send(ctx, operation.Fields)
+
+		return result, err
+	}
+
+	// the selection is evaluated after enriched fields are embedded, e.g. create then get by id
+	result, _, err := client.Send(ctx, nil)
 	if err != nil {
-		span.SetStatus(codes.Error, "failed to execute mutation")
-		span.RecordError(err)
+		return nil, err
+	}
 
+	rawArgs, err := decodeMutationArguments(operation)
+	if err != nil {
 		return nil, err
 	}
 
-	return schema.NewProcedureResult(result).Encode(), nil
+	result, err = c.enrichResponse(ctx, rawArgs, result, enrichSettings)
+	if err != nil || len(operation.Fields) == 0 {
+		return result, err
+	}
+
+	return utils.EvalNestedColumnFields(operation.Fields, result)
+}
+
+// return the mock response of the procedure without calling the upstream API.
+// The raw HTTP request procedure only serves its fixture file because the response has no schema.
+func (c *HTTPConnector) execMockProcedure(operation schema.MutationOperation) (any, error) {
+	var result any
+	if operation.Name == internal.ProcedureSendHTTPRequest {
+		fixture, _, err := c.mock.ReadFixture(operation.Name)
+		if err != nil {
+			return nil, schema.InternalServerError("failed to generate fake data", map[string]any{
+				"cause": err.Error(),
+			})
+		}
+
+		result = fixture
+	} else {
+		procedure, metadata, err := c.metadata.GetProcedure(operation.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err = c.generateFakeResult(metadata.NDCHttpSchema, operation.Name, procedure)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(operation.Fields) == 0 {
+		return result, nil
+	}
+
+	return utils.EvalNestedColumnFields(operation.Fields, result)
+}
+
+// execute the batch procedure of coalesced procedure calls.
+func (c *HTTPConnector) execBatchProcedure(ctx context.Context, procedureName string, arguments map[string]any) (any, error) {
+	procedure, metadata, err := c.metadata.GetProcedure(procedureName)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := c.upstreams.BuildRequests(metadata, procedureName, procedure, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.upstreams.CreateHTTPClient(requests)
+	result, _, err := client.Send(ctx, nil)
+
+	return result, err
+}
+
+func (c *HTTPConnector) authorizeMutationOperation(operation schema.MutationOperation) error {
+	if c.config.Authorization == nil {
+		return nil
+	}
+
+	rawArgs, err := decodeMutationArguments(operation)
+	if err != nil {
+		return err
+	}
+
+	return internal.AuthorizeOperation(c.config, operation.Name, rawArgs)
+}
+
+// evaluate authorization rules and reserve the idempotency token of the mutation operation if exists.
+func (c *HTTPConnector) guardMutationOperation(operation schema.MutationOperation) (string, error) {
+	if c.config.Authorization == nil && c.replayGuard == nil {
+		return "", nil
+	}
+
+	rawArgs, err := decodeMutationArguments(operation)
+	if err != nil {
+		return "", err
+	}
+
+	if err := internal.AuthorizeOperation(c.config, operation.Name, rawArgs); err != nil {
+		return "", err
+	}
+
+	return c.replayGuard.Acquire(operation.Name, rawArgs)
+}
+
+func decodeMutationArguments(operation schema.MutationOperation) (map[string]any, error) {
+	var rawArgs map[string]any
+	if len(operation.Arguments) == 0 {
+		return rawArgs, nil
+	}
+
+	if err := json.Unmarshal(operation.Arguments, &rawArgs); err != nil {
+		return nil, schema.BadRequestError("failed to decode arguments", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	return rawArgs, nil
 }
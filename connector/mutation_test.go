@@ -3,13 +3,16 @@ package connector
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
 
+	"github.com/hasura/ndc-http/connector/internal"
 	"github.com/hasura/ndc-http/connector/internal/compression"
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
 	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
 	"github.com/hasura/ndc-sdk-go/connector"
 	"github.com/hasura/ndc-sdk-go/ndctest"
@@ -195,3 +198,303 @@ func TestHTTPConnectorCompression(t *testing.T) {
 	// 	})
 	// })
 }
+
+func TestHTTPConnectorGraphQL(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/graphql", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		var reqBody struct {
+			Query         string         `json:"query"`
+			OperationName string         `json:"operationName"`
+			Variables     map[string]any `json:"variables"`
+		}
+		assert.NilError(t, json.NewDecoder(r.Body).Decode(&reqBody))
+		assert.Equal(t, "createPost", reqBody.OperationName)
+		assert.Equal(t, "mutation createPost($input: PostInput!) {\n  createPost(input: $input) {\n    id\n    title\n    userId\n  }\n}", reqBody.Query)
+
+		input := reqBody.Variables["input"].(map[string]any)
+		w.Header().Add(rest.ContentTypeHeader, rest.ContentTypeJSON)
+		if input["title"] == "" {
+			_, _ = w.Write([]byte(`{"data": null, "errors": [{"message": "title is required"}]}`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"data": {"createPost": {"id": 1, "title": "Hello world", "userId": 10}}}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Setenv("SERVER_URL", server.URL+"/graphql")
+	connServer, err := connector.NewServer(NewHTTPConnector(), &connector.ServerOptions{
+		Configuration: "testdata/graphql",
+	}, connector.WithoutRecovery())
+	assert.NilError(t, err)
+	testServer := connServer.BuildTestServer()
+	defer testServer.Close()
+
+	createPost := func(title string) *http.Response {
+		rawReqBody, err := json.Marshal(schema.MutationRequest{
+			CollectionRelationships: make(schema.MutationRequestCollectionRelationships),
+			Operations: []schema.MutationOperation{
+				{
+					Type:      schema.MutationOperationProcedure,
+					Name:      "createPost",
+					Arguments: []byte(fmt.Sprintf(`{"input": {"title": %q, "userId": 10}}`, title)),
+					Fields: schema.NewNestedObject(map[string]schema.FieldEncoder{
+						"id":    schema.NewColumnField("id", nil),
+						"title": schema.NewColumnField("title", nil),
+					}).Encode(),
+				},
+			},
+		})
+		assert.NilError(t, err)
+
+		res, err := http.Post(testServer.URL+"/mutation", "application/json", bytes.NewBuffer(rawReqBody))
+		assert.NilError(t, err)
+
+		return res
+	}
+
+	t.Run("success", func(t *testing.T) {
+		assertHTTPResponse(t, createPost("Hello world"), http.StatusOK, schema.MutationResponse{
+			OperationResults: []schema.MutationOperationResults{
+				schema.NewProcedureResult(map[string]any{
+					"id":    float64(1),
+					"title": "Hello world",
+				}).Encode(),
+			},
+		})
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		assertHTTPResponse(t, createPost(""), http.StatusUnprocessableEntity, schema.ErrorResponse{
+			Message: "the GraphQL response has errors",
+			Details: map[string]any{
+				"errors": []any{
+					map[string]any{"message": "title is required"},
+				},
+			},
+		})
+	})
+}
+
+func TestHTTPConnectorSOAP(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/stock", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "text/xml; charset=utf-8", r.Header.Get(rest.ContentTypeHeader))
+		assert.Equal(t, `"http://example.com/stock/GetQuote"`, r.Header.Get("SOAPAction"))
+
+		rawBody, err := io.ReadAll(r.Body)
+		assert.NilError(t, err)
+		assert.Assert(t, strings.Contains(string(rawBody), `<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/"><soap:Body>`))
+		assert.Assert(t, strings.Contains(string(rawBody), `<GetQuote xmlns="http://example.com/stock">`))
+
+		w.Header().Add(rest.ContentTypeHeader, "text/xml; charset=utf-8")
+		if strings.Contains(string(rawBody), "<symbol>UNKNOWN</symbol>") {
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <soap:Fault>
+      <faultcode>soap:Client</faultcode>
+      <faultstring>unknown stock symbol</faultstring>
+    </soap:Fault>
+  </soap:Body>
+</soap:Envelope>`))
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>
+<soap:Envelope xmlns:soap="http://schemas.xmlsoap.org/soap/envelope/">
+  <soap:Body>
+    <GetQuoteResponse xmlns="http://example.com/stock">
+      <quote currency="USD">
+        <symbol>HSR</symbol>
+        <price>12.5</price>
+        <volume>1000</volume>
+      </quote>
+    </GetQuoteResponse>
+  </soap:Body>
+</soap:Envelope>`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	t.Setenv("STOCK_SERVER_URL", server.URL+"/stock")
+	connServer, err := connector.NewServer(NewHTTPConnector(), &connector.ServerOptions{
+		Configuration: "testdata/soap",
+	}, connector.WithoutRecovery())
+	assert.NilError(t, err)
+	testServer := connServer.BuildTestServer()
+	defer testServer.Close()
+
+	getQuote := func(symbol string) *http.Response {
+		rawReqBody, err := json.Marshal(schema.MutationRequest{
+			CollectionRelationships: make(schema.MutationRequestCollectionRelationships),
+			Operations: []schema.MutationOperation{
+				{
+					Type:      schema.MutationOperationProcedure,
+					Name:      "getQuote",
+					Arguments: []byte(fmt.Sprintf(`{"body": {"symbol": %q}}`, symbol)),
+					Fields: schema.NewNestedObject(map[string]schema.FieldEncoder{
+						"quote": schema.NewColumnField("quote", schema.NewNestedObject(map[string]schema.FieldEncoder{
+							"currency": schema.NewColumnField("currency", nil),
+							"symbol":   schema.NewColumnField("symbol", nil),
+							"price":    schema.NewColumnField("price", nil),
+						})),
+					}).Encode(),
+				},
+			},
+		})
+		assert.NilError(t, err)
+
+		res, err := http.Post(testServer.URL+"/mutation", "application/json", bytes.NewBuffer(rawReqBody))
+		assert.NilError(t, err)
+
+		return res
+	}
+
+	t.Run("success", func(t *testing.T) {
+		assertHTTPResponse(t, getQuote("HSR"), http.StatusOK, schema.MutationResponse{
+			OperationResults: []schema.MutationOperationResults{
+				schema.NewProcedureResult(map[string]any{
+					"quote": map[string]any{
+						"currency": "USD",
+						"symbol":   "HSR",
+						"price":    12.5,
+					},
+				}).Encode(),
+			},
+		})
+	})
+
+	t.Run("fault", func(t *testing.T) {
+		assertHTTPResponse(t, getQuote("UNKNOWN"), http.StatusUnprocessableEntity, schema.ErrorResponse{
+			Message: "SOAP fault soap:Client: unknown stock symbol",
+			Details: map[string]any{
+				"code":   "soap:Client",
+				"reason": "unknown stock symbol",
+			},
+		})
+	})
+}
+
+func TestMutationConcurrency(t *testing.T) {
+	newMetadata := func(name string, sequential bool, procedures ...string) configuration.NDCHttpRuntimeSchema {
+		ndcSchema := &rest.NDCHttpSchema{
+			Procedures: map[string]rest.OperationInfo{},
+		}
+		for _, procedure := range procedures {
+			ndcSchema.Procedures[procedure] = rest.OperationInfo{}
+		}
+
+		return configuration.NDCHttpRuntimeSchema{
+			Name:          name,
+			Runtime:       rest.RuntimeSettings{SequentialMutation: sequential},
+			NDCHttpSchema: ndcSchema,
+		}
+	}
+
+	c := &HTTPConnector{
+		config: &configuration.Configuration{
+			Concurrency: configuration.ConcurrencySettings{Mutation: 3},
+		},
+		metadata: internal.MetadataCollection{
+			newMetadata("payments.yaml", true, "createPayment"),
+			newMetadata("petstore.yaml", false, "addPet", "updatePet", "deletePet", "addOrder"),
+		},
+	}
+
+	newRequest := func(names ...string) *schema.MutationRequest {
+		request := &schema.MutationRequest{}
+		for _, name := range names {
+			request.Operations = append(request.Operations, schema.MutationOperation{
+				Type: schema.MutationOperationProcedure,
+				Name: name,
+			})
+		}
+
+		return request
+	}
+
+	assert.Equal(t, uint(1), c.getMutationConcurrency(newRequest("addPet")))
+	assert.Equal(t, uint(2), c.getMutationConcurrency(newRequest("addPet", "updatePet")))
+	assert.Equal(t, uint(3), c.getMutationConcurrency(newRequest("addPet", "updatePet", "deletePet", "addOrder")))
+	assert.Equal(t, uint(1), c.getMutationConcurrency(newRequest("addPet", "createPayment")))
+
+	c.config.Concurrency.Mutation = 0
+	assert.Equal(t, uint(1), c.getMutationConcurrency(newRequest("addPet", "updatePet")))
+}
+
+func TestConnectorDryRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("dry-run requests must not be sent, got %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	t.Setenv("PET_STORE_URL", server.URL)
+	t.Setenv("PET_NAME", "Dog")
+	connServer, err := connector.NewServer(NewHTTPConnector(), &connector.ServerOptions{
+		Configuration: "testdata/dry-run",
+	}, connector.WithoutRecovery())
+	assert.NilError(t, err)
+	testServer := connServer.BuildTestServer()
+	defer testServer.Close()
+
+	t.Run("schema", func(t *testing.T) {
+		res, err := http.Get(testServer.URL + "/schema")
+		assert.NilError(t, err)
+		defer res.Body.Close()
+
+		var schemaResp schema.SchemaResponse
+		assert.NilError(t, json.NewDecoder(res.Body).Decode(&schemaResp))
+		assert.Assert(t, schemaResp.ObjectTypes[rest.HTTPDryRunOptionsObjectName].Fields["dryRun"].Type != nil)
+
+		for _, proc := range schemaResp.Procedures {
+			if proc.Name == "addPet" {
+				assert.DeepEqual(t, schema.NewNullableNamedType(rest.HTTPDryRunOptionsObjectName).Encode(), proc.Arguments[rest.HTTPOptionsArgumentName].Type)
+			}
+		}
+	})
+
+	t.Run("mutation", func(t *testing.T) {
+		reqBody := []byte(`{
+			"operations": [
+				{
+					"type": "procedure",
+					"name": "addPet",
+					"arguments": {
+						"body": {},
+						"httpOptions": { "dryRun": true }
+					}
+				}
+			],
+			"collection_relationships": {}
+		}`)
+
+		res, err := http.Post(testServer.URL+"/mutation", "application/json", bytes.NewBuffer(reqBody))
+		assert.NilError(t, err)
+		assertHTTPResponse(t, res, http.StatusOK, schema.MutationResponse{
+			OperationResults: []schema.MutationOperationResults{
+				schema.NewProcedureResult(map[string]any{
+					"operation": "addPet",
+					"server":    "0",
+					"method":    "POST",
+					"url":       server.URL + "/pet",
+					"headers": map[string]any{
+						"Accept":       []any{"application/json"},
+						"Content-Type": []any{"application/json"},
+					},
+					"body": "{\"id\":1,\"name\":\"Dog\"}\n",
+				}).Encode(),
+			},
+		})
+	})
+}
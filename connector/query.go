@@ -4,10 +4,14 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"strconv"
 
 	"github.com/hasura/ndc-http/connector/internal"
 	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
 	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-http/ndc-http-schema/version"
 	"github.com/hasura/ndc-sdk-go/schema"
 	"github.com/hasura/ndc-sdk-go/utils"
 	"go.opentelemetry.io/otel/codes"
@@ -16,20 +20,29 @@ import (
 
 // Query executes a query.
 func (c *HTTPConnector) Query(ctx context.Context, configuration *configuration.Configuration, state *State, request *schema.QueryRequest) (schema.QueryResponse, error) {
-	valueField, err := utils.EvalFunctionSelectionFieldValue(request)
+	valueField, err := c.evalQuerySelection(request)
 	if err != nil {
-		return nil, schema.UnprocessableContentError(err.Error(), nil)
+		return nil, err
 	}
 	requestVars := request.Variables
 	if len(requestVars) == 0 {
 		requestVars = []schema.QueryRequestVariablesElem{make(schema.QueryRequestVariablesElem)}
 	}
 
-	if len(requestVars) == 1 || c.config.Concurrency.Query <= 1 {
-		return c.execQuerySync(ctx, state, request, valueField, requestVars)
+	ctx, capture := c.startQueryDebugCapture(ctx, request, requestVars[0])
+
+	var rowSets []schema.RowSet
+	switch {
+	case c.canBatchQueryVariables(request):
+		rowSets, err = c.execQueryBatch(ctx, state, request, valueField, requestVars)
+	case len(requestVars) == 1 || c.config.Concurrency.Query <= 1:
+		rowSets, err = c.execQuerySync(ctx, state, request, valueField, requestVars)
+	default:
+		rowSets, err = c.execQueryAsync(ctx, state, request, valueField, requestVars)
 	}
+	c.finishDebugCapture(ctx, capture, rowSets, err)
 
-	return c.execQueryAsync(ctx, state, request, valueField, requestVars)
+	return rowSets, err
 }
 
 // QueryExplain explains a query by creating an execution plan.
@@ -39,6 +52,13 @@ func (c *HTTPConnector) QueryExplain(ctx context.Context, configuration *configu
 		requestVars = []schema.QueryRequestVariablesElem{make(schema.QueryRequestVariablesElem)}
 	}
 
+	if request.Collection == internal.FunctionConnectorInfo {
+		// the function doesn't send any request
+		return &schema.ExplainResponse{
+			Details: schema.ExplainResponseDetails{},
+		}, nil
+	}
+
 	requests, err := c.explainQuery(request, requestVars[0])
 	if err != nil {
 		return nil, err
@@ -60,7 +80,37 @@ func (c *HTTPConnector) explainQuery(request *schema.QueryRequest, variables map
 		})
 	}
 
-	return c.upstreams.BuildRequests(metadata, request.Collection, function, rawArgs)
+	if err := internal.AuthorizeOperation(c.config, request.Collection, rawArgs); err != nil {
+		return nil, err
+	}
+
+	requests, err := c.upstreams.BuildRequests(metadata, request.Collection, function, rawArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	valueField, err := c.evalQuerySelection(request)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := c.upstreams.PushDownFieldSelection(requests, valueField); err != nil {
+		return nil, err
+	}
+
+	if err := c.upstreams.ApplyPagination(requests, request.Query.Limit, request.Query.Offset); err != nil {
+		return nil, err
+	}
+
+	if err := c.upstreams.PushDownPredicate(requests, request.Query.Predicate, variables); err != nil {
+		return nil, err
+	}
+
+	if err := c.upstreams.PushDownOrderBy(requests, request.Query.OrderBy); err != nil {
+		return nil, err
+	}
+
+	return requests, nil
 }
 
 func (c *HTTPConnector) execQuerySync(ctx context.Context, state *State, request *schema.QueryRequest, valueField schema.NestedField, requestVars []schema.QueryRequestVariablesElem) ([]schema.RowSet, error) {
@@ -71,13 +121,10 @@ func (c *HTTPConnector) execQuerySync(ctx context.Context, state *State, request
 		if err != nil {
 			return nil, err
 		}
-		rowSets[i] = schema.RowSet{
-			Aggregates: schema.RowSetAggregates{},
-			Rows: []map[string]any{
-				{
-					"__value": result,
-				},
-			},
+
+		rowSets[i], err = c.createRowSet(request, result)
+		if err != nil {
+			return nil, err
 		}
 	}
 
@@ -97,16 +144,10 @@ func (c *HTTPConnector) execQueryAsync(ctx context.Context, state *State, reques
 				if err != nil {
 					return err
 				}
-				rowSets[index] = schema.RowSet{
-					Aggregates: schema.RowSetAggregates{},
-					Rows: []map[string]any{
-						{
-							"__value": result,
-						},
-					},
-				}
 
-				return nil
+				rowSets[index], err = c.createRowSet(request, result)
+
+				return err
 			})
 		}(i, requestVar)
 	}
@@ -118,10 +159,91 @@ func (c *HTTPConnector) execQueryAsync(ctx context.Context, state *State, reques
 	return rowSets, nil
 }
 
+// check if variable sets of the query can be fetched in batch requests.
+// Queries with pagination, predicates or sorting are executed per variable set because they apply to each row set.
+func (c *HTTPConnector) canBatchQueryVariables(request *schema.QueryRequest) bool {
+	return len(request.Variables) > 1 && !c.fake && c.mock == nil &&
+		c.variableBatcher.IsEnabled(request.Collection) &&
+		request.Query.Limit == nil && request.Query.Offset == nil && len(request.Query.Predicate) == 0 &&
+		(request.Query.OrderBy == nil || len(request.Query.OrderBy.Elements) == 0)
+}
+
+// fetch rows of variable sets in batch requests of the batch function, then fan out results into row sets.
+func (c *HTTPConnector) execQueryBatch(ctx context.Context, state *State, request *schema.QueryRequest, valueField schema.NestedField, requestVars []schema.QueryRequestVariablesElem) ([]schema.RowSet, error) {
+	ctx, span := state.Tracer.Start(ctx, "Execute Query Batch")
+	defer span.End()
+
+	argumentsList := make([]map[string]any, len(requestVars))
+	for i, requestVar := range requestVars {
+		rawArgs, err := utils.ResolveArgumentVariables(request.Arguments, requestVar)
+		if err != nil {
+			return nil, schema.UnprocessableContentError("failed to resolve argument variables", map[string]any{
+				"cause": err.Error(),
+			})
+		}
+
+		if err := internal.AuthorizeOperation(c.config, request.Collection, rawArgs); err != nil {
+			return nil, err
+		}
+
+		argumentsList[i] = rawArgs
+	}
+
+	results, err := c.variableBatcher.Execute(ctx, request.Collection, argumentsList)
+	if err != nil {
+		span.SetStatus(codes.Error, "failed to execute the batch request")
+		span.RecordError(err)
+
+		return nil, err
+	}
+
+	rowSets := make([]schema.RowSet, len(results))
+	for i, result := range results {
+		if len(valueField) > 0 {
+			result, err = utils.EvalNestedColumnFields(valueField, result)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		rowSets[i], err = c.createRowSet(request, result)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return rowSets, nil
+}
+
+// execute the batch function of batched query variables.
+func (c *HTTPConnector) execBatchFunction(ctx context.Context, functionName string, arguments map[string]any) (any, error) {
+	function, metadata, err := c.metadata.GetFunction(functionName)
+	if err != nil {
+		return nil, err
+	}
+
+	requests, err := c.upstreams.BuildRequests(metadata, functionName, function, arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	result, _, err := c.upstreams.CreateHTTPClient(requests).Send(ctx, nil)
+
+	return result, err
+}
+
 func (c *HTTPConnector) execQuery(ctx context.Context, state *State, request *schema.QueryRequest, queryFields schema.NestedField, variables map[string]any, index int) (any, error) {
 	ctx, span := state.Tracer.Start(ctx, fmt.Sprintf("Execute Query %d", index))
 	defer span.End()
 
+	if request.Collection == internal.FunctionConnectorInfo {
+		return c.execConnectorInfo(request, queryFields, variables)
+	}
+
+	if c.fake || c.mock != nil {
+		return c.execFakeQuery(request, queryFields, variables)
+	}
+
 	requests, err := c.explainQuery(request, variables)
 	if err != nil {
 		span.SetStatus(codes.Error, "failed to explain query")
@@ -130,8 +252,74 @@ func (c *HTTPConnector) execQuery(ctx context.Context, state *State, request *sc
 		return nil, err
 	}
 
-	client := c.upstreams.CreateHTTPClient(requests)
-	result, _, err := client.Send(ctx, queryFields)
+	cacheArguments := map[string]any{
+		"arguments": request.Arguments,
+		"variables": variables,
+	}
+	if request.Query.Limit != nil {
+		cacheArguments["limit"] = *request.Query.Limit
+	}
+	if request.Query.Offset != nil {
+		cacheArguments["offset"] = *request.Query.Offset
+	}
+	if len(request.Query.Predicate) > 0 {
+		cacheArguments["predicate"] = request.Query.Predicate
+	}
+	if request.Query.OrderBy != nil && len(request.Query.OrderBy.Elements) > 0 {
+		cacheArguments["order_by"] = request.Query.OrderBy.Elements
+	}
+	// cached responses of different credentials must not be shared
+	if c.responseCache != nil && requests.Schema != nil {
+		if fingerprint := c.upstreams.CredentialFingerprint(requests.Schema.Name); fingerprint != "" {
+			cacheArguments["credentials"] = fingerprint
+		}
+	}
+	result, err := c.responseCache.Execute(ctx, request.Collection, cacheArguments, queryFields, func(ctx context.Context) (any, error) {
+		client := c.upstreams.CreateHTTPClient(requests)
+		enrichSettings := requests.Operation.Request.Response.Enrich
+		if requests.HTTPOptions.Distributed {
+			enrichSettings = nil
+		}
+
+		fetchPages := client.CanFetchPages()
+		if len(enrichSettings) == 0 && !fetchPages {
+			result, _, err := client.Send(ctx, queryFields)
+
+			return result, err
+		}
+
+		// the selection is evaluated after pages are concatenated and enriched fields are embedded
+		var result any
+		var err error
+		if fetchPages {
+			result, _, err = client.SendPages(ctx)
+		} else {
+			result, _, err = client.Send(ctx, nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(enrichSettings) == 0 {
+			if len(queryFields) == 0 {
+				return result, nil
+			}
+
+			return utils.EvalNestedColumnFields(queryFields, result)
+		}
+
+		rawArgs, err := utils.ResolveArgumentVariables(request.Arguments, variables)
+		if err != nil {
+			return nil, err
+		}
+
+		result, err = c.enrichResponse(ctx, rawArgs, result, enrichSettings)
+		if err != nil || len(queryFields) == 0 {
+			return result, err
+		}
+
+		return utils.EvalNestedColumnFields(queryFields, result)
+	})
 	if err != nil {
 		span.SetStatus(codes.Error, "failed to execute the http request")
 		span.RecordError(err)
@@ -142,20 +330,228 @@ func (c *HTTPConnector) execQuery(ctx context.Context, state *State, request *sc
 	return result, nil
 }
 
-func (c *HTTPConnector) serializeExplainResponse(ctx context.Context, requests *internal.RequestBuilderResults) (*schema.ExplainResponse, error) {
-	explainResp := &schema.ExplainResponse{
-		Details: schema.ExplainResponseDetails{},
+// get the function of the query if it's executed as a collection.
+func (c *HTTPConnector) getQueryCollection(name string) *rest.OperationInfo {
+	if name == internal.FunctionConnectorInfo {
+		return nil
 	}
-	httpRequest := requests.Requests[0]
-	if httpRequest.Body != nil {
-		explainResp.Details["body"] = string(httpRequest.Body)
-		httpRequest.Body = nil
+
+	function, _, err := c.metadata.GetFunction(name)
+	if err != nil || !function.Collection {
+		return nil
 	}
 
-	req, cancel, err := httpRequest.CreateRequest(ctx)
+	return function
+}
+
+// evaluate the selection of the function result. Fields of collection queries are columns of items.
+func (c *HTTPConnector) evalQuerySelection(request *schema.QueryRequest) (schema.NestedField, error) {
+	collection := c.getQueryCollection(request.Collection)
+	if collection == nil {
+		valueField, err := utils.EvalFunctionSelectionFieldValue(request)
+		if err != nil {
+			return nil, schema.UnprocessableContentError(err.Error(), nil)
+		}
+
+		return valueField, nil
+	}
+
+	// the connector doesn't sort rows itself, so the order must be pushed down
+	if request.Query.OrderBy != nil && len(request.Query.OrderBy.Elements) > 0 && (collection.Request == nil || collection.Request.OrderBy == nil) {
+		return nil, schema.UnprocessableContentError(fmt.Sprintf("the collection %s doesn't support sorting", request.Collection), nil)
+	}
+
+	return internal.BuildCollectionSelection(collection, request.Query.Fields), nil
+}
+
+// create the row set of the query result. Items of collection results are returned as rows.
+func (c *HTTPConnector) createRowSet(request *schema.QueryRequest, result any) (schema.RowSet, error) {
+	rowSet := schema.RowSet{
+		Aggregates: schema.RowSetAggregates{},
+	}
+
+	collection := c.getQueryCollection(request.Collection)
+	if collection == nil {
+		rowSet.Rows = []map[string]any{
+			{
+				"__value": result,
+			},
+		}
+
+		return rowSet, nil
+	}
+
+	if len(request.Query.Fields) == 0 {
+		return rowSet, nil
+	}
+
+	rows, err := internal.EvalCollectionRows(collection, result, request.Query.Limit, request.Query.Offset)
+	if err != nil {
+		return rowSet, err
+	}
+	rowSet.Rows = rows
+
+	return rowSet, nil
+}
+
+// get the build version of the connector and loaded schema versions.
+func (c *HTTPConnector) execConnectorInfo(request *schema.QueryRequest, queryFields schema.NestedField, variables map[string]any) (any, error) {
+	rawArgs, err := utils.ResolveArgumentVariables(request.Arguments, variables)
+	if err != nil {
+		return nil, schema.UnprocessableContentError("failed to resolve argument variables", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	if err := internal.AuthorizeOperation(c.config, request.Collection, rawArgs); err != nil {
+		return nil, err
+	}
+
+	result := internal.NewConnectorInfo(version.BuildVersion, c.metadata, c.failedSchemas)
+	if len(queryFields) == 0 {
+		return result, nil
+	}
+
+	return utils.EvalNestedColumnFields(queryFields, result)
+}
+
+// generate fake data or the mock response of the function result without calling the upstream API.
+func (c *HTTPConnector) execFakeQuery(request *schema.QueryRequest, queryFields schema.NestedField, variables map[string]any) (any, error) {
+	function, metadata, err := c.metadata.GetFunction(request.Collection)
+	if err != nil {
+		return nil, err
+	}
+
+	rawArgs, err := utils.ResolveArgumentVariables(request.Arguments, variables)
+	if err != nil {
+		return nil, schema.UnprocessableContentError("failed to resolve argument variables", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	if err := internal.AuthorizeOperation(c.config, request.Collection, rawArgs); err != nil {
+		return nil, err
+	}
+
+	result, err := c.generateFakeResult(metadata.NDCHttpSchema, request.Collection, function)
 	if err != nil {
 		return nil, err
 	}
+
+	if len(queryFields) == 0 {
+		return result, nil
+	}
+
+	return utils.EvalNestedColumnFields(queryFields, result)
+}
+
+// resolve the mock response in the mock mode, or generate fake data of the operation result.
+func (c *HTTPConnector) generateFakeResult(httpSchema *rest.NDCHttpSchema, operationName string, operation *rest.OperationInfo) (any, error) {
+	var result any
+	var err error
+	if c.mock != nil {
+		result, err = c.mock.Resolve(httpSchema, operationName, operation)
+	} else {
+		result, err = internal.GenerateFakeData(httpSchema, operationName, operation.ResultType)
+	}
+
+	if err != nil {
+		return nil, schema.InternalServerError("failed to generate fake data", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	return result, nil
+}
+
+// embed results of other functions into items of the response.
+func (c *HTTPConnector) enrichResponse(ctx context.Context, rawArgs map[string]any, result any, settings []rest.ResponseEnrichSettings) (any, error) {
+	var forwardedHeaders any
+	if c.config.ForwardHeaders.Enabled && c.config.ForwardHeaders.ArgumentField != nil {
+		forwardedHeaders = rawArgs[*c.config.ForwardHeaders.ArgumentField]
+	}
+
+	fetch := func(ctx context.Context, operationName string, arguments map[string]any) (any, error) {
+		if forwardedHeaders != nil {
+			arguments[*c.config.ForwardHeaders.ArgumentField] = forwardedHeaders
+		}
+
+		if err := internal.AuthorizeOperation(c.config, operationName, arguments); err != nil {
+			return nil, err
+		}
+
+		function, metadata, err := c.metadata.GetFunction(operationName)
+		if err != nil {
+			return nil, err
+		}
+
+		requests, err := c.upstreams.BuildRequests(metadata, operationName, function, arguments)
+		if err != nil {
+			return nil, err
+		}
+
+		result, _, err := c.upstreams.CreateHTTPClient(requests).Send(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.unwrapForwardedHeadersResponse(result), nil
+	}
+
+	_, err := internal.EnrichResponse(ctx, c.unwrapForwardedHeadersResponse(result), settings, fetch)
+	if err != nil {
+		return nil, schema.InternalServerError(err.Error(), nil)
+	}
+
+	return result, nil
+}
+
+// get the result field of the response if response headers are forwarded.
+func (c *HTTPConnector) unwrapForwardedHeadersResponse(result any) any {
+	if !c.config.ForwardHeaders.Enabled || c.config.ForwardHeaders.ResponseHeaders == nil {
+		return result
+	}
+
+	if resultMap, ok := result.(map[string]any); ok {
+		return resultMap[c.config.ForwardHeaders.ResponseHeaders.ResultField]
+	}
+
+	return result
+}
+
+// remove the body from the request to be explained and return it as a string.
+// Stream bodies are encoded without compression.
+func takeExplainRequestBody(httpRequest *internal.RetryableRequest) (*string, error) {
+	if httpRequest.StreamBody != nil {
+		rawBody, err := httpRequest.StreamBody.Explain()
+		if err != nil {
+			return nil, schema.UnprocessableContentError("failed to encode the request body", map[string]any{
+				"cause": err.Error(),
+			})
+		}
+		httpRequest.StreamBody = nil
+		body := string(rawBody)
+
+		return &body, nil
+	}
+
+	if httpRequest.Body == nil {
+		return nil, nil
+	}
+
+	body := string(httpRequest.Body)
+	httpRequest.Body = nil
+
+	return &body, nil
+}
+
+// create the upstream request with mock credentials and masked sensitive headers without sending it.
+// Returns the name of the security scheme which is applied.
+func (c *HTTPConnector) createMaskedRequest(ctx context.Context, requests *internal.RequestBuilderResults, httpRequest *internal.RetryableRequest) (*http.Request, string, error) {
+	req, cancel, err := httpRequest.CreateRequest(ctx)
+	if err != nil {
+		return nil, "", err
+	}
 	defer cancel()
 
 	// mask sensitive forwarded headers if exists
@@ -165,7 +561,28 @@ func (c *HTTPConnector) serializeExplainResponse(ctx context.Context, requests *
 		}
 	}
 
-	c.upstreams.InjectMockRequestSettings(req, requests.Schema.Name, httpRequest.RawRequest.Security)
+	securityScheme := c.upstreams.InjectMockRequestSettings(req, requests.Schema.Name, httpRequest.RawRequest.Security)
+
+	return req, securityScheme, nil
+}
+
+func (c *HTTPConnector) serializeExplainResponse(ctx context.Context, requests *internal.RequestBuilderResults) (*schema.ExplainResponse, error) {
+	explainResp := &schema.ExplainResponse{
+		Details: schema.ExplainResponseDetails{},
+	}
+	httpRequest := requests.Requests[0]
+	body, err := takeExplainRequestBody(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		explainResp.Details["body"] = *body
+	}
+
+	req, securityScheme, err := c.createMaskedRequest(ctx, requests, httpRequest)
+	if err != nil {
+		return nil, err
+	}
 
 	explainResp.Details["url"] = req.URL.String()
 	rawHeaders, err := json.Marshal(req.Header)
@@ -176,5 +593,30 @@ func (c *HTTPConnector) serializeExplainResponse(ctx context.Context, requests *
 	}
 	explainResp.Details["headers"] = string(rawHeaders)
 
+	// effective runtime settings so operators can debug the configuration without sending requests
+	explainResp.Details["server"] = httpRequest.ServerID
+	explainResp.Details["timeout"] = strconv.FormatUint(uint64(httpRequest.GetTimeout()), 10)
+	rawRetry, err := json.Marshal(httpRequest.Runtime.Retry)
+	if err != nil {
+		return nil, schema.InternalServerError("failed to encode the retry policy", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+	explainResp.Details["retry"] = string(rawRetry)
+
+	if securityScheme != "" {
+		explainResp.Details["security"] = securityScheme
+	}
+
+	if presets := c.upstreams.GetArgumentPresetPaths(requests.Schema.Name, httpRequest.ServerID, requests.OperationName); len(presets) > 0 {
+		rawPresets, err := json.Marshal(presets)
+		if err != nil {
+			return nil, schema.InternalServerError("failed to encode argument presets", map[string]any{
+				"cause": err.Error(),
+			})
+		}
+		explainResp.Details["argumentPresets"] = string(rawPresets)
+	}
+
 	return explainResp, nil
 }
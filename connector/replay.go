@@ -0,0 +1,157 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/hasura/ndc-http/connector/internal"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// ReplayCommandArguments represent arguments of the replay command.
+type ReplayCommandArguments struct {
+	Configuration string `help:"Configuration directory." env:"HASURA_CONFIGURATION_DIRECTORY" default:"."`
+	File          string `arg:"" help:"Path of the recorded NDC query or mutation request JSON file."`
+}
+
+// ReplayRequest represents an upstream HTTP request which is rendered from a recorded NDC request.
+type ReplayRequest struct {
+	Operation string      `json:"operation"`
+	Server    string      `json:"server,omitempty"`
+	Method    string      `json:"method"`
+	URL       string      `json:"url"`
+	Headers   http.Header `json:"headers"`
+	Body      *string     `json:"body,omitempty"`
+}
+
+// Replay loads the configuration, builds upstream requests of a recorded NDC query or mutation request
+// and prints them to the writer without sending. Sensitive headers and credentials are masked.
+func Replay(ctx context.Context, args *ReplayCommandArguments, writer io.Writer) error {
+	rawRequest, err := os.ReadFile(args.File)
+	if err != nil {
+		return fmt.Errorf("failed to read the request file: %w", err)
+	}
+
+	c := NewHTTPConnector()
+	if _, err := c.ParseConfiguration(ctx, args.Configuration); err != nil {
+		return err
+	}
+
+	results, err := c.ReplayRequest(ctx, rawRequest)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(results)
+}
+
+// ReplayRequest builds upstream requests of a recorded NDC query or mutation request without sending.
+// Mutation requests are detected by the operations field.
+func (c *HTTPConnector) ReplayRequest(ctx context.Context, rawRequest []byte) ([]ReplayRequest, error) {
+	var rawFields map[string]json.RawMessage
+	if err := json.Unmarshal(rawRequest, &rawFields); err != nil {
+		return nil, fmt.Errorf("failed to decode the request: %w", err)
+	}
+
+	var builds []*internal.RequestBuilderResults
+	if _, ok := rawFields["operations"]; ok {
+		var request schema.MutationRequest
+		if err := json.Unmarshal(rawRequest, &request); err != nil {
+			return nil, fmt.Errorf("failed to decode the mutation request: %w", err)
+		}
+
+		for _, operation := range request.Operations {
+			requests, err := c.replayMutationOperation(operation)
+			if err != nil {
+				return nil, err
+			}
+			builds = append(builds, requests)
+		}
+	} else {
+		var request schema.QueryRequest
+		if err := json.Unmarshal(rawRequest, &request); err != nil {
+			return nil, fmt.Errorf("failed to decode the query request: %w", err)
+		}
+
+		if request.Collection == internal.FunctionConnectorInfo {
+			return nil, errors.New("the connector info function does not call upstream APIs")
+		}
+
+		requestVars := request.Variables
+		if len(requestVars) == 0 {
+			requestVars = []schema.QueryRequestVariablesElem{make(schema.QueryRequestVariablesElem)}
+		}
+
+		for _, variables := range requestVars {
+			requests, err := c.explainQuery(&request, variables)
+			if err != nil {
+				return nil, err
+			}
+			builds = append(builds, requests)
+		}
+	}
+
+	results := []ReplayRequest{}
+	for _, requests := range builds {
+		items, err := c.renderMaskedRequests(ctx, requests)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, items...)
+	}
+
+	return results, nil
+}
+
+// render built upstream requests with masked credentials and sensitive headers without sending.
+func (c *HTTPConnector) renderMaskedRequests(ctx context.Context, requests *internal.RequestBuilderResults) ([]ReplayRequest, error) {
+	results := make([]ReplayRequest, 0, len(requests.Requests))
+	for _, httpRequest := range requests.Requests {
+		result := ReplayRequest{
+			Operation: requests.OperationName,
+			Server:    httpRequest.ServerID,
+			Method:    httpRequest.RawRequest.Method,
+		}
+		body, err := takeExplainRequestBody(httpRequest)
+		if err != nil {
+			return nil, err
+		}
+		result.Body = body
+
+		req, _, err := c.createMaskedRequest(ctx, requests, httpRequest)
+		if err != nil {
+			return nil, err
+		}
+
+		result.Method = req.Method
+		result.URL = req.URL.String()
+		result.Headers = req.Header
+		results = append(results, result)
+	}
+
+	return results, nil
+}
+
+func (c *HTTPConnector) replayMutationOperation(operation schema.MutationOperation) (*internal.RequestBuilderResults, error) {
+	if operation.Type != schema.MutationOperationProcedure {
+		return nil, schema.BadRequestError(fmt.Sprintf("invalid operation type: %s", operation.Type), nil)
+	}
+
+	if err := c.authorizeMutationOperation(operation); err != nil {
+		return nil, err
+	}
+
+	if operation.Name == internal.ProcedureSendHTTPRequest {
+		return c.upstreams.BuildRawRequests(operation)
+	}
+
+	return c.explainProcedure(&operation)
+}
@@ -0,0 +1,90 @@
+package connector
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestReplay(t *testing.T) {
+	t.Setenv("API_KEY", "secret-api-key")
+
+	testCases := []struct {
+		Name     string
+		File     string
+		Expected []ReplayRequest
+	}{
+		{
+			Name: "query",
+			File: "testdata/replay/query.json",
+			Expected: []ReplayRequest{
+				{
+					Operation: "findPetsByStatus",
+					Server:    "0",
+					Method:    http.MethodGet,
+					URL:       "https://petstore3.swagger.io/api/v3/pet/findByStatus?status=available",
+					Headers: http.Header{
+						"Accept":       []string{"application/json"},
+						"Content-Type": []string{"application/json"},
+					},
+				},
+			},
+		},
+		{
+			Name: "mutation",
+			File: "testdata/replay/mutation.json",
+			Expected: []ReplayRequest{
+				{
+					Operation: "addPet",
+					Server:    "0",
+					Method:    http.MethodPost,
+					URL:       "https://petstore3.swagger.io/api/v3/pet",
+					Headers: http.Header{
+						"Accept":       []string{"application/json"},
+						"Content-Type": []string{"application/json"},
+					},
+					Body: replayBody("{\"name\":\"doggie\",\"photoUrls\":[]}\n"),
+				},
+				{
+					Operation: "sendHttpRequest",
+					Method:    http.MethodPost,
+					URL:       "https://example.com/v1",
+					Headers: http.Header{
+						"Content-Type": []string{"application/json"},
+					},
+					Body: replayBody(`{ "id": 1 }`),
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			var buf bytes.Buffer
+			assert.NilError(t, Replay(context.TODO(), &ReplayCommandArguments{
+				Configuration: "testdata/petstore3",
+				File:          tc.File,
+			}, &buf))
+
+			var results []ReplayRequest
+			assert.NilError(t, json.Unmarshal(buf.Bytes(), &results))
+			assert.DeepEqual(t, tc.Expected, results)
+		})
+	}
+
+	t.Run("connector_info", func(t *testing.T) {
+		c := NewHTTPConnector()
+		_, err := c.ParseConfiguration(context.TODO(), "testdata/petstore3")
+		assert.NilError(t, err)
+		_, err = c.ReplayRequest(context.TODO(), []byte(`{"collection":"_connectorInfo","arguments":{},"query":{},"collection_relationships":{}}`))
+		assert.ErrorContains(t, err, "does not call upstream APIs")
+	})
+}
+
+func replayBody(value string) *string {
+	return &value
+}
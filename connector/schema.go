@@ -17,21 +17,48 @@ func (c *HTTPConnector) GetSchema(ctx context.Context, configuration *configurat
 
 // ApplyNDCHttpSchemas applies slice of raw NDC HTTP schemas to the connector
 func (c *HTTPConnector) ApplyNDCHttpSchemas(ctx context.Context, config *configuration.Configuration, schemas []configuration.NDCHttpRuntimeSchema, logger *slog.Logger) error {
+	if config.ContinueOnError && len(schemas) == 0 {
+		return errBuildSchemaFailed
+	}
+
 	httpSchema, metadata, errs := configuration.MergeNDCHttpSchemas(config, schemas)
 	if len(errs) > 0 {
 		printSchemaValidationError(logger, errs)
+		if config.ContinueOnError {
+			if healthySchemas := c.skipFailedSchemas(schemas, errs); len(healthySchemas) < len(schemas) {
+				return c.ApplyNDCHttpSchemas(ctx, config, healthySchemas, logger)
+			}
+		}
+
 		if httpSchema == nil || config.Strict {
 			return errBuildSchemaFailed
 		}
 	}
 
+	registerErrors := map[string][]string{}
 	for _, meta := range metadata {
 		if err := c.upstreams.Register(ctx, &meta, httpSchema); err != nil {
-			return err
+			if !config.ContinueOnError {
+				return err
+			}
+
+			registerErrors[meta.Name] = []string{err.Error()}
 		}
 	}
 
+	// operations of failed upstreams are removed from the schema
+	if len(registerErrors) > 0 {
+		printSchemaValidationError(logger, registerErrors)
+
+		return c.ApplyNDCHttpSchemas(ctx, config, c.skipFailedSchemas(schemas, registerErrors), logger)
+	}
+
 	ndcSchema, procSendHttp := internal.ApplyDefaultConnectorSchema(httpSchema.ToSchemaResponse(), config.ForwardHeaders)
+	if config.SnapshotTest != nil && config.SnapshotTest.Enabled {
+		internal.ApplySnapshotTestSchema(ndcSchema, config.ForwardHeaders)
+	}
+	internal.ApplyConnectorInfoSchema(ndcSchema, config.ForwardHeaders)
+
 	schemaBytes, err := json.Marshal(ndcSchema)
 	if err != nil {
 		return err
@@ -44,6 +71,28 @@ func (c *HTTPConnector) ApplyNDCHttpSchemas(ctx context.Context, config *configu
 	return nil
 }
 
+// skipFailedSchemas records errors of failed schemas and returns healthy schemas.
+func (c *HTTPConnector) skipFailedSchemas(schemas []configuration.NDCHttpRuntimeSchema, errs map[string][]string) []configuration.NDCHttpRuntimeSchema {
+	if c.failedSchemas == nil {
+		c.failedSchemas = map[string][]string{}
+	}
+
+	for name, messages := range errs {
+		c.failedSchemas[name] = append(c.failedSchemas[name], messages...)
+	}
+
+	results := make([]configuration.NDCHttpRuntimeSchema, 0, len(schemas))
+	for _, item := range schemas {
+		if _, failed := errs[item.Name]; failed || item.NDCHttpSchema == nil {
+			continue
+		}
+
+		results = append(results, item)
+	}
+
+	return results
+}
+
 func printSchemaValidationError(logger *slog.Logger, errors map[string][]string) {
 	logger.Error("errors happen when validating NDC HTTP schemas", slog.Any("errors", errors))
 }
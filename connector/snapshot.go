@@ -0,0 +1,80 @@
+package connector
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hasura/ndc-http/connector/internal"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// execute the target operation of the snapshot test and assert the result against the stored snapshot.
+func (c *HTTPConnector) execSnapshotTest(ctx context.Context, operation schema.MutationOperation) (any, error) {
+	args, requests, err := c.explainSnapshotTest(operation)
+	if err != nil {
+		return nil, err
+	}
+
+	client := c.upstreams.CreateHTTPClient(requests)
+	result, _, err := client.Send(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err := c.snapshots.Assert(args.Name, result, args.Update)
+	if err != nil {
+		return nil, schema.InternalServerError(err.Error(), nil)
+	}
+
+	return report, nil
+}
+
+func (c *HTTPConnector) explainSnapshotTest(operation schema.MutationOperation) (*internal.SnapshotTestArguments, *internal.RequestBuilderResults, error) {
+	var args internal.SnapshotTestArguments
+	if err := json.Unmarshal(operation.Arguments, &args); err != nil {
+		return nil, nil, schema.BadRequestError("failed to decode arguments", map[string]any{
+			"cause": err.Error(),
+		})
+	}
+
+	if err := args.Validate(); err != nil {
+		return nil, nil, schema.UnprocessableContentError(err.Error(), nil)
+	}
+
+	if args.Arguments == nil {
+		args.Arguments = map[string]any{}
+	}
+
+	if c.config.ForwardHeaders.ArgumentField != nil && *c.config.ForwardHeaders.ArgumentField != "" {
+		rawArgs, err := decodeMutationArguments(operation)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		headersField := *c.config.ForwardHeaders.ArgumentField
+		if headers, ok := rawArgs[headersField]; ok {
+			if _, ok := args.Arguments[headersField]; !ok {
+				args.Arguments[headersField] = headers
+			}
+		}
+	}
+
+	if err := internal.AuthorizeOperation(c.config, args.Operation, args.Arguments); err != nil {
+		return nil, nil, err
+	}
+
+	operationInfo, metadata, err := c.metadata.GetFunction(args.Operation)
+	if err != nil {
+		operationInfo, metadata, err = c.metadata.GetProcedure(args.Operation)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	requests, err := c.upstreams.BuildRequests(metadata, args.Operation, operationInfo, args.Arguments)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return &args, requests, nil
+}
@@ -4,6 +4,8 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/hasura/ndc-http/connector/internal/contenttype"
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
 	"github.com/hasura/ndc-sdk-go/connector"
 )
 
@@ -17,9 +19,32 @@ type State struct {
 }
 
 type options struct {
-	client *http.Client
+	client          *http.Client
+	fake            bool
+	mock            *mockOptions
+	decoders        []responseDecoderOption
+	secretProviders map[string]SecretProvider
 }
 
+type mockOptions struct {
+	fixturesDir string
+}
+
+type responseDecoderOption struct {
+	pattern string
+	decoder ResponseDecoder
+}
+
+// ResponseDecoder abstracts a decoder of response bodies with a custom content type, e.g. application/vnd.vendor+cbor.
+// The decoded value should be composed of JSON-compatible types, e.g. map[string]any, []any, string, float64 and bool.
+type ResponseDecoder = contenttype.ResponseDecoder
+
+// ResponseDecoderFunc is a function adapter of the ResponseDecoder interface.
+type ResponseDecoderFunc = contenttype.ResponseDecoderFunc
+
+// SecretProvider abstracts a secrets backend which resolves secret references of a scheme, e.g. vault:secret/data/petstore#api_key.
+type SecretProvider = configuration.SecretProvider
+
 var defaultOptions options = options{
 	client: &http.Client{
 		Transport: http.DefaultTransport,
@@ -35,3 +60,53 @@ func WithClient(client *http.Client) Option {
 		opts.client = client
 	}
 }
+
+// WithResponseDecoder registers a custom decoder of response bodies whose content type matches the media type pattern,
+// e.g. application/vnd.vendor+cbor or application/*+cbor. Patterns follow the syntax of [path.Match].
+// Custom decoders take precedence over built-in decoders and are matched in the order of registration.
+func WithResponseDecoder(pattern string, decoder ResponseDecoder) Option {
+	return func(opts *options) {
+		opts.decoders = append(opts.decoders, responseDecoderOption{
+			pattern: pattern,
+			decoder: decoder,
+		})
+	}
+}
+
+// WithSecretProvider registers a custom provider of secret references with the scheme, e.g. gcpsm for gcpsm:projects/p/secrets/s.
+// Custom providers take precedence over providers of the secrets setting with the same scheme.
+func WithSecretProvider(scheme string, provider SecretProvider) Option {
+	return func(opts *options) {
+		providers := make(map[string]SecretProvider, len(opts.secretProviders)+1)
+		for key, value := range opts.secretProviders {
+			providers[key] = value
+		}
+		providers[scheme] = provider
+		opts.secretProviders = providers
+	}
+}
+
+// WithFakeData enables the fake data mode. Functions return generated fake data which conforms to their result types
+// without calling upstream APIs.
+func WithFakeData(enabled bool) Option {
+	return func(opts *options) {
+		opts.fake = enabled
+	}
+}
+
+// WithMockData enables the mock mode. Operations return responses from fixture files or examples of the API spec
+// without calling upstream APIs. Fixture files are read from the directory relative to the configuration directory,
+// or the default mocks directory if empty.
+func WithMockData(enabled bool, fixturesDir string) Option {
+	return func(opts *options) {
+		if !enabled {
+			opts.mock = nil
+
+			return
+		}
+
+		opts.mock = &mockOptions{
+			fixturesDir: fixturesDir,
+		}
+	}
+}
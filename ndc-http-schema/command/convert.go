@@ -11,6 +11,7 @@ import (
 	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
 	"github.com/hasura/ndc-http/ndc-http-schema/schema"
 	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
 	"gopkg.in/yaml.v3"
 )
 
@@ -64,8 +65,30 @@ func CommandConvertToNDCSchema(args *configuration.ConvertCommandArguments, logg
 		slog.Bool("strict", config.Strict),
 		slog.Bool("pure", config.Pure),
 		slog.Bool("no_deprecation", config.NoDeprecation),
+		slog.Bool("no_default_values", config.NoDefaultValues),
+		slog.Bool("enrich_links", config.EnrichLinks),
+		slog.Bool("emit_collections", config.EmitCollections),
+		slog.Bool("response_examples", config.ResponseExamples),
+		slog.Any("filter", config.Filter),
+		slog.Any("naming", config.Naming),
+		slog.String("split_by", string(config.SplitBy)),
 	)
 
+	if config.SplitBy != "" {
+		if _, err := configuration.ParseSplitStrategy(string(config.SplitBy)); err != nil {
+			logger.Error(err.Error())
+
+			return err
+		}
+
+		if config.Output == "" || config.Pure {
+			err := errors.New("the split option requires the output file and can't be used with the pure option")
+			logger.Error(err.Error())
+
+			return err
+		}
+	}
+
 	result, err := configuration.ConvertToNDCSchema(&config, logger)
 
 	if err != nil {
@@ -74,6 +97,18 @@ func CommandConvertToNDCSchema(args *configuration.ConvertCommandArguments, logg
 		return err
 	}
 
+	if config.SplitBy != "" {
+		if err := writeSplitSchemaFiles(result, config.Output, config.SplitBy); err != nil {
+			logger.Error("failed to write split schema files", slog.String("error", err.Error()))
+
+			return err
+		}
+
+		logger.Info("generated successfully", slog.Duration("execution_time", time.Since(start)))
+
+		return nil
+	}
+
 	if config.Output != "" {
 		if config.Pure {
 			err = utils.WriteSchemaFile(config.Output, result.ToSchemaResponse())
@@ -118,3 +153,28 @@ func CommandConvertToNDCSchema(args *configuration.ConvertCommandArguments, logg
 
 	return nil
 }
+
+// write schema files of operation groups next to the output file, and the index of schema files to the output file.
+func writeSplitSchemaFiles(result *schema.NDCHttpSchema, outputPath string, strategy configuration.SplitStrategy) error {
+	groups, err := configuration.SplitNDCSchema(result, strategy)
+	if err != nil {
+		return err
+	}
+
+	indexFiles := make([]map[string]string, 0, len(groups))
+	for _, groupName := range sdkUtils.GetSortedKeys(groups) {
+		groupPath := configuration.GetSplitFilePath(outputPath, groupName)
+		if err := utils.WriteSchemaFile(groupPath, groups[groupName]); err != nil {
+			return err
+		}
+
+		indexFiles = append(indexFiles, map[string]string{
+			"file": filepath.Base(groupPath),
+			"spec": string(schema.NDCSpec),
+		})
+	}
+
+	return utils.WriteSchemaFile(outputPath, map[string]any{
+		"files": indexFiles,
+	})
+}
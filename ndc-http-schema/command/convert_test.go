@@ -5,11 +5,15 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
 	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
 	"gotest.tools/v3/assert"
 )
 
@@ -158,3 +162,91 @@ func TestConvertToNDCSchema(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertToNDCSchemaSplit(t *testing.T) {
+	tempDir := t.TempDir()
+	indexPath := filepath.Join(tempDir, "petstore.yaml")
+	assert.NilError(t, CommandConvertToNDCSchema(&configuration.ConvertCommandArguments{
+		File:    "../openapi/testdata/petstore3/source.json",
+		Output:  indexPath,
+		SplitBy: string(configuration.SplitByTag),
+	}, nopLogger))
+
+	fullSchema, err := configuration.ConvertToNDCSchema(&configuration.ConvertConfig{
+		File: "../openapi/testdata/petstore3/source.json",
+		Spec: schema.OAS3Spec,
+	}, nopLogger)
+	assert.NilError(t, err)
+
+	for _, group := range []string{"pet", "store", "user"} {
+		_, err := os.Stat(filepath.Join(tempDir, "petstore-"+group+".yaml"))
+		assert.NilError(t, err)
+	}
+
+	assert.NilError(t, os.WriteFile(filepath.Join(tempDir, "config.yaml"), []byte("includes:\n  - petstore.yaml\nfiles: []\n"), 0o664))
+	config, err := configuration.ReadConfigurationFile(tempDir)
+	assert.NilError(t, err)
+	assert.Equal(t, 5, len(config.Files))
+	assert.Equal(t, "petstore-default.yaml", config.Files[0].File)
+	assert.Equal(t, schema.NDCSpec, config.Files[0].Spec)
+
+	schemas, errs := configuration.BuildSchemaFromConfig(config, tempDir, nopLogger)
+	assert.Equal(t, 0, len(errs))
+	mergedSchema, _, errs := configuration.MergeNDCHttpSchemas(config, schemas)
+	assert.Equal(t, 0, len(errs))
+	assert.DeepEqual(t, sdkUtils.GetSortedKeys(fullSchema.Functions), sdkUtils.GetSortedKeys(mergedSchema.Functions))
+	assert.DeepEqual(t, sdkUtils.GetSortedKeys(fullSchema.Procedures), sdkUtils.GetSortedKeys(mergedSchema.Procedures))
+
+	assert.Equal(t, filepath.Join(tempDir, "petstore-user.yaml"), schemas[4].Name)
+	_, ok := schemas[4].ObjectTypes["Order"]
+	assert.Assert(t, !ok, "the user schema shouldn't contain unused object types")
+
+	err = CommandConvertToNDCSchema(&configuration.ConvertCommandArguments{
+		File:    "../openapi/testdata/petstore3/source.json",
+		SplitBy: "operation",
+	}, nopLogger)
+	assert.ErrorContains(t, err, "invalid split strategy operation")
+}
+
+func TestConvertToNDCSchemaFromURL(t *testing.T) {
+	sourceBytes, err := os.ReadFile("../openapi/testdata/petstore3/source.json")
+	assert.NilError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+
+			return
+		}
+
+		_, _ = w.Write(sourceBytes)
+	}))
+	defer server.Close()
+
+	t.Setenv("SPEC_TOKEN", "Bearer secret")
+	config := configuration.ConvertConfig{
+		File: server.URL + "/openapi.json",
+		Spec: schema.OAS3Spec,
+		FileHeaders: map[string]sdkUtils.EnvString{
+			"Authorization": sdkUtils.NewEnvStringVariable("SPEC_TOKEN"),
+		},
+		Checksum: "sha256:1dd76ddbb3b64a16fa9bbdc0dacd13a0b858834b96d10ddf91cdd03681f48240",
+		Version:  "1.0.19",
+	}
+
+	result, err := configuration.ConvertToNDCSchema(&config, nopLogger)
+	assert.NilError(t, err)
+	assert.Equal(t, "1.0.19", result.Settings.Version)
+
+	config.Version = "1.0.20"
+	_, err = configuration.ConvertToNDCSchema(&config, nopLogger)
+	assert.ErrorContains(t, err, "the version 1.0.19 of "+config.File+" doesn't match the expected version 1.0.20")
+
+	config.Checksum = "sha256:0000"
+	_, err = configuration.ConvertToNDCSchema(&config, nopLogger)
+	assert.ErrorContains(t, err, "doesn't match the expected checksum sha256:0000")
+
+	config.FileHeaders = nil
+	_, err = configuration.ConvertToNDCSchema(&config, nopLogger)
+	assert.ErrorContains(t, err, "failed to download file from")
+}
@@ -0,0 +1,88 @@
+package command
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+)
+
+// DiffCommandArguments represent available command arguments for the diff command
+type DiffCommandArguments struct {
+	Old            string `arg:""          help:"File path or URL of the old NDC HTTP schema"`
+	New            string `arg:""          help:"File path or URL of the new NDC HTTP schema"`
+	Format         string `default:"text"  enum:"text,json,yaml"                                           help:"The output format, is one of text, json, yaml"`
+	FailOnBreaking bool   `default:"false" help:"Exit with an error if there are breaking changes"`
+}
+
+// DiffSchemas compares two NDC HTTP schemas and prints added, removed and changed operations, arguments and types
+func DiffSchemas(args *DiffCommandArguments, logger *slog.Logger) error {
+	oldSchema, err := readNDCHttpSchemaFile(args.Old)
+	if err != nil {
+		logger.Error(err.Error())
+
+		return err
+	}
+
+	newSchema, err := readNDCHttpSchemaFile(args.New)
+	if err != nil {
+		logger.Error(err.Error())
+
+		return err
+	}
+
+	result := configuration.DiffNDCHttpSchemas(oldSchema, newSchema)
+	if args.Format == "" || args.Format == "text" {
+		result.Render(os.Stdout)
+	} else {
+		format, err := schema.ParseSchemaFileFormat(args.Format)
+		if err != nil {
+			logger.Error("failed to parse format", slog.Any("error", err))
+
+			return err
+		}
+
+		resultBytes, err := utils.MarshalSchema(result, format)
+		if err != nil {
+			logger.Error("failed to encode the schema diff", slog.Any("error", err))
+
+			return err
+		}
+
+		fmt.Fprint(os.Stdout, string(resultBytes))
+	}
+
+	if args.FailOnBreaking && result.HasBreakingChanges() {
+		err := errors.New("detected breaking changes")
+		logger.Error(err.Error())
+
+		return err
+	}
+
+	return nil
+}
+
+// read the NDC HTTP schema from a JSON or YAML file
+func readNDCHttpSchemaFile(filePath string) (*schema.NDCHttpSchema, error) {
+	rawBytes, err := utils.ReadFileFromPath(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := utils.ApplyPatch(rawBytes, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filePath, err)
+	}
+
+	var result schema.NDCHttpSchema
+	if err := json.Unmarshal(jsonBytes, &result); err != nil {
+		return nil, fmt.Errorf("failed to decode the NDC HTTP schema at %s: %w", filePath, err)
+	}
+
+	return &result, nil
+}
@@ -0,0 +1,62 @@
+package command
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestDiffSchemas(t *testing.T) {
+	testCases := []struct {
+		name           string
+		oldPath        string
+		newPath        string
+		format         string
+		failOnBreaking bool
+		errorMsg       string
+	}{
+		{
+			name:     "file_not_found",
+			oldPath:  "foo.json",
+			newPath:  "../configuration/testdata/diff/new.json",
+			errorMsg: "failed to read content from foo.json",
+		},
+		{
+			name:    "text",
+			oldPath: "../configuration/testdata/diff/old.json",
+			newPath: "../configuration/testdata/diff/new.json",
+		},
+		{
+			name:           "yaml",
+			oldPath:        "../openapi/testdata/petstore3/expected.json",
+			newPath:        "../openapi/testdata/petstore3/expected.json",
+			format:         "yaml",
+			failOnBreaking: true,
+		},
+		{
+			name:           "fail_on_breaking",
+			oldPath:        "../configuration/testdata/diff/old.json",
+			newPath:        "../configuration/testdata/diff/new.json",
+			format:         "json",
+			failOnBreaking: true,
+			errorMsg:       "detected breaking changes",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := DiffSchemas(&DiffCommandArguments{
+				Old:            tc.oldPath,
+				New:            tc.newPath,
+				Format:         tc.format,
+				FailOnBreaking: tc.failOnBreaking,
+			}, nopLogger)
+
+			if tc.errorMsg != "" {
+				assert.ErrorContains(t, err, tc.errorMsg)
+			} else {
+				assert.NilError(t, err)
+			}
+		})
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -94,3 +95,27 @@ func assertDeepEqual(t *testing.T, expected any, reality any) {
 		cmp.Exporter(func(t reflect.Type) bool { return true }),
 	)
 }
+
+func TestUpdateCommandUnchangedSpec(t *testing.T) {
+	args := UpdateCommandArguments{
+		Dir: "testdata/patch",
+	}
+	outputPath := args.Dir + "/schema.output.json"
+	assert.NilError(t, UpdateConfiguration(&args, slog.Default(), true))
+
+	// the output file isn't rewritten and converted times are kept if the spec content doesn't change
+	schemas := readRuntimeSchemaFile(t, outputPath)
+	convertedAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for i := range schemas {
+		schemas[i].ConvertedAt = &convertedAt
+	}
+	rawBytes, err := json.MarshalIndent(schemas, "", "  ")
+	assert.NilError(t, err)
+	assert.NilError(t, os.WriteFile(outputPath, rawBytes, 0o664))
+
+	assert.NilError(t, UpdateConfiguration(&args, slog.Default(), true))
+	for _, s := range readRuntimeSchemaFile(t, outputPath) {
+		assert.Equal(t, convertedAt, *s.ConvertedAt)
+		assert.Assert(t, s.Hash != "")
+	}
+}
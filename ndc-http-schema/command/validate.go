@@ -0,0 +1,59 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/configuration"
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+)
+
+// ValidateCommandArguments represent input arguments of the `validate` command
+type ValidateCommandArguments struct {
+	Dir    string `default:"."     env:"HASURA_PLUGIN_CONNECTOR_CONTEXT_PATH"                 help:"The directory where the config.yaml file is present" short:"d"`
+	Format string `default:"text"  enum:"text,json,yaml"                                     help:"The output format, is one of text, json, yaml"`
+	Strict bool   `default:"false" help:"Exit with an error if there are warnings"`
+}
+
+// ValidateConfiguration checks the configuration directory and prints diagnostics without updating any file
+func ValidateConfiguration(args *ValidateCommandArguments, logger *slog.Logger, noColor bool) error {
+	result, err := configuration.LintConfiguration(args.Dir, logger)
+	if err != nil {
+		logger.Error(err.Error())
+
+		return err
+	}
+
+	if args.Format == "" || args.Format == "text" {
+		result.Render(os.Stdout, noColor)
+	} else {
+		format, err := schema.ParseSchemaFileFormat(args.Format)
+		if err != nil {
+			logger.Error("failed to parse format", slog.Any("error", err))
+
+			return err
+		}
+
+		resultBytes, err := utils.MarshalSchema(result, format)
+		if err != nil {
+			logger.Error("failed to encode diagnostics", slog.Any("error", err))
+
+			return err
+		}
+
+		fmt.Fprint(os.Stdout, string(resultBytes))
+	}
+
+	if result.HasError() {
+		return errors.New("detected configuration errors")
+	}
+
+	if args.Strict && result.HasWarning() {
+		return errors.New("detected configuration warnings")
+	}
+
+	return nil
+}
@@ -0,0 +1,43 @@
+package command
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestValidateConfiguration(t *testing.T) {
+	testCases := []struct {
+		name     string
+		dir      string
+		format   string
+		errorMsg string
+	}{
+		{
+			name:     "config_not_found",
+			dir:      "testdata/not_found",
+			errorMsg: "the config.{json,yaml,yml} file does not exist at testdata/not_found",
+		},
+		{
+			name:     "errors",
+			dir:      "../configuration/testdata/lint",
+			format:   "json",
+			errorMsg: "detected configuration errors",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := ValidateConfiguration(&ValidateCommandArguments{
+				Dir:    tc.dir,
+				Format: tc.format,
+			}, nopLogger, true)
+
+			if tc.errorMsg != "" {
+				assert.ErrorContains(t, err, tc.errorMsg)
+			} else {
+				assert.NilError(t, err)
+			}
+		})
+	}
+}
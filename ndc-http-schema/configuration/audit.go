@@ -0,0 +1,67 @@
+package configuration
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/invopop/jsonschema"
+)
+
+// AuditLogSink represents the destination of audit logs.
+type AuditLogSink string
+
+const (
+	// AuditLogSinkStdout writes audit logs to the standard output as JSON lines.
+	AuditLogSinkStdout AuditLogSink = "stdout"
+	// AuditLogSinkFile appends audit logs to a file as JSON lines.
+	AuditLogSinkFile AuditLogSink = "file"
+	// AuditLogSinkOTLP exports audit logs with the OpenTelemetry logs exporter of the connector.
+	AuditLogSinkOTLP AuditLogSink = "otlp"
+)
+
+var auditLogSink_enums = []AuditLogSink{AuditLogSinkStdout, AuditLogSinkFile, AuditLogSinkOTLP}
+
+// JSONSchema is used to generate a custom jsonschema.
+func (j AuditLogSink) JSONSchema() *jsonschema.Schema {
+	enums := make([]any, len(auditLogSink_enums))
+	for i, item := range auditLogSink_enums {
+		enums[i] = item
+	}
+
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: enums,
+	}
+}
+
+// AuditLogSettings hold settings to record every request to upstream services as structured audit logs.
+type AuditLogSettings struct {
+	// Enable audit logs.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+	// The destination of audit logs. Default to stdout
+	Sink AuditLogSink `json:"sink,omitempty" yaml:"sink,omitempty"`
+	// The path of the log file if the sink is file. Relative paths are resolved from the configuration directory. Default to audit.log
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Include request and response bodies in audit logs. Bodies may contain business data.
+	IncludeBodies bool `json:"includeBodies,omitempty" yaml:"includeBodies,omitempty"`
+	// The maximum number of bytes of each logged request and response body. Default to 4096
+	MaxBodyBytes uint `json:"maxBodyBytes,omitempty" yaml:"maxBodyBytes,omitempty"`
+}
+
+// Validate checks if the settings are valid.
+func (als AuditLogSettings) Validate() error {
+	if als.Sink != "" && !slices.Contains(auditLogSink_enums, als.Sink) {
+		return fmt.Errorf("invalid sink %s, expected one of %v", als.Sink, auditLogSink_enums)
+	}
+
+	return nil
+}
+
+// GetSink returns the sink or the default value.
+func (als AuditLogSettings) GetSink() AuditLogSink {
+	if als.Sink == "" {
+		return AuditLogSinkStdout
+	}
+
+	return als.Sink
+}
@@ -0,0 +1,118 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// DefaultRoleHeader is the default header name which the engine forwards the role of the request
+const DefaultRoleHeader = "X-Hasura-Role"
+
+var errAuthorizationRequireForwardHeaders = errors.New("authorization rules require forwardHeaders.enabled and forwardHeaders.argumentField to be set")
+
+// AuthorizationSettings represent connector-level rules which allow or deny operations by the role of the request.
+// The role value is read from the forwarded headers argument.
+type AuthorizationSettings struct {
+	// Name of the forwarded header which contains the role of the request. Default to X-Hasura-Role.
+	RoleHeader string `json:"roleHeader,omitempty" yaml:"roleHeader,omitempty"`
+	// Allow operations if the role doesn't match any rule. Operations are denied by default.
+	AllowUnknownRoles bool `json:"allowUnknownRoles,omitempty" yaml:"allowUnknownRoles,omitempty"`
+	// List of authorization rules.
+	Rules []AuthorizationRule `json:"rules" yaml:"rules"`
+}
+
+// Validate checks if the setting is valid.
+func (as AuthorizationSettings) Validate() error {
+	for i, rule := range as.Rules {
+		if err := rule.Validate(); err != nil {
+			return fmt.Errorf("rules[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// GetRoleHeader returns the role header name or the default value.
+func (as AuthorizationSettings) GetRoleHeader() string {
+	if as.RoleHeader == "" {
+		return DefaultRoleHeader
+	}
+
+	return as.RoleHeader
+}
+
+// IsAllowed checks if the role is permitted to execute the operation.
+// Deny patterns take precedence over allow patterns.
+func (as AuthorizationSettings) IsAllowed(role string, operationName string) bool {
+	matched := false
+	for _, rule := range as.Rules {
+		if !rule.MatchRole(role) {
+			continue
+		}
+
+		matched = true
+		if matchGlobs(rule.Deny, operationName) {
+			return false
+		}
+	}
+
+	if !matched {
+		return as.AllowUnknownRoles
+	}
+
+	for _, rule := range as.Rules {
+		if rule.MatchRole(role) && matchGlobs(rule.Allow, operationName) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AuthorizationRule represents permitted and forbidden operations of roles.
+type AuthorizationRule struct {
+	// Role names which the rule is applied to. Glob patterns are supported, e.g. admin-*.
+	Roles []string `json:"roles" yaml:"roles"`
+	// Glob patterns of function and procedure names to be allowed, e.g. get*, findPets*.
+	Allow []string `json:"allow,omitempty" yaml:"allow,omitempty"`
+	// Glob patterns of function and procedure names to be denied. Deny patterns take precedence over allow patterns.
+	Deny []string `json:"deny,omitempty" yaml:"deny,omitempty"`
+}
+
+// Validate checks if the rule is valid.
+func (ar AuthorizationRule) Validate() error {
+	if len(ar.Roles) == 0 {
+		return errors.New("roles must not be empty")
+	}
+
+	for key, patterns := range map[string][]string{
+		"roles": ar.Roles,
+		"allow": ar.Allow,
+		"deny":  ar.Deny,
+	} {
+		for _, pattern := range patterns {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return fmt.Errorf("%s: invalid glob pattern %s: %w", key, pattern, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// MatchRole checks if the role matches the rule.
+func (ar AuthorizationRule) MatchRole(role string) bool {
+	return matchGlobs(ar.Roles, role)
+}
+
+func matchGlobs(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.TrimSpace(pattern), value); ok {
+			return true
+		}
+	}
+
+	return false
+}
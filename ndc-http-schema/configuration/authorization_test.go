@@ -0,0 +1,51 @@
+package configuration
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestAuthorizationSettings(t *testing.T) {
+	settings := AuthorizationSettings{
+		Rules: []AuthorizationRule{
+			{
+				Roles: []string{"admin"},
+				Allow: []string{"*"},
+				Deny:  []string{"deletePet"},
+			},
+			{
+				Roles: []string{"user", "guest-*"},
+				Allow: []string{"get*", "findPets*"},
+			},
+		},
+	}
+	assert.NilError(t, settings.Validate())
+
+	testCases := []struct {
+		Role      string
+		Operation string
+		Expected  bool
+	}{
+		{Role: "admin", Operation: "addPet", Expected: true},
+		{Role: "admin", Operation: "deletePet", Expected: false},
+		{Role: "user", Operation: "getPetById", Expected: true},
+		{Role: "guest-1", Operation: "findPetsByStatus", Expected: true},
+		{Role: "user", Operation: "addPet", Expected: false},
+		{Role: "unknown", Operation: "getPetById", Expected: false},
+		{Role: "", Operation: "getPetById", Expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Role+"/"+tc.Operation, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, settings.IsAllowed(tc.Role, tc.Operation))
+		})
+	}
+
+	settings.AllowUnknownRoles = true
+	assert.Assert(t, settings.IsAllowed("unknown", "addPet"))
+	assert.Assert(t, !settings.IsAllowed("admin", "deletePet"))
+
+	assert.ErrorContains(t, AuthorizationRule{Roles: []string{"admin"}, Allow: []string{"[a-"}}.Validate(), "invalid glob pattern")
+	assert.ErrorContains(t, AuthorizationRule{}.Validate(), "roles must not be empty")
+}
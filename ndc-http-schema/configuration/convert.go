@@ -1,10 +1,15 @@
 package configuration
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
+	"net/url"
+	"path"
+	"strings"
 
 	"github.com/hasura/ndc-http/ndc-http-schema/openapi"
 	"github.com/hasura/ndc-http/ndc-http-schema/schema"
@@ -13,14 +18,36 @@ import (
 
 // ConvertToNDCSchema converts to NDC HTTP schema from config
 func ConvertToNDCSchema(config *ConvertConfig, logger *slog.Logger) (*schema.NDCHttpSchema, error) {
-	rawContent, err := utils.ReadFileFromPath(config.File)
+	result, _, err := convertToNDCSchema(config, logger)
+
+	return result, err
+}
+
+// convertToNDCSchema converts to NDC HTTP schema from config. Returns the schema and the SHA-256 hash of the spec content.
+func convertToNDCSchema(config *ConvertConfig, logger *slog.Logger) (*schema.NDCHttpSchema, string, error) {
+	rawContent, err := readSpecContent(config)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
-	rawContent, err = utils.ApplyPatch(rawContent, config.PatchBefore)
-	if err != nil {
-		return nil, err
+	return convertSpecContentToNDCSchema(config, rawContent, logger)
+}
+
+// convert the spec content to NDC HTTP schema. Returns the schema and the SHA-256 hash of the spec content.
+func convertSpecContentToNDCSchema(config *ConvertConfig, rawContent []byte, logger *slog.Logger) (*schema.NDCHttpSchema, string, error) {
+	var err error
+	contentHash := sha256.Sum256(rawContent)
+	specHash := hex.EncodeToString(contentHash[:])
+	if config.Checksum != "" && !strings.EqualFold(strings.TrimPrefix(config.Checksum, "sha256:"), specHash) {
+		return nil, "", fmt.Errorf("the checksum sha256:%s of %s doesn't match the expected checksum %s", specHash, config.File, config.Checksum)
+	}
+
+	// GraphQL SDL, WSDL and OData metadata documents aren't JSON, so patches can be applied to the introspection result only
+	if (config.Spec != schema.GraphQLSpec && config.Spec != schema.WSDLSpec && config.Spec != schema.ODataSpec) || len(config.PatchBefore) > 0 {
+		rawContent, err = utils.ApplyPatch(rawContent, config.PatchBefore)
+		if err != nil {
+			return nil, "", err
+		}
 	}
 
 	var result *schema.NDCHttpSchema
@@ -33,29 +60,131 @@ func ConvertToNDCSchema(config *ConvertConfig, logger *slog.Logger) (*schema.NDC
 		AllowedContentTypes: config.AllowedContentTypes,
 		Strict:              config.Strict,
 		NoDeprecation:       config.NoDeprecation,
+		NoDefaultValues:     config.NoDefaultValues,
+		EnrichLinks:         config.EnrichLinks,
+		EmitCollections:     config.EmitCollections,
+		ResponseExamples:    config.ResponseExamples,
+		Filter:              config.Filter,
+		Naming:              config.Naming,
 		Logger:              logger,
 	}
 
+	if config.Filter != nil {
+		if err := config.Filter.Validate(); err != nil {
+			return nil, "", fmt.Errorf("filter: %w", err)
+		}
+	}
+
+	if config.Naming != nil {
+		if err := config.Naming.Validate(); err != nil {
+			return nil, "", fmt.Errorf("naming: %w", err)
+		}
+	}
+
 	switch config.Spec {
 	case schema.OpenAPIv3Spec, schema.OAS3Spec:
 		result, errs = openapi.OpenAPIv3ToNDCSchema(rawContent, options)
 	case schema.OpenAPIv2Spec, (schema.OAS2Spec):
 		result, errs = openapi.OpenAPIv2ToNDCSchema(rawContent, options)
+	case schema.GraphQLSpec:
+		var serverURL string
+		if isGraphQLEndpoint(config.File) {
+			serverURL = config.File
+		}
+		result, errs = openapi.GraphQLToNDCSchema(rawContent, serverURL, options)
+	case schema.WSDLSpec:
+		result, errs = openapi.WSDLToNDCSchema(rawContent, options)
+	case schema.ODataSpec:
+		result, errs = openapi.ODataToNDCSchema(rawContent, options)
+	case schema.AsyncAPISpec:
+		result, errs = openapi.AsyncAPIToNDCSchema(rawContent, options)
+	case schema.PostmanSpec:
+		result, errs = openapi.PostmanToNDCSchema(rawContent, options)
+	case schema.HARSpec:
+		result, errs = openapi.HARToNDCSchema(rawContent, options)
+	case schema.InsomniaSpec:
+		result, errs = openapi.InsomniaToNDCSchema(rawContent, options)
 	case schema.NDCSpec:
 		if err := json.Unmarshal(rawContent, &result); err != nil {
-			return nil, err
+			return nil, "", err
 		}
 	default:
-		return nil, fmt.Errorf("invalid spec %s, expected %+v", config.Spec, []schema.SchemaSpecType{schema.OpenAPIv3Spec, schema.OpenAPIv2Spec, schema.OAS3Spec, schema.OAS2Spec, schema.NDCSpec})
+		return nil, "", fmt.Errorf("invalid spec %s, expected %+v", config.Spec, []schema.SchemaSpecType{schema.OpenAPIv3Spec, schema.OpenAPIv2Spec, schema.OAS3Spec, schema.OAS2Spec, schema.GraphQLSpec, schema.WSDLSpec, schema.ODataSpec, schema.AsyncAPISpec, schema.PostmanSpec, schema.HARSpec, schema.InsomniaSpec, schema.NDCSpec})
 	}
 
 	if result == nil {
-		return nil, errors.Join(errs...)
+		return nil, "", errors.Join(errs...)
 	} else if len(errs) > 0 {
 		logger.Error(errors.Join(errs...).Error())
 	}
 
-	return utils.ApplyPatchToHTTPSchema(result, config.PatchAfter)
+	if config.Version != "" && (result.Settings == nil || result.Settings.Version != config.Version) {
+		var version string
+		if result.Settings != nil {
+			version = result.Settings.Version
+		}
+
+		return nil, "", fmt.Errorf("the version %s of %s doesn't match the expected version %s", version, config.File, config.Version)
+	}
+
+	result, err = utils.ApplyPatchToHTTPSchema(result, config.PatchAfter)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// operations opt in to return redirection responses with the redirect option, e.g. by patches
+	buildRedirectResultTypes(result)
+
+	return result, specHash, nil
+}
+
+// read the spec content from the file path or URL.
+// The schema of a GraphQL endpoint is fetched with the introspection query.
+func readSpecContent(config *ConvertConfig) ([]byte, error) {
+	if config.Spec == schema.GraphQLSpec && isGraphQLEndpoint(config.File) {
+		return openapi.IntrospectGraphQL(config.File)
+	}
+
+	options := utils.ReadFileOptions{
+		Headers: make(map[string]string),
+	}
+	for key, envValue := range config.FileHeaders {
+		value, err := envValue.GetOrDefault("")
+		if err != nil {
+			return nil, fmt.Errorf("fileHeaders.%s: %w", key, err)
+		}
+
+		if value != "" {
+			options.Headers[key] = value
+		}
+	}
+
+	if utils.IsRemoteFilePath(config.File) {
+		if err := config.FileCredentials.evaluate(&options); err != nil {
+			return nil, err
+		}
+	}
+
+	return utils.ReadFileFromPathWithOptions(config.File, options)
+}
+
+// check if the file path is the URL of a GraphQL endpoint instead of a schema file
+func isGraphQLEndpoint(filePath string) bool {
+	if !strings.HasPrefix(filePath, "http://") && !strings.HasPrefix(filePath, "https://") {
+		return false
+	}
+
+	u, err := url.Parse(filePath)
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(path.Ext(u.Path)) {
+	case ".graphql", ".graphqls", ".gql", ".json":
+		return false
+	default:
+		return true
+	}
 }
 
 // ResolveConvertConfigArguments resolves convert config arguments
@@ -85,9 +214,25 @@ func ResolveConvertConfigArguments(config *ConvertConfig, configDir string, args
 		if args.NoDeprecation {
 			config.NoDeprecation = args.NoDeprecation
 		}
+		if args.NoDefaultValues {
+			config.NoDefaultValues = args.NoDefaultValues
+		}
+		if args.EnrichLinks {
+			config.EnrichLinks = args.EnrichLinks
+		}
+		if args.EmitCollections {
+			config.EmitCollections = args.EmitCollections
+		}
+		if args.ResponseExamples {
+			config.ResponseExamples = args.ResponseExamples
+		}
 		if len(args.AllowedContentTypes) > 0 {
 			config.AllowedContentTypes = args.AllowedContentTypes
 		}
+		resolveOperationFilterArguments(config, args)
+		if args.SplitBy != "" {
+			config.SplitBy = SplitStrategy(args.SplitBy)
+		}
 	}
 	if config.Spec == "" {
 		config.Spec = schema.OAS3Spec
@@ -133,3 +278,37 @@ func ResolveConvertConfigArguments(config *ConvertConfig, configDir string, args
 		}
 	}
 }
+
+// override operation filters of the config with command arguments
+func resolveOperationFilterArguments(config *ConvertConfig, args *ConvertCommandArguments) {
+	if len(args.IncludeTag) == 0 && len(args.ExcludeTag) == 0 && len(args.IncludePath) == 0 &&
+		len(args.ExcludePath) == 0 && len(args.Methods) == 0 && len(args.OperationID) == 0 {
+		return
+	}
+
+	filter := openapi.OperationFilter{}
+	if config.Filter != nil {
+		filter = *config.Filter
+	}
+
+	if len(args.IncludeTag) > 0 {
+		filter.IncludeTags = args.IncludeTag
+	}
+	if len(args.ExcludeTag) > 0 {
+		filter.ExcludeTags = args.ExcludeTag
+	}
+	if len(args.IncludePath) > 0 {
+		filter.IncludePaths = args.IncludePath
+	}
+	if len(args.ExcludePath) > 0 {
+		filter.ExcludePaths = args.ExcludePath
+	}
+	if len(args.Methods) > 0 {
+		filter.Methods = args.Methods
+	}
+	if len(args.OperationID) > 0 {
+		filter.OperationIDs = args.OperationID
+	}
+
+	config.Filter = &filter
+}
@@ -0,0 +1,79 @@
+package configuration
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestConvertToNDCSchemaRedirect(t *testing.T) {
+	tempDir := t.TempDir()
+	specPath := filepath.Join(tempDir, "openapi.yaml")
+	assert.NilError(t, os.WriteFile(specPath, []byte(`openapi: 3.0.0
+info:
+  title: auth
+  version: 1.0.0
+servers:
+  - url: https://auth.example.com
+paths:
+  /oauth/authorize:
+    get:
+      operationId: authorize
+      responses:
+        "200":
+          description: The consent page
+          content:
+            application/json:
+              schema:
+                type: string
+        "302":
+          description: Redirect to the consent URL
+  /logout:
+    get:
+      operationId: logout
+      responses:
+        "302":
+          description: Redirect to the home page
+`), 0o664))
+
+	config := &ConvertConfig{
+		File: specPath,
+		Spec: rest.OAS3Spec,
+	}
+
+	// operations which only respond with redirection are skipped
+	result, err := ConvertToNDCSchema(config, slog.Default())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"authorize"}, sdkUtils.GetSortedKeys(result.Functions))
+	assert.DeepEqual(t, schema.NewNamedType(string(rest.ScalarString)).Encode(), result.Functions["authorize"].ResultType)
+	_, ok := result.ObjectTypes[rest.RedirectResultObjectName]
+	assert.Assert(t, !ok)
+
+	patchPath := filepath.Join(tempDir, "patch.yaml")
+	assert.NilError(t, os.WriteFile(patchPath, []byte(`functions:
+  authorize:
+    request:
+      response:
+        redirect: true
+`), 0o664))
+	config.PatchAfter = []utils.PatchConfig{
+		{Path: patchPath, Strategy: utils.PatchStrategyMerge},
+	}
+
+	result, err = ConvertToNDCSchema(config, slog.Default())
+	assert.NilError(t, err)
+	assert.Assert(t, result.Functions["authorize"].Request.Response.Redirect)
+	assert.DeepEqual(t, schema.NewNamedType(rest.RedirectResultObjectName).Encode(), result.Functions["authorize"].ResultType)
+	objectType, ok := result.ObjectTypes[rest.RedirectResultObjectName]
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, []string{"location", "status"}, sdkUtils.GetSortedKeys(objectType.Fields))
+	_, ok = result.ScalarTypes[string(rest.ScalarInt32)]
+	assert.Assert(t, ok)
+}
@@ -0,0 +1,398 @@
+package configuration
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// SchemaChangeKind represents the kind of a schema change.
+type SchemaChangeKind string
+
+const (
+	SchemaChangeAdded   SchemaChangeKind = "added"
+	SchemaChangeRemoved SchemaChangeKind = "removed"
+	SchemaChangeChanged SchemaChangeKind = "changed"
+)
+
+// SchemaChange represents a change between two NDC HTTP schemas.
+type SchemaChange struct {
+	Kind SchemaChangeKind `json:"kind" yaml:"kind"`
+	// The path of the changed element, e.g. functions.getPet.arguments.id or object_types.Pet.fields.name
+	Path    string `json:"path"    yaml:"path"`
+	Message string `json:"message" yaml:"message"`
+	// The change breaks existing queries and mutations, or clients which depend on results
+	Breaking bool `json:"breaking" yaml:"breaking"`
+}
+
+// SchemaDiff represents the list of changes between two NDC HTTP schemas.
+type SchemaDiff struct {
+	Changes []SchemaChange `json:"changes" yaml:"changes"`
+}
+
+// HasBreakingChanges checks if the diff contains any breaking change.
+func (sd SchemaDiff) HasBreakingChanges() bool {
+	return slices.ContainsFunc(sd.Changes, func(change SchemaChange) bool {
+		return change.Breaking
+	})
+}
+
+// Render writes changes in the human-readable format.
+func (sd SchemaDiff) Render(w io.Writer) {
+	if len(sd.Changes) == 0 {
+		_, _ = fmt.Fprintln(w, "No changes")
+
+		return
+	}
+
+	var breakingCount int
+	for _, change := range sd.Changes {
+		label := "         "
+		if change.Breaking {
+			label = "BREAKING "
+			breakingCount++
+		}
+
+		_, _ = fmt.Fprintf(w, "%s%-8s %s: %s\n", label, change.Kind, change.Path, change.Message)
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%d changes, %d breaking\n", len(sd.Changes), breakingCount)
+}
+
+// the change of a type in comparison with the old type
+type typeChange int
+
+const (
+	typeUnchanged typeChange = iota
+	// the type becomes non-null
+	typeNonNull
+	// the type becomes nullable
+	typeNullable
+	typeIncompatible
+)
+
+// DiffNDCHttpSchemas compares two NDC HTTP schemas and classifies breaking changes.
+// Breaking changes are removed operations, arguments, fields and types, new required arguments,
+// type changes which aren't compatible with existing queries or results, and removed enum values.
+func DiffNDCHttpSchemas(oldSchema *rest.NDCHttpSchema, newSchema *rest.NDCHttpSchema) SchemaDiff {
+	differ := &schemaDiffer{
+		oldSchema:   oldSchema,
+		newSchema:   newSchema,
+		inputTypes:  map[string]bool{},
+		outputTypes: map[string]bool{},
+	}
+
+	for _, s := range []*rest.NDCHttpSchema{oldSchema, newSchema} {
+		for _, operations := range []map[string]rest.OperationInfo{s.Functions, s.Procedures} {
+			for _, operation := range operations {
+				differ.collectTypes(s, operation.ResultType, differ.outputTypes)
+				for _, argument := range operation.Arguments {
+					differ.collectTypes(s, argument.Type, differ.inputTypes)
+				}
+			}
+		}
+	}
+
+	differ.diffOperations("functions", oldSchema.Functions, newSchema.Functions)
+	differ.diffOperations("procedures", oldSchema.Procedures, newSchema.Procedures)
+	differ.diffObjectTypes()
+	differ.diffScalarTypes()
+
+	slices.SortStableFunc(differ.changes, func(a, b SchemaChange) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+
+	return SchemaDiff{Changes: differ.changes}
+}
+
+type schemaDiffer struct {
+	oldSchema   *rest.NDCHttpSchema
+	newSchema   *rest.NDCHttpSchema
+	inputTypes  map[string]bool
+	outputTypes map[string]bool
+	changes     []SchemaChange
+}
+
+func (sd *schemaDiffer) addChange(kind SchemaChangeKind, path string, breaking bool, message string) {
+	sd.changes = append(sd.changes, SchemaChange{
+		Kind:     kind,
+		Path:     path,
+		Message:  message,
+		Breaking: breaking,
+	})
+}
+
+// collect names of object types which are reachable from the type
+func (sd *schemaDiffer) collectTypes(ndcSchema *rest.NDCHttpSchema, schemaType schema.Type, results map[string]bool) {
+	name, ok := getNamedTypeName(schemaType)
+	if !ok || results[name] {
+		return
+	}
+
+	objectType, ok := ndcSchema.ObjectTypes[name]
+	if !ok {
+		return
+	}
+
+	results[name] = true
+	for _, field := range objectType.Fields {
+		sd.collectTypes(ndcSchema, field.Type, results)
+	}
+}
+
+func (sd *schemaDiffer) diffOperations(kind string, oldOperations map[string]rest.OperationInfo, newOperations map[string]rest.OperationInfo) {
+	operationKind := strings.TrimSuffix(kind, "s")
+	for _, name := range utils.GetSortedKeys(oldOperations) {
+		path := kind + "." + name
+		oldOperation := oldOperations[name]
+		newOperation, ok := newOperations[name]
+		if !ok {
+			sd.addChange(SchemaChangeRemoved, path, true, fmt.Sprintf("the %s is removed", operationKind))
+
+			continue
+		}
+
+		switch compareSchemaTypes(oldOperation.ResultType, newOperation.ResultType) {
+		case typeUnchanged:
+		case typeNonNull:
+			sd.addChange(SchemaChangeChanged, path+".result_type", false, formatTypeChange(oldOperation.ResultType, newOperation.ResultType))
+		default:
+			sd.addChange(SchemaChangeChanged, path+".result_type", true, formatTypeChange(oldOperation.ResultType, newOperation.ResultType))
+		}
+
+		for _, argName := range utils.GetSortedKeys(oldOperation.Arguments) {
+			argPath := path + ".arguments." + argName
+			oldArgument := oldOperation.Arguments[argName]
+			newArgument, ok := newOperation.Arguments[argName]
+			if !ok {
+				sd.addChange(SchemaChangeRemoved, argPath, true, "the argument is removed")
+
+				continue
+			}
+
+			switch compareSchemaTypes(oldArgument.Type, newArgument.Type) {
+			case typeUnchanged:
+			case typeNullable:
+				sd.addChange(SchemaChangeChanged, argPath, false, formatTypeChange(oldArgument.Type, newArgument.Type))
+			default:
+				sd.addChange(SchemaChangeChanged, argPath, true, formatTypeChange(oldArgument.Type, newArgument.Type))
+			}
+		}
+
+		for _, argName := range utils.GetSortedKeys(newOperation.Arguments) {
+			if _, ok := oldOperation.Arguments[argName]; ok {
+				continue
+			}
+
+			argType := newOperation.Arguments[argName].Type
+			if isNullableType(argType) {
+				sd.addChange(SchemaChangeAdded, path+".arguments."+argName, false, "the optional argument is added")
+			} else {
+				sd.addChange(SchemaChangeAdded, path+".arguments."+argName, true, fmt.Sprintf("the required argument of type %s is added", argType))
+			}
+		}
+
+		if oldOperation.Request != nil && newOperation.Request != nil &&
+			(oldOperation.Request.Method != newOperation.Request.Method || oldOperation.Request.URL != newOperation.Request.URL) {
+			sd.addChange(SchemaChangeChanged, path+".request", false, fmt.Sprintf("the request changes from %s %s to %s %s",
+				strings.ToUpper(oldOperation.Request.Method), oldOperation.Request.URL, strings.ToUpper(newOperation.Request.Method), newOperation.Request.URL))
+		}
+	}
+
+	for _, name := range utils.GetSortedKeys(newOperations) {
+		if _, ok := oldOperations[name]; !ok {
+			sd.addChange(SchemaChangeAdded, kind+"."+name, false, fmt.Sprintf("the %s is added", operationKind))
+		}
+	}
+}
+
+func (sd *schemaDiffer) diffObjectTypes() {
+	for _, name := range utils.GetSortedKeys(sd.oldSchema.ObjectTypes) {
+		path := "object_types." + name
+		oldObject := sd.oldSchema.ObjectTypes[name]
+		newObject, ok := sd.newSchema.ObjectTypes[name]
+		if !ok {
+			sd.addChange(SchemaChangeRemoved, path, true, "the object type is removed")
+
+			continue
+		}
+
+		isInput := sd.inputTypes[name]
+		isOutput := sd.outputTypes[name]
+		for _, fieldName := range utils.GetSortedKeys(oldObject.Fields) {
+			fieldPath := path + ".fields." + fieldName
+			oldField := oldObject.Fields[fieldName]
+			newField, ok := newObject.Fields[fieldName]
+			if !ok {
+				sd.addChange(SchemaChangeRemoved, fieldPath, true, "the field is removed")
+
+				continue
+			}
+
+			change := compareSchemaTypes(oldField.Type, newField.Type)
+			if change == typeUnchanged {
+				continue
+			}
+
+			breaking := change == typeIncompatible || (change == typeNullable && isOutput) || (change == typeNonNull && isInput)
+			sd.addChange(SchemaChangeChanged, fieldPath, breaking, formatTypeChange(oldField.Type, newField.Type))
+		}
+
+		for _, fieldName := range utils.GetSortedKeys(newObject.Fields) {
+			if _, ok := oldObject.Fields[fieldName]; ok {
+				continue
+			}
+
+			fieldType := newObject.Fields[fieldName].Type
+			if isInput && !isNullableType(fieldType) {
+				sd.addChange(SchemaChangeAdded, path+".fields."+fieldName, true, fmt.Sprintf("the required field of type %s is added to the input object", fieldType))
+			} else {
+				sd.addChange(SchemaChangeAdded, path+".fields."+fieldName, false, "the field is added")
+			}
+		}
+	}
+
+	for _, name := range utils.GetSortedKeys(sd.newSchema.ObjectTypes) {
+		if _, ok := sd.oldSchema.ObjectTypes[name]; !ok {
+			sd.addChange(SchemaChangeAdded, "object_types."+name, false, "the object type is added")
+		}
+	}
+}
+
+func (sd *schemaDiffer) diffScalarTypes() {
+	for _, name := range utils.GetSortedKeys(sd.oldSchema.ScalarTypes) {
+		path := "scalar_types." + name
+		oldScalar := sd.oldSchema.ScalarTypes[name]
+		newScalar, ok := sd.newSchema.ScalarTypes[name]
+		if !ok {
+			sd.addChange(SchemaChangeRemoved, path, true, "the scalar type is removed")
+
+			continue
+		}
+
+		if reflect.DeepEqual(oldScalar.Representation, newScalar.Representation) {
+			continue
+		}
+
+		oldEnum, oldErr := oldScalar.Representation.AsEnum()
+		newEnum, newErr := newScalar.Representation.AsEnum()
+		if oldErr == nil && newErr == nil {
+			for _, value := range oldEnum.OneOf {
+				if !slices.Contains(newEnum.OneOf, value) {
+					sd.addChange(SchemaChangeRemoved, path+".enum."+value, true, "the enum value is removed")
+				}
+			}
+
+			for _, value := range newEnum.OneOf {
+				if !slices.Contains(oldEnum.OneOf, value) {
+					sd.addChange(SchemaChangeAdded, path+".enum."+value, false, "the enum value is added")
+				}
+			}
+
+			continue
+		}
+
+		sd.addChange(SchemaChangeChanged, path+".representation", true, fmt.Sprintf("the representation changes from %s to %s",
+			getRepresentationType(oldScalar.Representation), getRepresentationType(newScalar.Representation)))
+	}
+
+	for _, name := range utils.GetSortedKeys(sd.newSchema.ScalarTypes) {
+		if _, ok := sd.oldSchema.ScalarTypes[name]; !ok {
+			sd.addChange(SchemaChangeAdded, "scalar_types."+name, false, "the scalar type is added")
+		}
+	}
+}
+
+// compare the new type with the old type. The change is incompatible if the underlying named type changes,
+// or the nullability of nested types changes in different directions.
+func compareSchemaTypes(oldType schema.Type, newType schema.Type) typeChange {
+	oldValue, oldErr := oldType.InterfaceT()
+	newValue, newErr := newType.InterfaceT()
+	if oldErr != nil || newErr != nil {
+		if reflect.DeepEqual(oldType, newType) {
+			return typeUnchanged
+		}
+
+		return typeIncompatible
+	}
+
+	oldNullable, oldIsNullable := oldValue.(*schema.NullableType)
+	newNullable, newIsNullable := newValue.(*schema.NullableType)
+
+	switch {
+	case oldIsNullable && newIsNullable:
+		return compareSchemaTypes(oldNullable.UnderlyingType, newNullable.UnderlyingType)
+	case oldIsNullable:
+		return mergeTypeChanges(typeNonNull, compareSchemaTypes(oldNullable.UnderlyingType, newType))
+	case newIsNullable:
+		return mergeTypeChanges(typeNullable, compareSchemaTypes(oldType, newNullable.UnderlyingType))
+	}
+
+	switch o := oldValue.(type) {
+	case *schema.ArrayType:
+		n, ok := newValue.(*schema.ArrayType)
+		if !ok {
+			return typeIncompatible
+		}
+
+		return compareSchemaTypes(o.ElementType, n.ElementType)
+	case *schema.NamedType:
+		n, ok := newValue.(*schema.NamedType)
+		if !ok || n.Name != o.Name {
+			return typeIncompatible
+		}
+
+		return typeUnchanged
+	default:
+		if reflect.DeepEqual(oldType, newType) {
+			return typeUnchanged
+		}
+
+		return typeIncompatible
+	}
+}
+
+func mergeTypeChanges(a typeChange, b typeChange) typeChange {
+	switch {
+	case b == typeUnchanged || a == b:
+		return a
+	case a == typeUnchanged:
+		return b
+	default:
+		return typeIncompatible
+	}
+}
+
+func isNullableType(schemaType schema.Type) bool {
+	ty, err := schemaType.InterfaceT()
+	if err != nil {
+		return false
+	}
+
+	_, ok := ty.(*schema.NullableType)
+
+	return ok
+}
+
+func formatTypeChange(oldType schema.Type, newType schema.Type) string {
+	return fmt.Sprintf("the type changes from %s to %s", oldType, newType)
+}
+
+func getRepresentationType(representation schema.TypeRepresentation) string {
+	if representation == nil {
+		return "none"
+	}
+
+	ty, err := representation.Type()
+	if err != nil {
+		return "unknown"
+	}
+
+	return string(ty)
+}
@@ -0,0 +1,62 @@
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestDiffNDCHttpSchemas(t *testing.T) {
+	readSchema := func(name string) *rest.NDCHttpSchema {
+		rawBytes, err := os.ReadFile(filepath.Join("testdata/diff", name))
+		assert.NilError(t, err)
+
+		var result rest.NDCHttpSchema
+		assert.NilError(t, json.Unmarshal(rawBytes, &result))
+
+		return &result
+	}
+
+	oldSchema := readSchema("old.json")
+	newSchema := readSchema("new.json")
+
+	t.Run("no_changes", func(t *testing.T) {
+		result := DiffNDCHttpSchemas(oldSchema, oldSchema)
+		assert.Equal(t, 0, len(result.Changes))
+		assert.Assert(t, !result.HasBreakingChanges())
+
+		var buf bytes.Buffer
+		result.Render(&buf)
+		assert.Equal(t, "No changes\n", buf.String())
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		expectedBytes, err := os.ReadFile("testdata/diff/expected.txt")
+		assert.NilError(t, err)
+
+		result := DiffNDCHttpSchemas(oldSchema, newSchema)
+		assert.Assert(t, result.HasBreakingChanges())
+
+		var buf bytes.Buffer
+		result.Render(&buf)
+		assert.Equal(t, string(expectedBytes), buf.String())
+	})
+
+	t.Run("non_breaking", func(t *testing.T) {
+		extendedSchema := readSchema("old.json")
+		extendedSchema.Functions["listPets"] = extendedSchema.Functions["listOrders"]
+		getPet := extendedSchema.Functions["getPet"]
+		getPet.ResultType = schema.NewNamedType("Pet").Encode()
+		extendedSchema.Functions["getPet"] = getPet
+
+		result := DiffNDCHttpSchemas(oldSchema, extendedSchema)
+		assert.Equal(t, 2, len(result.Changes))
+		assert.Assert(t, !result.HasBreakingChanges())
+	})
+}
@@ -0,0 +1,111 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// buildResponseEnrichFields adds enriched fields to item object types of operations with response enrich settings.
+// Enriched fields are nullable because the item object types may be shared with other operations.
+func buildResponseEnrichFields(restSchema *rest.NDCHttpSchema) error {
+	var errs []error
+	for _, operations := range []map[string]rest.OperationInfo{restSchema.Functions, restSchema.Procedures} {
+		for _, name := range utils.GetSortedKeys(operations) {
+			op := operations[name]
+			if op.Request == nil {
+				continue
+			}
+
+			for i, enrich := range op.Request.Response.Enrich {
+				if err := buildResponseEnrichField(restSchema, op.ResultType, enrich); err != nil {
+					errs = append(errs, fmt.Errorf("%s.request.response.enrich[%d]: %w", name, i, err))
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func buildResponseEnrichField(restSchema *rest.NDCHttpSchema, resultType schema.Type, enrich rest.ResponseEnrichSettings) error {
+	if enrich.Field == "" || enrich.KeyField == "" || enrich.Argument == "" {
+		return errors.New("field, keyField and argument are required")
+	}
+
+	target, ok := restSchema.Functions[enrich.Operation]
+	if !ok {
+		return fmt.Errorf("function %s does not exist", enrich.Operation)
+	}
+
+	if _, ok := target.Arguments[enrich.Argument]; !ok {
+		return fmt.Errorf("argument %s of function %s does not exist", enrich.Argument, enrich.Operation)
+	}
+
+	itemTypeName, err := getItemObjectTypeName(resultType)
+	if err != nil {
+		return err
+	}
+
+	itemType, ok := restSchema.ObjectTypes[itemTypeName]
+	if !ok {
+		return fmt.Errorf("expected object type, got %s", itemTypeName)
+	}
+
+	if _, ok := itemType.Fields[enrich.KeyField]; !ok {
+		return fmt.Errorf("key field %s does not exist in object %s", enrich.KeyField, itemTypeName)
+	}
+
+	if _, ok := itemType.Fields[enrich.Field]; ok {
+		return fmt.Errorf("field %s already exists in object %s", enrich.Field, itemTypeName)
+	}
+
+	enrichType := unwrapNullableType(target.ResultType)
+	if enrich.BatchSize > 0 {
+		arrayType, ok := enrichType.Interface().(*schema.ArrayType)
+		if !ok {
+			return fmt.Errorf("the result type of function %s must be an array to be called in batches", enrich.Operation)
+		}
+
+		enrichType = unwrapNullableType(arrayType.ElementType)
+	}
+
+	itemType.Fields[enrich.Field] = rest.ObjectField{
+		ObjectField: schema.ObjectField{
+			Description: utils.ToPtr("The result of " + enrich.Operation),
+			Type:        schema.NewNullableType(enrichType.Interface()).Encode(),
+		},
+	}
+
+	return nil
+}
+
+// getItemObjectTypeName gets the name of the object type of the result or items of the array result.
+func getItemObjectTypeName(resultType schema.Type) (string, error) {
+	switch t := resultType.Interface().(type) {
+	case *schema.NullableType:
+		return getItemObjectTypeName(t.UnderlyingType)
+	case *schema.ArrayType:
+		named, ok := unwrapNullableType(t.ElementType).Interface().(*schema.NamedType)
+		if !ok {
+			return "", errors.New("expected an array of objects")
+		}
+
+		return named.Name, nil
+	case *schema.NamedType:
+		return t.Name, nil
+	default:
+		return "", errors.New("expected an object or an array of objects")
+	}
+}
+
+func unwrapNullableType(input schema.Type) schema.Type {
+	if t, ok := input.Interface().(*schema.NullableType); ok {
+		return unwrapNullableType(t.UnderlyingType)
+	}
+
+	return input
+}
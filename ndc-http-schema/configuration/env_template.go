@@ -0,0 +1,243 @@
+package configuration
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/invopop/jsonschema"
+	"gopkg.in/yaml.v3"
+)
+
+// EnvTemplateMode represents the mode to render {{env "NAME"}} templates in string values of configuration and schema files.
+type EnvTemplateMode string
+
+const (
+	// EnvTemplateModeStrict fails to load files if any environment variable of templates isn't set and has no default value.
+	EnvTemplateModeStrict EnvTemplateMode = "strict"
+	// EnvTemplateModeLenient renders unset environment variables as default values or empty strings.
+	EnvTemplateModeLenient EnvTemplateMode = "lenient"
+)
+
+var envTemplateMode_enums = []EnvTemplateMode{EnvTemplateModeStrict, EnvTemplateModeLenient}
+
+// JSONSchema is used to generate a custom jsonschema.
+func (j EnvTemplateMode) JSONSchema() *jsonschema.Schema {
+	enums := make([]any, len(envTemplateMode_enums))
+	for i, item := range envTemplateMode_enums {
+		enums[i] = item
+	}
+
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: enums,
+	}
+}
+
+// Validate checks if the mode is valid. The empty mode disables templates.
+func (j EnvTemplateMode) Validate() error {
+	if j != "" && !slices.Contains(envTemplateMode_enums, j) {
+		return fmt.Errorf("invalid mode %s, expected one of %v", j, envTemplateMode_enums)
+	}
+
+	return nil
+}
+
+// matches {{env "NAME"}} and {{env "NAME" "default"}}. Other placeholders, e.g. {{env.NAME}} of argument presets, are kept.
+var envTemplateRegex = regexp.MustCompile(`\{\{\s*env\s+("(?:[^"\\]|\\.)*")(?:\s+("(?:[^"\\]|\\.)*"))?\s*\}\}`)
+
+// RenderEnvTemplates replaces {{env "NAME"}} templates in the string with values of environment variables.
+// The optional second argument is the default value if the variable isn't set, e.g. {{env "REGION" "us-east-1"}}.
+func RenderEnvTemplates(value string, mode EnvTemplateMode) (string, error) {
+	if mode == "" {
+		return value, nil
+	}
+
+	var err error
+	result := envTemplateRegex.ReplaceAllStringFunc(value, func(match string) string {
+		if err != nil {
+			return match
+		}
+
+		groups := envTemplateRegex.FindStringSubmatch(match)
+		name, unquoteErr := strconv.Unquote(groups[1])
+		if unquoteErr != nil {
+			err = fmt.Errorf("invalid template %s: %w", match, unquoteErr)
+
+			return match
+		}
+
+		if envValue, ok := os.LookupEnv(name); ok && envValue != "" {
+			return envValue
+		}
+
+		if groups[2] != "" {
+			defaultValue, unquoteErr := strconv.Unquote(groups[2])
+			if unquoteErr != nil {
+				err = fmt.Errorf("invalid template %s: %w", match, unquoteErr)
+
+				return match
+			}
+
+			return defaultValue
+		}
+
+		if mode == EnvTemplateModeStrict {
+			err = fmt.Errorf("the environment variable %s is not set", name)
+		}
+
+		return ""
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return result, nil
+}
+
+// RenderSchemaEnvTemplates renders {{env "NAME"}} templates in string values of NDC HTTP schemas.
+// Templates are kept in schema output files, so secrets aren't written to files, and rendered when the connector loads schemas.
+func RenderSchemaEnvTemplates(schemas []NDCHttpRuntimeSchema, mode EnvTemplateMode) error {
+	if mode == "" {
+		return nil
+	}
+
+	for i, item := range schemas {
+		if item.NDCHttpSchema == nil {
+			continue
+		}
+
+		rawBytes, err := json.Marshal(item.NDCHttpSchema)
+		if err != nil {
+			return err
+		}
+
+		if !bytes.Contains(rawBytes, []byte("{{")) {
+			continue
+		}
+
+		decoder := json.NewDecoder(bytes.NewReader(rawBytes))
+		decoder.UseNumber()
+
+		var document any
+		if err := decoder.Decode(&document); err != nil {
+			return err
+		}
+
+		document, err = renderEnvTemplateValues(document, mode, "")
+		if err != nil {
+			return fmt.Errorf("%s: %w", item.Name, err)
+		}
+
+		renderedBytes, err := json.Marshal(document)
+		if err != nil {
+			return err
+		}
+
+		var ndcSchema rest.NDCHttpSchema
+		if err := json.Unmarshal(renderedBytes, &ndcSchema); err != nil {
+			return fmt.Errorf("%s: %w", item.Name, err)
+		}
+
+		item.NDCHttpSchema = &ndcSchema
+		schemas[i] = item
+	}
+
+	return nil
+}
+
+// render templates of the configuration file and decode the rendered document to the configuration.
+func renderConfigurationEnvTemplates(rawBytes []byte, isJSON bool, mode EnvTemplateMode) (*Configuration, error) {
+	var document any
+	var err error
+	if isJSON {
+		decoder := json.NewDecoder(bytes.NewReader(rawBytes))
+		decoder.UseNumber()
+		err = decoder.Decode(&document)
+	} else {
+		err = yaml.Unmarshal(rawBytes, &document)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	document, err = renderEnvTemplateValues(document, mode, "")
+	if err != nil {
+		return nil, fmt.Errorf("envTemplate: %w", err)
+	}
+
+	var config Configuration
+	if isJSON {
+		renderedBytes, err := json.Marshal(document)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := json.Unmarshal(renderedBytes, &config); err != nil {
+			return nil, err
+		}
+	} else {
+		renderedBytes, err := yaml.Marshal(document)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := yaml.Unmarshal(renderedBytes, &config); err != nil {
+			return nil, err
+		}
+	}
+
+	return &config, nil
+}
+
+// render templates in string values of the decoded document recursively. Keys are kept.
+func renderEnvTemplateValues(value any, mode EnvTemplateMode, fieldPath string) (any, error) {
+	switch v := value.(type) {
+	case string:
+		result, err := RenderEnvTemplates(v, mode)
+		if err != nil && fieldPath != "" {
+			return nil, fmt.Errorf("%s: %w", fieldPath, err)
+		}
+
+		return result, err
+	case map[string]any:
+		for key, item := range v {
+			rendered, err := renderEnvTemplateValues(item, mode, joinFieldPath(fieldPath, key))
+			if err != nil {
+				return nil, err
+			}
+
+			v[key] = rendered
+		}
+
+		return v, nil
+	case []any:
+		for i, item := range v {
+			rendered, err := renderEnvTemplateValues(item, mode, fmt.Sprintf("%s[%d]", fieldPath, i))
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = rendered
+		}
+
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+func joinFieldPath(parent string, key string) string {
+	if parent == "" {
+		return key
+	}
+
+	return parent + "." + key
+}
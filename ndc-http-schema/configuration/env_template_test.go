@@ -0,0 +1,186 @@
+package configuration
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestRenderEnvTemplates(t *testing.T) {
+	t.Setenv("TEMPLATE_TENANT_ID", "tenant-1")
+	t.Setenv("TEMPLATE_REGION", "eu")
+
+	testCases := []struct {
+		Name     string
+		Value    string
+		Mode     EnvTemplateMode
+		Expected string
+		Error    string
+	}{
+		{
+			Name:     "disabled",
+			Value:    `https://{{env "TEMPLATE_REGION"}}.example.com`,
+			Expected: `https://{{env "TEMPLATE_REGION"}}.example.com`,
+		},
+		{
+			Name:     "multiple_variables",
+			Value:    `https://{{ env "TEMPLATE_REGION" }}.example.com/tenants/{{env "TEMPLATE_TENANT_ID"}}`,
+			Mode:     EnvTemplateModeStrict,
+			Expected: "https://eu.example.com/tenants/tenant-1",
+		},
+		{
+			Name:     "default_value",
+			Value:    `{{env "TEMPLATE_UNSET" "default"}}`,
+			Mode:     EnvTemplateModeStrict,
+			Expected: "default",
+		},
+		{
+			Name:     "other_placeholders",
+			Value:    `{{env.TEMPLATE_REGION}} {{ $.arguments.id }}`,
+			Mode:     EnvTemplateModeStrict,
+			Expected: `{{env.TEMPLATE_REGION}} {{ $.arguments.id }}`,
+		},
+		{
+			Name:     "lenient_unset",
+			Value:    `Bearer {{env "TEMPLATE_UNSET"}}`,
+			Mode:     EnvTemplateModeLenient,
+			Expected: "Bearer ",
+		},
+		{
+			Name:  "strict_unset",
+			Value: `Bearer {{env "TEMPLATE_UNSET"}}`,
+			Mode:  EnvTemplateModeStrict,
+			Error: "the environment variable TEMPLATE_UNSET is not set",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			result, err := RenderEnvTemplates(tc.Value, tc.Mode)
+			if tc.Error != "" {
+				assert.ErrorContains(t, err, tc.Error)
+
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, tc.Expected, result)
+		})
+	}
+}
+
+func TestReadConfigurationFileEnvTemplates(t *testing.T) {
+	t.Setenv("TEMPLATE_TENANT_ID", "tenant-1")
+
+	testCases := []struct {
+		Name     string
+		FileName string
+		Content  string
+		Expected string
+		Error    string
+	}{
+		{
+			Name:     "yaml_strict",
+			FileName: "config.yaml",
+			Content: `envTemplate: strict
+files:
+  - file: "https://example.com/{{env \"TEMPLATE_TENANT_ID\"}}/openapi.json"
+    spec: oas3
+`,
+			Expected: "https://example.com/tenant-1/openapi.json",
+		},
+		{
+			Name:     "json_lenient",
+			FileName: "config.json",
+			Content: `{
+  "envTemplate": "lenient",
+  "files": [{ "file": "https://example.com/{{env \"TEMPLATE_UNSET\"}}openapi.json", "spec": "oas3" }]
+}`,
+			Expected: "https://example.com/openapi.json",
+		},
+		{
+			Name:     "disabled",
+			FileName: "config.yaml",
+			Content: `files:
+  - file: "{{env \"TEMPLATE_TENANT_ID\"}}.json"
+    spec: oas3
+`,
+			Expected: `{{env "TEMPLATE_TENANT_ID"}}.json`,
+		},
+		{
+			Name:     "strict_unset",
+			FileName: "config.yaml",
+			Content: `envTemplate: strict
+files:
+  - file: "https://example.com/{{env \"TEMPLATE_UNSET\"}}/openapi.json"
+    spec: oas3
+`,
+			Error: "envTemplate: files[0].file: the environment variable TEMPLATE_UNSET is not set",
+		},
+		{
+			Name:     "invalid_mode",
+			FileName: "config.yaml",
+			Content: `envTemplate: unknown
+files: []
+`,
+			Error: "envTemplate: invalid mode unknown",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			configDir := t.TempDir()
+			assert.NilError(t, os.WriteFile(filepath.Join(configDir, tc.FileName), []byte(tc.Content), 0o664))
+
+			config, err := ReadConfigurationFile(configDir)
+			if tc.Error != "" {
+				assert.ErrorContains(t, err, tc.Error)
+
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.Equal(t, 1, len(config.Files))
+			assert.Equal(t, tc.Expected, config.Files[0].File)
+		})
+	}
+}
+
+func TestRenderSchemaEnvTemplates(t *testing.T) {
+	t.Setenv("TEMPLATE_TENANT_ID", "tenant-1")
+	t.Setenv("TEMPLATE_API_KEY", "secret")
+
+	schemas := []NDCHttpRuntimeSchema{
+		{
+			Name: "tenant",
+			Runtime: rest.RuntimeSettings{
+				Timeout: 10,
+			},
+			NDCHttpSchema: &rest.NDCHttpSchema{
+				Settings: &rest.NDCHttpSettings{
+					Servers: []rest.ServerConfig{
+						{URL: utils.NewEnvStringValue(`https://example.com/tenants/{{env "TEMPLATE_TENANT_ID"}}`)},
+					},
+					Headers: map[string]utils.EnvString{
+						"X-Api-Key":   utils.NewEnvStringValue(`{{env "TEMPLATE_TENANT_ID"}}:{{env "TEMPLATE_API_KEY"}}`),
+						"X-Forwarded": utils.NewEnvStringValue("{{env.TEMPLATE_API_KEY}}"),
+					},
+				},
+			},
+		},
+	}
+
+	assert.NilError(t, RenderSchemaEnvTemplates(schemas, EnvTemplateModeStrict))
+	settings := schemas[0].Settings
+	assert.Equal(t, "https://example.com/tenants/tenant-1", *settings.Servers[0].URL.Value)
+	assert.Equal(t, "tenant-1:secret", *settings.Headers["X-Api-Key"].Value)
+	assert.Equal(t, "{{env.TEMPLATE_API_KEY}}", *settings.Headers["X-Forwarded"].Value)
+	assert.Equal(t, uint(10), schemas[0].Runtime.Timeout)
+
+	schemas[0].Settings.Servers[0].URL = utils.NewEnvStringValue(`{{env "TEMPLATE_UNSET"}}`)
+	assert.ErrorContains(t, RenderSchemaEnvTemplates(schemas, EnvTemplateModeStrict), "tenant: settings.servers[0].url.value: the environment variable TEMPLATE_UNSET is not set")
+}
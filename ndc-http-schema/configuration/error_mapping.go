@@ -0,0 +1,93 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// ErrorMappingSettings hold settings to map HTTP status codes of upstream error responses to NDC error codes.
+// By default, upstream 4xx errors are returned as 422 Unprocessable Content and 5xx errors are passed through.
+type ErrorMappingSettings struct {
+	// Return status codes of upstream 4xx errors as is instead of 422 Unprocessable Content.
+	PassThrough bool `json:"passThrough,omitempty" yaml:"passThrough,omitempty"`
+	// Return null results instead of errors if upstream services respond 404 Not Found to GET functions.
+	NotFoundAsNull bool `json:"notFoundAsNull,omitempty" yaml:"notFoundAsNull,omitempty"`
+	// Mapping rules of upstream status codes. Keys are status codes, e.g. 404, or status ranges, e.g. 4XX.
+	// Exact status codes have higher priority than ranges.
+	Statuses map[string]ErrorMappingRule `json:"statuses,omitempty" yaml:"statuses,omitempty"`
+}
+
+// ErrorMappingRule represents the mapping rule of an upstream status code.
+type ErrorMappingRule struct {
+	// The status code of the NDC error. Keep the default mapping if empty.
+	Status int `json:"status,omitempty" jsonschema:"minimum=400,maximum=599" yaml:"status,omitempty"`
+	// Return a null result instead of an error. Only functions are affected.
+	Null bool `json:"null,omitempty" yaml:"null,omitempty"`
+}
+
+// Validate checks if the setting is valid.
+func (ems ErrorMappingSettings) Validate() error {
+	for key, rule := range ems.Statuses {
+		if !isErrorStatusPattern(key) {
+			return fmt.Errorf("statuses.%s: %w", key, errors.New("expected a status code or range from 400 to 599, e.g. 404 or 4XX"))
+		}
+
+		if rule.Status != 0 && (rule.Status < 400 || rule.Status > 599) {
+			return fmt.Errorf("statuses.%s.status: expected a status code from 400 to 599, got %d", key, rule.Status)
+		}
+	}
+
+	return nil
+}
+
+// FindRule finds the mapping rule of the upstream status code.
+// The exact status code is matched first, then the status range, e.g. 4XX.
+func (ems ErrorMappingSettings) FindRule(statusCode int) (ErrorMappingRule, bool) {
+	if rule, ok := ems.Statuses[strconv.Itoa(statusCode)]; ok {
+		return rule, true
+	}
+
+	rule, ok := ems.Statuses[strconv.Itoa(statusCode/100)+"XX"]
+
+	return rule, ok
+}
+
+// StatusCode returns the NDC status code of the upstream error status.
+// The default status code is returned if there is no rule for the status.
+func (ems ErrorMappingSettings) StatusCode(statusCode int, defaultStatusCode int) int {
+	if rule, ok := ems.FindRule(statusCode); ok && rule.Status > 0 {
+		return rule.Status
+	}
+
+	if ems.PassThrough && statusCode >= 400 && statusCode < 500 {
+		return statusCode
+	}
+
+	return defaultStatusCode
+}
+
+// IsNull checks if the upstream error status of the function is returned as a null result.
+func (ems ErrorMappingSettings) IsNull(statusCode int, method string) bool {
+	if rule, ok := ems.FindRule(statusCode); ok && rule.Null {
+		return true
+	}
+
+	return ems.NotFoundAsNull && statusCode == http.StatusNotFound && strings.EqualFold(method, http.MethodGet)
+}
+
+func isErrorStatusPattern(key string) bool {
+	if len(key) != 3 {
+		return false
+	}
+
+	if key[1:] == "XX" {
+		return key[0] == '4' || key[0] == '5'
+	}
+
+	code, err := strconv.Atoi(key)
+
+	return err == nil && code >= 400 && code <= 599
+}
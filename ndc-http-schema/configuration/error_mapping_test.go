@@ -0,0 +1,43 @@
+package configuration
+
+import (
+	"net/http"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestErrorMappingSettings(t *testing.T) {
+	settings := ErrorMappingSettings{
+		PassThrough:    true,
+		NotFoundAsNull: true,
+		Statuses: map[string]ErrorMappingRule{
+			"404": {Status: http.StatusNotFound},
+			"410": {Null: true},
+			"5XX": {Status: http.StatusBadGateway},
+		},
+	}
+	assert.NilError(t, settings.Validate())
+
+	assert.Equal(t, http.StatusNotFound, settings.StatusCode(http.StatusNotFound, http.StatusUnprocessableEntity))
+	assert.Equal(t, http.StatusUnauthorized, settings.StatusCode(http.StatusUnauthorized, http.StatusUnprocessableEntity))
+	assert.Equal(t, http.StatusBadGateway, settings.StatusCode(http.StatusServiceUnavailable, http.StatusServiceUnavailable))
+	assert.Equal(t, http.StatusUnprocessableEntity, ErrorMappingSettings{}.StatusCode(http.StatusUnauthorized, http.StatusUnprocessableEntity))
+
+	assert.Assert(t, settings.IsNull(http.StatusNotFound, "get"))
+	assert.Assert(t, !settings.IsNull(http.StatusNotFound, http.MethodPost))
+	assert.Assert(t, settings.IsNull(http.StatusGone, http.MethodPost))
+	assert.Assert(t, !settings.IsNull(http.StatusBadRequest, http.MethodGet))
+
+	for key, expected := range map[string]string{
+		"200": "statuses.200: expected a status code or range",
+		"4xx": "statuses.4xx: expected a status code or range",
+		"3XX": "statuses.3XX: expected a status code or range",
+	} {
+		err := ErrorMappingSettings{Statuses: map[string]ErrorMappingRule{key: {}}}.Validate()
+		assert.ErrorContains(t, err, expected)
+	}
+
+	err := ErrorMappingSettings{Statuses: map[string]ErrorMappingRule{"404": {Status: 200}}}.Validate()
+	assert.ErrorContains(t, err, "statuses.404.status: expected a status code from 400 to 599, got 200")
+}
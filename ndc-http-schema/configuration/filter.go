@@ -0,0 +1,94 @@
+package configuration
+
+import (
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// add comparison operators of filter settings to scalar types of filterable fields,
+// so the engine allows predicates on those fields.
+func applyFilterComparisonOperators(config *Configuration, ndcSchema *rest.NDCHttpSchema) {
+	for fnName, fn := range ndcSchema.Functions {
+		if fn.Request == nil || fn.Request.Filter == nil {
+			continue
+		}
+
+		for fieldPath, field := range fn.Request.Filter.Fields {
+			segments := strings.Split(fieldPath, ".")
+			if !fn.Collection && config.ForwardHeaders.Enabled && config.ForwardHeaders.ResponseHeaders != nil {
+				segments = append([]string{config.ForwardHeaders.ResponseHeaders.ResultField}, segments...)
+			}
+
+			scalarName, ok := findFieldScalarType(ndcSchema, fn.ResultType, segments)
+			if !ok {
+				slog.Warn(fmt.Sprintf("function %s: the filter field %s isn't a scalar field of the result type", fnName, fieldPath))
+
+				continue
+			}
+
+			scalar := ndcSchema.ScalarTypes[scalarName]
+			operators := maps.Clone(scalar.ComparisonOperators)
+			if operators == nil {
+				operators = map[string]schema.ComparisonOperatorDefinition{}
+			}
+
+			for _, op := range field.GetOperators() {
+				if _, ok := operators[op]; ok {
+					continue
+				}
+
+				switch op {
+				case "_eq":
+					operators[op] = schema.NewComparisonOperatorEqual().Encode()
+				case "_in":
+					operators[op] = schema.NewComparisonOperatorIn().Encode()
+				default:
+					operators[op] = schema.NewComparisonOperatorCustom(schema.NewNamedType(scalarName)).Encode()
+				}
+			}
+
+			scalar.ComparisonOperators = operators
+			ndcSchema.ScalarTypes[scalarName] = scalar
+		}
+	}
+}
+
+// find the scalar type name of the field at the path of the type. Nullable and array types are unwrapped.
+func findFieldScalarType(ndcSchema *rest.NDCHttpSchema, fieldType schema.Type, segments []string) (string, bool) {
+	ty, err := fieldType.InterfaceT()
+	if err != nil {
+		return "", false
+	}
+
+	switch t := ty.(type) {
+	case *schema.NullableType:
+		return findFieldScalarType(ndcSchema, t.UnderlyingType, segments)
+	case *schema.ArrayType:
+		return findFieldScalarType(ndcSchema, t.ElementType, segments)
+	case *schema.NamedType:
+		if len(segments) == 0 {
+			_, ok := ndcSchema.ScalarTypes[t.Name]
+
+			return t.Name, ok
+		}
+
+		objectType, ok := ndcSchema.ObjectTypes[t.Name]
+		if !ok {
+			return "", false
+		}
+
+		field, ok := objectType.Fields[segments[0]]
+		if !ok {
+			return "", false
+		}
+
+		return findFieldScalarType(ndcSchema, field.Type, segments[1:])
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,144 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ForwardRequestHeaderMapping represents a rule to rename and transform a forwarded header before sending it to upstream services,
+// e.g. X-Hasura-User-Id -> X-On-Behalf-Of.
+type ForwardRequestHeaderMapping struct {
+	// The name of the incoming header, case-insensitive.
+	Name string `json:"name" yaml:"name"`
+	// The new name of the header sent to upstream services. The name is unchanged if empty.
+	Rename string `json:"rename,omitempty" yaml:"rename,omitempty"`
+	// The static prefix to be prepended to the header value, e.g. Bearer.
+	Prefix string `json:"prefix,omitempty" yaml:"prefix,omitempty"`
+	// The static suffix to be appended to the header value.
+	Suffix string `json:"suffix,omitempty" yaml:"suffix,omitempty"`
+}
+
+// ForwardResponseHeaderMapping represents a rule to rename or filter response headers by a name pattern.
+type ForwardResponseHeaderMapping struct {
+	// The regular expression to match names of response headers, case-insensitive, e.g. ^X-RateLimit-(.*)$
+	Pattern string `json:"pattern" yaml:"pattern"`
+	// The new name of matched headers. Capture groups can be referenced by $1, ${name}, e.g. RateLimit-$1.
+	// The name is unchanged if empty.
+	Rename string `json:"rename,omitempty" yaml:"rename,omitempty"`
+	// Drop matched headers from the response.
+	Exclude bool `json:"exclude,omitempty" yaml:"exclude,omitempty"`
+}
+
+// ForwardHeadersMapper applies mapping rules of forwarded request and response headers.
+type ForwardHeadersMapper struct {
+	requestMappings  map[string]ForwardRequestHeaderMapping
+	responseMappings []compiledResponseHeaderMapping
+}
+
+type compiledResponseHeaderMapping struct {
+	regex   *regexp.Regexp
+	rename  string
+	exclude bool
+}
+
+// NewForwardHeadersMapper compiles mapping rules of forwarded headers. Returns nil if there is no rule.
+func NewForwardHeadersMapper(settings ForwardHeadersSettings) (*ForwardHeadersMapper, error) {
+	var responseMappings []ForwardResponseHeaderMapping
+	if settings.ResponseHeaders != nil {
+		responseMappings = settings.ResponseHeaders.Mappings
+	}
+
+	if !settings.Enabled || (len(settings.RequestMappings) == 0 && len(responseMappings) == 0) {
+		return nil, nil
+	}
+
+	result := &ForwardHeadersMapper{
+		requestMappings: make(map[string]ForwardRequestHeaderMapping),
+	}
+
+	for i, mapping := range settings.RequestMappings {
+		if mapping.Name == "" {
+			return nil, fmt.Errorf("requestMappings[%d]: %w", i, errors.New("name must not be empty"))
+		}
+
+		key := strings.ToLower(mapping.Name)
+		if _, ok := result.requestMappings[key]; ok {
+			return nil, fmt.Errorf("requestMappings[%d]: duplicated header %s", i, mapping.Name)
+		}
+
+		result.requestMappings[key] = mapping
+	}
+
+	for i, mapping := range responseMappings {
+		if mapping.Pattern == "" {
+			return nil, fmt.Errorf("responseHeaders.mappings[%d]: %w", i, errors.New("pattern must not be empty"))
+		}
+
+		regex, err := regexp.Compile("(?i)" + mapping.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("responseHeaders.mappings[%d]: invalid pattern: %w", i, err)
+		}
+
+		result.responseMappings = append(result.responseMappings, compiledResponseHeaderMapping{
+			regex:   regex,
+			rename:  mapping.Rename,
+			exclude: mapping.Exclude,
+		})
+	}
+
+	return result, nil
+}
+
+// MapRequestHeaders renames and transforms forwarded headers before sending them to upstream services.
+// Headers without any mapping rule are forwarded unchanged.
+func (fhm *ForwardHeadersMapper) MapRequestHeaders(headers map[string]string) map[string]string {
+	if fhm == nil || len(fhm.requestMappings) == 0 || len(headers) == 0 {
+		return headers
+	}
+
+	result := make(map[string]string, len(headers))
+	for key, value := range headers {
+		mapping, ok := fhm.requestMappings[strings.ToLower(key)]
+		if !ok {
+			result[key] = value
+
+			continue
+		}
+
+		if mapping.Rename != "" {
+			key = mapping.Rename
+		}
+
+		result[key] = mapping.Prefix + value + mapping.Suffix
+	}
+
+	return result
+}
+
+// MapResponseHeader applies the first rule which matches the name of the response header.
+// Returns the new header name and false if the header is excluded.
+func (fhm *ForwardHeadersMapper) MapResponseHeader(name string) (string, bool) {
+	if fhm == nil {
+		return name, true
+	}
+
+	for _, mapping := range fhm.responseMappings {
+		if !mapping.regex.MatchString(name) {
+			continue
+		}
+
+		if mapping.exclude {
+			return "", false
+		}
+
+		if mapping.rename == "" {
+			return name, true
+		}
+
+		return mapping.regex.ReplaceAllString(name, mapping.rename), true
+	}
+
+	return name, true
+}
@@ -0,0 +1,93 @@
+package configuration
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestForwardHeadersMapper(t *testing.T) {
+	mapper, err := NewForwardHeadersMapper(ForwardHeadersSettings{Enabled: true})
+	assert.NilError(t, err)
+	assert.Assert(t, mapper == nil)
+	assert.DeepEqual(t, map[string]string{"X-Foo": "bar"}, mapper.MapRequestHeaders(map[string]string{"X-Foo": "bar"}))
+
+	name, ok := mapper.MapResponseHeader("X-Foo")
+	assert.Assert(t, ok)
+	assert.Equal(t, "X-Foo", name)
+
+	mapper, err = NewForwardHeadersMapper(ForwardHeadersSettings{
+		Enabled: true,
+		RequestMappings: []ForwardRequestHeaderMapping{
+			{Name: "X-Hasura-User-Id", Rename: "X-On-Behalf-Of"},
+			{Name: "x-hasura-token", Rename: "Authorization", Prefix: "Bearer "},
+			{Name: "X-Tenant", Suffix: ".example.com"},
+		},
+		ResponseHeaders: &ForwardResponseHeadersSettings{
+			HeadersField: "headers",
+			ResultField:  "response",
+			Mappings: []ForwardResponseHeaderMapping{
+				{Pattern: "^X-Internal-", Exclude: true},
+				{Pattern: "^X-RateLimit-(.*)$", Rename: "RateLimit-$1"},
+				{Pattern: "^X-Request-Id$"},
+			},
+		},
+	})
+	assert.NilError(t, err)
+
+	assert.DeepEqual(t, map[string]string{
+		"X-On-Behalf-Of": "1",
+		"Authorization":  "Bearer abc",
+		"X-Tenant":       "foo.example.com",
+		"X-Foo":          "bar",
+	}, mapper.MapRequestHeaders(map[string]string{
+		"x-hasura-user-id": "1",
+		"X-Hasura-Token":   "abc",
+		"X-Tenant":         "foo",
+		"X-Foo":            "bar",
+	}))
+
+	testCases := []struct {
+		Input    string
+		Expected string
+		Excluded bool
+	}{
+		{Input: "X-Internal-Trace", Excluded: true},
+		{Input: "x-ratelimit-remaining", Expected: "RateLimit-remaining"},
+		{Input: "X-Request-Id", Expected: "X-Request-Id"},
+		{Input: "Content-Type", Expected: "Content-Type"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			name, ok := mapper.MapResponseHeader(tc.Input)
+			assert.Equal(t, !tc.Excluded, ok)
+			assert.Equal(t, tc.Expected, name)
+		})
+	}
+
+	_, err = NewForwardHeadersMapper(ForwardHeadersSettings{
+		Enabled:         true,
+		RequestMappings: []ForwardRequestHeaderMapping{{Rename: "X-Foo"}},
+	})
+	assert.ErrorContains(t, err, "requestMappings[0]: name must not be empty")
+
+	_, err = NewForwardHeadersMapper(ForwardHeadersSettings{
+		Enabled:         true,
+		RequestMappings: []ForwardRequestHeaderMapping{{Name: "X-Foo"}, {Name: "x-foo"}},
+	})
+	assert.ErrorContains(t, err, "requestMappings[1]: duplicated header x-foo")
+
+	var settings ForwardHeadersSettings
+	err = json.Unmarshal([]byte(`{
+		"enabled": true,
+		"argumentField": "headers",
+		"responseHeaders": {
+			"headersField": "headers",
+			"resultField": "response",
+			"mappings": [{ "pattern": "[a-" }]
+		}
+	}`), &settings)
+	assert.ErrorContains(t, err, "forwardHeaders.responseHeaders.mappings[0]: invalid pattern")
+}
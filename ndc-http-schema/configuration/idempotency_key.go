@@ -0,0 +1,72 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"slices"
+	"strings"
+)
+
+// DefaultIdempotencyKeyHeader is the default header name of generated idempotency keys.
+const DefaultIdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotencyKeySettings hold settings to generate and attach an idempotency key to procedure requests,
+// so upstream services don't double-create resources when requests are retried.
+// The key is generated once per operation and reused across retries.
+type IdempotencyKeySettings struct {
+	// The header name of the idempotency key. Default to Idempotency-Key.
+	Header string `json:"header,omitempty" yaml:"header,omitempty"`
+	// HTTP methods of procedure requests which the key is attached to.
+	// Default to POST if both methods and operations are empty.
+	Methods []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+	// Glob patterns of procedure names which the key is attached to regardless of the HTTP method, e.g. create*.
+	Operations []string `json:"operations,omitempty" yaml:"operations,omitempty"`
+}
+
+// Validate checks if the setting is valid.
+func (iks IdempotencyKeySettings) Validate() error {
+	if strings.ContainsAny(iks.Header, " \t\r\n:") {
+		return fmt.Errorf("header: invalid header name %q", iks.Header)
+	}
+
+	for i, method := range iks.Methods {
+		if strings.TrimSpace(method) == "" {
+			return fmt.Errorf("methods[%d]: %w", i, errors.New("method must not be empty"))
+		}
+	}
+
+	for i, pattern := range iks.Operations {
+		if _, err := path.Match(strings.TrimSpace(pattern), ""); err != nil {
+			return fmt.Errorf("operations[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+// GetHeader returns the header name of the idempotency key or the default value.
+func (iks IdempotencyKeySettings) GetHeader() string {
+	if iks.Header == "" {
+		return DefaultIdempotencyKeyHeader
+	}
+
+	return iks.Header
+}
+
+// Matches checks if the idempotency key should be attached to the request of the procedure.
+func (iks IdempotencyKeySettings) Matches(operationName string, method string) bool {
+	if matchGlobs(iks.Operations, operationName) {
+		return true
+	}
+
+	methods := iks.Methods
+	if len(methods) == 0 && len(iks.Operations) == 0 {
+		methods = []string{http.MethodPost}
+	}
+
+	return slices.ContainsFunc(methods, func(m string) bool {
+		return strings.EqualFold(strings.TrimSpace(m), method)
+	})
+}
@@ -0,0 +1,482 @@
+package configuration
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// LintSeverity represents the severity of a lint diagnostic.
+type LintSeverity string
+
+const (
+	// LintSeverityError represents problems which make the connector fail at runtime.
+	LintSeverityError LintSeverity = "error"
+	// LintSeverityWarning represents problems which may make some operations fail or behave unexpectedly.
+	LintSeverityWarning LintSeverity = "warning"
+)
+
+// Codes of lint diagnostics.
+const (
+	LintCodeInvalidSpec              = "invalid_spec"
+	LintCodeInvalidSchema            = "invalid_schema"
+	LintCodeInvalidSettings          = "invalid_settings"
+	LintCodeInvalidRuntimeSetting    = "invalid_runtime_setting"
+	LintCodeMissingEnvVariable       = "missing_env_variable"
+	LintCodeUndefinedSecurityScheme  = "undefined_security_scheme"
+	LintCodeHeaderForwardingRequired = "header_forwarding_required"
+	LintCodeConflictingType          = "conflicting_type"
+	LintCodeUnsupportedContentType   = "unsupported_content_type"
+)
+
+// LintDiagnostic represents a problem of the configuration or schema files with the suggested action.
+type LintDiagnostic struct {
+	Severity LintSeverity `json:"severity"       yaml:"severity"`
+	Code     string       `json:"code"           yaml:"code"`
+	File     string       `json:"file,omitempty" yaml:"file,omitempty"`
+	Path     string       `json:"path,omitempty" yaml:"path,omitempty"`
+	Message  string       `json:"message"        yaml:"message"`
+	Hint     string       `json:"hint,omitempty" yaml:"hint,omitempty"`
+}
+
+// LintResult holds diagnostics of the configuration directory.
+type LintResult struct {
+	Diagnostics []LintDiagnostic `json:"diagnostics" yaml:"diagnostics"`
+}
+
+// HasError checks if there is any diagnostic with the error severity.
+func (lr LintResult) HasError() bool {
+	return slices.ContainsFunc(lr.Diagnostics, func(d LintDiagnostic) bool {
+		return d.Severity == LintSeverityError
+	})
+}
+
+// HasWarning checks if there is any diagnostic with the warning severity.
+func (lr LintResult) HasWarning() bool {
+	return slices.ContainsFunc(lr.Diagnostics, func(d LintDiagnostic) bool {
+		return d.Severity == LintSeverityWarning
+	})
+}
+
+// Render writes diagnostics in the human-readable format.
+func (lr LintResult) Render(w io.Writer, noColor bool) {
+	if len(lr.Diagnostics) == 0 {
+		_, _ = fmt.Fprintln(w, "No problems found")
+
+		return
+	}
+
+	var errorCount int
+	for _, diag := range lr.Diagnostics {
+		if diag.Severity == LintSeverityError {
+			errorCount++
+			writeErrorIf(w, "   ", noColor)
+		} else {
+			writeWarningIf(w, " ", noColor)
+		}
+
+		_, _ = fmt.Fprintf(w, "[%s] ", diag.Code)
+		if diag.File != "" {
+			_, _ = fmt.Fprintf(w, "%s ", diag.File)
+		}
+
+		if diag.Path != "" {
+			_, _ = fmt.Fprintf(w, "%s: ", diag.Path)
+		}
+
+		_, _ = fmt.Fprintln(w, diag.Message)
+		if diag.Hint != "" {
+			_, _ = fmt.Fprintf(w, "          hint: %s\n", diag.Hint)
+		}
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%d errors, %d warnings\n", errorCount, len(lr.Diagnostics)-errorCount)
+}
+
+// LintConfiguration checks the configuration and schema files for problems that the connector would only surface at runtime,
+// such as missing environment variables, undefined security schemes, conflicting type names across files,
+// operations with unsupported content types and invalid retry or timeout values.
+// The configuration directory isn't modified.
+func LintConfiguration(configurationDir string, logger *slog.Logger) (*LintResult, error) {
+	config, err := ReadConfigurationFile(configurationDir)
+	if err != nil {
+		return nil, err
+	}
+
+	linter := &configLinter{
+		reportedVariables: map[string]bool{},
+	}
+
+	schemas := make([]NDCHttpRuntimeSchema, 0, len(config.Files))
+	existedFileIDs := []string{}
+
+	for i, file := range config.Files {
+		fileID := file.File
+		if slices.Contains(existedFileIDs, fileID) {
+			fileID += "_" + strconv.Itoa(i)
+		}
+		existedFileIDs = append(existedFileIDs, fileID)
+
+		linter.lintRuntimeSettings(fileID, &file)
+
+		ndcSchema, _, err := buildSchemaFile(config, configurationDir, &file, logger)
+		if err != nil {
+			linter.addError(LintCodeInvalidSpec, fileID, "", err.Error(), "Check the spec file and convert options of the file")
+		}
+
+		if ndcSchema == nil {
+			continue
+		}
+
+		schemas = append(schemas, NDCHttpRuntimeSchema{
+			Name:          fileID,
+			NDCHttpSchema: ndcSchema,
+		})
+	}
+
+	mergedSchema, _, errs := MergeNDCHttpSchemas(config, schemas)
+	for _, name := range utils.GetSortedKeys(errs) {
+		for _, msg := range errs[name] {
+			linter.addError(LintCodeInvalidSchema, name, "", msg, "")
+		}
+	}
+
+	if mergedSchema == nil {
+		return &linter.result, nil
+	}
+
+	linter.lintConflictingTypes(schemas)
+
+	for _, item := range schemas {
+		linter.lintSecuritySchemes(item.Name, item.NDCHttpSchema)
+		linter.lintContentTypes(item.Name, item.NDCHttpSchema)
+	}
+
+	validator, err := ValidateConfiguration(config, configurationDir, schemas, mergedSchema, logger, true)
+	if err != nil {
+		return nil, err
+	}
+
+	linter.addValidatorDiagnostics(validator)
+
+	return &linter.result, nil
+}
+
+type configLinter struct {
+	result            LintResult
+	reportedVariables map[string]bool
+}
+
+// validate timeout, retry and other runtime settings of the config item.
+// Values are only evaluated if all environment variables are set.
+func (cl *configLinter) lintRuntimeSettings(fileID string, item *ConfigItem) {
+	var hasMissingVariable bool
+	checkVariable := func(path string, variable *string, hasValue bool) {
+		if variable == nil || hasValue || os.Getenv(*variable) != "" {
+			return
+		}
+
+		hasMissingVariable = true
+		cl.addMissingVariable(fileID, path, *variable)
+	}
+
+	checkEnvInt := func(path string, value *utils.EnvInt) {
+		if value != nil {
+			checkVariable(path, value.Variable, value.Value != nil)
+		}
+	}
+
+	checkEnvFloat := func(path string, value *utils.EnvFloat) {
+		if value != nil {
+			checkVariable(path, value.Variable, value.Value != nil)
+		}
+	}
+
+	checkEnvInt("timeout", item.Timeout)
+	checkEnvInt("maxResponseBytes", item.MaxResponseBytes)
+	checkEnvInt("maxDecodeDurationMs", item.MaxDecodeDurationMs)
+	checkEnvInt("compressionMinBytes", item.CompressionMinBytes)
+
+	if item.Retry != nil {
+		checkEnvInt("retry.times", &item.Retry.Times)
+		checkEnvInt("retry.delay", &item.Retry.Delay)
+		checkEnvInt("retry.maxDelay", item.Retry.MaxDelay)
+		checkEnvFloat("retry.multiplier", item.Retry.Multiplier)
+		checkEnvFloat("retry.jitter", item.Retry.Jitter)
+	}
+
+	if item.RateLimit != nil {
+		checkEnvFloat("rateLimit.requestsPerSecond", &item.RateLimit.RequestsPerSecond)
+		checkEnvInt("rateLimit.burst", item.RateLimit.Burst)
+	}
+
+	if item.Timeouts != nil {
+		checkEnvInt("timeouts.function", item.Timeouts.Function)
+		checkEnvInt("timeouts.procedure", item.Timeouts.Procedure)
+		for _, name := range utils.GetSortedKeys(item.Timeouts.Operations) {
+			value := item.Timeouts.Operations[name]
+			checkEnvInt("timeouts.operations."+name, &value)
+		}
+	}
+
+	if hasMissingVariable {
+		return
+	}
+
+	if _, err := item.GetRuntimeSettings(); err != nil {
+		// joined errors are reported one by one
+		for _, msg := range strings.Split(err.Error(), "\n") {
+			cl.addError(LintCodeInvalidRuntimeSetting, fileID, "", msg, "Timeouts and retry values must be positive, and retry http status must be in between 400 and 599")
+		}
+	}
+
+	if _, err := NewOperationTimeouts(item.Timeouts); err != nil {
+		cl.addError(LintCodeInvalidRuntimeSetting, fileID, "", err.Error(), "Timeouts must be positive numbers of seconds")
+	}
+}
+
+// report object and scalar types which have the same name but different definitions in many schema files.
+// The merged schema keeps the type of the first file.
+func (cl *configLinter) lintConflictingTypes(schemas []NDCHttpRuntimeSchema) {
+	objectOwners := map[string]string{}
+	objectTypes := map[string]rest.ObjectType{}
+	scalarOwners := map[string]string{}
+	scalarTypes := schema.SchemaResponseScalarTypes{}
+
+	for _, item := range schemas {
+		for _, name := range utils.GetSortedKeys(item.ObjectTypes) {
+			object := item.ObjectTypes[name]
+			owner, ok := objectOwners[name]
+			if !ok {
+				objectOwners[name] = item.Name
+				objectTypes[name] = object
+
+				continue
+			}
+
+			if !reflect.DeepEqual(objectTypes[name], object) {
+				cl.addWarning(LintCodeConflictingType, item.Name, "object_types."+name,
+					fmt.Sprintf("the object type conflicts with the type of %s and is ignored", owner),
+					"Set naming.typePrefix of the file to avoid collisions")
+			}
+		}
+
+		for _, name := range utils.GetSortedKeys(item.ScalarTypes) {
+			if rest.IsDefaultScalar(name) {
+				continue
+			}
+
+			scalar := item.ScalarTypes[name]
+			owner, ok := scalarOwners[name]
+			if !ok {
+				scalarOwners[name] = item.Name
+				scalarTypes[name] = scalar
+
+				continue
+			}
+
+			if !reflect.DeepEqual(scalarTypes[name], scalar) {
+				cl.addWarning(LintCodeConflictingType, item.Name, "scalar_types."+name,
+					fmt.Sprintf("the scalar type conflicts with the type of %s and is ignored", owner),
+					"Set naming.typePrefix of the file to avoid collisions")
+			}
+		}
+	}
+}
+
+// report security requirements which reference security schemes that aren't defined in settings and servers.
+func (cl *configLinter) lintSecuritySchemes(fileID string, ndcSchema *rest.NDCHttpSchema) {
+	if ndcSchema.Settings == nil {
+		return
+	}
+
+	definedSchemes := map[string]bool{}
+	for key := range ndcSchema.Settings.SecuritySchemes {
+		definedSchemes[key] = true
+	}
+
+	for _, server := range ndcSchema.Settings.Servers {
+		for key := range server.SecuritySchemes {
+			definedSchemes[key] = true
+		}
+	}
+
+	checkSecurities := func(path string, securities rest.AuthSecurities) {
+		for _, security := range securities {
+			name := security.Name()
+			if name == "" || definedSchemes[name] {
+				continue
+			}
+
+			cl.addError(LintCodeUndefinedSecurityScheme, fileID, path,
+				fmt.Sprintf("the security scheme %s is not defined", name),
+				"Add the security scheme to settings.securitySchemes, or remove the security requirement")
+		}
+	}
+
+	checkSecurities("settings.security", ndcSchema.Settings.Security)
+	for i, server := range ndcSchema.Settings.Servers {
+		checkSecurities(fmt.Sprintf("settings.servers[%d].security", i), server.Security)
+	}
+
+	for _, op := range getSortedOperations(ndcSchema) {
+		if op.Info.Request != nil {
+			checkSecurities(op.Path+".request.security", op.Info.Request.Security)
+		}
+	}
+}
+
+// report operations whose request or response content types can't be encoded or decoded by the connector.
+func (cl *configLinter) lintContentTypes(fileID string, ndcSchema *rest.NDCHttpSchema) {
+	for _, op := range getSortedOperations(ndcSchema) {
+		req := op.Info.Request
+		if req == nil || req.GraphQL != nil || req.SOAP != nil || req.GRPC != nil || req.Protocol == rest.ProtocolGRPC {
+			continue
+		}
+
+		if req.RequestBody != nil && !isRequestContentTypeSupported(ndcSchema, op.Info, req) {
+			cl.addError(LintCodeUnsupportedContentType, fileID, op.Path+".request.requestBody.contentType",
+				"the request content type "+req.RequestBody.ContentType+" is not supported",
+				"Use a JSON, XML, form, multipart, text or binary content type, or patch the request body of the operation")
+		}
+
+		contentType := parseContentType(req.Response.ContentType)
+		if contentType != "" && !isResponseContentTypeSupported(contentType) {
+			cl.addWarning(LintCodeUnsupportedContentType, fileID, op.Path+".request.response.contentType",
+				"the response content type "+req.Response.ContentType+" is not supported",
+				"Register a custom response decoder for the content type, or patch the response of the operation")
+		}
+	}
+}
+
+func (cl *configLinter) addValidatorDiagnostics(validator *ConfigValidator) {
+	for _, name := range utils.GetSortedKeys(validator.errors) {
+		for _, msg := range validator.errors[name] {
+			cl.addError(LintCodeInvalidSettings, name, "", strings.TrimSpace(msg), "")
+		}
+	}
+
+	for _, name := range utils.GetSortedKeys(validator.warnings) {
+		for _, msg := range validator.warnings[name] {
+			cl.addWarning(LintCodeInvalidSettings, name, "", msg, "")
+		}
+	}
+
+	for _, variable := range utils.GetSortedKeys(validator.requiredVariables) {
+		cl.addMissingVariable("", "", variable)
+	}
+
+	if len(validator.requiredHeadersForwarding) > 0 && (!validator.config.ForwardHeaders.Enabled ||
+		validator.config.ForwardHeaders.ArgumentField == nil || *validator.config.ForwardHeaders.ArgumentField == "") {
+		schemes := utils.GetSortedKeys(validator.requiredHeadersForwarding)
+		cl.addWarning(LintCodeHeaderForwardingRequired, "", "forwardHeaders",
+			fmt.Sprintf("the Authorization header must be forwarded for the following authentication schemes: %v", schemes),
+			"Enable forwardHeaders and set forwardHeaders.argumentField. See https://github.com/hasura/ndc-http/blob/main/docs/authentication.md#headers-forwarding")
+	}
+}
+
+func (cl *configLinter) addMissingVariable(fileID string, path string, variable string) {
+	if cl.reportedVariables[variable] {
+		return
+	}
+
+	cl.reportedVariables[variable] = true
+	cl.addError(LintCodeMissingEnvVariable, fileID, path,
+		fmt.Sprintf("the environment variable %s is not set", variable),
+		"Add the variable to the environment of the connector, e.g. envMapping of connector.yaml")
+}
+
+func (cl *configLinter) addError(code string, fileID string, path string, message string, hint string) {
+	cl.result.Diagnostics = append(cl.result.Diagnostics, LintDiagnostic{
+		Severity: LintSeverityError,
+		Code:     code,
+		File:     fileID,
+		Path:     path,
+		Message:  message,
+		Hint:     hint,
+	})
+}
+
+func (cl *configLinter) addWarning(code string, fileID string, path string, message string, hint string) {
+	cl.result.Diagnostics = append(cl.result.Diagnostics, LintDiagnostic{
+		Severity: LintSeverityWarning,
+		Code:     code,
+		File:     fileID,
+		Path:     path,
+		Message:  message,
+		Hint:     hint,
+	})
+}
+
+type lintOperation struct {
+	Path string
+	Info rest.OperationInfo
+}
+
+func getSortedOperations(ndcSchema *rest.NDCHttpSchema) []lintOperation {
+	results := make([]lintOperation, 0, len(ndcSchema.Functions)+len(ndcSchema.Procedures))
+	for _, name := range utils.GetSortedKeys(ndcSchema.Functions) {
+		results = append(results, lintOperation{Path: "functions." + name, Info: ndcSchema.Functions[name]})
+	}
+
+	for _, name := range utils.GetSortedKeys(ndcSchema.Procedures) {
+		results = append(results, lintOperation{Path: "procedures." + name, Info: ndcSchema.Procedures[name]})
+	}
+
+	return results
+}
+
+func isRequestContentTypeSupported(ndcSchema *rest.NDCHttpSchema, operation rest.OperationInfo, req *rest.Request) bool {
+	contentType := parseContentType(req.RequestBody.ContentType)
+	switch {
+	case contentType == "", contentType == rest.ContentTypeOctetStream, contentType == rest.ContentTypeFormURLEncoded,
+		restUtils.IsContentTypeJSON(contentType), restUtils.IsContentTypeXML(contentType),
+		restUtils.IsContentTypeText(contentType), restUtils.IsContentTypeMultipartForm(contentType),
+		restUtils.IsContentTypeNdJSON(contentType), restUtils.IsContentTypeMsgPack(contentType):
+		return true
+	case restUtils.IsContentTypeProtobuf(contentType):
+		return req.Protobuf != nil && req.Protobuf.RequestMessage != ""
+	default:
+		// binary bodies are sent as is with any content type
+		bodyInfo, ok := operation.Arguments[rest.BodyKey]
+		if !ok {
+			return true
+		}
+
+		name, ok := getNamedTypeName(bodyInfo.Type)
+		if !ok {
+			return false
+		}
+
+		scalar, ok := ndcSchema.ScalarTypes[name]
+		if !ok {
+			return false
+		}
+
+		_, err := scalar.Representation.AsBytes()
+
+		return err == nil
+	}
+}
+
+func isResponseContentTypeSupported(contentType string) bool {
+	return restUtils.IsContentTypeJSON(contentType) || restUtils.IsContentTypeXML(contentType) ||
+		restUtils.IsContentTypeText(contentType) || restUtils.IsContentTypeNdJSON(contentType) ||
+		restUtils.IsContentTypeBinary(contentType)
+}
+
+func parseContentType(input string) string {
+	contentType, _, _ := strings.Cut(input, ";")
+
+	return strings.TrimSpace(contentType)
+}
@@ -0,0 +1,46 @@
+package configuration
+
+import (
+	"encoding/json"
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLintConfiguration(t *testing.T) {
+	t.Run("diagnostics", func(t *testing.T) {
+		expectedBytes, err := os.ReadFile("testdata/lint/expected.json")
+		assert.NilError(t, err)
+
+		var expected LintResult
+		assert.NilError(t, json.Unmarshal(expectedBytes, &expected))
+
+		result, err := LintConfiguration("testdata/lint", slog.Default())
+		assert.NilError(t, err)
+		assert.DeepEqual(t, expected, *result)
+		assert.Assert(t, result.HasError())
+		assert.Assert(t, result.HasWarning())
+	})
+
+	t.Run("env_variables", func(t *testing.T) {
+		t.Setenv("LINT_RETRY_TIMES", "-1")
+		t.Setenv("LINT_PET_STORE_URL", "https://pets.example.com")
+		t.Setenv("LINT_PET_STORE_API_KEY", "secret")
+
+		result, err := LintConfiguration("testdata/lint", slog.Default())
+		assert.NilError(t, err)
+		assert.Assert(t, !slices.ContainsFunc(result.Diagnostics, func(d LintDiagnostic) bool {
+			return d.Code == LintCodeMissingEnvVariable
+		}))
+		assert.Assert(t, slices.Contains(result.Diagnostics, LintDiagnostic{
+			Severity: LintSeverityError,
+			Code:     LintCodeInvalidRuntimeSetting,
+			File:     "pets.yaml",
+			Message:  "ConfigItem.retry: retry policy times must be positive",
+			Hint:     "Timeouts and retry values must be positive, and retry http status must be in between 400 and 599",
+		}))
+	})
+}
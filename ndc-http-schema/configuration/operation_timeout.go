@@ -0,0 +1,92 @@
+package configuration
+
+import (
+	"fmt"
+
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// OperationTimeoutSetting represents timeout overrides in seconds by operation kinds and names,
+// e.g. long-running procedures and fast functions in the same file.
+type OperationTimeoutSetting struct {
+	// The request timeout in seconds of functions
+	Function *utils.EnvInt `json:"function,omitempty" mapstructure:"function" yaml:"function,omitempty"`
+	// The request timeout in seconds of procedures
+	Procedure *utils.EnvInt `json:"procedure,omitempty" mapstructure:"procedure" yaml:"procedure,omitempty"`
+	// The request timeout in seconds of operations by names. Take precedence over timeouts of operation kinds
+	Operations map[string]utils.EnvInt `json:"operations,omitempty" mapstructure:"operations" yaml:"operations,omitempty"`
+}
+
+// OperationTimeouts hold evaluated timeout overrides of operations.
+type OperationTimeouts struct {
+	function   uint
+	procedure  uint
+	operations map[string]uint
+}
+
+// NewOperationTimeouts evaluates timeout overrides of operations. Returns nil if there is no setting.
+func NewOperationTimeouts(setting *OperationTimeoutSetting) (*OperationTimeouts, error) {
+	if setting == nil {
+		return nil, nil
+	}
+
+	result := &OperationTimeouts{
+		operations: make(map[string]uint),
+	}
+
+	var err error
+	if result.function, err = evalTimeoutSeconds(setting.Function); err != nil {
+		return nil, fmt.Errorf("timeouts.function: %w", err)
+	}
+
+	if result.procedure, err = evalTimeoutSeconds(setting.Procedure); err != nil {
+		return nil, fmt.Errorf("timeouts.procedure: %w", err)
+	}
+
+	for name, value := range setting.Operations {
+		timeout, err := evalTimeoutSeconds(&value)
+		if err != nil {
+			return nil, fmt.Errorf("timeouts.operations.%s: %w", name, err)
+		}
+
+		if timeout > 0 {
+			result.operations[name] = timeout
+		}
+	}
+
+	return result, nil
+}
+
+// GetTimeout returns the timeout in seconds of the operation. Returns zero if there is no override.
+func (ot *OperationTimeouts) GetTimeout(operationName string, isProcedure bool) uint {
+	if ot == nil {
+		return 0
+	}
+
+	if timeout, ok := ot.operations[operationName]; ok {
+		return timeout
+	}
+
+	if isProcedure {
+		return ot.procedure
+	}
+
+	return ot.function
+}
+
+func evalTimeoutSeconds(value *utils.EnvInt) (uint, error) {
+	if value == nil {
+		return 0, nil
+	}
+
+	result, err := value.GetOrDefault(0)
+	if err != nil {
+		return 0, err
+	}
+
+	if result < 0 {
+		return 0, fmt.Errorf("must be positive, got: %d", result)
+	}
+
+	return uint(result), nil
+}
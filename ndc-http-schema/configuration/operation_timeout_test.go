@@ -0,0 +1,67 @@
+package configuration
+
+import (
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestOperationTimeouts(t *testing.T) {
+	timeouts, err := NewOperationTimeouts(nil)
+	assert.NilError(t, err)
+	assert.Assert(t, timeouts == nil)
+	assert.Equal(t, uint(0), timeouts.GetTimeout("findPets", false))
+
+	t.Setenv("PROCEDURE_TIMEOUT", "120")
+	timeouts, err = NewOperationTimeouts(&OperationTimeoutSetting{
+		Function:  utils.ToPtr(utils.NewEnvIntValue(5)),
+		Procedure: utils.ToPtr(utils.NewEnvIntVariable("PROCEDURE_TIMEOUT")),
+		Operations: map[string]utils.EnvInt{
+			"exportPets": utils.NewEnvIntValue(600),
+		},
+	})
+	assert.NilError(t, err)
+
+	runtimeSchema := NDCHttpRuntimeSchema{
+		Runtime:  rest.RuntimeSettings{Timeout: 30, MaxResponseBytes: 1024},
+		Timeouts: timeouts,
+		NDCHttpSchema: &rest.NDCHttpSchema{
+			Functions: map[string]rest.OperationInfo{
+				"findPets": {},
+			},
+			Procedures: map[string]rest.OperationInfo{
+				"addPet":     {},
+				"exportPets": {},
+			},
+		},
+	}
+
+	testCases := []struct {
+		Operation string
+		Expected  uint
+	}{
+		{Operation: "findPets", Expected: 5},
+		{Operation: "addPet", Expected: 120},
+		{Operation: "exportPets", Expected: 600},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Operation, func(t *testing.T) {
+			runtime := runtimeSchema.GetOperationRuntime(tc.Operation)
+			assert.Equal(t, tc.Expected, runtime.Timeout)
+			assert.Equal(t, uint(1024), runtime.MaxResponseBytes)
+		})
+	}
+
+	// the file runtime settings aren't modified
+	assert.Equal(t, uint(30), runtimeSchema.Runtime.Timeout)
+
+	_, err = NewOperationTimeouts(&OperationTimeoutSetting{
+		Operations: map[string]utils.EnvInt{
+			"addPet": utils.NewEnvIntValue(-1),
+		},
+	})
+	assert.ErrorContains(t, err, "timeouts.operations.addPet: must be positive, got: -1")
+}
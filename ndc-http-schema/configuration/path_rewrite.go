@@ -0,0 +1,71 @@
+package configuration
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+)
+
+// PathRewriteRule represents a regular expression rule to rewrite the URL path of requests.
+// It's useful when the upstream service is mounted under a different prefix behind a gateway, e.g. /api/v2 -> /external/foo/api/v2.
+type PathRewriteRule struct {
+	// The regular expression to match the request path, e.g. ^/api/v2
+	Match string `json:"match" yaml:"match"`
+	// The replacement string. Capture groups can be referenced by $1, ${name}, e.g. /external/foo/api/v2
+	Replace string `json:"replace" yaml:"replace"`
+}
+
+// PathRewriter rewrites URL paths with compiled rules.
+type PathRewriter struct {
+	rules []compiledPathRewriteRule
+}
+
+type compiledPathRewriteRule struct {
+	regex   *regexp.Regexp
+	replace string
+}
+
+// NewPathRewriter compiles path rewrite rules. Returns nil if there is no rule.
+func NewPathRewriter(rules []PathRewriteRule) (*PathRewriter, error) {
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	result := &PathRewriter{
+		rules: make([]compiledPathRewriteRule, 0, len(rules)),
+	}
+
+	for i, rule := range rules {
+		if rule.Match == "" {
+			return nil, fmt.Errorf("pathRewrite[%d]: %w", i, errors.New("match must not be empty"))
+		}
+
+		regex, err := regexp.Compile(rule.Match)
+		if err != nil {
+			return nil, fmt.Errorf("pathRewrite[%d]: invalid match expression: %w", i, err)
+		}
+
+		result.rules = append(result.rules, compiledPathRewriteRule{
+			regex:   regex,
+			replace: rule.Replace,
+		})
+	}
+
+	return result, nil
+}
+
+// Rewrite applies the first rule which matches the path.
+// The path is returned unchanged if no rule matches.
+func (pr *PathRewriter) Rewrite(urlPath string) string {
+	if pr == nil {
+		return urlPath
+	}
+
+	for _, rule := range pr.rules {
+		if rule.regex.MatchString(urlPath) {
+			return rule.regex.ReplaceAllString(urlPath, rule.replace)
+		}
+	}
+
+	return urlPath
+}
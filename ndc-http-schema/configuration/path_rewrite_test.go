@@ -0,0 +1,41 @@
+package configuration
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestPathRewriter(t *testing.T) {
+	rewriter, err := NewPathRewriter([]PathRewriteRule{
+		{Match: "^/api/v2", Replace: "/external/foo/api/v2"},
+		{Match: `^/users/(?P<id>\w+)$`, Replace: "/accounts/${id}"},
+		{Match: "^/api", Replace: "/legacy"},
+	})
+	assert.NilError(t, err)
+
+	testCases := []struct {
+		Input    string
+		Expected string
+	}{
+		{Input: "/api/v2/pets", Expected: "/external/foo/api/v2/pets"},
+		{Input: "/api/v1/pets", Expected: "/legacy/v1/pets"},
+		{Input: "/users/foo", Expected: "/accounts/foo"},
+		{Input: "/pets", Expected: "/pets"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Input, func(t *testing.T) {
+			assert.Equal(t, tc.Expected, rewriter.Rewrite(tc.Input))
+		})
+	}
+
+	var nilRewriter *PathRewriter
+	assert.Equal(t, "/pets", nilRewriter.Rewrite("/pets"))
+
+	_, err = NewPathRewriter([]PathRewriteRule{{Match: "[a-"}})
+	assert.ErrorContains(t, err, "invalid match expression")
+
+	_, err = NewPathRewriter([]PathRewriteRule{{Replace: "/foo"}})
+	assert.ErrorContains(t, err, "match must not be empty")
+}
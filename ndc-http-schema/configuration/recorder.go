@@ -0,0 +1,52 @@
+package configuration
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/invopop/jsonschema"
+)
+
+// RecorderMode represents the mode of the recorder of upstream requests.
+type RecorderMode string
+
+const (
+	// RecorderModeRecord sends requests to upstream services and writes request and response pairs to cassette files.
+	RecorderModeRecord RecorderMode = "record"
+	// RecorderModeReplay serves matched responses from cassette files without calling upstream services.
+	RecorderModeReplay RecorderMode = "replay"
+)
+
+var recorderMode_enums = []RecorderMode{RecorderModeRecord, RecorderModeReplay}
+
+// JSONSchema is used to generate a custom jsonschema.
+func (j RecorderMode) JSONSchema() *jsonschema.Schema {
+	enums := make([]any, len(recorderMode_enums))
+	for i, item := range recorderMode_enums {
+		enums[i] = item
+	}
+
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: enums,
+	}
+}
+
+// RecorderSettings hold settings to record upstream requests and responses to cassette files and replay them.
+type RecorderSettings struct {
+	// The recorder mode.
+	Mode RecorderMode `json:"mode" yaml:"mode"`
+	// The directory of cassette files. Relative paths are resolved from the configuration directory. Default to cassettes
+	Dir string `json:"dir,omitempty" yaml:"dir,omitempty"`
+	// Match request bodies in addition to methods, paths and query strings when replaying responses.
+	MatchBody bool `json:"matchBody,omitempty" yaml:"matchBody,omitempty"`
+}
+
+// Validate checks if the settings are valid.
+func (rs RecorderSettings) Validate() error {
+	if !slices.Contains(recorderMode_enums, rs.Mode) {
+		return fmt.Errorf("invalid mode %s, expected one of %v", rs.Mode, recorderMode_enums)
+	}
+
+	return nil
+}
@@ -0,0 +1,226 @@
+package configuration
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+const defaultRemoteFileCacheDir = ".cache"
+
+// RemoteFilesSettings hold settings of schema files which are downloaded from remote sources, e.g. https://, s3:// and gs:// URLs.
+type RemoteFilesSettings struct {
+	// The directory to cache downloaded files. Cached files are used if remote sources are unavailable at startup.
+	// Relative paths are resolved from the configuration directory. Default to .cache
+	CacheDir string `json:"cacheDir,omitempty" yaml:"cacheDir,omitempty"`
+	// The interval in seconds to download remote files in the background and sync them to the cache. Disabled if zero.
+	// The running connector keeps the schema which is loaded at startup. Synced files are used at the next startup
+	SyncInterval uint `json:"syncInterval,omitempty" yaml:"syncInterval,omitempty"`
+}
+
+// RemoteFileCredentials hold credentials to download schema files from object storages.
+// Unset fields fall back to standard environment variables of the cloud provider.
+type RemoteFileCredentials struct {
+	// The access key ID of s3:// files. Default to the AWS_ACCESS_KEY_ID environment variable
+	AccessKeyID *utils.EnvString `json:"accessKeyId,omitempty" yaml:"accessKeyId,omitempty"`
+	// The secret access key of s3:// files. Default to the AWS_SECRET_ACCESS_KEY environment variable
+	SecretAccessKey *utils.EnvString `json:"secretAccessKey,omitempty" yaml:"secretAccessKey,omitempty"`
+	// The session token of temporary credentials of s3:// files. Default to the AWS_SESSION_TOKEN environment variable
+	SessionToken *utils.EnvString `json:"sessionToken,omitempty" yaml:"sessionToken,omitempty"`
+	// The region of the S3 bucket. Default to the AWS_REGION environment variable, or us-east-1
+	Region *utils.EnvString `json:"region,omitempty" yaml:"region,omitempty"`
+	// The endpoint of S3-compatible storages, e.g. http://minio:9000. Default to the AWS_ENDPOINT_URL_S3 environment variable
+	Endpoint *utils.EnvString `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+	// The OAuth2 access token of gs:// files. Default to the GOOGLE_OAUTH_ACCESS_TOKEN environment variable
+	AccessToken *utils.EnvString `json:"accessToken,omitempty" yaml:"accessToken,omitempty"`
+}
+
+// evaluate credentials with fallback environment variables.
+func (rfc *RemoteFileCredentials) evaluate(options *restUtils.ReadFileOptions) error {
+	if rfc == nil {
+		rfc = &RemoteFileCredentials{}
+	}
+
+	s3 := &restUtils.S3Credentials{}
+	for _, field := range []struct {
+		name       string
+		value      *utils.EnvString
+		defaultEnv string
+		target     *string
+	}{
+		{"accessKeyId", rfc.AccessKeyID, "AWS_ACCESS_KEY_ID", &s3.AccessKeyID},
+		{"secretAccessKey", rfc.SecretAccessKey, "AWS_SECRET_ACCESS_KEY", &s3.SecretAccessKey},
+		{"sessionToken", rfc.SessionToken, "AWS_SESSION_TOKEN", &s3.SessionToken},
+		{"region", rfc.Region, "AWS_REGION", &s3.Region},
+		{"endpoint", rfc.Endpoint, "AWS_ENDPOINT_URL_S3", &s3.Endpoint},
+		{"accessToken", rfc.AccessToken, "GOOGLE_OAUTH_ACCESS_TOKEN", &options.GCSAccessToken},
+	} {
+		if field.value == nil {
+			*field.target = os.Getenv(field.defaultEnv)
+
+			continue
+		}
+
+		value, err := field.value.GetOrDefault("")
+		if err != nil {
+			return fmt.Errorf("fileCredentials.%s: %w", field.name, err)
+		}
+
+		*field.target = value
+	}
+
+	options.S3 = s3
+
+	return nil
+}
+
+// read the spec content of the config item. Remote files are cached if the remoteFiles setting is enabled,
+// and the cached content is used if the remote source is unavailable.
+func readConfigItemContent(config *Configuration, configDir string, item *ConvertConfig, logger *slog.Logger) ([]byte, error) {
+	if config.RemoteFiles == nil || !restUtils.IsRemoteFilePath(item.File) {
+		return readSpecContent(item)
+	}
+
+	cachePath := getRemoteFileCachePath(config.RemoteFiles, configDir, item.File)
+	rawContent, err := readSpecContent(item)
+	if err != nil {
+		cachedContent, cacheErr := os.ReadFile(cachePath)
+		if cacheErr != nil {
+			return nil, err
+		}
+
+		logger.Warn(fmt.Sprintf("failed to download %s, use the cached file at %s: %s", item.File, cachePath, err))
+
+		return cachedContent, nil
+	}
+
+	if err := writeRemoteFileCache(cachePath, rawContent); err != nil {
+		logger.Warn(err.Error())
+	}
+
+	return rawContent, nil
+}
+
+// get the cache file path of the remote file. The file name is derived from the hash of the URL.
+func getRemoteFileCachePath(settings *RemoteFilesSettings, configDir string, fileURL string) string {
+	cacheDir := settings.CacheDir
+	if cacheDir == "" {
+		cacheDir = defaultRemoteFileCacheDir
+	}
+
+	if !filepath.IsAbs(cacheDir) {
+		cacheDir = filepath.Join(configDir, cacheDir)
+	}
+
+	urlHash := sha256.Sum256([]byte(fileURL))
+	fileName := hex.EncodeToString(urlHash[:8])
+	if u, err := url.Parse(fileURL); err == nil {
+		fileName += path.Ext(u.Path)
+	}
+
+	return filepath.Join(cacheDir, fileName)
+}
+
+func writeRemoteFileCache(cachePath string, content []byte) error {
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0o775); err != nil {
+		return fmt.Errorf("failed to create the cache directory of remote files: %w", err)
+	}
+
+	if err := os.WriteFile(cachePath, content, 0o664); err != nil {
+		return fmt.Errorf("failed to write the cache of remote files: %w", err)
+	}
+
+	return nil
+}
+
+// RemoteFileCacheSyncer downloads remote schema files periodically to keep the cache up to date.
+// It doesn't change the schema of the running connector.
+type RemoteFileCacheSyncer struct {
+	settings  *RemoteFilesSettings
+	configDir string
+	items     []ConvertConfig
+	logger    *slog.Logger
+}
+
+// NewRemoteFileCacheSyncer creates a cache syncer of remote schema files.
+// Returns nil if the sync interval isn't set or there is no remote file.
+func NewRemoteFileCacheSyncer(config *Configuration, configDir string, logger *slog.Logger) *RemoteFileCacheSyncer {
+	if config.RemoteFiles == nil || config.RemoteFiles.SyncInterval == 0 {
+		return nil
+	}
+
+	var items []ConvertConfig
+	for _, item := range config.Files {
+		if restUtils.IsRemoteFilePath(item.File) {
+			items = append(items, item.ConvertConfig)
+		}
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	return &RemoteFileCacheSyncer{
+		settings:  config.RemoteFiles,
+		configDir: configDir,
+		items:     items,
+		logger:    logger,
+	}
+}
+
+// Start downloads remote files in the background until the context is canceled.
+func (rfs *RemoteFileCacheSyncer) Start(ctx context.Context) {
+	go func() {
+		ticker := time.NewTicker(time.Duration(rfs.settings.SyncInterval) * time.Second)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				rfs.Sync()
+			}
+		}
+	}()
+}
+
+// Sync downloads remote files and updates the cache. Returns URLs of files whose content changed.
+func (rfs *RemoteFileCacheSyncer) Sync() []string {
+	var changedFiles []string
+	for _, item := range rfs.items {
+		rawContent, err := readSpecContent(&item)
+		if err != nil {
+			rfs.logger.Warn(fmt.Sprintf("failed to sync the remote file %s: %s", item.File, err))
+
+			continue
+		}
+
+		cachePath := getRemoteFileCachePath(rfs.settings, rfs.configDir, item.File)
+		if cachedContent, err := os.ReadFile(cachePath); err == nil && bytes.Equal(cachedContent, rawContent) {
+			continue
+		}
+
+		if err := writeRemoteFileCache(cachePath, rawContent); err != nil {
+			rfs.logger.Warn(err.Error())
+
+			continue
+		}
+
+		changedFiles = append(changedFiles, item.File)
+		rfs.logger.Warn(fmt.Sprintf("the remote file %s changed and the cache is updated. Restart the connector to apply the new schema", item.File))
+	}
+
+	return changedFiles
+}
@@ -0,0 +1,73 @@
+package configuration
+
+import (
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestRemoteFiles(t *testing.T) {
+	var content atomic.Value
+	content.Store("v1")
+	var unavailable atomic.Bool
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if unavailable.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(content.Load().(string)))
+	}))
+	defer server.Close()
+
+	fileURL := server.URL + "/schema.json"
+	cacheDir := t.TempDir()
+	config := &Configuration{
+		RemoteFiles: &RemoteFilesSettings{
+			CacheDir:     cacheDir,
+			SyncInterval: 60,
+		},
+		Files: []ConfigItem{
+			{ConvertConfig: ConvertConfig{File: fileURL}},
+			{ConvertConfig: ConvertConfig{File: "testdata/schema.json"}},
+		},
+	}
+
+	cachePath := getRemoteFileCachePath(config.RemoteFiles, ".", fileURL)
+	rawContent, err := readConfigItemContent(config, ".", &config.Files[0].ConvertConfig, slog.Default())
+	assert.NilError(t, err)
+	assert.Equal(t, "v1", string(rawContent))
+
+	cachedContent, err := os.ReadFile(cachePath)
+	assert.NilError(t, err)
+	assert.Equal(t, "v1", string(cachedContent))
+
+	// the cached file is used if the remote source is unavailable
+	unavailable.Store(true)
+	rawContent, err = readConfigItemContent(config, ".", &config.Files[0].ConvertConfig, slog.Default())
+	assert.NilError(t, err)
+	assert.Equal(t, "v1", string(rawContent))
+
+	syncer := NewRemoteFileCacheSyncer(config, ".", slog.Default())
+	assert.Assert(t, syncer != nil)
+	assert.Equal(t, 0, len(syncer.Sync()))
+
+	unavailable.Store(false)
+	assert.Equal(t, 0, len(syncer.Sync()))
+
+	content.Store("v2")
+	assert.DeepEqual(t, []string{fileURL}, syncer.Sync())
+	cachedContent, err = os.ReadFile(cachePath)
+	assert.NilError(t, err)
+	assert.Equal(t, "v2", string(cachedContent))
+
+	config.RemoteFiles.SyncInterval = 0
+	assert.Assert(t, NewRemoteFileCacheSyncer(config, ".", slog.Default()) == nil)
+}
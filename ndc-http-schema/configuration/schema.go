@@ -4,11 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
 	"slices"
 	"strconv"
+	"time"
 
 	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
 	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
@@ -23,7 +26,7 @@ func BuildSchemaFromConfig(config *Configuration, configDir string, logger *slog
 	existedFileIDs := []string{}
 
 	for i, file := range config.Files {
-		schemaOutput, err := buildSchemaFile(config, configDir, &file, logger)
+		schemaOutput, specHash, err := buildSchemaFile(config, configDir, &file, logger)
 		if err != nil {
 			errors[file.File] = []string{err.Error()}
 		}
@@ -39,8 +42,13 @@ func BuildSchemaFromConfig(config *Configuration, configDir string, logger *slog
 
 		ndcSchema := NDCHttpRuntimeSchema{
 			Name:          fileID,
+			Hash:          specHash,
+			ConvertedAt:   utils.ToPtr(time.Now().UTC().Truncate(time.Second)),
 			NDCHttpSchema: schemaOutput,
 		}
+		if schemaOutput.Settings != nil {
+			ndcSchema.Version = schemaOutput.Settings.Version
+		}
 
 		runtime, err := file.GetRuntimeSettings()
 		if err != nil {
@@ -49,6 +57,20 @@ func BuildSchemaFromConfig(config *Configuration, configDir string, logger *slog
 			ndcSchema.Runtime = *runtime
 		}
 
+		pathRewriter, err := NewPathRewriter(file.PathRewrite)
+		if err != nil {
+			errors[fileID] = append(errors[fileID], err.Error())
+		} else {
+			ndcSchema.PathRewrite = pathRewriter
+		}
+
+		timeouts, err := NewOperationTimeouts(file.Timeouts)
+		if err != nil {
+			errors[fileID] = append(errors[fileID], err.Error())
+		} else {
+			ndcSchema.Timeouts = timeouts
+		}
+
 		existedFileIDs = append(existedFileIDs, fileID)
 		schemas[i] = ndcSchema
 	}
@@ -104,8 +126,13 @@ func MergeNDCHttpSchemas(config *Configuration, schemas []NDCHttpRuntimeSchema)
 		}
 
 		meta := NDCHttpRuntimeSchema{
-			Name:    item.Name,
-			Runtime: item.Runtime,
+			Name:        item.Name,
+			Hash:        item.Hash,
+			Version:     item.Version,
+			ConvertedAt: item.ConvertedAt,
+			Runtime:     item.Runtime,
+			PathRewrite: item.PathRewrite,
+			Timeouts:    item.Timeouts,
 			NDCHttpSchema: &rest.NDCHttpSchema{
 				Settings:    settings,
 				Functions:   map[string]rest.OperationInfo{},
@@ -120,20 +147,20 @@ func MergeNDCHttpSchemas(config *Configuration, schemas []NDCHttpRuntimeSchema)
 			if originScalar, ok := ndcSchema.ScalarTypes[name]; !ok {
 				ndcSchema.ScalarTypes[name] = scalar
 			} else if !rest.IsDefaultScalar(name) && !reflect.DeepEqual(originScalar, scalar) {
-				slog.Warn(fmt.Sprintf("Scalar type %s is conflicted", name))
+				slog.Warn(fmt.Sprintf("Scalar type %s of %s is conflicted with another schema file and ignored. Set naming.typePrefix of the file to avoid collisions", name, item.Name))
 			}
 		}
 
 		for name, object := range item.ObjectTypes {
-			if _, ok := ndcSchema.ObjectTypes[name]; !ok {
+			if originObject, ok := ndcSchema.ObjectTypes[name]; !ok {
 				ndcSchema.ObjectTypes[name] = object
-			} else {
-				slog.Warn(fmt.Sprintf("Object type %s is conflicted", name))
+			} else if !reflect.DeepEqual(originObject, object) {
+				slog.Warn(fmt.Sprintf("Object type %s of %s is conflicted with another schema file and ignored. Set naming.typePrefix of the file to avoid collisions", name, item.Name))
 			}
 		}
 
 		for fnName, fnItem := range item.Functions {
-			if fnItem.Request == nil || fnItem.Request.URL == "" {
+			if fnItem.Request == nil || (fnItem.Request.URL == "" && fnItem.Request.Protocol != rest.ProtocolGRPC) {
 				continue
 			}
 
@@ -144,12 +171,20 @@ func MergeNDCHttpSchemas(config *Configuration, schemas []NDCHttpRuntimeSchema)
 				continue
 			}
 
+			if fnItem.Collection {
+				if _, err := item.GetCollectionType(fnItem); err != nil {
+					errs = append(errs, fmt.Sprintf("function %s: %s", fnName, err))
+
+					continue
+				}
+			}
+
 			meta.Functions[fnName] = cloneOperationInfo(fnItem, req)
 			ndcSchema.Functions[fnName] = cloneOperationInfo(fnItem, req)
 		}
 
 		for procName, procItem := range item.Procedures {
-			if procItem.Request == nil || procItem.Request.URL == "" {
+			if procItem.Request == nil || (procItem.Request.URL == "" && procItem.Request.Protocol != rest.ProtocolGRPC) {
 				continue
 			}
 
@@ -172,23 +207,30 @@ func MergeNDCHttpSchemas(config *Configuration, schemas []NDCHttpRuntimeSchema)
 		appliedSchemas[i] = meta
 	}
 
+	applyFilterComparisonOperators(config, ndcSchema)
+
 	return ndcSchema, appliedSchemas, errors
 }
 
-func buildSchemaFile(config *Configuration, configDir string, configItem *ConfigItem, logger *slog.Logger) (*rest.NDCHttpSchema, error) {
+func buildSchemaFile(config *Configuration, configDir string, configItem *ConfigItem, logger *slog.Logger) (*rest.NDCHttpSchema, string, error) {
 	if configItem.ConvertConfig.File == "" {
-		return nil, errFilePathRequired
+		return nil, "", errFilePathRequired
 	}
 	ResolveConvertConfigArguments(&configItem.ConvertConfig, configDir, nil)
-	ndcSchema, err := ConvertToNDCSchema(&configItem.ConvertConfig, logger)
+	rawContent, err := readConfigItemContent(config, configDir, &configItem.ConvertConfig, logger)
 	if err != nil {
-		return nil, err
+		return nil, "", err
+	}
+
+	ndcSchema, specHash, err := convertSpecContentToNDCSchema(&configItem.ConvertConfig, rawContent, logger)
+	if err != nil {
+		return nil, "", err
 	}
 
 	if ndcSchema.Settings == nil || len(ndcSchema.Settings.Servers) == 0 {
 		templates, err := getTemplates()
 		if err != nil {
-			return nil, err
+			return nil, "", err
 		}
 		if err := templates.ExecuteTemplate(os.Stderr, templateEmptySettings, map[string]any{
 			"ContextPath": configDir,
@@ -197,13 +239,18 @@ func buildSchemaFile(config *Configuration, configDir string, configItem *Config
 			logger.Warn(err.Error())
 		}
 
-		return nil, fmt.Errorf("the servers setting of schema %s is empty", configItem.ConvertConfig.File)
+		return nil, "", fmt.Errorf("the servers setting of schema %s is empty", configItem.ConvertConfig.File)
+	}
+
+	if err := buildResponseEnrichFields(ndcSchema); err != nil {
+		return nil, "", err
 	}
 
 	buildHTTPArguments(config, ndcSchema, configItem)
+	buildDryRunArguments(config, ndcSchema)
 	buildHeadersForwardingResponse(config, ndcSchema)
 
-	return ndcSchema, nil
+	return ndcSchema, specHash, nil
 }
 
 func buildHTTPArguments(config *Configuration, restSchema *rest.NDCHttpSchema, conf *ConfigItem) {
@@ -248,7 +295,17 @@ func buildHTTPArguments(config *Configuration, restSchema *rest.NDCHttpSchema, c
 		return
 	}
 
+	strategyScalar := schema.NewScalarType()
+	strategyScalar.Representation = schema.NewTypeRepresentationEnum([]string{
+		string(rest.DistributedExecutionAll),
+		string(rest.DistributedExecutionFailover),
+		string(rest.DistributedExecutionRace),
+	}).Encode()
+
+	restSchema.ScalarTypes[rest.HTTPDistributedStrategyScalarName] = *strategyScalar
 	restSchema.ObjectTypes[rest.HTTPDistributedOptionsObjectName] = distributedObjectType
+	restSchema.ObjectTypes[rest.DistributedComparisonObjectName] = distributedComparisonObjectType
+	restSchema.ObjectTypes[rest.DistributedDifferenceObjectName] = distributedDifferenceObjectType
 	restSchema.ObjectTypes[rest.DistributedErrorObjectName] = rest.ObjectType{
 		Description: utils.ToPtr("The error response of the remote request"),
 		Fields: map[string]rest.ObjectField{
@@ -281,6 +338,7 @@ func buildHTTPArguments(config *Configuration, restSchema *rest.NDCHttpSchema, c
 			Request:     fn.Request,
 			Arguments:   cloneDistributedArguments(fn.Arguments),
 			Description: fn.Description,
+			Tags:        fn.Tags,
 			ResultType:  schema.NewNamedType(buildDistributedResultObjectType(restSchema, funcName, fn.ResultType)).Encode(),
 		}
 		restSchema.Functions[funcName] = distributedFn
@@ -295,12 +353,98 @@ func buildHTTPArguments(config *Configuration, restSchema *rest.NDCHttpSchema, c
 			Request:     proc.Request,
 			Arguments:   cloneDistributedArguments(proc.Arguments),
 			Description: proc.Description,
+			Tags:        proc.Tags,
 			ResultType:  schema.NewNamedType(buildDistributedResultObjectType(restSchema, procName, proc.ResultType)).Encode(),
 		}
 		restSchema.Procedures[procName] = distributedProc
 	}
 }
 
+// add the dryRun option to httpOptions arguments of procedures.
+// Procedures of schemas with a single server don't have the httpOptions argument, so the HttpDryRunOptions object is used.
+func buildDryRunArguments(config *Configuration, restSchema *rest.NDCHttpSchema) {
+	if !config.DryRun || len(restSchema.Procedures) == 0 {
+		return
+	}
+
+	if _, ok := restSchema.ScalarTypes[string(rest.ScalarBoolean)]; !ok {
+		restSchema.ScalarTypes[string(rest.ScalarBoolean)] = schema.ScalarType{
+			AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+			ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+			Representation:      schema.NewTypeRepresentationBoolean().Encode(),
+		}
+	}
+
+	for _, name := range []string{rest.HTTPSingleOptionsObjectName, rest.HTTPDistributedOptionsObjectName} {
+		objectType, ok := restSchema.ObjectTypes[name]
+		if !ok {
+			continue
+		}
+
+		fields := maps.Clone(objectType.Fields)
+		fields["dryRun"] = dryRunObjectField
+		objectType.Fields = fields
+		restSchema.ObjectTypes[name] = objectType
+	}
+
+	for _, proc := range restSchema.Procedures {
+		if _, ok := proc.Arguments[rest.HTTPOptionsArgumentName]; ok {
+			continue
+		}
+
+		restSchema.ObjectTypes[rest.HTTPDryRunOptionsObjectName] = dryRunObjectType
+		proc.Arguments[rest.HTTPOptionsArgumentName] = httpDryRunOptionsArgument
+	}
+}
+
+// set the RedirectResult object as the result type of operations whose redirect option is enabled.
+// Redirects of these operations aren't followed, so the response body isn't decoded.
+func buildRedirectResultTypes(restSchema *rest.NDCHttpSchema) {
+	var hasRedirect bool
+	resultType := schema.NewNamedType(rest.RedirectResultObjectName).Encode()
+	for name, fn := range restSchema.Functions {
+		if fn.Request == nil || !fn.Request.Response.Redirect {
+			continue
+		}
+
+		hasRedirect = true
+		fn.ResultType = resultType
+		restSchema.Functions[name] = fn
+	}
+
+	for name, proc := range restSchema.Procedures {
+		if proc.Request == nil || !proc.Request.Response.Redirect {
+			continue
+		}
+
+		hasRedirect = true
+		proc.ResultType = resultType
+		restSchema.Procedures[name] = proc
+	}
+
+	if !hasRedirect {
+		return
+	}
+
+	if _, ok := restSchema.ScalarTypes[string(rest.ScalarInt32)]; !ok {
+		restSchema.ScalarTypes[string(rest.ScalarInt32)] = schema.ScalarType{
+			AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+			ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+			Representation:      schema.NewTypeRepresentationInt32().Encode(),
+		}
+	}
+
+	if _, ok := restSchema.ScalarTypes[string(rest.ScalarString)]; !ok {
+		restSchema.ScalarTypes[string(rest.ScalarString)] = schema.ScalarType{
+			AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+			ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+			Representation:      schema.NewTypeRepresentationString().Encode(),
+		}
+	}
+
+	restSchema.ObjectTypes[rest.RedirectResultObjectName] = redirectResultObjectType
+}
+
 func buildHeadersForwardingResponse(config *Configuration, restSchema *rest.NDCHttpSchema) {
 	if !config.ForwardHeaders.Enabled {
 		return
@@ -319,6 +463,11 @@ func buildHeadersForwardingResponse(config *Configuration, restSchema *rest.NDCH
 	}
 
 	for name, op := range restSchema.Functions {
+		// rows of collections can't carry response headers
+		if op.Collection {
+			continue
+		}
+
 		op.ResultType = createHeaderForwardingResponseTypes(restSchema, name, op.ResultType, config.ForwardHeaders.ResponseHeaders)
 		restSchema.Functions[name] = op
 	}
@@ -388,6 +537,12 @@ func buildDistributedResultObjectType(restSchema *rest.NDCHttpSchema, operationN
 					Type:        schema.NewArrayType(schema.NewNamedType(rest.DistributedErrorObjectName)).Encode(),
 				},
 			},
+			"comparison": {
+				ObjectField: schema.ObjectField{
+					Description: utils.ToPtr("The comparison of results if the compareServers option is set"),
+					Type:        schema.NewNullableNamedType(rest.DistributedComparisonObjectName).Encode(),
+				},
+			},
 		},
 	}
 
@@ -399,6 +554,45 @@ func buildDistributedName(name string) string {
 }
 
 func validateRequestSchema(req *rest.Request, defaultMethod string) (*rest.Request, error) {
+	if req.Protocol != "" && !req.Protocol.IsValid() {
+		return nil, fmt.Errorf("invalid request protocol: %s", req.Protocol)
+	}
+
+	if req.Protocol == rest.ProtocolGRPC {
+		if req.GRPC == nil {
+			return nil, errGRPCRequired
+		}
+
+		if err := req.GRPC.Validate(); err != nil {
+			return nil, fmt.Errorf("grpc: %w", err)
+		}
+
+		// gRPC methods are always called with POST requests to the method path
+		req.Method = http.MethodPost
+		if req.URL == "" {
+			req.URL = req.GRPC.FullMethod()
+		}
+	}
+
+	if req.SOAP != nil {
+		if req.SOAP.Version != "" && !req.SOAP.Version.IsValid() {
+			return nil, fmt.Errorf("invalid SOAP version: %s", req.SOAP.Version)
+		}
+
+		// SOAP messages are always sent with POST requests
+		req.Method = http.MethodPost
+	}
+
+	if req.Response.Envelope != "" && !req.Response.Envelope.IsValid() {
+		return nil, fmt.Errorf("invalid response envelope: %s", req.Response.Envelope)
+	}
+
+	if req.Protobuf != nil {
+		if err := req.Protobuf.Validate(); err != nil {
+			return nil, fmt.Errorf("protobuf: %w", err)
+		}
+	}
+
 	if req.Method == "" {
 		if defaultMethod == "" {
 			return nil, errHTTPMethodRequired
@@ -406,6 +600,24 @@ func validateRequestSchema(req *rest.Request, defaultMethod string) (*rest.Reque
 		req.Method = defaultMethod
 	}
 
+	if req.Pagination != nil {
+		if err := req.Pagination.Validate(); err != nil {
+			return nil, fmt.Errorf("pagination: %w", err)
+		}
+	}
+
+	if req.Filter != nil {
+		if err := req.Filter.Validate(); err != nil {
+			return nil, fmt.Errorf("filter: %w", err)
+		}
+	}
+
+	if req.OrderBy != nil {
+		if err := req.OrderBy.Validate(); err != nil {
+			return nil, fmt.Errorf("orderBy: %w", err)
+		}
+	}
+
 	return req, nil
 }
 
@@ -428,6 +640,7 @@ func cloneOperationInfo(operation rest.OperationInfo, req *rest.Request) rest.Op
 		Request:     req,
 		Arguments:   args,
 		Description: operation.Description,
+		Collection:  operation.Collection,
 		ResultType:  operation.ResultType,
 	}
 }
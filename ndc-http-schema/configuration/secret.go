@@ -0,0 +1,364 @@
+package configuration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// SecretsSettings hold settings of secrets backends. Values of EnvString settings in schema files, or values of their environment variables,
+// can be secret references, e.g. vault:secret/data/petstore#api_key, which are resolved from backends when the connector starts.
+type SecretsSettings struct {
+	// The interval in seconds to fetch secrets again. Credentials and headers of upstreams are refreshed if secrets are rotated. Disabled if zero.
+	RefreshInterval uint `json:"refreshInterval,omitempty" yaml:"refreshInterval,omitempty"`
+	// Settings of HashiCorp Vault to resolve vault: references.
+	Vault *VaultSecretsSettings `json:"vault,omitempty" yaml:"vault,omitempty"`
+	// Settings of AWS Secrets Manager to resolve awssm: references.
+	AWSSecretsManager *AWSSecretsManagerSettings `json:"awsSecretsManager,omitempty" yaml:"awsSecretsManager,omitempty"`
+}
+
+// VaultSecretsSettings hold settings of the HashiCorp Vault server. Secrets are read with the token authentication.
+type VaultSecretsSettings struct {
+	// The address of the Vault server, e.g. https://vault.example.com:8200. Default to the VAULT_ADDR environment variable
+	Address *utils.EnvString `json:"address,omitempty" yaml:"address,omitempty"`
+	// The token to read secrets. Default to the VAULT_TOKEN environment variable
+	Token *utils.EnvString `json:"token,omitempty" yaml:"token,omitempty"`
+	// The namespace of Vault Enterprise. Default to the VAULT_NAMESPACE environment variable
+	Namespace *utils.EnvString `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+// AWSSecretsManagerSettings hold settings of AWS Secrets Manager.
+// Unset fields fall back to standard environment variables of AWS.
+type AWSSecretsManagerSettings struct {
+	// Default to the AWS_ACCESS_KEY_ID environment variable
+	AccessKeyID *utils.EnvString `json:"accessKeyId,omitempty" yaml:"accessKeyId,omitempty"`
+	// Default to the AWS_SECRET_ACCESS_KEY environment variable
+	SecretAccessKey *utils.EnvString `json:"secretAccessKey,omitempty" yaml:"secretAccessKey,omitempty"`
+	// Default to the AWS_SESSION_TOKEN environment variable
+	SessionToken *utils.EnvString `json:"sessionToken,omitempty" yaml:"sessionToken,omitempty"`
+	// The region of secrets if references aren't ARNs. Default to the AWS_REGION environment variable, or us-east-1
+	Region *utils.EnvString `json:"region,omitempty" yaml:"region,omitempty"`
+	// The custom endpoint, e.g. http://localstack:4566. Default to the AWS_ENDPOINT_URL_SECRETS_MANAGER environment variable
+	Endpoint *utils.EnvString `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+}
+
+// SecretProvider abstracts a secrets backend.
+type SecretProvider interface {
+	// GetSecret gets the content of the secret at the path of the reference,
+	// e.g. secret/data/petstore of vault:secret/data/petstore#api_key.
+	GetSecret(ctx context.Context, path string) (string, error)
+}
+
+// SecretResolver resolves secret references with providers of reference schemes, e.g. vault: and awssm:.
+// Secrets are cached, so each secret is fetched once for many references.
+type SecretResolver struct {
+	providers map[string]SecretProvider
+	cache     map[string]string
+	lock      sync.Mutex
+}
+
+// NewSecretResolver creates a secret resolver with providers of the settings.
+func NewSecretResolver(settings *SecretsSettings) (*SecretResolver, error) {
+	resolver := &SecretResolver{
+		providers: map[string]SecretProvider{},
+		cache:     map[string]string{},
+	}
+
+	if settings == nil {
+		return resolver, nil
+	}
+
+	if settings.Vault != nil {
+		provider, err := newVaultSecretProvider(settings.Vault)
+		if err != nil {
+			return nil, fmt.Errorf("vault: %w", err)
+		}
+
+		resolver.RegisterProvider("vault", provider)
+	}
+
+	if settings.AWSSecretsManager != nil {
+		provider, err := newAWSSecretsManagerProvider(settings.AWSSecretsManager)
+		if err != nil {
+			return nil, fmt.Errorf("awsSecretsManager: %w", err)
+		}
+
+		resolver.RegisterProvider("awssm", provider)
+	}
+
+	return resolver, nil
+}
+
+// RegisterProvider registers the provider of secret references with the scheme, e.g. vault.
+// The provider replaces the existing provider of the scheme.
+func (sr *SecretResolver) RegisterProvider(scheme string, provider SecretProvider) {
+	sr.providers[scheme] = provider
+}
+
+// IsEmpty checks if there is no provider.
+func (sr *SecretResolver) IsEmpty() bool {
+	return len(sr.providers) == 0
+}
+
+// IsSecretReference checks if the value is a reference of registered schemes, e.g. vault:secret/data/petstore#api_key.
+func (sr *SecretResolver) IsSecretReference(value string) bool {
+	_, _, _, ok := sr.parseReference(value)
+
+	return ok
+}
+
+// Resolve gets the secret value of the reference. The optional fragment selects a field of JSON secrets,
+// e.g. vault:secret/data/petstore#api_key or awssm:prod/petstore#client_secret.
+func (sr *SecretResolver) Resolve(ctx context.Context, reference string) (string, error) {
+	value, _, err := sr.resolve(ctx, reference)
+
+	return value, err
+}
+
+// Refresh fetches cached secrets again. Returns keys of secrets whose values changed, e.g. vault:secret/data/petstore.
+// Cached values are kept if providers fail.
+func (sr *SecretResolver) Refresh(ctx context.Context) ([]string, error) {
+	sr.lock.Lock()
+	keys := utils.GetSortedKeys(sr.cache)
+	sr.lock.Unlock()
+
+	var changedKeys []string
+	var errs []error
+	for _, key := range keys {
+		scheme, path, _ := strings.Cut(key, ":")
+		content, err := sr.providers[scheme].GetSecret(ctx, path)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", key, err))
+
+			continue
+		}
+
+		sr.lock.Lock()
+		if sr.cache[key] != content {
+			sr.cache[key] = content
+			changedKeys = append(changedKeys, key)
+		}
+		sr.lock.Unlock()
+	}
+
+	return changedKeys, errors.Join(errs...)
+}
+
+// ResolveSchemas resolves secret references in settings of schemas. Schemas with references are copied, so input schemas aren't changed.
+func (sr *SecretResolver) ResolveSchemas(ctx context.Context, schemas []NDCHttpRuntimeSchema) ([]NDCHttpRuntimeSchema, error) {
+	results := slices.Clone(schemas)
+	if sr.IsEmpty() {
+		return results, nil
+	}
+
+	for i, item := range results {
+		if item.NDCHttpSchema == nil || item.Settings == nil {
+			continue
+		}
+
+		settings, keys, err := sr.ResolveSettings(ctx, item.Settings)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", item.Name, err)
+		}
+
+		if len(keys) == 0 {
+			continue
+		}
+
+		ndcSchema := *item.NDCHttpSchema
+		ndcSchema.Settings = settings
+		results[i].NDCHttpSchema = &ndcSchema
+	}
+
+	return results, nil
+}
+
+// ResolveSettings replaces EnvString values, whose literal or environment values are secret references, with secret values.
+// Returns the resolved copy of settings and keys of used secrets. The input settings is returned if there is no reference.
+func (sr *SecretResolver) ResolveSettings(ctx context.Context, settings *rest.NDCHttpSettings) (*rest.NDCHttpSettings, []string, error) {
+	if settings == nil || sr.IsEmpty() {
+		return settings, nil, nil
+	}
+
+	rawBytes, err := json.Marshal(settings)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(rawBytes))
+	decoder.UseNumber()
+
+	var document any
+	if err := decoder.Decode(&document); err != nil {
+		return nil, nil, err
+	}
+
+	var keys []string
+	document, err = sr.resolveEnvStringValues(ctx, document, "settings", &keys)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(keys) == 0 {
+		return settings, nil, nil
+	}
+
+	resolvedBytes, err := json.Marshal(document)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var result rest.NDCHttpSettings
+	if err := json.Unmarshal(resolvedBytes, &result); err != nil {
+		return nil, nil, err
+	}
+
+	return &result, keys, nil
+}
+
+func (sr *SecretResolver) resolveEnvStringValues(ctx context.Context, value any, fieldPath string, keys *[]string) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		if reference, ok := sr.getEnvStringReference(v); ok {
+			secret, key, err := sr.resolve(ctx, reference)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", fieldPath, err)
+			}
+
+			if !slices.Contains(*keys, key) {
+				*keys = append(*keys, key)
+			}
+
+			return map[string]any{"value": secret}, nil
+		}
+
+		for key, item := range v {
+			resolved, err := sr.resolveEnvStringValues(ctx, item, joinFieldPath(fieldPath, key), keys)
+			if err != nil {
+				return nil, err
+			}
+
+			v[key] = resolved
+		}
+
+		return v, nil
+	case []any:
+		for i, item := range v {
+			resolved, err := sr.resolveEnvStringValues(ctx, item, fmt.Sprintf("%s[%d]", fieldPath, i), keys)
+			if err != nil {
+				return nil, err
+			}
+
+			v[i] = resolved
+		}
+
+		return v, nil
+	default:
+		return value, nil
+	}
+}
+
+// get the secret reference of the EnvString object. The environment variable takes precedence over the literal value.
+func (sr *SecretResolver) getEnvStringReference(value map[string]any) (string, bool) {
+	if len(value) == 0 || len(value) > 2 {
+		return "", false
+	}
+
+	for key := range value {
+		if key != "value" && key != "env" {
+			return "", false
+		}
+	}
+
+	literal, _ := value["value"].(string)
+	if variable, ok := value["env"].(string); ok {
+		if envValue := os.Getenv(variable); envValue != "" {
+			literal = envValue
+		}
+	}
+
+	if !sr.IsSecretReference(literal) {
+		return "", false
+	}
+
+	return literal, true
+}
+
+// resolve the reference. Returns the secret value and the key of the secret.
+func (sr *SecretResolver) resolve(ctx context.Context, reference string) (string, string, error) {
+	scheme, path, field, ok := sr.parseReference(reference)
+	if !ok {
+		return "", "", fmt.Errorf("invalid secret reference %s", reference)
+	}
+
+	key := scheme + ":" + path
+	sr.lock.Lock()
+	content, ok := sr.cache[key]
+	sr.lock.Unlock()
+
+	if !ok {
+		var err error
+		content, err = sr.providers[scheme].GetSecret(ctx, path)
+		if err != nil {
+			return "", key, fmt.Errorf("failed to get the secret %s: %w", key, err)
+		}
+
+		sr.lock.Lock()
+		sr.cache[key] = content
+		sr.lock.Unlock()
+	}
+
+	if field == "" {
+		return content, key, nil
+	}
+
+	var values map[string]any
+	if err := json.Unmarshal([]byte(content), &values); err != nil {
+		return "", key, fmt.Errorf("the secret %s isn't a JSON object to select the field %s", key, field)
+	}
+
+	fieldValue, ok := values[field]
+	if !ok {
+		return "", key, fmt.Errorf("the field %s doesn't exist in the secret %s", field, key)
+	}
+
+	if str, ok := fieldValue.(string); ok {
+		return str, key, nil
+	}
+
+	fieldBytes, err := json.Marshal(fieldValue)
+	if err != nil {
+		return "", key, err
+	}
+
+	return string(fieldBytes), key, nil
+}
+
+// parse the reference to the scheme, the path and the optional field after the last #.
+func (sr *SecretResolver) parseReference(value string) (string, string, string, bool) {
+	scheme, path, ok := strings.Cut(value, ":")
+	if !ok || path == "" {
+		return "", "", "", false
+	}
+
+	if _, ok := sr.providers[scheme]; !ok {
+		return "", "", "", false
+	}
+
+	var field string
+	if index := strings.LastIndex(path, "#"); index >= 0 {
+		field = path[index+1:]
+		path = path[:index]
+	}
+
+	return scheme, path, field, path != ""
+}
@@ -0,0 +1,220 @@
+package configuration
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	restUtils "github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// get the value of the optional setting, or the value of the default environment variable if the setting isn't set.
+func getEnvStringOrDefaultEnv(value *utils.EnvString, defaultEnv string) (string, error) {
+	if value == nil {
+		return os.Getenv(defaultEnv), nil
+	}
+
+	return value.GetOrDefault("")
+}
+
+// vaultSecretProvider reads secrets from the HTTP API of HashiCorp Vault.
+type vaultSecretProvider struct {
+	address   string
+	token     string
+	namespace string
+	client    *http.Client
+}
+
+func newVaultSecretProvider(settings *VaultSecretsSettings) (*vaultSecretProvider, error) {
+	provider := &vaultSecretProvider{
+		client: http.DefaultClient,
+	}
+
+	for _, field := range []struct {
+		name       string
+		value      *utils.EnvString
+		defaultEnv string
+		target     *string
+	}{
+		{"address", settings.Address, "VAULT_ADDR", &provider.address},
+		{"token", settings.Token, "VAULT_TOKEN", &provider.token},
+		{"namespace", settings.Namespace, "VAULT_NAMESPACE", &provider.namespace},
+	} {
+		value, err := getEnvStringOrDefaultEnv(field.value, field.defaultEnv)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.name, err)
+		}
+
+		*field.target = value
+	}
+
+	if provider.address == "" {
+		return nil, errors.New("address is required")
+	}
+
+	return provider, nil
+}
+
+// GetSecret reads the secret at the path, e.g. secret/data/petstore, and returns key-value pairs as a JSON object.
+// Pairs of the KV version 2 engine are unwrapped from the nested data field.
+func (vsp *vaultSecretProvider) GetSecret(ctx context.Context, path string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(vsp.address, "/")+"/v1/"+strings.TrimPrefix(path, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+
+	if vsp.token != "" {
+		req.Header.Set("X-Vault-Token", vsp.token)
+	}
+
+	if vsp.namespace != "" {
+		req.Header.Set("X-Vault-Namespace", vsp.namespace)
+	}
+
+	respBody, err := doSecretRequest(vsp.client, req)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		Data map[string]any `json:"data"`
+	}
+
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("failed to decode the Vault response: %w", err)
+	}
+
+	data := result.Data
+	if nestedData, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nestedData
+		}
+	}
+
+	dataBytes, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+
+	return string(dataBytes), nil
+}
+
+// awsSecretsManagerProvider reads secrets with the GetSecretValue action of AWS Secrets Manager.
+type awsSecretsManagerProvider struct {
+	credentials restUtils.S3Credentials
+	client      *http.Client
+}
+
+func newAWSSecretsManagerProvider(settings *AWSSecretsManagerSettings) (*awsSecretsManagerProvider, error) {
+	provider := &awsSecretsManagerProvider{
+		client: http.DefaultClient,
+	}
+
+	for _, field := range []struct {
+		name       string
+		value      *utils.EnvString
+		defaultEnv string
+		target     *string
+	}{
+		{"accessKeyId", settings.AccessKeyID, "AWS_ACCESS_KEY_ID", &provider.credentials.AccessKeyID},
+		{"secretAccessKey", settings.SecretAccessKey, "AWS_SECRET_ACCESS_KEY", &provider.credentials.SecretAccessKey},
+		{"sessionToken", settings.SessionToken, "AWS_SESSION_TOKEN", &provider.credentials.SessionToken},
+		{"region", settings.Region, "AWS_REGION", &provider.credentials.Region},
+		{"endpoint", settings.Endpoint, "AWS_ENDPOINT_URL_SECRETS_MANAGER", &provider.credentials.Endpoint},
+	} {
+		value, err := getEnvStringOrDefaultEnv(field.value, field.defaultEnv)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.name, err)
+		}
+
+		*field.target = value
+	}
+
+	if provider.credentials.AccessKeyID == "" || provider.credentials.SecretAccessKey == "" {
+		return nil, errors.New("both the access key ID and the secret access key are required")
+	}
+
+	return provider, nil
+}
+
+// GetSecret gets the secret string of the secret ID, which is the name or the ARN of the secret.
+func (asp *awsSecretsManagerProvider) GetSecret(ctx context.Context, secretID string) (string, error) {
+	region := asp.credentials.Region
+	// arn:aws:secretsmanager:<region>:<account>:secret:<name>
+	if arnParts := strings.Split(secretID, ":"); len(arnParts) > 4 && arnParts[0] == "arn" && arnParts[3] != "" {
+		region = arnParts[3]
+	}
+
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	endpoint := asp.credentials.Endpoint
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://secretsmanager.%s.amazonaws.com", region)
+	}
+
+	payload, err := json.Marshal(map[string]string{"SecretId": secretID})
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(endpoint, "/")+"/", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	restUtils.SignAWSRequest(req, &asp.credentials, region, "secretsmanager", payload, time.Now().UTC())
+
+	respBody, err := doSecretRequest(asp.client, req)
+	if err != nil {
+		return "", err
+	}
+
+	var result struct {
+		SecretString string `json:"SecretString"`
+		SecretBinary string `json:"SecretBinary"`
+	}
+
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", fmt.Errorf("failed to decode the AWS Secrets Manager response: %w", err)
+	}
+
+	if result.SecretString != "" || result.SecretBinary == "" {
+		return result.SecretString, nil
+	}
+
+	binary, err := base64.StdEncoding.DecodeString(result.SecretBinary)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode the secret binary: %w", err)
+	}
+
+	return string(binary), nil
+}
+
+// send the request to the secrets backend and read the response body. Response bodies of errors aren't returned because they may contain sensitive data.
+func doSecretRequest(client *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("the secrets backend responded with the status %s", resp.Status)
+	}
+
+	return io.ReadAll(resp.Body)
+}
@@ -0,0 +1,169 @@
+package configuration
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/utils"
+	"gotest.tools/v3/assert"
+)
+
+func TestSecretResolver(t *testing.T) {
+	var apiKey atomic.Value
+	apiKey.Store("vault-key-1")
+	var vaultRequests atomic.Int32
+
+	vaultServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		vaultRequests.Add(1)
+		if r.Header.Get("X-Vault-Token") != "vault-token" {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		if r.URL.Path != "/v1/secret/data/petstore" {
+			w.WriteHeader(http.StatusNotFound)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"data": map[string]any{
+				"data": map[string]any{
+					"api_key": apiKey.Load(),
+					"port":    8080,
+				},
+				"metadata": map[string]any{"version": 1},
+			},
+		})
+	}))
+	defer vaultServer.Close()
+
+	awsServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" ||
+			!strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=test-key/") ||
+			!strings.Contains(r.Header.Get("Authorization"), "/eu-west-1/secretsmanager/aws4_request") {
+			w.WriteHeader(http.StatusForbidden)
+
+			return
+		}
+
+		var input struct {
+			SecretId string
+		}
+		_ = json.Unmarshal(body, &input)
+		if input.SecretId != "arn:aws:secretsmanager:eu-west-1:123456789012:secret:petstore" {
+			w.WriteHeader(http.StatusBadRequest)
+
+			return
+		}
+
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"SecretString": `{"client_secret":"aws-secret"}`,
+		})
+	}))
+	defer awsServer.Close()
+
+	t.Setenv("TEST_VAULT_ADDR", vaultServer.URL)
+	t.Setenv("TEST_PETSTORE_API_KEY", "vault:secret/data/petstore#api_key")
+
+	resolver, err := NewSecretResolver(&SecretsSettings{
+		Vault: &VaultSecretsSettings{
+			Address: utils.ToPtr(utils.NewEnvStringVariable("TEST_VAULT_ADDR")),
+			Token:   utils.ToPtr(utils.NewEnvStringValue("vault-token")),
+		},
+		AWSSecretsManager: &AWSSecretsManagerSettings{
+			AccessKeyID:     utils.ToPtr(utils.NewEnvStringValue("test-key")),
+			SecretAccessKey: utils.ToPtr(utils.NewEnvStringValue("test-secret")),
+			Endpoint:        utils.ToPtr(utils.NewEnvStringValue(awsServer.URL)),
+		},
+	})
+	assert.NilError(t, err)
+
+	assert.Assert(t, resolver.IsSecretReference("vault:secret/data/petstore#api_key"))
+	assert.Assert(t, !resolver.IsSecretReference("gcpsm:projects/p/secrets/s"))
+	assert.Assert(t, !resolver.IsSecretReference("https://example.com"))
+
+	value, err := resolver.Resolve(context.TODO(), "vault:secret/data/petstore#port")
+	assert.NilError(t, err)
+	assert.Equal(t, "8080", value)
+
+	_, err = resolver.Resolve(context.TODO(), "vault:secret/data/petstore#unknown")
+	assert.ErrorContains(t, err, "the field unknown doesn't exist in the secret vault:secret/data/petstore")
+
+	_, err = resolver.Resolve(context.TODO(), "vault:secret/data/unknown#api_key")
+	assert.ErrorContains(t, err, "404 Not Found")
+
+	schemas := []NDCHttpRuntimeSchema{
+		{
+			Name: "petstore",
+			NDCHttpSchema: &rest.NDCHttpSchema{
+				Settings: &rest.NDCHttpSettings{
+					Servers: []rest.ServerConfig{
+						{URL: utils.NewEnvStringValue("https://petstore.example.com")},
+					},
+					SecuritySchemes: map[string]rest.SecurityScheme{
+						"api_key": {
+							SecuritySchemer: rest.NewAPIKeyAuthConfig("api_key", rest.APIKeyInHeader, utils.NewEnvStringVariable("TEST_PETSTORE_API_KEY")),
+						},
+					},
+					Headers: map[string]utils.EnvString{
+						"X-Client-Secret": utils.NewEnvStringValue("awssm:arn:aws:secretsmanager:eu-west-1:123456789012:secret:petstore#client_secret"),
+					},
+				},
+			},
+		},
+		{
+			Name: "plain",
+			NDCHttpSchema: &rest.NDCHttpSchema{
+				Settings: &rest.NDCHttpSettings{
+					Headers: map[string]utils.EnvString{
+						"X-Plain": utils.NewEnvStringValue("plain"),
+					},
+				},
+			},
+		},
+	}
+
+	results, err := resolver.ResolveSchemas(context.TODO(), schemas)
+	assert.NilError(t, err)
+	assert.Equal(t, "vault-key-1", *results[0].Settings.SecuritySchemes["api_key"].SecuritySchemer.(*rest.APIKeyAuthConfig).Value.Value)
+	assert.Equal(t, "aws-secret", *results[0].Settings.Headers["X-Client-Secret"].Value)
+	assert.Equal(t, "https://petstore.example.com", *results[0].Settings.Servers[0].URL.Value)
+	// input schemas aren't changed
+	assert.Equal(t, "TEST_PETSTORE_API_KEY", *schemas[0].Settings.SecuritySchemes["api_key"].SecuritySchemer.(*rest.APIKeyAuthConfig).Value.Variable)
+	assert.Equal(t, schemas[1].NDCHttpSchema, results[1].NDCHttpSchema)
+	// the secret is fetched once for many references
+	assert.Equal(t, int32(2), vaultRequests.Load())
+
+	changedKeys, err := resolver.Refresh(context.TODO())
+	assert.NilError(t, err)
+	assert.Equal(t, 0, len(changedKeys))
+
+	apiKey.Store("vault-key-2")
+	changedKeys, err = resolver.Refresh(context.TODO())
+	assert.NilError(t, err)
+	assert.DeepEqual(t, []string{"vault:secret/data/petstore"}, changedKeys)
+
+	settings, keys, err := resolver.ResolveSettings(context.TODO(), schemas[0].Settings)
+	assert.NilError(t, err)
+	slices.Sort(keys)
+	assert.DeepEqual(t, []string{"awssm:arn:aws:secretsmanager:eu-west-1:123456789012:secret:petstore", "vault:secret/data/petstore"}, keys)
+	assert.Equal(t, "vault-key-2", *settings.SecuritySchemes["api_key"].SecuritySchemer.(*rest.APIKeyAuthConfig).Value.Value)
+
+	_, err = NewSecretResolver(&SecretsSettings{
+		Vault: &VaultSecretsSettings{
+			Address: utils.ToPtr(utils.NewEnvStringValue("")),
+		},
+	})
+	assert.ErrorContains(t, err, "vault: address is required")
+}
@@ -0,0 +1,234 @@
+package configuration
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+	"github.com/invopop/jsonschema"
+)
+
+// SplitStrategy represents the strategy to split the converted schema into many schema files.
+type SplitStrategy string
+
+const (
+	// SplitByTag groups operations by the first tag, e.g. OpenAPI tags.
+	SplitByTag SplitStrategy = "tag"
+	// SplitByPath groups operations by the first static segment of the URL path, e.g. /pets/{petId} belongs to pets.
+	SplitByPath SplitStrategy = "path"
+)
+
+var splitStrategy_enums = []SplitStrategy{SplitByTag, SplitByPath}
+
+// DefaultSplitGroup is the group of operations which don't have any tag or static path segment.
+const DefaultSplitGroup = "default"
+
+var splitGroupNameRegex = regexp.MustCompile(`[^a-z0-9]+`)
+
+// JSONSchema is used to generate a custom jsonschema.
+func (j SplitStrategy) JSONSchema() *jsonschema.Schema {
+	enums := make([]any, len(splitStrategy_enums))
+	for i, item := range splitStrategy_enums {
+		enums[i] = item
+	}
+
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: enums,
+	}
+}
+
+// ParseSplitStrategy parses the split strategy from string.
+func ParseSplitStrategy(value string) (SplitStrategy, error) {
+	result := SplitStrategy(value)
+	if !slices.Contains(splitStrategy_enums, result) {
+		return result, fmt.Errorf("invalid split strategy %s, expected one of %v", value, splitStrategy_enums)
+	}
+
+	return result, nil
+}
+
+// SchemaIndex represents the index of schema files which are split from a converted schema.
+// The index is referenced by the includes setting of the configuration.
+type SchemaIndex struct {
+	Files []ConfigItem `json:"files" yaml:"files"`
+}
+
+// SplitNDCSchema splits the NDC HTTP schema into many schemas by operation groups.
+// Each schema keeps the settings of the source schema and object and scalar types which are used by its operations.
+// Webhooks follow their registration procedures, or belong to the default group.
+func SplitNDCSchema(ndcSchema *rest.NDCHttpSchema, strategy SplitStrategy) (map[string]*rest.NDCHttpSchema, error) {
+	if _, err := ParseSplitStrategy(string(strategy)); err != nil {
+		return nil, err
+	}
+
+	results := make(map[string]*rest.NDCHttpSchema)
+	operationGroups := make(map[string]string)
+	getGroup := func(name string) *rest.NDCHttpSchema {
+		group, ok := results[name]
+		if !ok {
+			group = &rest.NDCHttpSchema{
+				SchemaRef:   ndcSchema.SchemaRef,
+				Settings:    ndcSchema.Settings,
+				Functions:   map[string]rest.OperationInfo{},
+				Procedures:  map[string]rest.OperationInfo{},
+				ObjectTypes: map[string]rest.ObjectType{},
+				ScalarTypes: schema.SchemaResponseScalarTypes{},
+			}
+			results[name] = group
+		}
+
+		return group
+	}
+
+	for name, fn := range ndcSchema.Functions {
+		groupName := getSplitGroupName(fn, strategy)
+		getGroup(groupName).Functions[name] = fn
+	}
+
+	for name, proc := range ndcSchema.Procedures {
+		groupName := getSplitGroupName(proc, strategy)
+		operationGroups[name] = groupName
+		getGroup(groupName).Procedures[name] = proc
+	}
+
+	for name, webhook := range ndcSchema.Webhooks {
+		groupName, ok := operationGroups[webhook.Operation]
+		if !ok {
+			groupName = DefaultSplitGroup
+		}
+
+		group := getGroup(groupName)
+		if group.Webhooks == nil {
+			group.Webhooks = map[string]rest.WebhookInfo{}
+		}
+		group.Webhooks[name] = webhook
+	}
+
+	for _, group := range results {
+		copyUsedTypes(ndcSchema, group)
+	}
+
+	return results, nil
+}
+
+// GetSplitFilePath returns the file path of the split schema group, e.g. schema.json -> schema-pets.json.
+func GetSplitFilePath(outputPath string, groupName string) string {
+	ext := filepath.Ext(outputPath)
+
+	return strings.TrimSuffix(outputPath, ext) + "-" + groupName + ext
+}
+
+// get the group name of the operation. Names are normalized to be safe in file names.
+func getSplitGroupName(operation rest.OperationInfo, strategy SplitStrategy) string {
+	var name string
+	switch strategy {
+	case SplitByTag:
+		if len(operation.Tags) > 0 {
+			name = operation.Tags[0]
+		}
+	case SplitByPath:
+		if operation.Request != nil {
+			name = getFirstStaticPathSegment(operation.Request.URL)
+		}
+	}
+
+	name = strings.Trim(splitGroupNameRegex.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if name == "" {
+		return DefaultSplitGroup
+	}
+
+	return name
+}
+
+func getFirstStaticPathSegment(rawURL string) string {
+	urlPath, _, _ := strings.Cut(rawURL, "?")
+	if _, after, ok := strings.Cut(urlPath, "://"); ok {
+		_, urlPath, _ = strings.Cut(after, "/")
+	}
+
+	for _, segment := range strings.Split(urlPath, "/") {
+		if segment != "" && !strings.HasPrefix(segment, "{") {
+			return segment
+		}
+	}
+
+	return ""
+}
+
+// copy object and scalar types which are used by operations and webhooks of the target schema
+func copyUsedTypes(source *rest.NDCHttpSchema, target *rest.NDCHttpSchema) {
+	var copyType func(schemaType schema.Type)
+	copyType = func(schemaType schema.Type) {
+		if len(schemaType) == 0 {
+			return
+		}
+
+		name, ok := getNamedTypeName(schemaType)
+		if !ok {
+			return
+		}
+
+		if scalar, ok := source.ScalarTypes[name]; ok {
+			target.ScalarTypes[name] = scalar
+
+			return
+		}
+
+		objectType, ok := source.ObjectTypes[name]
+		if !ok {
+			return
+		}
+
+		if _, copied := target.ObjectTypes[name]; copied {
+			return
+		}
+
+		target.ObjectTypes[name] = objectType
+		for _, field := range objectType.Fields {
+			copyType(field.Type)
+		}
+	}
+
+	for _, operations := range []map[string]rest.OperationInfo{target.Functions, target.Procedures} {
+		for _, operation := range operations {
+			copyType(operation.ResultType)
+			for _, argument := range operation.Arguments {
+				copyType(argument.Type)
+			}
+
+			if operation.Request != nil {
+				for _, errResponse := range operation.Request.Response.Errors {
+					copyType(errResponse.Type)
+				}
+			}
+		}
+	}
+
+	for _, webhook := range target.Webhooks {
+		copyType(webhook.PayloadType)
+	}
+}
+
+// get the name of the underlying named type. Nullable and array types are unwrapped.
+func getNamedTypeName(schemaType schema.Type) (string, bool) {
+	ty, err := schemaType.InterfaceT()
+	if err != nil {
+		return "", false
+	}
+
+	switch t := ty.(type) {
+	case *schema.NullableType:
+		return getNamedTypeName(t.UnderlyingType)
+	case *schema.ArrayType:
+		return getNamedTypeName(t.ElementType)
+	case *schema.NamedType:
+		return t.Name, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,419 @@
+package configuration
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/invopop/jsonschema"
+	"github.com/theory/jsonpath"
+	"github.com/theory/jsonpath/spec"
+)
+
+var transformTemplateRegex = regexp.MustCompile(`\{\{\s*([^}]+?)\s*\}\}`)
+
+// FieldTransformType represents the type of a field transformation.
+type FieldTransformType string
+
+const (
+	// FieldTransformSet sets the field with the value template.
+	FieldTransformSet FieldTransformType = "set"
+	// FieldTransformRename renames the field in the same object.
+	FieldTransformRename FieldTransformType = "rename"
+	// FieldTransformRemove removes the field.
+	FieldTransformRemove FieldTransformType = "remove"
+)
+
+var fieldTransformType_enums = []FieldTransformType{FieldTransformSet, FieldTransformRename, FieldTransformRemove}
+
+// JSONSchema is used to generate a custom jsonschema.
+func (j FieldTransformType) JSONSchema() *jsonschema.Schema {
+	enums := make([]any, len(fieldTransformType_enums))
+	for i, item := range fieldTransformType_enums {
+		enums[i] = item
+	}
+
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: enums,
+	}
+}
+
+// OperationTransformSettings hold transformations of requests and responses of an operation.
+// It adapts small quirks of upstream services, e.g. renamed fields, computed headers or envelope objects.
+type OperationTransformSettings struct {
+	// Transformations of the request before sending to the upstream service.
+	Request *RequestTransformSettings `json:"request,omitempty" yaml:"request,omitempty"`
+	// Transformations of the JSON response before decoding to the result type.
+	Response *ResponseTransformSettings `json:"response,omitempty" yaml:"response,omitempty"`
+}
+
+// Validate checks if the settings are valid.
+func (ots OperationTransformSettings) Validate() error {
+	_, err := NewOperationTransformer(ots)
+
+	return err
+}
+
+// RequestTransformSettings hold transformations of the request.
+// Templates and values are evaluated with the {"arguments": <arguments>, "body": <request body>} document.
+type RequestTransformSettings struct {
+	// Headers to be set. Values are templates with JSON path placeholders, e.g. Bearer {{ $.arguments.token }}
+	Headers map[string]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	// Transformations of the JSON request body.
+	Body []FieldTransform `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// ResponseTransformSettings hold transformations of the JSON response.
+// Templates and values are evaluated with the {"body": <response body>} document.
+type ResponseTransformSettings struct {
+	// The JSON path of the value to be unwrapped from the envelope object before field transformations, e.g. $.data
+	Unwrap string `json:"unwrap,omitempty" yaml:"unwrap,omitempty"`
+	// Transformations of the JSON response body.
+	Body []FieldTransform `json:"body,omitempty" yaml:"body,omitempty"`
+}
+
+// FieldTransform represents a transformation of a field in the JSON body.
+type FieldTransform struct {
+	// The transformation type.
+	Type FieldTransformType `json:"type" yaml:"type"`
+	// The JSON path of the field, e.g. name, items[*].name
+	Path string `json:"path" yaml:"path"`
+	// The new field name of the rename transformation.
+	To string `json:"to,omitempty" yaml:"to,omitempty"`
+	// The value of the set transformation. String values are templates with JSON path placeholders.
+	// If the string is a single placeholder the selected value is set without conversion, e.g. {{ $.arguments.limit }}
+	Value any `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// OperationTransformer applies compiled transformations of an operation.
+type OperationTransformer struct {
+	headers      map[string]*TransformTemplate
+	requestBody  []compiledFieldTransform
+	unwrap       *jsonpath.Path
+	responseBody []compiledFieldTransform
+}
+
+type compiledFieldTransform struct {
+	transformType FieldTransformType
+	segments      []*spec.Segment
+	name          string
+	to            string
+	value         any
+	template      *TransformTemplate
+}
+
+// NewOperationTransformers compiles transformations of operations. Returns nil if there is no transformation.
+func NewOperationTransformers(settings map[string]OperationTransformSettings) (map[string]*OperationTransformer, error) {
+	if len(settings) == 0 {
+		return nil, nil
+	}
+
+	results := make(map[string]*OperationTransformer)
+	for name, setting := range settings {
+		transformer, err := NewOperationTransformer(setting)
+		if err != nil {
+			return nil, fmt.Errorf("transforms.%s.%w", name, err)
+		}
+
+		results[name] = transformer
+	}
+
+	return results, nil
+}
+
+// NewOperationTransformer compiles transformations of an operation.
+func NewOperationTransformer(settings OperationTransformSettings) (*OperationTransformer, error) {
+	result := &OperationTransformer{}
+	if settings.Request != nil {
+		if len(settings.Request.Headers) > 0 {
+			result.headers = make(map[string]*TransformTemplate)
+		}
+
+		for key, header := range settings.Request.Headers {
+			template, err := ParseTransformTemplate(header)
+			if err != nil {
+				return nil, fmt.Errorf("request.headers.%s: %w", key, err)
+			}
+
+			result.headers[key] = template
+		}
+
+		body, err := compileFieldTransforms(settings.Request.Body)
+		if err != nil {
+			return nil, fmt.Errorf("request.body%w", err)
+		}
+
+		result.requestBody = body
+	}
+
+	if settings.Response != nil {
+		if settings.Response.Unwrap != "" {
+			unwrap, err := jsonpath.Parse(settings.Response.Unwrap)
+			if err != nil {
+				return nil, fmt.Errorf("response.unwrap: %w", err)
+			}
+
+			result.unwrap = unwrap
+		}
+
+		body, err := compileFieldTransforms(settings.Response.Body)
+		if err != nil {
+			return nil, fmt.Errorf("response.body%w", err)
+		}
+
+		result.responseBody = body
+	}
+
+	return result, nil
+}
+
+// HasRequestTransforms checks if there is any request transformation.
+func (ot *OperationTransformer) HasRequestTransforms() bool {
+	return ot != nil && (len(ot.headers) > 0 || len(ot.requestBody) > 0)
+}
+
+// HasRequestBodyTransforms checks if there is any transformation of the request body.
+func (ot *OperationTransformer) HasRequestBodyTransforms() bool {
+	return ot != nil && len(ot.requestBody) > 0
+}
+
+// HasResponseTransforms checks if there is any response transformation.
+func (ot *OperationTransformer) HasResponseTransforms() bool {
+	return ot != nil && (ot.unwrap != nil || len(ot.responseBody) > 0)
+}
+
+// RequestHeaders evaluates header templates with arguments and the request body.
+func (ot *OperationTransformer) RequestHeaders(arguments map[string]any, body any) map[string]string {
+	if ot == nil || len(ot.headers) == 0 {
+		return nil
+	}
+
+	root := map[string]any{
+		"arguments": arguments,
+		"body":      body,
+	}
+
+	results := make(map[string]string)
+	for key, template := range ot.headers {
+		results[key] = template.RenderString(root)
+	}
+
+	return results
+}
+
+// TransformRequestBody returns a copy of the request body with transformed fields.
+func (ot *OperationTransformer) TransformRequestBody(arguments map[string]any, body any) any {
+	if ot == nil {
+		return body
+	}
+
+	return applyFieldTransforms(ot.requestBody, body, map[string]any{
+		"arguments": arguments,
+	})
+}
+
+// TransformResponseBody unwraps the response body and returns a copy with transformed fields.
+func (ot *OperationTransformer) TransformResponseBody(body any) any {
+	if ot == nil {
+		return body
+	}
+
+	if ot.unwrap != nil {
+		body = selectTransformValue(ot.unwrap, body)
+	}
+
+	return applyFieldTransforms(ot.responseBody, body, map[string]any{})
+}
+
+func compileFieldTransforms(transforms []FieldTransform) ([]compiledFieldTransform, error) {
+	results := make([]compiledFieldTransform, len(transforms))
+	for i, transform := range transforms {
+		compiled, err := compileFieldTransform(transform)
+		if err != nil {
+			return nil, fmt.Errorf("[%d].%w", i, err)
+		}
+
+		results[i] = *compiled
+	}
+
+	return results, nil
+}
+
+func compileFieldTransform(transform FieldTransform) (*compiledFieldTransform, error) {
+	if !slices.Contains(fieldTransformType_enums, transform.Type) {
+		return nil, fmt.Errorf("type: invalid FieldTransformType. Expected %+v, got <%s>", fieldTransformType_enums, transform.Type)
+	}
+
+	segments, name, err := parseTransformFieldPath(transform.Path)
+	if err != nil {
+		return nil, fmt.Errorf("path: %w", err)
+	}
+
+	result := &compiledFieldTransform{
+		transformType: transform.Type,
+		segments:      segments,
+		name:          name,
+		to:            transform.To,
+		value:         transform.Value,
+	}
+
+	switch transform.Type {
+	case FieldTransformRename:
+		if transform.To == "" {
+			return nil, errors.New("to: required for the rename transformation")
+		}
+	case FieldTransformSet:
+		if rawTemplate, ok := transform.Value.(string); ok {
+			result.template, err = ParseTransformTemplate(rawTemplate)
+			if err != nil {
+				return nil, fmt.Errorf("value: %w", err)
+			}
+		}
+	default:
+	}
+
+	return result, nil
+}
+
+// parse the JSON path of a field relative to the body.
+// Parent segments support name, index and wildcard selectors. The last segment must be a field name.
+func parseTransformFieldPath(rawPath string) ([]*spec.Segment, string, error) {
+	jsonPath, err := rest.ParseFieldJSONPath(rawPath)
+	if err != nil {
+		return nil, "", err
+	}
+
+	segments := jsonPath.Query().Segments()
+	name, ok := segments[len(segments)-1].Selectors()[0].(spec.Name)
+	if !ok {
+		return nil, "", fmt.Errorf("the last segment of json path %s must be a field name", jsonPath.String())
+	}
+
+	return segments[:len(segments)-1], string(name), nil
+}
+
+func applyFieldTransforms(transforms []compiledFieldTransform, body any, root map[string]any) any {
+	for _, transform := range transforms {
+		root["body"] = body
+		// the update function never fails, and parent segments only contain supported selectors
+		body, _ = rest.UpdateFieldsByJSONPath(body, transform.segments, transform.transformType == FieldTransformSet, func(value any) (any, error) {
+			object, ok := value.(map[string]any)
+			if !ok {
+				// missing objects are created by set transformations only
+				if value != nil {
+					return value, nil
+				}
+
+				object = map[string]any{}
+			} else {
+				object = maps.Clone(object)
+			}
+
+			switch transform.transformType {
+			case FieldTransformSet:
+				if transform.template != nil {
+					object[transform.name] = transform.template.Render(root)
+				} else {
+					object[transform.name] = transform.value
+				}
+			case FieldTransformRename:
+				if value, ok := object[transform.name]; ok {
+					delete(object, transform.name)
+					object[transform.to] = value
+				}
+			case FieldTransformRemove:
+				delete(object, transform.name)
+			}
+
+			return object, nil
+		})
+	}
+
+	return body
+}
+
+// TransformTemplate represents a string template with JSON path placeholders, e.g. Bearer {{ $.arguments.token }}
+type TransformTemplate struct {
+	literals []string
+	paths    []*jsonpath.Path
+}
+
+// ParseTransformTemplate parses a template string with JSON path placeholders.
+func ParseTransformTemplate(input string) (*TransformTemplate, error) {
+	result := &TransformTemplate{}
+	var start int
+	for _, match := range transformTemplateRegex.FindAllStringSubmatchIndex(input, -1) {
+		rawPath := input[match[2]:match[3]]
+		if !strings.HasPrefix(rawPath, "$") {
+			return nil, fmt.Errorf("invalid placeholder %s. The json path must start with $", input[match[0]:match[1]])
+		}
+
+		jsonPath, err := jsonpath.Parse(rawPath)
+		if err != nil {
+			return nil, fmt.Errorf("invalid placeholder %s: %w", input[match[0]:match[1]], err)
+		}
+
+		result.literals = append(result.literals, input[start:match[0]])
+		result.paths = append(result.paths, jsonPath)
+		start = match[1]
+	}
+
+	result.literals = append(result.literals, input[start:])
+
+	return result, nil
+}
+
+// Render evaluates the template with the root document.
+// If the template is a single placeholder the selected value is returned without conversion.
+func (tt TransformTemplate) Render(root any) any {
+	if len(tt.paths) == 1 && tt.literals[0] == "" && tt.literals[1] == "" {
+		return selectTransformValue(tt.paths[0], root)
+	}
+
+	return tt.RenderString(root)
+}
+
+// RenderString evaluates the template with the root document to a string.
+// Null values are rendered as empty strings. Non-string values are encoded to JSON.
+func (tt TransformTemplate) RenderString(root any) string {
+	var sb strings.Builder
+	for i, literal := range tt.literals {
+		sb.WriteString(literal)
+		if i >= len(tt.paths) {
+			break
+		}
+
+		switch value := selectTransformValue(tt.paths[i], root).(type) {
+		case nil:
+		case string:
+			sb.WriteString(value)
+		default:
+			rawValue, err := json.Marshal(value)
+			if err == nil {
+				sb.Write(rawValue)
+			}
+		}
+	}
+
+	return sb.String()
+}
+
+// select the value of the JSON path. Singular queries return the first value, other queries return the array of matched values.
+func selectTransformValue(jsonPath *jsonpath.Path, root any) any {
+	values := jsonPath.Select(root)
+	if jsonPath.Query().Singular() == nil {
+		return values
+	}
+
+	if len(values) == 0 {
+		return nil
+	}
+
+	return values[0]
+}
@@ -0,0 +1,111 @@
+package configuration
+
+import (
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestOperationTransformer(t *testing.T) {
+	transformer, err := NewOperationTransformer(OperationTransformSettings{
+		Request: &RequestTransformSettings{
+			Headers: map[string]string{
+				"X-Pet-Name": "pet-{{ $.body.name }}",
+				"X-Limit":    "{{$.arguments.limit}}",
+			},
+			Body: []FieldTransform{
+				{Type: FieldTransformRename, Path: "name", To: "petName"},
+				{Type: FieldTransformRemove, Path: "tags[*].internal"},
+				{Type: FieldTransformSet, Path: "meta.limit", Value: "{{ $.arguments.limit }}"},
+				{Type: FieldTransformSet, Path: "source", Value: "ndc"},
+			},
+		},
+		Response: &ResponseTransformSettings{
+			Unwrap: "$.data",
+			Body: []FieldTransform{
+				{Type: FieldTransformRename, Path: "$[*].pet_name", To: "name"},
+			},
+		},
+	})
+	assert.NilError(t, err)
+	assert.Assert(t, transformer.HasRequestTransforms())
+	assert.Assert(t, transformer.HasResponseTransforms())
+
+	arguments := map[string]any{"limit": float64(10)}
+	body := map[string]any{
+		"name": "Dog",
+		"tags": []any{
+			map[string]any{"id": float64(1), "internal": true},
+		},
+	}
+
+	assert.DeepEqual(t, map[string]string{
+		"X-Pet-Name": "pet-Dog",
+		"X-Limit":    "10",
+	}, transformer.RequestHeaders(arguments, body))
+
+	assert.DeepEqual(t, map[string]any{
+		"petName": "Dog",
+		"tags": []any{
+			map[string]any{"id": float64(1)},
+		},
+		"meta":   map[string]any{"limit": float64(10)},
+		"source": "ndc",
+	}, transformer.TransformRequestBody(arguments, body))
+
+	// the input isn't mutated
+	assert.Equal(t, "Dog", body["name"])
+	assert.Equal(t, true, body["tags"].([]any)[0].(map[string]any)["internal"])
+
+	assert.DeepEqual(t, []any{
+		map[string]any{"id": float64(1), "name": "Dog"},
+	}, transformer.TransformResponseBody(map[string]any{
+		"data": []any{
+			map[string]any{"id": float64(1), "pet_name": "Dog"},
+		},
+	}))
+	assert.Assert(t, transformer.TransformResponseBody(map[string]any{}) == nil)
+}
+
+func TestOperationTransformSettingsValidate(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Settings OperationTransformSettings
+		Error    string
+	}{
+		{
+			Name: "invalid_type",
+			Settings: OperationTransformSettings{
+				Request: &RequestTransformSettings{Body: []FieldTransform{{Type: "move", Path: "name"}}},
+			},
+			Error: "request.body[0].type: invalid FieldTransformType",
+		},
+		{
+			Name: "rename_without_to",
+			Settings: OperationTransformSettings{
+				Response: &ResponseTransformSettings{Body: []FieldTransform{{Type: FieldTransformRename, Path: "name"}}},
+			},
+			Error: "response.body[0].to: required",
+		},
+		{
+			Name: "path_without_field_name",
+			Settings: OperationTransformSettings{
+				Request: &RequestTransformSettings{Body: []FieldTransform{{Type: FieldTransformRemove, Path: "tags[0]"}}},
+			},
+			Error: "must be a field name",
+		},
+		{
+			Name: "invalid_placeholder",
+			Settings: OperationTransformSettings{
+				Request: &RequestTransformSettings{Headers: map[string]string{"X-Name": "{{ arguments.name }}"}},
+			},
+			Error: "request.headers.X-Name: invalid placeholder",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			assert.ErrorContains(t, tc.Settings.Validate(), tc.Error)
+		})
+	}
+}
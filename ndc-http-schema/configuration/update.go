@@ -1,12 +1,14 @@
 package configuration
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/hasura/ndc-http/ndc-http-schema/schema"
 	"github.com/hasura/ndc-http/ndc-http-schema/utils"
@@ -44,7 +46,7 @@ func UpdateHTTPConfiguration(configurationDir string, logger *slog.Logger) (*Con
 			schemas[i] = s
 		}
 
-		if err := utils.WriteSchemaFile(filepath.Join(configurationDir, config.Output), schemas); err != nil {
+		if err := writeSchemaOutputFile(configurationDir, config.Output, schemas, logger); err != nil {
 			return nil, nil, nil, err
 		}
 	}
@@ -52,6 +54,45 @@ func UpdateHTTPConfiguration(configurationDir string, logger *slog.Logger) (*Con
 	return config, schemas, mergedSchema, nil
 }
 
+// write schemas to the output file if the content is changed.
+// Converted times of schemas whose spec content doesn't change are kept, so the output file is only rewritten when upstream specs change.
+func writeSchemaOutputFile(configurationDir string, outputPath string, schemas []NDCHttpRuntimeSchema, logger *slog.Logger) error {
+	existingSchemas, err := ReadSchemaOutputFile(configurationDir, outputPath, logger)
+	if err != nil {
+		logger.Warn(err.Error())
+	}
+
+	for i, s := range schemas {
+		for _, existing := range existingSchemas {
+			if existing.Name == s.Name && existing.Hash != "" && existing.Hash == s.Hash {
+				s.ConvertedAt = existing.ConvertedAt
+				schemas[i] = s
+
+				break
+			}
+		}
+	}
+
+	outputFilePath := filepath.Join(configurationDir, outputPath)
+	format, err := schema.ParseSchemaFileFormat(strings.TrimLeft(filepath.Ext(outputFilePath), "."))
+	if err != nil {
+		return err
+	}
+
+	rawBytes, err := utils.MarshalSchema(schemas, format)
+	if err != nil {
+		return err
+	}
+
+	if existingBytes, err := os.ReadFile(outputFilePath); err == nil && bytes.Equal(existingBytes, rawBytes) {
+		logger.Info("the schema output file is up to date", slog.String("path", outputFilePath))
+
+		return nil
+	}
+
+	return utils.WriteSchemaFile(outputFilePath, schemas)
+}
+
 func printSchemaValidationError(logger *slog.Logger, errors map[string][]string) {
 	logger.Error("errors happen when validating NDC HTTP schemas", slog.Any("errors", errors))
 }
@@ -65,6 +106,27 @@ func ReadConfigurationFile(configurationDir string) (*Configuration, error) {
 			return nil, err
 		}
 
+		if config.EnvTemplate != "" {
+			if err := config.EnvTemplate.Validate(); err != nil {
+				return nil, fmt.Errorf("envTemplate: %w", err)
+			}
+
+			renderedConfig, err := renderConfigurationEnvTemplates(jsonBytes, true, config.EnvTemplate)
+			if err != nil {
+				return nil, err
+			}
+
+			config = *renderedConfig
+		}
+
+		if err := config.loadIncludes(configurationDir); err != nil {
+			return nil, err
+		}
+
+		if err := config.Validate(); err != nil {
+			return nil, err
+		}
+
 		return &config, nil
 	}
 
@@ -93,5 +155,53 @@ func ReadConfigurationFile(configurationDir string) (*Configuration, error) {
 		return nil, err
 	}
 
+	if config.EnvTemplate != "" {
+		if err := config.EnvTemplate.Validate(); err != nil {
+			return nil, fmt.Errorf("envTemplate: %w", err)
+		}
+
+		renderedConfig, err := renderConfigurationEnvTemplates(yamlBytes, false, config.EnvTemplate)
+		if err != nil {
+			return nil, err
+		}
+
+		config = *renderedConfig
+	}
+
+	if err := config.loadIncludes(configurationDir); err != nil {
+		return nil, err
+	}
+
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
 	return &config, nil
 }
+
+// append schema files of index files to the files setting.
+// Relative file paths of index items are resolved from the directory of the index file.
+func (c *Configuration) loadIncludes(configurationDir string) error {
+	for _, include := range c.Includes {
+		rawBytes, err := utils.ReadFileFromPath(utils.ResolveFilePath(configurationDir, include))
+		if err != nil {
+			return fmt.Errorf("includes: %w", err)
+		}
+
+		var index SchemaIndex
+		if err := yaml.Unmarshal(rawBytes, &index); err != nil {
+			return fmt.Errorf("includes: failed to decode the schema index %s: %w", include, err)
+		}
+
+		for _, item := range index.Files {
+			if item.File == "" {
+				return fmt.Errorf("includes: %s: %w", include, errFilePathRequired)
+			}
+
+			item.File = utils.ResolveFilePath(filepath.Dir(include), item.File)
+			c.Files = append(c.Files, item)
+		}
+	}
+
+	return nil
+}
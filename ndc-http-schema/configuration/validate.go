@@ -176,6 +176,10 @@ func (cv *ConfigValidator) evaluateSchema(ndcSchema *NDCHttpRuntimeSchema) error
 
 	cv.validateArgumentPresets(ndcSchema.Name, "settings.argumentPresets", ndcSchema.Settings.ArgumentPresets, true)
 
+	for i, encryption := range ndcSchema.Settings.Encryption {
+		cv.validateFieldEncryption(ndcSchema.Name, fmt.Sprintf("settings.encryption[%d]", i), encryption)
+	}
+
 	for i, server := range ndcSchema.Settings.Servers {
 		serverPath := fmt.Sprintf("settings.server[%d]", i)
 		if server.URL.Value == nil {
@@ -233,6 +237,25 @@ func (cv *ConfigValidator) validateArgumentPresets(namespace string, key string,
 	}
 }
 
+func (cv *ConfigValidator) validateFieldEncryption(namespace string, key string, encryption schema.FieldEncryptionConfig) {
+	if _, _, _, err := encryption.Validate(); err != nil {
+		cv.addError(namespace, fmt.Sprintf("%s: %s", key, err))
+
+		return
+	}
+
+	_, err := encryption.Key.Get()
+	if err == nil {
+		return
+	}
+
+	if encryption.Key.Variable != nil {
+		cv.requiredVariables[*encryption.Key.Variable] = true
+	} else {
+		cv.addError(namespace, fmt.Sprintf("%s.key: %s", key, err))
+	}
+}
+
 func (cv *ConfigValidator) validateTLS(namespace string, key string, tlsConfig *schema.TLSConfig) {
 	if tlsConfig.CAPem != nil || tlsConfig.CAFile != nil {
 		var err error
@@ -339,6 +362,26 @@ func (cv *ConfigValidator) validateSecurityScheme(namespace string, key string,
 		if err != nil && schemer.Password.Variable != nil {
 			cv.requiredVariables[*schemer.Password.Variable] = true
 		}
+	case *schema.DigestAuthConfig:
+		_, err := schemer.Username.Get()
+		if err != nil && schemer.Username.Variable != nil {
+			cv.requiredVariables[*schemer.Username.Variable] = true
+		}
+
+		_, err = schemer.Password.Get()
+		if err != nil && schemer.Password.Variable != nil {
+			cv.requiredVariables[*schemer.Password.Variable] = true
+		}
+	case *schema.HMACAuthConfig:
+		_, err := schemer.Secret.Get()
+		if err != nil && schemer.Secret.Variable != nil {
+			cv.requiredVariables[*schemer.Secret.Variable] = true
+		}
+	case *schema.JWTAuthConfig:
+		_, err := schemer.PrivateKey.Get()
+		if err != nil && schemer.PrivateKey.Variable != nil {
+			cv.requiredVariables[*schemer.PrivateKey.Variable] = true
+		}
 	case *schema.MutualTLSAuthConfig:
 	case *schema.OAuth2Config:
 		for flowType, flow := range schemer.Flows {
@@ -19,6 +19,8 @@ var cli struct {
 	Update    command.UpdateCommandArguments        `cmd:""          help:"Update HTTP connector configuration"`
 	Convert   configuration.ConvertCommandArguments `cmd:""          help:"Convert API spec to NDC schema. For example:\n ndc-http-schema convert -f petstore.yaml -o petstore.json"`
 	Json2Yaml command.Json2YamlCommandArguments     `cmd:""          help:"Convert JSON file to YAML. For example:\n ndc-http-schema json2yaml -f petstore.json -o petstore.yaml"    name:"json2yaml"`
+	Validate  command.ValidateCommandArguments      `cmd:""          help:"Validate HTTP connector configuration and schema files without updating them"`
+	Diff      command.DiffCommandArguments          `cmd:""          help:"Compare two NDC HTTP schemas and report breaking changes. For example:\n ndc-http-schema diff old.json new.json"`
 	Version   struct{}                              `cmd:""          help:"Print the CLI version."`
 }
 
@@ -35,10 +37,14 @@ func main() {
 	switch cmd.Command() {
 	case "update":
 		err = command.UpdateConfiguration(&cli.Update, logger, cli.NoColor)
+	case "validate":
+		err = command.ValidateConfiguration(&cli.Validate, logger, cli.NoColor)
 	case "convert":
 		err = command.CommandConvertToNDCSchema(&cli.Convert, logger)
 	case "json2yaml":
 		err = command.Json2Yaml(&cli.Json2Yaml, logger)
+	case "diff <old> <new>":
+		err = command.DiffSchemas(&cli.Diff, logger)
 	case "version":
 		_, _ = fmt.Fprint(os.Stdout, version.BuildVersion)
 	default:
@@ -0,0 +1,17 @@
+package openapi
+
+import (
+	"github.com/hasura/ndc-http/ndc-http-schema/openapi/internal"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+// AsyncAPIToNDCSchema converts an AsyncAPI 2.x or 3.x document to NDC HTTP schema.
+// Messages which the application sends are converted to webhooks with payload object types.
+func AsyncAPIToNDCSchema(input []byte, options ConvertOptions) (*rest.NDCHttpSchema, []error) {
+	document, err := internal.AsyncAPIToOpenAPI(input)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return OpenAPIv3ToNDCSchema(document, options)
+}
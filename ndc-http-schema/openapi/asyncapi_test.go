@@ -0,0 +1,113 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestAsyncAPIToNDCSchema(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Source   string
+		Expected string
+		Schema   string
+		Options  ConvertOptions
+	}{
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/asyncapi/source.yaml -o ./ndc-http-schema/openapi/testdata/asyncapi/expected.json --spec asyncapi
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/asyncapi/source.yaml -o ./ndc-http-schema/openapi/testdata/asyncapi/schema.json --pure --spec asyncapi
+		{
+			Name:     "asyncapi3",
+			Source:   "testdata/asyncapi/source.yaml",
+			Expected: "testdata/asyncapi/expected.json",
+			Schema:   "testdata/asyncapi/schema.json",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assert.NilError(t, err)
+
+			expectedBytes, err := os.ReadFile(tc.Expected)
+			assert.NilError(t, err)
+			var expected schema.NDCHttpSchema
+			assert.NilError(t, json.Unmarshal(expectedBytes, &expected))
+
+			output, errs := AsyncAPIToNDCSchema(sourceBytes, tc.Options)
+			if output == nil {
+				t.Fatal(errors.Join(errs...))
+			}
+
+			assertRESTSchemaEqual(t, &expected, output)
+			assertConnectorSchema(t, tc.Schema, output)
+		})
+	}
+
+	t.Run("asyncapi2", func(t *testing.T) {
+		source := `{
+  "asyncapi": "2.6.0",
+  "info": { "title": "Account Service", "version": "1.0.0" },
+  "channels": {
+    "user/signedup": {
+      "subscribe": {
+        "operationId": "onUserSignedUp",
+        "message": {
+          "oneOf": [
+            { "$ref": "#/components/messages/UserSignedUp" },
+            { "payload": { "type": "object", "properties": { "email": { "type": "string" } } } }
+          ]
+        }
+      }
+    },
+    "user/delete": {
+      "publish": {
+        "message": { "$ref": "#/components/messages/UserSignedUp" }
+      }
+    }
+  },
+  "components": {
+    "messages": {
+      "UserSignedUp": {
+        "payload": { "$ref": "#/components/schemas/User" }
+      }
+    },
+    "schemas": {
+      "User": {
+        "type": "object",
+        "properties": { "displayName": { "type": "string" } }
+      }
+    }
+  }
+}`
+
+		output, errs := AsyncAPIToNDCSchema([]byte(source), ConvertOptions{})
+		if output == nil {
+			t.Fatal(errors.Join(errs...))
+		}
+
+		assert.Equal(t, len(output.Webhooks), 1)
+		webhook, ok := output.Webhooks["onUserSignedUp"]
+		assert.Assert(t, ok)
+		assert.Equal(t, webhook.Method, "post")
+		assert.Equal(t, webhook.ContentType, "application/json")
+		payloadName, err := webhook.PayloadType.AsNamed()
+		assert.NilError(t, err)
+		assert.Equal(t, payloadName.Name, "OnUserSignedUpPayload")
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, errs := AsyncAPIToNDCSchema([]byte(`{"asyncapi": "1.2.0"}`), ConvertOptions{})
+		assert.ErrorContains(t, errors.Join(errs...), "unsupported AsyncAPI version")
+
+		_, errs = AsyncAPIToNDCSchema([]byte(`{"asyncapi": "3.0.0", "operations": {"receive": {"action": "receive"}}}`), ConvertOptions{})
+		assert.ErrorContains(t, errors.Join(errs...), "there is no message which the application sends")
+
+		_, errs = AsyncAPIToNDCSchema([]byte(`{"asyncapi": "3.0.0", "operations": {"send": {"action": "send", "channel": {"$ref": "#/channels/unknown"}}}}`), ConvertOptions{})
+		assert.ErrorContains(t, errors.Join(errs...), "reference #/channels/unknown does not exist")
+	})
+}
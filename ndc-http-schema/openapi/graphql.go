@@ -0,0 +1,75 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/openapi/internal"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// GraphQLToNDCSchema converts a GraphQL schema to NDC HTTP schema.
+// The input can be either a GraphQL SDL document or the JSON result of the introspection query.
+// The server URL is the default URL of the GraphQL endpoint, and can be empty.
+func GraphQLToNDCSchema(input []byte, serverURL string, options ConvertOptions) (*rest.NDCHttpSchema, []error) {
+	var gqlSchema *ast.Schema
+	var err error
+
+	trimmedInput := bytes.TrimSpace(input)
+	if len(trimmedInput) > 0 && trimmedInput[0] == '{' && json.Valid(trimmedInput) {
+		gqlSchema, err = internal.ParseGraphQLIntrospection(trimmedInput)
+	} else {
+		gqlSchema, err = gqlparser.LoadSchema(&ast.Source{
+			Name:  "schema.graphql",
+			Input: string(input),
+		})
+	}
+
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	result, err := internal.NewGraphQLBuilder(internal.ConvertOptions(options)).BuildSchema(gqlSchema, serverURL)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return result, nil
+}
+
+// IntrospectGraphQL sends the introspection query to the GraphQL endpoint and returns the JSON result.
+func IntrospectGraphQL(endpoint string) ([]byte, error) {
+	reqBody, err := json.Marshal(map[string]string{
+		"query": internal.GraphQLIntrospectionQuery,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := http.DefaultClient.Post(endpoint, rest.ContentTypeJSON, bytes.NewReader(reqBody))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	result, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the introspection result from %s: %w", endpoint, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errorMsg := string(result)
+		if errorMsg == "" {
+			errorMsg = resp.Status
+		}
+
+		return nil, fmt.Errorf("failed to introspect the GraphQL schema from %s: %s", endpoint, errorMsg)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,123 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestGraphQLToNDCSchema(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Source   string
+		Expected string
+		Schema   string
+		Options  ConvertOptions
+	}{
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/graphql/source.graphql -o ./ndc-http-schema/openapi/testdata/graphql/expected.json --spec graphql --env-prefix PET_STORE --no-deprecation
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/graphql/source.graphql -o ./ndc-http-schema/openapi/testdata/graphql/schema.json --pure --spec graphql --env-prefix PET_STORE --no-deprecation
+		{
+			Name:     "graphql",
+			Source:   "testdata/graphql/source.graphql",
+			Expected: "testdata/graphql/expected.json",
+			Schema:   "testdata/graphql/schema.json",
+			Options: ConvertOptions{
+				EnvPrefix:     "PET_STORE",
+				NoDeprecation: true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assert.NilError(t, err)
+
+			expectedBytes, err := os.ReadFile(tc.Expected)
+			assert.NilError(t, err)
+			var expected schema.NDCHttpSchema
+			assert.NilError(t, json.Unmarshal(expectedBytes, &expected))
+
+			output, errs := GraphQLToNDCSchema(sourceBytes, "", tc.Options)
+			if output == nil {
+				t.Fatal(errors.Join(errs...))
+			}
+
+			assertRESTSchemaEqual(t, &expected, output)
+			assertConnectorSchema(t, tc.Schema, output)
+		})
+	}
+
+	t.Run("introspection", func(t *testing.T) {
+		introspection := `{
+			"data": {
+				"__schema": {
+					"queryType": { "name": "Query" },
+					"mutationType": null,
+					"types": [
+						{
+							"kind": "OBJECT",
+							"name": "Query",
+							"fields": [
+								{
+									"name": "users",
+									"args": [
+										{
+											"name": "ids",
+											"type": { "kind": "LIST", "name": null, "ofType": { "kind": "NON_NULL", "name": null, "ofType": { "kind": "SCALAR", "name": "ID" } } },
+											"defaultValue": null
+										}
+									],
+									"type": { "kind": "NON_NULL", "name": null, "ofType": { "kind": "LIST", "name": null, "ofType": { "kind": "OBJECT", "name": "User" } } }
+								}
+							]
+						},
+						{
+							"kind": "OBJECT",
+							"name": "User",
+							"fields": [
+								{ "name": "id", "args": [], "type": { "kind": "NON_NULL", "name": null, "ofType": { "kind": "SCALAR", "name": "ID" } } },
+								{ "name": "age", "args": [], "type": { "kind": "SCALAR", "name": "Int" } }
+							]
+						},
+						{ "kind": "SCALAR", "name": "ID" },
+						{ "kind": "SCALAR", "name": "Int" }
+					]
+				}
+			}
+		}`
+
+		output, errs := GraphQLToNDCSchema([]byte(introspection), "http://localhost:8080/graphql", ConvertOptions{})
+		if output == nil {
+			t.Fatal(errors.Join(errs...))
+		}
+
+		serverURL, err := output.Settings.Servers[0].URL.Get()
+		assert.NilError(t, err)
+		assert.Equal(t, "http://localhost:8080/graphql", serverURL)
+
+		fn, ok := output.Functions["users"]
+		assert.Assert(t, ok)
+		assert.DeepEqual(t, &schema.GraphQLRequest{
+			Query:         "query users($ids: [ID!]) {\n  users(ids: $ids) {\n    id\n    age\n  }\n}",
+			OperationName: "users",
+			Variables:     []string{"ids"},
+			ResultField:   "users",
+		}, fn.Request.GraphQL)
+		assert.Equal(t, 2, len(output.ObjectTypes["User"].Fields))
+	})
+
+	t.Run("failure_no_operation", func(t *testing.T) {
+		_, errs := GraphQLToNDCSchema([]byte("type Query { _empty: String @deprecated }"), "", ConvertOptions{NoDeprecation: true})
+		assert.ErrorContains(t, errors.Join(errs...), "there is no query or mutation field to be converted")
+	})
+
+	t.Run("failure_invalid_sdl", func(t *testing.T) {
+		_, errs := GraphQLToNDCSchema([]byte("type Query {"), "", ConvertOptions{})
+		assert.Assert(t, len(errs) > 0)
+	})
+}
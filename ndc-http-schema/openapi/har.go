@@ -0,0 +1,17 @@
+package openapi
+
+import (
+	"github.com/hasura/ndc-http/ndc-http-schema/openapi/internal"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+// HARToNDCSchema converts a HTTP Archive (HAR) file to NDC HTTP schema.
+// Argument and result types are inferred from recorded requests and responses.
+func HARToNDCSchema(input []byte, options ConvertOptions) (*rest.NDCHttpSchema, []error) {
+	result, err := internal.NewHARBuilder(internal.ConvertOptions(options)).BuildSchema(input)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return result, nil
+}
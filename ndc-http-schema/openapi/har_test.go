@@ -0,0 +1,76 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestHARToNDCSchema(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Source   string
+		Expected string
+		Schema   string
+		Options  ConvertOptions
+	}{
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/har/source.har -o ./ndc-http-schema/openapi/testdata/har/expected.json --spec har --env-prefix SHOP
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/har/source.har -o ./ndc-http-schema/openapi/testdata/har/schema.json --pure --spec har --env-prefix SHOP
+		{
+			Name:     "har",
+			Source:   "testdata/har/source.har",
+			Expected: "testdata/har/expected.json",
+			Schema:   "testdata/har/schema.json",
+			Options: ConvertOptions{
+				EnvPrefix: "SHOP",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assert.NilError(t, err)
+
+			expectedBytes, err := os.ReadFile(tc.Expected)
+			assert.NilError(t, err)
+			var expected schema.NDCHttpSchema
+			assert.NilError(t, json.Unmarshal(expectedBytes, &expected))
+
+			output, errs := HARToNDCSchema(sourceBytes, tc.Options)
+			if output == nil {
+				t.Fatal(errors.Join(errs...))
+			}
+
+			assertRESTSchemaEqual(t, &expected, output)
+			assertConnectorSchema(t, tc.Schema, output)
+		})
+	}
+
+	t.Run("path_templates", func(t *testing.T) {
+		source := `{"log": {"entries": [
+			{"request": {"method": "GET", "url": "http://localhost:8080/categories/5f1d7f3b9c1e4a2b3c4d5e6f/products/12/reviews"}, "response": {"status": 200, "content": {"mimeType": "application/json", "text": "[]"}}},
+			{"request": {"method": "GET", "url": "http://localhost:8080/addresses/1/1"}, "response": {"status": 200, "content": {"mimeType": "application/json", "text": "{}"}}}
+		]}}`
+
+		output, errs := HARToNDCSchema([]byte(source), ConvertOptions{})
+		if output == nil {
+			t.Fatal(errors.Join(errs...))
+		}
+
+		assert.Equal(t, "/categories/{categoryId}/products/{productId}/reviews", output.Functions["getCategoriesCategoryIdProductsProductIdReviews"].Request.URL)
+		assert.Equal(t, "/addresses/{addressId}/{id}", output.Functions["getAddressesAddressIdId"].Request.URL)
+	})
+
+	t.Run("failure_no_request", func(t *testing.T) {
+		source := `{"log": {"entries": [
+			{"_resourceType": "image", "request": {"method": "GET", "url": "https://example.com/logo.png"}, "response": {"status": 200, "content": {"mimeType": "image/png"}}}
+		]}}`
+		_, errs := HARToNDCSchema([]byte(source), ConvertOptions{})
+		assert.ErrorContains(t, errors.Join(errs...), "there is no API request in the HAR file to be converted")
+	})
+}
@@ -0,0 +1,17 @@
+package openapi
+
+import (
+	"github.com/hasura/ndc-http/ndc-http-schema/openapi/internal"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+// InsomniaToNDCSchema converts an Insomnia export file v4 to NDC HTTP schema.
+// GET requests are converted to functions and other requests to procedures.
+func InsomniaToNDCSchema(input []byte, options ConvertOptions) (*rest.NDCHttpSchema, []error) {
+	result, err := internal.NewInsomniaBuilder(internal.ConvertOptions(options)).BuildSchema(input)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return result, nil
+}
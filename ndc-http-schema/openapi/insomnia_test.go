@@ -0,0 +1,58 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestInsomniaToNDCSchema(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Source   string
+		Expected string
+		Schema   string
+		Options  ConvertOptions
+	}{
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/insomnia/source.json -o ./ndc-http-schema/openapi/testdata/insomnia/expected.json --spec insomnia --env-prefix LIBRARY
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/insomnia/source.json -o ./ndc-http-schema/openapi/testdata/insomnia/schema.json --pure --spec insomnia --env-prefix LIBRARY
+		{
+			Name:     "insomnia",
+			Source:   "testdata/insomnia/source.json",
+			Expected: "testdata/insomnia/expected.json",
+			Schema:   "testdata/insomnia/schema.json",
+			Options: ConvertOptions{
+				EnvPrefix: "LIBRARY",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assert.NilError(t, err)
+
+			expectedBytes, err := os.ReadFile(tc.Expected)
+			assert.NilError(t, err)
+			var expected schema.NDCHttpSchema
+			assert.NilError(t, json.Unmarshal(expectedBytes, &expected))
+
+			output, errs := InsomniaToNDCSchema(sourceBytes, tc.Options)
+			if output == nil {
+				t.Fatal(errors.Join(errs...))
+			}
+
+			assertRESTSchemaEqual(t, &expected, output)
+			assertConnectorSchema(t, tc.Schema, output)
+		})
+	}
+
+	t.Run("failure_no_request", func(t *testing.T) {
+		_, errs := InsomniaToNDCSchema([]byte(`{"_type": "export", "__export_format": 4, "resources": [{"_id": "wrk_1", "_type": "workspace"}]}`), ConvertOptions{})
+		assert.ErrorContains(t, errors.Join(errs...), "there is no request in the Insomnia export file to be converted")
+	})
+}
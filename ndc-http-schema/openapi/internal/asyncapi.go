@@ -0,0 +1,289 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// AsyncAPIToOpenAPI translates an AsyncAPI 2.x or 3.x document to an OpenAPI 3.1 document.
+// Messages which the application sends are converted to webhooks, so payload types are built by the OpenAPI 3 builder.
+// Subscribe operations of AsyncAPI 2.x and send operations of AsyncAPI 3.x are supported.
+func AsyncAPIToOpenAPI(input []byte) ([]byte, error) {
+	var document map[string]any
+	if err := yaml.Unmarshal(input, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode the AsyncAPI document: %w", err)
+	}
+
+	version, _ := document["asyncapi"].(string)
+	builder := &asyncAPIBuilder{
+		document: document,
+		webhooks: map[string]any{},
+	}
+
+	var err error
+	switch {
+	case strings.HasPrefix(version, "2."):
+		err = builder.buildChannelsV2()
+	case strings.HasPrefix(version, "3."):
+		err = builder.buildOperationsV3()
+	default:
+		return nil, fmt.Errorf("unsupported AsyncAPI version %q, expected 2.x or 3.x", version)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(builder.webhooks) == 0 {
+		return nil, errNoAsyncAPIMessage
+	}
+
+	result := map[string]any{
+		"openapi":  "3.1.0",
+		"info":     document["info"],
+		"webhooks": builder.webhooks,
+	}
+
+	if servers := builder.buildServers(); len(servers) > 0 {
+		result["servers"] = servers
+	}
+
+	if components, ok := document["components"].(map[string]any); ok && components["schemas"] != nil {
+		result["components"] = map[string]any{
+			"schemas": components["schemas"],
+		}
+	}
+
+	return json.Marshal(result)
+}
+
+type asyncAPIBuilder struct {
+	document map[string]any
+	webhooks map[string]any
+}
+
+// convert subscribe operations of channels in AsyncAPI 2.x documents.
+// The application publishes messages to subscribers of these channels.
+func (ab *asyncAPIBuilder) buildChannelsV2() error {
+	channels, _ := ab.document["channels"].(map[string]any)
+	for _, address := range sdkUtils.GetSortedKeys(channels) {
+		channel, err := ab.resolve(channels[address])
+		if err != nil {
+			return fmt.Errorf("channels.%s: %w", address, err)
+		}
+
+		operation, ok := channel["subscribe"].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		var messages []map[string]any
+		rawMessage, err := ab.resolve(operation["message"])
+		if err != nil {
+			return fmt.Errorf("channels.%s: %w", address, err)
+		}
+
+		if oneOf, ok := rawMessage["oneOf"].([]any); ok {
+			for _, item := range oneOf {
+				message, err := ab.resolve(item)
+				if err != nil {
+					return fmt.Errorf("channels.%s: %w", address, err)
+				}
+				messages = append(messages, message)
+			}
+		} else if rawMessage != nil {
+			messages = append(messages, rawMessage)
+		}
+
+		name, _ := operation["operationId"].(string)
+		if name == "" {
+			name = utils.ToCamelCase(address)
+		}
+
+		ab.addWebhook(name, operation, messages)
+	}
+
+	return nil
+}
+
+// convert send operations of AsyncAPI 3.x documents.
+// All messages of the channel are sent if the operation doesn't specify messages.
+func (ab *asyncAPIBuilder) buildOperationsV3() error {
+	operations, _ := ab.document["operations"].(map[string]any)
+	for _, name := range sdkUtils.GetSortedKeys(operations) {
+		operation, err := ab.resolve(operations[name])
+		if err != nil {
+			return fmt.Errorf("operations.%s: %w", name, err)
+		}
+
+		if action, _ := operation["action"].(string); action != "send" {
+			continue
+		}
+
+		channel, err := ab.resolve(operation["channel"])
+		if err != nil {
+			return fmt.Errorf("operations.%s: channel: %w", name, err)
+		}
+
+		var rawMessages []any
+		if items, ok := operation["messages"].([]any); ok {
+			rawMessages = items
+		} else if channelMessages, ok := channel["messages"].(map[string]any); ok {
+			for _, key := range sdkUtils.GetSortedKeys(channelMessages) {
+				rawMessages = append(rawMessages, channelMessages[key])
+			}
+		}
+
+		messages := make([]map[string]any, 0, len(rawMessages))
+		for _, item := range rawMessages {
+			message, err := ab.resolve(item)
+			if err != nil {
+				return fmt.Errorf("operations.%s: messages: %w", name, err)
+			}
+			messages = append(messages, message)
+		}
+
+		ab.addWebhook(name, operation, messages)
+	}
+
+	return nil
+}
+
+// add a webhook whose request body is the message payload.
+// The payload is the union of message payloads if the operation sends many messages.
+func (ab *asyncAPIBuilder) addWebhook(name string, operation map[string]any, messages []map[string]any) {
+	contentType := rest.ContentTypeJSON
+	if defaultContentType, ok := ab.document["defaultContentType"].(string); ok && defaultContentType != "" {
+		contentType = defaultContentType
+	}
+
+	payloads := []any{}
+	for _, message := range messages {
+		payload := message["payload"]
+		// AsyncAPI 3.x allows multi-format schema objects
+		if multiFormat, ok := payload.(map[string]any); ok && multiFormat["schemaFormat"] != nil {
+			payload = multiFormat["schema"]
+		}
+
+		if payload == nil {
+			continue
+		}
+
+		if messageContentType, ok := message["contentType"].(string); ok && messageContentType != "" {
+			contentType = messageContentType
+		}
+		payloads = append(payloads, payload)
+	}
+
+	if len(payloads) == 0 {
+		return
+	}
+
+	payload := payloads[0]
+	if len(payloads) > 1 {
+		payload = map[string]any{
+			"oneOf": payloads,
+		}
+	}
+
+	webhookOperation := map[string]any{
+		"operationId": name,
+		"requestBody": map[string]any{
+			"content": map[string]any{
+				contentType: map[string]any{
+					"schema": payload,
+				},
+			},
+		},
+		"responses": map[string]any{
+			"200": map[string]any{
+				"description": "The message is received",
+			},
+		},
+	}
+
+	for _, key := range []string{"summary", "description"} {
+		if value, ok := operation[key].(string); ok && value != "" {
+			webhookOperation[key] = value
+		}
+	}
+
+	ab.webhooks[name] = map[string]any{
+		"post": webhookOperation,
+	}
+}
+
+// convert HTTP servers of the document. Servers of other protocols are ignored.
+func (ab *asyncAPIBuilder) buildServers() []any {
+	servers, _ := ab.document["servers"].(map[string]any)
+	results := []any{}
+	for _, key := range sdkUtils.GetSortedKeys(servers) {
+		server, ok := servers[key].(map[string]any)
+		if !ok {
+			continue
+		}
+
+		protocol, _ := server["protocol"].(string)
+		if protocol != "http" && protocol != "https" {
+			continue
+		}
+
+		// AsyncAPI 3.x splits the url into host and pathname
+		serverURL, _ := server["url"].(string)
+		if host, ok := server["host"].(string); ok && host != "" {
+			pathname, _ := server["pathname"].(string)
+			serverURL = protocol + "://" + host + pathname
+		}
+
+		if serverURL != "" {
+			results = append(results, map[string]any{
+				"url": serverURL,
+			})
+		}
+	}
+
+	return results
+}
+
+// resolve the object and follow local references, e.g. #/components/messages/OrderCreated.
+func (ab *asyncAPIBuilder) resolve(value any) (map[string]any, error) {
+	for range 10 {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+
+		ref, ok := object["$ref"].(string)
+		if !ok {
+			return object, nil
+		}
+
+		if !strings.HasPrefix(ref, "#/") {
+			return nil, fmt.Errorf("unsupported reference %s, only local references are supported", ref)
+		}
+
+		var current any = ab.document
+		for _, segment := range strings.Split(ref[2:], "/") {
+			segment = strings.ReplaceAll(strings.ReplaceAll(segment, "~1", "/"), "~0", "~")
+			parent, ok := current.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("reference %s does not exist", ref)
+			}
+
+			current, ok = parent[segment]
+			if !ok {
+				return nil, fmt.Errorf("reference %s does not exist", ref)
+			}
+		}
+
+		value = current
+	}
+
+	return nil, fmt.Errorf("too many nested references of %v", value)
+}
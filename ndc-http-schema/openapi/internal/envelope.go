@@ -0,0 +1,240 @@
+package internal
+
+import (
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-sdk-go/schema"
+)
+
+// the suffix of object types which are flattened from enveloped types
+const flatObjectSuffix = "Flat"
+
+// getResponseEnvelope detects the standard envelope from the vendored media type of the response.
+func getResponseEnvelope(contentType string) rest.ResponseEnvelope {
+	switch strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0])) {
+	case "application/vnd.api+json":
+		return rest.ResponseEnvelopeJSONAPI
+	case "application/hal+json":
+		return rest.ResponseEnvelopeHAL
+	default:
+		return ""
+	}
+}
+
+// envelopeTypeBuilder rewrites result types of enveloped responses to flat object types,
+// which match results of the unwrapped envelope at runtime.
+type envelopeTypeBuilder struct {
+	schema *rest.NDCHttpSchema
+	// names of flattened object types, keyed by the original names
+	flatTypes map[string]string
+}
+
+// unwrapEnvelopeResultType returns the flat result type of the envelope.
+// Returns false if the result type doesn't match the envelope structure.
+func unwrapEnvelopeResultType(httpSchema *rest.NDCHttpSchema, envelope rest.ResponseEnvelope, resultType schema.TypeEncoder) (schema.TypeEncoder, bool) {
+	builder := &envelopeTypeBuilder{
+		schema:    httpSchema,
+		flatTypes: map[string]string{},
+	}
+
+	switch envelope {
+	case rest.ResponseEnvelopeJSONAPI:
+		return builder.unwrapJSONAPIDocument(resultType.Encode())
+	case rest.ResponseEnvelopeHAL:
+		name, ok := getNamedObjectType(httpSchema, resultType.Encode())
+		if !ok || !isHALObjectType(httpSchema.ObjectTypes[name]) {
+			return resultType, false
+		}
+
+		return builder.flattenHALType(resultType.Encode()), true
+	default:
+		return resultType, false
+	}
+}
+
+func (etb *envelopeTypeBuilder) unwrapJSONAPIDocument(documentType schema.Type) (schema.TypeEncoder, bool) {
+	name, ok := getNamedObjectType(etb.schema, documentType)
+	if !ok {
+		return nil, false
+	}
+
+	dataField, ok := etb.schema.ObjectTypes[name].Fields["data"]
+	if !ok {
+		return nil, false
+	}
+
+	resultType := etb.rewriteNamedType(dataField.Type, etb.flattenJSONAPIResource)
+	if _, isNullable := documentType.Interface().(*schema.NullableType); isNullable {
+		if _, ok := resultType.Encode().Interface().(*schema.NullableType); !ok {
+			resultType = schema.NewNullableType(resultType)
+		}
+	}
+
+	return resultType, true
+}
+
+// flattenJSONAPIResource creates a flat object type with the id, type, attributes and relationships of the resource object.
+func (etb *envelopeTypeBuilder) flattenJSONAPIResource(name string) string {
+	if flatName, ok := etb.flatTypes[name]; ok {
+		return flatName
+	}
+
+	objectType, ok := etb.schema.ObjectTypes[name]
+	if !ok {
+		return name
+	}
+
+	_, hasAttributes := objectType.Fields["attributes"]
+	_, hasRelationships := objectType.Fields["relationships"]
+	if !hasAttributes && !hasRelationships {
+		return name
+	}
+
+	flatName := name + flatObjectSuffix
+	etb.flatTypes[name] = flatName
+	flatObject := rest.ObjectType{
+		Description: objectType.Description,
+		Fields:      map[string]rest.ObjectField{},
+	}
+
+	for key, field := range objectType.Fields {
+		switch key {
+		case "attributes", "relationships", "links", "meta":
+		default:
+			flatObject.Fields[key] = field
+		}
+	}
+
+	if attributes, ok := etb.getObjectField(objectType, "attributes"); ok {
+		for key, field := range attributes.Fields {
+			if _, exists := flatObject.Fields[key]; !exists {
+				flatObject.Fields[key] = field
+			}
+		}
+	}
+
+	if relationships, ok := etb.getObjectField(objectType, "relationships"); ok {
+		for key, field := range relationships.Fields {
+			if _, exists := flatObject.Fields[key]; exists {
+				continue
+			}
+
+			relationshipName, ok := getNamedObjectType(etb.schema, field.Type)
+			if !ok {
+				continue
+			}
+
+			dataField, ok := etb.schema.ObjectTypes[relationshipName].Fields["data"]
+			if !ok {
+				continue
+			}
+
+			flatObject.Fields[key] = rest.ObjectField{
+				ObjectField: schema.ObjectField{
+					Description: field.Description,
+					Type:        etb.rewriteNamedType(dataField.Type, etb.flattenJSONAPIResource).Encode(),
+				},
+				HTTP: dataField.HTTP,
+			}
+		}
+	}
+
+	etb.schema.ObjectTypes[flatName] = flatObject
+
+	return flatName
+}
+
+// flattenHALType rewrites HAL object types of the type recursively.
+func (etb *envelopeTypeBuilder) flattenHALType(schemaType schema.Type) schema.TypeEncoder {
+	return etb.rewriteNamedType(schemaType, etb.flattenHALResource)
+}
+
+// flattenHALResource creates a flat object type without links, and fields of embedded resources are lifted into the object.
+func (etb *envelopeTypeBuilder) flattenHALResource(name string) string {
+	if flatName, ok := etb.flatTypes[name]; ok {
+		return flatName
+	}
+
+	objectType, ok := etb.schema.ObjectTypes[name]
+	if !ok || !isHALObjectType(objectType) {
+		return name
+	}
+
+	flatName := name + flatObjectSuffix
+	etb.flatTypes[name] = flatName
+	flatObject := rest.ObjectType{
+		Description: objectType.Description,
+		Fields:      map[string]rest.ObjectField{},
+	}
+
+	for key, field := range objectType.Fields {
+		if key != "_links" && key != "_embedded" {
+			flatObject.Fields[key] = field
+		}
+	}
+
+	if embedded, ok := etb.getObjectField(objectType, "_embedded"); ok {
+		for key, field := range embedded.Fields {
+			if _, exists := flatObject.Fields[key]; exists {
+				continue
+			}
+
+			field.Type = etb.flattenHALType(field.Type).Encode()
+			flatObject.Fields[key] = field
+		}
+	}
+
+	etb.schema.ObjectTypes[flatName] = flatObject
+
+	return flatName
+}
+
+func (etb *envelopeTypeBuilder) getObjectField(objectType rest.ObjectType, fieldName string) (rest.ObjectType, bool) {
+	field, ok := objectType.Fields[fieldName]
+	if !ok {
+		return rest.ObjectType{}, false
+	}
+
+	name, ok := getNamedObjectType(etb.schema, field.Type)
+	if !ok {
+		return rest.ObjectType{}, false
+	}
+
+	return etb.schema.ObjectTypes[name], true
+}
+
+// rewriteNamedType replaces the underlying named type of the type, nullable and array wrappers are kept.
+func (etb *envelopeTypeBuilder) rewriteNamedType(schemaType schema.Type, rewrite func(name string) string) schema.TypeEncoder {
+	switch t := schemaType.Interface().(type) {
+	case *schema.NullableType:
+		return schema.NewNullableType(etb.rewriteNamedType(t.UnderlyingType, rewrite))
+	case *schema.ArrayType:
+		return schema.NewArrayType(etb.rewriteNamedType(t.ElementType, rewrite))
+	case *schema.NamedType:
+		return schema.NewNamedType(rewrite(t.Name))
+	default:
+		return t
+	}
+}
+
+// get the name of the object type, which may be wrapped in a nullable type.
+func getNamedObjectType(httpSchema *rest.NDCHttpSchema, schemaType schema.Type) (string, bool) {
+	switch t := schemaType.Interface().(type) {
+	case *schema.NullableType:
+		return getNamedObjectType(httpSchema, t.UnderlyingType)
+	case *schema.NamedType:
+		_, ok := httpSchema.ObjectTypes[t.Name]
+
+		return t.Name, ok
+	default:
+		return "", false
+	}
+}
+
+func isHALObjectType(objectType rest.ObjectType) bool {
+	_, hasLinks := objectType.Fields["_links"]
+	_, hasEmbedded := objectType.Fields["_embedded"]
+
+	return hasLinks || hasEmbedded
+}
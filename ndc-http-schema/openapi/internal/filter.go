@@ -0,0 +1,85 @@
+package internal
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// OperationFilter represents filters of OpenAPI operations to be converted. Operations must match all filters.
+type OperationFilter struct {
+	// Allowed HTTP methods, e.g. get, post. All methods are allowed if empty
+	Methods []string `json:"methods,omitempty" yaml:"methods,omitempty"`
+	// Allowed operation IDs. All operations are allowed if empty
+	OperationIDs []string `json:"operationIds,omitempty" yaml:"operationIds,omitempty"`
+	// Convert operations which have any of these tags only
+	IncludeTags []string `json:"includeTags,omitempty" yaml:"includeTags,omitempty"`
+	// Skip operations which have any of these tags
+	ExcludeTags []string `json:"excludeTags,omitempty" yaml:"excludeTags,omitempty"`
+	// Convert operations whose paths match any of these glob patterns only, e.g. /pets/*.
+	// A pattern also matches sub paths of the matched path
+	IncludePaths []string `json:"includePaths,omitempty" yaml:"includePaths,omitempty"`
+	// Skip operations whose paths match any of these glob patterns, e.g. /admin/*.
+	// A pattern also matches sub paths of the matched path
+	ExcludePaths []string `json:"excludePaths,omitempty" yaml:"excludePaths,omitempty"`
+}
+
+// Validate checks if glob patterns of the filter are valid.
+func (of OperationFilter) Validate() error {
+	for _, pattern := range append(slices.Clone(of.IncludePaths), of.ExcludePaths...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid path pattern %s: %w", pattern, err)
+		}
+	}
+
+	return nil
+}
+
+// Match checks if the operation matches the filter. Returns true if the filter is nil.
+func (of *OperationFilter) Match(operationID string, pathKey string, method string, tags []string) bool {
+	if of == nil {
+		return true
+	}
+
+	if len(of.Methods) > 0 && !slices.ContainsFunc(of.Methods, func(m string) bool {
+		return strings.EqualFold(strings.TrimSpace(m), method)
+	}) {
+		return false
+	}
+
+	if len(of.OperationIDs) > 0 && !slices.Contains(of.OperationIDs, operationID) {
+		return false
+	}
+
+	if len(of.IncludeTags) > 0 && !slices.ContainsFunc(tags, func(tag string) bool {
+		return slices.Contains(of.IncludeTags, tag)
+	}) {
+		return false
+	}
+
+	if slices.ContainsFunc(tags, func(tag string) bool {
+		return slices.Contains(of.ExcludeTags, tag)
+	}) {
+		return false
+	}
+
+	if len(of.IncludePaths) > 0 && !matchPathPatterns(of.IncludePaths, pathKey) {
+		return false
+	}
+
+	return !matchPathPatterns(of.ExcludePaths, pathKey)
+}
+
+// check if the path or any of its parent paths matches a glob pattern.
+func matchPathPatterns(patterns []string, pathKey string) bool {
+	for _, pattern := range patterns {
+		for p := pathKey; p != "" && p != "/" && p != "."; p = path.Dir(p) {
+			if ok, _ := path.Match(pattern, p); ok {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,361 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+const (
+	graphqlDeprecatedDirective = "deprecated"
+	// the maximum depth of nested object fields in generated selection sets
+	graphqlMaxSelectionDepth = 3
+)
+
+var graphqlBuiltinScalars = map[string]rest.ScalarName{
+	"Int":     rest.ScalarInt32,
+	"Float":   rest.ScalarFloat64,
+	"String":  rest.ScalarString,
+	"Boolean": rest.ScalarBoolean,
+	"ID":      rest.ScalarString,
+}
+
+// GraphQLBuilder the NDC schema builder from GraphQL schema.
+// Query fields are converted to functions and mutation fields to procedures.
+type GraphQLBuilder struct {
+	*ConvertOptions
+
+	schema    *rest.NDCHttpSchema
+	gqlSchema *ast.Schema
+}
+
+// NewGraphQLBuilder creates a GraphQLBuilder instance
+func NewGraphQLBuilder(options ConvertOptions) *GraphQLBuilder {
+	return &GraphQLBuilder{
+		schema:         rest.NewNDCHttpSchema(),
+		ConvertOptions: applyConvertOptions(options),
+	}
+}
+
+// BuildSchema converts the GraphQL schema to NDC HTTP schema. The server URL is optional.
+func (gb *GraphQLBuilder) BuildSchema(gqlSchema *ast.Schema, serverURL string) (*rest.NDCHttpSchema, error) {
+	gb.gqlSchema = gqlSchema
+
+	envName := utils.StringSliceToConstantCase([]string{gb.EnvPrefix, "SERVER_URL"})
+	server := rest.ServerConfig{
+		URL: sdkUtils.NewEnvStringVariable(envName),
+	}
+	if serverURL != "" {
+		server.URL = sdkUtils.NewEnvString(envName, serverURL)
+	}
+	gb.schema.Settings.Servers = []rest.ServerConfig{server}
+
+	if gqlSchema.Query != nil {
+		for _, field := range gqlSchema.Query.Fields {
+			if !gb.isOperationField(field) {
+				continue
+			}
+
+			operation, err := gb.buildOperation(ast.Query, field)
+			if err != nil {
+				return nil, fmt.Errorf("query.%s: %w", field.Name, err)
+			}
+			gb.schema.Functions[field.Name] = *operation
+		}
+	}
+
+	if gqlSchema.Mutation != nil {
+		for _, field := range gqlSchema.Mutation.Fields {
+			if !gb.isOperationField(field) {
+				continue
+			}
+
+			operation, err := gb.buildOperation(ast.Mutation, field)
+			if err != nil {
+				return nil, fmt.Errorf("mutation.%s: %w", field.Name, err)
+			}
+			gb.schema.Procedures[field.Name] = *operation
+		}
+	}
+
+	if len(gb.schema.Functions) == 0 && len(gb.schema.Procedures) == 0 {
+		return nil, errNoGraphQLOperation
+	}
+
+	return NewNDCBuilder(gb.schema, *gb.ConvertOptions).Build()
+}
+
+func (gb *GraphQLBuilder) buildOperation(operationType ast.Operation, field *ast.FieldDefinition) (*rest.OperationInfo, error) {
+	resultType, err := gb.convertType(field.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	arguments := make(map[string]rest.ArgumentInfo)
+	var variables []string
+	variableDefinitions := make([]string, 0, len(field.Arguments))
+	argumentValues := make([]string, 0, len(field.Arguments))
+	for _, arg := range field.Arguments {
+		argType, err := gb.convertType(arg.Type)
+		if err != nil {
+			return nil, fmt.Errorf("arguments.%s: %w", arg.Name, err)
+		}
+
+		// arguments with default values are optional
+		if arg.DefaultValue != nil && arg.Type.NonNull {
+			argType = schema.NewNullableType(argType)
+		}
+
+		argument := rest.ArgumentInfo{
+			ArgumentInfo: schema.ArgumentInfo{
+				Type: argType.Encode(),
+			},
+		}
+		if arg.Description != "" {
+			argument.Description = &arg.Description
+		}
+
+		arguments[arg.Name] = argument
+		variables = append(variables, arg.Name)
+		variableDefinitions = append(variableDefinitions, fmt.Sprintf("$%s: %s", arg.Name, arg.Type.String()))
+		argumentValues = append(argumentValues, fmt.Sprintf("%s: $%s", arg.Name, arg.Name))
+	}
+
+	var query strings.Builder
+	query.WriteString(string(operationType) + " " + field.Name)
+	if len(variableDefinitions) > 0 {
+		query.WriteString("(" + strings.Join(variableDefinitions, ", ") + ")")
+	}
+	query.WriteString(" {\n  " + field.Name)
+	if len(argumentValues) > 0 {
+		query.WriteString("(" + strings.Join(argumentValues, ", ") + ")")
+	}
+
+	resultDef := gb.gqlSchema.Types[field.Type.Name()]
+	if resultDef != nil && !resultDef.IsLeafType() {
+		selectionSet := gb.buildSelectionSet(resultDef, 1, "  ")
+		if selectionSet == "" {
+			selectionSet = "{ __typename }"
+		}
+		query.WriteString(" " + selectionSet)
+	}
+	query.WriteString("\n}")
+
+	result := &rest.OperationInfo{
+		Request: &rest.Request{
+			URL:    "/",
+			Method: "post",
+			Response: rest.Response{
+				ContentType: rest.ContentTypeJSON,
+			},
+			GraphQL: &rest.GraphQLRequest{
+				Query:         query.String(),
+				OperationName: field.Name,
+				Variables:     variables,
+				ResultField:   field.Name,
+			},
+		},
+		Arguments:  arguments,
+		ResultType: resultType.Encode(),
+	}
+
+	if field.Description != "" {
+		result.Description = &field.Description
+	}
+
+	return result, nil
+}
+
+// build the selection set of composite types with leaf fields and nested object fields to the maximum depth.
+func (gb *GraphQLBuilder) buildSelectionSet(def *ast.Definition, depth int, parentIndent string) string {
+	indent := parentIndent + "  "
+	var lines []string
+
+	switch def.Kind {
+	case ast.Union:
+		lines = append(lines, indent+"__typename")
+		if depth >= graphqlMaxSelectionDepth {
+			break
+		}
+
+		for _, member := range def.Types {
+			memberDef := gb.gqlSchema.Types[member]
+			if memberDef == nil {
+				continue
+			}
+
+			selectionSet := gb.buildSelectionSet(memberDef, depth+1, indent)
+			if selectionSet != "" {
+				lines = append(lines, fmt.Sprintf("%s... on %s %s", indent, member, selectionSet))
+			}
+		}
+	case ast.Object, ast.Interface:
+		for _, field := range def.Fields {
+			if !gb.isSelectableField(field) {
+				continue
+			}
+
+			fieldDef := gb.gqlSchema.Types[field.Type.Name()]
+			if fieldDef == nil {
+				continue
+			}
+
+			if fieldDef.IsLeafType() {
+				lines = append(lines, indent+field.Name)
+
+				continue
+			}
+
+			if depth >= graphqlMaxSelectionDepth {
+				continue
+			}
+
+			selectionSet := gb.buildSelectionSet(fieldDef, depth+1, indent)
+			if selectionSet != "" {
+				lines = append(lines, indent+field.Name+" "+selectionSet)
+			}
+		}
+	}
+
+	if len(lines) == 0 {
+		return ""
+	}
+
+	return "{\n" + strings.Join(lines, "\n") + "\n" + parentIndent + "}"
+}
+
+func (gb *GraphQLBuilder) convertType(gqlType *ast.Type) (schema.TypeEncoder, error) {
+	if gqlType == nil {
+		return nil, errors.New("type is empty")
+	}
+
+	var result schema.TypeEncoder
+	if gqlType.Elem != nil {
+		elemType, err := gb.convertType(gqlType.Elem)
+		if err != nil {
+			return nil, err
+		}
+
+		result = schema.NewArrayType(elemType)
+	} else {
+		typeName, err := gb.convertNamedType(gqlType.NamedType)
+		if err != nil {
+			return nil, err
+		}
+
+		result = schema.NewNamedType(typeName)
+	}
+
+	if !gqlType.NonNull {
+		result = schema.NewNullableType(result)
+	}
+
+	return result, nil
+}
+
+func (gb *GraphQLBuilder) convertNamedType(name string) (string, error) {
+	if scalarName, ok := graphqlBuiltinScalars[name]; ok {
+		gb.schema.AddScalar(string(scalarName), *defaultScalarTypes[scalarName])
+
+		return string(scalarName), nil
+	}
+
+	if _, ok := gb.schema.ScalarTypes[name]; ok {
+		return name, nil
+	}
+
+	if _, ok := gb.schema.ObjectTypes[name]; ok {
+		return name, nil
+	}
+
+	def := gb.gqlSchema.Types[name]
+	if def == nil {
+		return "", fmt.Errorf("type %s does not exist", name)
+	}
+
+	switch def.Kind {
+	case ast.Scalar, ast.Union:
+		// NDC doesn't support union types, so they are represented as arbitrary JSON
+		gb.schema.AddScalar(name, *defaultScalarTypes[rest.ScalarJSON])
+	case ast.Enum:
+		enumValues := make([]string, 0, len(def.EnumValues))
+		for _, value := range def.EnumValues {
+			if gb.NoDeprecation && value.Directives.ForName(graphqlDeprecatedDirective) != nil {
+				continue
+			}
+			enumValues = append(enumValues, value.Name)
+		}
+
+		scalarType := schema.NewScalarType()
+		scalarType.Representation = schema.NewTypeRepresentationEnum(enumValues).Encode()
+		gb.schema.AddScalar(name, *scalarType)
+	default:
+		objectType := rest.ObjectType{
+			Fields: make(map[string]rest.ObjectField),
+		}
+		if def.Description != "" {
+			objectType.Description = &def.Description
+		}
+
+		// register the object type before evaluating fields to avoid infinite recursion of self-reference types
+		gb.schema.ObjectTypes[name] = objectType
+
+		for _, field := range def.Fields {
+			if def.Kind != ast.InputObject && !gb.isSelectableField(field) {
+				continue
+			}
+
+			fieldType, err := gb.convertType(field.Type)
+			if err != nil {
+				return "", fmt.Errorf("%s.%s: %w", name, field.Name, err)
+			}
+
+			// input fields with default values are optional
+			if field.DefaultValue != nil && field.Type.NonNull {
+				fieldType = schema.NewNullableType(fieldType)
+			}
+
+			objectField := rest.ObjectField{
+				ObjectField: schema.ObjectField{
+					Type: fieldType.Encode(),
+				},
+			}
+			if field.Description != "" {
+				objectField.Description = &field.Description
+			}
+
+			objectType.Fields[field.Name] = objectField
+		}
+	}
+
+	return name, nil
+}
+
+func (gb *GraphQLBuilder) isOperationField(field *ast.FieldDefinition) bool {
+	if strings.HasPrefix(field.Name, "__") {
+		return false
+	}
+
+	return !gb.NoDeprecation || field.Directives.ForName(graphqlDeprecatedDirective) == nil
+}
+
+// fields with required arguments can't be selected without argument values
+func (gb *GraphQLBuilder) isSelectableField(field *ast.FieldDefinition) bool {
+	if !gb.isOperationField(field) {
+		return false
+	}
+
+	for _, arg := range field.Arguments {
+		if arg.Type.NonNull && arg.DefaultValue == nil {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,275 @@
+package internal
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// GraphQLIntrospectionQuery is the query to introspect the schema of a GraphQL service.
+const GraphQLIntrospectionQuery = `query IntrospectionQuery {
+  __schema {
+    queryType { name }
+    mutationType { name }
+    types { ...FullType }
+  }
+}
+
+fragment FullType on __Type {
+  kind
+  name
+  description
+  fields(includeDeprecated: true) {
+    name
+    description
+    args { ...InputValue }
+    type { ...TypeRef }
+    isDeprecated
+    deprecationReason
+  }
+  inputFields { ...InputValue }
+  interfaces { ...TypeRef }
+  enumValues(includeDeprecated: true) {
+    name
+    description
+    isDeprecated
+    deprecationReason
+  }
+  possibleTypes { ...TypeRef }
+}
+
+fragment InputValue on __InputValue {
+  name
+  description
+  type { ...TypeRef }
+  defaultValue
+}
+
+fragment TypeRef on __Type {
+  kind
+  name
+  ofType {
+    kind
+    name
+    ofType {
+      kind
+      name
+      ofType {
+        kind
+        name
+        ofType {
+          kind
+          name
+          ofType {
+            kind
+            name
+            ofType {
+              kind
+              name
+              ofType {
+                kind
+                name
+              }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+type graphqlIntrospectionResponse struct {
+	Data *struct {
+		Schema *graphqlIntrospectionSchema `json:"__schema"`
+	} `json:"data"`
+	Schema *graphqlIntrospectionSchema `json:"__schema"`
+	Errors []json.RawMessage           `json:"errors"`
+}
+
+type graphqlIntrospectionSchema struct {
+	QueryType    *graphqlIntrospectionTypeRef `json:"queryType"`
+	MutationType *graphqlIntrospectionTypeRef `json:"mutationType"`
+	Types        []graphqlIntrospectionType   `json:"types"`
+}
+
+type graphqlIntrospectionType struct {
+	Kind          ast.DefinitionKind               `json:"kind"`
+	Name          string                           `json:"name"`
+	Description   string                           `json:"description"`
+	Fields        []graphqlIntrospectionField      `json:"fields"`
+	InputFields   []graphqlIntrospectionInputValue `json:"inputFields"`
+	Interfaces    []graphqlIntrospectionTypeRef    `json:"interfaces"`
+	EnumValues    []graphqlIntrospectionEnumValue  `json:"enumValues"`
+	PossibleTypes []graphqlIntrospectionTypeRef    `json:"possibleTypes"`
+}
+
+type graphqlIntrospectionField struct {
+	Name         string                           `json:"name"`
+	Description  string                           `json:"description"`
+	Args         []graphqlIntrospectionInputValue `json:"args"`
+	Type         graphqlIntrospectionTypeRef      `json:"type"`
+	IsDeprecated bool                             `json:"isDeprecated"`
+}
+
+type graphqlIntrospectionInputValue struct {
+	Name         string                      `json:"name"`
+	Description  string                      `json:"description"`
+	Type         graphqlIntrospectionTypeRef `json:"type"`
+	DefaultValue *string                     `json:"defaultValue"`
+}
+
+type graphqlIntrospectionEnumValue struct {
+	Name         string `json:"name"`
+	Description  string `json:"description"`
+	IsDeprecated bool   `json:"isDeprecated"`
+}
+
+type graphqlIntrospectionTypeRef struct {
+	Kind   string                       `json:"kind"`
+	Name   string                       `json:"name"`
+	OfType *graphqlIntrospectionTypeRef `json:"ofType"`
+}
+
+// ParseGraphQLIntrospection parses the JSON result of the introspection query to the GraphQL schema.
+// The input can be either the full response with the data field or the data object.
+func ParseGraphQLIntrospection(input []byte) (*ast.Schema, error) {
+	var resp graphqlIntrospectionResponse
+	if err := json.Unmarshal(input, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode the introspection result: %w", err)
+	}
+
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("the introspection result has errors: %s", string(resp.Errors[0]))
+	}
+
+	rawSchema := resp.Schema
+	if resp.Data != nil && resp.Data.Schema != nil {
+		rawSchema = resp.Data.Schema
+	}
+
+	if rawSchema == nil {
+		return nil, errors.New("the __schema field of the introspection result is required")
+	}
+
+	result := &ast.Schema{
+		Types:         make(map[string]*ast.Definition),
+		PossibleTypes: make(map[string][]*ast.Definition),
+		Implements:    make(map[string][]*ast.Definition),
+	}
+
+	for _, rawType := range rawSchema.Types {
+		def := &ast.Definition{
+			Kind:        rawType.Kind,
+			Name:        rawType.Name,
+			Description: rawType.Description,
+		}
+
+		for _, field := range rawType.Fields {
+			fieldDef := &ast.FieldDefinition{
+				Name:        field.Name,
+				Description: field.Description,
+				Type:        field.Type.toASTType(),
+			}
+
+			for _, arg := range field.Args {
+				fieldDef.Arguments = append(fieldDef.Arguments, arg.toArgumentDefinition())
+			}
+
+			if field.IsDeprecated {
+				fieldDef.Directives = append(fieldDef.Directives, &ast.Directive{Name: graphqlDeprecatedDirective})
+			}
+
+			def.Fields = append(def.Fields, fieldDef)
+		}
+
+		for _, field := range rawType.InputFields {
+			fieldDef := &ast.FieldDefinition{
+				Name:        field.Name,
+				Description: field.Description,
+				Type:        field.Type.toASTType(),
+			}
+			if field.DefaultValue != nil {
+				fieldDef.DefaultValue = &ast.Value{Raw: *field.DefaultValue}
+			}
+
+			def.Fields = append(def.Fields, fieldDef)
+		}
+
+		for _, iface := range rawType.Interfaces {
+			def.Interfaces = append(def.Interfaces, iface.Name)
+		}
+
+		for _, possibleType := range rawType.PossibleTypes {
+			def.Types = append(def.Types, possibleType.Name)
+		}
+
+		for _, enumValue := range rawType.EnumValues {
+			valueDef := &ast.EnumValueDefinition{
+				Name:        enumValue.Name,
+				Description: enumValue.Description,
+			}
+
+			if enumValue.IsDeprecated {
+				valueDef.Directives = append(valueDef.Directives, &ast.Directive{Name: graphqlDeprecatedDirective})
+			}
+
+			def.EnumValues = append(def.EnumValues, valueDef)
+		}
+
+		result.Types[def.Name] = def
+	}
+
+	if rawSchema.QueryType != nil {
+		result.Query = result.Types[rawSchema.QueryType.Name]
+	}
+
+	if rawSchema.MutationType != nil {
+		result.Mutation = result.Types[rawSchema.MutationType.Name]
+	}
+
+	return result, nil
+}
+
+func (tr graphqlIntrospectionTypeRef) toASTType() *ast.Type {
+	switch tr.Kind {
+	case "NON_NULL":
+		if tr.OfType == nil {
+			return nil
+		}
+
+		result := tr.OfType.toASTType()
+		if result != nil {
+			result.NonNull = true
+		}
+
+		return result
+	case "LIST":
+		if tr.OfType == nil {
+			return nil
+		}
+
+		return &ast.Type{
+			Elem: tr.OfType.toASTType(),
+		}
+	default:
+		return &ast.Type{
+			NamedType: tr.Name,
+		}
+	}
+}
+
+func (iv graphqlIntrospectionInputValue) toArgumentDefinition() *ast.ArgumentDefinition {
+	result := &ast.ArgumentDefinition{
+		Name:        iv.Name,
+		Description: iv.Description,
+		Type:        iv.Type.toASTType(),
+	}
+
+	if iv.DefaultValue != nil {
+		result.DefaultValue = &ast.Value{Raw: *iv.DefaultValue}
+	}
+
+	return result
+}
@@ -0,0 +1,405 @@
+package internal
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+)
+
+// HARBuilder the NDC schema builder from HTTP Archive (HAR) files.
+// Recorded entries are grouped by the method and path template, then converted to a Postman collection
+// so argument and result types are inferred from the recorded requests and responses.
+type HARBuilder struct {
+	*ConvertOptions
+}
+
+// NewHARBuilder creates a HARBuilder instance
+func NewHARBuilder(options ConvertOptions) *HARBuilder {
+	return &HARBuilder{
+		ConvertOptions: applyConvertOptions(options),
+	}
+}
+
+// BuildSchema converts the HAR file to NDC HTTP schema.
+func (hb *HARBuilder) BuildSchema(input []byte) (*rest.NDCHttpSchema, error) {
+	var document harDocument
+	if err := json.Unmarshal(input, &document); err != nil {
+		return nil, fmt.Errorf("failed to decode the HAR file: %w", err)
+	}
+
+	collection := hb.convertEntries(document.Log.Entries)
+	if len(collection.Item) == 0 {
+		return nil, errNoHARRequest
+	}
+
+	return NewPostmanBuilder(*hb.ConvertOptions).buildCollection(collection)
+}
+
+// group API entries by the method and path template and convert them to collection items.
+// Requests of static resources, e.g. scripts, stylesheets and images, are ignored.
+func (hb *HARBuilder) convertEntries(entries []harEntry) *postmanCollection {
+	collection := &postmanCollection{}
+	operations := []*harOperation{}
+	operationIndex := make(map[string]*harOperation)
+	originCounts := make(map[string]int)
+
+	for _, entry := range entries {
+		if !isHARAPIEntry(entry) {
+			continue
+		}
+
+		method := strings.ToLower(entry.Request.Method)
+		if !slices.Contains([]string{"get", "post", "put", "patch", "delete"}, method) {
+			continue
+		}
+
+		requestURL, err := url.Parse(entry.Request.URL)
+		if err != nil || (requestURL.Scheme != "http" && requestURL.Scheme != "https") {
+			hb.Logger.Warn(fmt.Sprintf("%s %s: invalid request URL, skipped", entry.Request.Method, entry.Request.URL))
+
+			continue
+		}
+
+		origin := requestURL.Scheme + "://" + requestURL.Host
+		segments, template, variables := templateHARPath(requestURL.Path)
+		key := method + " " + origin + template
+		originCounts[origin]++
+
+		if collection.Auth == nil {
+			collection.Auth = convertHARAuth(entry.Request.Headers)
+		}
+
+		if operation, ok := operationIndex[key]; ok {
+			operation.Entries = append(operation.Entries, entry)
+
+			continue
+		}
+
+		operation := &harOperation{
+			Method:    method,
+			Origin:    origin,
+			Path:      segments,
+			Template:  template,
+			Variables: variables,
+			Entries:   []harEntry{entry},
+		}
+		operationIndex[key] = operation
+		operations = append(operations, operation)
+	}
+
+	// the most requested origin is the server URL and other origins are converted to absolute URLs
+	slices.SortStableFunc(operations, func(a, b *harOperation) int {
+		return cmp.Compare(originCounts[b.Origin], originCounts[a.Origin])
+	})
+
+	for _, operation := range operations {
+		collection.Item = append(collection.Item, hb.convertOperation(operation))
+	}
+
+	return collection
+}
+
+// convert the group of recorded entries to a collection item. Query parameters and body fields are combined,
+// and JSON bodies are merged so optional fields of different requests are inferred.
+func (hb *HARBuilder) convertOperation(operation *harOperation) postmanItem {
+	request := &postmanRequest{
+		Method: strings.ToUpper(operation.Method),
+		URL: postmanURL{
+			Raw:      operation.Origin + operation.Template,
+			Host:     postmanPath{operation.Origin},
+			Path:     operation.Path,
+			Variable: operation.Variables,
+		},
+	}
+
+	queryIndex := make(map[string]int)
+	for _, entry := range operation.Entries {
+		for _, query := range entry.Request.QueryString {
+			if i, ok := queryIndex[query.Name]; ok {
+				if request.URL.Query[i].Value == "" {
+					request.URL.Query[i].Value = query.Value
+				}
+
+				continue
+			}
+
+			queryIndex[query.Name] = len(request.URL.Query)
+			request.URL.Query = append(request.URL.Query, postmanKeyValue{
+				Key:   query.Name,
+				Value: query.Value,
+			})
+		}
+	}
+
+	if body, contentType := hb.convertPostData(operation.Entries); body != nil {
+		request.Body = body
+		if contentType != "" {
+			request.Header = []postmanKeyValue{
+				{Key: "Content-Type", Value: contentType},
+			}
+		}
+	}
+
+	// recorded requests don't have names, so operation names are generated from the method and path
+	return postmanItem{
+		Description: postmanDescription(request.Method + " " + operation.Template),
+		Request:     request,
+		Response:    hb.convertResponses(operation.Entries),
+	}
+}
+
+// convert recorded request bodies to the example body. Returns the body and the content type.
+func (hb *HARBuilder) convertPostData(entries []harEntry) (*postmanBody, string) {
+	var mimeType, rawText string
+	var jsonValues []any
+	var params []postmanKeyValue
+	paramNames := make(map[string]bool)
+
+	for _, entry := range entries {
+		postData := entry.Request.PostData
+		if postData == nil {
+			continue
+		}
+
+		if mimeType == "" {
+			mimeType = getHARMimeType(postData.MimeType)
+		}
+
+		for _, param := range postData.Params {
+			if param.Name == "" || paramNames[param.Name] {
+				continue
+			}
+
+			paramNames[param.Name] = true
+			field := postmanKeyValue{
+				Key:   param.Name,
+				Value: param.Value,
+			}
+			if param.FileName != "" {
+				field.Type = "file"
+			}
+			params = append(params, field)
+		}
+
+		if postData.Text == "" {
+			continue
+		}
+
+		if rawText == "" {
+			rawText = postData.Text
+		}
+
+		if mimeType == "" || utils.IsContentTypeJSON(mimeType) {
+			if value, err := decodeJSONExample(postData.Text); err == nil {
+				jsonValues = append(jsonValues, value)
+			}
+		}
+	}
+
+	switch {
+	case mimeType == rest.ContentTypeFormURLEncoded:
+		if len(params) == 0 && rawText != "" {
+			values, err := url.ParseQuery(rawText)
+			if err != nil {
+				hb.Logger.Warn(fmt.Sprintf("failed to decode the form body: %s", err))
+			}
+
+			for key := range values {
+				params = append(params, postmanKeyValue{
+					Key:   key,
+					Value: values.Get(key),
+				})
+			}
+			slices.SortFunc(params, func(a, b postmanKeyValue) int {
+				return strings.Compare(a.Key, b.Key)
+			})
+		}
+
+		return &postmanBody{Mode: "urlencoded", URLEncoded: params}, mimeType
+	case utils.IsContentTypeMultipartForm(mimeType):
+		return &postmanBody{Mode: "formdata", FormData: params}, mimeType
+	case len(jsonValues) > 0:
+		rawJSON, err := json.Marshal(mergeJSONExamples(jsonValues))
+		if err != nil {
+			hb.Logger.Warn(fmt.Sprintf("failed to encode the example JSON body: %s", err))
+
+			return nil, ""
+		}
+
+		if mimeType == "" {
+			mimeType = rest.ContentTypeJSON
+		}
+
+		body := &postmanBody{Mode: "raw", Raw: string(rawJSON)}
+		body.Options.Raw.Language = "json"
+
+		return body, mimeType
+	case rawText != "":
+		return &postmanBody{Mode: "raw", Raw: rawText}, mimeType
+	default:
+		return nil, ""
+	}
+}
+
+// convert successful responses to the example response. JSON bodies of responses are merged.
+// Responses without body, e.g. 204 No Content, aren't examples.
+func (hb *HARBuilder) convertResponses(entries []harEntry) []postmanResponse {
+	var response *postmanResponse
+	var jsonValues []any
+
+	for _, entry := range entries {
+		if entry.Response.Status < 200 || entry.Response.Status >= 300 {
+			continue
+		}
+
+		content := entry.Response.Content
+		body := content.Text
+		if body == "" {
+			continue
+		}
+
+		if content.Encoding == "base64" {
+			decoded, err := base64.StdEncoding.DecodeString(body)
+			if err != nil {
+				continue
+			}
+			body = string(decoded)
+		}
+
+		mimeType := getHARMimeType(content.MimeType)
+		if response == nil {
+			response = &postmanResponse{
+				Code: entry.Response.Status,
+				Body: body,
+			}
+			if mimeType != "" {
+				response.Header = []postmanKeyValue{
+					{Key: "Content-Type", Value: mimeType},
+				}
+			}
+		}
+
+		if mimeType == "" || utils.IsContentTypeJSON(mimeType) {
+			if value, err := decodeJSONExample(body); err == nil {
+				jsonValues = append(jsonValues, value)
+			}
+		}
+	}
+
+	if response == nil {
+		return nil
+	}
+
+	if len(jsonValues) > 1 {
+		if rawJSON, err := json.Marshal(mergeJSONExamples(jsonValues)); err == nil {
+			response.Body = string(rawJSON)
+		}
+	}
+
+	return []postmanResponse{*response}
+}
+
+// check if the entry is an API request. Static resources of web pages are ignored.
+func isHARAPIEntry(entry harEntry) bool {
+	switch entry.ResourceType {
+	case "xhr", "fetch":
+		return true
+	case "", "other":
+	default:
+		return false
+	}
+
+	if postData := entry.Request.PostData; postData != nil && (postData.Text != "" || len(postData.Params) > 0) {
+		return true
+	}
+
+	mimeType := getHARMimeType(entry.Response.Content.MimeType)
+
+	return utils.IsContentTypeJSON(mimeType) || utils.IsContentTypeXML(mimeType)
+}
+
+// detect the auth type from the Authorization header of the recorded request.
+func convertHARAuth(headers []harNameValue) *postmanAuth {
+	for _, header := range headers {
+		if !strings.EqualFold(header.Name, "authorization") {
+			continue
+		}
+
+		scheme, _, _ := strings.Cut(strings.TrimSpace(header.Value), " ")
+		switch strings.ToLower(scheme) {
+		case "bearer":
+			return &postmanAuth{Type: "bearer"}
+		case "basic":
+			return &postmanAuth{Type: "basic"}
+		}
+	}
+
+	return nil
+}
+
+// replace identifier segments of the request path with path variables.
+// The variable name is derived from the previous segment, e.g. /users/1 becomes /users/{userId}.
+// Returns the segments of the collection URL, the path template and path variables.
+func templateHARPath(rawPath string) ([]string, string, []postmanVariable) {
+	var segments, templates []string
+	var variables []postmanVariable
+	var previous string
+
+	for _, segment := range strings.Split(rawPath, "/") {
+		if segment == "" {
+			continue
+		}
+
+		if !isHARIdentifierSegment(segment) {
+			segments = append(segments, segment)
+			templates = append(templates, segment)
+			previous = segment
+
+			continue
+		}
+
+		baseName := "id"
+		if previous != "" {
+			baseName = utils.ToCamelCase(singularize(previous) + "_id")
+		}
+
+		name := baseName
+		for i := 2; slices.ContainsFunc(variables, func(v postmanVariable) bool {
+			return v.Key == name
+		}); i++ {
+			name = baseName + strconv.Itoa(i)
+		}
+
+		segments = append(segments, ":"+name)
+		templates = append(templates, "{"+name+"}")
+		variables = append(variables, postmanVariable{
+			Key:   name,
+			Value: segment,
+		})
+		previous = ""
+	}
+
+	return segments, "/" + strings.Join(templates, "/"), variables
+}
+
+// get the naive singular form of the resource name, e.g. users -> user, categories -> category.
+func singularize(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return word[:len(word)-3] + "y"
+	case strings.HasSuffix(word, "sses"):
+		return word[:len(word)-2]
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss"):
+		return word[:len(word)-1]
+	default:
+		return word
+	}
+}
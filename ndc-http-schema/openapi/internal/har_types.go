@@ -0,0 +1,90 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	harNumericSegmentRegex = regexp.MustCompile(`^-?\d+$`)
+	harUUIDSegmentRegex    = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+	harHexSegmentRegex     = regexp.MustCompile(`^[0-9a-fA-F]{24,}$`)
+)
+
+// harDocument represents the root object of HTTP Archive (HAR) 1.2 files.
+type harDocument struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harEntry struct {
+	// the resource type is recorded by Chromium-based browsers, e.g. xhr, fetch, script
+	ResourceType string      `json:"_resourceType,omitempty"`
+	Request      harRequest  `json:"request"`
+	Response     harResponse `json:"response"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	Headers     []harNameValue `json:"headers,omitempty"`
+	QueryString []harNameValue `json:"queryString,omitempty"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string     `json:"mimeType"`
+	Text     string     `json:"text,omitempty"`
+	Params   []harParam `json:"params,omitempty"`
+}
+
+type harParam struct {
+	Name        string `json:"name"`
+	Value       string `json:"value,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+	ContentType string `json:"contentType,omitempty"`
+}
+
+type harResponse struct {
+	Status  int            `json:"status"`
+	Headers []harNameValue `json:"headers,omitempty"`
+	Content harContent     `json:"content"`
+}
+
+type harContent struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// harOperation groups recorded entries of the same method and path template.
+type harOperation struct {
+	Method    string
+	Origin    string
+	Path      []string
+	Template  string
+	Variables []postmanVariable
+	Entries   []harEntry
+}
+
+// check if the path segment looks like an identifier which is converted to a path argument,
+// e.g. a number, an UUID or an object ID.
+func isHARIdentifierSegment(segment string) bool {
+	return harNumericSegmentRegex.MatchString(segment) ||
+		harUUIDSegmentRegex.MatchString(segment) ||
+		harHexSegmentRegex.MatchString(segment)
+}
+
+// get the MIME type without parameters, e.g. application/json; charset=utf-8.
+func getHARMimeType(mimeType string) string {
+	return strings.ToLower(strings.TrimSpace(strings.Split(mimeType, ";")[0]))
+}
@@ -0,0 +1,278 @@
+package internal
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
+)
+
+// InsomniaBuilder the NDC schema builder from Insomnia export files v4.
+// Requests and request groups are converted to a Postman collection, and environment data to collection variables.
+type InsomniaBuilder struct {
+	*ConvertOptions
+}
+
+// NewInsomniaBuilder creates an InsomniaBuilder instance
+func NewInsomniaBuilder(options ConvertOptions) *InsomniaBuilder {
+	return &InsomniaBuilder{
+		ConvertOptions: applyConvertOptions(options),
+	}
+}
+
+// BuildSchema converts the Insomnia export file to NDC HTTP schema.
+func (ib *InsomniaBuilder) BuildSchema(input []byte) (*rest.NDCHttpSchema, error) {
+	var export insomniaExport
+	if err := json.Unmarshal(input, &export); err != nil {
+		return nil, fmt.Errorf("failed to decode the Insomnia export file: %w", err)
+	}
+
+	if export.ExportFormat != 0 && export.ExportFormat != 4 {
+		ib.Logger.Warn(fmt.Sprintf("the export format isn't v4, some fields may be ignored: %d", export.ExportFormat))
+	}
+
+	if !slices.ContainsFunc(export.Resources, func(resource insomniaResource) bool {
+		return resource.Type == insomniaRequestType
+	}) {
+		return nil, errNoInsomniaRequest
+	}
+
+	return NewPostmanBuilder(*ib.ConvertOptions).buildCollection(ib.convertResources(export.Resources))
+}
+
+// convert resources to the collection. Request groups are converted to folders.
+func (ib *InsomniaBuilder) convertResources(resources []insomniaResource) *postmanCollection {
+	collection := &postmanCollection{}
+	children := make(map[string][]insomniaResource)
+	groupIDs := make(map[string]bool)
+	workspaceIDs := make(map[string]bool)
+	var parentIDs []string
+
+	for _, resource := range resources {
+		switch resource.Type {
+		case insomniaWorkspaceType:
+			workspaceIDs[resource.ID] = true
+		case insomniaRequestGroupType:
+			groupIDs[resource.ID] = true
+		}
+	}
+
+	for _, resource := range resources {
+		if resource.Type != insomniaRequestType && resource.Type != insomniaRequestGroupType {
+			continue
+		}
+
+		if _, ok := children[resource.ParentID]; !ok {
+			parentIDs = append(parentIDs, resource.ParentID)
+		}
+		children[resource.ParentID] = append(children[resource.ParentID], resource)
+	}
+
+	for _, parentID := range parentIDs {
+		if !groupIDs[parentID] {
+			collection.Item = append(collection.Item, ib.convertChildren(parentID, children)...)
+		}
+	}
+
+	collection.Variable = convertInsomniaEnvironments(resources, workspaceIDs)
+
+	return collection
+}
+
+// convert children of the workspace or request group recursively, ordered by the sort key of the UI.
+func (ib *InsomniaBuilder) convertChildren(parentID string, children map[string][]insomniaResource) []postmanItem {
+	resources := slices.Clone(children[parentID])
+	slices.SortStableFunc(resources, func(a, b insomniaResource) int {
+		return cmp.Compare(a.MetaSortKey, b.MetaSortKey)
+	})
+
+	items := make([]postmanItem, 0, len(resources))
+	for _, resource := range resources {
+		item := postmanItem{
+			Name:        resource.Name,
+			Description: postmanDescription(resource.Description),
+			Auth:        ib.convertAuthentication(resource.Authentication),
+		}
+
+		if resource.Type == insomniaRequestGroupType {
+			item.Item = ib.convertChildren(resource.ID, children)
+		} else {
+			item.Request = ib.convertRequest(&resource)
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
+
+func (ib *InsomniaBuilder) convertRequest(resource *insomniaResource) *postmanRequest {
+	request := &postmanRequest{
+		Method: resource.Method,
+		URL:    parsePostmanRawURL(normalizeInsomniaTemplate(resource.URL)),
+	}
+
+	for _, param := range resource.Parameters {
+		request.URL.Query = append(request.URL.Query, postmanKeyValue{
+			Key:         param.Name,
+			Value:       normalizeInsomniaTemplate(param.Value),
+			Disabled:    param.Disabled,
+			Description: postmanDescription(param.Description),
+		})
+	}
+
+	for _, param := range resource.PathParameters {
+		request.URL.Variable = append(request.URL.Variable, postmanVariable{
+			Key:         param.Name,
+			Value:       normalizeInsomniaTemplate(param.Value),
+			Description: postmanDescription(param.Description),
+		})
+	}
+
+	var hasContentType bool
+	for _, header := range resource.Headers {
+		if strings.EqualFold(header.Name, "content-type") {
+			hasContentType = true
+		}
+
+		request.Header = append(request.Header, postmanKeyValue{
+			Key:         header.Name,
+			Value:       normalizeInsomniaTemplate(header.Value),
+			Disabled:    header.Disabled,
+			Description: postmanDescription(header.Description),
+		})
+	}
+
+	if resource.Body == nil {
+		return request
+	}
+
+	mimeType := getHARMimeType(resource.Body.MimeType)
+	var params []postmanKeyValue
+	for _, param := range resource.Body.Params {
+		field := postmanKeyValue{
+			Key:         param.Name,
+			Value:       normalizeInsomniaTemplate(param.Value),
+			Disabled:    param.Disabled,
+			Description: postmanDescription(param.Description),
+		}
+		if param.Type == "file" {
+			field.Type = "file"
+		}
+		params = append(params, field)
+	}
+
+	switch {
+	case mimeType == rest.ContentTypeFormURLEncoded:
+		request.Body = &postmanBody{Mode: "urlencoded", URLEncoded: params}
+	case utils.IsContentTypeMultipartForm(mimeType):
+		request.Body = &postmanBody{Mode: "formdata", FormData: params}
+	case mimeType == "application/graphql":
+		request.Body = &postmanBody{Mode: "graphql"}
+		mimeType = rest.ContentTypeJSON
+	case resource.Body.Text != "":
+		request.Body = &postmanBody{Mode: "raw", Raw: normalizeInsomniaTemplate(resource.Body.Text)}
+		if utils.IsContentTypeJSON(mimeType) {
+			request.Body.Options.Raw.Language = "json"
+		}
+	}
+
+	if mimeType != "" && !hasContentType {
+		request.Header = append(request.Header, postmanKeyValue{
+			Key:   "Content-Type",
+			Value: mimeType,
+		})
+	}
+
+	return request
+}
+
+// convert the authentication of requests and request groups. Empty and none types inherit the auth of the parent.
+func (ib *InsomniaBuilder) convertAuthentication(auth *insomniaAuthentication) *postmanAuth {
+	if auth == nil || auth.Disabled {
+		return nil
+	}
+
+	switch auth.Type {
+	case "bearer", "basic":
+		return &postmanAuth{Type: auth.Type}
+	case "apikey":
+		location := "header"
+		if auth.AddTo == "queryParams" {
+			location = "query"
+		}
+
+		return &postmanAuth{
+			Type: auth.Type,
+			APIKey: []postmanKeyValue{
+				{Key: "key", Value: auth.Key},
+				{Key: "in", Value: location},
+			},
+		}
+	case "", "none":
+		return nil
+	default:
+		ib.Logger.Warn(fmt.Sprintf("unsupported authentication type %s, skipped", auth.Type))
+
+		return nil
+	}
+}
+
+// convert data of environments to collection variables. Variables of the base environment
+// take precedence over sub environments. Nested objects are flattened, e.g. api.url.
+func convertInsomniaEnvironments(resources []insomniaResource, workspaceIDs map[string]bool) []postmanVariable {
+	environments := slices.DeleteFunc(slices.Clone(resources), func(resource insomniaResource) bool {
+		return resource.Type != insomniaEnvironmentType
+	})
+	slices.SortStableFunc(environments, func(a, b insomniaResource) int {
+		isBaseA, isBaseB := workspaceIDs[a.ParentID], workspaceIDs[b.ParentID]
+		switch {
+		case isBaseA == isBaseB:
+			return 0
+		case isBaseA:
+			return -1
+		default:
+			return 1
+		}
+	})
+
+	values := make(map[string]any)
+	var flatten func(prefix string, data map[string]any)
+	flatten = func(prefix string, data map[string]any) {
+		for key, value := range data {
+			if object, ok := value.(map[string]any); ok {
+				flatten(prefix+key+".", object)
+
+				continue
+			}
+
+			if _, ok := values[prefix+key]; !ok && value != nil {
+				values[prefix+key] = value
+			}
+		}
+	}
+
+	for _, environment := range environments {
+		flatten("", environment.Data)
+	}
+
+	variables := make([]postmanVariable, 0, len(values))
+	for _, key := range sdkUtils.GetSortedKeys(values) {
+		variables = append(variables, postmanVariable{
+			Key:   key,
+			Value: values[key],
+		})
+	}
+
+	return variables
+}
+
+// replace Insomnia environment variables with Postman variables, e.g. {{ _.baseUrl }} -> {{baseUrl}}.
+func normalizeInsomniaTemplate(input string) string {
+	return insomniaVariableRegex.ReplaceAllString(input, "{{$1}}")
+}
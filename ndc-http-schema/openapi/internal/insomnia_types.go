@@ -0,0 +1,66 @@
+package internal
+
+import (
+	"regexp"
+)
+
+// insomniaVariableRegex matches environment variables of Insomnia templates, e.g. {{ _.baseUrl }}
+var insomniaVariableRegex = regexp.MustCompile(`\{\{\s*_\.([^{}\s]+)\s*\}\}`)
+
+const (
+	insomniaRequestType      = "request"
+	insomniaRequestGroupType = "request_group"
+	insomniaEnvironmentType  = "environment"
+	insomniaWorkspaceType    = "workspace"
+)
+
+// insomniaExport represents the root object of Insomnia export files v4.
+type insomniaExport struct {
+	Type         string             `json:"_type"`
+	ExportFormat int                `json:"__export_format"`
+	Resources    []insomniaResource `json:"resources"`
+}
+
+// insomniaResource is a workspace, an environment, a request group or a request.
+type insomniaResource struct {
+	ID             string                  `json:"_id"`
+	Type           string                  `json:"_type"`
+	ParentID       string                  `json:"parentId"`
+	Name           string                  `json:"name"`
+	Description    string                  `json:"description,omitempty"`
+	MetaSortKey    float64                 `json:"metaSortKey,omitempty"`
+	URL            string                  `json:"url,omitempty"`
+	Method         string                  `json:"method,omitempty"`
+	Body           *insomniaBody           `json:"body,omitempty"`
+	Parameters     []insomniaParameter     `json:"parameters,omitempty"`
+	PathParameters []insomniaParameter     `json:"pathParameters,omitempty"`
+	Headers        []insomniaParameter     `json:"headers,omitempty"`
+	Authentication *insomniaAuthentication `json:"authentication,omitempty"`
+	Data           map[string]any          `json:"data,omitempty"`
+}
+
+type insomniaBody struct {
+	MimeType string              `json:"mimeType,omitempty"`
+	Text     string              `json:"text,omitempty"`
+	Params   []insomniaParameter `json:"params,omitempty"`
+}
+
+type insomniaParameter struct {
+	Name        string `json:"name"`
+	Value       string `json:"value"`
+	Description string `json:"description,omitempty"`
+	Type        string `json:"type,omitempty"`
+	FileName    string `json:"fileName,omitempty"`
+	Disabled    bool   `json:"disabled,omitempty"`
+}
+
+type insomniaAuthentication struct {
+	Type     string `json:"type,omitempty"`
+	Disabled bool   `json:"disabled,omitempty"`
+	Token    string `json:"token,omitempty"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Key      string `json:"key,omitempty"`
+	Value    string `json:"value,omitempty"`
+	AddTo    string `json:"addTo,omitempty"`
+}
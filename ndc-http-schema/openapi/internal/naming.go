@@ -0,0 +1,132 @@
+package internal
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/invopop/jsonschema"
+)
+
+var (
+	operationNameTemplateRegex = regexp.MustCompile(`\{(\w+)\}`)
+	repeatedUnderscoreRegex    = regexp.MustCompile(`_{2,}`)
+)
+
+// NamingCase represents the casing of generated names.
+type NamingCase string
+
+const (
+	// NamingCaseCamel converts names to camelCase, e.g. getPetById.
+	NamingCaseCamel NamingCase = "camel"
+	// NamingCaseSnake converts names to snake_case, e.g. get_pet_by_id.
+	NamingCaseSnake NamingCase = "snake"
+)
+
+var namingCase_enums = []NamingCase{NamingCaseCamel, NamingCaseSnake}
+
+// JSONSchema is used to generate a custom jsonschema.
+func (j NamingCase) JSONSchema() *jsonschema.Schema {
+	enums := make([]any, len(namingCase_enums))
+	for i, item := range namingCase_enums {
+		enums[i] = item
+	}
+
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: enums,
+	}
+}
+
+// NamingStrategy represents settings to customize names of operations and types.
+type NamingStrategy struct {
+	// The template of operation names of OpenAPI operations, e.g. {method}_{tag}_{operationId}.
+	// Available variables are {method}, {tag}, {operationId} and {path}.
+	// {operationId} falls back to the name which is generated from the method and path if the operation doesn't have the operationId
+	OperationTemplate string `json:"operationTemplate,omitempty" yaml:"operationTemplate,omitempty"`
+	// The casing of operation names. Names are kept as is if empty
+	OperationCase NamingCase `json:"operationCase,omitempty" yaml:"operationCase,omitempty"`
+	// Add a prefix to names of object and scalar types only, e.g. Stripe. Avoid collisions of type names when merging many schema files
+	TypePrefix string `json:"typePrefix,omitempty" yaml:"typePrefix,omitempty"`
+}
+
+// Validate checks if the naming strategy is valid.
+func (ns NamingStrategy) Validate() error {
+	if ns.OperationCase != "" && !slices.Contains(namingCase_enums, ns.OperationCase) {
+		return fmt.Errorf("invalid operationCase %s, expected one of %v", ns.OperationCase, namingCase_enums)
+	}
+
+	for _, match := range operationNameTemplateRegex.FindAllStringSubmatch(ns.OperationTemplate, -1) {
+		switch match[1] {
+		case "method", "tag", "operationId", "path":
+		default:
+			return fmt.Errorf("invalid variable %s of operationTemplate, expected one of {method}, {tag}, {operationId}, {path}", match[0])
+		}
+	}
+
+	return nil
+}
+
+// render the operation name from the template. Returns an empty string if the template is empty.
+func (ns *NamingStrategy) renderOperationName(operationId string, pathKey string, method string, tags []string, options *ConvertOptions) string {
+	if ns == nil || ns.OperationTemplate == "" {
+		return ""
+	}
+
+	if operationId == "" {
+		operationId = buildPathMethodName(pathKey, method, options)
+	}
+
+	if alias, ok := options.MethodAlias[method]; ok {
+		method = alias
+	}
+
+	var tag string
+	if len(tags) > 0 {
+		tag = tags[0]
+	}
+
+	name := operationNameTemplateRegex.ReplaceAllStringFunc(ns.OperationTemplate, func(variable string) string {
+		switch strings.Trim(variable, "{}") {
+		case "method":
+			return method
+		case "tag":
+			return tag
+		case "operationId":
+			return operationId
+		case "path":
+			return buildPathMethodName(pathKey, "", options)
+		default:
+			return variable
+		}
+	})
+
+	return strings.Trim(repeatedUnderscoreRegex.ReplaceAllString(formatOperationName(name), "_"), "_")
+}
+
+// convert the operation name with the casing option.
+func (ns *NamingStrategy) formatOperationCase(name string) string {
+	if ns == nil {
+		return name
+	}
+
+	switch ns.OperationCase {
+	case NamingCaseCamel:
+		return utils.ToCamelCase(name)
+	case NamingCaseSnake:
+		return utils.ToSnakeCase(name)
+	default:
+		return name
+	}
+}
+
+// get the prefix of type names. Returns an empty string if the strategy is nil.
+func (ns *NamingStrategy) getTypePrefix() string {
+	if ns == nil {
+		return ""
+	}
+
+	return ns.TypePrefix
+}
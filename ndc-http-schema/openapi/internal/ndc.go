@@ -62,6 +62,23 @@ func (nsc *NDCBuilder) validate() error {
 		nsc.newSchema.Procedures[newName] = *op
 	}
 
+	for key, webhook := range nsc.schema.Webhooks {
+		if len(webhook.PayloadType) > 0 {
+			payloadType, err := nsc.validateType(webhook.PayloadType)
+			if err != nil {
+				return fmt.Errorf("webhook %s: payloadType: %w", key, err)
+			}
+
+			webhook.PayloadType = payloadType.Encode()
+		}
+
+		if webhook.Operation != "" {
+			webhook.Operation = nsc.formatOperationName(webhook.Operation)
+		}
+
+		nsc.newSchema.Webhooks[nsc.formatOperationName(key)] = webhook
+	}
+
 	return nil
 }
 
@@ -70,15 +87,50 @@ func (nsc *NDCBuilder) validateOperation(operationName string, operation rest.Op
 	result := &rest.OperationInfo{
 		Request:     operation.Request,
 		Description: operation.Description,
+		Tags:        operation.Tags,
+		Collection:  operation.Collection,
 		Arguments:   make(map[string]rest.ArgumentInfo),
 	}
+
+	// target operations of links and generated enrichment settings are renamed with the prefix
+	if len(operation.Links) > 0 {
+		result.Links = make(map[string]rest.OperationLink)
+		for key, link := range operation.Links {
+			link.Operation = nsc.formatOperationName(link.Operation)
+			result.Links[key] = link
+		}
+	}
+
+	if result.Request != nil {
+		for i, enrich := range result.Request.Response.Enrich {
+			result.Request.Response.Enrich[i].Operation = nsc.formatOperationName(enrich.Operation)
+		}
+
+		for code, errResponse := range result.Request.Response.Errors {
+			errorType, err := nsc.validateType(errResponse.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s: response.errors.%s: %w", operationName, code, err)
+			}
+
+			errResponse.Type = errorType.Encode()
+			result.Request.Response.Errors[code] = errResponse
+		}
+	}
 	for key, field := range operation.Arguments {
 		fieldType, err := nsc.validateType(field.Type)
 		if err != nil {
 			return nil, fmt.Errorf("%s: arguments.%s: %w", operationName, key, err)
 		}
+		httpParam := field.HTTP
+		if httpParam != nil && nsc.NoDefaultValues {
+			param := *httpParam
+			param.Schema = nsc.stripDefaultValue(param.Schema)
+			httpParam = &param
+		}
+
 		result.Arguments[key] = rest.ArgumentInfo{
-			HTTP: field.HTTP,
+			HTTP:           httpParam,
+			Classification: field.Classification,
 			ArgumentInfo: schema.ArgumentInfo{
 				Description: field.ArgumentInfo.Description,
 				Type:        fieldType.Encode(),
@@ -147,9 +199,10 @@ func (nsc *NDCBuilder) validateType(schemaType schema.Type) (schema.TypeEncoder,
 		nsc.usedTypes[t.Name] = newName
 
 		newObjectType := rest.ObjectType{
-			Description: objectType.Description,
-			XML:         objectType.XML,
-			Fields:      make(map[string]rest.ObjectField),
+			Description:   objectType.Description,
+			XML:           objectType.XML,
+			Discriminator: objectType.Discriminator,
+			Fields:        make(map[string]rest.ObjectField),
 		}
 
 		for key, field := range objectType.Fields {
@@ -163,7 +216,8 @@ func (nsc *NDCBuilder) validateType(schemaType schema.Type) (schema.TypeEncoder,
 					Description: field.Description,
 					Arguments:   field.Arguments,
 				},
-				HTTP: field.HTTP,
+				HTTP:           nsc.stripDefaultValue(field.HTTP),
+				Classification: field.Classification,
 			}
 		}
 		nsc.newSchema.ObjectTypes[newName] = newObjectType
@@ -174,18 +228,34 @@ func (nsc *NDCBuilder) validateType(schemaType schema.Type) (schema.TypeEncoder,
 	}
 }
 
+// remove default values of the type schema if the NoDefaultValues option is enabled.
+// The type schema is copied because it may be shared with other fields.
+func (nsc *NDCBuilder) stripDefaultValue(typeSchema *rest.TypeSchema) *rest.TypeSchema {
+	if typeSchema == nil || !nsc.NoDefaultValues {
+		return typeSchema
+	}
+
+	result := *typeSchema
+	result.Default = nil
+	result.Items = nsc.stripDefaultValue(typeSchema.Items)
+	result.AdditionalProperties = nsc.stripDefaultValue(typeSchema.AdditionalProperties)
+
+	return &result
+}
+
 func (nsc *NDCBuilder) formatTypeName(name string) string {
-	if nsc.Prefix == "" {
+	typePrefix := nsc.Naming.getTypePrefix()
+	if nsc.Prefix == "" && typePrefix == "" {
 		return name
 	}
 
-	return utils.StringSliceToPascalCase([]string{nsc.Prefix, name})
+	return utils.StringSliceToPascalCase([]string{nsc.Prefix, typePrefix, name})
 }
 
 func (nsc *NDCBuilder) formatOperationName(name string) string {
-	if nsc.Prefix == "" {
-		return name
+	if nsc.Prefix != "" {
+		name = utils.StringSliceToCamelCase([]string{nsc.Prefix, name})
 	}
 
-	return utils.StringSliceToCamelCase([]string{nsc.Prefix, name})
+	return nsc.Naming.formatOperationCase(name)
 }
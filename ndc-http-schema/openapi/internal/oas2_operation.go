@@ -32,11 +32,11 @@ func newOAS2OperationBuilder(builder *OAS2Builder, pathKey string, method string
 
 // BuildFunction build a HTTP NDC function information from OpenAPI v2 operation
 func (oc *oas2OperationBuilder) BuildFunction(operation *v2.Operation, commonParams []*v2.Parameter) (*rest.OperationInfo, string, error) {
-	if operation == nil {
+	if operation == nil || !oc.builder.Filter.Match(operation.OperationId, oc.pathKey, oc.method, operation.Tags) {
 		return nil, "", nil
 	}
 
-	funcName := buildUniqueOperationName(oc.builder.schema, operation.OperationId, oc.pathKey, oc.method, oc.builder.ConvertOptions)
+	funcName := buildUniqueOperationName(oc.builder.schema, operation.OperationId, oc.pathKey, oc.method, operation.Tags, oc.builder.ConvertOptions)
 	oc.builder.Logger.Info("function",
 		slog.String("name", funcName),
 		slog.String("path", oc.pathKey),
@@ -49,6 +49,11 @@ func (oc *oas2OperationBuilder) BuildFunction(operation *v2.Operation, commonPar
 	if resultType == nil {
 		return nil, "", nil
 	}
+
+	response.Errors, err = oc.convertErrorResponses(operation, funcName)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", oc.pathKey, err)
+	}
 	reqBody, err := oc.convertParameters(operation, commonParams, []string{funcName})
 	if err != nil {
 		return nil, "", fmt.Errorf("%s: %w", funcName, err)
@@ -68,20 +73,25 @@ func (oc *oas2OperationBuilder) BuildFunction(operation *v2.Operation, commonPar
 			Security:    convertSecurities(operation.Security),
 		},
 		Description: &description,
+		Tags:        operation.Tags,
 		Arguments:   arguments,
 		ResultType:  resultType.Encode(),
 	}
 
+	if err := applyCollectionExtension(oc.builder.schema, &function, funcName, operation.Extensions, oc.builder.ConvertOptions); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", funcName, err)
+	}
+
 	return &function, funcName, nil
 }
 
 // BuildProcedure build a HTTP NDC function information from OpenAPI v2 operation
 func (oc *oas2OperationBuilder) BuildProcedure(operation *v2.Operation, commonParams []*v2.Parameter) (*rest.OperationInfo, string, error) {
-	if operation == nil {
+	if operation == nil || !oc.builder.Filter.Match(operation.OperationId, oc.pathKey, oc.method, operation.Tags) {
 		return nil, "", nil
 	}
 
-	procName := buildUniqueOperationName(oc.builder.schema, operation.OperationId, oc.pathKey, oc.method, oc.builder.ConvertOptions)
+	procName := buildUniqueOperationName(oc.builder.schema, operation.OperationId, oc.pathKey, oc.method, operation.Tags, oc.builder.ConvertOptions)
 
 	oc.builder.Logger.Info("procedure",
 		slog.String("name", procName),
@@ -98,6 +108,11 @@ func (oc *oas2OperationBuilder) BuildProcedure(operation *v2.Operation, commonPa
 		return nil, "", nil
 	}
 
+	response.Errors, err = oc.convertErrorResponses(operation, procName)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", oc.pathKey, err)
+	}
+
 	reqBody, err := oc.convertParameters(operation, commonParams, []string{procName})
 	if err != nil {
 		return nil, "", fmt.Errorf("%s: %w", oc.pathKey, err)
@@ -117,6 +132,7 @@ func (oc *oas2OperationBuilder) BuildProcedure(operation *v2.Operation, commonPa
 			Response:    *response,
 		},
 		Description: &description,
+		Tags:        operation.Tags,
 		Arguments:   arguments,
 		ResultType:  resultType.Encode(),
 	}
@@ -168,6 +184,7 @@ func (oc *oas2OperationBuilder) convertParameters(operation *v2.Operation, commo
 			typeSchema = &rest.TypeSchema{
 				Type:    evaluateOpenAPITypes([]string{param.Type}),
 				Pattern: param.Pattern,
+				Default: decodeDefaultValue(param.Default),
 			}
 			if param.Maximum != nil {
 				maximum := float64(*param.Maximum)
@@ -208,6 +225,10 @@ func (oc *oas2OperationBuilder) convertParameters(operation *v2.Operation, commo
 			ArgumentInfo: schema.ArgumentInfo{
 				Type: schemaType,
 			},
+			Classification: getDataClassificationFromExtensions(param.Extensions),
+		}
+		if argument.Classification == "" && typeSchema != nil {
+			argument.Classification = typeSchema.Classification
 		}
 		if param.Description != "" {
 			description := utils.StripHTMLTags(param.Description)
@@ -232,7 +253,8 @@ func (oc *oas2OperationBuilder) convertParameters(operation *v2.Operation, commo
 					ObjectField: schema.ObjectField{
 						Type: argument.Type,
 					},
-					HTTP: typeSchema,
+					HTTP:           typeSchema,
+					Classification: argument.Classification,
 				}
 
 				if argument.Description != nil {
@@ -341,6 +363,10 @@ func (oc *oas2OperationBuilder) convertResponse(operation *v2.Operation, fieldPa
 		}
 	}
 
+	if oc.builder.ResponseExamples {
+		response.Example = oc.getResponseExample(resp, contentType)
+	}
+
 	if resp.Schema == nil {
 		return getResultTypeFromContentType(oc.builder.schema, contentType), response, nil
 	}
@@ -351,9 +377,89 @@ func (oc *oas2OperationBuilder) convertResponse(operation *v2.Operation, fieldPa
 		return nil, nil, err
 	}
 
+	// vendored media types of standard envelopes are unwrapped into flat objects at runtime
+	if envelope := getResponseEnvelope(contentType); envelope != "" {
+		if unwrappedType, ok := unwrapEnvelopeResultType(oc.builder.schema, envelope, schemaType); ok {
+			response.Envelope = envelope
+			schemaType = unwrappedType
+		}
+	}
+
 	return schemaType, response, nil
 }
 
+// convert schemas of 4xx and 5xx responses, as well as the default response, so error bodies are decoded into typed details.
+// Error types are named after the operation and the status code, e.g. GetPet404Error, unless the schema is a reference.
+func (oc *oas2OperationBuilder) convertErrorResponses(operation *v2.Operation, operationName string) (map[string]rest.ErrorResponse, error) {
+	if operation.Responses == nil {
+		return nil, nil
+	}
+
+	contentType := oc.getContentTypeV2(operation.Produces)
+	if contentType == "" {
+		contentType = rest.ContentTypeJSON
+	}
+
+	results := make(map[string]rest.ErrorResponse)
+	convert := func(code string, resp *v2.Response) error {
+		if resp == nil || resp.Schema == nil {
+			return nil
+		}
+
+		errorType, _, err := newOAS2SchemaBuilder(oc.builder, oc.pathKey, rest.InBody).
+			getSchemaTypeFromProxy(resp.Schema, false, []string{operationName, code, "Error"})
+		if err != nil {
+			return fmt.Errorf("responses.%s: %w", code, err)
+		}
+
+		results[code] = rest.ErrorResponse{
+			ContentType: contentType,
+			Type:        errorType.Encode(),
+		}
+
+		return nil
+	}
+
+	var hasSuccess bool
+	if operation.Responses.Codes != nil {
+		for r := operation.Responses.Codes.First(); r != nil; r = r.Next() {
+			if isErrorResponseCode(r.Key()) {
+				if err := convert(strings.ToUpper(r.Key()), r.Value()); err != nil {
+					return nil, err
+				}
+			} else if strings.HasPrefix(r.Key(), "2") {
+				hasSuccess = true
+			}
+		}
+	}
+
+	// the default response is the success response if the operation doesn't have any 2xx response
+	if hasSuccess {
+		if err := convert("default", operation.Responses.Default); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	return results, nil
+}
+
+// get the example of the response body with the content type, the first example or examples of the schema.
+func (oc *oas2OperationBuilder) getResponseExample(resp *v2.Response, contentType string) any {
+	if resp.Examples != nil && resp.Examples.Values != nil && resp.Examples.Values.Len() > 0 {
+		if node := resp.Examples.Values.GetOrZero(contentType); node != nil {
+			return decodeExampleNode(node)
+		}
+
+		return decodeExampleNode(resp.Examples.Values.First().Value())
+	}
+
+	return buildSchemaExample(resp.Schema, 0)
+}
+
 func (oc *oas2OperationBuilder) getContentTypeV2(contentTypes []string) string {
 	for _, contentType := range preferredContentTypes {
 		if len(contentTypes) == 0 || slices.Contains(contentTypes, contentType) {
@@ -163,6 +163,10 @@ func (oc *oas2SchemaBuilder) evalObjectType(baseSchema *base.Schema, forceProper
 	refName := utils.StringSliceToPascalCase(fieldPaths)
 
 	if baseSchema.Properties == nil || baseSchema.Properties.IsZero() {
+		if result, typeResult := buildTypedMapType(oc.builder.schema, baseSchema); result != nil {
+			return result, typeResult, nil
+		}
+
 		// treat no-property objects as a JSON scalar
 		var scalarType schema.TypeEncoder = oc.builder.buildScalarJSON()
 		if baseSchema.Nullable != nil && *baseSchema.Nullable {
@@ -221,6 +225,7 @@ func (oc *oas2SchemaBuilder) evalObjectType(baseSchema *base.Schema, forceProper
 		if propApiSchema.Description != "" {
 			objField.Description = &propApiSchema.Description
 		}
+		objField.Classification = propApiSchema.Classification
 
 		object.Fields[propName] = objField
 	}
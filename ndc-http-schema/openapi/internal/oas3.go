@@ -25,6 +25,12 @@ type OAS3Builder struct {
 	// or self-reference types that haven't added into the object_types map yet.
 	// This cache temporarily stores them to avoid infinite recursive reference.
 	schemaCache map[string]SchemaInfoCache
+	// operation names by operation IDs and local operation references to resolve links.
+	operationNames map[string]string
+	// links of operations which are resolved after all operations are converted.
+	operationLinks map[string]*orderedmap.Map[string, *v3.Link]
+	// callbacks of operations which are converted to webhooks after all operations are converted.
+	operationCallbacks map[string]*orderedmap.Map[string, *v3.Callback]
 }
 
 // SchemaInfoCache stores prebuilt information of component schema types.
@@ -39,7 +45,11 @@ func NewOAS3Builder(options ConvertOptions) *OAS3Builder {
 	builder := &OAS3Builder{
 		schema:         rest.NewNDCHttpSchema(),
 		schemaCache:    make(map[string]SchemaInfoCache),
+		operationNames: make(map[string]string),
+		operationLinks: make(map[string]*orderedmap.Map[string, *v3.Link]),
 		ConvertOptions: applyConvertOptions(options),
+
+		operationCallbacks: make(map[string]*orderedmap.Map[string, *v3.Callback]),
 	}
 
 	return builder
@@ -59,13 +69,25 @@ func (oc *OAS3Builder) BuildDocumentModel(docModel *libopenapi.DocumentModel[v3.
 			}
 		}
 	}
-	for iterPath := docModel.Model.Paths.PathItems.First(); iterPath != nil; iterPath = iterPath.Next() {
-		if err := oc.pathToNDCOperations(iterPath); err != nil {
-			return nil, err
+	if docModel.Model.Paths != nil && docModel.Model.Paths.PathItems != nil {
+		for iterPath := docModel.Model.Paths.PathItems.First(); iterPath != nil; iterPath = iterPath.Next() {
+			if err := oc.pathToNDCOperations(iterPath); err != nil {
+				return nil, err
+			}
 		}
 	}
 
-	if docModel.Model.Components.SecuritySchemes != nil {
+	oc.buildOperationLinks()
+
+	if err := oc.buildOperationCallbacks(); err != nil {
+		return nil, err
+	}
+
+	if err := oc.buildWebhooks(docModel.Model.Webhooks); err != nil {
+		return nil, err
+	}
+
+	if docModel.Model.Components != nil && docModel.Model.Components.SecuritySchemes != nil && docModel.Model.Components.SecuritySchemes.Len() > 0 {
 		oc.schema.Settings.SecuritySchemes = make(map[string]rest.SecurityScheme)
 		for scheme := docModel.Model.Components.SecuritySchemes.First(); scheme != nil; scheme = scheme.Next() {
 			err := oc.convertSecuritySchemes(scheme)
@@ -153,6 +175,10 @@ func (oc *OAS3Builder) convertSecuritySchemes(scheme orderedmap.Pair[string, *v3
 			user := sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase([]string{oc.EnvPrefix, key, "USERNAME"}))
 			password := sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase([]string{oc.EnvPrefix, key, "PASSWORD"}))
 			result.SecuritySchemer = rest.NewBasicAuthConfig(user, password)
+		case string(rest.DigestAuthScheme):
+			user := sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase([]string{oc.EnvPrefix, key, "USERNAME"}))
+			password := sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase([]string{oc.EnvPrefix, key, "PASSWORD"}))
+			result.SecuritySchemer = rest.NewDigestAuthConfig(user, password)
 		default:
 			valueEnv := sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase([]string{oc.EnvPrefix, key, "TOKEN"}))
 			result.SecuritySchemer = rest.NewHTTPAuthConfig(security.Scheme, rest.AuthorizationHeader, valueEnv)
@@ -167,20 +193,23 @@ func (oc *OAS3Builder) convertSecuritySchemes(scheme orderedmap.Pair[string, *v3
 			flows[rest.ImplicitFlow] = oc.convertV3OAuthFLow(key, security.Flows.Implicit)
 		}
 		if security.Flows.AuthorizationCode != nil {
-			flows[rest.AuthorizationCodeFlow] = oc.convertV3OAuthFLow(key, security.Flows.AuthorizationCode)
+			flow := oc.convertV3OAuthFLow(key, security.Flows.AuthorizationCode)
+			setOAuthFlowCredentialEnvs(&flow, rest.AuthorizationCodeFlow, []string{oc.EnvPrefix, key})
+
+			flows[rest.AuthorizationCodeFlow] = flow
 		}
 		if security.Flows.ClientCredentials != nil {
-			clientID := sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase([]string{oc.EnvPrefix, key, "CLIENT_ID"}))
-			clientSecret := sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase([]string{oc.EnvPrefix, key, "CLIENT_SECRET"}))
 			flow := oc.convertV3OAuthFLow(key, security.Flows.ClientCredentials)
-			flow.ClientID = &clientID
-			flow.ClientSecret = &clientSecret
+			setOAuthFlowCredentialEnvs(&flow, rest.ClientCredentialsFlow, []string{oc.EnvPrefix, key})
 
 			flows[rest.ClientCredentialsFlow] = flow
 		}
 
 		if security.Flows.Password != nil {
-			flows[rest.PasswordFlow] = oc.convertV3OAuthFLow(key, security.Flows.Password)
+			flow := oc.convertV3OAuthFLow(key, security.Flows.Password)
+			setOAuthFlowCredentialEnvs(&flow, rest.PasswordFlow, []string{oc.EnvPrefix, key})
+
+			flows[rest.PasswordFlow] = flow
 		}
 
 		result.SecuritySchemer = rest.NewOAuth2Config(flows)
@@ -211,6 +240,7 @@ func (oc *OAS3Builder) pathToNDCOperations(pathItem orderedmap.Pair[string, *v3.
 		}
 		if funcGet != nil {
 			oc.schema.Functions[funcName] = *funcGet
+			oc.registerOperation(funcName, pathKey, "get", pathValue.Get)
 		}
 	}
 
@@ -220,6 +250,7 @@ func (oc *OAS3Builder) pathToNDCOperations(pathItem orderedmap.Pair[string, *v3.
 	}
 	if procPost != nil {
 		oc.schema.Procedures[procPostName] = *procPost
+		oc.registerOperation(procPostName, pathKey, "post", pathValue.Post)
 	}
 
 	procPut, procPutName, err := newOAS3OperationBuilder(oc, pathKey, "put", pathValue.Parameters).BuildProcedure(pathValue.Put)
@@ -228,6 +259,7 @@ func (oc *OAS3Builder) pathToNDCOperations(pathItem orderedmap.Pair[string, *v3.
 	}
 	if procPut != nil {
 		oc.schema.Procedures[procPutName] = *procPut
+		oc.registerOperation(procPutName, pathKey, "put", pathValue.Put)
 	}
 
 	procPatch, procPutName, err := newOAS3OperationBuilder(oc, pathKey, "patch", pathValue.Parameters).BuildProcedure(pathValue.Patch)
@@ -236,6 +268,7 @@ func (oc *OAS3Builder) pathToNDCOperations(pathItem orderedmap.Pair[string, *v3.
 	}
 	if procPatch != nil {
 		oc.schema.Procedures[procPutName] = *procPatch
+		oc.registerOperation(procPutName, pathKey, "patch", pathValue.Patch)
 	}
 
 	procDelete, procDeleteName, err := newOAS3OperationBuilder(oc, pathKey, "delete", pathValue.Parameters).BuildProcedure(pathValue.Delete)
@@ -244,6 +277,7 @@ func (oc *OAS3Builder) pathToNDCOperations(pathItem orderedmap.Pair[string, *v3.
 	}
 	if procDelete != nil {
 		oc.schema.Procedures[procDeleteName] = *procDelete
+		oc.registerOperation(procDeleteName, pathKey, "delete", pathValue.Delete)
 	}
 
 	return nil
@@ -389,9 +423,10 @@ func (oc *OAS3Builder) populateWriteSchemaType(schemaType schema.Type) (schema.T
 			return schemaType, ty.Name, false
 		}
 		writeObject := rest.ObjectType{
-			Description: objectType.Description,
-			XML:         objectType.XML,
-			Fields:      make(map[string]rest.ObjectField),
+			Description:   objectType.Description,
+			XML:           objectType.XML,
+			Discriminator: objectType.Discriminator,
+			Fields:        make(map[string]rest.ObjectField),
 		}
 		var hasWriteField bool
 		for key, field := range objectType.Fields {
@@ -0,0 +1,234 @@
+package internal
+
+import (
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+const linkResponseBodyExpressionPrefix = "$response.body#/"
+
+// registerOperation stores the operation name to be resolved by links, links of the success response and callbacks.
+func (oc *OAS3Builder) registerOperation(name string, pathKey string, method string, operation *v3.Operation) {
+	oc.operationNames[buildOperationRef(pathKey, method)] = name
+	if operation.OperationId != "" {
+		oc.operationNames[operation.OperationId] = name
+	}
+
+	if operation.Callbacks != nil && operation.Callbacks.Len() > 0 {
+		oc.operationCallbacks[name] = operation.Callbacks
+	}
+
+	if links := getSuccessResponseLinks(operation.Responses); links != nil && links.Len() > 0 {
+		oc.operationLinks[name] = links
+	}
+}
+
+// buildOperationLinks resolves target operations of links.
+// If the enrichLinks option is enabled, links to functions with a single key of the response body are converted to response enrichment settings.
+func (oc *OAS3Builder) buildOperationLinks() {
+	for _, name := range sdkUtils.GetSortedKeys(oc.operationLinks) {
+		operation, isFunction := oc.schema.Functions[name]
+		if !isFunction {
+			operation = oc.schema.Procedures[name]
+		}
+
+		for iter := oc.operationLinks[name].First(); iter != nil; iter = iter.Next() {
+			linkName := iter.Key()
+			link, ok := oc.convertLink(name, linkName, iter.Value())
+			if !ok {
+				continue
+			}
+
+			if operation.Links == nil {
+				operation.Links = make(map[string]rest.OperationLink)
+			}
+			operation.Links[linkName] = *link
+
+			if oc.EnrichLinks {
+				if enrich := oc.buildLinkEnrichSettings(operation, linkName, link); enrich != nil {
+					operation.Request.Response.Enrich = append(operation.Request.Response.Enrich, *enrich)
+				}
+			}
+		}
+
+		if isFunction {
+			oc.schema.Functions[name] = operation
+		} else {
+			oc.schema.Procedures[name] = operation
+		}
+	}
+}
+
+func (oc *OAS3Builder) convertLink(operationName string, linkName string, link *v3.Link) (*rest.OperationLink, bool) {
+	if link == nil {
+		return nil, false
+	}
+
+	targetName, ok := oc.resolveLinkTarget(link)
+	if !ok {
+		oc.Logger.Warn("the target operation of the link does not exist",
+			slog.String("operation", operationName),
+			slog.String("link", linkName),
+			slog.String("operation_id", link.OperationId),
+			slog.String("operation_ref", link.OperationRef),
+		)
+
+		return nil, false
+	}
+
+	target, ok := oc.schema.Functions[targetName]
+	if !ok {
+		target = oc.schema.Procedures[targetName]
+	}
+
+	result := &rest.OperationLink{
+		Operation:   targetName,
+		RequestBody: link.RequestBody,
+		Description: utils.StripHTMLTags(link.Description),
+	}
+
+	if link.Parameters == nil {
+		return result, true
+	}
+
+	for param := link.Parameters.First(); param != nil; param = param.Next() {
+		argumentName, ok := findLinkArgumentName(target.Arguments, param.Key())
+		if !ok {
+			oc.Logger.Warn("the parameter of the link does not exist in the target operation",
+				slog.String("operation", operationName),
+				slog.String("link", linkName),
+				slog.String("parameter", param.Key()),
+			)
+
+			continue
+		}
+
+		if result.Arguments == nil {
+			result.Arguments = make(map[string]string)
+		}
+		result.Arguments[argumentName] = param.Value()
+	}
+
+	return result, true
+}
+
+func (oc *OAS3Builder) resolveLinkTarget(link *v3.Link) (string, bool) {
+	if link.OperationId != "" {
+		name, ok := oc.operationNames[link.OperationId]
+
+		return name, ok
+	}
+
+	// only local references are supported, e.g. #/paths/~1users~1{id}/get
+	ref, err := url.PathUnescape(link.OperationRef)
+	if err != nil || !strings.HasPrefix(ref, "#/paths/") {
+		return "", false
+	}
+
+	name, ok := oc.operationNames[ref]
+
+	return name, ok
+}
+
+// buildLinkEnrichSettings creates the response enrichment setting if the link targets a function with a single key from the response body.
+func (oc *OAS3Builder) buildLinkEnrichSettings(operation rest.OperationInfo, linkName string, link *rest.OperationLink) *rest.ResponseEnrichSettings {
+	if _, ok := oc.schema.Functions[link.Operation]; !ok || len(link.Arguments) != 1 || link.RequestBody != "" {
+		return nil
+	}
+
+	var argumentName, keyField string
+	for key, expression := range link.Arguments {
+		argumentName = key
+		keyField = strings.TrimPrefix(expression, linkResponseBodyExpressionPrefix)
+		if keyField == expression || keyField == "" || strings.Contains(keyField, "/") {
+			return nil
+		}
+	}
+
+	itemType := schema.GetUnderlyingNamedType(operation.ResultType)
+	if itemType == nil {
+		return nil
+	}
+
+	objectType, ok := oc.schema.ObjectTypes[itemType.Name]
+	if !ok {
+		return nil
+	}
+
+	field := utils.ToCamelCase(linkName)
+	if _, ok := objectType.Fields[keyField]; !ok {
+		return nil
+	}
+
+	if _, ok := objectType.Fields[field]; ok {
+		return nil
+	}
+
+	return &rest.ResponseEnrichSettings{
+		Field:     field,
+		Operation: link.Operation,
+		KeyField:  keyField,
+		Argument:  argumentName,
+	}
+}
+
+// findLinkArgumentName finds the argument name of the link parameter.
+// The parameter name can be qualified with the location, e.g. path.id.
+func findLinkArgumentName(arguments map[string]rest.ArgumentInfo, paramName string) (string, bool) {
+	var location rest.ParameterLocation
+	if in, name, ok := strings.Cut(paramName, "."); ok {
+		if paramLocation, err := rest.ParseParameterLocation(in); err == nil {
+			location = paramLocation
+			paramName = name
+		}
+	}
+
+	for _, key := range sdkUtils.GetSortedKeys(arguments) {
+		argument := arguments[key]
+		if argument.HTTP != nil && argument.HTTP.Name == paramName && (location == "" || argument.HTTP.In == location) {
+			return key, true
+		}
+	}
+
+	if _, ok := arguments[paramName]; ok {
+		return paramName, true
+	}
+
+	return "", false
+}
+
+// getSuccessResponseLinks gets links of the first success response which is used to build the result type.
+func getSuccessResponseLinks(responses *v3.Responses) *orderedmap.Map[string, *v3.Link] {
+	if responses == nil || responses.Codes == nil {
+		return nil
+	}
+
+	for r := responses.Codes.First(); r != nil; r = r.Next() {
+		code, err := strconv.ParseInt(r.Key(), 10, 32)
+		if err != nil || code < 200 || code >= 300 {
+			continue
+		}
+
+		if r.Value() == nil {
+			return nil
+		}
+
+		return r.Value().Links
+	}
+
+	return nil
+}
+
+// buildOperationRef builds the local JSON reference of the operation, e.g. #/paths/~1users~1{id}/get.
+func buildOperationRef(pathKey string, method string) string {
+	return "#/paths/" + strings.NewReplacer("~", "~0", "/", "~1").Replace(pathKey) + "/" + method
+}
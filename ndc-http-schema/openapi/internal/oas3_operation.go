@@ -37,12 +37,13 @@ func newOAS3OperationBuilder(builder *OAS3Builder, pathKey string, method string
 
 // BuildFunction build a HTTP NDC function information from OpenAPI v3 operation
 func (oc *oas3OperationBuilder) BuildFunction(itemGet *v3.Operation) (*rest.OperationInfo, string, error) {
-	if oc.builder.ConvertOptions.NoDeprecation && itemGet.Deprecated != nil && *itemGet.Deprecated {
+	if (oc.builder.ConvertOptions.NoDeprecation && itemGet.Deprecated != nil && *itemGet.Deprecated) ||
+		!oc.builder.Filter.Match(itemGet.OperationId, oc.pathKey, oc.method, itemGet.Tags) {
 		return nil, "", nil
 	}
 
 	start := time.Now()
-	funcName := buildUniqueOperationName(oc.builder.schema, itemGet.OperationId, oc.pathKey, oc.method, oc.builder.ConvertOptions)
+	funcName := buildUniqueOperationName(oc.builder.schema, itemGet.OperationId, oc.pathKey, oc.method, itemGet.Tags, oc.builder.ConvertOptions)
 
 	defer func() {
 		oc.builder.Logger.Info("function",
@@ -62,6 +63,11 @@ func (oc *oas3OperationBuilder) BuildFunction(itemGet *v3.Operation) (*rest.Oper
 		return nil, "", nil
 	}
 
+	schemaResponse.Errors, err = oc.convertErrorResponses(itemGet.Responses, oc.pathKey, funcName)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", oc.pathKey, err)
+	}
+
 	err = oc.convertParameters(itemGet.Parameters, oc.pathKey, []string{funcName})
 	if err != nil {
 		return nil, "", fmt.Errorf("%s: %w", funcName, err)
@@ -82,20 +88,26 @@ func (oc *oas3OperationBuilder) BuildFunction(itemGet *v3.Operation) (*rest.Oper
 			Response: *schemaResponse,
 		},
 		Description: &description,
+		Tags:        itemGet.Tags,
 		Arguments:   arguments,
 		ResultType:  resultType.Encode(),
 	}
 
+	if err := applyCollectionExtension(oc.builder.schema, &function, funcName, itemGet.Extensions, oc.builder.ConvertOptions); err != nil {
+		return nil, "", fmt.Errorf("%s: %w", funcName, err)
+	}
+
 	return &function, funcName, nil
 }
 
 func (oc *oas3OperationBuilder) BuildProcedure(operation *v3.Operation) (*rest.OperationInfo, string, error) {
-	if operation == nil || (oc.builder.ConvertOptions.NoDeprecation && operation.Deprecated != nil && *operation.Deprecated) {
+	if operation == nil || (oc.builder.ConvertOptions.NoDeprecation && operation.Deprecated != nil && *operation.Deprecated) ||
+		!oc.builder.Filter.Match(operation.OperationId, oc.pathKey, oc.method, operation.Tags) {
 		return nil, "", nil
 	}
 
 	start := time.Now()
-	procName := buildUniqueOperationName(oc.builder.schema, operation.OperationId, oc.pathKey, oc.method, oc.builder.ConvertOptions)
+	procName := buildUniqueOperationName(oc.builder.schema, operation.OperationId, oc.pathKey, oc.method, operation.Tags, oc.builder.ConvertOptions)
 
 	defer func() {
 		oc.builder.Logger.Info("procedure",
@@ -115,6 +127,11 @@ func (oc *oas3OperationBuilder) BuildProcedure(operation *v3.Operation) (*rest.O
 		return nil, "", nil
 	}
 
+	schemaResponse.Errors, err = oc.convertErrorResponses(operation.Responses, oc.pathKey, procName)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: %w", oc.pathKey, err)
+	}
+
 	err = oc.convertParameters(operation.Parameters, oc.pathKey, []string{procName})
 	if err != nil {
 		return nil, "", fmt.Errorf("%s: %w", oc.pathKey, err)
@@ -158,6 +175,7 @@ func (oc *oas3OperationBuilder) BuildProcedure(operation *v3.Operation) (*rest.O
 			Response:    *schemaResponse,
 		},
 		Description: &description,
+		Tags:        operation.Tags,
 		Arguments:   arguments,
 		ResultType:  resultType.Encode(),
 	}
@@ -221,6 +239,11 @@ func (oc *oas3OperationBuilder) convertParameters(params []*v3.Parameter, apiPat
 			argument.Description = &paramDescription
 		}
 
+		argument.Classification = getDataClassificationFromExtensions(param.Extensions)
+		if argument.Classification == "" && apiSchema != nil {
+			argument.Classification = apiSchema.Classification
+		}
+
 		oc.Arguments[paramName] = argument
 	}
 
@@ -424,6 +447,10 @@ func (oc *oas3OperationBuilder) convertResponse(responses *v3.Responses, apiPath
 	schemaResponse := &rest.Response{
 		ContentType: contentType,
 	}
+	if oc.builder.ResponseExamples {
+		schemaResponse.Example = oc.getResponseExample(bodyContent)
+	}
+
 	if bodyContent.Schema == nil {
 		return getResultTypeFromContentType(oc.builder.schema, contentType), schemaResponse, nil
 	}
@@ -434,14 +461,97 @@ func (oc *oas3OperationBuilder) convertResponse(responses *v3.Responses, apiPath
 		return nil, nil, err
 	}
 
-	switch contentType {
-	case rest.ContentTypeNdJSON:
-		// Newline Delimited JSON (ndjson) format represents a stream of structured objects
-		// so the response would be wrapped with an array
+	// vendored media types of standard envelopes are unwrapped into flat objects at runtime
+	if envelope := getResponseEnvelope(contentType); envelope != "" {
+		if unwrappedType, ok := unwrapEnvelopeResultType(oc.builder.schema, envelope, schemaType); ok {
+			schemaResponse.Envelope = envelope
+			schemaType = unwrappedType
+		}
+	}
+
+	// Newline Delimited JSON (ndjson) format represents a stream of structured objects
+	// so the response would be wrapped with an array
+	if utils.IsContentTypeNdJSON(contentType) {
 		return schema.NewArrayType(schemaType), schemaResponse, nil
-	default:
-		return schemaType, schemaResponse, nil
 	}
+
+	return schemaType, schemaResponse, nil
+}
+
+// convert schemas of 4xx and 5xx responses, as well as the default response, so error bodies are decoded into typed details.
+// Error types are named after the operation and the status code, e.g. GetPet404Error, unless the schema is a reference.
+func (oc *oas3OperationBuilder) convertErrorResponses(responses *v3.Responses, apiPath string, operationName string) (map[string]rest.ErrorResponse, error) {
+	if responses == nil {
+		return nil, nil
+	}
+
+	results := make(map[string]rest.ErrorResponse)
+	convert := func(code string, resp *v3.Response) error {
+		if resp == nil || resp.Content == nil {
+			return nil
+		}
+
+		contentType, bodyContent := oc.getContentType(resp.Content)
+		if bodyContent == nil || bodyContent.Schema == nil {
+			return nil
+		}
+
+		errorType, _, err := newOAS3SchemaBuilder(oc.builder, apiPath, rest.InBody, false).
+			getSchemaTypeFromProxy(bodyContent.Schema, false, []string{operationName, code, "Error"})
+		if err != nil {
+			return fmt.Errorf("responses.%s: %w", code, err)
+		}
+
+		results[code] = rest.ErrorResponse{
+			ContentType: contentType,
+			Type:        errorType.Encode(),
+		}
+
+		return nil
+	}
+
+	var hasSuccess bool
+	if responses.Codes != nil {
+		for r := responses.Codes.First(); r != nil; r = r.Next() {
+			if isErrorResponseCode(r.Key()) {
+				if err := convert(strings.ToUpper(r.Key()), r.Value()); err != nil {
+					return nil, err
+				}
+			} else if strings.HasPrefix(r.Key(), "2") {
+				hasSuccess = true
+			}
+		}
+	}
+
+	// the default response is the success response if the operation doesn't have any 2xx response
+	if hasSuccess {
+		if err := convert("default", responses.Default); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, nil
+	}
+
+	return results, nil
+}
+
+// get the example of the response body from the media type, the first named example or examples of the schema.
+func (oc *oas3OperationBuilder) getResponseExample(bodyContent *v3.MediaType) any {
+	if bodyContent.Example != nil {
+		return decodeExampleNode(bodyContent.Example)
+	}
+
+	if bodyContent.Examples != nil {
+		for item := bodyContent.Examples.First(); item != nil; item = item.Next() {
+			if example := item.Value(); example != nil && example.Value != nil {
+				return decodeExampleNode(example.Value)
+			}
+		}
+	}
+
+	return buildSchemaExample(bodyContent.Schema, 0)
 }
 
 func (oc *oas3OperationBuilder) getOperationDescription(operation *v3.Operation) string {
@@ -10,6 +10,7 @@ import (
 	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
 	"github.com/hasura/ndc-http/ndc-http-schema/utils"
 	"github.com/hasura/ndc-sdk-go/schema"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
 )
 
@@ -118,6 +119,10 @@ func (oc *oas3SchemaBuilder) getSchemaType(typeSchema *base.Schema, fieldPaths [
 	}
 
 	if typeSchema.AdditionalProperties != nil && (typeSchema.AdditionalProperties.B || typeSchema.AdditionalProperties.A != nil) {
+		if result, typeResult := buildTypedMapType(oc.builder.schema, typeSchema); result != nil {
+			return result, typeResult, nil
+		}
+
 		return oc.builder.buildScalarJSON(), createSchemaFromOpenAPISchema(typeSchema), nil
 	}
 
@@ -267,6 +272,7 @@ func (oc *oas3SchemaBuilder) evalObjectType(baseSchema *base.Schema, forceProper
 		if propApiSchema.Description != "" {
 			objField.Description = &propApiSchema.Description
 		}
+		objField.Classification = propApiSchema.Classification
 
 		switch {
 		case !propApiSchema.ReadOnly && !propApiSchema.WriteOnly:
@@ -361,6 +367,13 @@ func (oc *oas3SchemaBuilder) buildUnionSchemaType(baseSchema *base.Schema, schem
 		return typeEncoder, typeSchema, nil
 	}
 
+	if unionType != oasAllOf && baseSchema.Discriminator != nil && baseSchema.Discriminator.PropertyName != "" {
+		typeEncoder, typeSchema, err := oc.buildDiscriminatedUnionType(baseSchema, proxies, nullable, fieldPaths)
+		if err != nil || typeEncoder != nil {
+			return typeEncoder, typeSchema, err
+		}
+	}
+
 	typeSchema := &rest.TypeSchema{
 		Type: []string{"object"},
 	}
@@ -443,6 +456,145 @@ func (oc *oas3SchemaBuilder) buildUnionSchemaType(baseSchema *base.Schema, schem
 	return schema.NewNamedType(refName), typeSchema, nil
 }
 
+// Convert oneOf or anyOf schemas with the discriminator to a tagged union object.
+// The object has an enum field of the discriminator property and a nullable field of each variant object.
+// Returns nil if any variant isn't a reference to an object schema, so the union is merged instead.
+func (oc *oas3SchemaBuilder) buildDiscriminatedUnionType(baseSchema *base.Schema, proxies []*base.SchemaProxy, nullable bool, fieldPaths []string) (schema.TypeEncoder, *rest.TypeSchema, error) {
+	tagName := baseSchema.Discriminator.PropertyName
+	discriminator := &rest.UnionDiscriminator{
+		PropertyName: tagName,
+		Mapping:      map[string]string{},
+	}
+	readObject := rest.ObjectType{
+		Fields:        map[string]rest.ObjectField{},
+		Discriminator: discriminator,
+	}
+	writeObject := rest.ObjectType{
+		Fields:        map[string]rest.ObjectField{},
+		Discriminator: discriminator,
+	}
+
+	if baseSchema.Description != "" {
+		description := utils.StripHTMLTags(baseSchema.Description)
+		readObject.Description = &description
+		writeObject.Description = &description
+	}
+
+	var tags []string
+	var hasWriteVariant bool
+	for i, proxy := range proxies {
+		ref := proxy.GetReference()
+		if ref == "" {
+			return nil, nil, nil
+		}
+
+		enc, ty, err := newOAS3SchemaBuilder(oc.builder, oc.apiPath, oc.location, false).
+			getSchemaTypeFromProxy(proxy, false, append(fieldPaths, strconv.Itoa(i)))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if enc == nil {
+			return nil, nil, nil
+		}
+
+		name := getNamedType(enc, false, "")
+		variant, ok := oc.builder.schema.ObjectTypes[name]
+		if !ok {
+			return nil, nil, nil
+		}
+
+		fieldName := utils.ToCamelCase(name)
+		if _, exists := readObject.Fields[fieldName]; exists || fieldName == tagName {
+			return nil, nil, nil
+		}
+
+		for _, tag := range getDiscriminatorTags(baseSchema.Discriminator, ref) {
+			if _, exists := discriminator.Mapping[tag]; !exists {
+				discriminator.Mapping[tag] = fieldName
+				tags = append(tags, tag)
+			}
+		}
+
+		field := rest.ObjectField{
+			ObjectField: schema.ObjectField{
+				Description: variant.Description,
+				Type:        schema.NewNullableNamedType(name).Encode(),
+			},
+			HTTP: ty,
+		}
+		readObject.Fields[fieldName] = field
+
+		writeName := formatWriteObjectName(name)
+		if _, ok := oc.builder.schema.ObjectTypes[writeName]; ok {
+			hasWriteVariant = true
+			field.Type = schema.NewNullableNamedType(writeName).Encode()
+		}
+		writeObject.Fields[fieldName] = field
+	}
+
+	enumName := utils.StringSliceToPascalCase(append(fieldPaths, tagName, "Enum"))
+	enumScalar := schema.NewScalarType()
+	enumScalar.Representation = schema.NewTypeRepresentationEnum(tags).Encode()
+	oc.builder.schema.ScalarTypes[enumName] = *enumScalar
+
+	tagField := rest.ObjectField{
+		ObjectField: schema.ObjectField{
+			Description: sdkUtils.ToPtr("The discriminator of the variant"),
+			Type:        schema.NewNamedType(enumName).Encode(),
+		},
+		HTTP: &rest.TypeSchema{
+			Type: []string{"string"},
+		},
+	}
+	readObject.Fields[tagName] = tagField
+	writeObject.Fields[tagName] = tagField
+
+	refName := utils.ToPascalCase(strings.Join(fieldPaths, " "))
+	oc.builder.schema.ObjectTypes[refName] = readObject
+	if hasWriteVariant {
+		writeRefName := formatWriteObjectName(refName)
+		oc.builder.schema.ObjectTypes[writeRefName] = writeObject
+		if oc.writeMode {
+			refName = writeRefName
+		}
+	}
+
+	typeSchema := &rest.TypeSchema{
+		Type: []string{"object"},
+	}
+	if readObject.Description != nil {
+		typeSchema.Description = *readObject.Description
+	}
+
+	var result schema.TypeEncoder = schema.NewNamedType(refName)
+	if nullable {
+		result = schema.NewNullableType(result)
+	}
+
+	return result, typeSchema, nil
+}
+
+// get tag values of the variant reference from the discriminator mapping.
+// Mapping values can be references or schema names. The schema name is the tag if the variant isn't mapped.
+func getDiscriminatorTags(discriminator *base.Discriminator, ref string) []string {
+	schemaName := getSchemaRefTypeNameV3(ref)
+	var tags []string
+	if discriminator.Mapping != nil {
+		for item := discriminator.Mapping.First(); item != nil; item = item.Next() {
+			if item.Value() == ref || item.Value() == schemaName {
+				tags = append(tags, item.Key())
+			}
+		}
+	}
+
+	if len(tags) == 0 && schemaName != "" {
+		tags = append(tags, schemaName)
+	}
+
+	return tags
+}
+
 type unionSiblingField struct {
 	Type        schema.TypeEncoder
 	EnumOneOf   []string
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
+	v3 "github.com/pb33f/libopenapi/datamodel/high/v3"
+	"github.com/pb33f/libopenapi/orderedmap"
+)
+
+// buildOperationCallbacks converts callbacks of operations to webhooks which are registered by the operation.
+// The callback name is appended to the operation name, e.g. createSubscriptionOnEvent.
+func (oc *OAS3Builder) buildOperationCallbacks() error {
+	for _, name := range sdkUtils.GetSortedKeys(oc.operationCallbacks) {
+		for iter := oc.operationCallbacks[name].First(); iter != nil; iter = iter.Next() {
+			callback := iter.Value()
+			if callback == nil || callback.Expression == nil {
+				continue
+			}
+
+			for expr := callback.Expression.First(); expr != nil; expr = expr.Next() {
+				webhookName := name + utils.ToPascalCase(iter.Key())
+				if err := oc.convertWebhook(webhookName, name, expr.Key(), expr.Value()); err != nil {
+					return fmt.Errorf("%s: callbacks.%s: %w", name, iter.Key(), err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// buildWebhooks converts webhooks of OpenAPI 3.1 documents, which are registered out of band.
+func (oc *OAS3Builder) buildWebhooks(webhooks *orderedmap.Map[string, *v3.PathItem]) error {
+	if webhooks == nil {
+		return nil
+	}
+
+	for iter := webhooks.First(); iter != nil; iter = iter.Next() {
+		webhookName := utils.ToCamelCase(iter.Key())
+		if err := oc.convertWebhook(webhookName, "", "", iter.Value()); err != nil {
+			return fmt.Errorf("webhooks.%s: %w", iter.Key(), err)
+		}
+	}
+
+	return nil
+}
+
+// convertWebhook converts requests of the path item which the upstream service sends to subscribers.
+// Payload types are built from request bodies. The method is appended to the name if the path item has many operations.
+func (oc *OAS3Builder) convertWebhook(name string, operationName string, url string, pathItem *v3.PathItem) error {
+	if pathItem == nil {
+		return nil
+	}
+
+	operations := pathItem.GetOperations()
+	for iter := operations.First(); iter != nil; iter = iter.Next() {
+		method := strings.ToLower(iter.Key())
+		operation := iter.Value()
+		if operation == nil || (oc.NoDeprecation && operation.Deprecated != nil && *operation.Deprecated) {
+			continue
+		}
+
+		webhookName := name
+		switch {
+		case operationName == "" && operation.OperationId != "":
+			webhookName = formatOperationName(operation.OperationId)
+		case operations.Len() > 1:
+			webhookName += utils.ToPascalCase(method)
+		}
+
+		webhook := rest.WebhookInfo{
+			Operation: operationName,
+			URL:       url,
+			Method:    method,
+		}
+
+		description := operation.Summary
+		if description == "" {
+			description = operation.Description
+		}
+
+		if description != "" {
+			description = utils.StripHTMLTags(description)
+			webhook.Description = &description
+		}
+
+		if operation.RequestBody != nil && operation.RequestBody.Content != nil {
+			contentType, content := newOAS3OperationBuilder(oc, url, method, nil).getContentType(operation.RequestBody.Content)
+			if content != nil && content.Schema != nil {
+				payloadType, _, err := newOAS3SchemaBuilder(oc, url, rest.InBody, false).
+					getSchemaTypeFromProxy(content.Schema, false, []string{webhookName, "Payload"})
+				if err != nil {
+					return fmt.Errorf("%s: %w", webhookName, err)
+				}
+
+				webhook.ContentType = contentType
+				if payloadType != nil {
+					webhook.PayloadType = payloadType.Encode()
+				}
+			}
+		}
+
+		oc.Logger.Info("webhook",
+			slog.String("name", webhookName),
+			slog.String("operation", operationName),
+			slog.String("method", method),
+		)
+		oc.schema.Webhooks[webhookName] = webhook
+	}
+
+	return nil
+}
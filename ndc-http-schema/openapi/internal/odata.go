@@ -0,0 +1,605 @@
+package internal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
+)
+
+const (
+	// the field of the entity array in responses of entity sets
+	odataValueField = "value"
+	// the max depth of nested complex properties which are filterable and sortable
+	odataMaxPropertyDepth = 3
+)
+
+var odataPrimitiveScalars = map[string]rest.ScalarName{
+	"Edm.String":         rest.ScalarString,
+	"Edm.Boolean":        rest.ScalarBoolean,
+	"Edm.Byte":           rest.ScalarInt32,
+	"Edm.SByte":          rest.ScalarInt32,
+	"Edm.Int16":          rest.ScalarInt32,
+	"Edm.Int32":          rest.ScalarInt32,
+	"Edm.Int64":          rest.ScalarInt64,
+	"Edm.Single":         rest.ScalarFloat32,
+	"Edm.Double":         rest.ScalarFloat64,
+	"Edm.Decimal":        rest.ScalarBigDecimal,
+	"Edm.Guid":           rest.ScalarUUID,
+	"Edm.Date":           rest.ScalarDate,
+	"Edm.DateTimeOffset": rest.ScalarTimestampTZ,
+	"Edm.TimeOfDay":      rest.ScalarString,
+	"Edm.Duration":       rest.ScalarString,
+	"Edm.Binary":         rest.ScalarBytes,
+	"Edm.Stream":         rest.ScalarBytes,
+	"Edm.Untyped":        rest.ScalarJSON,
+}
+
+// literals of these primitive types aren't quoted in URLs and $filter expressions
+var odataUnquotedTypes = []string{"Edm.Int64", "Edm.Decimal", "Edm.Guid", "Edm.Date", "Edm.DateTimeOffset", "Edm.TimeOfDay"}
+
+// ODataBuilder the NDC schema builder from OData v4 $metadata documents.
+// Entity sets are converted to functions which push down field selections, predicates,
+// sorting and pagination into the $select, $filter, $orderby, $top and $skip query options.
+type ODataBuilder struct {
+	*ConvertOptions
+
+	schema          *rest.NDCHttpSchema
+	entityTypes     map[string]odataStructuredType
+	complexTypes    map[string]odataStructuredType
+	enumTypes       map[string]odataEnumType
+	typeDefinitions map[string]odataTypeDefinition
+	// aliases of schema namespaces
+	aliases map[string]string
+}
+
+// NewODataBuilder creates an ODataBuilder instance
+func NewODataBuilder(options ConvertOptions) *ODataBuilder {
+	return &ODataBuilder{
+		schema:          rest.NewNDCHttpSchema(),
+		ConvertOptions:  applyConvertOptions(options),
+		entityTypes:     make(map[string]odataStructuredType),
+		complexTypes:    make(map[string]odataStructuredType),
+		enumTypes:       make(map[string]odataEnumType),
+		typeDefinitions: make(map[string]odataTypeDefinition),
+		aliases:         make(map[string]string),
+	}
+}
+
+// BuildSchema converts the OData $metadata document to NDC HTTP schema.
+func (ob *ODataBuilder) BuildSchema(input []byte) (*rest.NDCHttpSchema, error) {
+	var edmx odataEdmx
+	if err := xml.Unmarshal(input, &edmx); err != nil {
+		return nil, fmt.Errorf("failed to decode the OData metadata document: %w", err)
+	}
+
+	if edmx.Version != "" && !strings.HasPrefix(edmx.Version, "4.") {
+		ob.Logger.Warn("the metadata document isn't OData v4, some elements may be ignored: " + edmx.Version)
+	}
+
+	var containers []odataEntityContainer
+	for _, odataSchema := range edmx.Schemas {
+		if odataSchema.Alias != "" {
+			ob.aliases[odataSchema.Alias] = odataSchema.Namespace
+		}
+
+		for _, entityType := range odataSchema.EntityTypes {
+			ob.entityTypes[odataSchema.Namespace+"."+entityType.Name] = entityType
+		}
+
+		for _, complexType := range odataSchema.ComplexTypes {
+			ob.complexTypes[odataSchema.Namespace+"."+complexType.Name] = complexType
+		}
+
+		for _, enumType := range odataSchema.EnumTypes {
+			ob.enumTypes[odataSchema.Namespace+"."+enumType.Name] = enumType
+		}
+
+		for _, typeDefinition := range odataSchema.TypeDefinitions {
+			ob.typeDefinitions[odataSchema.Namespace+"."+typeDefinition.Name] = typeDefinition
+		}
+
+		containers = append(containers, odataSchema.EntityContainers...)
+	}
+
+	if len(containers) == 0 {
+		return nil, errNoODataEntityContainer
+	}
+
+	envName := utils.StringSliceToConstantCase([]string{ob.EnvPrefix, "SERVER_URL"})
+	ob.schema.Settings.Servers = []rest.ServerConfig{
+		{
+			URL: sdkUtils.NewEnvStringVariable(envName),
+		},
+	}
+
+	for _, container := range containers {
+		for _, entitySet := range container.EntitySets {
+			if err := ob.buildEntitySet(entitySet); err != nil {
+				return nil, fmt.Errorf("%s: %w", entitySet.Name, err)
+			}
+		}
+
+		for _, singleton := range container.Singletons {
+			if err := ob.buildSingleton(singleton); err != nil {
+				return nil, fmt.Errorf("%s: %w", singleton.Name, err)
+			}
+		}
+	}
+
+	if len(ob.schema.Functions) == 0 {
+		return nil, errNoODataEntitySet
+	}
+
+	return NewNDCBuilder(ob.schema, *ob.ConvertOptions).Build()
+}
+
+// build the function which lists entities of the entity set, and the function which gets an entity by key.
+func (ob *ODataBuilder) buildEntitySet(entitySet odataEntitySet) error {
+	qualifiedName := ob.resolveAlias(entitySet.EntityType)
+	entityType, ok := ob.entityTypes[qualifiedName]
+	if !ok {
+		return fmt.Errorf("entity type %s not found", entitySet.EntityType)
+	}
+
+	typeName, err := ob.convertStructuredType(qualifiedName, entityType)
+	if err != nil {
+		return err
+	}
+
+	collectionTypeName := utils.ToPascalCase(entitySet.Name) + "Collection"
+	collectionDescription := "A page of entities of the " + entitySet.Name + " entity set"
+	ob.schema.ObjectTypes[collectionTypeName] = rest.ObjectType{
+		Description: &collectionDescription,
+		Fields: map[string]rest.ObjectField{
+			odataValueField: {
+				ObjectField: schema.ObjectField{
+					Type: schema.NewArrayType(schema.NewNamedType(typeName)).Encode(),
+				},
+				HTTP: &rest.TypeSchema{
+					Type: []string{"array"},
+					Items: &rest.TypeSchema{
+						Type: []string{"object"},
+					},
+				},
+			},
+		},
+	}
+
+	properties := ob.collectPrimitiveProperties(entityType, "", 0)
+	filterFields := make(map[string]rest.FilterFieldSettings)
+	sortFields := make([]string, 0, len(properties))
+	for _, property := range properties {
+		sortFields = append(sortFields, property.path)
+		if property.filterOperators == nil {
+			continue
+		}
+
+		filterFields[odataValueField+"."+property.path] = rest.FilterFieldSettings{
+			Operators: property.filterOperators,
+			Unquoted:  property.unquoted,
+		}
+	}
+
+	request := &rest.Request{
+		URL:    "/" + entitySet.Name,
+		Method: "get",
+		Response: rest.Response{
+			ContentType: rest.ContentTypeJSON,
+		},
+		FieldSelection: &rest.FieldSelectionSettings{
+			Parameter: "$select",
+		},
+		Pagination: &rest.PaginationSettings{
+			Type:            rest.PaginationOffset,
+			LimitParameter:  "$top",
+			OffsetParameter: "$skip",
+			ItemsField:      odataValueField,
+		},
+	}
+
+	if len(filterFields) > 0 {
+		request.Filter = &rest.FilterSettings{
+			Style:  rest.FilterOData,
+			Fields: filterFields,
+		}
+	}
+
+	if len(sortFields) > 0 {
+		slices.Sort(sortFields)
+		request.OrderBy = &rest.OrderBySettings{
+			Parameter: "$orderby",
+			Fields:    sortFields,
+		}
+	}
+
+	description := getODataDescription(entitySet.Annotations)
+	if description == "" {
+		description = fmt.Sprintf("Query entities of the %s entity set", entitySet.Name)
+	}
+
+	function := rest.OperationInfo{
+		Request:     request,
+		Description: &description,
+		Arguments:   map[string]rest.ArgumentInfo{},
+		ResultType:  schema.NewNamedType(collectionTypeName).Encode(),
+		Collection:  ob.EmitCollections,
+	}
+	ob.schema.Functions[utils.ToCamelCase(entitySet.Name)] = function
+
+	return ob.buildEntityByKey(entitySet, qualifiedName, entityType, typeName)
+}
+
+// build the function which gets an entity by key, e.g. /People('russellwhyte') or /OrderDetails(OrderID=1,ProductID=2).
+func (ob *ODataBuilder) buildEntityByKey(entitySet odataEntitySet, qualifiedName string, entityType odataStructuredType, typeName string) error {
+	keys := ob.getEntityKeys(qualifiedName, entityType)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	properties := ob.getStructuredProperties(qualifiedName, entityType)
+	arguments := make(map[string]rest.ArgumentInfo)
+	keySegments := make([]string, len(keys))
+	for i, key := range keys {
+		index := slices.IndexFunc(properties, func(property odataProperty) bool {
+			return property.Name == key
+		})
+		if index < 0 {
+			return fmt.Errorf("key property %s not found", key)
+		}
+
+		property := properties[index]
+		scalarName, ok := ob.resolvePrimitiveType(property.Type)
+		if !ok {
+			return fmt.Errorf("key property %s must be a primitive type, got %s", key, property.Type)
+		}
+
+		ob.schema.AddScalar(string(scalarName), *defaultScalarTypes[scalarName])
+		segment := "{" + key + "}"
+		if scalarName == rest.ScalarString {
+			segment = "'" + segment + "'"
+		}
+		if len(keys) > 1 {
+			segment = key + "=" + segment
+		}
+		keySegments[i] = segment
+
+		argDescription := "The " + key + " key of the entity"
+		arguments[key] = rest.ArgumentInfo{
+			ArgumentInfo: schema.ArgumentInfo{
+				Description: &argDescription,
+				Type:        schema.NewNamedType(string(scalarName)).Encode(),
+			},
+			HTTP: &rest.RequestParameter{
+				Name: key,
+				In:   rest.InPath,
+				Schema: &rest.TypeSchema{
+					Type: []string{getScalarJSONType(scalarName)},
+				},
+			},
+		}
+	}
+
+	description := fmt.Sprintf("Get an entity of the %s entity set by key", entitySet.Name)
+	ob.schema.Functions[utils.ToCamelCase(entitySet.Name)+"ByKey"] = rest.OperationInfo{
+		Request: &rest.Request{
+			URL:    "/" + entitySet.Name + "(" + strings.Join(keySegments, ",") + ")",
+			Method: "get",
+			Response: rest.Response{
+				ContentType: rest.ContentTypeJSON,
+			},
+			FieldSelection: &rest.FieldSelectionSettings{
+				Parameter: "$select",
+			},
+		},
+		Description: &description,
+		Arguments:   arguments,
+		ResultType:  schema.NewNullableType(schema.NewNamedType(typeName)).Encode(),
+	}
+
+	return nil
+}
+
+// build the function which gets the entity of the singleton, e.g. /Me.
+func (ob *ODataBuilder) buildSingleton(singleton odataEntitySet) error {
+	qualifiedName := ob.resolveAlias(singleton.Type)
+	entityType, ok := ob.entityTypes[qualifiedName]
+	if !ok {
+		return fmt.Errorf("entity type %s not found", singleton.Type)
+	}
+
+	typeName, err := ob.convertStructuredType(qualifiedName, entityType)
+	if err != nil {
+		return err
+	}
+
+	description := getODataDescription(singleton.Annotations)
+	if description == "" {
+		description = fmt.Sprintf("Get the %s singleton", singleton.Name)
+	}
+
+	ob.schema.Functions[utils.ToCamelCase(singleton.Name)] = rest.OperationInfo{
+		Request: &rest.Request{
+			URL:    "/" + singleton.Name,
+			Method: "get",
+			Response: rest.Response{
+				ContentType: rest.ContentTypeJSON,
+			},
+			FieldSelection: &rest.FieldSelectionSettings{
+				Parameter: "$select",
+			},
+		},
+		Description: &description,
+		Arguments:   map[string]rest.ArgumentInfo{},
+		ResultType:  schema.NewNullableType(schema.NewNamedType(typeName)).Encode(),
+	}
+
+	return nil
+}
+
+// convert the entity or complex type to an object type. Properties of base types are inherited.
+// Navigation properties aren't converted because they require the $expand query option.
+func (ob *ODataBuilder) convertStructuredType(qualifiedName string, structuredType odataStructuredType) (string, error) {
+	typeName := utils.ToPascalCase(structuredType.Name)
+	if _, ok := ob.schema.ObjectTypes[typeName]; ok {
+		return typeName, nil
+	}
+
+	objectType := rest.ObjectType{
+		Fields: make(map[string]rest.ObjectField),
+	}
+	if description := getODataDescription(structuredType.Annotations); description != "" {
+		objectType.Description = &description
+	}
+
+	// register the object type before evaluating fields to avoid infinite recursion of self-reference types
+	ob.schema.ObjectTypes[typeName] = objectType
+
+	for _, property := range ob.getStructuredProperties(qualifiedName, structuredType) {
+		field, err := ob.convertProperty(property)
+		if err != nil {
+			return "", fmt.Errorf("%s.%s: %w", structuredType.Name, property.Name, err)
+		}
+
+		objectType.Fields[property.Name] = field
+	}
+
+	return typeName, nil
+}
+
+func (ob *ODataBuilder) convertProperty(property odataProperty) (rest.ObjectField, error) {
+	elementType, isArray := unwrapODataCollectionType(property.Type)
+	namedType, err := ob.convertTypeName(elementType)
+	if err != nil {
+		return rest.ObjectField{}, err
+	}
+
+	var fieldType schema.TypeEncoder = schema.NewNamedType(namedType)
+	typeSchema := &rest.TypeSchema{
+		Type: []string{ob.getJSONType(namedType)},
+	}
+	if isArray {
+		fieldType = schema.NewArrayType(fieldType)
+		typeSchema = &rest.TypeSchema{
+			Type:  []string{"array"},
+			Items: typeSchema,
+		}
+	}
+
+	if property.IsNullable() {
+		fieldType = schema.NewNullableType(fieldType)
+	}
+
+	field := rest.ObjectField{
+		ObjectField: schema.ObjectField{
+			Type: fieldType.Encode(),
+		},
+		HTTP: typeSchema,
+	}
+	if description := getODataDescription(property.Annotations); description != "" {
+		field.Description = &description
+	}
+
+	return field, nil
+}
+
+// convert the qualified type name to the name of a scalar or object type.
+func (ob *ODataBuilder) convertTypeName(typeName string) (string, error) {
+	qualifiedName := ob.resolveAlias(typeName)
+	if scalarName, ok := ob.resolvePrimitiveType(qualifiedName); ok {
+		ob.schema.AddScalar(string(scalarName), *defaultScalarTypes[scalarName])
+
+		return string(scalarName), nil
+	}
+
+	if complexType, ok := ob.complexTypes[qualifiedName]; ok {
+		return ob.convertStructuredType(qualifiedName, complexType)
+	}
+
+	if entityType, ok := ob.entityTypes[qualifiedName]; ok {
+		return ob.convertStructuredType(qualifiedName, entityType)
+	}
+
+	if enumType, ok := ob.enumTypes[qualifiedName]; ok {
+		scalarName := utils.ToPascalCase(enumType.Name)
+		if _, ok := ob.schema.ScalarTypes[scalarName]; ok {
+			return scalarName, nil
+		}
+
+		enumValues := make([]string, len(enumType.Members))
+		for i, member := range enumType.Members {
+			enumValues[i] = member.Name
+		}
+
+		scalarType := schema.NewScalarType()
+		scalarType.Representation = schema.NewTypeRepresentationEnum(enumValues).Encode()
+		ob.schema.AddScalar(scalarName, *scalarType)
+
+		return scalarName, nil
+	}
+
+	if strings.HasPrefix(qualifiedName, "Edm.") {
+		ob.Logger.Warn(fmt.Sprintf("unsupported type %s, fallback to JSON", typeName))
+		ob.schema.AddScalar(string(rest.ScalarJSON), *defaultScalarTypes[rest.ScalarJSON])
+
+		return string(rest.ScalarJSON), nil
+	}
+
+	return "", fmt.Errorf("type %s not found", typeName)
+}
+
+// resolve the scalar of primitive types and type definitions of primitive types.
+func (ob *ODataBuilder) resolvePrimitiveType(typeName string) (rest.ScalarName, bool) {
+	qualifiedName := ob.resolveAlias(typeName)
+	if typeDefinition, ok := ob.typeDefinitions[qualifiedName]; ok {
+		qualifiedName = typeDefinition.UnderlyingType
+	}
+
+	scalarName, ok := odataPrimitiveScalars[qualifiedName]
+
+	return scalarName, ok
+}
+
+// replace the alias of the namespace in the qualified name.
+func (ob *ODataBuilder) resolveAlias(qualifiedName string) string {
+	index := strings.LastIndex(qualifiedName, ".")
+	if index < 0 {
+		return qualifiedName
+	}
+
+	if namespace, ok := ob.aliases[qualifiedName[:index]]; ok {
+		return namespace + qualifiedName[index:]
+	}
+
+	return qualifiedName
+}
+
+// get properties of the structured type including properties of base types.
+func (ob *ODataBuilder) getStructuredProperties(qualifiedName string, structuredType odataStructuredType) []odataProperty {
+	visited := map[string]bool{qualifiedName: true}
+	properties := slices.Clone(structuredType.Properties)
+	for baseName := ob.resolveAlias(structuredType.BaseType); baseName != "" && !visited[baseName]; {
+		visited[baseName] = true
+		baseType, ok := ob.entityTypes[baseName]
+		if !ok {
+			baseType, ok = ob.complexTypes[baseName]
+		}
+		if !ok {
+			break
+		}
+
+		properties = append(slices.Clone(baseType.Properties), properties...)
+		baseName = ob.resolveAlias(baseType.BaseType)
+	}
+
+	return properties
+}
+
+// get key property names of the entity type. Keys may be inherited from the base type.
+func (ob *ODataBuilder) getEntityKeys(qualifiedName string, entityType odataStructuredType) []string {
+	visited := map[string]bool{}
+	for {
+		if len(entityType.Keys) > 0 {
+			keys := make([]string, len(entityType.Keys))
+			for i, key := range entityType.Keys {
+				keys[i] = key.Name
+			}
+
+			return keys
+		}
+
+		visited[qualifiedName] = true
+		qualifiedName = ob.resolveAlias(entityType.BaseType)
+		baseType, ok := ob.entityTypes[qualifiedName]
+		if qualifiedName == "" || visited[qualifiedName] || !ok {
+			return nil
+		}
+
+		entityType = baseType
+	}
+}
+
+// odataPrimitiveProperty represents a filterable and sortable property of primitive types.
+type odataPrimitiveProperty struct {
+	// the property path in dot notation
+	path            string
+	filterOperators []string
+	unquoted        bool
+}
+
+// collect properties of primitive types and nested complex types.
+// Collection properties can't be compared in $filter and $orderby expressions.
+func (ob *ODataBuilder) collectPrimitiveProperties(structuredType odataStructuredType, prefix string, depth int) []odataPrimitiveProperty {
+	var results []odataPrimitiveProperty
+	for _, property := range ob.getStructuredProperties("", structuredType) {
+		if _, isArray := unwrapODataCollectionType(property.Type); isArray {
+			continue
+		}
+
+		qualifiedName := ob.resolveAlias(property.Type)
+		if complexType, ok := ob.complexTypes[qualifiedName]; ok {
+			if depth < odataMaxPropertyDepth {
+				results = append(results, ob.collectPrimitiveProperties(complexType, prefix+property.Name+".", depth+1)...)
+			}
+
+			continue
+		}
+
+		primitiveType := qualifiedName
+		if typeDefinition, ok := ob.typeDefinitions[qualifiedName]; ok {
+			primitiveType = typeDefinition.UnderlyingType
+		}
+
+		result := odataPrimitiveProperty{
+			path:     prefix + property.Name,
+			unquoted: slices.Contains(odataUnquotedTypes, primitiveType),
+		}
+
+		switch scalarName := odataPrimitiveScalars[primitiveType]; {
+		case ob.enumTypes[qualifiedName].Name != "":
+			result.filterOperators = []string{"_eq", "_neq", "_in"}
+		case scalarName == rest.ScalarBoolean:
+			result.filterOperators = []string{"_eq", "_neq"}
+		case scalarName == rest.ScalarBytes || scalarName == rest.ScalarJSON || scalarName == "":
+			// binary, stream and untyped properties aren't comparable
+			continue
+		case scalarName == rest.ScalarString || scalarName == rest.ScalarUUID:
+			result.filterOperators = []string{"_eq", "_neq", "_in"}
+		default:
+			result.filterOperators = []string{"_eq", "_neq", "_gt", "_gte", "_lt", "_lte", "_in"}
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
+
+// get the JSON schema type of the scalar or object type
+func (ob *ODataBuilder) getJSONType(typeName string) string {
+	if _, ok := ob.schema.ObjectTypes[typeName]; ok {
+		return "object"
+	}
+
+	return getScalarJSONType(rest.ScalarName(typeName))
+}
+
+func getScalarJSONType(scalarName rest.ScalarName) string {
+	switch scalarName {
+	case rest.ScalarBoolean:
+		return "boolean"
+	case rest.ScalarInt32, rest.ScalarInt64:
+		return "integer"
+	case rest.ScalarFloat32, rest.ScalarFloat64:
+		return "number"
+	case rest.ScalarJSON:
+		return "object"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,102 @@
+package internal
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// odataEdmx represents the root element of OData v4 $metadata documents in the CSDL XML format.
+type odataEdmx struct {
+	XMLName xml.Name      `xml:"Edmx"`
+	Version string        `xml:"Version,attr"`
+	Schemas []odataSchema `xml:"DataServices>Schema"`
+}
+
+type odataSchema struct {
+	Namespace        string                 `xml:"Namespace,attr"`
+	Alias            string                 `xml:"Alias,attr"`
+	EntityTypes      []odataStructuredType  `xml:"EntityType"`
+	ComplexTypes     []odataStructuredType  `xml:"ComplexType"`
+	EnumTypes        []odataEnumType        `xml:"EnumType"`
+	TypeDefinitions  []odataTypeDefinition  `xml:"TypeDefinition"`
+	EntityContainers []odataEntityContainer `xml:"EntityContainer"`
+}
+
+// odataStructuredType represents entity types and complex types.
+type odataStructuredType struct {
+	Name        string             `xml:"Name,attr"`
+	BaseType    string             `xml:"BaseType,attr"`
+	Keys        []odataPropertyRef `xml:"Key>PropertyRef"`
+	Properties  []odataProperty    `xml:"Property"`
+	Annotations []odataAnnotation  `xml:"Annotation"`
+}
+
+type odataPropertyRef struct {
+	Name string `xml:"Name,attr"`
+}
+
+type odataProperty struct {
+	Name        string            `xml:"Name,attr"`
+	Type        string            `xml:"Type,attr"`
+	Nullable    *bool             `xml:"Nullable,attr"`
+	Annotations []odataAnnotation `xml:"Annotation"`
+}
+
+// IsNullable checks if the property is nullable. Properties are nullable by default.
+func (op odataProperty) IsNullable() bool {
+	return op.Nullable == nil || *op.Nullable
+}
+
+type odataEnumType struct {
+	Name    string            `xml:"Name,attr"`
+	Members []odataEnumMember `xml:"Member"`
+}
+
+type odataEnumMember struct {
+	Name string `xml:"Name,attr"`
+}
+
+type odataTypeDefinition struct {
+	Name           string `xml:"Name,attr"`
+	UnderlyingType string `xml:"UnderlyingType,attr"`
+}
+
+type odataEntityContainer struct {
+	Name        string            `xml:"Name,attr"`
+	EntitySets  []odataEntitySet  `xml:"EntitySet"`
+	Singletons  []odataEntitySet  `xml:"Singleton"`
+	Annotations []odataAnnotation `xml:"Annotation"`
+}
+
+// odataEntitySet represents entity sets and singletons. Singletons refer to the entity type with the Type attribute.
+type odataEntitySet struct {
+	Name        string            `xml:"Name,attr"`
+	EntityType  string            `xml:"EntityType,attr"`
+	Type        string            `xml:"Type,attr"`
+	Annotations []odataAnnotation `xml:"Annotation"`
+}
+
+type odataAnnotation struct {
+	Term   string `xml:"Term,attr"`
+	String string `xml:"String,attr"`
+}
+
+// get the description of Core.Description annotations.
+func getODataDescription(annotations []odataAnnotation) string {
+	for _, annotation := range annotations {
+		if annotation.Term == "Core.Description" || annotation.Term == "Org.OData.Core.V1.Description" {
+			return annotation.String
+		}
+	}
+
+	return ""
+}
+
+// get the element type name of the collection type, e.g. Collection(Edm.String).
+func unwrapODataCollectionType(typeName string) (string, bool) {
+	if strings.HasPrefix(typeName, "Collection(") && strings.HasSuffix(typeName, ")") {
+		return typeName[len("Collection(") : len(typeName)-1], true
+	}
+
+	return typeName, false
+}
@@ -0,0 +1,788 @@
+package internal
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
+)
+
+const (
+	postmanBearerScheme = "bearer"
+	postmanBasicScheme  = "basic"
+	postmanAPIKeyScheme = "api_key"
+)
+
+// PostmanBuilder the NDC schema builder from Postman Collection v2.1 documents.
+// GET requests are converted to functions and other requests to procedures.
+type PostmanBuilder struct {
+	*ConvertOptions
+
+	schema     *rest.NDCHttpSchema
+	collection *postmanCollection
+	variables  map[string]string
+	serverURL  string
+}
+
+// NewPostmanBuilder creates a PostmanBuilder instance
+func NewPostmanBuilder(options ConvertOptions) *PostmanBuilder {
+	return &PostmanBuilder{
+		schema:         rest.NewNDCHttpSchema(),
+		ConvertOptions: applyConvertOptions(options),
+		variables:      make(map[string]string),
+	}
+}
+
+// BuildSchema converts the Postman collection to NDC HTTP schema.
+func (pb *PostmanBuilder) BuildSchema(input []byte) (*rest.NDCHttpSchema, error) {
+	var collection postmanCollection
+	if err := json.Unmarshal(input, &collection); err != nil {
+		return nil, fmt.Errorf("failed to decode the Postman collection: %w", err)
+	}
+
+	if collection.Info.Schema != "" && !strings.Contains(collection.Info.Schema, "v2.") {
+		pb.Logger.Warn("the collection schema isn't v2.1, some fields may be ignored: " + collection.Info.Schema)
+	}
+
+	return pb.buildCollection(&collection)
+}
+
+// build the NDC HTTP schema from the decoded collection.
+// HAR and Insomnia documents are converted to collections and built here.
+func (pb *PostmanBuilder) buildCollection(collection *postmanCollection) (*rest.NDCHttpSchema, error) {
+	pb.collection = collection
+	for _, variable := range collection.Variable {
+		if variable.Value != nil {
+			pb.variables[variable.Key] = fmt.Sprint(variable.Value)
+		}
+	}
+
+	if collection.Auth != nil {
+		if key := pb.convertAuth(collection.Auth); key != "" {
+			pb.schema.Settings.Security = rest.AuthSecurities{
+				rest.NewAuthSecurity(key, []string{}),
+			}
+		}
+	}
+
+	pb.convertItems(collection.Item, []string{}, collection.Auth)
+
+	if len(pb.schema.Functions) == 0 && len(pb.schema.Procedures) == 0 {
+		return nil, errNoPostmanRequest
+	}
+
+	envName := utils.StringSliceToConstantCase([]string{pb.EnvPrefix, "SERVER_URL"})
+	server := rest.ServerConfig{
+		URL: sdkUtils.NewEnvStringVariable(envName),
+	}
+	if pb.serverURL != "" {
+		if serverURL, ok := pb.resolveVariables(pb.serverURL); ok {
+			server.URL = sdkUtils.NewEnvString(envName, serverURL)
+		}
+	}
+	pb.schema.Settings.Servers = []rest.ServerConfig{server}
+
+	return NewNDCBuilder(pb.schema, *pb.ConvertOptions).Build()
+}
+
+// convert requests of items recursively. Folder names are used as tags of operations.
+func (pb *PostmanBuilder) convertItems(items []postmanItem, folders []string, auth *postmanAuth) {
+	for _, item := range items {
+		itemAuth := auth
+		if item.Auth != nil {
+			itemAuth = item.Auth
+		}
+
+		if item.IsFolder() {
+			pb.convertItems(item.Item, append(slices.Clone(folders), item.Name), itemAuth)
+
+			continue
+		}
+
+		pb.convertRequest(&item, folders, itemAuth)
+	}
+}
+
+func (pb *PostmanBuilder) convertRequest(item *postmanItem, folders []string, auth *postmanAuth) {
+	request := item.Request
+	if request.Auth != nil {
+		auth = request.Auth
+	}
+
+	method := strings.ToLower(request.Method)
+	if method == "" {
+		method = "get"
+	}
+
+	if !slices.Contains([]string{"get", "post", "put", "patch", "delete"}, method) {
+		pb.Logger.Warn(fmt.Sprintf("%s: unsupported method %s, skipped", item.Name, request.Method))
+
+		return
+	}
+
+	arguments := make(map[string]rest.ArgumentInfo)
+	requestURL := pb.convertURL(&request.URL, arguments)
+	if requestURL == "" {
+		pb.Logger.Warn(fmt.Sprintf("%s: the request URL has unresolved variables in the host, skipped", item.Name))
+
+		return
+	}
+
+	operationName := pb.buildOperationName(item.Name, folders, requestURL, method)
+	typeNamePrefix := utils.ToPascalCase(operationName)
+
+	result := &rest.OperationInfo{
+		Request: &rest.Request{
+			URL:    requestURL,
+			Method: method,
+		},
+		Arguments: arguments,
+	}
+
+	if len(folders) > 0 {
+		result.Tags = slices.Clone(folders)
+	}
+
+	description := string(request.Description)
+	if description == "" {
+		description = string(item.Description)
+	}
+	if description == "" {
+		description = item.Name
+	}
+	result.Description = &description
+
+	contentType := pb.convertHeaders(request.Header, result.Request, arguments, auth != nil)
+
+	if auth != nil && auth != pb.collection.Auth {
+		if auth.Type == "noauth" {
+			result.Request.Security = rest.AuthSecurities{rest.AuthSecurity{}}
+		} else if key := pb.convertAuth(auth); key != "" {
+			result.Request.Security = rest.AuthSecurities{
+				rest.NewAuthSecurity(key, []string{}),
+			}
+		}
+	}
+
+	if method != "get" && request.Body != nil && !request.Body.Disabled {
+		bodyContentType, bodyArgument := pb.convertBody(request.Body, contentType, typeNamePrefix+"Body")
+		if bodyArgument != nil {
+			if _, ok := arguments[rest.BodyKey]; ok {
+				pb.Logger.Warn(fmt.Sprintf("%s: the body argument is duplicated with a request parameter and ignored", item.Name))
+			} else {
+				bodyDescription := "Request body of " + cmp.Or(item.Name, description)
+				bodyArgument.Description = &bodyDescription
+				arguments[rest.BodyKey] = *bodyArgument
+				result.Request.RequestBody = &rest.RequestBody{
+					ContentType: bodyContentType,
+				}
+			}
+		}
+	}
+
+	resultType, responseContentType := pb.convertResponse(item.Response, typeNamePrefix+"Result")
+	result.ResultType = resultType.Encode()
+	result.Request.Response = rest.Response{
+		ContentType: responseContentType,
+	}
+
+	if method == "get" {
+		pb.schema.Functions[operationName] = *result
+	} else {
+		pb.schema.Procedures[operationName] = *result
+	}
+}
+
+// build the unique operation name from the request name.
+// Folder names are prepended if the name is duplicated.
+func (pb *PostmanBuilder) buildOperationName(name string, folders []string, requestURL string, method string) string {
+	isExisted := func(operationName string) bool {
+		_, isFunction := pb.schema.Functions[operationName]
+		_, isProcedure := pb.schema.Procedures[operationName]
+
+		return isFunction || isProcedure
+	}
+
+	operationName := utils.ToCamelCase(name)
+	if operationName == "" {
+		requestPath := strings.Split(requestURL, "?")[0]
+		if u, err := url.Parse(requestPath); err == nil && u.IsAbs() {
+			requestPath = u.Path
+		}
+		operationName = buildPathMethodName(requestPath, method, pb.ConvertOptions)
+	}
+
+	if !isExisted(operationName) {
+		return operationName
+	}
+
+	if len(folders) > 0 {
+		operationName = utils.ToCamelCase(strings.Join(append(slices.Clone(folders), name), " "))
+		if !isExisted(operationName) {
+			return operationName
+		}
+	}
+
+	for i := 2; ; i++ {
+		candidate := operationName + strconv.Itoa(i)
+		if !isExisted(candidate) {
+			return candidate
+		}
+	}
+}
+
+// convert the request URL to the relative path with path and query arguments.
+// Returns an absolute URL if the host is different from the server URL, or empty if the host can't be resolved.
+func (pb *PostmanBuilder) convertURL(requestURL *postmanURL, arguments map[string]rest.ArgumentInfo) string {
+	baseURL := requestURL.BaseURL()
+	if pb.serverURL == "" {
+		pb.serverURL = baseURL
+	}
+
+	pathVariables := make(map[string]postmanVariable)
+	for _, variable := range requestURL.Variable {
+		pathVariables[variable.Key] = variable
+	}
+
+	addPathArgument := func(name string) {
+		if _, ok := arguments[name]; ok {
+			return
+		}
+
+		variable := pathVariables[name]
+		var example string
+		if variable.Value != nil {
+			example = fmt.Sprint(variable.Value)
+		}
+		scalarName, typeSchema := pb.inferScalarFromString(example)
+		argument := rest.ArgumentInfo{
+			ArgumentInfo: schema.ArgumentInfo{
+				Type: schema.NewNamedType(scalarName).Encode(),
+			},
+			HTTP: &rest.RequestParameter{
+				Name:   name,
+				In:     rest.InPath,
+				Schema: typeSchema,
+			},
+		}
+		if variable.Description != "" {
+			description := string(variable.Description)
+			argument.Description = &description
+		}
+
+		arguments[name] = argument
+	}
+
+	segments := make([]string, 0, len(requestURL.Path))
+	for _, segment := range requestURL.Path {
+		if segment == "" {
+			continue
+		}
+
+		if strings.HasPrefix(segment, ":") && len(segment) > 1 {
+			name := segment[1:]
+			addPathArgument(name)
+			segments = append(segments, "{"+name+"}")
+
+			continue
+		}
+
+		// variables without values in the collection are converted to path arguments
+		segment = postmanVariableRegex.ReplaceAllStringFunc(segment, func(s string) string {
+			name := postmanVariableRegex.FindStringSubmatch(s)[1]
+			if value, ok := pb.variables[name]; ok {
+				return value
+			}
+
+			addPathArgument(name)
+
+			return "{" + name + "}"
+		})
+		segments = append(segments, segment)
+	}
+
+	path := "/" + strings.Join(segments, "/")
+
+	for _, query := range requestURL.Query {
+		if query.Disabled || query.Key == "" {
+			continue
+		}
+
+		if _, ok := arguments[query.Key]; ok {
+			continue
+		}
+
+		value, _ := pb.resolveVariables(query.Value)
+		scalarName, typeSchema := pb.inferScalarFromString(value)
+		argument := rest.ArgumentInfo{
+			ArgumentInfo: schema.ArgumentInfo{
+				Type: schema.NewNullableType(schema.NewNamedType(scalarName)).Encode(),
+			},
+			HTTP: &rest.RequestParameter{
+				Name:   query.Key,
+				In:     rest.InQuery,
+				Schema: typeSchema,
+			},
+		}
+		if query.Description != "" {
+			description := string(query.Description)
+			argument.Description = &description
+		}
+
+		arguments[query.Key] = argument
+	}
+
+	if baseURL == pb.serverURL {
+		return path
+	}
+
+	resolvedBaseURL, ok := pb.resolveVariables(baseURL)
+	if !ok {
+		return ""
+	}
+
+	return resolvedBaseURL + path
+}
+
+// convert request headers to static headers or header arguments if the value has variables.
+// Returns the value of the Content-Type header.
+func (pb *PostmanBuilder) convertHeaders(headers []postmanKeyValue, request *rest.Request, arguments map[string]rest.ArgumentInfo, hasAuth bool) string {
+	var contentType string
+	for _, header := range headers {
+		if header.Disabled || header.Key == "" {
+			continue
+		}
+
+		switch strings.ToLower(header.Key) {
+		case "content-type":
+			contentType = strings.TrimSpace(strings.Split(header.Value, ";")[0])
+
+			continue
+		case "accept", "content-length", "host":
+			continue
+		case "authorization":
+			if hasAuth {
+				continue
+			}
+		}
+
+		if !postmanVariableRegex.MatchString(header.Value) {
+			if request.Headers == nil {
+				request.Headers = make(map[string]sdkUtils.EnvString)
+			}
+			request.Headers[header.Key] = sdkUtils.NewEnvStringValue(header.Value)
+
+			continue
+		}
+
+		argumentName := encodeHeaderArgumentName(header.Key)
+		if _, ok := arguments[argumentName]; ok {
+			continue
+		}
+
+		pb.schema.AddScalar(string(rest.ScalarString), *defaultScalarTypes[rest.ScalarString])
+		argument := rest.ArgumentInfo{
+			ArgumentInfo: schema.ArgumentInfo{
+				Type: schema.NewNullableType(schema.NewNamedType(string(rest.ScalarString))).Encode(),
+			},
+			HTTP: &rest.RequestParameter{
+				Name: header.Key,
+				In:   rest.InHeader,
+				Schema: &rest.TypeSchema{
+					Type: []string{"string"},
+				},
+			},
+		}
+		if header.Description != "" {
+			description := string(header.Description)
+			argument.Description = &description
+		}
+
+		arguments[argumentName] = argument
+	}
+
+	return contentType
+}
+
+// convert the auth object to the security scheme. Returns the key of the scheme.
+func (pb *PostmanBuilder) convertAuth(auth *postmanAuth) string {
+	if pb.schema.Settings.SecuritySchemes == nil {
+		pb.schema.Settings.SecuritySchemes = make(map[string]rest.SecurityScheme)
+	}
+
+	var key string
+	var scheme rest.SecurityScheme
+	switch auth.Type {
+	case "bearer":
+		key = postmanBearerScheme
+		valueEnv := sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase([]string{pb.EnvPrefix, key, "TOKEN"}))
+		scheme.SecuritySchemer = rest.NewHTTPAuthConfig("bearer", rest.AuthorizationHeader, valueEnv)
+	case "basic":
+		key = postmanBasicScheme
+		user := sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase([]string{pb.EnvPrefix, key, "USERNAME"}))
+		password := sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase([]string{pb.EnvPrefix, key, "PASSWORD"}))
+		scheme.SecuritySchemer = rest.NewBasicAuthConfig(user, password)
+	case "apikey":
+		key = postmanAPIKeyScheme
+		name := getPostmanAuthAttribute(auth.APIKey, "key")
+		if name == "" {
+			name = "X-API-Key"
+		}
+
+		location := rest.APIKeyInHeader
+		if getPostmanAuthAttribute(auth.APIKey, "in") == "query" {
+			location = rest.APIKeyInQuery
+		}
+
+		valueEnv := sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase([]string{pb.EnvPrefix, key}))
+		scheme.SecuritySchemer = rest.NewAPIKeyAuthConfig(name, location, valueEnv)
+	case "noauth", "":
+		return ""
+	default:
+		pb.Logger.Warn(fmt.Sprintf("unsupported auth type %s, skipped", auth.Type))
+
+		return ""
+	}
+
+	pb.schema.Settings.SecuritySchemes[key] = scheme
+
+	return key
+}
+
+// convert the example body to the body argument. Returns the content type and the argument.
+func (pb *PostmanBuilder) convertBody(body *postmanBody, contentType string, typeName string) (string, *rest.ArgumentInfo) {
+	var bodyType schema.TypeEncoder
+	var typeSchema *rest.TypeSchema
+
+	switch body.Mode {
+	case "raw":
+		if strings.TrimSpace(body.Raw) == "" {
+			return "", nil
+		}
+
+		language := body.Options.Raw.Language
+		if language == "json" || utils.IsContentTypeJSON(contentType) || (contentType == "" && language == "" && isJSONLikeString(body.Raw)) {
+			if !utils.IsContentTypeJSON(contentType) {
+				contentType = rest.ContentTypeJSON
+			}
+
+			// variables in the example body are replaced with null values to be decodable
+			value, err := decodeJSONExample(postmanVariableRegex.ReplaceAllString(body.Raw, "null"))
+			if err != nil {
+				pb.Logger.Warn(fmt.Sprintf("%s: failed to decode the example JSON body, fallback to JSON: %s", typeName, err))
+				value = nil
+			}
+
+			bodyType, typeSchema = pb.inferType(typeName, value)
+
+			break
+		}
+
+		if !utils.IsContentTypeText(contentType) {
+			switch language {
+			case "xml":
+				contentType = "text/xml"
+			case "html":
+				contentType = "text/html"
+			default:
+				contentType = rest.ContentTypeTextPlain
+			}
+		}
+
+		pb.schema.AddScalar(string(rest.ScalarString), *defaultScalarTypes[rest.ScalarString])
+		bodyType = schema.NewNamedType(string(rest.ScalarString))
+		typeSchema = &rest.TypeSchema{Type: []string{"string"}}
+	case "urlencoded", "formdata":
+		fields := body.URLEncoded
+		contentType = rest.ContentTypeFormURLEncoded
+		if body.Mode == "formdata" {
+			fields = body.FormData
+			contentType = rest.ContentTypeMultipartFormData
+		}
+
+		objectType := rest.ObjectType{
+			Fields: make(map[string]rest.ObjectField),
+		}
+		for _, field := range fields {
+			if field.Disabled || field.Key == "" {
+				continue
+			}
+
+			var scalarName string
+			var fieldSchema *rest.TypeSchema
+			if field.Type == "file" {
+				pb.schema.AddScalar(string(rest.ScalarBinary), *defaultScalarTypes[rest.ScalarBinary])
+				scalarName = string(rest.ScalarBinary)
+				fieldSchema = &rest.TypeSchema{Type: []string{"string"}, Format: "binary"}
+			} else {
+				value, _ := pb.resolveVariables(field.Value)
+				scalarName, fieldSchema = pb.inferScalarFromString(value)
+			}
+
+			objectField := rest.ObjectField{
+				ObjectField: schema.ObjectField{
+					Type: schema.NewNullableType(schema.NewNamedType(scalarName)).Encode(),
+				},
+				HTTP: fieldSchema,
+			}
+			if field.Description != "" {
+				description := string(field.Description)
+				objectField.Description = &description
+			}
+
+			objectType.Fields[field.Key] = objectField
+		}
+
+		if len(objectType.Fields) == 0 {
+			return "", nil
+		}
+
+		pb.schema.ObjectTypes[typeName] = objectType
+		bodyType = schema.NewNamedType(typeName)
+		typeSchema = &rest.TypeSchema{Type: []string{"object"}}
+	case "graphql":
+		contentType = rest.ContentTypeJSON
+		pb.schema.AddScalar(string(rest.ScalarString), *defaultScalarTypes[rest.ScalarString])
+		pb.schema.AddScalar(string(rest.ScalarJSON), *defaultScalarTypes[rest.ScalarJSON])
+		pb.schema.ObjectTypes[typeName] = rest.ObjectType{
+			Fields: map[string]rest.ObjectField{
+				"query": {
+					ObjectField: schema.ObjectField{
+						Type: schema.NewNamedType(string(rest.ScalarString)).Encode(),
+					},
+					HTTP: &rest.TypeSchema{Type: []string{"string"}},
+				},
+				"variables": {
+					ObjectField: schema.ObjectField{
+						Type: schema.NewNullableType(schema.NewNamedType(string(rest.ScalarJSON))).Encode(),
+					},
+					HTTP: &rest.TypeSchema{Type: []string{}},
+				},
+			},
+		}
+		bodyType = schema.NewNamedType(typeName)
+		typeSchema = &rest.TypeSchema{Type: []string{"object"}}
+	case "file":
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+
+		pb.schema.AddScalar(string(rest.ScalarBinary), *defaultScalarTypes[rest.ScalarBinary])
+		bodyType = schema.NewNamedType(string(rest.ScalarBinary))
+		typeSchema = &rest.TypeSchema{Type: []string{"string"}, Format: "binary"}
+	default:
+		return "", nil
+	}
+
+	return contentType, &rest.ArgumentInfo{
+		ArgumentInfo: schema.ArgumentInfo{
+			Type: bodyType.Encode(),
+		},
+		HTTP: &rest.RequestParameter{
+			In:     rest.InBody,
+			Schema: typeSchema,
+		},
+	}
+}
+
+// convert the result type from the example of successful responses.
+// Returns the nullable JSON scalar if there isn't any example.
+func (pb *PostmanBuilder) convertResponse(responses []postmanResponse, typeName string) (schema.TypeEncoder, string) {
+	index := slices.IndexFunc(responses, func(res postmanResponse) bool {
+		return res.Code >= 200 && res.Code < 300
+	})
+	if index < 0 && len(responses) > 0 && responses[0].Code == 0 {
+		index = 0
+	}
+
+	if index < 0 {
+		pb.schema.AddScalar(string(rest.ScalarJSON), *defaultScalarTypes[rest.ScalarJSON])
+
+		return schema.NewNullableType(schema.NewNamedType(string(rest.ScalarJSON))), rest.ContentTypeJSON
+	}
+
+	response := responses[index]
+	var contentType string
+	for _, header := range response.Header {
+		if strings.EqualFold(header.Key, "content-type") {
+			contentType = strings.TrimSpace(strings.Split(header.Value, ";")[0])
+		}
+	}
+
+	if contentType == "" || utils.IsContentTypeJSON(contentType) {
+		value, err := decodeJSONExample(response.Body)
+		if err == nil || contentType != "" {
+			if contentType == "" {
+				contentType = rest.ContentTypeJSON
+			}
+
+			resultType, _ := pb.inferType(typeName, value)
+
+			return schema.NewNullableType(resultType), contentType
+		}
+
+		contentType = rest.ContentTypeTextPlain
+	}
+
+	scalarName := guessScalarResultTypeFromContentType(contentType)
+	pb.schema.AddScalar(string(scalarName), *defaultScalarTypes[scalarName])
+
+	return schema.NewNullableType(schema.NewNamedType(string(scalarName))), contentType
+}
+
+// infer the NDC type from the example JSON value. Fields of objects are nullable
+// because examples don't tell which fields are required.
+func (pb *PostmanBuilder) inferType(typeName string, value any) (schema.TypeEncoder, *rest.TypeSchema) {
+	switch v := value.(type) {
+	case bool:
+		pb.schema.AddScalar(string(rest.ScalarBoolean), *defaultScalarTypes[rest.ScalarBoolean])
+
+		return schema.NewNamedType(string(rest.ScalarBoolean)), &rest.TypeSchema{Type: []string{"boolean"}}
+	case json.Number:
+		scalarName, typeSchema := pb.inferScalarFromString(v.String())
+
+		return schema.NewNamedType(scalarName), typeSchema
+	case string:
+		pb.schema.AddScalar(string(rest.ScalarString), *defaultScalarTypes[rest.ScalarString])
+
+		return schema.NewNamedType(string(rest.ScalarString)), &rest.TypeSchema{Type: []string{"string"}}
+	case []any:
+		element := mergeJSONExamples(v)
+		elementType, elementSchema := pb.inferType(typeName+"Item", element)
+		if element == nil {
+			elementType = schema.NewNullableType(elementType)
+		}
+
+		return schema.NewArrayType(elementType), &rest.TypeSchema{
+			Type:  []string{"array"},
+			Items: elementSchema,
+		}
+	case map[string]any:
+		objectType := rest.ObjectType{
+			Fields: make(map[string]rest.ObjectField),
+		}
+		for key, fieldValue := range v {
+			fieldType, fieldSchema := pb.inferType(typeName+utils.ToPascalCase(key), fieldValue)
+			if _, ok := fieldType.(*schema.NullableType); !ok {
+				fieldType = schema.NewNullableType(fieldType)
+			}
+
+			objectType.Fields[key] = rest.ObjectField{
+				ObjectField: schema.ObjectField{
+					Type: fieldType.Encode(),
+				},
+				HTTP: fieldSchema,
+			}
+		}
+
+		pb.schema.ObjectTypes[typeName] = objectType
+
+		return schema.NewNamedType(typeName), &rest.TypeSchema{Type: []string{"object"}}
+	default:
+		pb.schema.AddScalar(string(rest.ScalarJSON), *defaultScalarTypes[rest.ScalarJSON])
+
+		return schema.NewNullableType(schema.NewNamedType(string(rest.ScalarJSON))), &rest.TypeSchema{Type: []string{}}
+	}
+}
+
+// infer the scalar from the example value of parameters, e.g. 10 is Int32, true is Boolean.
+func (pb *PostmanBuilder) inferScalarFromString(value string) (string, *rest.TypeSchema) {
+	var scalarName rest.ScalarName
+	var typeSchema *rest.TypeSchema
+
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		scalarName = rest.ScalarInt32
+		typeSchema = &rest.TypeSchema{Type: []string{"integer"}}
+		if i > math.MaxInt32 || i < math.MinInt32 {
+			scalarName = rest.ScalarInt64
+			typeSchema.Format = "int64"
+		}
+	} else if _, err := strconv.ParseFloat(value, 64); err == nil && value != "NaN" && !strings.Contains(strings.ToLower(value), "inf") {
+		scalarName = rest.ScalarFloat64
+		typeSchema = &rest.TypeSchema{Type: []string{"number"}}
+	} else if value == "true" || value == "false" {
+		scalarName = rest.ScalarBoolean
+		typeSchema = &rest.TypeSchema{Type: []string{"boolean"}}
+	} else {
+		scalarName = rest.ScalarString
+		typeSchema = &rest.TypeSchema{Type: []string{"string"}}
+	}
+
+	pb.schema.AddScalar(string(scalarName), *defaultScalarTypes[scalarName])
+
+	return string(scalarName), typeSchema
+}
+
+// replace variables of the collection in the string. Returns false if there are unresolved variables.
+func (pb *PostmanBuilder) resolveVariables(input string) (string, bool) {
+	resolved := true
+	result := postmanVariableRegex.ReplaceAllStringFunc(input, func(s string) string {
+		name := postmanVariableRegex.FindStringSubmatch(s)[1]
+		if value, ok := pb.variables[name]; ok {
+			return value
+		}
+		resolved = false
+
+		return s
+	})
+
+	return result, resolved
+}
+
+// merge examples of array elements. Fields of object elements are combined.
+func mergeJSONExamples(values []any) any {
+	var result map[string]any
+	for _, value := range values {
+		object, ok := value.(map[string]any)
+		if !ok {
+			if value != nil && result == nil {
+				return value
+			}
+
+			continue
+		}
+
+		if result == nil {
+			result = make(map[string]any)
+		}
+
+		for key, fieldValue := range object {
+			if existing, ok := result[key]; !ok || existing == nil {
+				result[key] = fieldValue
+			}
+		}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	return result
+}
+
+func decodeJSONExample(input string) (any, error) {
+	decoder := json.NewDecoder(strings.NewReader(input))
+	decoder.UseNumber()
+
+	var result any
+	if err := decoder.Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func isJSONLikeString(input string) bool {
+	input = strings.TrimSpace(input)
+
+	return strings.HasPrefix(input, "{") || strings.HasPrefix(input, "[")
+}
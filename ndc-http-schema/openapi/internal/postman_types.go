@@ -0,0 +1,291 @@
+package internal
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+var (
+	postmanVariableRegex     = regexp.MustCompile(`\{\{\s*([^{}\s]+)\s*\}\}`)
+	postmanFullVariableRegex = regexp.MustCompile(`^\{\{\s*([^{}\s]+)\s*\}\}$`)
+	postmanRawURLRegex       = regexp.MustCompile(`^((?:[a-zA-Z][a-zA-Z0-9+.-]*://)?[^/?#]*)([^?#]*)(?:\?([^#]*))?`)
+)
+
+// postmanCollection represents the root object of Postman Collection v2.1 documents.
+type postmanCollection struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Auth     *postmanAuth      `json:"auth,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+type postmanInfo struct {
+	Name        string             `json:"name"`
+	Description postmanDescription `json:"description,omitempty"`
+	Schema      string             `json:"schema"`
+}
+
+// postmanItem is either a request or a folder of items.
+type postmanItem struct {
+	Name        string             `json:"name"`
+	Description postmanDescription `json:"description,omitempty"`
+	Item        []postmanItem      `json:"item,omitempty"`
+	Auth        *postmanAuth       `json:"auth,omitempty"`
+	Request     *postmanRequest    `json:"request,omitempty"`
+	Response    []postmanResponse  `json:"response,omitempty"`
+}
+
+// IsFolder checks if the item is a folder.
+func (pi postmanItem) IsFolder() bool {
+	return pi.Request == nil
+}
+
+type postmanRequest struct {
+	Method      string             `json:"method"`
+	URL         postmanURL         `json:"url"`
+	Header      []postmanKeyValue  `json:"header,omitempty"`
+	Body        *postmanBody       `json:"body,omitempty"`
+	Auth        *postmanAuth       `json:"auth,omitempty"`
+	Description postmanDescription `json:"description,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The request can be a URL string.
+func (pr *postmanRequest) UnmarshalJSON(b []byte) error {
+	var rawURL string
+	if err := json.Unmarshal(b, &rawURL); err == nil {
+		*pr = postmanRequest{
+			Method: "GET",
+			URL:    parsePostmanRawURL(rawURL),
+		}
+
+		return nil
+	}
+
+	type plain postmanRequest
+	var result plain
+	if err := json.Unmarshal(b, &result); err != nil {
+		return err
+	}
+
+	*pr = postmanRequest(result)
+
+	return nil
+}
+
+// postmanURL represents the URL object of the request.
+type postmanURL struct {
+	Raw      string            `json:"raw,omitempty"`
+	Protocol string            `json:"protocol,omitempty"`
+	Host     postmanPath       `json:"host,omitempty"`
+	Port     string            `json:"port,omitempty"`
+	Path     postmanPath       `json:"path,omitempty"`
+	Query    []postmanKeyValue `json:"query,omitempty"`
+	Variable []postmanVariable `json:"variable,omitempty"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The URL can be a raw string.
+func (pu *postmanURL) UnmarshalJSON(b []byte) error {
+	var rawURL string
+	if err := json.Unmarshal(b, &rawURL); err == nil {
+		*pu = parsePostmanRawURL(rawURL)
+
+		return nil
+	}
+
+	type plain postmanURL
+	var result plain
+	if err := json.Unmarshal(b, &result); err != nil {
+		return err
+	}
+
+	*pu = postmanURL(result)
+	if len(pu.Host) == 0 && len(pu.Path) == 0 && pu.Raw != "" {
+		parsed := parsePostmanRawURL(pu.Raw)
+		pu.Host = parsed.Host
+		pu.Path = parsed.Path
+		if len(pu.Query) == 0 {
+			pu.Query = parsed.Query
+		}
+	}
+
+	return nil
+}
+
+// BaseURL returns the protocol, host and port of the URL.
+func (pu postmanURL) BaseURL() string {
+	host := strings.Join(pu.Host, ".")
+	if host == "" {
+		return ""
+	}
+
+	if pu.Protocol != "" && !strings.Contains(host, "://") {
+		host = pu.Protocol + "://" + host
+	}
+
+	if pu.Port != "" {
+		host += ":" + pu.Port
+	}
+
+	return host
+}
+
+// postmanPath is an array of segments which can be a string separated by delimiters.
+type postmanPath []string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (pp *postmanPath) UnmarshalJSON(b []byte) error {
+	var rawPath string
+	if err := json.Unmarshal(b, &rawPath); err == nil {
+		*pp = []string{rawPath}
+
+		return nil
+	}
+
+	var segments []json.RawMessage
+	if err := json.Unmarshal(b, &segments); err != nil {
+		return err
+	}
+
+	result := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		var value string
+		if err := json.Unmarshal(segment, &value); err == nil {
+			result = append(result, value)
+
+			continue
+		}
+
+		// path segments can be objects with the type and value
+		var object struct {
+			Value string `json:"value"`
+		}
+		if err := json.Unmarshal(segment, &object); err != nil {
+			return err
+		}
+		result = append(result, object.Value)
+	}
+
+	*pp = result
+
+	return nil
+}
+
+type postmanKeyValue struct {
+	Key         string             `json:"key"`
+	Value       string             `json:"value"`
+	Type        string             `json:"type,omitempty"`
+	Src         any                `json:"src,omitempty"`
+	Disabled    bool               `json:"disabled,omitempty"`
+	Description postmanDescription `json:"description,omitempty"`
+}
+
+type postmanVariable struct {
+	Key         string             `json:"key"`
+	Value       any                `json:"value,omitempty"`
+	Type        string             `json:"type,omitempty"`
+	Description postmanDescription `json:"description,omitempty"`
+}
+
+type postmanBody struct {
+	Mode       string             `json:"mode"`
+	Raw        string             `json:"raw,omitempty"`
+	URLEncoded []postmanKeyValue  `json:"urlencoded,omitempty"`
+	FormData   []postmanKeyValue  `json:"formdata,omitempty"`
+	GraphQL    *json.RawMessage   `json:"graphql,omitempty"`
+	Options    postmanBodyOptions `json:"options,omitempty"`
+	Disabled   bool               `json:"disabled,omitempty"`
+}
+
+type postmanBodyOptions struct {
+	Raw struct {
+		Language string `json:"language,omitempty"`
+	} `json:"raw,omitempty"`
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanKeyValue `json:"bearer,omitempty"`
+	Basic  []postmanKeyValue `json:"basic,omitempty"`
+	APIKey []postmanKeyValue `json:"apikey,omitempty"`
+}
+
+// get the value of the auth attribute by key.
+func getPostmanAuthAttribute(attributes []postmanKeyValue, key string) string {
+	for _, attr := range attributes {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+
+	return ""
+}
+
+type postmanResponse struct {
+	Name   string            `json:"name"`
+	Code   int               `json:"code"`
+	Header []postmanKeyValue `json:"header,omitempty"`
+	Body   string            `json:"body,omitempty"`
+}
+
+// postmanDescription can be a string or an object with the content field.
+type postmanDescription string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (pd *postmanDescription) UnmarshalJSON(b []byte) error {
+	var value string
+	if err := json.Unmarshal(b, &value); err == nil {
+		*pd = postmanDescription(value)
+
+		return nil
+	}
+
+	var object struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(b, &object); err != nil {
+		return err
+	}
+
+	*pd = postmanDescription(object.Content)
+
+	return nil
+}
+
+// parse the raw URL string to the URL object, e.g. {{baseUrl}}/pets/:id?limit=10
+func parsePostmanRawURL(rawURL string) postmanURL {
+	result := postmanURL{
+		Raw: rawURL,
+	}
+
+	matches := postmanRawURLRegex.FindStringSubmatch(strings.TrimSpace(rawURL))
+	if len(matches) < 4 {
+		return result
+	}
+
+	if matches[1] != "" {
+		result.Host = []string{matches[1]}
+	}
+
+	for _, segment := range strings.Split(strings.TrimPrefix(matches[2], "/"), "/") {
+		if segment != "" {
+			result.Path = append(result.Path, segment)
+		}
+	}
+
+	if matches[3] != "" {
+		for _, pair := range strings.Split(matches[3], "&") {
+			if pair == "" {
+				continue
+			}
+
+			key, value, _ := strings.Cut(pair, "=")
+			result.Query = append(result.Query, postmanKeyValue{
+				Key:   key,
+				Value: value,
+			})
+		}
+	}
+
+	return result
+}
@@ -18,7 +18,17 @@ var (
 )
 
 var (
-	errParameterNameRequired = errors.New("parameter name is empty")
+	errParameterNameRequired  = errors.New("parameter name is empty")
+	errNoGraphQLOperation     = errors.New("there is no query or mutation field to be converted")
+	errNoSOAPBinding          = errors.New("there is no SOAP binding in the WSDL document")
+	errNoSOAPOperation        = errors.New("there is no SOAP operation to be converted")
+	errElementNameRequired    = errors.New("element name is empty")
+	errNoPostmanRequest       = errors.New("there is no request in the Postman collection to be converted")
+	errNoHARRequest           = errors.New("there is no API request in the HAR file to be converted")
+	errNoInsomniaRequest      = errors.New("there is no request in the Insomnia export file to be converted")
+	errNoODataEntityContainer = errors.New("there is no entity container in the OData metadata document")
+	errNoODataEntitySet       = errors.New("there is no entity set or singleton in the OData metadata document to be converted")
+	errNoAsyncAPIMessage      = errors.New("there is no message which the application sends in the AsyncAPI document")
 )
 
 var preferredContentTypes = []string{rest.ContentTypeJSON, rest.ContentTypeXML}
@@ -54,6 +64,11 @@ var defaultScalarTypes = map[rest.ScalarName]*schema.ScalarType{
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
 		Representation:      schema.NewTypeRepresentationFloat64().Encode(),
 	},
+	rest.ScalarBigDecimal: {
+		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
+		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
+		Representation:      schema.NewTypeRepresentationBigDecimal().Encode(),
+	},
 	rest.ScalarJSON: {
 		AggregateFunctions:  schema.ScalarTypeAggregateFunctions{},
 		ComparisonOperators: map[string]schema.ComparisonOperatorDefinition{},
@@ -119,6 +134,9 @@ var defaultScalarTypes = map[rest.ScalarName]*schema.ScalarType{
 
 const xmlValueFieldName string = "xmlValue"
 
+// the max depth of nested schemas to build the response example.
+const maxSchemaExampleDepth = 5
+
 var xmlValueField = rest.ObjectField{
 	ObjectField: schema.ObjectField{
 		Description: utils.ToPtr("Value of the xml field"),
@@ -141,6 +159,12 @@ type ConvertOptions struct {
 	EnvPrefix           string
 	Strict              bool
 	NoDeprecation       bool
+	NoDefaultValues     bool
+	EnrichLinks         bool
+	EmitCollections     bool
+	ResponseExamples    bool
+	Filter              *OperationFilter
+	Naming              *NamingStrategy
 	Logger              *slog.Logger
 }
 
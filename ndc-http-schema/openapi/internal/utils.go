@@ -1,10 +1,13 @@
 package internal
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/url"
 	"slices"
+	"strconv"
 	"strings"
 	"unicode"
 
@@ -13,6 +16,7 @@ import (
 	"github.com/hasura/ndc-sdk-go/schema"
 	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
 	"github.com/pb33f/libopenapi/datamodel/high/base"
+	"github.com/pb33f/libopenapi/orderedmap"
 	"gopkg.in/yaml.v3"
 )
 
@@ -240,6 +244,8 @@ func createSchemaFromOpenAPISchema(input *base.Schema) *rest.TypeSchema {
 	ps.Description = utils.StripHTMLTags(input.Description)
 	ps.ReadOnly = input.ReadOnly != nil && *input.ReadOnly
 	ps.WriteOnly = input.WriteOnly != nil && *input.WriteOnly
+	ps.Classification = getDataClassificationFromExtensions(input.Extensions)
+	ps.Default = decodeDefaultValue(input.Default)
 
 	if input.XML != nil {
 		ps.XML = &rest.XMLSchema{
@@ -254,6 +260,20 @@ func createSchemaFromOpenAPISchema(input *base.Schema) *rest.TypeSchema {
 	return ps
 }
 
+// decode the default value of the schema. Invalid values are ignored
+func decodeDefaultValue(node *yaml.Node) any {
+	if node == nil {
+		return nil
+	}
+
+	var value any
+	if err := node.Decode(&value); err != nil {
+		return nil
+	}
+
+	return value
+}
+
 // getMethodAlias merge method alias map with default value
 func getMethodAlias(inputs ...map[string]string) map[string]string {
 	methodAlias := map[string]string{
@@ -311,6 +331,39 @@ func isPrimitiveScalar(names []string) bool {
 	return true
 }
 
+// build a map scalar, e.g. StringMap, if the object only has additionalProperties of a primitive type.
+// The value schema is kept in the additionalProperties field of the type schema to validate and encode map values.
+func buildTypedMapType(httpSchema *rest.NDCHttpSchema, baseSchema *base.Schema) (schema.TypeEncoder, *rest.TypeSchema) {
+	if (baseSchema.Properties != nil && !baseSchema.Properties.IsZero()) ||
+		baseSchema.AdditionalProperties == nil || baseSchema.AdditionalProperties.A == nil {
+		return nil, nil
+	}
+
+	valueSchema := baseSchema.AdditionalProperties.A.Schema()
+	if valueSchema == nil || len(valueSchema.Enum) > 0 {
+		return nil, nil
+	}
+
+	valueTypes := evaluateOpenAPITypes(valueSchema.Type)
+	if len(valueTypes) != 1 || valueTypes[0] == "file" || !isPrimitiveScalar(valueTypes) {
+		return nil, nil
+	}
+
+	valueScalarName, _ := getScalarFromType(httpSchema, valueTypes, valueSchema.Format, nil, "", nil)
+	scalarName := valueScalarName + "Map"
+	httpSchema.AddScalar(scalarName, *defaultScalarTypes[rest.ScalarJSON])
+
+	var result schema.TypeEncoder = schema.NewNamedType(scalarName)
+	if (baseSchema.Nullable != nil && *baseSchema.Nullable) || slices.Contains(baseSchema.Type, "null") {
+		result = schema.NewNullableType(result)
+	}
+
+	typeResult := createSchemaFromOpenAPISchema(baseSchema)
+	typeResult.AdditionalProperties = createSchemaFromOpenAPISchema(valueSchema)
+
+	return result, typeResult
+}
+
 // get the inner named type of the type encoder
 func getNamedType(typeSchema schema.TypeEncoder, recursive bool, defaultValue string) string {
 	switch ty := typeSchema.(type) {
@@ -486,6 +539,21 @@ func isUnsupportedResponseCodes[T int | int64](code T) bool {
 	return code < 200 || (code >= 300 && code < 400)
 }
 
+// check if the response key is a 4xx or 5xx status code or status class, e.g. 404 or 4XX
+func isErrorResponseCode(key string) bool {
+	if len(key) != 3 || (key[0] != '4' && key[0] != '5') {
+		return false
+	}
+
+	if strings.EqualFold(key[1:], "XX") {
+		return true
+	}
+
+	_, err := strconv.ParseInt(key, 10, 32)
+
+	return err == nil
+}
+
 // format the operation name and remove special characters
 func formatOperationName(input string) string {
 	if input == "" {
@@ -512,8 +580,11 @@ func formatOperationName(input string) string {
 	return sb.String()
 }
 
-func buildUniqueOperationName(httpSchema *rest.NDCHttpSchema, operationId, pathKey, method string, options *ConvertOptions) string {
-	opName := formatOperationName(operationId)
+func buildUniqueOperationName(httpSchema *rest.NDCHttpSchema, operationId, pathKey, method string, tags []string, options *ConvertOptions) string {
+	opName := options.Naming.renderOperationName(operationId, pathKey, method, tags, options)
+	if opName == "" {
+		opName = formatOperationName(operationId)
+	}
 	exists := opName == ""
 	if !exists {
 		_, exists = httpSchema.Functions[opName]
@@ -581,6 +652,29 @@ func createTLSConfig(keys []string) *rest.TLSConfig {
 	}
 }
 
+// setOAuthFlowCredentialEnvs sets environment variables of credentials which the connector requests access tokens with.
+func setOAuthFlowCredentialEnvs(flow *rest.OAuthFlow, flowType rest.OAuthFlowType, keys []string) {
+	newEnv := func(name string) *sdkUtils.EnvString {
+		return sdkUtils.ToPtr(sdkUtils.NewEnvStringVariable(utils.StringSliceToConstantCase(append(keys, name))))
+	}
+
+	switch flowType {
+	case rest.ClientCredentialsFlow:
+		flow.ClientID = newEnv("CLIENT_ID")
+		flow.ClientSecret = newEnv("CLIENT_SECRET")
+	case rest.PasswordFlow:
+		flow.ClientID = newEnv("CLIENT_ID")
+		flow.ClientSecret = newEnv("CLIENT_SECRET")
+		flow.Username = newEnv("USERNAME")
+		flow.Password = newEnv("PASSWORD")
+	case rest.AuthorizationCodeFlow:
+		flow.ClientID = newEnv("CLIENT_ID")
+		flow.ClientSecret = newEnv("CLIENT_SECRET")
+		flow.RefreshToken = newEnv("REFRESH_TOKEN")
+	default:
+	}
+}
+
 func evalOperationPath(httpSchema *rest.NDCHttpSchema, rawPath string, arguments map[string]rest.ArgumentInfo) (string, map[string]rest.ArgumentInfo, error) {
 	var pathURL *url.URL
 	var isAbsolute bool
@@ -656,7 +750,8 @@ func evalOperationPath(httpSchema *rest.NDCHttpSchema, rawPath string, arguments
 func guessScalarResultTypeFromContentType(contentType string) rest.ScalarName {
 	ct := strings.TrimSpace(strings.Split(contentType, ";")[0])
 	switch {
-	case utils.IsContentTypeJSON(ct) || utils.IsContentTypeXML(ct) || ct == rest.ContentTypeNdJSON:
+	case utils.IsContentTypeJSON(ct) || utils.IsContentTypeXML(ct) || utils.IsContentTypeNdJSON(ct) ||
+		utils.IsContentTypeYAML(ct) || utils.IsContentTypeMsgPack(ct) || utils.IsContentTypeProtobuf(ct):
 		return rest.ScalarJSON
 	case utils.IsContentTypeText(ct):
 		return rest.ScalarString
@@ -687,3 +782,152 @@ func replaceNamedType(schemaType schema.Type, name string) (schema.TypeEncoder,
 		return nil, fmt.Errorf("invalid type: %v", schemaType)
 	}
 }
+
+// get the data classification from vendor extensions, e.g. x-data-classification: pii, x-pii: true or x-sensitive: true.
+// Unknown classification values are ignored.
+func getDataClassificationFromExtensions(extensions *orderedmap.Map[string, *yaml.Node]) rest.DataClassification {
+	if extensions == nil {
+		return ""
+	}
+
+	if node := extensions.GetOrZero("x-data-classification"); node != nil {
+		classification, err := rest.ParseDataClassification(strings.ToLower(node.Value))
+		if err == nil {
+			return classification
+		}
+	}
+
+	if node := extensions.GetOrZero("x-pii"); node != nil && node.Value == "true" {
+		return rest.DataClassificationPII
+	}
+
+	if node := extensions.GetOrZero("x-sensitive"); node != nil && node.Value == "true" {
+		return rest.DataClassificationSensitive
+	}
+
+	return ""
+}
+
+// ndcCollectionExtension represents the x-ndc-collection extension of list operations.
+type ndcCollectionExtension struct {
+	Filter     *rest.FilterSettings     `yaml:"filter"`
+	OrderBy    *rest.OrderBySettings    `yaml:"orderBy"`
+	Pagination *rest.PaginationSettings `yaml:"pagination"`
+}
+
+// decode the example node to a JSON-compatible value. Returns nil if the example can't be encoded to JSON, e.g. maps with non-string keys.
+func decodeExampleNode(node *yaml.Node) any {
+	var result any
+	if err := node.Decode(&result); err != nil {
+		return nil
+	}
+
+	if _, err := json.Marshal(result); err != nil {
+		return nil
+	}
+
+	return result
+}
+
+// build the example value from examples of the schema, or examples of properties and array items recursively.
+// Returns nil if there is no example. Deep and recursive schemas are cut off at the max depth.
+func buildSchemaExample(proxy *base.SchemaProxy, depth int) any {
+	if proxy == nil || depth > maxSchemaExampleDepth {
+		return nil
+	}
+
+	schemaObject := proxy.Schema()
+	if schemaObject == nil {
+		return nil
+	}
+
+	if schemaObject.Example != nil {
+		return decodeExampleNode(schemaObject.Example)
+	}
+
+	if len(schemaObject.Examples) > 0 {
+		return decodeExampleNode(schemaObject.Examples[0])
+	}
+
+	if schemaObject.Items != nil && schemaObject.Items.IsA() {
+		if item := buildSchemaExample(schemaObject.Items.A, depth+1); item != nil {
+			return []any{item}
+		}
+
+		return nil
+	}
+
+	result := map[string]any{}
+	for _, item := range schemaObject.AllOf {
+		if value, ok := buildSchemaExample(item, depth+1).(map[string]any); ok {
+			maps.Copy(result, value)
+		}
+	}
+
+	if schemaObject.Properties != nil {
+		for prop := schemaObject.Properties.First(); prop != nil; prop = prop.Next() {
+			if value := buildSchemaExample(prop.Value(), depth+1); value != nil {
+				result[prop.Key()] = value
+			}
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+
+	return result
+}
+
+// applyCollectionExtension sets filter, order by and pagination settings of the function from the x-ndc-collection extension.
+// The function is converted to a collection if the emitCollections option is enabled and the result type is an array of objects.
+func applyCollectionExtension(ndcSchema *rest.NDCHttpSchema, function *rest.OperationInfo, funcName string, extensions *orderedmap.Map[string, *yaml.Node], options *ConvertOptions) error {
+	if extensions == nil {
+		return nil
+	}
+
+	node := extensions.GetOrZero("x-ndc-collection")
+	if node == nil {
+		return nil
+	}
+
+	var extension ndcCollectionExtension
+	if err := node.Decode(&extension); err != nil {
+		return fmt.Errorf("x-ndc-collection: %w", err)
+	}
+
+	if extension.Filter != nil {
+		if err := extension.Filter.Validate(); err != nil {
+			return fmt.Errorf("x-ndc-collection: filter: %w", err)
+		}
+		function.Request.Filter = extension.Filter
+	}
+
+	if extension.OrderBy != nil {
+		if err := extension.OrderBy.Validate(); err != nil {
+			return fmt.Errorf("x-ndc-collection: orderBy: %w", err)
+		}
+		function.Request.OrderBy = extension.OrderBy
+	}
+
+	if extension.Pagination != nil {
+		if err := extension.Pagination.Validate(); err != nil {
+			return fmt.Errorf("x-ndc-collection: pagination: %w", err)
+		}
+		function.Request.Pagination = extension.Pagination
+	}
+
+	if !options.EmitCollections {
+		return nil
+	}
+
+	if _, err := ndcSchema.GetCollectionType(*function); err != nil {
+		options.Logger.Warn(fmt.Sprintf("%s: %s, converted to a function", funcName, err))
+
+		return nil
+	}
+
+	function.Collection = true
+
+	return nil
+}
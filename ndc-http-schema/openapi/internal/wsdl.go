@@ -0,0 +1,711 @@
+package internal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"slices"
+
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"github.com/hasura/ndc-http/ndc-http-schema/utils"
+	"github.com/hasura/ndc-sdk-go/schema"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
+)
+
+const (
+	wsdlStyleRPC = "rpc"
+	// the namespace prefix of body elements whose children aren't qualified
+	wsdlNamespacePrefix = "ns"
+)
+
+var xsdBuiltinScalars = map[string]rest.ScalarName{
+	"string":             rest.ScalarString,
+	"normalizedString":   rest.ScalarString,
+	"token":              rest.ScalarString,
+	"anyURI":             rest.ScalarString,
+	"QName":              rest.ScalarString,
+	"NMTOKEN":            rest.ScalarString,
+	"ID":                 rest.ScalarString,
+	"IDREF":              rest.ScalarString,
+	"language":           rest.ScalarString,
+	"time":               rest.ScalarString,
+	"duration":           rest.ScalarString,
+	"hexBinary":          rest.ScalarString,
+	"boolean":            rest.ScalarBoolean,
+	"byte":               rest.ScalarInt32,
+	"short":              rest.ScalarInt32,
+	"int":                rest.ScalarInt32,
+	"unsignedByte":       rest.ScalarInt32,
+	"unsignedShort":      rest.ScalarInt32,
+	"long":               rest.ScalarInt64,
+	"integer":            rest.ScalarInt64,
+	"unsignedInt":        rest.ScalarInt64,
+	"unsignedLong":       rest.ScalarInt64,
+	"positiveInteger":    rest.ScalarInt64,
+	"nonNegativeInteger": rest.ScalarInt64,
+	"negativeInteger":    rest.ScalarInt64,
+	"nonPositiveInteger": rest.ScalarInt64,
+	"float":              rest.ScalarFloat32,
+	"double":             rest.ScalarFloat64,
+	"decimal":            rest.ScalarBigDecimal,
+	"date":               rest.ScalarDate,
+	"dateTime":           rest.ScalarTimestampTZ,
+	"base64Binary":       rest.ScalarBytes,
+	"anyType":            rest.ScalarJSON,
+}
+
+// WSDLBuilder the NDC schema builder from WSDL 1.1 documents.
+// Operations of the SOAP binding are converted to procedures.
+type WSDLBuilder struct {
+	*ConvertOptions
+
+	schema       *rest.NDCHttpSchema
+	definitions  *wsdlDefinitions
+	elements     map[string]xsdElementDecl
+	complexTypes map[string]xsdComplexType
+	simpleTypes  map[string]xsdSimpleType
+}
+
+// NewWSDLBuilder creates a WSDLBuilder instance
+func NewWSDLBuilder(options ConvertOptions) *WSDLBuilder {
+	return &WSDLBuilder{
+		schema:         rest.NewNDCHttpSchema(),
+		ConvertOptions: applyConvertOptions(options),
+		elements:       make(map[string]xsdElementDecl),
+		complexTypes:   make(map[string]xsdComplexType),
+		simpleTypes:    make(map[string]xsdSimpleType),
+	}
+}
+
+// BuildSchema converts the WSDL document to NDC HTTP schema.
+func (wb *WSDLBuilder) BuildSchema(input []byte) (*rest.NDCHttpSchema, error) {
+	var definitions wsdlDefinitions
+	if err := xml.Unmarshal(input, &definitions); err != nil {
+		return nil, fmt.Errorf("failed to decode the WSDL document: %w", err)
+	}
+
+	if len(definitions.Imports) > 0 {
+		wb.Logger.Warn("imported WSDL documents aren't supported and ignored")
+	}
+
+	wb.definitions = &definitions
+	for _, xsd := range definitions.Schemas {
+		for _, element := range xsd.Elements {
+			wb.elements[element.Name] = xsdElementDecl{
+				xsdElement: element,
+				Namespace:  xsd.TargetNamespace,
+				Qualified:  xsd.ElementFormDefault == "qualified",
+			}
+		}
+
+		for _, complexType := range xsd.ComplexTypes {
+			wb.complexTypes[complexType.Name] = complexType
+		}
+
+		for _, simpleType := range xsd.SimpleTypes {
+			wb.simpleTypes[simpleType.Name] = simpleType
+		}
+	}
+
+	binding, address, version := wb.findSOAPBinding()
+	if binding == nil {
+		return nil, errNoSOAPBinding
+	}
+
+	envName := utils.StringSliceToConstantCase([]string{wb.EnvPrefix, "SERVER_URL"})
+	server := rest.ServerConfig{
+		URL: sdkUtils.NewEnvStringVariable(envName),
+	}
+	if address != "" {
+		server.URL = sdkUtils.NewEnvString(envName, address)
+	}
+	wb.schema.Settings.Servers = []rest.ServerConfig{server}
+
+	portType := wb.findPortType(binding.Type)
+	if portType == nil {
+		return nil, fmt.Errorf("portType %s of the binding %s not found", binding.Type, binding.Name)
+	}
+
+	for _, bindingOperation := range binding.Operations {
+		operation, err := wb.buildOperation(binding, &bindingOperation, portType, version)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", bindingOperation.Name, err)
+		}
+
+		if operation != nil {
+			wb.schema.Procedures[utils.ToCamelCase(bindingOperation.Name)] = *operation
+		}
+	}
+
+	if len(wb.schema.Procedures) == 0 {
+		return nil, errNoSOAPOperation
+	}
+
+	return NewNDCBuilder(wb.schema, *wb.ConvertOptions).Build()
+}
+
+// find the SOAP binding of service ports. SOAP 1.1 bindings are preferred.
+func (wb *WSDLBuilder) findSOAPBinding() (*wsdlBinding, string, rest.SOAPVersion) {
+	var soap12Binding *wsdlBinding
+	var soap12Address string
+
+	for _, service := range wb.definitions.Services {
+		for _, port := range service.Ports {
+			binding := wb.findBinding(port.Binding)
+			if binding == nil {
+				continue
+			}
+
+			if binding.SOAPBinding != nil && port.SOAPAddress != nil {
+				return binding, port.SOAPAddress.Location, rest.SOAPVersion11
+			}
+
+			if soap12Binding == nil && binding.SOAP12Binding != nil && port.SOAP12Address != nil {
+				soap12Binding = binding
+				soap12Address = port.SOAP12Address.Location
+			}
+		}
+	}
+
+	if soap12Binding != nil {
+		return soap12Binding, soap12Address, rest.SOAPVersion12
+	}
+
+	// the document may not define any service, e.g. if the address is configured by the environment variable
+	for i, binding := range wb.definitions.Bindings {
+		if binding.SOAPBinding != nil {
+			return &wb.definitions.Bindings[i], "", rest.SOAPVersion11
+		}
+
+		if binding.SOAP12Binding != nil {
+			return &wb.definitions.Bindings[i], "", rest.SOAPVersion12
+		}
+	}
+
+	return nil, "", ""
+}
+
+func (wb *WSDLBuilder) findBinding(name string) *wsdlBinding {
+	localName := xmlLocalName(name)
+	for i, binding := range wb.definitions.Bindings {
+		if binding.Name == localName {
+			return &wb.definitions.Bindings[i]
+		}
+	}
+
+	return nil
+}
+
+func (wb *WSDLBuilder) findPortType(name string) *wsdlPortType {
+	localName := xmlLocalName(name)
+	for i, portType := range wb.definitions.PortTypes {
+		if portType.Name == localName {
+			return &wb.definitions.PortTypes[i]
+		}
+	}
+
+	return nil
+}
+
+func (wb *WSDLBuilder) findMessage(name string) *wsdlMessage {
+	localName := xmlLocalName(name)
+	for i, message := range wb.definitions.Messages {
+		if message.Name == localName {
+			return &wb.definitions.Messages[i]
+		}
+	}
+
+	return nil
+}
+
+func (wb *WSDLBuilder) buildOperation(binding *wsdlBinding, bindingOperation *wsdlBindingOperation, portType *wsdlPortType, version rest.SOAPVersion) (*rest.OperationInfo, error) {
+	index := slices.IndexFunc(portType.Operations, func(op wsdlPortTypeOperation) bool {
+		return op.Name == bindingOperation.Name
+	})
+	if index < 0 {
+		return nil, fmt.Errorf("operation not found in the portType %s", portType.Name)
+	}
+
+	operation := portType.Operations[index]
+	if operation.Input == nil || operation.Output == nil {
+		// one-way and notification operations don't have any response to be returned
+		wb.Logger.Warn(fmt.Sprintf("%s: skipped the operation without input or output", operation.Name))
+
+		return nil, nil
+	}
+
+	soapRequest := &rest.SOAPRequest{
+		Version: version,
+	}
+	style := ""
+	if binding.SOAPBinding != nil {
+		style = binding.SOAPBinding.Style
+	} else if binding.SOAP12Binding != nil {
+		style = binding.SOAP12Binding.Style
+	}
+
+	for _, soapOperation := range []*wsdlSOAPOperation{bindingOperation.SOAPOperation, bindingOperation.SOAP12Operation} {
+		if soapOperation == nil {
+			continue
+		}
+
+		soapRequest.Action = soapOperation.SOAPAction
+		if soapOperation.Style != "" {
+			style = soapOperation.Style
+		}
+
+		break
+	}
+
+	// the namespace of wrapper elements of rpc operations
+	namespace := wb.definitions.TargetNamespace
+	if bindingOperation.Input != nil {
+		for _, body := range []*wsdlSOAPBody{bindingOperation.Input.SOAPBody, bindingOperation.Input.SOAP12Body} {
+			if body != nil && body.Namespace != "" {
+				namespace = body.Namespace
+			}
+		}
+	}
+
+	inputType, err := wb.convertMessage(operation.Input.Message, operation.Name, utils.ToPascalCase(operation.Name)+"Request", style, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("input: %w", err)
+	}
+
+	outputType, err := wb.convertMessage(operation.Output.Message, operation.Name+"Response", utils.ToPascalCase(operation.Name)+"Response", style, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("output: %w", err)
+	}
+
+	contentType := soapRequest.ContentType()
+	result := &rest.OperationInfo{
+		Request: &rest.Request{
+			URL:    "/",
+			Method: "post",
+			RequestBody: &rest.RequestBody{
+				ContentType: contentType,
+			},
+			Response: rest.Response{
+				ContentType: contentType,
+			},
+			SOAP: soapRequest,
+		},
+		Arguments:  map[string]rest.ArgumentInfo{},
+		ResultType: schema.NewNullableType(schema.NewNamedType(outputType)).Encode(),
+	}
+
+	description := operation.Documentation
+	if description == "" {
+		description = fmt.Sprintf("Call the %s SOAP operation", operation.Name)
+	}
+	result.Description = &description
+
+	if inputType != "" {
+		bodyDescription := "Request message of " + operation.Name
+		result.Arguments[rest.BodyKey] = rest.ArgumentInfo{
+			ArgumentInfo: schema.ArgumentInfo{
+				Description: &bodyDescription,
+				Type:        schema.NewNamedType(inputType).Encode(),
+			},
+			HTTP: &rest.RequestParameter{
+				In: rest.InBody,
+			},
+		}
+	}
+
+	if outputType == "" {
+		result.ResultType = schema.NewNullableType(schema.NewNamedType(string(rest.ScalarJSON))).Encode()
+		wb.schema.AddScalar(string(rest.ScalarJSON), *defaultScalarTypes[rest.ScalarJSON])
+	}
+
+	return result, nil
+}
+
+// convert the message to the object type of the body element.
+// Messages of document operations refer to a top-level element.
+// Parts of rpc operations are wrapped in the element of the operation name.
+func (wb *WSDLBuilder) convertMessage(messageName string, elementName string, typeName string, style string, namespace string) (string, error) {
+	message := wb.findMessage(messageName)
+	if message == nil {
+		return "", fmt.Errorf("message %s not found", messageName)
+	}
+
+	if len(message.Parts) == 0 {
+		return "", nil
+	}
+
+	if style != wsdlStyleRPC {
+		if len(message.Parts) > 1 || message.Parts[0].Element == "" {
+			return "", fmt.Errorf("message %s: document operations require a single part which refers to an element", message.Name)
+		}
+
+		return wb.convertRootElement(message.Parts[0].Element)
+	}
+
+	objectType := rest.ObjectType{
+		Fields: make(map[string]rest.ObjectField),
+		XML: &rest.XMLSchema{
+			Name:      elementName,
+			Prefix:    wsdlNamespacePrefix,
+			Namespace: namespace,
+		},
+	}
+
+	for _, part := range message.Parts {
+		element := xsdElement{
+			Name: part.Name,
+			Type: part.Type,
+		}
+		if part.Element != "" {
+			element = xsdElement{
+				Name: xmlLocalName(part.Element),
+				Ref:  part.Element,
+			}
+		}
+
+		fieldName, field, err := wb.convertElementField(typeName, element, false)
+		if err != nil {
+			return "", fmt.Errorf("message %s: %w", message.Name, err)
+		}
+
+		objectType.Fields[fieldName] = field
+	}
+
+	wb.schema.ObjectTypes[typeName] = objectType
+
+	return typeName, nil
+}
+
+// convert the top-level element to an object type which is encoded and decoded as the root element of the SOAP body.
+func (wb *WSDLBuilder) convertRootElement(qname string) (string, error) {
+	decl, ok := wb.elements[xmlLocalName(qname)]
+	if !ok {
+		return "", fmt.Errorf("element %s not found", qname)
+	}
+
+	xmlSchema := &rest.XMLSchema{
+		Name:      decl.Name,
+		Namespace: decl.Namespace,
+	}
+	if !decl.Qualified {
+		xmlSchema.Prefix = wsdlNamespacePrefix
+	}
+
+	typeName := utils.ToPascalCase(decl.Name)
+	if _, ok := wb.complexTypes[typeName]; ok {
+		// avoid conflicts with the named complex type
+		typeName += "Element"
+	}
+
+	if objectType, ok := wb.schema.ObjectTypes[typeName]; ok && objectType.XML != nil {
+		return typeName, nil
+	}
+
+	complexType := decl.ComplexType
+	if complexType == nil && decl.Type != "" {
+		if ct, ok := wb.complexTypes[xmlLocalName(decl.Type)]; ok {
+			complexType = &ct
+		}
+	}
+
+	objectType := rest.ObjectType{
+		Fields: make(map[string]rest.ObjectField),
+		XML:    xmlSchema,
+	}
+	if decl.Documentation != "" {
+		objectType.Description = &decl.Documentation
+	}
+	wb.schema.ObjectTypes[typeName] = objectType
+
+	if complexType == nil {
+		// the element has a simple type, e.g. <element name="Result" type="xs:string"/>
+		objectType.Fields[xmlValueFieldName] = xmlValueField
+
+		return typeName, nil
+	}
+
+	if err := wb.convertComplexTypeFields(typeName, complexType, objectType.Fields); err != nil {
+		return "", err
+	}
+
+	return typeName, nil
+}
+
+func (wb *WSDLBuilder) convertComplexType(typeName string, complexType *xsdComplexType) (string, error) {
+	if _, ok := wb.schema.ObjectTypes[typeName]; ok {
+		return typeName, nil
+	}
+
+	objectType := rest.ObjectType{
+		Fields: make(map[string]rest.ObjectField),
+	}
+	if complexType.Documentation != "" {
+		objectType.Description = &complexType.Documentation
+	}
+
+	// register the object type before evaluating fields to avoid infinite recursion of self-reference types
+	wb.schema.ObjectTypes[typeName] = objectType
+
+	if err := wb.convertComplexTypeFields(typeName, complexType, objectType.Fields); err != nil {
+		return "", err
+	}
+
+	return typeName, nil
+}
+
+func (wb *WSDLBuilder) convertComplexTypeFields(typeName string, complexType *xsdComplexType, fields map[string]rest.ObjectField) error {
+	if complexType.SimpleContent != nil {
+		fields[xmlValueFieldName] = xmlValueField
+		for _, content := range []*xsdExtension{complexType.SimpleContent.Extension, complexType.SimpleContent.Restriction} {
+			if content != nil {
+				if err := wb.convertAttributes(typeName, content.Attributes, fields); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	if complexType.ComplexContent != nil {
+		for _, content := range []*xsdExtension{complexType.ComplexContent.Extension, complexType.ComplexContent.Restriction} {
+			if content == nil {
+				continue
+			}
+
+			// fields of the base type are inherited by extensions
+			if baseType, ok := wb.complexTypes[xmlLocalName(content.Base)]; ok && content == complexType.ComplexContent.Extension {
+				if err := wb.convertComplexTypeFields(typeName, &baseType, fields); err != nil {
+					return err
+				}
+			}
+
+			if err := wb.convertGroups(typeName, content.Sequence, content.All, content.Choice, fields); err != nil {
+				return err
+			}
+
+			if err := wb.convertAttributes(typeName, content.Attributes, fields); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := wb.convertGroups(typeName, complexType.Sequence, complexType.All, complexType.Choice, fields); err != nil {
+		return err
+	}
+
+	return wb.convertAttributes(typeName, complexType.Attributes, fields)
+}
+
+func (wb *WSDLBuilder) convertGroups(typeName string, sequence *xsdGroup, all *xsdGroup, choice *xsdGroup, fields map[string]rest.ObjectField) error {
+	for _, group := range []*xsdGroup{sequence, all} {
+		if group != nil {
+			if err := wb.convertGroup(typeName, group, false, fields); err != nil {
+				return err
+			}
+		}
+	}
+
+	if choice != nil {
+		return wb.convertGroup(typeName, choice, true, fields)
+	}
+
+	return nil
+}
+
+// convert elements of the model group to fields. Elements of choices are optional.
+func (wb *WSDLBuilder) convertGroup(typeName string, group *xsdGroup, optional bool, fields map[string]rest.ObjectField) error {
+	for _, element := range group.Elements {
+		fieldName, field, err := wb.convertElementField(typeName, element, optional)
+		if err != nil {
+			return fmt.Errorf("%s: %w", typeName, err)
+		}
+
+		fields[fieldName] = field
+	}
+
+	for _, sequence := range group.Sequences {
+		if err := wb.convertGroup(typeName, &sequence, optional, fields); err != nil {
+			return err
+		}
+	}
+
+	for _, choice := range group.Choices {
+		if err := wb.convertGroup(typeName, &choice, true, fields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (wb *WSDLBuilder) convertAttributes(typeName string, attributes []xsdAttribute, fields map[string]rest.ObjectField) error {
+	for _, attribute := range attributes {
+		if attribute.Name == "" {
+			continue
+		}
+
+		var scalarName string
+		var err error
+		if attribute.SimpleType != nil {
+			scalarName, err = wb.convertSimpleType(typeName+utils.ToPascalCase(attribute.Name), attribute.SimpleType)
+		} else {
+			scalarName, err = wb.convertTypeName(typeName+utils.ToPascalCase(attribute.Name), attribute.Type)
+		}
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", typeName, attribute.Name, err)
+		}
+
+		var fieldType schema.TypeEncoder = schema.NewNamedType(scalarName)
+		if attribute.Use != "required" {
+			fieldType = schema.NewNullableType(fieldType)
+		}
+
+		fields[attribute.Name] = rest.ObjectField{
+			ObjectField: schema.ObjectField{
+				Type: fieldType.Encode(),
+			},
+			HTTP: &rest.TypeSchema{
+				Type: []string{wb.getJSONType(scalarName)},
+				XML: &rest.XMLSchema{
+					Attribute: true,
+				},
+			},
+		}
+	}
+
+	return nil
+}
+
+func (wb *WSDLBuilder) convertElementField(typeName string, element xsdElement, optional bool) (string, rest.ObjectField, error) {
+	if element.Ref != "" {
+		decl, ok := wb.elements[xmlLocalName(element.Ref)]
+		if !ok {
+			return "", rest.ObjectField{}, fmt.Errorf("element %s not found", element.Ref)
+		}
+
+		refElement := decl.xsdElement
+		refElement.MinOccurs = element.MinOccurs
+		refElement.MaxOccurs = element.MaxOccurs
+		element = refElement
+	}
+
+	if element.Name == "" {
+		return "", rest.ObjectField{}, errElementNameRequired
+	}
+
+	fieldTypeName := typeName + utils.ToPascalCase(element.Name)
+	var namedType string
+	var err error
+	switch {
+	case element.ComplexType != nil:
+		namedType, err = wb.convertComplexType(fieldTypeName, element.ComplexType)
+	case element.SimpleType != nil:
+		namedType, err = wb.convertSimpleType(fieldTypeName, element.SimpleType)
+	default:
+		namedType, err = wb.convertTypeName(fieldTypeName, element.Type)
+	}
+	if err != nil {
+		return "", rest.ObjectField{}, fmt.Errorf("%s: %w", element.Name, err)
+	}
+
+	var fieldType schema.TypeEncoder = schema.NewNamedType(namedType)
+	typeSchema := &rest.TypeSchema{
+		Type: []string{wb.getJSONType(namedType)},
+	}
+	if element.IsArray() {
+		fieldType = schema.NewArrayType(fieldType)
+		typeSchema = &rest.TypeSchema{
+			Type:  []string{"array"},
+			Items: typeSchema,
+		}
+	}
+
+	if optional || element.IsOptional() {
+		fieldType = schema.NewNullableType(fieldType)
+	}
+
+	field := rest.ObjectField{
+		ObjectField: schema.ObjectField{
+			Type: fieldType.Encode(),
+		},
+		HTTP: typeSchema,
+	}
+	if element.Documentation != "" {
+		field.Description = &element.Documentation
+	}
+
+	return element.Name, field, nil
+}
+
+// convert the type reference to the name of a scalar or object type.
+// The type name of the parent path is used for anonymous types.
+func (wb *WSDLBuilder) convertTypeName(typeName string, qname string) (string, error) {
+	localName := xmlLocalName(qname)
+	if qname == "" {
+		localName = "anyType"
+	}
+
+	if complexType, ok := wb.complexTypes[localName]; ok {
+		return wb.convertComplexType(utils.ToPascalCase(localName), &complexType)
+	}
+
+	if simpleType, ok := wb.simpleTypes[localName]; ok {
+		return wb.convertSimpleType(utils.ToPascalCase(localName), &simpleType)
+	}
+
+	scalarName, ok := xsdBuiltinScalars[localName]
+	if !ok {
+		wb.Logger.Warn(fmt.Sprintf("%s: unsupported type %s, fallback to JSON", typeName, qname))
+		scalarName = rest.ScalarJSON
+	}
+
+	wb.schema.AddScalar(string(scalarName), *defaultScalarTypes[scalarName])
+
+	return string(scalarName), nil
+}
+
+// convert the simple type to a scalar. Restrictions with enumerations are converted to enum scalars.
+func (wb *WSDLBuilder) convertSimpleType(typeName string, simpleType *xsdSimpleType) (string, error) {
+	if simpleType.Restriction == nil {
+		wb.schema.AddScalar(string(rest.ScalarString), *defaultScalarTypes[rest.ScalarString])
+
+		return string(rest.ScalarString), nil
+	}
+
+	if len(simpleType.Restriction.Enumerations) == 0 {
+		return wb.convertTypeName(typeName, simpleType.Restriction.Base)
+	}
+
+	if _, ok := wb.schema.ScalarTypes[typeName]; ok {
+		return typeName, nil
+	}
+
+	enumValues := make([]string, len(simpleType.Restriction.Enumerations))
+	for i, enum := range simpleType.Restriction.Enumerations {
+		enumValues[i] = enum.Value
+	}
+
+	scalarType := schema.NewScalarType()
+	scalarType.Representation = schema.NewTypeRepresentationEnum(enumValues).Encode()
+	wb.schema.AddScalar(typeName, *scalarType)
+
+	return typeName, nil
+}
+
+// get the JSON schema type of the scalar or object type
+func (wb *WSDLBuilder) getJSONType(typeName string) string {
+	if _, ok := wb.schema.ObjectTypes[typeName]; ok {
+		return "object"
+	}
+
+	switch rest.ScalarName(typeName) {
+	case rest.ScalarBoolean:
+		return "boolean"
+	case rest.ScalarInt32, rest.ScalarInt64:
+		return "integer"
+	case rest.ScalarFloat32, rest.ScalarFloat64:
+		return "number"
+	case rest.ScalarJSON:
+		return "object"
+	default:
+		return "string"
+	}
+}
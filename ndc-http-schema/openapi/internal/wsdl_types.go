@@ -0,0 +1,210 @@
+package internal
+
+import (
+	"encoding/xml"
+	"strconv"
+	"strings"
+)
+
+// wsdlDefinitions represents the root element of WSDL 1.1 documents.
+type wsdlDefinitions struct {
+	XMLName         xml.Name         `xml:"definitions"`
+	TargetNamespace string           `xml:"targetNamespace,attr"`
+	Schemas         []xsdSchema      `xml:"types>schema"`
+	Messages        []wsdlMessage    `xml:"message"`
+	PortTypes       []wsdlPortType   `xml:"portType"`
+	Bindings        []wsdlBinding    `xml:"binding"`
+	Services        []wsdlService    `xml:"service"`
+	Imports         []wsdlImportNode `xml:"import"`
+}
+
+type wsdlImportNode struct {
+	Location string `xml:"location,attr"`
+}
+
+type wsdlMessage struct {
+	Name  string     `xml:"name,attr"`
+	Parts []wsdlPart `xml:"part"`
+}
+
+type wsdlPart struct {
+	Name    string `xml:"name,attr"`
+	Element string `xml:"element,attr"`
+	Type    string `xml:"type,attr"`
+}
+
+type wsdlPortType struct {
+	Name       string                  `xml:"name,attr"`
+	Operations []wsdlPortTypeOperation `xml:"operation"`
+}
+
+type wsdlPortTypeOperation struct {
+	Name          string           `xml:"name,attr"`
+	Documentation string           `xml:"documentation"`
+	Input         *wsdlMessageNode `xml:"input"`
+	Output        *wsdlMessageNode `xml:"output"`
+}
+
+type wsdlMessageNode struct {
+	Message string `xml:"message,attr"`
+}
+
+type wsdlBinding struct {
+	Name          string                 `xml:"name,attr"`
+	Type          string                 `xml:"type,attr"`
+	SOAPBinding   *wsdlSOAPBinding       `xml:"http://schemas.xmlsoap.org/wsdl/soap/ binding"`
+	SOAP12Binding *wsdlSOAPBinding       `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ binding"`
+	Operations    []wsdlBindingOperation `xml:"operation"`
+}
+
+type wsdlSOAPBinding struct {
+	Style string `xml:"style,attr"`
+}
+
+type wsdlBindingOperation struct {
+	Name            string               `xml:"name,attr"`
+	SOAPOperation   *wsdlSOAPOperation   `xml:"http://schemas.xmlsoap.org/wsdl/soap/ operation"`
+	SOAP12Operation *wsdlSOAPOperation   `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ operation"`
+	Input           *wsdlBindingIOPacket `xml:"input"`
+}
+
+type wsdlSOAPOperation struct {
+	SOAPAction string `xml:"soapAction,attr"`
+	Style      string `xml:"style,attr"`
+}
+
+type wsdlBindingIOPacket struct {
+	SOAPBody   *wsdlSOAPBody `xml:"http://schemas.xmlsoap.org/wsdl/soap/ body"`
+	SOAP12Body *wsdlSOAPBody `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ body"`
+}
+
+type wsdlSOAPBody struct {
+	Use       string `xml:"use,attr"`
+	Namespace string `xml:"namespace,attr"`
+}
+
+type wsdlService struct {
+	Name  string     `xml:"name,attr"`
+	Ports []wsdlPort `xml:"port"`
+}
+
+type wsdlPort struct {
+	Name          string           `xml:"name,attr"`
+	Binding       string           `xml:"binding,attr"`
+	SOAPAddress   *wsdlSOAPAddress `xml:"http://schemas.xmlsoap.org/wsdl/soap/ address"`
+	SOAP12Address *wsdlSOAPAddress `xml:"http://schemas.xmlsoap.org/wsdl/soap12/ address"`
+}
+
+type wsdlSOAPAddress struct {
+	Location string `xml:"location,attr"`
+}
+
+// xsdSchema represents a subset of XML schema definitions which are embedded in the types of WSDL documents.
+type xsdSchema struct {
+	TargetNamespace    string           `xml:"targetNamespace,attr"`
+	ElementFormDefault string           `xml:"elementFormDefault,attr"`
+	Elements           []xsdElement     `xml:"element"`
+	ComplexTypes       []xsdComplexType `xml:"complexType"`
+	SimpleTypes        []xsdSimpleType  `xml:"simpleType"`
+}
+
+type xsdElement struct {
+	Name          string          `xml:"name,attr"`
+	Type          string          `xml:"type,attr"`
+	Ref           string          `xml:"ref,attr"`
+	MinOccurs     string          `xml:"minOccurs,attr"`
+	MaxOccurs     string          `xml:"maxOccurs,attr"`
+	Nillable      bool            `xml:"nillable,attr"`
+	Documentation string          `xml:"annotation>documentation"`
+	ComplexType   *xsdComplexType `xml:"complexType"`
+	SimpleType    *xsdSimpleType  `xml:"simpleType"`
+}
+
+// IsOptional checks if the element can be omitted.
+func (xe xsdElement) IsOptional() bool {
+	return xe.MinOccurs == "0" || xe.Nillable
+}
+
+// IsArray checks if the element can occur many times.
+func (xe xsdElement) IsArray() bool {
+	if xe.MaxOccurs == "" {
+		return false
+	}
+
+	if xe.MaxOccurs == "unbounded" {
+		return true
+	}
+
+	maxOccurs, err := strconv.Atoi(xe.MaxOccurs)
+
+	return err == nil && maxOccurs > 1
+}
+
+type xsdComplexType struct {
+	Name           string          `xml:"name,attr"`
+	Documentation  string          `xml:"annotation>documentation"`
+	Sequence       *xsdGroup       `xml:"sequence"`
+	All            *xsdGroup       `xml:"all"`
+	Choice         *xsdGroup       `xml:"choice"`
+	Attributes     []xsdAttribute  `xml:"attribute"`
+	ComplexContent *xsdContentNode `xml:"complexContent"`
+	SimpleContent  *xsdContentNode `xml:"simpleContent"`
+}
+
+type xsdGroup struct {
+	Elements  []xsdElement `xml:"element"`
+	Sequences []xsdGroup   `xml:"sequence"`
+	Choices   []xsdGroup   `xml:"choice"`
+}
+
+type xsdContentNode struct {
+	Extension   *xsdExtension `xml:"extension"`
+	Restriction *xsdExtension `xml:"restriction"`
+}
+
+type xsdExtension struct {
+	Base       string         `xml:"base,attr"`
+	Sequence   *xsdGroup      `xml:"sequence"`
+	All        *xsdGroup      `xml:"all"`
+	Choice     *xsdGroup      `xml:"choice"`
+	Attributes []xsdAttribute `xml:"attribute"`
+}
+
+type xsdAttribute struct {
+	Name       string         `xml:"name,attr"`
+	Type       string         `xml:"type,attr"`
+	Use        string         `xml:"use,attr"`
+	SimpleType *xsdSimpleType `xml:"simpleType"`
+}
+
+type xsdSimpleType struct {
+	Name          string          `xml:"name,attr"`
+	Documentation string          `xml:"annotation>documentation"`
+	Restriction   *xsdRestriction `xml:"restriction"`
+}
+
+type xsdRestriction struct {
+	Base         string              `xml:"base,attr"`
+	Enumerations []xsdEnumerationRef `xml:"enumeration"`
+}
+
+type xsdEnumerationRef struct {
+	Value string `xml:"value,attr"`
+}
+
+// xsdElementDecl is a top-level element with the namespace of the schema.
+type xsdElementDecl struct {
+	xsdElement
+
+	Namespace string
+	Qualified bool
+}
+
+// get the local name of a qualified name, e.g. tns:GetPrice
+func xmlLocalName(qname string) string {
+	if i := strings.LastIndex(qname, ":"); i >= 0 {
+		return qname[i+1:]
+	}
+
+	return qname
+}
@@ -11,6 +11,20 @@ import (
 
 type ConvertOptions internal.ConvertOptions
 
+// OperationFilter represents filters of OpenAPI operations to be converted.
+type OperationFilter = internal.OperationFilter
+
+// NamingStrategy represents settings to customize names of operations and types.
+type NamingStrategy = internal.NamingStrategy
+
+// NamingCase represents the casing of generated names.
+type NamingCase = internal.NamingCase
+
+const (
+	NamingCaseCamel = internal.NamingCaseCamel
+	NamingCaseSnake = internal.NamingCaseSnake
+)
+
 // OpenAPIv3ToNDCSchema converts OpenAPI v3 JSON bytes to NDC HTTP schema
 func OpenAPIv3ToNDCSchema(input []byte, options ConvertOptions) (*rest.NDCHttpSchema, []error) {
 	input = []byte(utils.RemoveYAMLSpecialCharacters(input))
@@ -25,7 +39,8 @@ func OpenAPIv3ToNDCSchema(input []byte, options ConvertOptions) (*rest.NDCHttpSc
 		return nil, errs
 	}
 
-	if docModel.Model.Paths == nil || docModel.Model.Paths.PathItems == nil || docModel.Model.Paths.PathItems.IsZero() {
+	if (docModel.Model.Paths == nil || docModel.Model.Paths.PathItems == nil || docModel.Model.Paths.PathItems.IsZero()) &&
+		(docModel.Model.Webhooks == nil || docModel.Model.Webhooks.IsZero()) {
 		return nil, append(errs, errors.New("there is no API to be converted"))
 	}
 
@@ -4,9 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"os"
+	"strings"
 	"testing"
 
 	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	sdkSchema "github.com/hasura/ndc-sdk-go/schema"
+	sdkUtils "github.com/hasura/ndc-sdk-go/utils"
 	"gotest.tools/v3/assert"
 )
 
@@ -81,6 +84,35 @@ func TestOpenAPIv3ToRESTSchema(t *testing.T) {
 			Schema:   "testdata/union3/schema.json",
 			Options:  ConvertOptions{},
 		},
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/links3/source.json -o ./ndc-http-schema/openapi/testdata/links3/expected.json --spec openapi3 --enrich-links
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/links3/source.json -o ./ndc-http-schema/openapi/testdata/links3/schema.json --pure --spec openapi3 --enrich-links
+		{
+			Name:     "links",
+			Source:   "testdata/links3/source.json",
+			Expected: "testdata/links3/expected.json",
+			Schema:   "testdata/links3/schema.json",
+			Options: ConvertOptions{
+				EnrichLinks: true,
+			},
+		},
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/webhooks3/source.json -o ./ndc-http-schema/openapi/testdata/webhooks3/expected.json --spec openapi3
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/webhooks3/source.json -o ./ndc-http-schema/openapi/testdata/webhooks3/schema.json --pure --spec openapi3
+		{
+			Name:     "webhooks",
+			Source:   "testdata/webhooks3/source.json",
+			Expected: "testdata/webhooks3/expected.json",
+			Schema:   "testdata/webhooks3/schema.json",
+			Options:  ConvertOptions{},
+		},
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/envelope3/source.json -o ./ndc-http-schema/openapi/testdata/envelope3/expected.json --spec openapi3
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/envelope3/source.json -o ./ndc-http-schema/openapi/testdata/envelope3/schema.json --pure --spec openapi3
+		{
+			Name:     "envelope",
+			Source:   "testdata/envelope3/source.json",
+			Expected: "testdata/envelope3/expected.json",
+			Schema:   "testdata/envelope3/schema.json",
+			Options:  ConvertOptions{},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -103,6 +135,361 @@ func TestOpenAPIv3ToRESTSchema(t *testing.T) {
 		})
 	}
 
+	t.Run("collections", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/collection3/source.json")
+		assert.NilError(t, err)
+
+		output, errs := OpenAPIv3ToNDCSchema(sourceBytes, ConvertOptions{EmitCollections: true})
+		assert.NilError(t, errors.Join(errs...))
+
+		listBooks := output.Functions["listBooks"]
+		assert.Assert(t, listBooks.Collection)
+		assert.Equal(t, "data", listBooks.Request.Pagination.ItemsField)
+		assert.Equal(t, schema.FilterOperator, listBooks.Request.Filter.Style)
+		assert.DeepEqual(t, []string{"_eq", "_gt"}, listBooks.Request.Filter.Fields["data.year"].Operators)
+		assert.Assert(t, output.Functions["listAuthors"].Collection)
+		// the result of getStats isn't an array of objects
+		assert.Assert(t, !output.Functions["getStats"].Collection)
+
+		output, errs = OpenAPIv3ToNDCSchema(sourceBytes, ConvertOptions{})
+		assert.NilError(t, errors.Join(errs...))
+		assert.Assert(t, !output.Functions["listBooks"].Collection)
+		assert.Equal(t, "data", output.Functions["listBooks"].Request.Pagination.ItemsField)
+	})
+
+	t.Run("response_examples", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/petstore3/source.json")
+		assert.NilError(t, err)
+
+		output, errs := OpenAPIv3ToNDCSchema(sourceBytes, ConvertOptions{ResponseExamples: true})
+		assert.NilError(t, errors.Join(errs...))
+
+		// the example is built from examples of nested properties
+		pet := map[string]any{
+			"id":       int(10),
+			"name":     "doggie",
+			"category": map[string]any{"id": int(1), "name": "Dogs"},
+		}
+		assert.DeepEqual(t, pet, output.Functions["getPetById"].Request.Response.Example)
+		assert.DeepEqual(t, []any{pet}, output.Functions["findPetsByStatus"].Request.Response.Example)
+		assert.Assert(t, output.Functions["getInventory"].Request.Response.Example == nil)
+
+		output, errs = OpenAPIv3ToNDCSchema(sourceBytes, ConvertOptions{})
+		assert.NilError(t, errors.Join(errs...))
+		assert.Assert(t, output.Functions["getPetById"].Request.Response.Example == nil)
+	})
+
+	t.Run("default_values", func(t *testing.T) {
+		sourceBytes := []byte(`{
+			"openapi": "3.0.3",
+			"info": { "title": "Library API", "version": "1.0.0" },
+			"paths": {
+				"/books": {
+					"post": {
+						"operationId": "createBook",
+						"parameters": [
+							{ "name": "lang", "in": "query", "schema": { "type": "string", "default": "en" } }
+						],
+						"requestBody": {
+							"content": {
+								"application/json": {
+									"schema": {
+										"type": "object",
+										"properties": {
+											"title": { "type": "string" },
+											"tags": { "type": "array", "items": { "type": "string" }, "default": ["new"] },
+											"pages": { "type": "integer", "default": 100 }
+										}
+									}
+								}
+							}
+						},
+						"responses": { "200": { "description": "OK" } }
+					}
+				}
+			}
+		}`)
+
+		output, errs := OpenAPIv3ToNDCSchema(sourceBytes, ConvertOptions{})
+		assert.NilError(t, errors.Join(errs...))
+		assert.Equal(t, "en", output.Procedures["createBook"].Arguments["lang"].HTTP.Schema.Default)
+		bodyFields := output.ObjectTypes["CreateBookBody"].Fields
+		assert.DeepEqual(t, []any{"new"}, bodyFields["tags"].HTTP.Default)
+		assert.Equal(t, 100, bodyFields["pages"].HTTP.Default)
+		assert.Assert(t, bodyFields["title"].HTTP.Default == nil)
+
+		output, errs = OpenAPIv3ToNDCSchema(sourceBytes, ConvertOptions{NoDefaultValues: true})
+		assert.NilError(t, errors.Join(errs...))
+		assert.Assert(t, output.Procedures["createBook"].Arguments["lang"].HTTP.Schema.Default == nil)
+		assert.Assert(t, output.ObjectTypes["CreateBookBody"].Fields["pages"].HTTP.Default == nil)
+	})
+
+	t.Run("typed_maps", func(t *testing.T) {
+		sourceBytes := []byte(`{
+			"openapi": "3.0.3",
+			"info": { "title": "Counter API", "version": "1.0.0" },
+			"paths": {
+				"/counters": {
+					"post": {
+						"operationId": "updateCounters",
+						"requestBody": {
+							"content": {
+								"application/json": {
+									"schema": {
+										"type": "object",
+										"properties": {
+											"counters": { "type": "object", "additionalProperties": { "type": "integer" } },
+											"labels": { "type": "object", "additionalProperties": { "type": "string" } },
+											"extra": { "type": "object", "additionalProperties": { "type": "object" } }
+										}
+									}
+								}
+							}
+						},
+						"responses": { "200": { "description": "OK" } }
+					}
+				}
+			}
+		}`)
+
+		output, errs := OpenAPIv3ToNDCSchema(sourceBytes, ConvertOptions{})
+		assert.NilError(t, errors.Join(errs...))
+		bodyFields := output.ObjectTypes["UpdateCountersBody"].Fields
+		assert.DeepEqual(t, sdkSchema.NewNullableNamedType("Int32Map").Encode(), bodyFields["counters"].Type)
+		assert.DeepEqual(t, []string{"integer"}, bodyFields["counters"].HTTP.AdditionalProperties.Type)
+		assert.DeepEqual(t, sdkSchema.NewNullableNamedType("StringMap").Encode(), bodyFields["labels"].Type)
+		assert.DeepEqual(t, sdkSchema.NewNullableNamedType("JSON").Encode(), bodyFields["extra"].Type)
+		assert.Assert(t, bodyFields["extra"].HTTP.AdditionalProperties == nil)
+		_, ok := output.ScalarTypes["Int32Map"]
+		assert.Assert(t, ok)
+	})
+
+	t.Run("operation_filter", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/petstore3/source.json")
+		assert.NilError(t, err)
+
+		output, errs := OpenAPIv3ToNDCSchema(sourceBytes, ConvertOptions{
+			Filter: &OperationFilter{
+				IncludeTags:  []string{"pet", "store"},
+				ExcludePaths: []string{"/pet/{petId}"},
+				Methods:      []string{"get", "POST"},
+			},
+		})
+		assert.NilError(t, errors.Join(errs...))
+		assert.DeepEqual(t, []string{"findPetsByStatus", "findPetsByTags", "getInventory", "getOrderById"}, sdkUtils.GetSortedKeys(output.Functions))
+		assert.DeepEqual(t, []string{"addPet", "placeOrder"}, sdkUtils.GetSortedKeys(output.Procedures))
+		_, ok := output.ObjectTypes["User"]
+		assert.Assert(t, !ok, "unused object types should be removed")
+
+		output, errs = OpenAPIv3ToNDCSchema(sourceBytes, ConvertOptions{
+			Filter: &OperationFilter{
+				OperationIDs: []string{"getPetById", "loginUser"},
+				ExcludeTags:  []string{"user"},
+			},
+		})
+		assert.NilError(t, errors.Join(errs...))
+		assert.DeepEqual(t, []string{"getPetById"}, sdkUtils.GetSortedKeys(output.Functions))
+		assert.Equal(t, 0, len(output.Procedures))
+
+		assert.ErrorContains(t, OperationFilter{ExcludePaths: []string{"/pet/["}}.Validate(), "invalid path pattern /pet/[")
+	})
+
+	t.Run("naming_strategy", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/petstore3/source.json")
+		assert.NilError(t, err)
+
+		options := ConvertOptions{
+			Filter: &OperationFilter{IncludeTags: []string{"pet"}, Methods: []string{"get"}},
+			Naming: &NamingStrategy{
+				OperationTemplate: "{method}_{tag}_{operationId}",
+				OperationCase:     NamingCaseSnake,
+				TypePrefix:        "PetStore",
+			},
+		}
+		output, errs := OpenAPIv3ToNDCSchema(sourceBytes, options)
+		assert.NilError(t, errors.Join(errs...))
+		assert.DeepEqual(t, []string{"get_pet_find_pets_by_status", "get_pet_find_pets_by_tags", "get_pet_get_pet_by_id"}, sdkUtils.GetSortedKeys(output.Functions))
+		assert.DeepEqual(t, sdkSchema.NewNamedType("PetStorePet").Encode(), output.Functions["get_pet_get_pet_by_id"].ResultType)
+		_, ok := output.ObjectTypes["Pet"]
+		assert.Assert(t, !ok)
+
+		options.Naming = &NamingStrategy{OperationTemplate: "{path}_{method}", OperationCase: NamingCaseCamel}
+		output, errs = OpenAPIv3ToNDCSchema(sourceBytes, options)
+		assert.NilError(t, errors.Join(errs...))
+		assert.DeepEqual(t, []string{"petFindByStatusGet", "petFindByTagsGet", "petPetIdGet"}, sdkUtils.GetSortedKeys(output.Functions))
+
+		assert.ErrorContains(t, NamingStrategy{OperationTemplate: "{verb}_{operationId}"}.Validate(), "invalid variable {verb} of operationTemplate")
+		assert.ErrorContains(t, NamingStrategy{OperationCase: "kebab"}.Validate(), "invalid operationCase kebab")
+	})
+
+	t.Run("discriminator", func(t *testing.T) {
+		sourceBytes := []byte(`{
+			"openapi": "3.0.3",
+			"info": { "title": "Payments API", "version": "1.0.0" },
+			"paths": {
+				"/sources": {
+					"post": {
+						"operationId": "createSource",
+						"requestBody": {
+							"content": {
+								"application/json": {
+									"schema": { "$ref": "#/components/schemas/PaymentSource" }
+								}
+							}
+						},
+						"responses": {
+							"200": {
+								"description": "OK",
+								"content": {
+									"application/json": {
+										"schema": { "$ref": "#/components/schemas/PaymentSource" }
+									}
+								}
+							}
+						}
+					}
+				}
+			},
+			"components": {
+				"schemas": {
+					"PaymentSource": {
+						"description": "A payment source",
+						"oneOf": [
+							{ "$ref": "#/components/schemas/Card" },
+							{ "$ref": "#/components/schemas/BankAccount" }
+						],
+						"discriminator": {
+							"propertyName": "object",
+							"mapping": {
+								"card": "#/components/schemas/Card",
+								"bank_account": "#/components/schemas/BankAccount"
+							}
+						}
+					},
+					"Card": {
+						"type": "object",
+						"required": ["object"],
+						"properties": {
+							"object": { "type": "string" },
+							"brand": { "type": "string" }
+						}
+					},
+					"BankAccount": {
+						"type": "object",
+						"required": ["object"],
+						"properties": {
+							"object": { "type": "string" },
+							"routing_number": { "type": "string" }
+						}
+					}
+				}
+			}
+		}`)
+
+		output, errs := OpenAPIv3ToNDCSchema(sourceBytes, ConvertOptions{})
+		assert.NilError(t, errors.Join(errs...))
+
+		paymentSource, ok := output.ObjectTypes["PaymentSource"]
+		assert.Assert(t, ok)
+		assert.DeepEqual(t, &schema.UnionDiscriminator{
+			PropertyName: "object",
+			Mapping: map[string]string{
+				"card":         "card",
+				"bank_account": "bankAccount",
+			},
+		}, paymentSource.Discriminator)
+		assert.Equal(t, 3, len(paymentSource.Fields))
+		assert.DeepEqual(t, sdkSchema.NewNamedType("PaymentSourceObjectEnum").Encode(), paymentSource.Fields["object"].Type)
+		assert.DeepEqual(t, sdkSchema.NewNullableNamedType("Card").Encode(), paymentSource.Fields["card"].Type)
+		assert.DeepEqual(t, sdkSchema.NewNullableNamedType("BankAccount").Encode(), paymentSource.Fields["bankAccount"].Type)
+
+		enum, err := output.ScalarTypes["PaymentSourceObjectEnum"].Representation.AsEnum()
+		assert.NilError(t, err)
+		assert.DeepEqual(t, []string{"card", "bank_account"}, enum.OneOf)
+
+		// variants without the discriminator mapping are tagged by schema names
+		// and inline variants fall back to the merge strategy
+		output, errs = OpenAPIv3ToNDCSchema([]byte(strings.ReplaceAll(string(sourceBytes), `"mapping": {
+								"card": "#/components/schemas/Card",
+								"bank_account": "#/components/schemas/BankAccount"
+							}`, `"mapping": {}`)), ConvertOptions{})
+		assert.NilError(t, errors.Join(errs...))
+		assert.DeepEqual(t, map[string]string{
+			"Card":        "card",
+			"BankAccount": "bankAccount",
+		}, output.ObjectTypes["PaymentSource"].Discriminator.Mapping)
+
+		output, errs = OpenAPIv3ToNDCSchema([]byte(strings.ReplaceAll(string(sourceBytes), `{ "$ref": "#/components/schemas/BankAccount" }`, `{ "type": "object", "properties": { "iban": { "type": "string" } } }`)), ConvertOptions{})
+		assert.NilError(t, errors.Join(errs...))
+		assert.Assert(t, output.ObjectTypes["PaymentSource"].Discriminator == nil)
+		_, ok = output.ObjectTypes["PaymentSource"].Fields["brand"]
+		assert.Assert(t, ok)
+	})
+
+	t.Run("webhooks_only", func(t *testing.T) {
+		output, errs := OpenAPIv3ToNDCSchema([]byte(`{
+			"openapi": "3.1.0",
+			"info": { "title": "Events", "version": "1.0.0" },
+			"webhooks": {
+				"newPet": {
+					"post": {
+						"operationId": "onNewPet",
+						"requestBody": {
+							"content": {
+								"application/json": {
+									"schema": { "type": "object", "properties": { "name": { "type": "string" } } }
+								}
+							}
+						},
+						"responses": { "200": { "description": "OK" } }
+					}
+				}
+			}
+		}`), ConvertOptions{Prefix: "petstore"})
+		assert.NilError(t, errors.Join(errs...))
+		assert.Equal(t, 0, len(output.Procedures))
+
+		webhook, ok := output.Webhooks["petstoreOnNewPet"]
+		assert.Assert(t, ok)
+		assert.Equal(t, "post", webhook.Method)
+		payloadType, err := webhook.PayloadType.AsNamed()
+		assert.NilError(t, err)
+		assert.Equal(t, "PetstoreOnNewPetPayload", payloadType.Name)
+		_, ok = output.ObjectTypes["PetstoreOnNewPetPayload"]
+		assert.Assert(t, ok)
+	})
+
+	t.Run("failure_invalid_collection", func(t *testing.T) {
+		_, errs := OpenAPIv3ToNDCSchema([]byte(`{
+			"openapi": "3.0.3",
+			"info": { "title": "Library API", "version": "1.0.0" },
+			"paths": {
+				"/books": {
+					"get": {
+						"operationId": "listBooks",
+						"x-ndc-collection": {
+							"pagination": { "type": "unknown" }
+						},
+						"responses": {
+							"200": {
+								"description": "Books",
+								"content": {
+									"application/json": {
+										"schema": {
+											"type": "array",
+											"items": { "type": "object", "properties": { "id": { "type": "integer" } } }
+										}
+									}
+								}
+							}
+						}
+					}
+				}
+			}
+		}`), ConvertOptions{EmitCollections: true})
+		assert.ErrorContains(t, errors.Join(errs...), "listBooks: x-ndc-collection: pagination")
+	})
+
 	t.Run("failure_empty", func(t *testing.T) {
 		_, err := OpenAPIv3ToNDCSchema([]byte(""), ConvertOptions{})
 		assert.ErrorContains(t, errors.Join(err...), "there is nothing in the spec, it's empty")
@@ -131,6 +518,10 @@ func assertRESTSchemaEqual(t *testing.T, expected *schema.NDCHttpSchema, output
 	assertDeepEqual(t, expected.ObjectTypes, objectTypes)
 	assertDeepEqual(t, expected.Procedures, output.Procedures)
 	assertDeepEqual(t, expected.Functions, output.Functions)
+	assertDeepEqual(t, len(expected.Webhooks), len(output.Webhooks))
+	for key, webhook := range expected.Webhooks {
+		assertDeepEqual(t, webhook, output.Webhooks[key])
+	}
 }
 
 func assertDeepEqual(t *testing.T, expected any, reality any) {
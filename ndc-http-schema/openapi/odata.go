@@ -0,0 +1,17 @@
+package openapi
+
+import (
+	"github.com/hasura/ndc-http/ndc-http-schema/openapi/internal"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+// ODataToNDCSchema converts an OData v4 $metadata document in the CSDL XML format to NDC HTTP schema.
+// Entity sets and singletons are converted to functions.
+func ODataToNDCSchema(input []byte, options ConvertOptions) (*rest.NDCHttpSchema, []error) {
+	result, err := internal.NewODataBuilder(internal.ConvertOptions(options)).BuildSchema(input)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return result, nil
+}
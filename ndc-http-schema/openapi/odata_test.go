@@ -0,0 +1,90 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestODataToNDCSchema(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Source   string
+		Expected string
+		Schema   string
+		Options  ConvertOptions
+	}{
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/odata/source.xml -o ./ndc-http-schema/openapi/testdata/odata/expected.json --spec odata --env-prefix TRIPPIN --emit-collections
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/odata/source.xml -o ./ndc-http-schema/openapi/testdata/odata/schema.json --pure --spec odata --env-prefix TRIPPIN --emit-collections
+		{
+			Name:     "odata",
+			Source:   "testdata/odata/source.xml",
+			Expected: "testdata/odata/expected.json",
+			Schema:   "testdata/odata/schema.json",
+			Options: ConvertOptions{
+				EnvPrefix:       "TRIPPIN",
+				EmitCollections: true,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assert.NilError(t, err)
+
+			expectedBytes, err := os.ReadFile(tc.Expected)
+			assert.NilError(t, err)
+			var expected schema.NDCHttpSchema
+			assert.NilError(t, json.Unmarshal(expectedBytes, &expected))
+
+			output, errs := ODataToNDCSchema(sourceBytes, tc.Options)
+			if output == nil {
+				t.Fatal(errors.Join(errs...))
+			}
+
+			assertRESTSchemaEqual(t, &expected, output)
+			assertConnectorSchema(t, tc.Schema, output)
+		})
+	}
+
+	t.Run("entity_set", func(t *testing.T) {
+		sourceBytes, err := os.ReadFile("testdata/odata/source.xml")
+		assert.NilError(t, err)
+
+		output, errs := ODataToNDCSchema(sourceBytes, ConvertOptions{})
+		if output == nil {
+			t.Fatal(errors.Join(errs...))
+		}
+
+		people := output.Functions["people"]
+		assert.Assert(t, !people.Collection)
+		assert.DeepEqual(t, &schema.FilterSettings{
+			Style: schema.FilterOData,
+			Fields: map[string]schema.FilterFieldSettings{
+				"value.Concurrency": {Operators: []string{"_eq", "_neq", "_gt", "_gte", "_lt", "_lte", "_in"}, Unquoted: true},
+				"value.FirstName":   {Operators: []string{"_eq", "_neq", "_in"}},
+				"value.Gender":      {Operators: []string{"_eq", "_neq", "_in"}},
+				"value.LastName":    {Operators: []string{"_eq", "_neq", "_in"}},
+				"value.UserName":    {Operators: []string{"_eq", "_neq", "_in"}},
+			},
+		}, people.Request.Filter)
+		assert.DeepEqual(t, []string{"Concurrency", "FirstName", "Gender", "LastName", "UserName"}, people.Request.OrderBy.Fields)
+
+		// properties of the base type are inherited
+		flight := output.ObjectTypes["Flight"]
+		_, ok := flight.Fields["PlanItemId"]
+		assert.Assert(t, ok)
+		assert.Equal(t, "/Flights({PlanItemId})", output.Functions["flightsByKey"].Request.URL)
+		assert.Equal(t, "/OrderDetails(OrderId={OrderId},ProductId={ProductId})", output.Functions["orderDetailsByKey"].Request.URL)
+	})
+
+	t.Run("failure_no_entity_container", func(t *testing.T) {
+		_, errs := ODataToNDCSchema([]byte(`<edmx:Edmx Version="4.0" xmlns:edmx="http://docs.oasis-open.org/odata/ns/edmx"><edmx:DataServices></edmx:DataServices></edmx:Edmx>`), ConvertOptions{})
+		assert.ErrorContains(t, errors.Join(errs...), "there is no entity container in the OData metadata document")
+	})
+}
@@ -0,0 +1,17 @@
+package openapi
+
+import (
+	"github.com/hasura/ndc-http/ndc-http-schema/openapi/internal"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+// PostmanToNDCSchema converts a Postman Collection v2.1 document to NDC HTTP schema.
+// GET requests are converted to functions and other requests to procedures.
+func PostmanToNDCSchema(input []byte, options ConvertOptions) (*rest.NDCHttpSchema, []error) {
+	result, err := internal.NewPostmanBuilder(internal.ConvertOptions(options)).BuildSchema(input)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return result, nil
+}
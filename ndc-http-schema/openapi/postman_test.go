@@ -0,0 +1,79 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestPostmanToNDCSchema(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Source   string
+		Expected string
+		Schema   string
+		Options  ConvertOptions
+	}{
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/postman/source.json -o ./ndc-http-schema/openapi/testdata/postman/expected.json --spec postman --env-prefix PET_STORE
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/postman/source.json -o ./ndc-http-schema/openapi/testdata/postman/schema.json --pure --spec postman --env-prefix PET_STORE
+		{
+			Name:     "postman",
+			Source:   "testdata/postman/source.json",
+			Expected: "testdata/postman/expected.json",
+			Schema:   "testdata/postman/schema.json",
+			Options: ConvertOptions{
+				EnvPrefix: "PET_STORE",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assert.NilError(t, err)
+
+			expectedBytes, err := os.ReadFile(tc.Expected)
+			assert.NilError(t, err)
+			var expected schema.NDCHttpSchema
+			assert.NilError(t, json.Unmarshal(expectedBytes, &expected))
+
+			output, errs := PostmanToNDCSchema(sourceBytes, tc.Options)
+			if output == nil {
+				t.Fatal(errors.Join(errs...))
+			}
+
+			assertRESTSchemaEqual(t, &expected, output)
+			assertConnectorSchema(t, tc.Schema, output)
+		})
+	}
+
+	t.Run("duplicated_names", func(t *testing.T) {
+		source := `{
+			"info": { "name": "Duplicates", "schema": "https://schema.getpostman.com/json/collection/v2.1.0/collection.json" },
+			"item": [
+				{ "name": "Users", "item": [{ "name": "List", "request": { "method": "GET", "url": "http://localhost:8080/users" } }] },
+				{ "name": "Posts", "item": [{ "name": "List", "request": { "method": "GET", "url": "http://localhost:8080/posts" } }] },
+				{ "name": "Posts", "item": [{ "name": "List", "request": { "method": "GET", "url": "http://localhost:8080/posts/archived" } }] }
+			]
+		}`
+
+		output, errs := PostmanToNDCSchema([]byte(source), ConvertOptions{})
+		if output == nil {
+			t.Fatal(errors.Join(errs...))
+		}
+
+		assert.Equal(t, "/users", output.Functions["list"].Request.URL)
+		assert.Equal(t, "/posts", output.Functions["postsList"].Request.URL)
+		assert.Equal(t, "/posts/archived", output.Functions["postsList2"].Request.URL)
+		assert.Equal(t, "http://localhost:8080", *output.Settings.Servers[0].URL.Value)
+	})
+
+	t.Run("failure_no_request", func(t *testing.T) {
+		_, errs := PostmanToNDCSchema([]byte(`{"info": {"name": "Empty"}, "item": []}`), ConvertOptions{})
+		assert.ErrorContains(t, errors.Join(errs...), "there is no request in the Postman collection to be converted")
+	})
+}
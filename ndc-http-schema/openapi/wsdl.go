@@ -0,0 +1,17 @@
+package openapi
+
+import (
+	"github.com/hasura/ndc-http/ndc-http-schema/openapi/internal"
+	rest "github.com/hasura/ndc-http/ndc-http-schema/schema"
+)
+
+// WSDLToNDCSchema converts a WSDL 1.1 document to NDC HTTP schema.
+// Operations of the SOAP binding are converted to procedures.
+func WSDLToNDCSchema(input []byte, options ConvertOptions) (*rest.NDCHttpSchema, []error) {
+	result, err := internal.NewWSDLBuilder(internal.ConvertOptions(options)).BuildSchema(input)
+	if err != nil {
+		return nil, []error{err}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,110 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/hasura/ndc-http/ndc-http-schema/schema"
+	"gotest.tools/v3/assert"
+)
+
+func TestWSDLToNDCSchema(t *testing.T) {
+	testCases := []struct {
+		Name     string
+		Source   string
+		Expected string
+		Schema   string
+		Options  ConvertOptions
+	}{
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/wsdl/source.wsdl -o ./ndc-http-schema/openapi/testdata/wsdl/expected.json --spec wsdl --env-prefix STOCK
+		// go run ./ndc-http-schema convert -f ./ndc-http-schema/openapi/testdata/wsdl/source.wsdl -o ./ndc-http-schema/openapi/testdata/wsdl/schema.json --pure --spec wsdl --env-prefix STOCK
+		{
+			Name:     "wsdl",
+			Source:   "testdata/wsdl/source.wsdl",
+			Expected: "testdata/wsdl/expected.json",
+			Schema:   "testdata/wsdl/schema.json",
+			Options: ConvertOptions{
+				EnvPrefix: "STOCK",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Name, func(t *testing.T) {
+			sourceBytes, err := os.ReadFile(tc.Source)
+			assert.NilError(t, err)
+
+			expectedBytes, err := os.ReadFile(tc.Expected)
+			assert.NilError(t, err)
+			var expected schema.NDCHttpSchema
+			assert.NilError(t, json.Unmarshal(expectedBytes, &expected))
+
+			output, errs := WSDLToNDCSchema(sourceBytes, tc.Options)
+			if output == nil {
+				t.Fatal(errors.Join(errs...))
+			}
+
+			assertRESTSchemaEqual(t, &expected, output)
+			assertConnectorSchema(t, tc.Schema, output)
+		})
+	}
+
+	t.Run("rpc", func(t *testing.T) {
+		source := `<definitions xmlns="http://schemas.xmlsoap.org/wsdl/"
+			xmlns:soap12="http://schemas.xmlsoap.org/wsdl/soap12/"
+			xmlns:xsd="http://www.w3.org/2001/XMLSchema"
+			xmlns:tns="urn:calculator"
+			targetNamespace="urn:calculator">
+			<message name="AddRequest">
+				<part name="a" type="xsd:int"/>
+				<part name="b" type="xsd:int"/>
+			</message>
+			<message name="AddResponse">
+				<part name="result" type="xsd:int"/>
+			</message>
+			<portType name="CalculatorPortType">
+				<operation name="Add">
+					<input message="tns:AddRequest"/>
+					<output message="tns:AddResponse"/>
+				</operation>
+			</portType>
+			<binding name="CalculatorBinding" type="tns:CalculatorPortType">
+				<soap12:binding style="rpc" transport="http://schemas.xmlsoap.org/soap/http"/>
+				<operation name="Add">
+					<soap12:operation soapAction="urn:calculator#Add"/>
+					<input><soap12:body use="literal" namespace="urn:calculator:v1"/></input>
+					<output><soap12:body use="literal" namespace="urn:calculator:v1"/></output>
+				</operation>
+			</binding>
+		</definitions>`
+
+		output, errs := WSDLToNDCSchema([]byte(source), ConvertOptions{})
+		if output == nil {
+			t.Fatal(errors.Join(errs...))
+		}
+
+		procedure, ok := output.Procedures["add"]
+		assert.Assert(t, ok)
+		assert.DeepEqual(t, &schema.SOAPRequest{
+			Version: schema.SOAPVersion12,
+			Action:  "urn:calculator#Add",
+		}, procedure.Request.SOAP)
+		assert.Equal(t, schema.ContentTypeSOAPXML, procedure.Request.RequestBody.ContentType)
+
+		requestType := output.ObjectTypes["AddRequest"]
+		assert.DeepEqual(t, &schema.XMLSchema{
+			Name:      "Add",
+			Prefix:    "ns",
+			Namespace: "urn:calculator:v1",
+		}, requestType.XML)
+		assert.Equal(t, 2, len(requestType.Fields))
+		assert.Equal(t, "AddResponse", output.ObjectTypes["AddResponse"].XML.Name)
+	})
+
+	t.Run("failure_no_binding", func(t *testing.T) {
+		_, errs := WSDLToNDCSchema([]byte(`<definitions xmlns="http://schemas.xmlsoap.org/wsdl/"></definitions>`), ConvertOptions{})
+		assert.ErrorContains(t, errors.Join(errs...), "there is no SOAP binding in the WSDL document")
+	})
+}
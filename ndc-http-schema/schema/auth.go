@@ -31,6 +31,9 @@ const (
 	OAuth2Scheme        SecuritySchemeType = "oauth2"
 	OpenIDConnectScheme SecuritySchemeType = "openIdConnect"
 	MutualTLSScheme     SecuritySchemeType = "mutualTLS"
+	HMACScheme          SecuritySchemeType = "hmac"
+	JWTScheme           SecuritySchemeType = "jwt"
+	DigestAuthScheme    SecuritySchemeType = "digest"
 )
 
 var securityScheme_enums = []SecuritySchemeType{
@@ -41,6 +44,9 @@ var securityScheme_enums = []SecuritySchemeType{
 	OAuth2Scheme,
 	OpenIDConnectScheme,
 	MutualTLSScheme,
+	HMACScheme,
+	JWTScheme,
+	DigestAuthScheme,
 }
 
 // JSONSchema is used to generate a custom jsonschema
@@ -153,6 +159,10 @@ func (j SecurityScheme) JSONSchema() *jsonschema.Schema {
 	apiKeySchema.Set("name", &jsonschema.Schema{
 		Type: "string",
 	})
+	apiKeySchema.Set("secondaryValue", &jsonschema.Schema{
+		Description: "The secondary key for zero-downtime key rotation",
+		Ref:         "#/$defs/EnvString",
+	})
 
 	httpAuthSchema := orderedmap.New[string, *jsonschema.Schema]()
 	httpAuthSchema.Set("type", &jsonschema.Schema{
@@ -166,6 +176,10 @@ func (j SecurityScheme) JSONSchema() *jsonschema.Schema {
 	httpAuthSchema.Set("scheme", &jsonschema.Schema{
 		Type: "string",
 	})
+	httpAuthSchema.Set("secondaryValue", &jsonschema.Schema{
+		Description: "The secondary token for zero-downtime key rotation",
+		Ref:         "#/$defs/EnvString",
+	})
 
 	basicAuthSchema := orderedmap.New[string, *jsonschema.Schema]()
 	basicAuthSchema.Set("type", &jsonschema.Schema{
@@ -191,17 +205,15 @@ func (j SecurityScheme) JSONSchema() *jsonschema.Schema {
 		Type: "string",
 	})
 
-	cookieSchema := orderedmap.New[string, *jsonschema.Schema]()
-	cookieSchema.Set("type", &jsonschema.Schema{
-		Type: "string",
-		Enum: []any{CookieAuthScheme},
-	})
-
 	mutualTLSSchema := orderedmap.New[string, *jsonschema.Schema]()
 	mutualTLSSchema.Set("type", &jsonschema.Schema{
 		Type: "string",
 		Enum: []any{MutualTLSScheme},
 	})
+	mutualTLSSchema.Set("tls", &jsonschema.Schema{
+		Description: "The client certificate set of the security scheme. Operations which use the scheme are requested with this certificate instead of the server default",
+		Ref:         "#/$defs/TLSConfig",
+	})
 
 	return &jsonschema.Schema{
 		OneOf: []*jsonschema.Schema{
@@ -226,16 +238,15 @@ func (j SecurityScheme) JSONSchema() *jsonschema.Schema {
 				Properties: oidcSchema,
 				Required:   []string{"type", "openIdConnectUrl"},
 			},
-			{
-				Type:       "object",
-				Properties: cookieSchema,
-				Required:   []string{"type"},
-			},
+			CookieAuthConfig{}.JSONSchema(),
 			{
 				Type:       "object",
 				Properties: mutualTLSSchema,
 				Required:   []string{"type"},
 			},
+			HMACAuthConfig{}.JSONSchema(),
+			JWTAuthConfig{}.JSONSchema(),
+			DigestAuthConfig{}.JSONSchema(),
 		},
 	}
 }
@@ -288,13 +299,40 @@ func (j *SecurityScheme) UnmarshalJSON(b []byte) error {
 		_ = config.Validate()
 		j.SecuritySchemer = &config
 	case CookieAuthScheme:
-		j.SecuritySchemer = &CookieAuthConfig{
-			Type: rawScheme.Type,
+		var config CookieAuthConfig
+		if err := json.Unmarshal(b, &config); err != nil {
+			return err
 		}
+		_ = config.Validate()
+		j.SecuritySchemer = &config
 	case MutualTLSScheme:
-		j.SecuritySchemer = &MutualTLSAuthConfig{
-			Type: rawScheme.Type,
+		var config MutualTLSAuthConfig
+		if err := json.Unmarshal(b, &config); err != nil {
+			return err
 		}
+		_ = config.Validate()
+		j.SecuritySchemer = &config
+	case HMACScheme:
+		var config HMACAuthConfig
+		if err := json.Unmarshal(b, &config); err != nil {
+			return err
+		}
+		_ = config.Validate()
+		j.SecuritySchemer = &config
+	case JWTScheme:
+		var config JWTAuthConfig
+		if err := json.Unmarshal(b, &config); err != nil {
+			return err
+		}
+		_ = config.Validate()
+		j.SecuritySchemer = &config
+	case DigestAuthScheme:
+		var config DigestAuthConfig
+		if err := json.Unmarshal(b, &config); err != nil {
+			return err
+		}
+		_ = config.Validate()
+		j.SecuritySchemer = &config
 	}
 
 	return nil
@@ -322,6 +360,8 @@ type APIKeyAuthConfig struct {
 	In    APIKeyLocation     `json:"in"    mapstructure:"in"    yaml:"in"`
 	Name  string             `json:"name"  mapstructure:"name"  yaml:"name"`
 	Value utils.EnvString    `json:"value" mapstructure:"value" yaml:"value"`
+	// The secondary key for zero-downtime key rotation. The request is retried once with the secondary key if the primary key is rejected with 401
+	SecondaryValue *utils.EnvString `json:"secondaryValue,omitempty" mapstructure:"secondaryValue" yaml:"secondaryValue,omitempty"`
 }
 
 var _ SecuritySchemer = &APIKeyAuthConfig{}
@@ -378,6 +418,8 @@ type HTTPAuthConfig struct {
 	Header string             `json:"header" mapstructure:"header" yaml:"header"`
 	Scheme string             `json:"scheme" mapstructure:"scheme" yaml:"scheme"`
 	Value  utils.EnvString    `json:"value"  mapstructure:"value"  yaml:"value"`
+	// The secondary token for zero-downtime key rotation. The request is retried once with the secondary token if the primary token is rejected with 401
+	SecondaryValue *utils.EnvString `json:"secondaryValue,omitempty" mapstructure:"secondaryValue" yaml:"secondaryValue,omitempty"`
 }
 
 var _ SecuritySchemer = &HTTPAuthConfig{}
@@ -435,6 +477,58 @@ func (ss BasicAuthConfig) GetType() SecuritySchemeType {
 	return ss.Type
 }
 
+// DigestAuthConfig contains configurations for the [digest] authentication.
+// The client responds to the 401 challenge of the server with MD5, SHA-256 or SHA-512-256 digests.
+//
+// [digest]: https://datatracker.ietf.org/doc/html/rfc7616
+type DigestAuthConfig struct {
+	Type     SecuritySchemeType `json:"type"     mapstructure:"type"     yaml:"type"`
+	Username utils.EnvString    `json:"username" mapstructure:"username" yaml:"username"`
+	Password utils.EnvString    `json:"password" mapstructure:"password" yaml:"password"`
+}
+
+var _ SecuritySchemer = &DigestAuthConfig{}
+
+// NewDigestAuthConfig creates a new DigestAuthConfig instance.
+func NewDigestAuthConfig(username, password utils.EnvString) *DigestAuthConfig {
+	return &DigestAuthConfig{
+		Type:     DigestAuthScheme,
+		Username: username,
+		Password: password,
+	}
+}
+
+// Validate if the current instance is valid
+func (ss DigestAuthConfig) Validate() error {
+	return nil
+}
+
+// GetValue get the authentication credential value
+func (ss DigestAuthConfig) GetType() SecuritySchemeType {
+	return ss.Type
+}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j DigestAuthConfig) JSONSchema() *jsonschema.Schema {
+	envStringRef := &jsonschema.Schema{
+		Ref: "#/$defs/EnvString",
+	}
+
+	digestSchema := orderedmap.New[string, *jsonschema.Schema]()
+	digestSchema.Set("type", &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{DigestAuthScheme},
+	})
+	digestSchema.Set("username", envStringRef)
+	digestSchema.Set("password", envStringRef)
+
+	return &jsonschema.Schema{
+		Type:       "object",
+		Properties: digestSchema,
+		Required:   []string{"type", "username", "password"},
+	}
+}
+
 // OAuthFlowType represents the OAuth flow type enum
 type OAuthFlowType string
 
@@ -490,6 +584,14 @@ type OAuthFlow struct {
 	ClientID         *utils.EnvString           `json:"clientId,omitempty"         mapstructure:"clientId"         yaml:"clientId,omitempty"`
 	ClientSecret     *utils.EnvString           `json:"clientSecret,omitempty"     mapstructure:"clientSecret"     yaml:"clientSecret,omitempty"`
 	EndpointParams   map[string]utils.EnvString `json:"endpointParams,omitempty"   mapstructure:"endpointParams"   yaml:"endpointParams,omitempty"`
+	// The username of the resource owner in the password flow.
+	Username *utils.EnvString `json:"username,omitempty" mapstructure:"username" yaml:"username,omitempty"`
+	// The password of the resource owner in the password flow.
+	Password *utils.EnvString `json:"password,omitempty" mapstructure:"password" yaml:"password,omitempty"`
+	// The refresh token to request access tokens in the authorization code flow without user interaction.
+	RefreshToken *utils.EnvString `json:"refreshToken,omitempty" mapstructure:"refreshToken" yaml:"refreshToken,omitempty"`
+	// Generate DPoP proofs to request sender-constrained tokens.
+	DPoP *DPoPConfig `json:"dpop,omitempty" mapstructure:"dpop" yaml:"dpop,omitempty"`
 }
 
 // Validate if the current instance is valid
@@ -502,6 +604,12 @@ func (ss OAuthFlow) Validate(flowType OAuthFlowType) error {
 		return errors.New("tokenUrl: value and env are empty")
 	}
 
+	if ss.DPoP != nil {
+		if err := ss.DPoP.Validate(); err != nil {
+			return fmt.Errorf("dpop: %w", err)
+		}
+	}
+
 	if flowType != ClientCredentialsFlow {
 		return nil
 	}
@@ -521,6 +629,72 @@ func (ss OAuthFlow) Validate(flowType OAuthFlowType) error {
 	return nil
 }
 
+// DPoPAlgorithm represents the signing algorithm of DPoP proofs.
+type DPoPAlgorithm string
+
+const (
+	DPoPES256 DPoPAlgorithm = "ES256"
+	DPoPRS256 DPoPAlgorithm = "RS256"
+)
+
+var dpopAlgorithm_enums = []DPoPAlgorithm{DPoPES256, DPoPRS256}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j DPoPAlgorithm) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(dpopAlgorithm_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *DPoPAlgorithm) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseDPoPAlgorithm(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// ParseDPoPAlgorithm parses DPoPAlgorithm from string
+func ParseDPoPAlgorithm(value string) (DPoPAlgorithm, error) {
+	result := DPoPAlgorithm(value)
+	if !slices.Contains(dpopAlgorithm_enums, result) {
+		return result, fmt.Errorf("invalid DPoPAlgorithm. Expected %+v, got <%s>", dpopAlgorithm_enums, value)
+	}
+
+	return result, nil
+}
+
+// DPoPConfig contains settings to generate [DPoP] proofs for sender-constrained OAuth 2.0 tokens.
+//
+// [DPoP]: https://datatracker.ietf.org/doc/html/rfc9449
+type DPoPConfig struct {
+	// The algorithm to sign DPoP proofs. Default to ES256.
+	Algorithm DPoPAlgorithm `json:"alg,omitempty" mapstructure:"alg" yaml:"alg,omitempty"`
+	// The PEM-encoded private key to sign DPoP proofs. An ephemeral key is generated on startup if empty.
+	PrivateKey *utils.EnvString `json:"privateKey,omitempty" mapstructure:"privateKey" yaml:"privateKey,omitempty"`
+}
+
+// Validate if the current instance is valid
+func (dc DPoPConfig) Validate() error {
+	if dc.Algorithm != "" {
+		if _, err := ParseDPoPAlgorithm(string(dc.Algorithm)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // OAuth2Config contains configurations for [OAuth 2.0] API specification
 //
 // [OAuth 2.0]: https://swagger.io/docs/specification/authentication/oauth2
@@ -653,6 +827,27 @@ func (ss OpenIDConnectConfig) Validate() error {
 // CookieAuthConfig represents a cookie authentication configuration.
 type CookieAuthConfig struct {
 	Type SecuritySchemeType `json:"type" mapstructure:"type" yaml:"type"`
+	// The login request which the connector executes to obtain session cookies.
+	// The Cookie header must be forwarded from the Hasura engine if empty
+	Login *CookieLoginConfig `json:"login,omitempty" mapstructure:"login" yaml:"login,omitempty"`
+}
+
+// CookieLoginConfig represents the login request to obtain session cookies of the cookie authentication.
+type CookieLoginConfig struct {
+	// The absolute URL of the login request
+	URL utils.EnvString `json:"url" mapstructure:"url" yaml:"url"`
+	// The HTTP method of the login request. Default to POST
+	Method string `json:"method,omitempty" mapstructure:"method" yaml:"method,omitempty"`
+	// Headers of the login request
+	Headers map[string]utils.EnvString `json:"headers,omitempty" mapstructure:"headers" yaml:"headers,omitempty"`
+	// The body template of the login request. {{env.NAME}} placeholders are replaced with environment variables
+	Body string `json:"body,omitempty" mapstructure:"body" yaml:"body,omitempty"`
+	// The content type of the body. Default to application/json
+	ContentType string `json:"contentType,omitempty" mapstructure:"contentType" yaml:"contentType,omitempty"`
+	// Names of session cookies to be stored. All cookies of the login response are stored if empty
+	Cookies []string `json:"cookies,omitempty" mapstructure:"cookies" yaml:"cookies,omitempty"`
+	// The lifetime of the session in seconds if cookies don't have an expiry. Default to 3600
+	TTL uint `json:"ttl,omitempty" mapstructure:"ttl" yaml:"ttl,omitempty"`
 }
 
 var _ SecuritySchemer = &CookieAuthConfig{}
@@ -671,12 +866,80 @@ func (ss CookieAuthConfig) GetType() SecuritySchemeType {
 
 // Validate if the current instance is valid
 func (ss CookieAuthConfig) Validate() error {
+	if ss.Login == nil {
+		return nil
+	}
+
+	if ss.Login.URL.Value == nil && ss.Login.URL.Variable == nil {
+		return errors.New("login.url is required for cookie security")
+	}
+
 	return nil
 }
 
+// JSONSchema is used to generate a custom jsonschema
+func (j CookieAuthConfig) JSONSchema() *jsonschema.Schema {
+	envStringRef := &jsonschema.Schema{
+		Ref: "#/$defs/EnvString",
+	}
+
+	loginSchema := orderedmap.New[string, *jsonschema.Schema]()
+	loginSchema.Set("url", envStringRef)
+	loginSchema.Set("method", &jsonschema.Schema{
+		Description: "The HTTP method of the login request. Default to POST",
+		Type:        "string",
+	})
+	loginSchema.Set("headers", &jsonschema.Schema{
+		Description:          "Headers of the login request",
+		Type:                 "object",
+		AdditionalProperties: envStringRef,
+	})
+	loginSchema.Set("body", &jsonschema.Schema{
+		Description: "The body template of the login request. {{env.NAME}} placeholders are replaced with environment variables",
+		Type:        "string",
+	})
+	loginSchema.Set("contentType", &jsonschema.Schema{
+		Description: "The content type of the body. Default to application/json",
+		Type:        "string",
+	})
+	loginSchema.Set("cookies", &jsonschema.Schema{
+		Description: "Names of session cookies to be stored. All cookies of the login response are stored if empty",
+		Type:        "array",
+		Items: &jsonschema.Schema{
+			Type: "string",
+		},
+	})
+	loginSchema.Set("ttl", &jsonschema.Schema{
+		Description: "The lifetime of the session in seconds if cookies don't have an expiry. Default to 3600",
+		Type:        "integer",
+		Minimum:     json.Number("1"),
+	})
+
+	cookieSchema := orderedmap.New[string, *jsonschema.Schema]()
+	cookieSchema.Set("type", &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{CookieAuthScheme},
+	})
+	cookieSchema.Set("login", &jsonschema.Schema{
+		Description: "The login request which the connector executes to obtain session cookies",
+		Type:        "object",
+		Properties:  loginSchema,
+		Required:    []string{"url"},
+	})
+
+	return &jsonschema.Schema{
+		Type:       "object",
+		Properties: cookieSchema,
+		Required:   []string{"type"},
+	}
+}
+
 // MutualTLSAuthConfig represents a mutualTLS authentication configuration.
 type MutualTLSAuthConfig struct {
 	Type SecuritySchemeType `json:"type" mapstructure:"type" yaml:"type"`
+	// The client certificate set of the security scheme, e.g. to use different client certificates for API products behind the same host.
+	// Operations which use the scheme are requested with this certificate instead of the TLS config of the server.
+	TLS *TLSConfig `json:"tls,omitempty" mapstructure:"tls" yaml:"tls,omitempty"`
 }
 
 var _ SecuritySchemer = &MutualTLSAuthConfig{}
@@ -695,9 +958,350 @@ func (ss MutualTLSAuthConfig) GetType() SecuritySchemeType {
 
 // Validate if the current instance is valid
 func (ss MutualTLSAuthConfig) Validate() error {
+	if ss.TLS == nil {
+		return nil
+	}
+
+	if err := ss.TLS.Validate(); err != nil {
+		return fmt.Errorf("tls: %w", err)
+	}
+
 	return nil
 }
 
+// HMACAlgorithm represents the hash algorithm of HMAC signatures.
+type HMACAlgorithm string
+
+const (
+	HMACSHA256 HMACAlgorithm = "sha256"
+	HMACSHA512 HMACAlgorithm = "sha512"
+)
+
+var hmacAlgorithm_enums = []HMACAlgorithm{HMACSHA256, HMACSHA512}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j HMACAlgorithm) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(hmacAlgorithm_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *HMACAlgorithm) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseHMACAlgorithm(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// ParseHMACAlgorithm parses HMACAlgorithm from string
+func ParseHMACAlgorithm(value string) (HMACAlgorithm, error) {
+	result := HMACAlgorithm(value)
+	if !slices.Contains(hmacAlgorithm_enums, result) {
+		return result, fmt.Errorf("invalid HMACAlgorithm. Expected %+v, got <%s>", hmacAlgorithm_enums, value)
+	}
+
+	return result, nil
+}
+
+// HMACEncoding represents the encoding of HMAC signatures.
+type HMACEncoding string
+
+const (
+	HMACEncodingHex    HMACEncoding = "hex"
+	HMACEncodingBase64 HMACEncoding = "base64"
+)
+
+var hmacEncoding_enums = []HMACEncoding{HMACEncodingHex, HMACEncodingBase64}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j HMACEncoding) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(hmacEncoding_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *HMACEncoding) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseHMACEncoding(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// ParseHMACEncoding parses HMACEncoding from string
+func ParseHMACEncoding(value string) (HMACEncoding, error) {
+	result := HMACEncoding(value)
+	if !slices.Contains(hmacEncoding_enums, result) {
+		return result, fmt.Errorf("invalid HMACEncoding. Expected %+v, got <%s>", hmacEncoding_enums, value)
+	}
+
+	return result, nil
+}
+
+// DefaultHMACTemplate is the default template of the canonical string to be signed
+const DefaultHMACTemplate = "{method}\n{path}\n{timestamp}\n{bodyDigest}"
+
+// HMACAuthConfig contains configurations to sign requests with HMAC signatures.
+// The canonical string is rendered from the template with placeholders:
+// {method}, {host}, {path}, {query}, {timestamp}, {body} and {bodyDigest}.
+type HMACAuthConfig struct {
+	Type SecuritySchemeType `json:"type" mapstructure:"type" yaml:"type"`
+	// The hash algorithm of the signature. Default to sha256
+	Algorithm HMACAlgorithm `json:"algorithm,omitempty" mapstructure:"algorithm" yaml:"algorithm,omitempty"`
+	// The secret key to sign requests
+	Secret utils.EnvString `json:"secret" mapstructure:"secret" yaml:"secret"`
+	// The request header to send the signature
+	Header string `json:"header" mapstructure:"header" yaml:"header"`
+	// The prefix of the signature header value, e.g. sha256=
+	Prefix string `json:"prefix,omitempty" mapstructure:"prefix" yaml:"prefix,omitempty"`
+	// The template of the canonical string to be signed. Default to {method}\n{path}\n{timestamp}\n{bodyDigest}
+	Template string `json:"template,omitempty" mapstructure:"template" yaml:"template,omitempty"`
+	// The request header to send the unix timestamp of the signature. Default to X-Timestamp if the template contains the {timestamp} placeholder
+	TimestampHeader string `json:"timestampHeader,omitempty" mapstructure:"timestampHeader" yaml:"timestampHeader,omitempty"`
+	// The encoding of the signature. Default to hex
+	Encoding HMACEncoding `json:"encoding,omitempty" mapstructure:"encoding" yaml:"encoding,omitempty"`
+}
+
+var _ SecuritySchemer = &HMACAuthConfig{}
+
+// NewHMACAuthConfig creates a new HMACAuthConfig instance.
+func NewHMACAuthConfig(header string, secret utils.EnvString) *HMACAuthConfig {
+	return &HMACAuthConfig{
+		Type:   HMACScheme,
+		Header: header,
+		Secret: secret,
+	}
+}
+
+// GetValue get the authentication credential value
+func (ss HMACAuthConfig) GetType() SecuritySchemeType {
+	return ss.Type
+}
+
+// Validate if the current instance is valid
+func (ss HMACAuthConfig) Validate() error {
+	if ss.Header == "" {
+		return errors.New("header is required for hmac security")
+	}
+
+	if ss.Algorithm != "" {
+		if _, err := ParseHMACAlgorithm(string(ss.Algorithm)); err != nil {
+			return err
+		}
+	}
+
+	if ss.Encoding != "" {
+		if _, err := ParseHMACEncoding(string(ss.Encoding)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j HMACAuthConfig) JSONSchema() *jsonschema.Schema {
+	hmacSchema := orderedmap.New[string, *jsonschema.Schema]()
+	hmacSchema.Set("type", &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{HMACScheme},
+	})
+	hmacSchema.Set("algorithm", HMACAlgorithm("").JSONSchema())
+	hmacSchema.Set("secret", &jsonschema.Schema{
+		Ref: "#/$defs/EnvString",
+	})
+	hmacSchema.Set("header", &jsonschema.Schema{
+		Description: "The request header to send the signature",
+		Type:        "string",
+	})
+	hmacSchema.Set("prefix", &jsonschema.Schema{
+		Description: "The prefix of the signature header value, e.g. sha256=",
+		Type:        "string",
+	})
+	hmacSchema.Set("template", &jsonschema.Schema{
+		Description: "The template of the canonical string to be signed. Supported placeholders are {method}, {host}, {path}, {query}, {timestamp}, {body} and {bodyDigest}",
+		Type:        "string",
+	})
+	hmacSchema.Set("timestampHeader", &jsonschema.Schema{
+		Description: "The request header to send the unix timestamp of the signature",
+		Type:        "string",
+	})
+	hmacSchema.Set("encoding", HMACEncoding("").JSONSchema())
+
+	return &jsonschema.Schema{
+		Type:       "object",
+		Properties: hmacSchema,
+		Required:   []string{"type", "secret", "header"},
+	}
+}
+
+// JWTAlgorithm represents the signing algorithm of self-issued JWTs.
+type JWTAlgorithm string
+
+const (
+	JWTRS256 JWTAlgorithm = "RS256"
+	JWTES256 JWTAlgorithm = "ES256"
+)
+
+var jwtAlgorithm_enums = []JWTAlgorithm{JWTRS256, JWTES256}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j JWTAlgorithm) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(jwtAlgorithm_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *JWTAlgorithm) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseJWTAlgorithm(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// ParseJWTAlgorithm parses JWTAlgorithm from string
+func ParseJWTAlgorithm(value string) (JWTAlgorithm, error) {
+	result := JWTAlgorithm(value)
+	if !slices.Contains(jwtAlgorithm_enums, result) {
+		return result, fmt.Errorf("invalid JWTAlgorithm. Expected %+v, got <%s>", jwtAlgorithm_enums, value)
+	}
+
+	return result, nil
+}
+
+// DefaultJWTTTL is the default lifetime of self-issued JWTs in seconds
+const DefaultJWTTTL = 3600
+
+// JWTAuthConfig contains configurations to mint JWTs from a private key and send them as bearer tokens,
+// e.g. Google service accounts or the private_key_jwt client authentication.
+type JWTAuthConfig struct {
+	Type SecuritySchemeType `json:"type" mapstructure:"type" yaml:"type"`
+	// The algorithm to sign tokens. Default to RS256
+	Algorithm JWTAlgorithm `json:"algorithm,omitempty" mapstructure:"algorithm" yaml:"algorithm,omitempty"`
+	// The PEM-encoded private key to sign tokens
+	PrivateKey utils.EnvString `json:"privateKey" mapstructure:"privateKey" yaml:"privateKey"`
+	// The key ID which is set to the kid header
+	KeyID *utils.EnvString `json:"keyId,omitempty" mapstructure:"keyId" yaml:"keyId,omitempty"`
+	// The issuer claim (iss)
+	Issuer *utils.EnvString `json:"issuer,omitempty" mapstructure:"issuer" yaml:"issuer,omitempty"`
+	// The subject claim (sub)
+	Subject *utils.EnvString `json:"subject,omitempty" mapstructure:"subject" yaml:"subject,omitempty"`
+	// The audience claim (aud)
+	Audience *utils.EnvString `json:"audience,omitempty" mapstructure:"audience" yaml:"audience,omitempty"`
+	// The lifetime of tokens in seconds. Default to 3600
+	TTL uint `json:"ttl,omitempty" mapstructure:"ttl" yaml:"ttl,omitempty"`
+	// Custom claims of tokens
+	Claims map[string]any `json:"claims,omitempty" mapstructure:"claims" yaml:"claims,omitempty"`
+	// The request header to send the token. Default to Authorization
+	Header string `json:"header,omitempty" mapstructure:"header" yaml:"header,omitempty"`
+	// The authorization scheme of the token. Default to Bearer
+	Scheme string `json:"scheme,omitempty" mapstructure:"scheme" yaml:"scheme,omitempty"`
+}
+
+var _ SecuritySchemer = &JWTAuthConfig{}
+
+// NewJWTAuthConfig creates a new JWTAuthConfig instance.
+func NewJWTAuthConfig(privateKey utils.EnvString) *JWTAuthConfig {
+	return &JWTAuthConfig{
+		Type:       JWTScheme,
+		PrivateKey: privateKey,
+	}
+}
+
+// GetValue get the authentication credential value
+func (ss JWTAuthConfig) GetType() SecuritySchemeType {
+	return ss.Type
+}
+
+// Validate if the current instance is valid
+func (ss JWTAuthConfig) Validate() error {
+	if ss.PrivateKey.Value == nil && ss.PrivateKey.Variable == nil {
+		return errors.New("privateKey is required for jwt security")
+	}
+
+	if ss.Algorithm != "" {
+		if _, err := ParseJWTAlgorithm(string(ss.Algorithm)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j JWTAuthConfig) JSONSchema() *jsonschema.Schema {
+	envStringRef := &jsonschema.Schema{
+		Ref: "#/$defs/EnvString",
+	}
+
+	jwtSchema := orderedmap.New[string, *jsonschema.Schema]()
+	jwtSchema.Set("type", &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{JWTScheme},
+	})
+	jwtSchema.Set("algorithm", JWTAlgorithm("").JSONSchema())
+	jwtSchema.Set("privateKey", envStringRef)
+	jwtSchema.Set("keyId", envStringRef)
+	jwtSchema.Set("issuer", envStringRef)
+	jwtSchema.Set("subject", envStringRef)
+	jwtSchema.Set("audience", envStringRef)
+	jwtSchema.Set("ttl", &jsonschema.Schema{
+		Description: "The lifetime of tokens in seconds",
+		Type:        "integer",
+		Minimum:     json.Number("1"),
+	})
+	jwtSchema.Set("claims", &jsonschema.Schema{
+		Description: "Custom claims of tokens",
+		Type:        "object",
+	})
+	jwtSchema.Set("header", &jsonschema.Schema{
+		Description: "The request header to send the token. Default to Authorization",
+		Type:        "string",
+	})
+	jwtSchema.Set("scheme", &jsonschema.Schema{
+		Description: "The authorization scheme of the token. Default to Bearer",
+		Type:        "string",
+	})
+
+	return &jsonschema.Schema{
+		Type:       "object",
+		Properties: jwtSchema,
+		Required:   []string{"type", "privateKey"},
+	}
+}
+
 // AuthSecurity wraps the raw security requirement with helpers
 type AuthSecurity map[string][]string
 
@@ -1,9 +1,14 @@
 package schema
 
 const (
-	HTTPOptionsArgumentName          string = "httpOptions"
-	HTTPSingleOptionsObjectName      string = "HttpSingleOptions"
-	HTTPDistributedOptionsObjectName string = "HttpDistributedOptions"
-	HTTPServerIDScalarName           string = "HttpServerId"
-	DistributedErrorObjectName       string = "DistributedError"
+	HTTPOptionsArgumentName           string = "httpOptions"
+	HTTPSingleOptionsObjectName       string = "HttpSingleOptions"
+	HTTPDistributedOptionsObjectName  string = "HttpDistributedOptions"
+	HTTPDryRunOptionsObjectName       string = "HttpDryRunOptions"
+	HTTPServerIDScalarName            string = "HttpServerId"
+	HTTPDistributedStrategyScalarName string = "HttpDistributedStrategy"
+	DistributedErrorObjectName        string = "DistributedError"
+	DistributedComparisonObjectName   string = "DistributedComparison"
+	DistributedDifferenceObjectName   string = "DistributedDifference"
+	RedirectResultObjectName          string = "RedirectResult"
 )
@@ -0,0 +1,92 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hasura/ndc-sdk-go/utils"
+	"github.com/theory/jsonpath"
+	"github.com/theory/jsonpath/spec"
+)
+
+// FieldEncryptionConfig represents the application-layer encryption of argument and response fields.
+// Argument fields are encrypted before sending requests and response fields are decrypted after receiving responses.
+type FieldEncryptionConfig struct {
+	// The encryption algorithm. Default to aes-gcm
+	Algorithm FieldEncryptionAlgorithm `json:"algorithm,omitempty" mapstructure:"algorithm" yaml:"algorithm,omitempty"`
+	// The base64-encoded encryption key. If the kms setting is set, the key is the base64-encoded ciphertext blob of the data key which is encrypted by AWS KMS
+	Key utils.EnvString `json:"key" mapstructure:"key" yaml:"key"`
+	// Decrypt the data key with AWS KMS when the connector starts, so the plaintext key isn't stored in the configuration
+	KMS *FieldEncryptionKMSConfig `json:"kms,omitempty" mapstructure:"kms" yaml:"kms,omitempty"`
+	// JSON paths of argument fields to be encrypted, e.g. body.ssn, body.contacts[*].email
+	Arguments []string `json:"arguments,omitempty" mapstructure:"arguments" yaml:"arguments,omitempty"`
+	// JSON paths of response fields to be decrypted, e.g. ssn, $[*].email
+	Response []string `json:"response,omitempty" mapstructure:"response" yaml:"response,omitempty"`
+	// Target operations to be applied. Apply to all operations if empty
+	Targets []string `json:"targets,omitempty" mapstructure:"targets" yaml:"targets,omitempty"`
+}
+
+// FieldEncryptionKMSConfig represents AWS KMS settings to decrypt the data key of field encryption.
+type FieldEncryptionKMSConfig struct {
+	// The ID or ARN of the KMS key which encrypted the data key. KMS rejects the data key if it was encrypted by another key
+	KeyID *utils.EnvString `json:"keyId,omitempty" mapstructure:"keyId" yaml:"keyId,omitempty"`
+	// The encryption context which was used to encrypt the data key
+	EncryptionContext map[string]string `json:"encryptionContext,omitempty" mapstructure:"encryptionContext" yaml:"encryptionContext,omitempty"`
+	// Default to the AWS_ACCESS_KEY_ID environment variable
+	AccessKeyID *utils.EnvString `json:"accessKeyId,omitempty" mapstructure:"accessKeyId" yaml:"accessKeyId,omitempty"`
+	// Default to the AWS_SECRET_ACCESS_KEY environment variable
+	SecretAccessKey *utils.EnvString `json:"secretAccessKey,omitempty" mapstructure:"secretAccessKey" yaml:"secretAccessKey,omitempty"`
+	// Default to the AWS_SESSION_TOKEN environment variable
+	SessionToken *utils.EnvString `json:"sessionToken,omitempty" mapstructure:"sessionToken" yaml:"sessionToken,omitempty"`
+	// The region of the KMS key if the key ID isn't an ARN. Default to the AWS_REGION environment variable, or us-east-1
+	Region *utils.EnvString `json:"region,omitempty" mapstructure:"region" yaml:"region,omitempty"`
+	// The custom endpoint, e.g. http://localstack:4566. Default to the AWS_ENDPOINT_URL_KMS environment variable
+	Endpoint *utils.EnvString `json:"endpoint,omitempty" mapstructure:"endpoint" yaml:"endpoint,omitempty"`
+}
+
+// Validate checks if the configuration is valid and returns parsed argument paths, response paths and target expressions.
+func (fec FieldEncryptionConfig) Validate() ([]*jsonpath.Path, []*jsonpath.Path, []regexp.Regexp, error) {
+	if fec.Algorithm != "" && !fec.Algorithm.IsValid() {
+		return nil, nil, nil, fmt.Errorf("invalid FieldEncryptionAlgorithm. Expected %+v, got <%s>", fieldEncryptionAlgorithm_enums, fec.Algorithm)
+	}
+
+	if len(fec.Arguments) == 0 && len(fec.Response) == 0 {
+		return nil, nil, nil, errors.New("require at least one argument or response field in FieldEncryptionConfig")
+	}
+
+	arguments := make([]*jsonpath.Path, len(fec.Arguments))
+	for i, rawPath := range fec.Arguments {
+		jsonPath, err := ParseFieldJSONPath(rawPath)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("arguments[%d]: %w", i, err)
+		}
+
+		if _, ok := jsonPath.Query().Segments()[0].Selectors()[0].(spec.Name); !ok {
+			return nil, nil, nil, fmt.Errorf("arguments[%d]: invalid json path. The root selector must be an argument name", i)
+		}
+
+		arguments[i] = jsonPath
+	}
+
+	response := make([]*jsonpath.Path, len(fec.Response))
+	for i, rawPath := range fec.Response {
+		jsonPath, err := ParseFieldJSONPath(rawPath)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("response[%d]: %w", i, err)
+		}
+
+		response[i] = jsonPath
+	}
+
+	targets := make([]regexp.Regexp, len(fec.Targets))
+	for i, target := range fec.Targets {
+		rg, err := regexp.Compile(target)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to compile field encryption target expression %s: %w", target, err)
+		}
+		targets[i] = *rg
+	}
+
+	return arguments, response, targets, nil
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"slices"
+	"strings"
 
 	"github.com/invopop/jsonschema"
 )
@@ -19,9 +20,16 @@ const (
 	OAS3Spec      SchemaSpecType = "oas3"
 	OAS2Spec      SchemaSpecType = "oas2"
 	NDCSpec       SchemaSpecType = "ndc"
+	GraphQLSpec   SchemaSpecType = "graphql"
+	WSDLSpec      SchemaSpecType = "wsdl"
+	PostmanSpec   SchemaSpecType = "postman"
+	HARSpec       SchemaSpecType = "har"
+	InsomniaSpec  SchemaSpecType = "insomnia"
+	ODataSpec     SchemaSpecType = "odata"
+	AsyncAPISpec  SchemaSpecType = "asyncapi"
 )
 
-var schemaSpecType_enums = []SchemaSpecType{OAS3Spec, OAS2Spec, OpenAPIv3Spec, OpenAPIv2Spec, NDCSpec}
+var schemaSpecType_enums = []SchemaSpecType{OAS3Spec, OAS2Spec, OpenAPIv3Spec, OpenAPIv2Spec, NDCSpec, GraphQLSpec, WSDLSpec, PostmanSpec, HARSpec, InsomniaSpec, ODataSpec, AsyncAPISpec}
 
 // JSONSchema is used to generate a custom jsonschema
 func (j SchemaSpecType) JSONSchema() *jsonschema.Schema {
@@ -221,11 +229,16 @@ const (
 	ContentTypeJSON              = "application/json"
 	ContentTypeNdJSON            = "application/x-ndjson"
 	ContentTypeXML               = "application/xml"
+	ContentTypeTextXML           = "text/xml"
+	ContentTypeSOAPXML           = "application/soap+xml"
 	ContentTypeFormURLEncoded    = "application/x-www-form-urlencoded"
 	ContentTypeMultipartFormData = "multipart/form-data"
 	ContentTypeTextPlain         = "text/plain"
 	ContentTypeTextHTML          = "text/html"
+	ContentTypeCSV               = "text/csv"
 	ContentTypeOctetStream       = "application/octet-stream"
+	ContentTypeMsgPack           = "application/msgpack"
+	ContentTypeProtobuf          = "application/protobuf"
 )
 
 // ParameterEncodingStyle represents the encoding style of the parameter.
@@ -301,3 +314,821 @@ func ParseParameterEncodingStyle(input string) (ParameterEncodingStyle, error) {
 
 	return result, nil
 }
+
+// ServerSelectionStrategy represents the strategy to select a server for single-target requests.
+type ServerSelectionStrategy string
+
+const (
+	// ServerSelectionRandom selects a random server.
+	ServerSelectionRandom ServerSelectionStrategy = "random"
+	// ServerSelectionFastest selects the server with the lowest recent latency, with periodic exploration of other servers.
+	ServerSelectionFastest ServerSelectionStrategy = "fastest"
+	// ServerSelectionRoundRobin selects healthy servers in turn.
+	ServerSelectionRoundRobin ServerSelectionStrategy = "roundRobin"
+	// ServerSelectionWeighted selects healthy servers in turn, proportionally to weights of servers.
+	ServerSelectionWeighted ServerSelectionStrategy = "weighted"
+)
+
+var serverSelectionStrategy_enums = []ServerSelectionStrategy{ServerSelectionRandom, ServerSelectionFastest, ServerSelectionRoundRobin, ServerSelectionWeighted}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j ServerSelectionStrategy) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(serverSelectionStrategy_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *ServerSelectionStrategy) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseServerSelectionStrategy(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the strategy enum is valid
+func (j ServerSelectionStrategy) IsValid() bool {
+	return slices.Contains(serverSelectionStrategy_enums, j)
+}
+
+// ParseServerSelectionStrategy parses ServerSelectionStrategy from string
+func ParseServerSelectionStrategy(input string) (ServerSelectionStrategy, error) {
+	result := ServerSelectionStrategy(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid ServerSelectionStrategy. Expected %+v, got <%s>", serverSelectionStrategy_enums, input)
+	}
+
+	return result, nil
+}
+
+// DistributedExecutionStrategy represents the strategy to execute requests of distributed operations.
+type DistributedExecutionStrategy string
+
+const (
+	// DistributedExecutionAll sends requests to all servers and returns all results.
+	DistributedExecutionAll DistributedExecutionStrategy = "all"
+	// DistributedExecutionFailover tries servers in priority order until one succeeds.
+	DistributedExecutionFailover DistributedExecutionStrategy = "failover"
+	// DistributedExecutionRace sends requests to all servers, returns the first successful response and cancels the rest.
+	DistributedExecutionRace DistributedExecutionStrategy = "race"
+)
+
+var distributedExecutionStrategy_enums = []DistributedExecutionStrategy{DistributedExecutionAll, DistributedExecutionFailover, DistributedExecutionRace}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j DistributedExecutionStrategy) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(distributedExecutionStrategy_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *DistributedExecutionStrategy) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseDistributedExecutionStrategy(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the strategy enum is valid
+func (j DistributedExecutionStrategy) IsValid() bool {
+	return slices.Contains(distributedExecutionStrategy_enums, j)
+}
+
+// ParseDistributedExecutionStrategy parses DistributedExecutionStrategy from string
+func ParseDistributedExecutionStrategy(input string) (DistributedExecutionStrategy, error) {
+	result := DistributedExecutionStrategy(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid DistributedExecutionStrategy. Expected %+v, got <%s>", distributedExecutionStrategy_enums, input)
+	}
+
+	return result, nil
+}
+
+// ResponseStreamMode represents the mode to read the response body in chunks.
+type ResponseStreamMode string
+
+const (
+	// ResponseStreamSSE reads server-sent events of text/event-stream responses into an array of event objects.
+	ResponseStreamSSE ResponseStreamMode = "sse"
+	// ResponseStreamBinary reads binary responses into an array of base64-encoded chunks.
+	ResponseStreamBinary ResponseStreamMode = "binary"
+)
+
+var responseStreamMode_enums = []ResponseStreamMode{ResponseStreamSSE, ResponseStreamBinary}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j ResponseStreamMode) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(responseStreamMode_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *ResponseStreamMode) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseResponseStreamMode(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the stream mode enum is valid
+func (j ResponseStreamMode) IsValid() bool {
+	return slices.Contains(responseStreamMode_enums, j)
+}
+
+// ParseResponseStreamMode parses ResponseStreamMode from string
+func ParseResponseStreamMode(input string) (ResponseStreamMode, error) {
+	result := ResponseStreamMode(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid ResponseStreamMode. Expected %+v, got <%s>", responseStreamMode_enums, input)
+	}
+
+	return result, nil
+}
+
+// FieldEncryptionAlgorithm represents the algorithm to encrypt and decrypt field values.
+type FieldEncryptionAlgorithm string
+
+const (
+	// FieldEncryptionAESGCM uses AES in Galois/Counter mode with a 128, 192 or 256-bit key.
+	FieldEncryptionAESGCM FieldEncryptionAlgorithm = "aes-gcm"
+)
+
+var fieldEncryptionAlgorithm_enums = []FieldEncryptionAlgorithm{FieldEncryptionAESGCM}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j FieldEncryptionAlgorithm) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(fieldEncryptionAlgorithm_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *FieldEncryptionAlgorithm) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseFieldEncryptionAlgorithm(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the algorithm enum is valid
+func (j FieldEncryptionAlgorithm) IsValid() bool {
+	return slices.Contains(fieldEncryptionAlgorithm_enums, j)
+}
+
+// ParseFieldEncryptionAlgorithm parses FieldEncryptionAlgorithm from string
+func ParseFieldEncryptionAlgorithm(input string) (FieldEncryptionAlgorithm, error) {
+	result := FieldEncryptionAlgorithm(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid FieldEncryptionAlgorithm. Expected %+v, got <%s>", fieldEncryptionAlgorithm_enums, input)
+	}
+
+	return result, nil
+}
+
+// DataClassification represents the sensitivity classification of a field, e.g. personally identifiable information.
+type DataClassification string
+
+const (
+	// DataClassificationPII marks personally identifiable information.
+	DataClassificationPII DataClassification = "pii"
+	// DataClassificationSensitive marks sensitive information that isn't PII, e.g. secrets or financial data.
+	DataClassificationSensitive DataClassification = "sensitive"
+)
+
+var dataClassification_enums = []DataClassification{DataClassificationPII, DataClassificationSensitive}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j DataClassification) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(dataClassification_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *DataClassification) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseDataClassification(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the classification enum is valid
+func (j DataClassification) IsValid() bool {
+	return slices.Contains(dataClassification_enums, j)
+}
+
+// Annotate prepends the classification label to the description, e.g. [PII] Email address of the user.
+// The NDC schema doesn't have a metadata field, so the label is surfaced to the engine through the description.
+func (j DataClassification) Annotate(description *string) *string {
+	if j == "" {
+		return description
+	}
+
+	label := "[" + strings.ToUpper(string(j)) + "]"
+	if description == nil || *description == "" {
+		return &label
+	}
+
+	result := label + " " + *description
+
+	return &result
+}
+
+// ParseDataClassification parses DataClassification from string
+func ParseDataClassification(input string) (DataClassification, error) {
+	result := DataClassification(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid DataClassification. Expected %+v, got <%s>", dataClassification_enums, input)
+	}
+
+	return result, nil
+}
+
+// FieldSelectionStyle represents the syntax of the field selection query parameter.
+type FieldSelectionStyle string
+
+const (
+	// FieldSelectionComma joins names of top-level fields with commas, e.g. id,name,owner.
+	FieldSelectionComma FieldSelectionStyle = "comma"
+	// FieldSelectionDot joins paths of selected fields in dot notation with commas, e.g. id,owner.name.
+	FieldSelectionDot FieldSelectionStyle = "dot"
+	// FieldSelectionParentheses wraps nested selections in parentheses, e.g. id,owner(name).
+	FieldSelectionParentheses FieldSelectionStyle = "parentheses"
+)
+
+var fieldSelectionStyle_enums = []FieldSelectionStyle{FieldSelectionComma, FieldSelectionDot, FieldSelectionParentheses}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j FieldSelectionStyle) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(fieldSelectionStyle_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *FieldSelectionStyle) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseFieldSelectionStyle(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the style enum is valid
+func (j FieldSelectionStyle) IsValid() bool {
+	return slices.Contains(fieldSelectionStyle_enums, j)
+}
+
+// ParseFieldSelectionStyle parses FieldSelectionStyle from string
+func ParseFieldSelectionStyle(input string) (FieldSelectionStyle, error) {
+	result := FieldSelectionStyle(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid FieldSelectionStyle. Expected %+v, got <%s>", fieldSelectionStyle_enums, input)
+	}
+
+	return result, nil
+}
+
+// PaginationType represents the pagination strategy of an operation.
+type PaginationType string
+
+const (
+	// PaginationOffset sends the number of skipped items, e.g. ?offset=20&limit=10.
+	PaginationOffset PaginationType = "offset"
+	// PaginationPage sends the page number and the page size, e.g. ?page=3&per_page=10.
+	PaginationPage PaginationType = "page"
+	// PaginationCursor sends the cursor token which is read from the previous response body.
+	PaginationCursor PaginationType = "cursor"
+	// PaginationLink follows the next URL of the Link response header.
+	PaginationLink PaginationType = "link"
+)
+
+var paginationType_enums = []PaginationType{PaginationOffset, PaginationPage, PaginationCursor, PaginationLink}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j PaginationType) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(paginationType_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *PaginationType) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParsePaginationType(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the pagination type enum is valid
+func (j PaginationType) IsValid() bool {
+	return slices.Contains(paginationType_enums, j)
+}
+
+// ParsePaginationType parses PaginationType from string
+func ParsePaginationType(input string) (PaginationType, error) {
+	result := PaginationType(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid PaginationType. Expected %+v, got <%s>", paginationType_enums, input)
+	}
+
+	return result, nil
+}
+
+// FilterStyle represents the syntax of filter query parameters.
+type FilterStyle string
+
+const (
+	// FilterPlain sends the value as it is, e.g. ?status=available. Only equality and in operators are supported.
+	FilterPlain FilterStyle = "plain"
+	// FilterOperator prefixes the value with the operator, e.g. ?status=eq.available or ?status=in.(available,sold).
+	FilterOperator FilterStyle = "operator"
+	// FilterOData combines predicates into the $filter query option of OData services, e.g. ?$filter=Status eq 'available' and Price gt 10.
+	FilterOData FilterStyle = "odata"
+)
+
+var filterStyle_enums = []FilterStyle{FilterPlain, FilterOperator, FilterOData}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j FilterStyle) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(filterStyle_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *FilterStyle) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseFilterStyle(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the filter style enum is valid
+func (j FilterStyle) IsValid() bool {
+	return slices.Contains(filterStyle_enums, j)
+}
+
+// ParseFilterStyle parses FilterStyle from string
+func ParseFilterStyle(input string) (FilterStyle, error) {
+	result := FilterStyle(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid FilterStyle. Expected %+v, got <%s>", filterStyle_enums, input)
+	}
+
+	return result, nil
+}
+
+// OrderByStyle represents the syntax of the sort query parameter.
+type OrderByStyle string
+
+const (
+	// OrderByOData appends the direction to each field, e.g. ?$orderby=Name asc,Price desc.
+	OrderByOData OrderByStyle = "odata"
+	// OrderBySign prefixes fields of the descending order with a minus sign, e.g. ?sort=name,-price.
+	OrderBySign OrderByStyle = "sign"
+)
+
+var orderByStyle_enums = []OrderByStyle{OrderByOData, OrderBySign}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j OrderByStyle) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(orderByStyle_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *OrderByStyle) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseOrderByStyle(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the order by style enum is valid
+func (j OrderByStyle) IsValid() bool {
+	return slices.Contains(orderByStyle_enums, j)
+}
+
+// ParseOrderByStyle parses OrderByStyle from string
+func ParseOrderByStyle(input string) (OrderByStyle, error) {
+	result := OrderByStyle(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid OrderByStyle. Expected %+v, got <%s>", orderByStyle_enums, input)
+	}
+
+	return result, nil
+}
+
+// RequestProtocol represents the protocol of requests to the upstream service.
+type RequestProtocol string
+
+const (
+	// ProtocolHTTP sends HTTP requests. This is the default protocol.
+	ProtocolHTTP RequestProtocol = "http"
+	// ProtocolGRPC transcodes JSON arguments to protobuf messages and calls a unary gRPC method.
+	ProtocolGRPC RequestProtocol = "grpc"
+)
+
+var requestProtocol_enums = []RequestProtocol{ProtocolHTTP, ProtocolGRPC}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j RequestProtocol) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(requestProtocol_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *RequestProtocol) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseRequestProtocol(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the request protocol enum is valid
+func (j RequestProtocol) IsValid() bool {
+	return slices.Contains(requestProtocol_enums, j)
+}
+
+// ParseRequestProtocol parses RequestProtocol from string
+func ParseRequestProtocol(input string) (RequestProtocol, error) {
+	result := RequestProtocol(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid RequestProtocol. Expected %+v, got <%s>", requestProtocol_enums, input)
+	}
+
+	return result, nil
+}
+
+// SOAPVersion represents the version of the SOAP protocol.
+type SOAPVersion string
+
+const (
+	// SOAPVersion11 sends the SOAP 1.1 envelope with the text/xml content type and the SOAPAction header.
+	SOAPVersion11 SOAPVersion = "1.1"
+	// SOAPVersion12 sends the SOAP 1.2 envelope with the application/soap+xml content type and the action parameter.
+	SOAPVersion12 SOAPVersion = "1.2"
+)
+
+var soapVersion_enums = []SOAPVersion{SOAPVersion11, SOAPVersion12}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j SOAPVersion) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(soapVersion_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *SOAPVersion) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseSOAPVersion(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the SOAP version enum is valid
+func (j SOAPVersion) IsValid() bool {
+	return slices.Contains(soapVersion_enums, j)
+}
+
+// ParseSOAPVersion parses SOAPVersion from string
+func ParseSOAPVersion(input string) (SOAPVersion, error) {
+	result := SOAPVersion(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid SOAPVersion. Expected %+v, got <%s>", soapVersion_enums, input)
+	}
+
+	return result, nil
+}
+
+// ResponseValidationMode represents the behavior when the response body violates the schema.
+type ResponseValidationMode string
+
+const (
+	// ResponseValidationError fails the request with violations in the error details.
+	ResponseValidationError ResponseValidationMode = "error"
+	// ResponseValidationWarn logs violations and records them in the trace span, then returns the response as it is.
+	ResponseValidationWarn ResponseValidationMode = "warn"
+)
+
+var responseValidationMode_enums = []ResponseValidationMode{ResponseValidationError, ResponseValidationWarn}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j ResponseValidationMode) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(responseValidationMode_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *ResponseValidationMode) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseResponseValidationMode(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the response validation mode enum is valid
+func (j ResponseValidationMode) IsValid() bool {
+	return slices.Contains(responseValidationMode_enums, j)
+}
+
+// ParseResponseValidationMode parses ResponseValidationMode from string
+func ParseResponseValidationMode(input string) (ResponseValidationMode, error) {
+	result := ResponseValidationMode(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid ResponseValidationMode. Expected %+v, got <%s>", responseValidationMode_enums, input)
+	}
+
+	return result, nil
+}
+
+// RetryBackoffStrategy represents the strategy to compute the delay between retries.
+type RetryBackoffStrategy string
+
+const (
+	// RetryBackoffFixed waits the same delay between retries.
+	RetryBackoffFixed RetryBackoffStrategy = "fixed"
+	// RetryBackoffExponential multiplies the delay after each retry.
+	RetryBackoffExponential RetryBackoffStrategy = "exponential"
+)
+
+var retryBackoffStrategy_enums = []RetryBackoffStrategy{RetryBackoffFixed, RetryBackoffExponential}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j RetryBackoffStrategy) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(retryBackoffStrategy_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *RetryBackoffStrategy) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseRetryBackoffStrategy(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the retry backoff strategy enum is valid
+func (j RetryBackoffStrategy) IsValid() bool {
+	return slices.Contains(retryBackoffStrategy_enums, j)
+}
+
+// ParseRetryBackoffStrategy parses RetryBackoffStrategy from string
+func ParseRetryBackoffStrategy(input string) (RetryBackoffStrategy, error) {
+	result := RetryBackoffStrategy(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid RetryBackoffStrategy. Expected %+v, got <%s>", retryBackoffStrategy_enums, input)
+	}
+
+	return result, nil
+}
+
+// RequestCompression represents the encoding to compress request bodies automatically.
+type RequestCompression string
+
+const (
+	// RequestCompressionGzip compresses request bodies with the gzip encoding.
+	RequestCompressionGzip RequestCompression = "gzip"
+	// RequestCompressionDeflate compresses request bodies with the deflate encoding.
+	RequestCompressionDeflate RequestCompression = "deflate"
+	// RequestCompressionBrotli compresses request bodies with the brotli encoding.
+	RequestCompressionBrotli RequestCompression = "br"
+	// RequestCompressionZstd compresses request bodies with the zstd encoding.
+	RequestCompressionZstd RequestCompression = "zstd"
+)
+
+var requestCompression_enums = []RequestCompression{
+	RequestCompressionGzip,
+	RequestCompressionDeflate,
+	RequestCompressionBrotli,
+	RequestCompressionZstd,
+}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j RequestCompression) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(requestCompression_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *RequestCompression) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseRequestCompression(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the request compression enum is valid
+func (j RequestCompression) IsValid() bool {
+	return slices.Contains(requestCompression_enums, j)
+}
+
+// ParseRequestCompression parses RequestCompression from string
+func ParseRequestCompression(input string) (RequestCompression, error) {
+	result := RequestCompression(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid RequestCompression. Expected %+v, got <%s>", requestCompression_enums, input)
+	}
+
+	return result, nil
+}
+
+// ResponseEnvelope represents the standard envelope format of response bodies to be unwrapped.
+type ResponseEnvelope string
+
+const (
+	// ResponseEnvelopeJSONAPI unwraps JSON:API documents. Attributes and relationships of resources in data are flattened into objects.
+	ResponseEnvelopeJSONAPI ResponseEnvelope = "jsonapi"
+	// ResponseEnvelopeHAL unwraps HAL resources. Links are removed and embedded resources are lifted into objects.
+	ResponseEnvelopeHAL ResponseEnvelope = "hal"
+)
+
+var responseEnvelope_enums = []ResponseEnvelope{ResponseEnvelopeJSONAPI, ResponseEnvelopeHAL}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j ResponseEnvelope) JSONSchema() *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Type: "string",
+		Enum: toAnySlice(responseEnvelope_enums),
+	}
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *ResponseEnvelope) UnmarshalJSON(b []byte) error {
+	var rawResult string
+	if err := json.Unmarshal(b, &rawResult); err != nil {
+		return err
+	}
+
+	result, err := ParseResponseEnvelope(rawResult)
+	if err != nil {
+		return err
+	}
+
+	*j = result
+
+	return nil
+}
+
+// IsValid checks if the response envelope enum is valid
+func (j ResponseEnvelope) IsValid() bool {
+	return slices.Contains(responseEnvelope_enums, j)
+}
+
+// ParseResponseEnvelope parses ResponseEnvelope from string
+func ParseResponseEnvelope(input string) (ResponseEnvelope, error) {
+	result := ResponseEnvelope(input)
+	if !result.IsValid() {
+		return result, fmt.Errorf("invalid ResponseEnvelope. Expected %+v, got <%s>", responseEnvelope_enums, input)
+	}
+
+	return result, nil
+}
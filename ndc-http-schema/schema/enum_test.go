@@ -61,3 +61,25 @@ func TestParameterEncodingStyle(t *testing.T) {
 		t.Fatalf("expected string, got: %s", got.JSONSchema().Type)
 	}
 }
+
+func TestDataClassification(t *testing.T) {
+	rawValue := "pii"
+	var got DataClassification
+	if err := json.Unmarshal([]byte(fmt.Sprintf(`"%s"`, rawValue)), &got); err != nil {
+		t.Fatal(err.Error())
+	}
+	if got != DataClassification(rawValue) {
+		t.Fatalf("expected %s, got: %s", rawValue, got)
+	}
+
+	description := "Email address"
+	if annotated := got.Annotate(&description); annotated == nil || *annotated != "[PII] Email address" {
+		t.Fatalf("expected [PII] Email address, got: %v", annotated)
+	}
+	if annotated := DataClassificationSensitive.Annotate(nil); annotated == nil || *annotated != "[SENSITIVE]" {
+		t.Fatalf("expected [SENSITIVE], got: %v", annotated)
+	}
+	if err := json.Unmarshal([]byte(`"secret"`), &got); err == nil {
+		t.Fatal("expected invalid DataClassification error, got nil")
+	}
+}
@@ -0,0 +1,144 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/theory/jsonpath"
+	"github.com/theory/jsonpath/spec"
+)
+
+// ParseFieldJSONPath parses the json path of a field, e.g. body.contacts[*].email or $.data.items[0].
+// The root selector is optional. Only name, index and wildcard selectors are supported.
+func ParseFieldJSONPath(rawPath string) (*jsonpath.Path, error) {
+	if rawPath == "" {
+		return nil, errors.New("json path is empty")
+	}
+
+	switch rawPath[0] {
+	case '$':
+	case '.', '[':
+		rawPath = "$" + rawPath
+	default:
+		rawPath = "$." + rawPath
+	}
+
+	jsonPath, err := jsonpath.Parse(rawPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse the json path: %w", err)
+	}
+
+	segments := jsonPath.Query().Segments()
+	if len(segments) == 0 {
+		return nil, errors.New("json path must select a field")
+	}
+
+	for _, segment := range segments {
+		if segment.IsDescendant() || len(segment.Selectors()) != 1 {
+			return nil, fmt.Errorf("unsupported json path %s. Descendant segments and multiple selectors aren't supported", rawPath)
+		}
+
+		switch segment.Selectors()[0].(type) {
+		case spec.Name, spec.Index, spec.WildcardSelector:
+		default:
+			return nil, fmt.Errorf("unsupported json path selector %s", segment.Selectors()[0].String())
+		}
+	}
+
+	return jsonPath, nil
+}
+
+// UpdateFieldsByJSONPath replaces values which are selected by segments of a field json path with results of the update function.
+// Maps and slices in the path are copied so the input isn't mutated. Null and missing values are skipped.
+// If the create flag is enabled, missing objects of name selectors are created and null values are passed to the update function.
+func UpdateFieldsByJSONPath(value any, segments []*spec.Segment, create bool, update func(value any) (any, error)) (any, error) {
+	if value == nil && !create {
+		return nil, nil
+	}
+
+	if len(segments) == 0 {
+		return update(value)
+	}
+
+	switch selector := segments[0].Selectors()[0].(type) {
+	case spec.Name:
+		object, ok := value.(map[string]any)
+		if !ok {
+			if value != nil {
+				return value, nil
+			}
+
+			object = map[string]any{}
+		}
+
+		fieldValue, ok := object[string(selector)]
+		if (!ok || fieldValue == nil) && !create {
+			return value, nil
+		}
+
+		newValue, err := UpdateFieldsByJSONPath(fieldValue, segments[1:], create, update)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", string(selector), err)
+		}
+
+		result := maps.Clone(object)
+		result[string(selector)] = newValue
+
+		return result, nil
+	case spec.Index:
+		array, ok := value.([]any)
+		if !ok {
+			return value, nil
+		}
+
+		index := int(selector)
+		if index < 0 {
+			index += len(array)
+		}
+
+		if index < 0 || index >= len(array) {
+			return value, nil
+		}
+
+		newValue, err := UpdateFieldsByJSONPath(array[index], segments[1:], create, update)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", index, err)
+		}
+
+		result := slices.Clone(array)
+		result[index] = newValue
+
+		return result, nil
+	case spec.WildcardSelector:
+		switch collection := value.(type) {
+		case []any:
+			result := make([]any, len(collection))
+			for i, item := range collection {
+				newValue, err := UpdateFieldsByJSONPath(item, segments[1:], create, update)
+				if err != nil {
+					return nil, fmt.Errorf("[%d]: %w", i, err)
+				}
+				result[i] = newValue
+			}
+
+			return result, nil
+		case map[string]any:
+			result := make(map[string]any)
+			for key, item := range collection {
+				newValue, err := UpdateFieldsByJSONPath(item, segments[1:], create, update)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", key, err)
+				}
+				result[key] = newValue
+			}
+
+			return result, nil
+		default:
+			return value, nil
+		}
+	default:
+		return nil, errors.New("unsupported json path selector: " + selector.String())
+	}
+}
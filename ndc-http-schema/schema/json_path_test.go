@@ -0,0 +1,124 @@
+package schema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestParseFieldJSONPath(t *testing.T) {
+	for _, rawPath := range []string{"body.ssn", "$.body.contacts[*].email", "[0].id", ".data"} {
+		_, err := ParseFieldJSONPath(rawPath)
+		assert.NilError(t, err, rawPath)
+	}
+
+	for rawPath, expected := range map[string]string{
+		"":               "json path is empty",
+		"$":              "json path must select a field",
+		"$..email":       "Descendant segments and multiple selectors aren't supported",
+		"body[0,1]":      "Descendant segments and multiple selectors aren't supported",
+		"body[?@.email]": "unsupported json path selector",
+	} {
+		_, err := ParseFieldJSONPath(rawPath)
+		assert.ErrorContains(t, err, expected, rawPath)
+	}
+}
+
+func TestUpdateFieldsByJSONPath(t *testing.T) {
+	upper := func(value any) (any, error) {
+		str, ok := value.(string)
+		if !ok {
+			return nil, errors.New("expected a string")
+		}
+
+		return strings.ToUpper(str), nil
+	}
+
+	input := map[string]any{
+		"name": "alice",
+		"contacts": []any{
+			map[string]any{"email": "a@example.com"},
+			map[string]any{"email": nil},
+			map[string]any{},
+		},
+		"tags": map[string]any{"a": "x", "b": "y"},
+	}
+
+	testCases := []struct {
+		Path     string
+		Create   bool
+		Update   func(value any) (any, error)
+		Expected any
+		Error    string
+	}{
+		{
+			Path: "contacts[*].email",
+			Expected: map[string]any{
+				"name": "alice",
+				"contacts": []any{
+					map[string]any{"email": "A@EXAMPLE.COM"},
+					map[string]any{"email": nil},
+					map[string]any{},
+				},
+				"tags": map[string]any{"a": "x", "b": "y"},
+			},
+		},
+		{
+			Path: "tags.*",
+			Expected: map[string]any{
+				"name":     "alice",
+				"contacts": input["contacts"],
+				"tags":     map[string]any{"a": "X", "b": "Y"},
+			},
+		},
+		{
+			Path:     "contacts[-3].missing.email",
+			Expected: input,
+		},
+		{
+			Path:   "meta.source",
+			Create: true,
+			Update: func(value any) (any, error) {
+				assert.Assert(t, value == nil)
+
+				return "api", nil
+			},
+			Expected: map[string]any{
+				"name":     "alice",
+				"contacts": input["contacts"],
+				"tags":     input["tags"],
+				"meta":     map[string]any{"source": "api"},
+			},
+		},
+		{
+			Path:  "contacts[0]",
+			Error: "contacts: [0]: expected a string",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.Path, func(t *testing.T) {
+			jsonPath, err := ParseFieldJSONPath(tc.Path)
+			assert.NilError(t, err)
+
+			update := tc.Update
+			if update == nil {
+				update = upper
+			}
+
+			result, err := UpdateFieldsByJSONPath(input, jsonPath.Query().Segments(), tc.Create, update)
+			if tc.Error != "" {
+				assert.ErrorContains(t, err, tc.Error)
+
+				return
+			}
+
+			assert.NilError(t, err)
+			assert.DeepEqual(t, tc.Expected, result)
+			// the input isn't mutated
+			assert.Equal(t, "a@example.com", input["contacts"].([]any)[0].(map[string]any)["email"])
+		})
+	}
+}
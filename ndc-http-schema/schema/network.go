@@ -0,0 +1,397 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/hasura/ndc-sdk-go/utils"
+)
+
+// DNSConfig represents custom DNS resolution settings of a server.
+type DNSConfig struct {
+	// Static host to IP address overrides, similar to curl's --resolve option.
+	// Keys are host names or host:port pairs, e.g. api.example.com or api.example.com:443.
+	Hosts map[string]utils.EnvString `json:"hosts,omitempty" mapstructure:"hosts" yaml:"hosts,omitempty"`
+	// Addresses of custom DNS servers, e.g. 10.0.0.2 or 10.0.0.2:53. Servers are tried in order.
+	Servers []utils.EnvString `json:"servers,omitempty" mapstructure:"servers" yaml:"servers,omitempty"`
+}
+
+// Validate if the current instance is valid.
+func (dc DNSConfig) Validate() error {
+	for host := range dc.Hosts {
+		if host == "" {
+			return errors.New("hosts: host name must not be empty")
+		}
+	}
+
+	return nil
+}
+
+// GetHosts evaluates and validates static host overrides.
+func (dc DNSConfig) GetHosts() (map[string]string, error) {
+	results := make(map[string]string)
+	for host, envIP := range dc.Hosts {
+		ip, err := envIP.GetOrDefault("")
+		if err != nil {
+			return nil, fmt.Errorf("hosts[%s]: %w", host, err)
+		}
+
+		if ip == "" {
+			continue
+		}
+
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("hosts[%s]: invalid IP address %s", host, ip)
+		}
+
+		results[host] = ip
+	}
+
+	return results, nil
+}
+
+// GetServers evaluates addresses of custom DNS servers. The default port 53 is added if the address doesn't have a port.
+func (dc DNSConfig) GetServers() ([]string, error) {
+	var results []string
+	for i, envServer := range dc.Servers {
+		server, err := envServer.GetOrDefault("")
+		if err != nil {
+			return nil, fmt.Errorf("servers[%d]: %w", i, err)
+		}
+
+		if server == "" {
+			continue
+		}
+
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+
+		results = append(results, server)
+	}
+
+	return results, nil
+}
+
+// DialerConfig represents dialing options of connections to a server.
+type DialerConfig struct {
+	// Dial IPv4 addresses first and fall back to IPv6 addresses if failed.
+	// Useful if IPv6 routes are broken in the environment.
+	PreferIPv4 *utils.EnvBool `json:"preferIPv4,omitempty" mapstructure:"preferIPv4" yaml:"preferIPv4,omitempty"`
+	// Dial IPv6 addresses first and fall back to IPv4 addresses if failed.
+	// Useful in IPv6-only environments.
+	PreferIPv6 *utils.EnvBool `json:"preferIPv6,omitempty" mapstructure:"preferIPv6" yaml:"preferIPv6,omitempty"`
+	// The delay in milliseconds to wait for the primary address family before spawning a fallback connection (Happy Eyeballs).
+	// Default to 300ms. A negative value disables the fallback.
+	FallbackDelay *utils.EnvInt `json:"fallbackDelay,omitempty" mapstructure:"fallbackDelay" yaml:"fallbackDelay,omitempty"`
+}
+
+// GetPreferredIPVersion evaluates the preferred IP version. Returns 0 if there is no preference.
+func (dc DialerConfig) GetPreferredIPVersion() (int, error) {
+	var preferIPv4, preferIPv6 bool
+	var err error
+	if dc.PreferIPv4 != nil {
+		preferIPv4, err = dc.PreferIPv4.GetOrDefault(false)
+		if err != nil {
+			return 0, fmt.Errorf("preferIPv4: %w", err)
+		}
+	}
+
+	if dc.PreferIPv6 != nil {
+		preferIPv6, err = dc.PreferIPv6.GetOrDefault(false)
+		if err != nil {
+			return 0, fmt.Errorf("preferIPv6: %w", err)
+		}
+	}
+
+	switch {
+	case preferIPv4 && preferIPv6:
+		return 0, errors.New("preferIPv4 and preferIPv6 can't be enabled at the same time")
+	case preferIPv4:
+		return 4, nil
+	case preferIPv6:
+		return 6, nil
+	default:
+		return 0, nil
+	}
+}
+
+// GetFallbackDelay evaluates the fallback delay in milliseconds. Returns nil if not set.
+func (dc DialerConfig) GetFallbackDelay() (*int64, error) {
+	if dc.FallbackDelay == nil {
+		return nil, nil
+	}
+
+	delay, err := dc.FallbackDelay.Get()
+	if err != nil {
+		return nil, fmt.Errorf("fallbackDelay: %w", err)
+	}
+
+	return &delay, nil
+}
+
+// ConnectionConfig represents connection pooling and HTTP client tuning options of upstream servers.
+// Unset fields inherit Go defaults.
+type ConnectionConfig struct {
+	// The maximum number of idle (keep-alive) connections across all hosts. Zero means no limit.
+	MaxIdleConns *utils.EnvInt `json:"maxIdleConns,omitempty" mapstructure:"maxIdleConns" yaml:"maxIdleConns,omitempty"`
+	// The maximum number of idle (keep-alive) connections to keep per host. Default to 2.
+	MaxIdleConnsPerHost *utils.EnvInt `json:"maxIdleConnsPerHost,omitempty" mapstructure:"maxIdleConnsPerHost" yaml:"maxIdleConnsPerHost,omitempty"`
+	// The maximum number of connections per host, including connections in the dialing, active, and idle states. Zero means no limit.
+	MaxConnsPerHost *utils.EnvInt `json:"maxConnsPerHost,omitempty" mapstructure:"maxConnsPerHost" yaml:"maxConnsPerHost,omitempty"`
+	// The maximum amount of time in seconds an idle (keep-alive) connection will remain idle before closing itself. Zero means no limit.
+	IdleConnTimeout *utils.EnvInt `json:"idleConnTimeout,omitempty" mapstructure:"idleConnTimeout" yaml:"idleConnTimeout,omitempty"`
+	// Disable HTTP keep-alives and only use the connection to the server for a single HTTP request.
+	DisableKeepAlives *utils.EnvBool `json:"disableKeepAlives,omitempty" mapstructure:"disableKeepAlives" yaml:"disableKeepAlives,omitempty"`
+	// Enable or disable HTTP/2. HTTP/2 is attempted by default unless the server has a custom TLS configuration.
+	HTTP2 *utils.EnvBool `json:"http2,omitempty" mapstructure:"http2" yaml:"http2,omitempty"`
+}
+
+// ConnectionSettings represent evaluated values of ConnectionConfig. Nil fields aren't set.
+type ConnectionSettings struct {
+	MaxIdleConns        *int
+	MaxIdleConnsPerHost *int
+	MaxConnsPerHost     *int
+	IdleConnTimeout     *time.Duration
+	DisableKeepAlives   *bool
+	HTTP2               *bool
+}
+
+// Evaluate gets values from environment variables and validates them.
+func (cc ConnectionConfig) Evaluate() (*ConnectionSettings, error) {
+	result := &ConnectionSettings{}
+	var err error
+
+	if result.MaxIdleConns, err = evalNonNegativeEnvInt("maxIdleConns", cc.MaxIdleConns); err != nil {
+		return nil, err
+	}
+
+	if result.MaxIdleConnsPerHost, err = evalNonNegativeEnvInt("maxIdleConnsPerHost", cc.MaxIdleConnsPerHost); err != nil {
+		return nil, err
+	}
+
+	if result.MaxConnsPerHost, err = evalNonNegativeEnvInt("maxConnsPerHost", cc.MaxConnsPerHost); err != nil {
+		return nil, err
+	}
+
+	idleConnTimeout, err := evalNonNegativeEnvInt("idleConnTimeout", cc.IdleConnTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	if idleConnTimeout != nil {
+		timeout := time.Duration(*idleConnTimeout) * time.Second
+		result.IdleConnTimeout = &timeout
+	}
+
+	if cc.DisableKeepAlives != nil && !isEmptyEnvValue(cc.DisableKeepAlives.Value, cc.DisableKeepAlives.Variable) {
+		disableKeepAlives, err := cc.DisableKeepAlives.Get()
+		if err != nil {
+			return nil, fmt.Errorf("disableKeepAlives: %w", err)
+		}
+
+		result.DisableKeepAlives = &disableKeepAlives
+	}
+
+	if cc.HTTP2 != nil && !isEmptyEnvValue(cc.HTTP2.Value, cc.HTTP2.Variable) {
+		http2, err := cc.HTTP2.Get()
+		if err != nil {
+			return nil, fmt.Errorf("http2: %w", err)
+		}
+
+		result.HTTP2 = &http2
+	}
+
+	return result, nil
+}
+
+func evalNonNegativeEnvInt(name string, value *utils.EnvInt) (*int, error) {
+	if value == nil || isEmptyEnvValue(value.Value, value.Variable) {
+		return nil, nil
+	}
+
+	result, err := value.Get()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+
+	if result < 0 {
+		return nil, fmt.Errorf("%s: expected a non-negative integer, got %d", name, result)
+	}
+
+	intValue := int(result)
+
+	return &intValue, nil
+}
+
+// check if both the literal value and the environment variable are empty, so the setting is ignored.
+func isEmptyEnvValue[T any](value *T, variable *string) bool {
+	return value == nil && (variable == nil || os.Getenv(*variable) == "")
+}
+
+// ProxyConfig represents the proxy settings of outbound requests to upstream servers.
+type ProxyConfig struct {
+	// The proxy URL, e.g. http://proxy.example.com:8080 or socks5://proxy.example.com:1080.
+	// Supported schemes are http, https, socks5 and socks5h. Requests are sent directly if the URL is empty.
+	URL *utils.EnvString `json:"url,omitempty" mapstructure:"url" yaml:"url,omitempty"`
+	// The username of the proxy authentication. Overrides the user info of the proxy URL.
+	Username *utils.EnvString `json:"username,omitempty" mapstructure:"username" yaml:"username,omitempty"`
+	// The password of the proxy authentication. Overrides the user info of the proxy URL.
+	Password *utils.EnvString `json:"password,omitempty" mapstructure:"password" yaml:"password,omitempty"`
+	// Hosts which are requested directly without the proxy, similar to the NO_PROXY environment variable.
+	// Each entry can be a host name, a domain suffix with a leading dot, e.g. .example.com, an IP address, a CIDR range, or * to bypass the proxy for all hosts.
+	// An optional port restricts the entry to requests with that port, e.g. api.example.com:8443.
+	NoProxy []utils.EnvString `json:"noProxy,omitempty" mapstructure:"noProxy" yaml:"noProxy,omitempty"`
+}
+
+// ProxySettings represent evaluated values of ProxyConfig.
+type ProxySettings struct {
+	// The proxy URL with user info. Nil if requests are sent directly.
+	URL *url.URL
+	// Evaluated no-proxy entries.
+	NoProxy []string
+}
+
+// Evaluate gets values from environment variables and validates them.
+func (pc ProxyConfig) Evaluate() (*ProxySettings, error) {
+	result := &ProxySettings{}
+	if pc.URL == nil {
+		return result, nil
+	}
+
+	rawURL, err := pc.URL.GetOrDefault("")
+	if err != nil {
+		return nil, fmt.Errorf("url: %w", err)
+	}
+
+	if rawURL == "" {
+		return result, nil
+	}
+
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("url: %w", err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("url: unsupported proxy scheme %q, expected one of http, https, socks5 and socks5h", proxyURL.Scheme)
+	}
+
+	if proxyURL.Host == "" {
+		return nil, errors.New("url: proxy host is required")
+	}
+
+	if pc.Username != nil || pc.Password != nil {
+		username, password, err := pc.getUserInfo()
+		if err != nil {
+			return nil, err
+		}
+
+		if username != "" || password != "" {
+			proxyURL.User = url.UserPassword(username, password)
+		}
+	}
+
+	result.URL = proxyURL
+
+	for i, envEntry := range pc.NoProxy {
+		entry, err := envEntry.GetOrDefault("")
+		if err != nil {
+			return nil, fmt.Errorf("noProxy[%d]: %w", i, err)
+		}
+
+		// an entry can be a comma-separated list, e.g. the value of the NO_PROXY variable
+		for _, item := range strings.Split(entry, ",") {
+			item = strings.ToLower(strings.TrimSpace(item))
+			if item != "" {
+				result.NoProxy = append(result.NoProxy, item)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+func (pc ProxyConfig) getUserInfo() (string, string, error) {
+	var username, password string
+	var err error
+	if pc.Username != nil {
+		username, err = pc.Username.GetOrDefault("")
+		if err != nil {
+			return "", "", fmt.Errorf("username: %w", err)
+		}
+	}
+
+	if pc.Password != nil {
+		password, err = pc.Password.GetOrDefault("")
+		if err != nil {
+			return "", "", fmt.Errorf("password: %w", err)
+		}
+	}
+
+	return username, password, nil
+}
+
+// HealthCheckConfig represents active health checking settings of a server.
+// The connector probes the health endpoint in background and the server is healthy if the response status is 2xx.
+type HealthCheckConfig struct {
+	// The path of the health endpoint, relative to the server URL, e.g. /health.
+	Path string `json:"path" mapstructure:"path" yaml:"path"`
+	// The interval in seconds between probes. Default to 30 seconds.
+	Interval *uint `json:"interval,omitempty" mapstructure:"interval" yaml:"interval,omitempty"`
+	// The timeout in seconds of each probe. Default to 5 seconds.
+	Timeout *uint `json:"timeout,omitempty" mapstructure:"timeout" yaml:"timeout,omitempty"`
+	// The number of consecutive failed probes after which the server is considered unhealthy. Default to 1.
+	UnhealthyThreshold *uint `json:"unhealthyThreshold,omitempty" mapstructure:"unhealthyThreshold" yaml:"unhealthyThreshold,omitempty"`
+}
+
+// Validate if the current instance is valid.
+func (hc HealthCheckConfig) Validate() error {
+	if hc.Path == "" {
+		return errors.New("path is required")
+	}
+
+	if hc.Interval != nil && *hc.Interval == 0 {
+		return errors.New("interval must be positive")
+	}
+
+	if hc.Timeout != nil && *hc.Timeout == 0 {
+		return errors.New("timeout must be positive")
+	}
+
+	return nil
+}
+
+// GetInterval returns the interval between probes.
+func (hc HealthCheckConfig) GetInterval() time.Duration {
+	if hc.Interval == nil || *hc.Interval == 0 {
+		return 30 * time.Second
+	}
+
+	return time.Duration(*hc.Interval) * time.Second
+}
+
+// GetTimeout returns the timeout of each probe.
+func (hc HealthCheckConfig) GetTimeout() time.Duration {
+	if hc.Timeout == nil || *hc.Timeout == 0 {
+		return 5 * time.Second
+	}
+
+	return time.Duration(*hc.Timeout) * time.Second
+}
+
+// GetUnhealthyThreshold returns the number of consecutive failed probes after which the server is unhealthy.
+func (hc HealthCheckConfig) GetUnhealthyThreshold() int {
+	if hc.UnhealthyThreshold == nil || *hc.UnhealthyThreshold == 0 {
+		return 1
+	}
+
+	return int(*hc.UnhealthyThreshold)
+}
@@ -5,6 +5,9 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/hasura/ndc-sdk-go/schema"
 	"github.com/hasura/ndc-sdk-go/utils"
@@ -29,6 +32,10 @@ type NDCHttpSchema struct {
 
 	// A list of scalar types which will be used as the types of collection columns
 	ScalarTypes schema.SchemaResponseScalarTypes `json:"scalar_types" mapstructure:"scalar_types" yaml:"scalar_types"`
+
+	// Requests which the upstream service sends to subscribers, e.g. OpenAPI callbacks and webhooks or AsyncAPI messages.
+	// The connector doesn't receive webhooks. Payload types are kept in object types so downstream tooling can reuse them
+	Webhooks map[string]WebhookInfo `json:"webhooks,omitempty" mapstructure:"webhooks" yaml:"webhooks,omitempty"`
 }
 
 // NewNDCHttpSchema creates a NDCHttpSchema instance
@@ -38,6 +45,7 @@ func NewNDCHttpSchema() *NDCHttpSchema {
 		Settings:    &NDCHttpSettings{},
 		Functions:   map[string]OperationInfo{},
 		Procedures:  map[string]OperationInfo{},
+		Webhooks:    map[string]WebhookInfo{},
 		ObjectTypes: make(map[string]ObjectType),
 		ScalarTypes: make(schema.SchemaResponseScalarTypes),
 	}
@@ -46,10 +54,19 @@ func NewNDCHttpSchema() *NDCHttpSchema {
 // ToSchemaResponse converts the instance to NDC schema.SchemaResponse
 func (ndc NDCHttpSchema) ToSchemaResponse() *schema.SchemaResponse {
 	functionKeys := utils.GetSortedKeys(ndc.Functions)
-	functions := make([]schema.FunctionInfo, len(functionKeys))
-	for i, key := range functionKeys {
+	functions := make([]schema.FunctionInfo, 0, len(functionKeys))
+	collections := []schema.CollectionInfo{}
+	for _, key := range functionKeys {
 		fn := ndc.Functions[key]
-		functions[i] = fn.FunctionSchema(key)
+		if fn.Collection {
+			if collectionType, err := ndc.GetCollectionType(fn); err == nil {
+				collections = append(collections, fn.CollectionSchema(key, collectionType))
+
+				continue
+			}
+		}
+
+		functions = append(functions, fn.FunctionSchema(key))
 	}
 
 	procedureKeys := utils.GetSortedKeys(ndc.Procedures)
@@ -64,7 +81,7 @@ func (ndc NDCHttpSchema) ToSchemaResponse() *schema.SchemaResponse {
 	}
 
 	return &schema.SchemaResponse{
-		Collections: []schema.CollectionInfo{},
+		Collections: collections,
 		ScalarTypes: ndc.ScalarTypes,
 		ObjectTypes: objectTypes,
 		Functions:   functions,
@@ -92,6 +109,38 @@ func (rm NDCHttpSchema) GetProcedure(name string) *OperationInfo {
 	return &fn
 }
 
+// GetCollectionType gets the object type name of rows if the function is exposed as a collection.
+// The result type must be an array of objects, or an object which contains the array at the items field of the pagination settings.
+func (rm NDCHttpSchema) GetCollectionType(operation OperationInfo) (string, error) {
+	resultType := schema.UnwrapNullableType(operation.ResultType)
+	if operation.Request != nil && operation.Request.Pagination != nil && operation.Request.Pagination.ItemsField != "" {
+		itemsField := operation.Request.Pagination.ItemsField
+		for _, segment := range strings.Split(itemsField, ".") {
+			namedType, ok := resultType.Interface().(*schema.NamedType)
+			if !ok {
+				return "", fmt.Errorf("expected an object type at the items field %s", itemsField)
+			}
+
+			field, ok := rm.ObjectTypes[namedType.Name].Fields[segment]
+			if !ok {
+				return "", fmt.Errorf("the items field %s does not exist in the result type", itemsField)
+			}
+			resultType = schema.UnwrapNullableType(field.Type)
+		}
+	}
+
+	if arrayType, ok := resultType.Interface().(*schema.ArrayType); ok {
+		elementType := schema.UnwrapNullableType(arrayType.ElementType)
+		if namedType, ok := elementType.Interface().(*schema.NamedType); ok {
+			if _, ok := rm.ObjectTypes[namedType.Name]; ok {
+				return namedType.Name, nil
+			}
+		}
+	}
+
+	return "", errors.New("the result type of the collection must be an array of objects")
+}
+
 // AddScalar adds a new scalar if not exist.
 func (rm *NDCHttpSchema) AddScalar(name string, scalar schema.ScalarType) {
 	_, ok := rm.ScalarTypes[name]
@@ -102,12 +151,293 @@ func (rm *NDCHttpSchema) AddScalar(name string, scalar schema.ScalarType) {
 
 type Response struct {
 	ContentType string `json:"contentType" mapstructure:"contentType" yaml:"contentType"`
+	// Read the response body in chunks instead of decoding the entire body, e.g. server-sent events or large files
+	Stream *ResponseStreamSettings `json:"stream,omitempty" mapstructure:"stream" yaml:"stream,omitempty"`
+	// Normalize the decoded response body before returning it to the engine
+	Normalize *ResponseNormalizeSettings `json:"normalize,omitempty" mapstructure:"normalize" yaml:"normalize,omitempty"`
+	// Unwrap the standard envelope of the response body into flat objects before decoding, e.g. JSON:API or HAL
+	Envelope ResponseEnvelope `json:"envelope,omitempty" mapstructure:"envelope" yaml:"envelope,omitempty"`
+	// Embed results of other functions into items of the response, e.g. details of items of list endpoints which return IDs only
+	Enrich []ResponseEnrichSettings `json:"enrich,omitempty" mapstructure:"enrich" yaml:"enrich,omitempty"`
+	// An example of the success response body from the API spec, which is served in the mock mode
+	Example any `json:"example,omitempty" mapstructure:"example" yaml:"example,omitempty"`
+	// Don't follow redirects. The status code and the Location header of the response are returned as the RedirectResult object
+	Redirect bool `json:"redirect,omitempty" mapstructure:"redirect" yaml:"redirect,omitempty"`
+	// Schemas of error response bodies by status code, e.g. 404, 4XX or default.
+	// Error bodies are decoded into the typedError field of error details
+	Errors map[string]ErrorResponse `json:"errors,omitempty" mapstructure:"errors" yaml:"errors,omitempty"`
+	// Return a null result instead of an error if the upstream service responds 404 Not Found.
+	// Only functions are affected. The result type of the function becomes nullable
+	NotFoundAsNull bool `json:"404AsNull,omitempty" mapstructure:"404AsNull" yaml:"404AsNull,omitempty"`
+}
+
+// ErrorResponse represents the schema of an error response body.
+type ErrorResponse struct {
+	// The content type of the error response body
+	ContentType string `json:"contentType,omitempty" mapstructure:"contentType" yaml:"contentType,omitempty"`
+	// The type of the error response body
+	Type schema.Type `json:"type" mapstructure:"type" yaml:"type"`
+}
+
+// FindError finds the error response schema of the status code.
+// The exact status code takes precedence over the status class, e.g. 4XX, and the default response.
+func (r Response) FindError(statusCode int) (*ErrorResponse, bool) {
+	if len(r.Errors) == 0 {
+		return nil, false
+	}
+
+	for _, key := range []string{strconv.Itoa(statusCode), strconv.Itoa(statusCode/100) + "XX", "default"} {
+		if errResponse, ok := r.Errors[key]; ok {
+			return &errResponse, true
+		}
+	}
+
+	return nil, false
+}
+
+// ResponseEnrichSettings hold settings to call a function for each item of the response and embed the result.
+type ResponseEnrichSettings struct {
+	// The name of the new field to embed the result into
+	Field string `json:"field" mapstructure:"field" yaml:"field"`
+	// The name of the function to be called
+	Operation string `json:"operation" mapstructure:"operation" yaml:"operation"`
+	// The field of each item whose value is the key to be sent
+	KeyField string `json:"keyField" mapstructure:"keyField" yaml:"keyField"`
+	// The argument of the function to receive the key
+	Argument string `json:"argument" mapstructure:"argument" yaml:"argument"`
+	// Send keys in batches with an array argument. The function is called for each key if zero
+	BatchSize uint `json:"batchSize,omitempty" mapstructure:"batchSize" yaml:"batchSize,omitempty"`
+	// The field of items in the batch result to be matched with keys. Default to keyField
+	ResultKeyField string `json:"resultKeyField,omitempty" mapstructure:"resultKeyField" yaml:"resultKeyField,omitempty"`
+	// The maximum number of concurrent calls. Default to 5
+	Concurrency uint `json:"concurrency,omitempty" mapstructure:"concurrency" yaml:"concurrency,omitempty"`
+}
+
+// ResponseNormalizeSettings hold settings to clean up null values and empty collections of the response body.
+type ResponseNormalizeSettings struct {
+	// Remove null-valued keys from objects
+	StripNulls bool `json:"stripNulls,omitempty" mapstructure:"stripNulls" yaml:"stripNulls,omitempty"`
+	// Remove keys of empty arrays and empty objects from objects
+	StripEmptyCollections bool `json:"stripEmptyCollections,omitempty" mapstructure:"stripEmptyCollections" yaml:"stripEmptyCollections,omitempty"`
+	// Default values to replace null fields or empty collections, keyed by the field name. Defaults are applied to objects at any depth before stripping
+	Defaults map[string]any `json:"defaults,omitempty" mapstructure:"defaults" yaml:"defaults,omitempty"`
+}
+
+// ResponseStreamSettings hold settings to read the response body in chunks.
+// The result type of the operation should be an array of JSON or Bytes.
+type ResponseStreamSettings struct {
+	// The stream mode of the response body
+	Mode ResponseStreamMode `json:"mode" mapstructure:"mode" yaml:"mode"`
+	// The size in bytes of binary chunks. Default to 1 MiB
+	ChunkSize uint `json:"chunkSize,omitempty" mapstructure:"chunkSize" yaml:"chunkSize,omitempty"`
+	// The maximum number of chunks or events to be read. The connector stops reading the response body when reaching the limit. Unlimited if zero
+	MaxChunks uint `json:"maxChunks,omitempty" mapstructure:"maxChunks" yaml:"maxChunks,omitempty"`
+}
+
+// PaginationSettings hold settings of the pagination strategy of an operation.
+type PaginationSettings struct {
+	// The pagination strategy
+	Type PaginationType `json:"type" mapstructure:"type" yaml:"type"`
+	// The query parameter of the page size, e.g. limit or per_page
+	LimitParameter string `json:"limitParameter,omitempty" mapstructure:"limitParameter" yaml:"limitParameter,omitempty"`
+	// The query parameter of the offset. Required by the offset strategy
+	OffsetParameter string `json:"offsetParameter,omitempty" mapstructure:"offsetParameter" yaml:"offsetParameter,omitempty"`
+	// The query parameter of the page number. Required by the page strategy
+	PageParameter string `json:"pageParameter,omitempty" mapstructure:"pageParameter" yaml:"pageParameter,omitempty"`
+	// The number of the first page. Default to 1
+	FirstPage *int `json:"firstPage,omitempty" mapstructure:"firstPage" yaml:"firstPage,omitempty"`
+	// The query parameter of the cursor token. Required by the cursor strategy
+	CursorParameter string `json:"cursorParameter,omitempty" mapstructure:"cursorParameter" yaml:"cursorParameter,omitempty"`
+	// The path in dot notation of the next cursor token in the response body. Required by the cursor strategy
+	CursorField string `json:"cursorField,omitempty" mapstructure:"cursorField" yaml:"cursorField,omitempty"`
+	// The path in dot notation of the items array in the response body. The response body is the items array if empty
+	ItemsField string `json:"itemsField,omitempty" mapstructure:"itemsField" yaml:"itemsField,omitempty"`
+	// The page size of automatic page fetching if the query doesn't have a limit
+	PageSize uint `json:"pageSize,omitempty" mapstructure:"pageSize" yaml:"pageSize,omitempty"`
+	// The maximum number of pages to be fetched. Pages are fetched automatically if greater than 1
+	MaxPages uint `json:"maxPages,omitempty" mapstructure:"maxPages" yaml:"maxPages,omitempty"`
+}
+
+// Validate checks if the pagination settings are valid.
+func (ps PaginationSettings) Validate() error {
+	if !ps.Type.IsValid() {
+		return fmt.Errorf("invalid pagination type: %s", ps.Type)
+	}
+
+	switch ps.Type {
+	case PaginationOffset:
+		if ps.OffsetParameter == "" {
+			return errors.New("offsetParameter is required by the offset pagination")
+		}
+	case PaginationPage:
+		if ps.PageParameter == "" {
+			return errors.New("pageParameter is required by the page pagination")
+		}
+	case PaginationCursor:
+		if ps.CursorParameter == "" || ps.CursorField == "" {
+			return errors.New("cursorParameter and cursorField are required by the cursor pagination")
+		}
+	}
+
+	return nil
+}
+
+// FilterComparisonOperators map comparison operators which can be pushed down to their prefixes in the operator style.
+var FilterComparisonOperators = map[string]string{
+	"_eq":    "eq",
+	"_neq":   "neq",
+	"_gt":    "gt",
+	"_gte":   "gte",
+	"_lt":    "lt",
+	"_lte":   "lte",
+	"_in":    "in",
+	"_like":  "like",
+	"_ilike": "ilike",
+}
+
+// FilterSettings hold settings to map comparison predicates of the query onto upstream query parameters,
+// e.g. status=available, status=eq.available or $filter=Status eq 'available'.
+type FilterSettings struct {
+	// The syntax of parameter values. Default to plain
+	Style FilterStyle `json:"style,omitempty" mapstructure:"style" yaml:"style,omitempty"`
+	// The query parameter of the combined expression in the odata style. Default to $filter
+	Parameter string `json:"parameter,omitempty" mapstructure:"parameter" yaml:"parameter,omitempty"`
+	// Filterable fields. Keys are field paths in dot notation of the result object, or items if the result is an array
+	Fields map[string]FilterFieldSettings `json:"fields" mapstructure:"fields" yaml:"fields"`
+}
+
+// FilterFieldSettings hold the filter mapping of a field.
+type FilterFieldSettings struct {
+	// The name of the query parameter. Default to the field path
+	Parameter string `json:"parameter,omitempty" mapstructure:"parameter" yaml:"parameter,omitempty"`
+	// Comparison operators which are allowed, e.g. _eq, _in or _gt. Default to _eq
+	Operators []string `json:"operators,omitempty" mapstructure:"operators" yaml:"operators,omitempty"`
+	// Render string values without quotes in the odata style, e.g. literals of Edm.DateTimeOffset or Edm.Guid
+	Unquoted bool `json:"unquoted,omitempty" mapstructure:"unquoted" yaml:"unquoted,omitempty"`
+}
+
+// GetParameter returns the query parameter of the combined expression in the odata style.
+func (fs FilterSettings) GetParameter() string {
+	if fs.Parameter != "" {
+		return fs.Parameter
+	}
+
+	return "$filter"
+}
+
+// GetParameter returns the query parameter name of the field.
+func (ffs FilterFieldSettings) GetParameter(fieldPath string) string {
+	if ffs.Parameter != "" {
+		return ffs.Parameter
+	}
+
+	return fieldPath
+}
+
+// GetOperators returns allowed comparison operators of the field.
+func (ffs FilterFieldSettings) GetOperators() []string {
+	if len(ffs.Operators) == 0 {
+		return []string{"_eq"}
+	}
+
+	return ffs.Operators
+}
+
+// Validate checks if the filter settings are valid.
+func (fs FilterSettings) Validate() error {
+	if fs.Style != "" && !fs.Style.IsValid() {
+		return fmt.Errorf("invalid filter style: %s", fs.Style)
+	}
+
+	if len(fs.Fields) == 0 {
+		return errors.New("fields must not be empty")
+	}
+
+	for fieldPath, field := range fs.Fields {
+		if fieldPath == "" {
+			return errors.New("field path must not be empty")
+		}
+
+		for _, op := range field.GetOperators() {
+			if _, ok := FilterComparisonOperators[op]; !ok {
+				return fmt.Errorf("%s: unsupported comparison operator %s", fieldPath, op)
+			}
+
+			switch fs.Style {
+			case FilterOperator:
+			case FilterOData:
+				if op == "_like" || op == "_ilike" {
+					return fmt.Errorf("%s: the comparison operator %s isn't supported by the odata style", fieldPath, op)
+				}
+			default:
+				if op != "_eq" && op != "_in" {
+					return fmt.Errorf("%s: the comparison operator %s requires the operator style", fieldPath, op)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// OrderBySettings hold settings to map order_by elements of collection queries onto an upstream query parameter,
+// e.g. $orderby=Name asc,Price desc in OData services or sort=name,-price.
+type OrderBySettings struct {
+	// The name of the query parameter
+	Parameter string `json:"parameter" mapstructure:"parameter" yaml:"parameter"`
+	// The syntax of the parameter value. Default to odata
+	Style OrderByStyle `json:"style,omitempty" mapstructure:"style" yaml:"style,omitempty"`
+	// Sortable field paths in dot notation of rows. Field names must match upstream field names
+	Fields []string `json:"fields" mapstructure:"fields" yaml:"fields"`
+}
+
+// Validate checks if the order by settings are valid.
+func (obs OrderBySettings) Validate() error {
+	if obs.Parameter == "" {
+		return errors.New("parameter is required")
+	}
+
+	if obs.Style != "" && !obs.Style.IsValid() {
+		return fmt.Errorf("invalid order by style: %s", obs.Style)
+	}
+
+	if len(obs.Fields) == 0 {
+		return errors.New("fields must not be empty")
+	}
+
+	if slices.Contains(obs.Fields, "") {
+		return errors.New("field path must not be empty")
+	}
+
+	return nil
 }
 
 // RuntimeSettings contain runtime settings for a server
 type RuntimeSettings struct { // configure the request timeout in seconds, default 30s
 	Timeout uint        `json:"timeout,omitempty" mapstructure:"timeout" yaml:"timeout,omitempty"`
 	Retry   RetryPolicy `json:"retry,omitempty"   mapstructure:"retry"   yaml:"retry,omitempty"`
+	// The maximum size in bytes of the response body after decompression. Unlimited if zero
+	MaxResponseBytes uint `json:"maxResponseBytes,omitempty" mapstructure:"maxResponseBytes" yaml:"maxResponseBytes,omitempty"`
+	// The maximum duration in milliseconds to read and decode the response body. Unlimited if zero
+	MaxDecodeDurationMs uint `json:"maxDecodeDurationMs,omitempty" mapstructure:"maxDecodeDurationMs" yaml:"maxDecodeDurationMs,omitempty"`
+	// Limit the rate of outbound requests to each server
+	RateLimit *RateLimitSettings `json:"rateLimit,omitempty" mapstructure:"rateLimit" yaml:"rateLimit,omitempty"`
+	// Validate decoded response bodies against the schema. Violations fail the request or are logged. Disabled if empty
+	ValidateResponse ResponseValidationMode `json:"validateResponse,omitempty" mapstructure:"validateResponse" yaml:"validateResponse,omitempty"`
+	// Compress request bodies with the encoding if the Content-Encoding header isn't set. Disabled if empty
+	Compression RequestCompression `json:"compression,omitempty" mapstructure:"compression" yaml:"compression,omitempty"`
+	// The minimum size in bytes of request bodies to be compressed automatically
+	CompressionMinBytes uint `json:"compressionMinBytes,omitempty" mapstructure:"compressionMinBytes" yaml:"compressionMinBytes,omitempty"`
+	// Execute operations of mutation requests one by one if the request contains procedures of the upstream service
+	SequentialMutation bool `json:"sequentialMutation,omitempty" mapstructure:"sequentialMutation" yaml:"sequentialMutation,omitempty"`
+}
+
+// RateLimitSettings hold settings of the client-side rate limiter.
+type RateLimitSettings struct {
+	// The number of requests allowed per second
+	RequestsPerSecond float64 `json:"requestsPerSecond" mapstructure:"requestsPerSecond" yaml:"requestsPerSecond"`
+	// The maximum number of requests allowed to be sent at once. Default to 1
+	Burst uint `json:"burst,omitempty" mapstructure:"burst" yaml:"burst,omitempty"`
 }
 
 // Request represents the HTTP request information of the webhook
@@ -119,10 +449,144 @@ type Request struct {
 	Servers     []ServerConfig             `json:"servers,omitempty"     mapstructure:"servers"                                          yaml:"servers,omitempty"`
 	RequestBody *RequestBody               `json:"requestBody,omitempty" mapstructure:"requestBody"                                      yaml:"requestBody,omitempty"`
 	Response    Response                   `json:"response"              mapstructure:"response"                                         yaml:"response"`
+	// The protocol of the request. Default to http
+	Protocol RequestProtocol `json:"protocol,omitempty" mapstructure:"protocol" yaml:"protocol,omitempty"`
+	// The GraphQL operation of the request if the upstream is a GraphQL service
+	GraphQL *GraphQLRequest `json:"graphql,omitempty" mapstructure:"graphql" yaml:"graphql,omitempty"`
+	// The gRPC method of the request if the protocol is grpc
+	GRPC *GRPCRequest `json:"grpc,omitempty" mapstructure:"grpc" yaml:"grpc,omitempty"`
+	// The SOAP operation of the request. The XML body is wrapped in the SOAP envelope
+	SOAP *SOAPRequest `json:"soap,omitempty" mapstructure:"soap" yaml:"soap,omitempty"`
+	// Protobuf messages of request and response bodies with the application/protobuf content type
+	Protobuf *ProtobufSettings `json:"protobuf,omitempty" mapstructure:"protobuf" yaml:"protobuf,omitempty"`
+	// Push down field selections of the query to an upstream query parameter, e.g. sparse fieldsets
+	FieldSelection *FieldSelectionSettings `json:"fieldSelection,omitempty" mapstructure:"fieldSelection" yaml:"fieldSelection,omitempty"`
+	// Map the limit and offset of the query onto upstream pagination parameters and fetch multiple pages
+	Pagination *PaginationSettings `json:"pagination,omitempty" mapstructure:"pagination" yaml:"pagination,omitempty"`
+	// Map comparison predicates of the query onto upstream query parameters
+	Filter *FilterSettings `json:"filter,omitempty" mapstructure:"filter" yaml:"filter,omitempty"`
+	// Map order_by elements of collection queries onto an upstream query parameter
+	OrderBy *OrderBySettings `json:"orderBy,omitempty" mapstructure:"orderBy" yaml:"orderBy,omitempty"`
 
 	*RuntimeSettings `yaml:",inline"`
 }
 
+// FieldSelectionSettings hold settings to map field selections of the query onto an upstream query parameter,
+// so the upstream API only returns selected fields, e.g. fields=id,name in Jira or fields=items(id,name) in Google APIs.
+type FieldSelectionSettings struct {
+	// The name of the query parameter
+	Parameter string `json:"parameter" mapstructure:"parameter" yaml:"parameter"`
+	// The syntax of the parameter value. Default to comma
+	Style FieldSelectionStyle `json:"style,omitempty" mapstructure:"style" yaml:"style,omitempty"`
+	// Field paths in dot notation which are always requested, e.g. identifiers which are required by response enrichment
+	Always []string `json:"always,omitempty" mapstructure:"always" yaml:"always,omitempty"`
+}
+
+// GraphQLRequest represents the GraphQL operation of a request.
+// The request body is built from the query document and variables, and the result is extracted from the data field of the response.
+type GraphQLRequest struct {
+	// The GraphQL query document
+	Query string `json:"query" mapstructure:"query" yaml:"query"`
+	// The operation name in the query document
+	OperationName string `json:"operationName,omitempty" mapstructure:"operationName" yaml:"operationName,omitempty"`
+	// Names of arguments which are sent as GraphQL variables
+	Variables []string `json:"variables,omitempty" mapstructure:"variables" yaml:"variables,omitempty"`
+	// The root field of the data object in the response to be returned as the result
+	ResultField string `json:"resultField" mapstructure:"resultField" yaml:"resultField"`
+}
+
+// ProtobufSettings represent protobuf messages of request and response bodies which are serialized in the binary protobuf format.
+// JSON arguments are encoded to the request message, and the response message is decoded to JSON.
+type ProtobufSettings struct {
+	// Path of the protobuf descriptor set file which is generated by protoc --include_imports --descriptor_set_out.
+	// Relative paths are resolved from the configuration directory
+	DescriptorSet string `json:"descriptorSet" mapstructure:"descriptorSet" yaml:"descriptorSet"`
+	// The fully-qualified message name of the request body, e.g. petstore.CreatePetRequest
+	RequestMessage string `json:"requestMessage,omitempty" mapstructure:"requestMessage" yaml:"requestMessage,omitempty"`
+	// The fully-qualified message name of the response body, e.g. petstore.Pet
+	ResponseMessage string `json:"responseMessage,omitempty" mapstructure:"responseMessage" yaml:"responseMessage,omitempty"`
+}
+
+// GRPCRequest represents the unary gRPC method of a request.
+// JSON arguments are transcoded to the protobuf request message, and the response message is transcoded back to JSON.
+type GRPCRequest struct {
+	// Path of the protobuf descriptor set file which is generated by protoc --include_imports --descriptor_set_out.
+	// Relative paths are resolved from the configuration directory
+	DescriptorSet string `json:"descriptorSet" mapstructure:"descriptorSet" yaml:"descriptorSet"`
+	// The fully-qualified service name, e.g. helloworld.Greeter
+	Service string `json:"service" mapstructure:"service" yaml:"service"`
+	// The method name of the service, e.g. SayHello
+	Method string `json:"method" mapstructure:"method" yaml:"method"`
+	// Names of arguments which are sent as fields of the request message. The body argument is sent as the request message if empty
+	Arguments []string `json:"arguments,omitempty" mapstructure:"arguments" yaml:"arguments,omitempty"`
+	// TLS configuration of the connection. TLS is enabled if set or the server URL is https
+	TLS *TLSConfig `json:"tls,omitempty" mapstructure:"tls" yaml:"tls,omitempty"`
+}
+
+// SOAPRequest represents the SOAP operation of a request.
+// The XML request body is wrapped in the SOAP envelope, and the result is unwrapped from the body of the response envelope.
+type SOAPRequest struct {
+	// The SOAP version, is one of 1.1, 1.2. Default to 1.1
+	Version SOAPVersion `json:"version,omitempty" mapstructure:"version" yaml:"version,omitempty"`
+	// The SOAP action of the operation, sent in the SOAPAction header (1.1) or the action parameter of the content type (1.2)
+	Action string `json:"action,omitempty" mapstructure:"action" yaml:"action,omitempty"`
+}
+
+// GetVersion returns the SOAP version, or 1.1 if empty.
+func (sr SOAPRequest) GetVersion() SOAPVersion {
+	if sr.Version == "" {
+		return SOAPVersion11
+	}
+
+	return sr.Version
+}
+
+// ContentType returns the content type of SOAP messages of the version.
+func (sr SOAPRequest) ContentType() string {
+	if sr.GetVersion() == SOAPVersion12 {
+		return ContentTypeSOAPXML
+	}
+
+	return ContentTypeTextXML
+}
+
+// FullMethod returns the full method path, e.g. /helloworld.Greeter/SayHello
+func (gr GRPCRequest) FullMethod() string {
+	return "/" + gr.Service + "/" + gr.Method
+}
+
+// Validate checks if the gRPC request settings are valid.
+func (gr GRPCRequest) Validate() error {
+	if gr.DescriptorSet == "" {
+		return errors.New("descriptorSet is required")
+	}
+
+	if gr.Service == "" || gr.Method == "" {
+		return errors.New("service and method are required")
+	}
+
+	if gr.TLS != nil {
+		if err := gr.TLS.Validate(); err != nil {
+			return fmt.Errorf("tls: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Validate checks if the setting is valid.
+func (ps ProtobufSettings) Validate() error {
+	if ps.DescriptorSet == "" {
+		return errors.New("descriptorSet is required")
+	}
+
+	if ps.RequestMessage == "" && ps.ResponseMessage == "" {
+		return errors.New("requestMessage or responseMessage is required")
+	}
+
+	return nil
+}
+
 // Clone copies this instance to a new one
 func (r Request) Clone() *Request {
 	return &Request{
@@ -133,6 +597,15 @@ func (r Request) Clone() *Request {
 		Servers:         r.Servers,
 		RequestBody:     r.RequestBody,
 		Response:        r.Response,
+		Protocol:        r.Protocol,
+		GraphQL:         r.GraphQL,
+		GRPC:            r.GRPC,
+		SOAP:            r.SOAP,
+		Protobuf:        r.Protobuf,
+		FieldSelection:  r.FieldSelection,
+		Pagination:      r.Pagination,
+		Filter:          r.Filter,
+		OrderBy:         r.OrderBy,
 		RuntimeSettings: r.RuntimeSettings,
 	}
 }
@@ -158,9 +631,14 @@ type TypeSchema struct {
 	MinLength   *int64      `json:"minLength,omitempty" mapstructure:"minLength" yaml:"minLength,omitempty"`
 	Items       *TypeSchema `json:"items,omitempty"     mapstructure:"items"     yaml:"items,omitempty"`
 	XML         *XMLSchema  `json:"xml,omitempty"       mapstructure:"xml"       yaml:"xml,omitempty"`
+	Default     any         `json:"default,omitempty"   mapstructure:"default"   yaml:"default,omitempty"`
 	Description string      `json:"-"                   yaml:"-"`
 	ReadOnly    bool        `json:"-"                   yaml:"-"`
 	WriteOnly   bool        `json:"-"                   yaml:"-"`
+	// The data classification from vendor extensions. Only used while converting the spec
+	Classification DataClassification `json:"-" yaml:"-"`
+	// The value schema of homogeneous maps which are converted from additionalProperties
+	AdditionalProperties *TypeSchema `json:"additionalProperties,omitempty" mapstructure:"additionalProperties" yaml:"additionalProperties,omitempty"`
 }
 
 // RetryPolicy represents the retry policy of request
@@ -171,6 +649,31 @@ type RetryPolicy struct {
 	Delay uint `json:"delay,omitempty" mapstructure:"delay" yaml:"delay,omitempty"`
 	// HTTPStatus retries if the remote service returns one of these http status
 	HTTPStatus []int `json:"httpStatus,omitempty" mapstructure:"httpStatus" yaml:"httpStatus,omitempty"`
+	// The strategy to compute the delay between retries. Default to fixed
+	Backoff RetryBackoffStrategy `json:"backoff,omitempty" mapstructure:"backoff" yaml:"backoff,omitempty"`
+	// The factor to multiply the delay after each retry of the exponential backoff. Default to 2
+	Multiplier float64 `json:"multiplier,omitempty" mapstructure:"multiplier" yaml:"multiplier,omitempty"`
+	// The maximum delay in milliseconds between retries. Requests aren't retried if Retry-After headers ask for longer delays. Unlimited if zero
+	MaxDelay uint `json:"maxDelay,omitempty" mapstructure:"maxDelay" yaml:"maxDelay,omitempty"`
+	// The fraction of the delay, between 0 and 1, which is randomized to spread retries of concurrent requests
+	Jitter float64 `json:"jitter,omitempty" mapstructure:"jitter" yaml:"jitter,omitempty"`
+}
+
+// Validate checks if backoff options of the retry policy are valid.
+func (rp RetryPolicy) Validate() error {
+	if rp.Backoff != "" && !rp.Backoff.IsValid() {
+		return fmt.Errorf("invalid retry backoff strategy: %s", rp.Backoff)
+	}
+
+	if rp.Multiplier != 0 && rp.Multiplier < 1 {
+		return fmt.Errorf("retry multiplier must be at least 1, got: %v", rp.Multiplier)
+	}
+
+	if rp.Jitter < 0 || rp.Jitter > 1 {
+		return fmt.Errorf("retry jitter must be in between 0 and 1, got: %v", rp.Jitter)
+	}
+
+	return nil
 }
 
 // Schema returns the object type schema of this type
@@ -190,6 +693,22 @@ func (rp RetryPolicy) Schema() schema.ObjectType {
 				Description: utils.ToPtr("List of HTTP status the connector will retry on"),
 				Type:        schema.NewNullableType(schema.NewArrayType(schema.NewNamedType(string(ScalarInt32)))).Encode(),
 			},
+			"backoff": {
+				Description: utils.ToPtr("The strategy to compute the delay between retries, is one of fixed or exponential"),
+				Type:        schema.NewNullableType(schema.NewNamedType(string(ScalarString))).Encode(),
+			},
+			"multiplier": {
+				Description: utils.ToPtr("The factor to multiply the delay after each retry of the exponential backoff"),
+				Type:        schema.NewNullableType(schema.NewNamedType(string(ScalarFloat64))).Encode(),
+			},
+			"maxDelay": {
+				Description: utils.ToPtr("The maximum delay in milliseconds between retries"),
+				Type:        schema.NewNullableType(schema.NewNamedType(string(ScalarInt32))).Encode(),
+			},
+			"jitter": {
+				Description: utils.ToPtr("The fraction of the delay, between 0 and 1, which is randomized"),
+				Type:        schema.NewNullableType(schema.NewNamedType(string(ScalarFloat64))).Encode(),
+			},
 		},
 	}
 }
@@ -245,6 +764,35 @@ func (eo *EncodingObject) GetHeader(key string) *RequestParameter {
 type RequestBody struct {
 	ContentType string                    `json:"contentType,omitempty" mapstructure:"contentType" yaml:"contentType,omitempty"`
 	Encoding    map[string]EncodingObject `json:"encoding,omitempty"    mapstructure:"encoding"    yaml:"encoding,omitempty"`
+	// Encode items of the array body line by line while sending the request. Supports application/x-ndjson and text/csv
+	Stream *RequestBodyStreamSettings `json:"stream,omitempty" mapstructure:"stream" yaml:"stream,omitempty"`
+}
+
+// RequestBodyStreamSettings hold settings to encode large ndjson or CSV bodies line by line
+// instead of building the whole payload in memory before sending.
+type RequestBodyStreamSettings struct {
+	// Compress the body with gzip while encoding
+	Gzip bool `json:"gzip,omitempty" mapstructure:"gzip" yaml:"gzip,omitempty"`
+	// The gzip compression level from 1 (best speed) to 9 (best compression). Default to the default compression level of gzip
+	CompressionLevel int `json:"compressionLevel,omitempty" jsonschema:"minimum=0,maximum=9" mapstructure:"compressionLevel" yaml:"compressionLevel,omitempty"`
+	// Column names of the CSV header. Default to sorted keys of the first item
+	Columns []string `json:"columns,omitempty" mapstructure:"columns" yaml:"columns,omitempty"`
+}
+
+// WebhookInfo describes a request which the upstream service sends to subscribers.
+type WebhookInfo struct {
+	// The procedure which registers the subscription, e.g. the operation of OpenAPI callbacks. Empty if the webhook is registered out of band
+	Operation string `json:"operation,omitempty" mapstructure:"operation" yaml:"operation,omitempty"`
+	// The URL of the request. It can be a runtime expression of OpenAPI callbacks, e.g. {$request.body#/callbackUrl}. Empty if the subscriber URL is registered out of band
+	URL string `json:"url,omitempty" mapstructure:"url" yaml:"url,omitempty"`
+	// The HTTP method of the request
+	Method string `json:"method,omitempty" mapstructure:"method" yaml:"method,omitempty"`
+	// The description of the webhook
+	Description *string `json:"description,omitempty" mapstructure:"description,omitempty" yaml:"description,omitempty"`
+	// The content type of the payload
+	ContentType string `json:"contentType,omitempty" mapstructure:"contentType" yaml:"contentType,omitempty"`
+	// The type of the payload
+	PayloadType schema.Type `json:"payloadType,omitempty" mapstructure:"payloadType" yaml:"payloadType,omitempty"`
 }
 
 // OperationInfo extends connector command operation with OpenAPI HTTP information
@@ -254,6 +802,13 @@ type OperationInfo struct {
 	Arguments map[string]ArgumentInfo `json:"arguments" mapstructure:"arguments" yaml:"arguments"`
 	// Column description
 	Description *string `json:"description,omitempty" mapstructure:"description,omitempty" yaml:"description,omitempty"`
+	// Tags of the operation, e.g. OpenAPI tags. Used as grouping labels of metrics and traces
+	Tags []string `json:"tags,omitempty" mapstructure:"tags,omitempty" yaml:"tags,omitempty"`
+	// Follow-up operations which can be called with values of the response, e.g. OpenAPI links
+	Links map[string]OperationLink `json:"links,omitempty" mapstructure:"links,omitempty" yaml:"links,omitempty"`
+	// Expose the function as a NDC collection of result items instead of a function, so predicates, limit and offset
+	// of queries are pushed down with the filter and pagination settings. Only applies to functions which return arrays of objects
+	Collection bool `json:"collection,omitempty" mapstructure:"collection,omitempty" yaml:"collection,omitempty"`
 	// The name of the result type
 	ResultType schema.Type `json:"result_type" mapstructure:"result_type" yaml:"result_type"`
 }
@@ -301,21 +856,80 @@ func (j *OperationInfo) UnmarshalJSON(b []byte) error {
 		j.Description = &description
 	}
 
+	if rawTags, ok := raw["tags"]; ok {
+		var tags []string
+		if err := json.Unmarshal(rawTags, &tags); err != nil {
+			return fmt.Errorf("field tags in ProcedureInfo: %w", err)
+		}
+		j.Tags = tags
+	}
+
+	if rawLinks, ok := raw["links"]; ok {
+		var links map[string]OperationLink
+		if err := json.Unmarshal(rawLinks, &links); err != nil {
+			return fmt.Errorf("field links in ProcedureInfo: %w", err)
+		}
+		j.Links = links
+	}
+
+	if rawCollection, ok := raw["collection"]; ok {
+		if err := json.Unmarshal(rawCollection, &j.Collection); err != nil {
+			return fmt.Errorf("field collection in ProcedureInfo: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// OperationLink represents a relationship from the response of an operation to a follow-up operation.
+type OperationLink struct {
+	// The name of the target operation
+	Operation string `json:"operation" mapstructure:"operation" yaml:"operation"`
+	// Arguments of the target operation. Values are constants or runtime expressions, e.g. $response.body#/id
+	Arguments map[string]string `json:"arguments,omitempty" mapstructure:"arguments" yaml:"arguments,omitempty"`
+	// The request body of the target operation. The value is a constant or a runtime expression
+	RequestBody string `json:"requestBody,omitempty" mapstructure:"requestBody" yaml:"requestBody,omitempty"`
+	// The description of the link
+	Description string `json:"description,omitempty" mapstructure:"description" yaml:"description,omitempty"`
+}
+
 // Schema returns the connector schema of the function
 func (j OperationInfo) FunctionSchema(name string) schema.FunctionInfo {
 	arguments := make(schema.FunctionInfoArguments)
 	for key, argument := range j.Arguments {
-		arguments[key] = argument.ArgumentInfo
+		arguments[key] = argument.Schema()
+	}
+
+	// missing resources are returned as null results
+	resultType := j.ResultType
+	if j.Request != nil && j.Request.Response.NotFoundAsNull {
+		if _, ok := resultType.Interface().(*schema.NullableType); !ok {
+			resultType = schema.NewNullableType(resultType.Interface()).Encode()
+		}
 	}
 
 	return schema.FunctionInfo{
 		Name:        name,
 		Arguments:   arguments,
 		Description: j.Description,
-		ResultType:  j.ResultType,
+		ResultType:  resultType,
+	}
+}
+
+// CollectionSchema returns the connector schema of the collection
+func (j OperationInfo) CollectionSchema(name string, collectionType string) schema.CollectionInfo {
+	arguments := make(schema.CollectionInfoArguments)
+	for key, argument := range j.Arguments {
+		arguments[key] = argument.Schema()
+	}
+
+	return schema.CollectionInfo{
+		Name:                  name,
+		Arguments:             arguments,
+		Description:           j.Description,
+		Type:                  collectionType,
+		UniquenessConstraints: schema.CollectionInfoUniquenessConstraints{},
+		ForeignKeys:           schema.CollectionInfoForeignKeys{},
 	}
 }
 
@@ -323,7 +937,7 @@ func (j OperationInfo) FunctionSchema(name string) schema.FunctionInfo {
 func (j OperationInfo) ProcedureSchema(name string) schema.ProcedureInfo {
 	arguments := make(schema.ProcedureInfoArguments)
 	for key, argument := range j.Arguments {
-		arguments[key] = argument.ArgumentInfo
+		arguments[key] = argument.Schema()
 	}
 
 	return schema.ProcedureInfo{
@@ -342,6 +956,8 @@ type ObjectType struct {
 	Fields map[string]ObjectField `json:"fields" mapstructure:"fields" yaml:"fields"`
 	// XML schema
 	XML *XMLSchema `json:"xml,omitempty" mapstructure:"xml" yaml:"xml,omitempty"`
+	// The discriminator of the tagged union object. The object has the tag field and a nullable field of each variant
+	Discriminator *UnionDiscriminator `json:"discriminator,omitempty" mapstructure:"discriminator" yaml:"discriminator,omitempty"`
 }
 
 // Schema returns schema the object field
@@ -358,17 +974,55 @@ func (of ObjectType) Schema() schema.ObjectType {
 	return result
 }
 
+// UnionDiscriminator represents the discriminator of a tagged union object which is converted from an OpenAPI oneOf schema.
+// The upstream payload is the variant object with the tag property, e.g. { "object": "card", "brand": "visa" }.
+// The NDC value has the tag field and the variant value in its own field, e.g. { "object": "card", "card": { "brand": "visa" } }.
+type UnionDiscriminator struct {
+	// The name of the tag property in the payload
+	PropertyName string `json:"propertyName" mapstructure:"propertyName" yaml:"propertyName"`
+	// Mapping of tag values to the variant field names of the union object
+	Mapping map[string]string `json:"mapping" mapstructure:"mapping" yaml:"mapping"`
+}
+
+// GetVariantField returns the variant field name of the tag value.
+func (ud UnionDiscriminator) GetVariantField(tag any) (string, bool) {
+	tagValue, ok := tag.(string)
+	if !ok {
+		return "", false
+	}
+
+	fieldName, ok := ud.Mapping[tagValue]
+
+	return fieldName, ok
+}
+
+// GetTag returns the first tag value of the variant field name.
+func (ud UnionDiscriminator) GetTag(fieldName string) (string, bool) {
+	for _, tag := range utils.GetSortedKeys(ud.Mapping) {
+		if ud.Mapping[tag] == fieldName {
+			return tag, true
+		}
+	}
+
+	return "", false
+}
+
 // ObjectField defined on this object type
 type ObjectField struct {
 	schema.ObjectField `yaml:",inline"`
 
 	// The field schema information of the HTTP request
 	HTTP *TypeSchema `json:"http,omitempty" mapstructure:"http" yaml:"http,omitempty"`
+	// The data classification of the field, e.g. pii
+	Classification DataClassification `json:"classification,omitempty" mapstructure:"classification" yaml:"classification,omitempty"`
 }
 
 // Schema returns schema the object field
 func (of ObjectField) Schema() schema.ObjectField {
-	return of.ObjectField
+	result := of.ObjectField
+	result.Description = of.Classification.Annotate(of.Description)
+
+	return result
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
@@ -409,6 +1063,12 @@ func (j *ObjectField) UnmarshalJSON(b []byte) error {
 		j.HTTP = &ty
 	}
 
+	if rawClassification, ok := raw["classification"]; ok {
+		if err := json.Unmarshal(rawClassification, &j.Classification); err != nil {
+			return fmt.Errorf("field classification in ObjectField: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -418,6 +1078,16 @@ type ArgumentInfo struct {
 
 	// The request parameter information of the HTTP request
 	HTTP *RequestParameter `json:"http,omitempty" mapstructure:"http" yaml:"http,omitempty"`
+	// The data classification of the argument, e.g. pii
+	Classification DataClassification `json:"classification,omitempty" mapstructure:"classification" yaml:"classification,omitempty"`
+}
+
+// Schema returns the connector schema of the argument
+func (j ArgumentInfo) Schema() schema.ArgumentInfo {
+	result := j.ArgumentInfo
+	result.Description = j.Classification.Annotate(j.Description)
+
+	return result
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
@@ -451,6 +1121,12 @@ func (j *ArgumentInfo) UnmarshalJSON(b []byte) error {
 		j.HTTP = &param
 	}
 
+	if rawClassification, ok := raw["classification"]; ok {
+		if err := json.Unmarshal(rawClassification, &j.Classification); err != nil {
+			return fmt.Errorf("field classification in ArgumentInfo: %w", err)
+		}
+	}
+
 	return nil
 }
 
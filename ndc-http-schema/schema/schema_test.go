@@ -126,3 +126,25 @@ func TestDecodeHTTPFunctionInfo(t *testing.T) {
 		})
 	}
 }
+
+func TestFunctionSchemaNotFoundAsNull(t *testing.T) {
+	var fn OperationInfo
+	assert.NilError(t, json.Unmarshal([]byte(`{
+		"request": {
+			"url": "/pets/{petId}",
+			"method": "get",
+			"response": {
+				"contentType": "application/json",
+				"404AsNull": true
+			}
+		},
+		"arguments": {},
+		"result_type": { "type": "named", "name": "Pet" }
+	}`), &fn))
+	assert.Assert(t, fn.Request.Response.NotFoundAsNull)
+	assert.DeepEqual(t, schema.NewNullableNamedType("Pet").Encode(), fn.FunctionSchema("getPet").ResultType)
+
+	// nullable result types aren't wrapped again
+	fn.ResultType = schema.NewNullableNamedType("Pet").Encode()
+	assert.DeepEqual(t, schema.NewNullableNamedType("Pet").Encode(), fn.FunctionSchema("getPet").ResultType)
+}
@@ -25,6 +25,14 @@ type NDCHttpSettings struct {
 	Security        AuthSecurities             `json:"security,omitempty"        mapstructure:"security"        yaml:"security,omitempty"`
 	Version         string                     `json:"version,omitempty"         mapstructure:"version"         yaml:"version,omitempty"`
 	TLS             *TLSConfig                 `json:"tls,omitempty"             mapstructure:"tls"             yaml:"tls,omitempty"`
+	// The strategy to select a server for single-target requests if there are many servers, e.g. random, fastest, roundRobin or weighted. Default to random.
+	ServerSelection ServerSelectionStrategy `json:"serverSelection,omitempty" mapstructure:"serverSelection" yaml:"serverSelection,omitempty"`
+	// Encryption of argument and response fields for upstreams that require application-layer encryption, e.g. PII fields.
+	Encryption []FieldEncryptionConfig `json:"encryption,omitempty" mapstructure:"encryption" yaml:"encryption,omitempty"`
+	// Connection pooling and HTTP client options of all servers. Can be overridden by settings of each server.
+	Connection *ConnectionConfig `json:"connection,omitempty" mapstructure:"connection" yaml:"connection,omitempty"`
+	// Proxy settings of outbound requests to all servers. Can be overridden by settings of each server.
+	Proxy *ProxyConfig `json:"proxy,omitempty" mapstructure:"proxy" yaml:"proxy,omitempty"`
 }
 
 // Validate if the current instance is valid
@@ -53,6 +61,16 @@ func (rs *NDCHttpSettings) Validate() error {
 		}
 	}
 
+	if rs.ServerSelection != "" && !rs.ServerSelection.IsValid() {
+		return fmt.Errorf("serverSelection: invalid strategy %s", rs.ServerSelection)
+	}
+
+	for i, encryption := range rs.Encryption {
+		if _, _, _, err := encryption.Validate(); err != nil {
+			return fmt.Errorf("encryption[%d]: %w", i, err)
+		}
+	}
+
 	return nil
 }
 
@@ -65,6 +83,20 @@ type ServerConfig struct {
 	SecuritySchemes map[string]SecurityScheme  `json:"securitySchemes,omitempty" mapstructure:"securitySchemes" yaml:"securitySchemes,omitempty"`
 	Security        AuthSecurities             `json:"security,omitempty"        mapstructure:"security"        yaml:"security,omitempty"`
 	TLS             *TLSConfig                 `json:"tls,omitempty"             mapstructure:"tls"             yaml:"tls,omitempty"`
+	// Mark the server as the default target of single (non-distributed) executions. Can be set from an environment variable.
+	Default *utils.EnvBool `json:"default,omitempty" mapstructure:"default" yaml:"default,omitempty"`
+	// The relative weight of the server in the weighted server selection. Servers with zero weight don't receive requests unless all candidate servers have zero weight. Default to 1.
+	Weight *uint `json:"weight,omitempty" mapstructure:"weight" yaml:"weight,omitempty"`
+	// Custom DNS resolution of the server, e.g. static host overrides for split-horizon DNS or blue/green deployments.
+	DNS *DNSConfig `json:"dns,omitempty" mapstructure:"dns" yaml:"dns,omitempty"`
+	// Dialing options of connections to the server, e.g. the preferred IP version.
+	Dialer *DialerConfig `json:"dialer,omitempty" mapstructure:"dialer" yaml:"dialer,omitempty"`
+	// Connection pooling and HTTP client options of the server. Unset fields inherit the global connection settings.
+	Connection *ConnectionConfig `json:"connection,omitempty" mapstructure:"connection" yaml:"connection,omitempty"`
+	// Active health checking of the server. Unhealthy servers are excluded from the server selection and distributed executions.
+	HealthCheck *HealthCheckConfig `json:"healthCheck,omitempty" mapstructure:"healthCheck" yaml:"healthCheck,omitempty"`
+	// Proxy settings of outbound requests to the server. Replace the global proxy settings if set.
+	Proxy *ProxyConfig `json:"proxy,omitempty" mapstructure:"proxy" yaml:"proxy,omitempty"`
 }
 
 // Validate if the current instance is valid
@@ -89,9 +121,39 @@ func (ss *ServerConfig) Validate() error {
 		}
 	}
 
+	if ss.DNS != nil {
+		if err := ss.DNS.Validate(); err != nil {
+			return fmt.Errorf("dns: %w", err)
+		}
+	}
+
+	if ss.HealthCheck != nil {
+		if err := ss.HealthCheck.Validate(); err != nil {
+			return fmt.Errorf("healthCheck: %w", err)
+		}
+	}
+
 	return nil
 }
 
+// IsDefault checks if the server is the default target of single executions.
+func (ss ServerConfig) IsDefault() (bool, error) {
+	if ss.Default == nil {
+		return false, nil
+	}
+
+	return ss.Default.GetOrDefault(false)
+}
+
+// GetWeight returns the weight of the server in the weighted server selection.
+func (ss ServerConfig) GetWeight() uint {
+	if ss.Weight == nil {
+		return 1
+	}
+
+	return *ss.Weight
+}
+
 // Validate if the current instance is valid
 func (ss ServerConfig) GetURL() (*url.URL, error) {
 	rawURL, err := ss.URL.Get()
@@ -172,12 +234,14 @@ const (
 	ArgumentPresetValueTypeLiteral       ArgumentPresetValueType = "literal"
 	ArgumentPresetValueTypeEnv           ArgumentPresetValueType = "env"
 	ArgumentPresetValueTypeForwardHeader ArgumentPresetValueType = "forwardHeader"
+	ArgumentPresetValueTypeTemplate      ArgumentPresetValueType = "template"
 )
 
 var argumentPresetValueType_enums = []ArgumentPresetValueType{
 	ArgumentPresetValueTypeLiteral,
 	ArgumentPresetValueTypeEnv,
 	ArgumentPresetValueTypeForwardHeader,
+	ArgumentPresetValueTypeTemplate,
 }
 
 // JSONSchema is used to generate a custom jsonschema
@@ -264,9 +328,46 @@ func (j *ArgumentPresetValue) UnmarshalJSON(b []byte) error {
 			return fmt.Errorf("ArgumentPresetValue.name: %w", err)
 		}
 
-		j.inner = &ArgumentPresetValueForwardHeader{
-			Type: valueType,
-			Name: name,
+		required, err := utils.GetBooleanDefault(rawValue, "required")
+		if err != nil {
+			return fmt.Errorf("ArgumentPresetValue.required: %w", err)
+		}
+
+		pattern, err := utils.GetNullableString(rawValue, "pattern")
+		if err != nil {
+			return fmt.Errorf("ArgumentPresetValue.pattern: %w", err)
+		}
+
+		forwardHeader := &ArgumentPresetValueForwardHeader{
+			Type:     valueType,
+			Name:     name,
+			Required: required,
+		}
+
+		if pattern != nil && *pattern != "" {
+			if _, err := regexp.Compile(*pattern); err != nil {
+				return fmt.Errorf("ArgumentPresetValue.pattern: %w", err)
+			}
+
+			forwardHeader.Pattern = *pattern
+		}
+
+		j.inner = forwardHeader
+	case ArgumentPresetValueTypeTemplate:
+		template, err := getStringFromAnyMap(rawValue, "template")
+		if err != nil {
+			return fmt.Errorf("ArgumentPresetValue.template: %w", err)
+		}
+
+		required, err := utils.GetBooleanDefault(rawValue, "required")
+		if err != nil {
+			return fmt.Errorf("ArgumentPresetValue.required: %w", err)
+		}
+
+		j.inner = &ArgumentPresetValueTemplate{
+			Type:     valueType,
+			Template: template,
+			Required: required,
 		}
 	}
 
@@ -285,6 +386,7 @@ func (j ArgumentPresetValue) JSONSchema() *jsonschema.Schema {
 			ArgumentPresetValueLiteral{}.JSONSchema(),
 			ArgumentPresetValueEnv{}.JSONSchema(),
 			ArgumentPresetValueForwardHeader{}.JSONSchema(),
+			ArgumentPresetValueTemplate{}.JSONSchema(),
 		},
 	}
 }
@@ -359,6 +461,10 @@ func (j ArgumentPresetValueEnv) GetType() ArgumentPresetValueType {
 type ArgumentPresetValueForwardHeader struct {
 	Type ArgumentPresetValueType `json:"type" mapstructure:"type" yaml:"type"`
 	Name string                  `json:"name" mapstructure:"name" yaml:"name"`
+	// Fail the request if the header doesn't exist. Otherwise the argument isn't changed
+	Required bool `json:"required,omitempty" mapstructure:"required" yaml:"required,omitempty"`
+	// The regular expression to validate the header value
+	Pattern string `json:"pattern,omitempty" mapstructure:"pattern" yaml:"pattern,omitempty"`
 }
 
 // JSONSchema is used to generate a custom jsonschema
@@ -374,6 +480,16 @@ func (j ArgumentPresetValueForwardHeader) JSONSchema() *jsonschema.Schema {
 		Type:        "string",
 	})
 
+	properties.Set("required", &jsonschema.Schema{
+		Description: "Fail the request if the header doesn't exist. Otherwise the argument isn't changed",
+		Type:        "boolean",
+	})
+
+	properties.Set("pattern", &jsonschema.Schema{
+		Description: "The regular expression to validate the header value",
+		Type:        "string",
+	})
+
 	return &jsonschema.Schema{
 		Type:       "object",
 		Properties: properties,
@@ -386,6 +502,45 @@ func (apv ArgumentPresetValueForwardHeader) GetType() ArgumentPresetValueType {
 	return apv.Type
 }
 
+// ArgumentPresetValueTemplate represents an argument preset value which is rendered from a template per request,
+// e.g. tenant-{{headers.x-tenant-id}}. Supported expressions are headers.<name>, session.<name>, env.<name>, now, now.unix, now.unixMilli and uuid.
+type ArgumentPresetValueTemplate struct {
+	Type     ArgumentPresetValueType `json:"type"     mapstructure:"type"     yaml:"type"`
+	Template string                  `json:"template" mapstructure:"template" yaml:"template"`
+	// Fail the request if a referenced header doesn't exist. Otherwise the argument isn't changed
+	Required bool `json:"required,omitempty" mapstructure:"required" yaml:"required,omitempty"`
+}
+
+// JSONSchema is used to generate a custom jsonschema
+func (j ArgumentPresetValueTemplate) JSONSchema() *jsonschema.Schema {
+	properties := orderedmap.New[string, *jsonschema.Schema]()
+	properties.Set("type", &jsonschema.Schema{
+		Type: "string",
+		Enum: []any{ArgumentPresetValueTypeTemplate},
+	})
+
+	properties.Set("template", &jsonschema.Schema{
+		Description: "The template with {{expression}} placeholders, e.g. {{headers.x-hasura-user-id}}, {{now}} or {{uuid}}",
+		Type:        "string",
+	})
+
+	properties.Set("required", &jsonschema.Schema{
+		Description: "Fail the request if a referenced header doesn't exist. Otherwise the argument isn't changed",
+		Type:        "boolean",
+	})
+
+	return &jsonschema.Schema{
+		Type:       "object",
+		Properties: properties,
+		Required:   []string{"type", "template"},
+	}
+}
+
+// GetType gets the type of the current argument preset value.
+func (apv ArgumentPresetValueTemplate) GetType() ArgumentPresetValueType {
+	return apv.Type
+}
+
 // ParseHttpURL parses and validate if the URL has HTTP scheme
 func ParseHttpURL(input string) (*url.URL, error) {
 	if !strings.HasPrefix(input, "https://") && !strings.HasPrefix(input, "http://") {
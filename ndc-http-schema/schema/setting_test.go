@@ -185,6 +185,15 @@ func TestNDCHttpSettings(t *testing.T) {
 							"name": "X-Test-Header"
 						},
 						"targets": ["updatePet"]
+					},
+					{
+						"path": "body.nonce",
+						"value": {
+							"type": "template",
+							"template": "{{headers.x-hasura-user-id}}-{{uuid}}",
+							"required": true
+						},
+						"targets": ["updatePet"]
 					}
 				]
 			}`,
@@ -351,6 +360,17 @@ func TestNDCHttpSettings(t *testing.T) {
 						},
 						Targets: []string{"updatePet"},
 					},
+					{
+						Path: "body.nonce",
+						Value: ArgumentPresetValue{
+							inner: ArgumentPresetValueTemplate{
+								Type:     ArgumentPresetValueTypeTemplate,
+								Template: "{{headers.x-hasura-user-id}}-{{uuid}}",
+								Required: true,
+							},
+						},
+						Targets: []string{"updatePet"},
+					},
 				},
 			},
 		},
@@ -25,6 +25,13 @@ type TLSConfig struct {
 	KeyFile *utils.EnvString `json:"keyFile,omitempty" mapstructure:"keyFile" yaml:"keyFile,omitempty"`
 	// Alternative to key_file. Provide the key contents as a base64-encoded string instead of a filepath.
 	KeyPem *utils.EnvString `json:"keyPem,omitempty" mapstructure:"keyPem" yaml:"keyPem,omitempty"`
+	// Path to the PKCS#12 bundle (.p12 or .pfx) which contains the certificate chain and the private key.
+	// Alternative to the certificate and key files.
+	PKCS12File *utils.EnvString `json:"pkcs12File,omitempty" mapstructure:"pkcs12File" yaml:"pkcs12File,omitempty"`
+	// Alternative to pkcs12File. Provide the PKCS#12 bundle contents as a base64-encoded string instead of a filepath.
+	PKCS12Base64 *utils.EnvString `json:"pkcs12Base64,omitempty" mapstructure:"pkcs12Base64" yaml:"pkcs12Base64,omitempty"`
+	// The passphrase to decrypt the PKCS#12 bundle or the encrypted private key.
+	KeyPassphrase *utils.EnvString `json:"keyPassphrase,omitempty" mapstructure:"keyPassphrase" yaml:"keyPassphrase,omitempty"`
 	// Path to the CA cert. For a client this verifies the server certificate. For a server this verifies client certificates.
 	// If empty uses system root CA.
 	CAFile *utils.EnvString `json:"caFile,omitempty" mapstructure:"caFile" yaml:"caFile,omitempty"`
@@ -107,6 +114,16 @@ func (tc TLSConfig) Validate() error {
 		}
 	}
 
+	if err := tc.validatePKCS12(); err != nil {
+		return err
+	}
+
+	if tc.KeyPassphrase != nil {
+		if _, err := tc.KeyPassphrase.GetOrDefault(""); err != nil {
+			return fmt.Errorf("TLSConfig.keyPassphrase: %w", err)
+		}
+	}
+
 	if tc.IncludeSystemCACertsPool != nil {
 		_, err := tc.IncludeSystemCACertsPool.GetOrDefault(false)
 		if err != nil {
@@ -124,6 +141,45 @@ func (tc TLSConfig) Validate() error {
 	return nil
 }
 
+func (tc TLSConfig) validatePKCS12() error {
+	var pkcs12File, pkcs12Base64 string
+	var err error
+
+	if tc.PKCS12File != nil {
+		pkcs12File, err = tc.PKCS12File.GetOrDefault("")
+		if err != nil {
+			return fmt.Errorf("TLSConfig.pkcs12File: %w", err)
+		}
+	}
+
+	if tc.PKCS12Base64 != nil {
+		pkcs12Base64, err = tc.PKCS12Base64.GetOrDefault("")
+		if err != nil {
+			return fmt.Errorf("TLSConfig.pkcs12Base64: %w", err)
+		}
+	}
+
+	if pkcs12File == "" && pkcs12Base64 == "" {
+		return nil
+	}
+
+	if pkcs12File != "" && pkcs12Base64 != "" {
+		return errors.New("invalid TLS configuration: provide either a PKCS#12 file or the base64-encoded string, but not both")
+	}
+
+	for _, value := range []*utils.EnvString{tc.CertFile, tc.CertPem, tc.KeyFile, tc.KeyPem} {
+		if value == nil {
+			continue
+		}
+
+		if str, err := value.GetOrDefault(""); err == nil && str != "" {
+			return errors.New("invalid TLS configuration: the PKCS#12 bundle can't be used with the certificate and key")
+		}
+	}
+
+	return nil
+}
+
 // GetMinVersion parses the minx TLS version from string.
 func (tc TLSConfig) GetMinVersion() (uint16, error) {
 	return tc.convertTLSVersion(tc.MinVersion, defaultMinTLSVersion)
@@ -68,11 +68,33 @@ func WriteSchemaFile(outputPath string, content any) error {
 
 // ReadFileFromPath read file content from either file path or URL
 func ReadFileFromPath(filePath string) ([]byte, error) {
+	return ReadFileFromPathWithHeaders(filePath, nil)
+}
+
+// ReadFileFromPathWithHeaders read file content from either file path or URL.
+// Headers are sent with the request if the file path is a URL, e.g. the Authorization header of private specs.
+func ReadFileFromPathWithHeaders(filePath string, headers map[string]string) ([]byte, error) {
+	return ReadFileFromPathWithOptions(filePath, ReadFileOptions{
+		Headers: headers,
+	})
+}
+
+// ReadFileFromPathWithOptions read file content from either file path, HTTP(S) URL, S3 URL (s3://bucket/key) or GCS URL (gs://bucket/object).
+func ReadFileFromPathWithOptions(filePath string, options ReadFileOptions) ([]byte, error) {
 	var result []byte
 
 	fileURL, err := url.Parse(filePath)
-	if err == nil && slices.Contains([]string{"http", "https"}, strings.ToLower(fileURL.Scheme)) {
-		resp, err := http.DefaultClient.Get(filePath)
+	if err == nil && slices.Contains(remoteFileSchemes, strings.ToLower(fileURL.Scheme)) {
+		req, err := newRemoteFileRequest(fileURL, options)
+		if err != nil {
+			return nil, err
+		}
+
+		for key, value := range options.Headers {
+			req.Header.Set(key, value)
+		}
+
+		resp, err := http.DefaultClient.Do(req)
 		if err != nil {
 			return nil, err
 		}
@@ -175,7 +197,7 @@ func WalkFiles(filePath string, callback func(data []byte) error) error {
 
 // ResolveFilePath resolves file path with directory
 func ResolveFilePath(dir string, filePath string) string {
-	if !strings.HasPrefix(filePath, "/") && !strings.HasPrefix(filePath, "\\") && !strings.HasPrefix(filePath, "http") {
+	if !strings.HasPrefix(filePath, "/") && !strings.HasPrefix(filePath, "\\") && !strings.HasPrefix(filePath, "http") && !IsRemoteFilePath(filePath) {
 		return path.Join(dir, filePath)
 	}
 
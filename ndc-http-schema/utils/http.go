@@ -16,6 +16,46 @@ func IsContentTypeXML(contentType string) bool {
 	return contentType == schema.ContentTypeXML || strings.HasSuffix(contentType, "+xml")
 }
 
+// IsContentTypeNdJSON checks if the content type is newline-delimited JSON, also known as JSON Lines
+func IsContentTypeNdJSON(contentType string) bool {
+	switch contentType {
+	case schema.ContentTypeNdJSON, "application/ndjson", "application/jsonl", "application/x-jsonl", "application/jsonlines", "application/x-jsonlines":
+		return true
+	default:
+		return false
+	}
+}
+
+// IsContentTypeYAML checks if the content type is YAML
+func IsContentTypeYAML(contentType string) bool {
+	switch contentType {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	default:
+		return strings.HasSuffix(contentType, "+yaml")
+	}
+}
+
+// IsContentTypeMsgPack checks if the content type is MessagePack
+func IsContentTypeMsgPack(contentType string) bool {
+	switch contentType {
+	case schema.ContentTypeMsgPack, "application/x-msgpack", "application/vnd.msgpack":
+		return true
+	default:
+		return strings.HasSuffix(contentType, "+msgpack")
+	}
+}
+
+// IsContentTypeProtobuf checks if the content type is the binary protobuf format
+func IsContentTypeProtobuf(contentType string) bool {
+	switch contentType {
+	case schema.ContentTypeProtobuf, "application/x-protobuf", "application/vnd.google.protobuf":
+		return true
+	default:
+		return strings.HasSuffix(contentType, "+proto") || strings.HasSuffix(contentType, "+protobuf")
+	}
+}
+
 // IsContentTypeText checks if the content type relates to text
 func IsContentTypeText(contentType string) bool {
 	return strings.HasPrefix(contentType, "text/") || strings.HasPrefix(contentType, "image/svg")
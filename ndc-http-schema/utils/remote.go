@@ -0,0 +1,189 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+)
+
+var remoteFileSchemes = []string{"http", "https", "s3", "gs"}
+
+// the SHA-256 hash of the empty payload of GET requests
+const emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// ReadFileOptions represent options to read files from remote sources.
+type ReadFileOptions struct {
+	// Headers of HTTP(S) requests, e.g. the Authorization header of private specs
+	Headers map[string]string
+	// Credentials to download files from s3:// URLs. Objects of public buckets are downloaded without signatures if nil
+	S3 *S3Credentials
+	// The OAuth2 access token to download files from gs:// URLs. Objects of public buckets are downloaded without the token if empty
+	GCSAccessToken string
+}
+
+// S3Credentials represent credentials of AWS APIs, e.g. to download files from S3.
+type S3Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// The region of the bucket. Default to us-east-1
+	Region string
+	// The endpoint of S3-compatible storages, e.g. http://localhost:9000. Objects are addressed in the path style if set
+	Endpoint string
+}
+
+// IsRemoteFilePath checks if the file path is a URL of remote sources, e.g. https://, s3:// and gs://.
+func IsRemoteFilePath(filePath string) bool {
+	scheme, _, ok := strings.Cut(filePath, "://")
+
+	return ok && slices.Contains(remoteFileSchemes, strings.ToLower(scheme))
+}
+
+// create the GET request to download the remote file.
+func newRemoteFileRequest(fileURL *url.URL, options ReadFileOptions) (*http.Request, error) {
+	switch strings.ToLower(fileURL.Scheme) {
+	case "s3":
+		return newS3FileRequest(fileURL, options.S3, time.Now().UTC())
+	case "gs":
+		objectPath := strings.TrimPrefix(fileURL.Path, "/")
+		if fileURL.Host == "" || objectPath == "" {
+			return nil, fmt.Errorf("invalid GCS URL %s, expected gs://bucket/object", fileURL.String())
+		}
+
+		req, err := http.NewRequest(http.MethodGet, "https://storage.googleapis.com/"+fileURL.Host+"/"+escapeURIPath(objectPath), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		if options.GCSAccessToken != "" {
+			req.Header.Set("Authorization", "Bearer "+options.GCSAccessToken)
+		}
+
+		return req, nil
+	default:
+		return http.NewRequest(http.MethodGet, fileURL.String(), nil)
+	}
+}
+
+// create the GET request of the S3 object which is signed with the AWS Signature Version 4.
+func newS3FileRequest(fileURL *url.URL, credentials *S3Credentials, now time.Time) (*http.Request, error) {
+	bucket := fileURL.Host
+	objectPath := strings.TrimPrefix(fileURL.Path, "/")
+	if bucket == "" || objectPath == "" {
+		return nil, fmt.Errorf("invalid S3 URL %s, expected s3://bucket/key", fileURL.String())
+	}
+
+	if credentials == nil {
+		credentials = &S3Credentials{}
+	}
+
+	region := credentials.Region
+	if region == "" {
+		region = "us-east-1"
+	}
+
+	var rawURL string
+	if credentials.Endpoint != "" {
+		rawURL = strings.TrimRight(credentials.Endpoint, "/") + "/" + bucket + "/" + escapeURIPath(objectPath)
+	} else {
+		rawURL = fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", bucket, region, escapeURIPath(objectPath))
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if credentials.AccessKeyID == "" && credentials.SecretAccessKey == "" {
+		return req, nil
+	}
+
+	if credentials.AccessKeyID == "" || credentials.SecretAccessKey == "" {
+		return nil, errors.New("both the access key ID and the secret access key are required to download files from S3")
+	}
+
+	SignAWSRequest(req, credentials, region, "s3", nil, now)
+
+	return req, nil
+}
+
+// SignAWSRequest signs the request of the AWS service, e.g. s3 or secretsmanager, with the AWS Signature Version 4.
+// The payload must be the same as the request body. See https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_sigv-create-signed-request.html
+func SignAWSRequest(req *http.Request, credentials *S3Credentials, region string, service string, payload []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := emptyPayloadHash
+	if len(payload) > 0 {
+		hash := sha256.Sum256(payload)
+		payloadHash = hex.EncodeToString(hash[:])
+	}
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	signedHeaderNames := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if credentials.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", credentials.SessionToken)
+		signedHeaderNames = append(signedHeaderNames, "x-amz-security-token")
+	}
+
+	var canonicalHeaders strings.Builder
+	for _, name := range signedHeaderNames {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+
+	signedHeaders := strings.Join(signedHeaderNames, ";")
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	canonicalRequestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(canonicalRequestHash[:])
+
+	signingKey := hmacSHA256([]byte("AWS4"+credentials.SecretAccessKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", credentials.AccessKeyID, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+
+	return h.Sum(nil)
+}
+
+// escape the URI path with the RFC 3986 encoding that AWS signatures require. Unreserved characters and slashes are kept.
+func escapeURIPath(value string) string {
+	var sb strings.Builder
+	for _, b := range []byte(value) {
+		if (b >= 'A' && b <= 'Z') || (b >= 'a' && b <= 'z') || (b >= '0' && b <= '9') ||
+			b == '-' || b == '_' || b == '.' || b == '~' || b == '/' {
+			sb.WriteByte(b)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", b)
+		}
+	}
+
+	return sb.String()
+}
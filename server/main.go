@@ -1,21 +1,53 @@
 package main
 
 import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
 	rest "github.com/hasura/ndc-http/connector"
 	"github.com/hasura/ndc-http/ndc-http-schema/version"
 	"github.com/hasura/ndc-sdk-go/connector"
 )
 
+// CLI extends the connector CLI with debugging commands.
+type CLI struct {
+	connector.ServeCLI
+
+	Replay rest.ReplayCommandArguments `cmd:"" help:"Print upstream HTTP requests of a recorded NDC query or mutation request without sending them."`
+}
+
+// Execute executes the command.
+func (cli *CLI) Execute(ctx context.Context, command string) error {
+	switch command {
+	case "replay <file>":
+		return rest.Replay(ctx, &cli.Replay, os.Stdout)
+	default:
+		return fmt.Errorf("unknown command <%s>", command)
+	}
+}
+
 // Start the connector server at http://localhost:8080
 //
 //	go run . serve
 //
+// Or print upstream requests of a recorded NDC request without sending them:
+//
+//	go run . replay --configuration ./config request.json
+//
 // See [NDC Go SDK] for more information.
 //
 // [NDC Go SDK]: https://github.com/hasura/ndc-sdk-go
 func main() {
-	if err := connector.Start(
-		rest.NewHTTPConnector(),
+	// NDC_HTTP_FAKE=true enables the fake data mode for frontend development without upstream credentials
+	fake, _ := strconv.ParseBool(os.Getenv("NDC_HTTP_FAKE"))
+	// NDC_HTTP_MOCK=true enables the mock mode which serves fixtures in NDC_HTTP_MOCK_FIXTURES or examples of the API spec
+	mock, _ := strconv.ParseBool(os.Getenv("NDC_HTTP_MOCK"))
+
+	if err := connector.StartCustom(
+		&CLI{},
+		rest.NewHTTPConnector(rest.WithFakeData(fake), rest.WithMockData(mock, os.Getenv("NDC_HTTP_MOCK_FIXTURES"))),
 		connector.WithMetricsPrefix("ndc_http"),
 		connector.WithDefaultServiceName("ndc_http"),
 		connector.WithVersion(version.BuildVersion),